@@ -0,0 +1,140 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/rks0134/CSCE4600/Project1/scheduler"
+)
+
+// algorithm is a registered scheduling algorithm selectable from the command line.
+type algorithm struct {
+	name        string
+	title       string
+	usesQuantum bool // whether the schedule depends on WithQuantum
+	new         scheduler.Factory
+}
+
+// titles are the report titles of the built-in algorithms; other registered
+// algorithms are titled by name.
+var titles = map[string]string{
+	"fcfs":     "First-come, first-serve",
+	"sjf":      "Shortest-job-first",
+	"priority": "Priority",
+	"rr":       "Round-robin",
+	"edf":      "Earliest-deadline-first",
+	"rm":       "Rate-monotonic",
+}
+
+// quantumAlgorithms are the built-in algorithms that preempt on the quantum.
+// EDF and RM round-robin the normal processes.
+var quantumAlgorithms = map[string]bool{"rr": true, "edf": true, "rm": true}
+
+// realTimeAlgorithms are the built-in algorithms that schedule as
+// round-robin does unless some process is real-time.
+var realTimeAlgorithms = map[string]bool{"edf": true, "rm": true}
+
+// forWorkload returns the algorithms selected by list to run over
+// processes: algs, less the real-time algorithms when list is "all" and no
+// process has a deadline or period, as they would only repeat round-robin.
+func forWorkload(algs []algorithm, list string, processes []scheduler.Process) []algorithm {
+	if list != "all" {
+		return algs
+	}
+	for _, p := range processes {
+		if p.Deadline > 0 || p.Period > 0 {
+			return algs
+		}
+	}
+	kept := make([]algorithm, 0, len(algs))
+	for _, alg := range algs {
+		if !realTimeAlgorithms[alg.name] {
+			kept = append(kept, alg)
+		}
+	}
+
+	return kept
+}
+
+// algorithms returns every registered algorithm in registration order.
+func algorithms() []algorithm {
+	names := scheduler.Names()
+	algs := make([]algorithm, 0, len(names))
+	for _, name := range names {
+		alg, _ := findAlgorithm(name)
+		algs = append(algs, alg)
+	}
+
+	return algs
+}
+
+// selectAlgorithms resolves a comma separated list of algorithm names, or "all".
+func selectAlgorithms(list string) ([]algorithm, error) {
+	if list == "all" {
+		return algorithms(), nil
+	}
+	var selected []algorithm
+	for _, name := range strings.Split(list, ",") {
+		alg, ok := findAlgorithm(strings.TrimSpace(name))
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown algorithm %q (known: %s)", ErrInvalidArgs, name, strings.Join(scheduler.Names(), ", "))
+		}
+		selected = append(selected, alg)
+	}
+
+	return selected, nil
+}
+
+func findAlgorithm(name string) (algorithm, bool) {
+	factory, ok := scheduler.Lookup(name)
+	if !ok {
+		return algorithm{}, false
+	}
+	title, ok := titles[name]
+	if !ok {
+		title = name
+	}
+
+	return algorithm{name: name, title: title, usesQuantum: quantumAlgorithms[name], new: factory}, true
+}
+
+// job is one algorithm to run, with the options to run it with, and its
+// result once it has.
+type job struct {
+	alg  algorithm
+	opts []scheduler.Option
+	r    *scheduler.ScheduleResult
+	err  error
+}
+
+// scheduleAll runs every job over its own copy of processes, each in its own
+// goroutine, or takes its result from cache, and returns once they have all
+// finished, so the results can be reported in order.
+func scheduleAll(ctx context.Context, cache *resultCache, jobs []job, processes []scheduler.Process) {
+	var wg sync.WaitGroup
+	for k := range jobs {
+		wg.Add(1)
+		go func(j *job) {
+			defer wg.Done()
+			j.r, j.err = cache.schedule(ctx, j.alg, j.opts, append([]scheduler.Process(nil), processes...))
+		}(&jobs[k])
+	}
+	wg.Wait()
+}
+
+// listAlgorithmsCmd prints the registered algorithms.
+type listAlgorithmsCmd struct{}
+
+func (c *listAlgorithmsCmd) defineFlags(*flag.FlagSet) {}
+
+func (c *listAlgorithmsCmd) run(_ context.Context, w io.Writer, _ []string) error {
+	for _, alg := range algorithms() {
+		_, _ = fmt.Fprintf(w, "%-10s %s\n", alg.name, alg.title)
+	}
+
+	return nil
+}
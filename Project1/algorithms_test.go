@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/rks0134/CSCE4600/Project1/scheduler"
+)
+
+func Test_forWorkload(t *testing.T) {
+	t.Parallel()
+	names := func(algs []algorithm) []string {
+		var names []string
+		for _, alg := range algs {
+			names = append(names, alg.name)
+		}
+		return names
+	}
+	all, rt := algorithms(), []algorithm{{name: "rr"}, {name: "edf"}}
+	normal := []scheduler.Process{{ProcessID: 1, BurstDuration: 2}}
+	tests := []struct {
+		name      string
+		algs      []algorithm
+		list      string
+		processes []scheduler.Process
+		want      []string
+	}{
+		{name: "all without real-time processes", algs: all, list: "all", processes: normal, want: []string{"fcfs", "sjf", "priority", "rr"}},
+		{name: "all with a deadline", algs: all, list: "all", processes: append(normal, scheduler.Process{ProcessID: 2, BurstDuration: 1, Deadline: 5}), want: names(all)},
+		{name: "all with a period", algs: all, list: "all", processes: append(normal, scheduler.Process{ProcessID: 2, BurstDuration: 1, Period: 5}), want: names(all)},
+		{name: "named", algs: rt, list: "rr,edf", processes: normal, want: []string{"rr", "edf"}},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := names(forWorkload(tt.algs, tt.list, tt.processes)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("forWorkload() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_scheduleAll(t *testing.T) {
+	t.Parallel()
+	processes := scheduler.Generate(rand.New(rand.NewSource(1)), 50)
+	algs := algorithms()
+	jobs := make([]job, len(algs))
+	for k, alg := range algs {
+		jobs[k] = job{alg: alg}
+	}
+	scheduleAll(context.Background(), nil, jobs, processes)
+	for k, j := range jobs {
+		want, err := algs[k].new().Schedule(context.Background(), processes)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if j.err != nil || !reflect.DeepEqual(j.r.Rows, want.Rows) {
+			t.Errorf("%s concurrently = %v, %v, want the rows it schedules alone", algs[k].name, j.r, j.err)
+		}
+	}
+}
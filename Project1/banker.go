@@ -0,0 +1,155 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/rks0134/CSCE4600/Project1/deadlock"
+	"github.com/rks0134/CSCE4600/Project1/scheduler"
+)
+
+// bankerCmd runs the banker's algorithm on a resource allocation state.
+type bankerCmd struct {
+	requests    string
+	interactive bool
+	// in is where interactive commands are read from.
+	in io.Reader
+}
+
+func (c *bankerCmd) defineFlags(flags *flag.FlagSet) {
+	flags.StringVar(&c.requests, "request", "", "decide `requests` in turn, each a process and its count of each resource, separated by semicolons, such as \"P1 1 0 2; P4 3 3 0\"")
+	flags.BoolVar(&c.interactive, "interactive", false, "then read requests and releases from standard input, deciding each as it comes")
+}
+
+func (c *bankerCmd) run(_ context.Context, w io.Writer, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("%w: must give a file of the allocation, maximum, and available resources", ErrInvalidArgs)
+	}
+	var s *deadlock.State
+	if err := readInputFile(args[0], func(r io.Reader) (err error) {
+		s, err = deadlock.ParseState(r)
+		return err
+	}); err != nil {
+		return err
+	}
+	if err := s.Validate(); err != nil {
+		return &fileError{path: args[0], err: err}
+	}
+
+	scheduler.RenderTitle(w, fmt.Sprintf("Banker's algorithm, %d processes and %d resources", len(s.Processes), len(s.Resources)))
+	deadlock.RenderState(w, s)
+	deadlock.RenderSafety(w, s, s.Safety())
+	if c.requests != "" {
+		for _, text := range strings.Split(c.requests, ";") {
+			p, counts, err := parseBankerRequest(s, strings.Fields(text))
+			if err != nil {
+				return fmt.Errorf("%w: -request: %v", ErrInvalidArgs, err)
+			}
+			d, err := s.Request(p, counts)
+			if err != nil {
+				return fmt.Errorf("%w: -request: %v", ErrInvalidArgs, err)
+			}
+			deadlock.RenderDecision(w, s, d)
+		}
+	}
+	if !c.interactive {
+		return nil
+	}
+	if c.in == nil {
+		c.in = os.Stdin
+	}
+
+	return c.converse(w, s)
+}
+
+const bankerHelp = `Commands:
+  request P A B ...  request resources for process P, granted only if safe
+  release P A B ...  release resources process P holds
+  state              show the allocation, maximum, need, and available resources
+  safety             run the safety algorithm on the current state
+  quit               stop
+`
+
+// converse reads commands from c.in until it ends or is told to quit,
+// deciding the requests they make of s.
+func (c *bankerCmd) converse(w io.Writer, s *deadlock.State) error {
+	_, _ = fmt.Fprint(w, bankerHelp)
+	in := bufio.NewScanner(c.in)
+	for {
+		_, _ = fmt.Fprint(w, "> ")
+		if err := flush(w); err != nil {
+			return err
+		}
+		if !in.Scan() {
+			_, _ = fmt.Fprintln(w)
+			return in.Err()
+		}
+		fields := strings.Fields(in.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		switch fields[0] {
+		case "request", "release":
+			if err := decideBanker(w, s, fields[0], fields[1:]); err != nil {
+				_, _ = fmt.Fprintf(w, "%v\n", err)
+			}
+		case "state":
+			deadlock.RenderState(w, s)
+		case "safety":
+			deadlock.RenderSafety(w, s, s.Safety())
+		case "quit", "exit":
+			return nil
+		default:
+			_, _ = fmt.Fprint(w, bankerHelp)
+		}
+	}
+}
+
+// decideBanker makes the request or release of the process and counts in fields.
+func decideBanker(w io.Writer, s *deadlock.State, verb string, fields []string) error {
+	p, counts, err := parseBankerRequest(s, fields)
+	if err != nil {
+		return err
+	}
+	if verb == "release" {
+		if err := s.Release(p, counts); err != nil {
+			return err
+		}
+		deadlock.RenderState(w, s)
+		return nil
+	}
+	d, err := s.Request(p, counts)
+	if err != nil {
+		return err
+	}
+	deadlock.RenderDecision(w, s, d)
+
+	return nil
+}
+
+// parseBankerRequest parses a process name and its count of each resource.
+func parseBankerRequest(s *deadlock.State, fields []string) (int, []int, error) {
+	if len(fields) != len(s.Resources)+1 {
+		return 0, nil, fmt.Errorf("want a process and a count of each of %s", strings.Join(s.Resources, " "))
+	}
+	p, ok := s.Process(fields[0])
+	if !ok {
+		return 0, nil, fmt.Errorf("no process %s", fields[0])
+	}
+	counts := make([]int, len(s.Resources))
+	for r, f := range fields[1:] {
+		n, err := strconv.Atoi(f)
+		if err != nil || n < 0 {
+			return 0, nil, fmt.Errorf("invalid count %q", f)
+		}
+		counts[r] = n
+	}
+
+	return p, counts, nil
+}
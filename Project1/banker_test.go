@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func Test_runBanker(t *testing.T) {
+	t.Parallel()
+	state := filepath.Join(t.TempDir(), "state.txt")
+	if err := os.WriteFile(state, []byte(`resources A B C
+available 3 3 2
+allocation
+P0 0 1 0
+P1 2 0 0
+P2 3 0 2
+P3 2 1 1
+P4 0 0 2
+max
+P0 7 5 3
+P1 3 2 2
+P2 9 0 2
+P3 2 2 2
+P4 4 3 3
+`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	var w strings.Builder
+	if err := run(context.Background(), &w, "binary_name", "banker", "-request", "P1 1 0 2; P0 0 2 0", state); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"| PROCESS | ALLOCATION A B C | MAX A B C | NEED A B C |\n",
+		"| P0      | 0 1 0            | 7 5 3     | 7 4 3      |\n",
+		"|    3 | P4      | 7 4 3      | 4 3 1      | 7 4 5            |\n",
+		"Safe, with the safe sequence <P1, P3, P4, P0, P2>\n",
+		"Request by P1 for A B C 1 0 2: granted, leaving a safe state with the safe sequence <P1, P3, P4, P0, P2>\n",
+		"Request by P0 for A B C 0 2 0: denied, P0 waits: granting it would leave P0, P1, P2, P3, P4 unable to finish\n",
+	} {
+		if !strings.Contains(w.String(), want) {
+			t.Errorf("banker wrote:\n%s\nwant it to contain %q", w.String(), want)
+		}
+	}
+
+	w.Reset()
+	c := &bankerCmd{interactive: true, in: strings.NewReader("request P4 3 3 0\nrequest P9 1 1 1\nrelease P1 2 0 0\nrequest P3 0 1 1\nquit\nrequest P0 1 0 0\n")}
+	if err := c.run(context.Background(), &w, []string{state}); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"> Request by P4 for A B C 3 3 0: denied, P4 waits: granting it would leave P0, P1, P2, P3, P4 unable to finish\n",
+		"> no process P9\n",
+		"Available A B C: 5 3 2\n",
+		"> Request by P3 for A B C 0 1 1: granted, leaving a safe state with the safe sequence <P3, P4, P0, P1, P2>\n",
+	} {
+		if !strings.Contains(w.String(), want) {
+			t.Errorf("banker -interactive wrote:\n%s\nwant it to contain %q", w.String(), want)
+		}
+	}
+	if strings.Contains(w.String(), "Request by P0") {
+		t.Errorf("banker -interactive went on after quit:\n%s", w.String())
+	}
+
+	for _, args := range [][]string{
+		{},
+		{"-request", "P1 1 0", state},
+		{"-request", "P7 1 0 2", state},
+	} {
+		err := run(context.Background(), io.Discard, append([]string{"binary_name", "banker"}, args...)...)
+		if !errors.Is(err, ErrInvalidArgs) {
+			t.Errorf("banker %v error = %v, want %v", args, err, ErrInvalidArgs)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// baseline is a record of bench results that later runs are compared with,
+// so performance work on the engine can be measured and regressions caught.
+type baseline struct {
+	// Seed is the seed the workloads were generated from.
+	Seed    int64           `json:"seed"`
+	Results []baselineEntry `json:"results"`
+}
+
+// baselineEntry is the cost of one scheduler on one workload size.
+type baselineEntry struct {
+	Size        int    `json:"size"`
+	Algorithm   string `json:"algorithm"`
+	NsPerOp     int64  `json:"ns_per_op"`
+	AllocsPerOp uint64 `json:"allocs_per_op"`
+	BytesPerOp  uint64 `json:"bytes_per_op"`
+}
+
+// loadBaseline reads the baseline saved at path.
+func loadBaseline(path string) (*baseline, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("%w: error reading baseline", err)
+	}
+	var b baseline
+	if err := json.Unmarshal(data, &b); err != nil {
+		return nil, &fileError{path: path, err: fmt.Errorf("invalid baseline: %w", err)}
+	}
+
+	return &b, nil
+}
+
+// save writes b to path as indented JSON, so baselines diff well under
+// version control.
+func (b *baseline) save(path string) error {
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("%w: error writing baseline", err)
+	}
+
+	return nil
+}
+
+// compare describes how entry changed from its baseline, and reports
+// whether its ns/op or allocs/op grew by more than threshold percent. An
+// entry the baseline has no result for is new, never a regression.
+func (b *baseline) compare(entry baselineEntry, threshold float64) (string, bool) {
+	for _, old := range b.Results {
+		if old.Size != entry.Size || old.Algorithm != entry.Algorithm {
+			continue
+		}
+		ns := change(float64(old.NsPerOp), float64(entry.NsPerOp))
+		allocs := change(float64(old.AllocsPerOp), float64(entry.AllocsPerOp))
+		text := fmt.Sprintf("%+.0f%% time, %+.0f%% allocs", ns, allocs)
+		if ns > threshold || allocs > threshold {
+			return text + " REGRESSION", true
+		}
+		return text, false
+	}
+
+	return "new", false
+}
+
+// change is the percentage by which got differs from was, 0 when was is 0
+// and got is too.
+func change(was, got float64) float64 {
+	switch {
+	case was == got:
+		return 0
+	case was == 0:
+		return 100
+	}
+	return 100 * (got - was) / was
+}
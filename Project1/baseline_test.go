@@ -0,0 +1,40 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func Test_benchBaseline(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "bench.json")
+	args := []string{"binary_name", "-seed", "3", "bench", "-sizes", "20", "-algorithms", "fcfs", "-benchtime", "1ns", "-progress", "never"}
+	if err := run(context.Background(), io.Discard, append(args, "-save", path)...); err != nil {
+		t.Fatalf("bench -save error = %v", err)
+	}
+	b, err := loadBaseline(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.Seed != 3 || len(b.Results) != 1 || b.Results[0].Algorithm != "fcfs" || b.Results[0].Size != 20 {
+		t.Errorf("saved baseline = %+v, want seed 3 and one fcfs result on 20", b)
+	}
+
+	// A baseline that allocated nothing makes any allocation a regression.
+	b.Results[0].AllocsPerOp = 0
+	if err := b.save(path); err != nil {
+		t.Fatal(err)
+	}
+	var w strings.Builder
+	err = run(context.Background(), &w, append(args, "-baseline", path)...)
+	if !errors.Is(err, ErrRegression) || !strings.Contains(w.String(), "REGRESSION") {
+		t.Errorf("bench -baseline error = %v, want %v flagged in:\n%s", err, ErrRegression, w.String())
+	}
+	if err := run(context.Background(), io.Discard, append(args, "-baseline", path, "-algorithms", "sjf")...); err != nil {
+		t.Errorf("bench -baseline with no baseline result error = %v, want nil", err)
+	}
+}
@@ -0,0 +1,219 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"runtime"
+	"sync"
+
+	"github.com/olekukonko/tablewriter"
+
+	"github.com/rks0134/CSCE4600/Project1/scheduler"
+)
+
+// batchCmd runs the selected algorithms over many scheduling files, such as
+// a class's submissions, on a bounded pool of workers.
+type batchCmd struct {
+	*globalFlags
+	simulationFlags
+	webhookFlags
+	dbFlags
+	algorithms string
+	jobs       int
+}
+
+func (c *batchCmd) defineFlags(flags *flag.FlagSet) {
+	flags.StringVar(&c.algorithms, "algorithms", "all", "comma separated `algorithms` to run on each file, or all")
+	flags.IntVar(&c.jobs, "jobs", runtime.NumCPU(), "`number` of files to schedule at once")
+	c.webhookFlags.define(flags)
+	c.dbFlags.define(flags)
+	c.simulationFlags.define(flags)
+}
+
+// fileResult is the outcome of one scheduling file in a batch: the metrics
+// of each algorithm on it, or the error that stopped it.
+type fileResult struct {
+	path    string
+	metrics []scheduler.Metrics
+	total   int
+	err     error
+}
+
+func (c *batchCmd) run(ctx context.Context, w io.Writer, args []string) (err error) {
+	w, finish, err := c.webhookFlags.start("batch", args, w)
+	if err != nil {
+		return err
+	}
+	defer func() { err = finish(err) }()
+	if len(args) == 0 {
+		return fmt.Errorf("%w: must give the scheduling files to process", ErrInvalidArgs)
+	}
+	if c.jobs < 1 {
+		return fmt.Errorf("%w: -jobs must be at least 1", ErrInvalidArgs)
+	}
+	algs, err := selectAlgorithms(c.algorithms)
+	if err != nil {
+		return err
+	}
+	// Check the simulation flags before loading any workload; the options
+	// themselves depend on each one's time unit.
+	if _, err := c.options(); err != nil {
+		return err
+	}
+	bar, err := c.progressBar("batch")
+	if err != nil {
+		return err
+	}
+	records, err := c.dbFlags.open("batch", c.seed.value)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if commitErr := records.commit(); err == nil {
+			err = commitErr
+		}
+	}()
+
+	results := make([]fileResult, len(args))
+	paths := make(chan int)
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	for n := 0; n < c.jobs && n < len(args); n++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for k := range paths {
+				results[k] = c.scheduleFile(ctx, args[k], algs, records)
+				done <- struct{}{}
+			}
+		}()
+	}
+	go func() {
+		defer close(paths)
+		for k := range args {
+			select {
+			case paths <- k:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	finished := 0
+	for range done {
+		if finished++; bar != nil {
+			bar.update(finished, len(args))
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		for k := range results {
+			if results[k].path == "" {
+				results[k] = fileResult{path: args[k], err: err}
+			}
+		}
+	}
+
+	_, _ = fmt.Fprintf(w, "Batch of %d files (seed %d)\n", len(args), c.seed.value)
+	outputBatch(w, algs, results)
+	var failed []error
+	for _, r := range results {
+		if r.err != nil {
+			failed = append(failed, r.err)
+		}
+	}
+	if len(failed) > 0 {
+		_, _ = fmt.Fprintf(w, "%d of %d files failed.\n", len(failed), len(args))
+		// The first failure decides the exit code.
+		return failed[0]
+	}
+
+	return nil
+}
+
+// scheduleFile runs every algorithm in algs on the scheduling file at path,
+// adding each schedule to records. Each file gets its own copy of the
+// simulation flags, since loading a workload records its time unit in them.
+func (c *batchCmd) scheduleFile(ctx context.Context, path string, algs []algorithm, records *dbRun) fileResult {
+	r := fileResult{path: path}
+	flags := c.simulationFlags
+	processes, err := flags.load(path)
+	if err != nil {
+		r.err = err
+		return r
+	}
+	opts, err := flags.options(scheduler.WithSeed(c.seed.value), scheduler.WithMetricsOnly())
+	if err != nil {
+		r.err = err
+		return r
+	}
+	for _, alg := range algs {
+		s, err := c.resultCache().schedule(ctx, alg, opts, processes)
+		if err != nil {
+			r.err = &fileError{path: path, err: fmt.Errorf("%s: %w", alg.name, err)}
+			return r
+		}
+		records.add(path, alg.name, s)
+		r.metrics, r.total = append(r.metrics, s.UnitMetrics()), s.Total
+		s.Release()
+	}
+
+	return r
+}
+
+// outputBatch writes a row per file and algorithm, then the mean of each
+// algorithm's averages over the files that every algorithm completed.
+func outputBatch(w io.Writer, algs []algorithm, results []fileResult) {
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"File", "Algorithm", "Average wait", "Average turnaround", "Throughput", "Completed"})
+	sums := make([]scheduler.Metrics, len(algs))
+	counted := 0
+	for _, r := range results {
+		if r.err != nil {
+			msg := r.err.Error()
+			var fe *fileError
+			if errors.As(r.err, &fe) {
+				msg = fe.err.Error()
+			}
+			table.Append([]string{r.path, "", "error: " + msg, "", "", ""})
+			continue
+		}
+		for k, m := range r.metrics {
+			table.Append([]string{
+				r.path,
+				algs[k].name,
+				fmt.Sprintf("%.2f", m.AverageWait),
+				fmt.Sprintf("%.2f", m.AverageTurnaround),
+				fmt.Sprintf("%.2f/t", m.Throughput),
+				fmt.Sprintf("%d of %d", m.Completed, r.total),
+			})
+			sums[k].AverageWait += m.AverageWait
+			sums[k].AverageTurnaround += m.AverageTurnaround
+			sums[k].Throughput += m.Throughput
+		}
+		counted++
+	}
+	table.Render()
+
+	if counted == 0 {
+		return
+	}
+	_, _ = fmt.Fprintf(w, "Mean over %d files\n", counted)
+	table = tablewriter.NewWriter(w)
+	table.SetHeader([]string{"Algorithm", "Average wait", "Average turnaround", "Throughput"})
+	n := float64(counted)
+	for k, alg := range algs {
+		table.Append([]string{
+			alg.name,
+			fmt.Sprintf("%.2f", sums[k].AverageWait/n),
+			fmt.Sprintf("%.2f", sums[k].AverageTurnaround/n),
+			fmt.Sprintf("%.2f/t", sums[k].Throughput/n),
+		})
+	}
+	table.Render()
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func Test_runBatch(t *testing.T) {
+	t.Parallel()
+	var w strings.Builder
+	err := run(context.Background(), &w, "binary_name", "batch", "-jobs", "2", "-algorithms", "fcfs,sjf", "-progress", "never",
+		"example_processes.csv", "missing.csv", "example_processes.csv")
+	if got := exitCode(err); got != ExitFileNotFound {
+		t.Errorf("batch with a missing file exit code = %v, want %v", got, ExitFileNotFound)
+	}
+	out := w.String()
+	for _, want := range []string{"Batch of 3 files", "| missing.csv", "Mean over 2 files", "1 of 3 files failed."} {
+		if !strings.Contains(out, want) {
+			t.Errorf("batch output is missing %q:\n%s", want, out)
+		}
+	}
+	if n := strings.Count(out, "| example_processes.csv | fcfs"); n != 2 {
+		t.Errorf("batch output has %d fcfs rows for example_processes.csv, want 2:\n%s", n, out)
+	}
+	if err := run(context.Background(), io.Discard, "binary_name", "batch", "-jobs", "0", "example_processes.csv"); !errors.Is(err, ErrInvalidArgs) {
+		t.Errorf("batch -jobs 0 error = %v, want %v", err, ErrInvalidArgs)
+	}
+}
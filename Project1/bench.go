@@ -0,0 +1,188 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/olekukonko/tablewriter"
+
+	"github.com/rks0134/CSCE4600/Project1/scheduler"
+)
+
+// benchCmd times each selected scheduler on generated workloads of each size.
+type benchCmd struct {
+	*globalFlags
+	sizes      string
+	algorithms string
+	benchtime  time.Duration
+	// save and baseline are baseline files to record the results in and to
+	// compare them with, and threshold the percentage worse a result may be
+	// than its baseline.
+	save      string
+	baseline  string
+	threshold float64
+}
+
+func (c *benchCmd) defineFlags(flags *flag.FlagSet) {
+	flags.StringVar(&c.sizes, "sizes", "100,1k,10k", "comma separated workload `sizes`, k and M suffixes allowed")
+	flags.StringVar(&c.algorithms, "algorithms", "all", "comma separated `algorithms` to benchmark, or all")
+	flags.DurationVar(&c.benchtime, "benchtime", time.Second, "minimum `duration` to run each scheduler on each size")
+	flags.StringVar(&c.save, "save", "", "record the results as a JSON baseline in `file`")
+	flags.StringVar(&c.baseline, "baseline", "", "compare the results with the JSON baseline in `file`, generating workloads from its seed unless -seed is given")
+	flags.Float64Var(&c.threshold, "threshold", 20, "`percent` by which ns/op or allocs/op may exceed the -baseline before it counts as a regression")
+}
+
+func (c *benchCmd) run(ctx context.Context, w io.Writer, _ []string) error {
+	sizes, err := parseSizes(c.sizes)
+	if err != nil {
+		return err
+	}
+	algs, err := selectAlgorithms(c.algorithms)
+	if err != nil {
+		return err
+	}
+	if c.benchtime <= 0 {
+		return fmt.Errorf("%w: -benchtime must be positive", ErrInvalidArgs)
+	}
+
+	if c.threshold < 0 {
+		return fmt.Errorf("%w: -threshold must not be negative", ErrInvalidArgs)
+	}
+	var base *baseline
+	if c.baseline != "" {
+		if base, err = loadBaseline(c.baseline); err != nil {
+			return err
+		}
+		if c.seed.random {
+			c.seed.value = base.Seed
+		}
+	}
+
+	bar, err := c.progressBar("bench")
+	if err != nil {
+		return err
+	}
+	runs, total := 0, len(sizes)*len(algs)
+
+	_, _ = fmt.Fprintf(w, "Seed: %d\n", c.seed.value)
+	rng := c.rand()
+	table := tablewriter.NewWriter(w)
+	header := []string{"Size", "Algorithm", "ns/op", "ops/sec", "allocs/op", "B/op"}
+	if base != nil {
+		header = append(header, "vs baseline")
+	}
+	table.SetHeader(header)
+	record := baseline{Seed: c.seed.value}
+	var regressions []string
+	for _, size := range sizes {
+		processes := scheduler.Generate(rng, size)
+		for _, alg := range algs {
+			result, err := benchmark(ctx, alg, processes, c.benchtime, scheduler.WithSeed(c.seed.value))
+			if err != nil {
+				table.Render()
+				return err
+			}
+			entry := baselineEntry{
+				Size:        size,
+				Algorithm:   alg.name,
+				NsPerOp:     result.nsPerOp(),
+				AllocsPerOp: result.allocs / uint64(result.n),
+				BytesPerOp:  result.bytes / uint64(result.n),
+			}
+			record.Results = append(record.Results, entry)
+			row := []string{
+				fmt.Sprint(size),
+				alg.name,
+				fmt.Sprint(entry.NsPerOp),
+				fmt.Sprintf("%.2f", float64(result.n)/result.elapsed.Seconds()),
+				fmt.Sprint(entry.AllocsPerOp),
+				fmt.Sprint(entry.BytesPerOp),
+			}
+			if base != nil {
+				change, regressed := base.compare(entry, c.threshold)
+				if regressed {
+					regressions = append(regressions, fmt.Sprintf("%s on %d", alg.name, size))
+				}
+				row = append(row, change)
+			}
+			table.Append(row)
+			if runs++; bar != nil {
+				bar.update(runs, total)
+			}
+		}
+	}
+	table.Render()
+
+	if c.save != "" {
+		if err := record.save(c.save); err != nil {
+			return err
+		}
+	}
+	if len(regressions) > 0 {
+		return fmt.Errorf("%w: %s slower or allocating more than %g%% over %s", ErrRegression, joinList(regressions, "and"), c.threshold, c.baseline)
+	}
+
+	return nil
+}
+
+// benchResult is the cost of n runs of one scheduler on one workload.
+type benchResult struct {
+	n             int
+	elapsed       time.Duration
+	allocs, bytes uint64
+}
+
+func (r benchResult) nsPerOp() int64 { return r.elapsed.Nanoseconds() / int64(r.n) }
+
+// benchmark runs alg on processes repeatedly for at least benchtime and at
+// least once, measuring wall time and heap allocations across the runs.
+func benchmark(ctx context.Context, alg algorithm, processes []scheduler.Process, benchtime time.Duration, opts ...scheduler.Option) (benchResult, error) {
+	s := alg.new(opts...)
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+
+	var r benchResult
+	start := time.Now()
+	for r.n == 0 || r.elapsed < benchtime {
+		if _, err := s.Schedule(ctx, processes); err != nil {
+			return r, fmt.Errorf("%w: benchmarking %s on %d processes", err, alg.name, len(processes))
+		}
+		r.n++
+		r.elapsed = time.Since(start)
+	}
+
+	runtime.ReadMemStats(&after)
+	r.allocs = after.Mallocs - before.Mallocs
+	r.bytes = after.TotalAlloc - before.TotalAlloc
+
+	return r, nil
+}
+
+// parseSizes parses a comma separated list of sizes such as "100,10k,1M".
+func parseSizes(list string) ([]int, error) {
+	var sizes []int
+	for _, s := range strings.Split(list, ",") {
+		s = strings.TrimSpace(s)
+		multiplier := 1
+		switch {
+		case strings.HasSuffix(s, "k"):
+			multiplier, s = 1_000, strings.TrimSuffix(s, "k")
+		case strings.HasSuffix(s, "M"):
+			multiplier, s = 1_000_000, strings.TrimSuffix(s, "M")
+		}
+		n, err := strconv.Atoi(s)
+		if err != nil || n <= 0 {
+			return nil, fmt.Errorf("%w: invalid size %q", ErrInvalidArgs, s)
+		}
+		sizes = append(sizes, n*multiplier)
+	}
+
+	return sizes, nil
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/rks0134/CSCE4600/Project1/scheduler"
+)
+
+func Test_parseSizes(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		list    string
+		want    []int
+		wantErr error
+	}{
+		{name: "suffixes", list: "100,10k,1M", want: []int{100, 10_000, 1_000_000}},
+		{name: "not a number", list: "ten", wantErr: ErrInvalidArgs},
+		{name: "zero", list: "0", wantErr: ErrInvalidArgs},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := parseSizes(tt.list)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseSizes() = %v, want %v", got, tt.want)
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_benchmark(t *testing.T) {
+	t.Parallel()
+	alg, _ := findAlgorithm("fcfs")
+	processes := scheduler.Generate(rand.New(rand.NewSource(1)), 10)
+	r, err := benchmark(context.Background(), alg, processes, time.Nanosecond)
+	if err != nil {
+		t.Fatalf("benchmark() error = %v", err)
+	}
+	if r.n < 1 || r.elapsed <= 0 || r.allocs == 0 {
+		t.Errorf("benchmark() = %+v, want at least one timed, allocating run", r)
+	}
+}
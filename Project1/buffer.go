@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/rks0134/CSCE4600/Project1/concurrency"
+	"github.com/rks0134/CSCE4600/Project1/scheduler"
+)
+
+// bufferCmd simulates producers and consumers sharing a bounded buffer.
+type bufferCmd struct {
+	*globalFlags
+	producers int
+	consumers int
+	size      int
+	produce   string
+	consume   string
+	access    string
+	duration  int
+}
+
+func (c *bufferCmd) defineFlags(flags *flag.FlagSet) {
+	flags.IntVar(&c.producers, "producers", 2, "number of `producers`")
+	flags.IntVar(&c.consumers, "consumers", 2, "number of `consumers`")
+	flags.IntVar(&c.size, "size", 5, "number of `items` the buffer holds")
+	flags.StringVar(&c.produce, "produce", "2..6", "time to produce an item, a `duration` or a range lo..hi to draw from")
+	flags.StringVar(&c.consume, "consume", "2..6", "time to consume an item, a `duration` or a range lo..hi to draw from")
+	flags.StringVar(&c.access, "access", "1", "time to insert or remove an item holding the buffer's lock, a `duration` or a range lo..hi")
+	flags.IntVar(&c.duration, "duration", 100, "`time` to simulate")
+}
+
+func (c *bufferCmd) run(_ context.Context, w io.Writer, args []string) error {
+	if len(args) > 0 {
+		return fmt.Errorf("%w: buffer takes no arguments", ErrInvalidArgs)
+	}
+	cfg := concurrency.BufferConfig{Producers: c.producers, Consumers: c.consumers, Size: c.size, Duration: c.duration}
+	for _, d := range []struct {
+		name  string
+		value string
+		r     *concurrency.Range
+	}{
+		{"produce", c.produce, &cfg.Produce},
+		{"consume", c.consume, &cfg.Consume},
+		{"access", c.access, &cfg.Access},
+	} {
+		r, err := parseDurationRange(d.value)
+		if err != nil {
+			return fmt.Errorf("%w: -%s: %v", ErrInvalidArgs, d.name, err)
+		}
+		*d.r = r
+	}
+	r, err := concurrency.SimulateBuffer(cfg, c.rand())
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidArgs, err)
+	}
+
+	scheduler.RenderTitle(w, fmt.Sprintf("Bounded buffer of %d items", cfg.Size))
+	_, _ = fmt.Fprintf(w, "Seed: %d\n\n", c.seed.value)
+	_, _ = fmt.Fprintf(w, "Producers: %d, consumers: %d; producing takes %s, consuming %s, and each insert or remove %s\n\n",
+		cfg.Producers, cfg.Consumers, cfg.Produce, cfg.Consume, cfg.Access)
+	concurrency.RenderTimeline(w, r.Threads, cfg.Duration, map[concurrency.State]string{
+		concurrency.Working:  "producing or consuming",
+		concurrency.Critical: "holding mutex",
+		concurrency.Blocked:  "blocked",
+	})
+	concurrency.RenderBuffer(w, r)
+
+	return nil
+}
+
+// parseDurationRange parses a duration, "n", or a range of them, "lo..hi",
+// none of them negative.
+func parseDurationRange(s string) (concurrency.Range, error) {
+	loText, hiText, ok := strings.Cut(s, "..")
+	if !ok {
+		hiText = loText
+	}
+	lo, errLo := strconv.Atoi(loText)
+	hi, errHi := strconv.Atoi(hiText)
+	if errLo != nil || errHi != nil || lo < 0 || hi < lo {
+		return concurrency.Range{}, fmt.Errorf("invalid duration %q, want n or lo..hi", s)
+	}
+
+	return concurrency.Range{Min: lo, Max: hi}, nil
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func Test_runBuffer(t *testing.T) {
+	t.Parallel()
+	var a, b strings.Builder
+	for _, w := range []*strings.Builder{&a, &b} {
+		if err := run(context.Background(), w, "binary_name", "-seed", "1", "buffer", "-producers", "3", "-consumers", "1", "-size", "2", "-duration", "50"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if a.String() != b.String() {
+		t.Errorf("buffer with the same seed wrote:\n%s\nand:\n%s", a.String(), b.String())
+	}
+	for _, want := range []string{
+		"Bounded buffer of 2 items",
+		"Seed: 1\n",
+		"Producers: 3, consumers: 1; producing takes 2 to 6, consuming 2 to 6, and each insert or remove 1\n",
+		"    P1 |",
+		"       '=' producing or consuming, '#' holding mutex, '.' blocked\n",
+		"     2 |",
+		"| THREAD | WORKING | CRITICAL | BLOCKED ON EMPTY | BLOCKED ON FULL | BLOCKED ON MUTEX | BLOCKED |\n",
+	} {
+		if !strings.Contains(a.String(), want) {
+			t.Errorf("buffer wrote:\n%s\nwant it to contain %q", a.String(), want)
+		}
+	}
+
+	for _, args := range [][]string{
+		{"-size", "0"},
+		{"-produce", "5..2"},
+		{"-access", "-1"},
+		{"extra"},
+	} {
+		err := run(context.Background(), io.Discard, append([]string{"binary_name", "buffer"}, args...)...)
+		if !errors.Is(err, ErrInvalidArgs) {
+			t.Errorf("buffer %v error = %v, want %v", args, err, ErrInvalidArgs)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"time"
+
+	"github.com/rks0134/CSCE4600/Project1/scheduler"
+)
+
+// cacheFormat versions the cache entries, to be bumped whenever a change to
+// the simulator or to ScheduleResult would make entries already saved wrong.
+const cacheFormat = 1
+
+// resultCache saves schedule results in a directory, keyed by the workload,
+// the algorithm, and its options, so repeated runs of the same simulation,
+// in sweeps or when only the report changes, skip straight to rendering.
+// A nil *resultCache caches nothing.
+type resultCache struct {
+	dir string
+}
+
+// resultCache returns the cache of the -cache directory, or nil without
+// one.
+func (g *globalFlags) resultCache() *resultCache {
+	if g.cache == "" {
+		return nil
+	}
+	return &resultCache{dir: g.cache}
+}
+
+// cacheKey is what a cached result is looked up by.
+type cacheKey struct {
+	Format int `json:"format"`
+	// Build is the version control revision of the binary, when it was
+	// stamped with one, so a rebuilt simulator does not reuse old results.
+	Build     string              `json:"build,omitempty"`
+	Algorithm string              `json:"algorithm"`
+	Options   scheduler.Options   `json:"options"`
+	Processes []scheduler.Process `json:"processes"`
+}
+
+// schedule runs the algorithm alg with opts over processes, or returns the
+// result cached from an earlier run, tracing it if ctx carries a tracer.
+// Only complete results are cached; failing to save one leaves it uncached
+// rather than failing the run.
+func (c *resultCache) schedule(ctx context.Context, alg algorithm, opts []scheduler.Option, processes []scheduler.Process) (*scheduler.ScheduleResult, error) {
+	start := time.Now()
+	r, cached, err := c.lookup(ctx, alg, opts, processes)
+	tracerFrom(ctx).traceRun(alg, start, len(processes), cached, r, err)
+
+	return r, err
+}
+
+// lookup is schedule without the tracing, also reporting whether the
+// result came from the cache.
+func (c *resultCache) lookup(ctx context.Context, alg algorithm, opts []scheduler.Option, processes []scheduler.Process) (*scheduler.ScheduleResult, bool, error) {
+	if c == nil {
+		r, err := alg.new(opts...).Schedule(ctx, processes)
+		return r, false, err
+	}
+	o := scheduler.DefaultOptions()
+	for _, opt := range opts {
+		opt(&o)
+	}
+	key, err := json.Marshal(cacheKey{Format: cacheFormat, Build: buildRevision(), Algorithm: alg.name, Options: o, Processes: processes})
+	if err != nil {
+		r, err := alg.new(opts...).Schedule(ctx, processes)
+		return r, false, err
+	}
+	sum := sha256.Sum256(key)
+	path := filepath.Join(c.dir, hex.EncodeToString(sum[:])+".json")
+
+	if data, err := os.ReadFile(path); err == nil {
+		var r scheduler.ScheduleResult
+		if json.Unmarshal(data, &r) == nil {
+			if o.Progress != nil {
+				o.Progress(r.Total, r.Total)
+			}
+			return &r, true, nil
+		}
+	}
+	r, err := alg.new(opts...).Schedule(ctx, processes)
+	if err != nil || r.Checkpoint != nil || r.Streamed {
+		return r, false, err
+	}
+	if data, err := json.Marshal(r); err == nil && os.MkdirAll(c.dir, 0o755) == nil {
+		// Written aside and renamed, so concurrent runs never read half
+		// an entry.
+		if f, err := os.CreateTemp(c.dir, ".tmp-*"); err == nil {
+			_, err = f.Write(data)
+			if closeErr := f.Close(); err == nil && closeErr == nil {
+				err = os.Rename(f.Name(), path)
+			}
+			if err != nil {
+				_ = os.Remove(f.Name())
+			}
+		}
+	}
+
+	return r, false, nil
+}
+
+// buildRevision returns the version control revision the binary was built
+// from, marked as modified if the tree had uncommitted changes, or "" when
+// the build was not stamped.
+func buildRevision() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return ""
+	}
+	var revision, modified string
+	for _, s := range info.Settings {
+		switch s.Key {
+		case "vcs.revision":
+			revision = s.Value
+		case "vcs.modified":
+			if s.Value == "true" {
+				modified = "+modified"
+			}
+		}
+	}
+	if revision == "" {
+		return ""
+	}
+	return revision + modified
+}
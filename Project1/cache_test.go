@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/rks0134/CSCE4600/Project1/scheduler"
+)
+
+func Test_resultCache(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	cache := &resultCache{dir: dir}
+	processes := []scheduler.Process{
+		{ProcessID: 1, BurstDuration: 5, Priority: 2, Cycles: []scheduler.Cycle{{IO: 2, CPU: 1}}},
+		{ProcessID: 2, BurstDuration: 3, ArrivalTime: 1, Priority: 1, Deadline: 9},
+	}
+	alg, _ := findAlgorithm("rr")
+	opts := []scheduler.Option{scheduler.WithSeed(1), scheduler.WithCPUs(2), scheduler.WithSwitchCost(1)}
+	want, err := cache.schedule(context.Background(), alg, opts, processes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := cache.schedule(context.Background(), alg, opts, processes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("cached result = %+v, want %+v", got, want)
+	}
+
+	// A second run renders what was cached rather than simulating again.
+	var first, second strings.Builder
+	if err := run(context.Background(), &first, "binary_name", "-cache", dir, "-seed", "1", "example_processes.csv"); err != nil {
+		t.Fatal(err)
+	}
+	entries, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil || len(entries) != len(forWorkload(algorithms(), "all", nil))+1 {
+		t.Fatalf("cache holds %d entries, want one per algorithm and the rr run: %v", len(entries), err)
+	}
+	for _, path := range entries {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		data = []byte(strings.Replace(string(data), `"average_wait":`, `"average_wait":1234,"ignored":`, 1))
+		if err := os.WriteFile(path, data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := run(context.Background(), &second, "binary_name", "-cache", dir, "-seed", "1", "example_processes.csv"); err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(second.String(), "1234.00"); n != len(forWorkload(algorithms(), "all", nil)) {
+		t.Errorf("second run shows the cached average wait %d times, want %d:\n%s", n, len(forWorkload(algorithms(), "all", nil)), second.String())
+	}
+}
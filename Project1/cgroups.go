@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/rks0134/CSCE4600/Project1/scheduler"
+)
+
+// defaultCPUWeight is the cpu.weight of a cgroup left at its default, the
+// share of a process at nice 0.
+const defaultCPUWeight = 100
+
+// cgroupsCmd writes a workload of one process per cgroup, weighted by the
+// cgroups' cpu.weight values.
+type cgroupsCmd struct {
+	root  string
+	burst int64
+}
+
+func (c *cgroupsCmd) defineFlags(flags *flag.FlagSet) {
+	flags.StringVar(&c.root, "root", "/sys/fs/cgroup", "the `directory` the cgroup v2 hierarchy is mounted on")
+	flags.Int64Var(&c.burst, "burst", 10, "the burst of every service's process, so only their weights tell them apart")
+}
+
+func (c *cgroupsCmd) run(_ context.Context, w io.Writer, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("%w: must give the services or cgroups to import", ErrInvalidArgs)
+	}
+	if c.burst <= 0 {
+		return fmt.Errorf("%w: -burst must be positive", ErrInvalidArgs)
+	}
+	processes := make([]scheduler.Process, len(args))
+	for k, service := range args {
+		weight, err := readCPUWeight(cgroupDir(c.root, service))
+		if err != nil {
+			return fmt.Errorf("%s: %w", service, err)
+		}
+		processes[k] = scheduler.Process{ProcessID: int64(k + 1), BurstDuration: c.burst, Nice: weightNice(weight)}
+	}
+
+	return writeWorkload(w, processes)
+}
+
+// cgroupDir returns the directory under root of service: a cgroup path
+// relative to root, such as system.slice/nginx.service, a top-level slice,
+// or else a systemd unit in system.slice, a service unless it says what
+// kind of unit it is.
+func cgroupDir(root, service string) string {
+	switch ext := filepath.Ext(service); {
+	case strings.Contains(service, "/"), ext == ".slice":
+		return filepath.Join(root, service)
+	case ext == "":
+		service += ".service"
+	}
+	return filepath.Join(root, "system.slice", service)
+}
+
+// readCPUWeight reads the cpu.weight of the cgroup in dir, which is only
+// there when the cpu controller is enabled for it.
+func readCPUWeight(dir string) (int64, error) {
+	data, err := os.ReadFile(filepath.Join(dir, "cpu.weight"))
+	if os.IsNotExist(err) {
+		if _, statErr := os.Stat(dir); statErr == nil {
+			return 0, fmt.Errorf("%w: no cpu.weight in %s; is the cpu controller enabled in its parent's cgroup.subtree_control?", err, dir)
+		}
+	}
+	if err != nil {
+		return 0, err
+	}
+	weight, err := strconv.ParseInt(strings.TrimSpace(string(data)), 10, 64)
+	if err != nil || weight < 1 || weight > 10000 {
+		return 0, fmt.Errorf("%s: malformed cpu.weight %q", dir, strings.TrimSpace(string(data)))
+	}
+
+	return weight, nil
+}
+
+// weightNice returns the nice value whose share of the CPU is closest to a
+// cpu.weight's, the inverse of the kernel's mapping: each step of nice
+// changes a share by 25%, and the default weight is nice 0.
+func weightNice(weight int64) int64 {
+	nice := int64(math.Round(-math.Log(float64(weight)/defaultCPUWeight) / math.Log(1.25)))
+	switch {
+	case nice < -20:
+		return -20
+	case nice > 19:
+		return 19
+	}
+	return nice
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func Test_weightNice(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		weight, want int64
+	}{
+		{weight: 100, want: 0},
+		{weight: 125, want: -1},
+		{weight: 80, want: 1},
+		{weight: 200, want: -3},
+		{weight: 1, want: 19},
+		{weight: 10000, want: -20},
+	}
+	for _, tt := range tests {
+		if got := weightNice(tt.weight); got != tt.want {
+			t.Errorf("weightNice(%d) = %d, want %d", tt.weight, got, tt.want)
+		}
+	}
+}
+
+func Test_runCgroups(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	for dir, weight := range map[string]string{
+		"system.slice/nginx.service":  "200\n",
+		"system.slice/cron.timer":     "100\n",
+		"user.slice":                  "50\n",
+		"batch/jobs":                  "10000\n",
+		"system.slice/broken.service": "heavy\n",
+	} {
+		if err := os.MkdirAll(filepath.Join(root, dir), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(root, dir, "cpu.weight"), []byte(weight), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.MkdirAll(filepath.Join(root, "system.slice", "nocpu.service"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	var b strings.Builder
+	if err := run(context.Background(), &b, "binary_name", "cgroups", "-root", root, "-burst", "20", "nginx", "cron.timer", "user.slice", "batch/jobs"); err != nil {
+		t.Fatal(err)
+	}
+	if want := "1,20,0,0,nice=-3\n2,20,0,0\n3,20,0,0,nice=3\n4,20,0,0,nice=-20\n"; b.String() != want {
+		t.Errorf("cgroups wrote\n%s\nwant\n%s", b.String(), want)
+	}
+
+	for _, tt := range []struct {
+		service string
+		want    error
+		message string
+	}{
+		{service: "missing", want: fs.ErrNotExist},
+		{service: "nocpu", want: fs.ErrNotExist, message: "cpu controller"},
+		{service: "broken", message: "malformed cpu.weight"},
+	} {
+		err := run(context.Background(), io.Discard, "binary_name", "cgroups", "-root", root, tt.service)
+		if err == nil || (tt.want != nil && !errors.Is(err, tt.want)) || !strings.Contains(err.Error(), tt.message) {
+			t.Errorf("cgroups %s error = %v, want %v mentioning %q", tt.service, err, tt.want, tt.message)
+		}
+	}
+}
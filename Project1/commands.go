@@ -0,0 +1,792 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rks0134/CSCE4600/Project1/scheduler"
+)
+
+// programName is the name of the built binary, used in help text.
+const programName = "Project1"
+
+// runner is the implementation of a subcommand: it registers its flags, then
+// runs with the positional arguments left after parsing them.
+type runner interface {
+	defineFlags(flags *flag.FlagSet)
+	run(ctx context.Context, w io.Writer, args []string) error
+}
+
+// command describes a subcommand for dispatch, --help, and the man page.
+type command struct {
+	name     string
+	args     string // positional arguments in the usage line
+	summary  string
+	details  string
+	examples []string
+	new      func(g *globalFlags) runner
+}
+
+// commands lists every subcommand; the first is run when none is named.
+var commands = []command{
+	{
+		name:    "schedule",
+		args:    "<file>",
+		summary: "Run every scheduler over a scheduling file",
+		details: "Each line of the scheduling file is a process record of the form " +
+			"<ProcessID>,<Burst Duration>,<Arrival Time>[,<Priority>]. " +
+			"A Gantt chart and schedule table is printed for each algorithm. " +
+			"This is the default command, so the name can be left out.",
+		examples: []string{
+			programName + " example_processes.csv",
+			programName + " schedule -format json example_processes.csv",
+			programName + " schedule -format trace example_processes.csv",
+		},
+		new: func(g *globalFlags) runner { return &scheduleCmd{globalFlags: g} },
+	},
+	{
+		name:    "resume",
+		args:    "<checkpoint>",
+		summary: "Continue simulations paused with schedule -checkpoint",
+		details: "Resumes every simulation saved in the checkpoint file by schedule -checkpoint-at " +
+			"and -checkpoint, with the options it was started with, and reports each complete " +
+			"schedule as if it had never been paused.",
+		examples: []string{
+			programName + " schedule -checkpoint-at 10 -checkpoint run.ckpt example_processes.csv",
+			programName + " resume run.ckpt",
+		},
+		new: func(g *globalFlags) runner { return &resumeCmd{globalFlags: g} },
+	},
+	{
+		name:    "bench",
+		summary: "Time each scheduler on generated workloads",
+		details: "Generates a workload of each size and reports ns/op, ops/sec, " +
+			"allocations, and bytes allocated per scheduler run. Only the simulation " +
+			"is timed, not rendering the report. Each scheduler runs on each size for " +
+			"at least -benchtime. -save records the results as a JSON baseline, and " +
+			"-baseline compares a run against one, failing if any scheduler got slower " +
+			"or allocates more by over -threshold percent.",
+		examples: []string{
+			programName + " bench -sizes 100,1k,10k -algorithms all",
+			programName + " bench -sizes 1k -algorithms rr,sjf",
+			programName + " bench -save bench.json",
+			programName + " bench -baseline bench.json -threshold 10",
+		},
+		new: func(g *globalFlags) runner { return &benchCmd{globalFlags: g} },
+	},
+	{
+		name:    "compare",
+		args:    "<file>",
+		summary: "Rank algorithms on one workload",
+		details: "Runs the selected algorithms on the scheduling file and ranks them " +
+			"per metric and by a weighted overall score, followed by a short summary " +
+			"of which algorithm won and why.",
+		examples: []string{
+			programName + " compare example_processes.csv",
+			programName + " compare -algorithms sjf,rr -weights wait=2,throughput=1 example_processes.csv",
+		},
+		new: func(g *globalFlags) runner { return &compareCmd{globalFlags: g} },
+	},
+	{
+		name:    "markdown",
+		args:    "<file>",
+		summary: "Summarize results as markdown for a pull request",
+		details: "Runs the selected algorithms on the scheduling file and writes a markdown " +
+			"table of their metrics, ready to post on a pull request or with an assignment " +
+			"submission. With -baseline, each metric shows its change from the result of the " +
+			"same algorithm in a file written by schedule -format json, marked better or worse, " +
+			"followed by a line naming every regression.",
+		examples: []string{
+			programName + " -output main.json schedule -format json example_processes.csv",
+			programName + " markdown -baseline main.json example_processes.csv > comment.md",
+			"gh pr comment --body-file comment.md",
+		},
+		new: func(g *globalFlags) runner { return &markdownCmd{globalFlags: g} },
+	},
+	{
+		name:    "diff",
+		args:    "<old file> <new file>",
+		summary: "Compare two files of results",
+		details: "Compares two files written by schedule -format json, matching schedules by " +
+			"title, and prints for each schedule that changed the first slice at which its Gantt " +
+			"charts diverge and every metric that differs, or that it is identical. Exits with " +
+			"code 7 when any schedule differs, so refactoring the engine can be checked to " +
+			"leave behavior unchanged.",
+		examples: []string{
+			programName + " -seed 1 -output old.json schedule -format json example_processes.csv",
+			programName + " diff old.json new.json",
+		},
+		new: func(*globalFlags) runner { return &diffCmd{} },
+	},
+	{
+		name:    "sweep",
+		args:    "<file>",
+		summary: "Sweep the quantum or jitter arrival times",
+		details: "Runs the selected quantum-based algorithms on the scheduling file once per quantum " +
+			"and prints average wait, average turnaround, and context switches per " +
+			"quantum, followed by a plot of average wait to help find the knee of the curve. " +
+			"With -jitter, instead runs the selected algorithms over -trials copies of the " +
+			"workload whose arrival times are shifted at random, and reports the mean, spread, " +
+			"and range of each metric next to the unjittered baseline.",
+		examples: []string{
+			programName + " sweep -quantum 1..16 example_processes.csv",
+			programName + " sweep -quantum 2..20:2 example_processes.csv",
+			programName + " -seed 7 sweep -jitter 2 -trials 200 -algorithms fcfs,rr example_processes.csv",
+		},
+		new: func(g *globalFlags) runner { return &sweepCmd{globalFlags: g} },
+	},
+	{
+		name:    "batch",
+		args:    "<file>...",
+		summary: "Run algorithms over many scheduling files",
+		details: "Runs the selected algorithms on every scheduling file, -jobs files at a time, " +
+			"and prints each algorithm's average wait, average turnaround, and throughput per " +
+			"file, followed by their means over the files. A file that cannot be loaded or " +
+			"scheduled is reported in its row and the rest are still processed; the command " +
+			"then exits with the code of the first failure.",
+		examples: []string{
+			programName + " batch submissions/*.csv",
+			programName + " batch -jobs 4 -algorithms fcfs,rr submissions/*.csv",
+			programName + " batch -webhook https://hooks.slack.com/services/... submissions/*.csv",
+		},
+		new: func(g *globalFlags) runner { return &batchCmd{globalFlags: g} },
+	},
+	{
+		name:    "convert",
+		args:    "<file> <binary file>",
+		summary: "Convert a scheduling file to a binary workload",
+		details: "Writes the processes of the scheduling file to a binary workload of fixed-size " +
+			"records, which every command accepts in place of a scheduling file and loads " +
+			"almost instantly by memory-mapping it, however large it is. Processes with I/O " +
+			"cycles, affinity, locks, parents, or suspensions cannot be converted. A file to " +
+			"write named .pb or .binpb is written instead as a protobuf Workload, as defined in " +
+			"proto/simulator.proto, which keeps every field.",
+		examples: []string{
+			programName + " convert big.csv big.wl",
+			programName + " compare big.wl",
+			programName + " convert example_processes.csv workload.pb",
+		},
+		new: func(*globalFlags) runner { return &convertCmd{} },
+	},
+	{
+		name:    "generate",
+		args:    "[file]",
+		summary: "Generate a random workload",
+		details: "Writes -n random processes as a scheduling file, or with -binary as a binary " +
+			"workload, to the file or else to standard output. Processes are drawn in shards " +
+			"of 65536 across every CPU, each shard from its own random stream derived from " +
+			"-seed, so the same -seed and -n give the same workload on any machine.",
+		examples: []string{
+			programName + " -seed 1 generate -n 10M big.csv",
+			programName + " -seed 1 generate -n 10M -binary big.wl",
+		},
+		new: func(g *globalFlags) runner { return &generateCmd{globalFlags: g} },
+	},
+	{
+		name:    "snapshot",
+		args:    "[file]",
+		summary: "Capture this machine's processes as a workload (Linux)",
+		details: "Samples /proc twice, -interval apart, and writes each process that ran in " +
+			"between as a scheduling file, to the file or else to standard output: its PID, " +
+			"the CPU time it used as its burst in time units of -unit, when it started as its " +
+			"arrival (0 if it was already running), and its priority and nice value. " +
+			"Real-time processes get priority 1 and the rest 11 to 50 by nice value, so the " +
+			"priority scheduler favours what the kernel favours.",
+		examples: []string{
+			programName + " snapshot -interval 5s -unit 100ms mine.csv",
+			programName + " compare mine.csv",
+		},
+		new: func(*globalFlags) runner { return &snapshotCmd{} },
+	},
+	{
+		name:    "cgroups",
+		args:    "<service>...",
+		summary: "Write a workload weighted by cgroup CPU weights",
+		details: "Reads the cgroup v2 cpu.weight of each service, a systemd unit in system.slice " +
+			"(a service unless it has a unit suffix), a top-level slice, or a cgroup path under " +
+			"-root, and writes a scheduling file with one process per service, numbered from 1 " +
+			"in the order given, each with a burst of -burst and the nice value whose share " +
+			"matches its weight: weight 100, the default, is nice 0, and each step of nice is " +
+			"25% more or less CPU, as in the kernel. Round-robin then slices the CPU in " +
+			"proportion to the weights.",
+		examples: []string{
+			programName + " -output weighted.csv cgroups nginx postgresql user.slice",
+			programName + " compare -algorithms rr,fcfs weighted.csv",
+		},
+		new: func(*globalFlags) runner { return &cgroupsCmd{} },
+	},
+	{
+		name:    "pods",
+		args:    "<manifest or directory>...",
+		summary: "Write a workload of Kubernetes pods",
+		details: "Reads the Kubernetes manifests given, JSON or YAML, and every .json, .yaml, and " +
+			".yml file in the directories given, and writes a scheduling file with a process for " +
+			"each Pod and each replica of a Deployment, StatefulSet, ReplicaSet, Job, or CronJob, " +
+			"numbered from 1 in order of namespace, kind, and name, each with a burst of -burst. " +
+			"Each process gets the nice value matching the CPU share the kubelet gives its " +
+			"CPU request (or limit, without one), and a priority ranking its priority class's " +
+			"value, from 1 for the highest; PriorityClass objects among the manifests define the " +
+			"classes besides the built-in ones.",
+		examples: []string{
+			programName + " -output cluster.csv pods manifests/",
+			"kubectl get pods,priorityclasses -A -o json > cluster.json && " + programName + " pods cluster.json",
+		},
+		new: func(*globalFlags) runner { return &podsCmd{} },
+	},
+	{
+		name:    "memory",
+		args:    "[<trace file>]",
+		summary: "Compare contiguous memory allocation algorithms",
+		details: "Runs first fit, best fit, worst fit, and the buddy system over a trace of requests " +
+			"and releases, a line such as A 212 requesting 212 units for A and free A releasing " +
+			"them, or over -generate N random operations, in -size units of memory. After each " +
+			"operation a map of memory is drawn with its external fragmentation, the share of free " +
+			"memory outside the largest free block, and its internal fragmentation, the space " +
+			"allocated beyond what was asked for, followed by a table comparing the algorithms.",
+		examples: []string{
+			programName + " memory trace.txt",
+			programName + " -seed 3 memory -generate 30 -size 256 -min-block 8",
+		},
+		new: func(g *globalFlags) runner { return &memoryCmd{globalFlags: g} },
+	},
+	{
+		name:    "paging",
+		args:    "[file]",
+		summary: "Compare page replacement algorithms",
+		details: "Runs FIFO, LRU, OPT, Clock, and LFU page replacement over the reference string " +
+			"in the file, page numbers separated by spaces, commas, or newlines, or over a " +
+			"generated one with locality of reference, and compares their page faults with " +
+			"-frames frames. A sweep over frame counts follows, flagging every instance of " +
+			"Belady's anomaly, where an algorithm faults more with more frames. -steps shows " +
+			"each algorithm's frames after every reference, as textbook exercises do.",
+		examples: []string{
+			programName + " paging -frames 3 -steps references.txt",
+			programName + " paging -algorithms fifo -sweep 1..6 references.txt",
+			programName + " -seed 7 paging -generate 1000 -pages 32 -frames 8",
+		},
+		new: func(g *globalFlags) runner { return &pagingCmd{globalFlags: g} },
+	},
+	{
+		name:    "translate",
+		args:    "file",
+		summary: "Translate virtual addresses to physical ones",
+		details: "Splits each virtual address in the file, decimal or 0x hex, into its page and " +
+			"offset and finds its frame, in the -page-table file or by demand paging into " +
+			"-frames frames with a page replacement algorithm, reporting each physical " +
+			"address and page fault, the fault rate, and the effective access time it gives " +
+			"with -memory-time memory accesses and -fault-time fault service.",
+		examples: []string{
+			programName + " translate -page-size 1024 -page-table table.txt addresses.txt",
+			programName + " translate -frames 4 -algorithm fifo -hex addresses.txt",
+		},
+		new: func(*globalFlags) runner { return &translateCmd{} },
+	},
+	{
+		name:    "disk",
+		args:    "[file]",
+		summary: "Compare disk scheduling algorithms",
+		details: "Runs FCFS, SSTF, SCAN, C-SCAN, LOOK, and C-LOOK over the request queue in the " +
+			"file, cylinder numbers separated by spaces, commas, or newlines, or over a " +
+			"generated one, with the head starting at -head and moving in -direction. Each " +
+			"algorithm's head movement is charted as the Gantt chart is for processes, and a " +
+			"table compares their total head movement, waits, and service order.",
+		examples: []string{
+			programName + " disk -head 53 requests.txt",
+			programName + " disk -head 53 -direction down -algorithms scan,look -waits requests.txt",
+			programName + " -seed 4 disk -generate 20 -cylinders 5000 -head 2150",
+		},
+		new: func(g *globalFlags) runner { return &diskCmd{globalFlags: g} },
+	},
+	{
+		name:    "banker",
+		args:    "file",
+		summary: "Avoid deadlock with the banker's algorithm",
+		details: "Reads the resources available and each process's allocation and maximum claim, " +
+			"prints the need matrix, and runs the safety algorithm step by step to find a safe " +
+			"sequence, or the processes that could not finish. Each -request is then granted if " +
+			"the state stays safe, or left waiting, and -interactive goes on to take requests " +
+			"and releases from standard input.",
+		examples: []string{
+			programName + " banker state.txt",
+			programName + " banker -request \"P1 1 0 2; P0 0 2 0\" state.txt",
+			programName + " banker -interactive state.txt",
+		},
+		new: func(*globalFlags) runner { return &bankerCmd{} },
+	},
+	{
+		name:    "deadlock",
+		args:    "file",
+		summary: "Detect deadlock on a resource allocation graph",
+		details: "Reads a resource allocation graph, its processes, its resources and their " +
+			"instances, and request and assignment edges, lists its cycles, and reduces it to " +
+			"find the processes deadlocked, which with one instance of every resource are those " +
+			"a cycle traps. A smallest set of victims whose termination ends the deadlock is " +
+			"then suggested. -format dot writes the graph for Graphviz instead, with the " +
+			"deadlock in red.",
+		examples: []string{
+			programName + " deadlock graph.txt",
+			programName + " deadlock -format dot graph.txt | dot -Tsvg -o graph.svg",
+		},
+		new: func(*globalFlags) runner { return &deadlockCmd{} },
+	},
+	{
+		name:    "buffer",
+		summary: "Simulate producers and consumers sharing a bounded buffer",
+		details: "Simulates -producers producers and -consumers consumers sharing a buffer of " +
+			"-size items through the empty, full, and mutex semaphores, for -duration time " +
+			"units, with the times to produce, consume, and access an item drawn from the given " +
+			"ranges. Each thread's timeline is drawn as the Gantt chart is, followed by the " +
+			"buffer's occupancy over time, each thread's time blocked on each semaphore, and " +
+			"the throughput.",
+		examples: []string{
+			programName + " -seed 1 buffer",
+			programName + " buffer -producers 4 -consumers 1 -size 3 -consume 5..9",
+		},
+		new: func(g *globalFlags) runner { return &bufferCmd{globalFlags: g} },
+	},
+	{
+		name:    "philosophers",
+		summary: "Compare dining philosophers strategies",
+		details: "Simulates -philosophers philosophers thinking and eating for -duration time " +
+			"units under each strategy: naive, taking the left fork and then the right; " +
+			"resource ordering, taking the lower-numbered fork first; a waiter handing out both " +
+			"forks at once; and Chandy-Misra, passing clean and dirty forks on request. Each " +
+			"timeline is drawn as the Gantt chart is, followed by a table of deadlocks, meals, " +
+			"the longest waits and starving philosophers, and the fairness of the meals.",
+		examples: []string{
+			programName + " -seed 1 philosophers",
+			programName + " philosophers -strategies naive,ordering -think 1..3 -reach 2",
+		},
+		new: func(g *globalFlags) runner { return &philosophersCmd{globalFlags: g} },
+	},
+	{
+		name:    "serve",
+		summary: "Serve the schedulers over HTTP",
+		details: "Listens on -port for HTTP requests until interrupted. POST a workload to " +
+			"/schedule, as a scheduling file or, with Content-Type application/json, as a JSON " +
+			"array of processes, to run the algorithms on it; the response is a JSON array of " +
+			"one result per algorithm, as schedule -format json reports them. Query parameters " +
+			"set algorithms (default all), quantum, seed, quiet, jitter, and the simulation " +
+			"flags, named as on the command line. Failures respond with status 400, 413, or " +
+			"500 and the error as -errors json reports it. GET /algorithms lists the algorithms. " +
+			"GET /healthz always succeeds while the server runs, and GET /readyz fails with 503 " +
+			"once it is asked to stop; on SIGTERM or an interrupt it keeps serving for -drain, " +
+			"then lets requests in flight finish for up to 10s.",
+		examples: []string{
+			programName + " serve -port 8080",
+			"curl --data-binary @example_processes.csv 'localhost:8080/schedule?algorithms=rr,sjf&quantum=4&seed=1'",
+		},
+		new: func(g *globalFlags) runner { return &serveCmd{globalFlags: g} },
+	},
+	{
+		name:     "list-algorithms",
+		summary:  "List the registered scheduling algorithms",
+		details:  "Names listed here are accepted by the -algorithms flag of bench and compare.",
+		examples: []string{programName + " list-algorithms"},
+		new:      func(*globalFlags) runner { return &listAlgorithmsCmd{} },
+	},
+	{
+		name:    "help",
+		args:    "[command]",
+		summary: "Show help for the program or a command",
+		examples: []string{
+			programName + " help",
+			programName + " help compare",
+		},
+		new: func(*globalFlags) runner { return &helpCmd{} },
+	},
+	{
+		name:     "man",
+		summary:  "Print a man page in roff format",
+		examples: []string{programName + " man > " + strings.ToLower(programName) + ".1"},
+		new:      func(*globalFlags) runner { return &manCmd{} },
+	},
+}
+
+func findCommand(name string) (command, bool) {
+	for _, c := range commands {
+		if c.name == name {
+			return c, true
+		}
+	}
+
+	return command{}, false
+}
+
+// globalFlags are accepted before the command name as well as among the
+// flags of every command.
+type globalFlags struct {
+	seed       seedFlag
+	progress   string
+	cpuProfile string
+	memProfile string
+	errors     string
+	cache      string
+	output     string
+	otlp       string
+	otlpSlices bool
+}
+
+func (g *globalFlags) define(flags *flag.FlagSet) {
+	flags.Var(&g.seed, "seed", "`seed` for all randomness such as workload generation and random tie-breaks (default random, printed in every report)")
+	flags.StringVar(&g.progress, "progress", "auto", "show progress on stderr: auto (only on a terminal), always, or never")
+	flags.StringVar(&g.cpuProfile, "cpuprofile", "", "write a CPU profile to `file`")
+	flags.StringVar(&g.memProfile, "memprofile", "", "write a heap profile to `file` on exit")
+	flags.StringVar(&g.errors, "errors", "text", "report errors on stderr as text or json (file, line, column, message)")
+	flags.StringVar(&g.cache, "cache", "", "cache simulation results in `dir`, keyed by workload, algorithm, and options, so repeated runs only render them again")
+	flags.StringVar(&g.otlp, "otlp", "", "export OpenTelemetry spans of the command and each simulation to the OTLP/HTTP traces `URL`, such as http://localhost:4318/v1/traces (default from OTEL_EXPORTER_OTLP_TRACES_ENDPOINT or OTEL_EXPORTER_OTLP_ENDPOINT, else none)")
+	flags.BoolVar(&g.otlpSlices, "otlp-slices", false, "also export a span per Gantt slice, up to 1000 per simulation, laid out in proportion within the simulation's span")
+	flags.StringVar(&g.output, "output", "", "write the report to `file`, or to an s3:// or gs:// object, instead of standard output")
+}
+
+// run runs the command named name once all flags are parsed, under the
+// profilers and tracing requested by the global flags.
+func (g *globalFlags) run(ctx context.Context, w io.Writer, name string, r runner, args []string) (err error) {
+	switch g.errors {
+	case "text":
+	case "json":
+		defer func() {
+			if err != nil {
+				err = &jsonError{err: err}
+			}
+		}()
+	default:
+		return fmt.Errorf("%w: unknown error format %q", ErrInvalidArgs, g.errors)
+	}
+	g.seed.resolve()
+
+	if g.output != "" {
+		out, finish, err := createOutput(g.output)
+		if err != nil {
+			return err
+		}
+		w = out
+		defer func() {
+			if finishErr := finish(err); err == nil {
+				err = finishErr
+			}
+		}()
+	}
+
+	if endpoint := otlpEndpoint(g.otlp); endpoint != "" {
+		t := startTracer(endpoint, name, g.otlpSlices)
+		ctx = withTracer(ctx, t)
+		defer func() { t.close(err) }()
+	}
+
+	stop, err := startProfiling(g.cpuProfile, g.memProfile)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if stopErr := stop(); err == nil {
+			err = stopErr
+		}
+	}()
+
+	return r.run(ctx, w, args)
+}
+
+// progressBar returns a progress bar on stderr for the -progress mode, or nil
+// when progress is disabled.
+func (g *globalFlags) progressBar(label string) (*progressBar, error) {
+	w, err := progressOutput(g.progress)
+	if w == nil {
+		return nil, err
+	}
+
+	return newProgressBar(w, label), nil
+}
+
+// progressOption reports a scheduler's progress under label, if enabled.
+func (g *globalFlags) progressOption(label string) (scheduler.Option, error) {
+	bar, err := g.progressBar(label)
+	if bar == nil {
+		return scheduler.WithProgress(nil), err
+	}
+
+	return scheduler.WithProgress(bar.update), nil
+}
+
+// rand returns a random source seeded by the -seed flag.
+func (g *globalFlags) rand() *rand.Rand {
+	return rand.New(rand.NewSource(g.seed.value))
+}
+
+// seedFlag is a flag.Value that picks a random seed when none is given.
+type seedFlag struct {
+	value int64
+	set   bool
+	// random is set when the seed was picked rather than given.
+	random bool
+}
+
+func (f *seedFlag) String() string {
+	if f == nil || !f.set {
+		return "random"
+	}
+	return strconv.FormatInt(f.value, 10)
+}
+
+func (f *seedFlag) Set(s string) error {
+	v, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return err
+	}
+	f.value, f.set = v, true
+
+	return nil
+}
+
+// resolve picks a seed from the clock unless one was given.
+func (f *seedFlag) resolve() {
+	if !f.set {
+		f.value, f.set, f.random = time.Now().UnixNano(), true, true
+	}
+}
+
+// dispatch parses the flags preceding the command name, which may be global
+// flags or flags of the default command, and runs the named command, or the
+// default command when args does not start with a command name.
+func dispatch(ctx context.Context, w io.Writer, args []string) error {
+	g := &globalFlags{}
+	flags, r := commands[0].flagSet(g)
+	flags.Usage = func() { printHelp(w) }
+	if err := flags.Parse(args); err != nil {
+		return flagError(err)
+	}
+
+	rest := flags.Args()
+	if len(rest) > 0 {
+		if c, ok := findCommand(rest[0]); ok {
+			var (
+				err     error
+				globals []*flag.Flag
+			)
+			flags.Visit(func(f *flag.Flag) {
+				if !isGlobalFlag(f.Name) && err == nil {
+					err = fmt.Errorf("%w: flag -%s must follow the %s command", ErrInvalidArgs, f.Name, commands[0].name)
+				}
+				globals = append(globals, f)
+			})
+			if err != nil {
+				return err
+			}
+			return c.execute(ctx, w, globals, rest[1:])
+		}
+	}
+	return g.run(ctx, w, commands[0].name, r, rest)
+}
+
+func isGlobalFlag(name string) bool {
+	for _, f := range globalFlagList() {
+		if f.Name == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// flagSet returns the command's flags, bound to a new runner.
+func (c command) flagSet(g *globalFlags) (*flag.FlagSet, runner) {
+	r := c.new(g)
+	flags := flag.NewFlagSet(c.name, flag.ContinueOnError)
+	r.defineFlags(flags)
+	g.define(flags)
+	flags.Usage = func() { printCommandHelp(flags.Output(), c) }
+
+	return flags, r
+}
+
+// execute parses the command's flags from args and runs it.
+// Global flags given before the command name are carried over in globals.
+func (c command) execute(ctx context.Context, w io.Writer, globals []*flag.Flag, args []string) error {
+	g := &globalFlags{}
+	flags, r := c.flagSet(g)
+	for _, f := range globals {
+		if err := flags.Set(f.Name, f.Value.String()); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidArgs, err)
+		}
+	}
+	if err := flags.Parse(args); err != nil {
+		return flagError(err)
+	}
+	return g.run(ctx, w, c.name, r, flags.Args())
+}
+
+// flagError reports a flag parsing failure as invalid arguments,
+// and asking for help as no failure at all.
+func flagError(err error) error {
+	if errors.Is(err, flag.ErrHelp) {
+		return nil
+	}
+
+	return fmt.Errorf("%w: %v", ErrInvalidArgs, err)
+}
+
+func (c command) usage() string {
+	u := programName + " " + c.name
+	if hasFlags(c) {
+		u += " [flags]"
+	}
+	if c.args != "" {
+		u += " " + c.args
+	}
+
+	return u
+}
+
+func hasFlags(c command) bool {
+	return len(commandFlags(c)) > 0
+}
+
+// commandFlags returns the flags specific to a command, without the global flags.
+func commandFlags(c command) []*flag.Flag {
+	flags, _ := c.flagSet(&globalFlags{})
+	var list []*flag.Flag
+	flags.VisitAll(func(f *flag.Flag) {
+		if !isGlobalFlag(f.Name) {
+			list = append(list, f)
+		}
+	})
+
+	return list
+}
+
+// helpCmd prints the program overview, or the help of one command.
+type helpCmd struct{}
+
+func (c *helpCmd) defineFlags(*flag.FlagSet) {}
+
+func (c *helpCmd) run(_ context.Context, w io.Writer, args []string) error {
+	if len(args) == 0 {
+		printHelp(w)
+		return nil
+	}
+	found, ok := findCommand(args[0])
+	if !ok {
+		return fmt.Errorf("%w: unknown command %q", ErrInvalidArgs, args[0])
+	}
+	printCommandHelp(w, found)
+
+	return nil
+}
+
+func printHelp(w io.Writer) {
+	_, _ = fmt.Fprintf(w, "%s runs CPU scheduling algorithms over a workload of processes.\n\n", programName)
+	_, _ = fmt.Fprintf(w, "Usage:\n  %s <command> [flags] [arguments]\n  %s [flags] <file>  (same as \"schedule\")\n\n", programName, programName)
+	_, _ = fmt.Fprintln(w, "Commands:")
+	for _, c := range commands {
+		_, _ = fmt.Fprintf(w, "  %-16s %s\n", c.name, c.summary)
+	}
+	_, _ = fmt.Fprintln(w, "\nGlobal flags (before the command or among its flags):")
+	printFlags(w, globalFlagList())
+	_, _ = fmt.Fprintln(w, "\nExit codes:")
+	for _, e := range exitCodes {
+		_, _ = fmt.Fprintf(w, "  %-4d %s\n", e.code, e.meaning)
+	}
+	_, _ = fmt.Fprintf(w, "\nRun \"%s help <command>\" for details on a command.\n", programName)
+}
+
+func printCommandHelp(w io.Writer, c command) {
+	_, _ = fmt.Fprintf(w, "Usage: %s\n\n%s.\n", c.usage(), c.summary)
+	if c.details != "" {
+		_, _ = fmt.Fprintf(w, "\n%s\n", c.details)
+	}
+	if hasFlags(c) {
+		_, _ = fmt.Fprintln(w, "\nFlags:")
+		printFlags(w, commandFlags(c))
+	}
+	_, _ = fmt.Fprintln(w, "\nGlobal flags:")
+	printFlags(w, globalFlagList())
+	if len(c.examples) > 0 {
+		_, _ = fmt.Fprintln(w, "\nExamples:")
+		for _, e := range c.examples {
+			_, _ = fmt.Fprintf(w, "  %s\n", e)
+		}
+	}
+}
+
+func globalFlagList() []*flag.Flag {
+	var g globalFlags
+	flags := flag.NewFlagSet("", flag.ContinueOnError)
+	g.define(flags)
+	var list []*flag.Flag
+	flags.VisitAll(func(f *flag.Flag) { list = append(list, f) })
+
+	return list
+}
+
+// printFlags prints flags in the style of flag.PrintDefaults.
+func printFlags(w io.Writer, list []*flag.Flag) {
+	flags := flag.NewFlagSet("", flag.ContinueOnError)
+	for _, f := range list {
+		flags.Var(f.Value, f.Name, f.Usage)
+	}
+	flags.SetOutput(w)
+	flags.PrintDefaults()
+}
+
+// manCmd prints a man page generated from the command descriptions.
+type manCmd struct{}
+
+func (c *manCmd) defineFlags(*flag.FlagSet) {}
+
+func (c *manCmd) run(_ context.Context, w io.Writer, _ []string) error {
+	name := strings.ToUpper(programName)
+	_, _ = fmt.Fprintf(w, ".TH %s 1\n", name)
+	_, _ = fmt.Fprintf(w, ".SH NAME\n%s \\- CPU process scheduling simulator\n", roff(programName))
+	_, _ = fmt.Fprintln(w, ".SH SYNOPSIS")
+	for _, c := range commands {
+		_, _ = fmt.Fprintf(w, ".B %s\n.br\n", roff(c.usage()))
+	}
+	_, _ = fmt.Fprintf(w, ".SH DESCRIPTION\n%s runs CPU scheduling algorithms over a workload of processes "+
+		"and reports Gantt charts, schedule tables, and summary metrics.\n", roff(programName))
+	_, _ = fmt.Fprintln(w, ".SH COMMANDS")
+	for _, c := range commands {
+		_, _ = fmt.Fprintf(w, ".SS %s\n%s.\n", roff(c.name), roff(c.summary))
+		if c.details != "" {
+			_, _ = fmt.Fprintf(w, ".PP\n%s\n", roff(c.details))
+		}
+		manFlags(w, commandFlags(c))
+	}
+	_, _ = fmt.Fprintln(w, ".SH GLOBAL FLAGS\nAccepted before the command name or among the flags of any command.")
+	manFlags(w, globalFlagList())
+	_, _ = fmt.Fprintln(w, ".SH EXAMPLES")
+	for _, c := range commands {
+		for _, e := range c.examples {
+			_, _ = fmt.Fprintf(w, ".PP\n.B %s\n", roff(e))
+		}
+	}
+	_, _ = fmt.Fprintln(w, ".SH EXIT STATUS")
+	for _, e := range exitCodes {
+		_, _ = fmt.Fprintf(w, ".TP\n.B %d\n%s\n", e.code, roff(e.meaning))
+	}
+
+	return nil
+}
+
+func manFlags(w io.Writer, list []*flag.Flag) {
+	for _, f := range list {
+		arg, usage := flag.UnquoteUsage(f)
+		_, _ = fmt.Fprintf(w, ".TP\n.BI \\-%s \" %s\"\n%s (default: %s)\n", roff(f.Name), roff(arg), roff(usage), roff(f.DefValue))
+	}
+}
+
+// roff escapes text for use in a man page line.
+func roff(s string) string {
+	s = strings.NewReplacer(`\`, `\e`, "-", `\-`).Replace(s)
+	if strings.HasPrefix(s, ".") || strings.HasPrefix(s, "'") {
+		s = `\&` + s
+	}
+
+	return s
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_printCommandHelp(t *testing.T) {
+	t.Parallel()
+	for _, c := range commands {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+			var w strings.Builder
+			printCommandHelp(&w, c)
+			for _, want := range append([]string{c.usage(), c.summary}, c.examples...) {
+				if !strings.Contains(w.String(), want) {
+					t.Errorf("help for %s is missing %q:\n%s", c.name, want, w.String())
+				}
+			}
+		})
+	}
+}
+
+func Test_roff(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{in: "-sizes", want: `\-sizes`},
+		{in: `a\b`, want: `a\eb`},
+		{in: ".hidden", want: `\&.hidden`},
+	}
+	for _, tt := range tests {
+		if got := roff(tt.in); got != tt.want {
+			t.Errorf("roff(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func Test_seedFlag(t *testing.T) {
+	t.Parallel()
+	var explicit seedFlag
+	if err := explicit.Set("42"); err != nil {
+		t.Fatal(err)
+	}
+	explicit.resolve()
+	if explicit.value != 42 || explicit.String() != "42" {
+		t.Errorf("explicit seed = %v (%s), want 42", explicit.value, explicit.String())
+	}
+
+	var random seedFlag
+	if random.String() != "random" {
+		t.Errorf("unset seed String() = %q, want random", random.String())
+	}
+	random.resolve()
+	if !random.set {
+		t.Error("resolve() did not pick a seed")
+	}
+
+	if err := new(seedFlag).Set("abc"); err == nil {
+		t.Error("Set(abc) succeeded, want error")
+	}
+}
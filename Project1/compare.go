@@ -0,0 +1,325 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+
+	"github.com/rks0134/CSCE4600/Project1/scheduler"
+)
+
+// metric is one of the schedule metrics algorithms are ranked on.
+type metric struct {
+	name           string
+	label          string
+	higherIsBetter bool
+	value          func(scheduler.Metrics) float64
+}
+
+var compareMetrics = []metric{
+	{name: "wait", label: "average wait", value: func(m scheduler.Metrics) float64 { return m.AverageWait }},
+	{name: "turnaround", label: "average turnaround", value: func(m scheduler.Metrics) float64 { return m.AverageTurnaround }},
+	{name: "throughput", label: "throughput", higherIsBetter: true, value: func(m scheduler.Metrics) float64 { return m.Throughput }},
+}
+
+// standing is an algorithm's placing in a comparison.
+type standing struct {
+	alg     algorithm
+	metrics scheduler.Metrics
+	total   int   // processes in the workload
+	ranks   []int // per compareMetrics entry, 1 is best
+	score   float64
+	// violations counts the invariants the schedule broke.
+	violations int
+}
+
+// complete reports whether the algorithm finished every process; averages
+// over a partial run are not comparable with those of a complete one.
+func (s standing) complete() bool { return s.metrics.Completed+s.metrics.Killed == s.total }
+
+// compareCmd runs the selected algorithms on one workload and ranks them.
+type compareCmd struct {
+	*globalFlags
+	simulationFlags
+	algorithms string
+	weights    string
+}
+
+func (c *compareCmd) defineFlags(flags *flag.FlagSet) {
+	flags.StringVar(&c.algorithms, "algorithms", "all", "comma separated `algorithms` to compare, or all")
+	flags.StringVar(&c.weights, "weights", "wait=1,turnaround=1,throughput=1", "comma separated metric=`weight` pairs for the overall score")
+	c.defineJitter(flags, "shift each arrival by `n` time units drawn from -jitter-dist before scheduling, for robustness experiments and workload variants (reproducible with -seed)")
+	c.simulationFlags.define(flags)
+}
+
+func (c *compareCmd) run(ctx context.Context, w io.Writer, args []string) error {
+	algs, err := selectAlgorithms(c.algorithms)
+	if err != nil {
+		return err
+	}
+	weights, err := parseWeights(c.weights)
+	if err != nil {
+		return err
+	}
+	path, err := workloadPath(args)
+	if err != nil {
+		return err
+	}
+	processes, err := c.load(path)
+	if err != nil {
+		return err
+	}
+	if processes, err = c.jittered(c.rand(), processes); err != nil {
+		return err
+	}
+
+	algs = forWorkload(algs, c.algorithms, processes)
+	jobs := make([]job, 0, len(algs))
+	for _, alg := range algs {
+		progress, err := c.progressOption(alg.name)
+		if err != nil {
+			return err
+		}
+		opts, err := c.options(scheduler.WithSeed(c.seed.value), progress)
+		if err != nil {
+			return err
+		}
+		jobs = append(jobs, job{alg: alg, opts: opts})
+	}
+	scheduleAll(ctx, c.resultCache(), jobs, processes)
+
+	standings := make([]standing, 0, len(algs))
+	for _, j := range jobs {
+		if err := j.err; err != nil {
+			// Rank the algorithms that finished before the interruption.
+			if len(standings) > 0 && errors.Is(err, context.Canceled) {
+				rank(standings, weights)
+				outputComparison(w, c.seed.value, standings)
+				_, _ = fmt.Fprintf(w, "Cancelled after %d of %d algorithms.\n", len(standings), len(algs))
+			}
+			return err
+		}
+		r := j.r
+		standings = append(standings, standing{alg: j.alg, metrics: r.UnitMetrics(), total: r.Total, violations: len(r.Violations)})
+		r.Release()
+	}
+	rank(standings, weights)
+
+	outputComparison(w, c.seed.value, standings)
+	_, _ = fmt.Fprintln(w, summarize(standings))
+	for _, s := range standings {
+		if s.violations > 0 {
+			_, _ = fmt.Fprintf(w, "INVALID SCHEDULE: %s broke %d invariants; run schedule to see them.\n", s.alg.title, s.violations)
+		}
+	}
+
+	return nil
+}
+
+// parseWeights parses metric=weight pairs; metrics left out get no weight.
+func parseWeights(list string) ([]float64, error) {
+	weights := make([]float64, len(compareMetrics))
+	for _, pair := range strings.Split(list, ",") {
+		name, value, _ := strings.Cut(strings.TrimSpace(pair), "=")
+		i := metricIndex(name)
+		if i < 0 {
+			return nil, fmt.Errorf("%w: unknown metric %q", ErrInvalidArgs, name)
+		}
+		weight, err := strconv.ParseFloat(value, 64)
+		if err != nil || weight < 0 {
+			return nil, fmt.Errorf("%w: invalid weight %q for %s", ErrInvalidArgs, value, name)
+		}
+		weights[i] = weight
+	}
+
+	return weights, nil
+}
+
+func metricIndex(name string) int {
+	for i := range compareMetrics {
+		if compareMetrics[i].name == name {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// rank fills in each standing's per-metric ranks and weighted score, then sorts
+// the standings best first. A metric's score is its value relative to the best
+// value for that metric, clamped to [0,1], so the best algorithm on every
+// metric scores 1. Algorithms that did not complete every process score 0 and
+// rank below all that did.
+func rank(standings []standing, weights []float64) {
+	var totalWeight float64
+	for _, weight := range weights {
+		totalWeight += weight
+	}
+	anyComplete := false
+	for _, s := range standings {
+		anyComplete = anyComplete || s.complete()
+	}
+
+	for m, mt := range compareMetrics {
+		best, found := 0.0, false
+		for i := range standings {
+			if anyComplete && !standings[i].complete() {
+				continue
+			}
+			if v := mt.value(standings[i].metrics); !found || better(mt, v, best) {
+				best, found = v, true
+			}
+		}
+		for i := range standings {
+			v := mt.value(standings[i].metrics)
+			place := 1
+			for j := range standings {
+				if ahead(mt, standings[j], standings[i]) {
+					place++
+				}
+			}
+			standings[i].ranks = append(standings[i].ranks, place)
+			if totalWeight > 0 && standings[i].complete() {
+				standings[i].score += weights[m] * relative(mt, v, best) / totalWeight
+			}
+		}
+	}
+
+	sort.SliceStable(standings, func(i, j int) bool {
+		if standings[i].complete() != standings[j].complete() {
+			return standings[i].complete()
+		}
+		return standings[i].score > standings[j].score
+	})
+}
+
+// ahead reports whether a places before b on metric mt.
+func ahead(mt metric, a, b standing) bool {
+	if a.complete() != b.complete() {
+		return a.complete()
+	}
+	return better(mt, mt.value(a.metrics), mt.value(b.metrics))
+}
+
+func better(mt metric, a, b float64) bool {
+	if mt.higherIsBetter {
+		return a > b
+	}
+	return a < b
+}
+
+// relative scores v against the best value, from 1 for the best down to 0.
+func relative(mt metric, v, best float64) float64 {
+	switch {
+	case v == best:
+		return 1
+	case mt.higherIsBetter && best > 0:
+		return math.Max(v/best, 0)
+	case !mt.higherIsBetter && best >= 0 && v > 0:
+		return best / v
+	default:
+		// Negative or zero values have no meaningful ratio to the best.
+		return 0
+	}
+}
+
+func outputComparison(w io.Writer, seed int64, standings []standing) {
+	_, _ = fmt.Fprintf(w, "Comparison (seed %d)\n", seed)
+	table := tablewriter.NewWriter(w)
+	header := []string{"Rank", "Algorithm"}
+	for _, mt := range compareMetrics {
+		header = append(header, mt.label)
+	}
+	// Migrations only happen on several CPUs; show them when any did.
+	migrated := false
+	for _, s := range standings {
+		migrated = migrated || s.metrics.Migrations > 0
+	}
+	if migrated {
+		header = append(header, "migration penalty")
+	}
+	powered := false
+	for _, s := range standings {
+		powered = powered || s.metrics.Energy > 0
+	}
+	if powered {
+		header = append(header, "energy")
+	}
+	table.SetHeader(append(header, "Score"))
+	for i, s := range standings {
+		row := []string{fmt.Sprint(i + 1), s.alg.name}
+		for m, mt := range compareMetrics {
+			row = append(row, fmt.Sprintf("%.2f (#%d)", mt.value(s.metrics), s.ranks[m]))
+		}
+		if migrated {
+			row = append(row, fmt.Sprintf("%.2f (%d)", s.metrics.MigrationPenalty, s.metrics.Migrations))
+		}
+		if powered {
+			row = append(row, fmt.Sprintf("%.2f", s.metrics.Energy))
+		}
+		score := fmt.Sprintf("%.2f", s.score)
+		if !s.complete() {
+			score = fmt.Sprintf("incomplete (%d of %d)", s.metrics.Completed, s.total)
+		}
+		table.Append(append(row, score))
+	}
+	table.Render()
+}
+
+// summarize explains in one paragraph which algorithm won and why.
+func summarize(standings []standing) string {
+	winner := standings[0]
+	var led, trailed []string
+	for m, mt := range compareMetrics {
+		if winner.ranks[m] == 1 {
+			led = append(led, mt.label)
+			continue
+		}
+		for _, s := range standings {
+			if s.ranks[m] == 1 {
+				trailed = append(trailed, fmt.Sprintf("%s, where %s led", mt.label, s.alg.name))
+				break
+			}
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s won with an overall score of %.2f", winner.alg.name, winner.score)
+	if len(led) > 0 {
+		fmt.Fprintf(&b, ", ranking first on %s", joinList(led, "and"))
+	}
+	b.WriteString(".")
+	if len(trailed) > 0 {
+		fmt.Fprintf(&b, " It trailed on %s.", strings.Join(trailed, "; "))
+	}
+	if len(standings) > 1 && standings[1].complete() {
+		fmt.Fprintf(&b, " %s came second with %.2f.", standings[1].alg.name, standings[1].score)
+	}
+	var incomplete []string
+	for _, s := range standings {
+		if !s.complete() {
+			incomplete = append(incomplete, fmt.Sprintf("%s (%d of %d)", s.alg.name, s.metrics.Completed, s.total))
+		}
+	}
+	if len(incomplete) > 0 {
+		fmt.Fprintf(&b, " Ranked last for not completing every process: %s.", joinList(incomplete, "and"))
+	}
+
+	return b.String()
+}
+
+// joinList joins items as English prose: "a", "a and b", "a, b and c".
+func joinList(items []string, conjunction string) string {
+	if len(items) < 2 {
+		return strings.Join(items, "")
+	}
+	return strings.Join(items[:len(items)-1], ", ") + " " + conjunction + " " + items[len(items)-1]
+}
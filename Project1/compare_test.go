@@ -0,0 +1,68 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/rks0134/CSCE4600/Project1/scheduler"
+)
+
+func Test_rank(t *testing.T) {
+	t.Parallel()
+	standings := []standing{
+		{alg: algorithm{name: "slow"}, metrics: scheduler.Metrics{AverageWait: 4, AverageTurnaround: 10, Throughput: 0.1, Completed: 3}, total: 3},
+		{alg: algorithm{name: "partial"}, metrics: scheduler.Metrics{AverageWait: -4, AverageTurnaround: 1, Throughput: 1, Completed: 1}, total: 3},
+		{alg: algorithm{name: "fast"}, metrics: scheduler.Metrics{AverageWait: 2, AverageTurnaround: 5, Throughput: 0.2, Completed: 3}, total: 3},
+		{alg: algorithm{name: "mixed"}, metrics: scheduler.Metrics{AverageWait: 1, AverageTurnaround: 10, Throughput: 0.1, Completed: 3}, total: 3},
+	}
+	rank(standings, []float64{1, 1, 1})
+
+	var got []string
+	for _, s := range standings {
+		got = append(got, s.alg.name)
+		if s.score < 0 || s.score > 1 {
+			t.Errorf("rank() score for %s = %v, want within [0,1]", s.alg.name, s.score)
+		}
+	}
+	if want := []string{"fast", "mixed", "slow", "partial"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("rank() order = %v, want %v", got, want)
+	}
+	if want := []int{2, 1, 1}; !reflect.DeepEqual(standings[0].ranks, want) {
+		t.Errorf("rank() winner ranks = %v, want %v", standings[0].ranks, want)
+	}
+
+	summary := summarize(standings)
+	for _, want := range []string{"fast won", "ranking first on average turnaround and throughput", "where mixed led", "mixed came second", "not completing every process: partial (1 of 3)"} {
+		if !strings.Contains(summary, want) {
+			t.Errorf("summarize() = %q, missing %q", summary, want)
+		}
+	}
+}
+
+func Test_relative(t *testing.T) {
+	t.Parallel()
+	wait, throughput := compareMetrics[0], compareMetrics[2]
+	tests := []struct {
+		name    string
+		mt      metric
+		v, best float64
+		want    float64
+	}{
+		{name: "best", mt: wait, v: 2, best: 2, want: 1},
+		{name: "half as good", mt: wait, v: 4, best: 2, want: 0.5},
+		{name: "zero best wait", mt: wait, v: 4, best: 0, want: 0},
+		{name: "negative best wait", mt: wait, v: 1, best: -4, want: 0},
+		{name: "higher is better", mt: throughput, v: 0.1, best: 0.2, want: 0.5},
+		{name: "negative throughput", mt: throughput, v: -1, best: 0.2, want: 0},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := relative(tt.mt, tt.v, tt.best); got != tt.want {
+				t.Errorf("relative() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
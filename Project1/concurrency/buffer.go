@@ -0,0 +1,190 @@
+package concurrency
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+// Range is a span of durations, from Min to Max time units inclusive, each
+// drawn uniformly.
+type Range struct {
+	Min, Max int
+}
+
+func (r Range) draw(rng *rand.Rand) int {
+	if r.Max <= r.Min {
+		return r.Min
+	}
+	return r.Min + rng.Intn(r.Max-r.Min+1)
+}
+
+func (r Range) String() string {
+	if r.Max <= r.Min {
+		return fmt.Sprint(r.Min)
+	}
+	return fmt.Sprintf("%d to %d", r.Min, r.Max)
+}
+
+// BufferConfig sets up a producer-consumer simulation.
+type BufferConfig struct {
+	Producers, Consumers int
+	// Size is the number of items the buffer holds.
+	Size int
+	// Produce and Consume are how long producing and consuming an item
+	// take, and Access how long inserting or removing one takes, holding
+	// the buffer's lock.
+	Produce, Consume, Access Range
+	// Duration is how long to simulate.
+	Duration int
+}
+
+// Level is the number of items in the buffer from the time At until the
+// next level.
+type Level struct {
+	At, Items int
+}
+
+// BufferResult is a producer-consumer simulation's outcome.
+type BufferResult struct {
+	Config             BufferConfig
+	Produced, Consumed int
+	// Threads are the producers and then the consumers.
+	Threads []*Thread
+	// Occupancy is each change in the number of items in the buffer,
+	// starting from an empty one at 0.
+	Occupancy []Level
+}
+
+// Throughput is the items consumed per time unit.
+func (r *BufferResult) Throughput() float64 {
+	return float64(r.Consumed) / float64(r.Config.Duration)
+}
+
+// MeanOccupancy is the mean number of items in the buffer over time.
+func (r *BufferResult) MeanOccupancy() float64 {
+	total := 0
+	for k, l := range r.Occupancy {
+		total += l.Items * (r.levelEnd(k) - l.At)
+	}
+	return float64(total) / float64(r.Config.Duration)
+}
+
+// TimeAt sums the time the buffer held items items.
+func (r *BufferResult) TimeAt(items int) int {
+	total := 0
+	for k, l := range r.Occupancy {
+		if l.Items == items {
+			total += r.levelEnd(k) - l.At
+		}
+	}
+	return total
+}
+
+// ItemsAt is the number of items in the buffer at the time at.
+func (r *BufferResult) ItemsAt(at int) int {
+	items := 0
+	for _, l := range r.Occupancy {
+		if l.At > at {
+			break
+		}
+		items = l.Items
+	}
+	return items
+}
+
+func (r *BufferResult) levelEnd(k int) int {
+	if k+1 < len(r.Occupancy) {
+		return r.Occupancy[k+1].At
+	}
+	return r.Config.Duration
+}
+
+// SimulateBuffer runs producers and consumers sharing a bounded buffer
+// through the textbook's three semaphores: empty, counting the free slots,
+// full, counting the items, and mutex, locking the buffer. Each producer
+// produces an item, waits on empty and then mutex, inserts it, and signals
+// mutex and full; each consumer waits on full and then mutex, removes an
+// item, signals mutex and empty, and consumes it. Durations are drawn from
+// rng.
+func SimulateBuffer(cfg BufferConfig, rng *rand.Rand) (*BufferResult, error) {
+	switch {
+	case cfg.Producers < 1 || cfg.Consumers < 1:
+		return nil, fmt.Errorf("need a producer and a consumer, not %d and %d", cfg.Producers, cfg.Consumers)
+	case cfg.Size < 1:
+		return nil, fmt.Errorf("need a buffer of at least one item, not %d", cfg.Size)
+	case cfg.Duration < 1:
+		return nil, fmt.Errorf("need a positive duration, not %d", cfg.Duration)
+	}
+	for _, d := range []Range{cfg.Produce, cfg.Consume, cfg.Access} {
+		if d.Min < 0 || d.Max < d.Min {
+			return nil, fmt.Errorf("invalid range of durations %d to %d", d.Min, d.Max)
+		}
+	}
+
+	s := &sim{}
+	r := &BufferResult{Config: cfg, Occupancy: []Level{{}}}
+	empty := &semaphore{s: s, name: "empty", value: cfg.Size}
+	full := &semaphore{s: s, name: "full"}
+	mutex := &semaphore{s: s, name: "mutex", value: 1}
+	items := 0
+	record := func() {
+		if last := &r.Occupancy[len(r.Occupancy)-1]; last.At == s.now {
+			last.Items = items
+		} else {
+			r.Occupancy = append(r.Occupancy, Level{At: s.now, Items: items})
+		}
+	}
+
+	for k := 1; k <= cfg.Producers; k++ {
+		t := s.newThread(fmt.Sprintf("P%d", k))
+		var produce func()
+		produce = func() {
+			s.set(t, Working)
+			s.after(cfg.Produce.draw(rng), func() {
+				empty.wait(t, func() {
+					mutex.wait(t, func() {
+						s.set(t, Critical)
+						s.after(cfg.Access.draw(rng), func() {
+							items++
+							r.Produced++
+							record()
+							mutex.signal()
+							full.signal()
+							produce()
+						})
+					})
+				})
+			})
+		}
+		s.after(0, produce)
+	}
+	for k := 1; k <= cfg.Consumers; k++ {
+		t := s.newThread(fmt.Sprintf("C%d", k))
+		var consume func()
+		consume = func() {
+			full.wait(t, func() {
+				mutex.wait(t, func() {
+					s.set(t, Critical)
+					s.after(cfg.Access.draw(rng), func() {
+						items--
+						record()
+						mutex.signal()
+						empty.signal()
+						s.set(t, Working)
+						s.after(cfg.Consume.draw(rng), func() {
+							r.Consumed++
+							consume()
+						})
+					})
+				})
+			})
+		}
+		s.after(0, consume)
+	}
+	s.run(cfg.Duration)
+
+	for _, t := range s.threads {
+		r.Threads = append(r.Threads, t.Thread)
+	}
+	return r, nil
+}
@@ -0,0 +1,85 @@
+package concurrency
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestSimulateBuffer(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		cfg  BufferConfig
+		// wantBlocked names the semaphore the faster side spends most of
+		// its time blocked on, waiting for the slower.
+		wantBlocked string
+		fast        int
+	}{
+		{
+			name:        "slow consumer",
+			cfg:         BufferConfig{Producers: 2, Consumers: 1, Size: 3, Produce: Range{1, 2}, Consume: Range{6, 8}, Access: Range{1, 1}, Duration: 200},
+			wantBlocked: "empty",
+			fast:        0,
+		},
+		{
+			name:        "slow producer",
+			cfg:         BufferConfig{Producers: 1, Consumers: 2, Size: 3, Produce: Range{6, 8}, Consume: Range{1, 2}, Access: Range{1, 1}, Duration: 200},
+			wantBlocked: "full",
+			fast:        1,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			r, err := SimulateBuffer(tt.cfg, rand.New(rand.NewSource(1)))
+			if err != nil {
+				t.Fatalf("SimulateBuffer() error = %v", err)
+			}
+			for _, l := range r.Occupancy {
+				if l.Items < 0 || l.Items > tt.cfg.Size {
+					t.Fatalf("SimulateBuffer() occupancy %+v out of 0 to %d", l, tt.cfg.Size)
+				}
+			}
+			if last := r.Occupancy[len(r.Occupancy)-1].Items; r.Consumed > r.Produced-last || r.Consumed < r.Produced-last-tt.cfg.Consumers {
+				t.Errorf("SimulateBuffer() produced %d and consumed %d, leaving %d items", r.Produced, r.Consumed, last)
+			}
+			for _, th := range r.Threads {
+				total := 0
+				for _, s := range th.Segments {
+					total += s.Stop - s.Start
+				}
+				if total != tt.cfg.Duration {
+					t.Errorf("SimulateBuffer() timeline of %s spans %d, want %d", th.Name, total, tt.cfg.Duration)
+				}
+			}
+			if fast := r.Threads[tt.fast]; fast.Blocked[tt.wantBlocked] < tt.cfg.Duration/2 {
+				t.Errorf("SimulateBuffer() %s blocked %v, want over half the time on %s", fast.Name, fast.Blocked, tt.wantBlocked)
+			}
+
+			again, _ := SimulateBuffer(tt.cfg, rand.New(rand.NewSource(1)))
+			if !reflect.DeepEqual(r, again) {
+				t.Error("SimulateBuffer() differs with the same seed")
+			}
+		})
+	}
+
+	if _, err := SimulateBuffer(BufferConfig{Producers: 1, Consumers: 1, Duration: 10}, rand.New(rand.NewSource(1))); err == nil {
+		t.Error("SimulateBuffer() with no buffer succeeded")
+	}
+}
+
+func TestBufferResultOccupancy(t *testing.T) {
+	t.Parallel()
+	r := &BufferResult{Config: BufferConfig{Size: 2, Duration: 10}, Occupancy: []Level{{0, 0}, {2, 1}, {4, 2}, {8, 1}}}
+	if got := r.MeanOccupancy(); got != 1.2 {
+		t.Errorf("MeanOccupancy() = %v, want 1.2", got)
+	}
+	if full, empty := r.TimeAt(2), r.TimeAt(0); full != 4 || empty != 2 {
+		t.Errorf("TimeAt() full = %d, empty = %d, want 4 and 2", full, empty)
+	}
+	if got := r.ItemsAt(5); got != 2 {
+		t.Errorf("ItemsAt(5) = %d, want 2", got)
+	}
+}
@@ -0,0 +1,171 @@
+package concurrency
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+)
+
+// timelineWidth is the most columns a timeline spans its duration with.
+const timelineWidth = 60
+
+// stateMarks are the characters timelines draw each state with.
+var stateMarks = map[State]byte{Working: '=', Critical: '#', Blocked: '.', Idle: ' '}
+
+// columns returns how many columns a timeline of duration time units takes,
+// and the time at the middle of column c.
+func columns(duration int) (int, func(c int) float64) {
+	width := duration
+	if width > timelineWidth {
+		width = timelineWidth
+	}
+	return width, func(c int) float64 {
+		return (float64(c) + 0.5) * float64(duration) / float64(width)
+	}
+}
+
+// RenderTimeline writes the timeline of each thread up to duration as the
+// Gantt chart is drawn, a row per thread, each column showing its state at
+// that time, followed by a legend naming what labels says each state is.
+func RenderTimeline(w io.Writer, threads []*Thread, duration int, labels map[State]string) {
+	width, mid := columns(duration)
+	label := strconv.Itoa(duration)
+	_, _ = fmt.Fprintf(w, "%6s 0%s%s\n", "", strings.Repeat(" ", width-len(label)), label)
+	for _, t := range threads {
+		row := make([]byte, width)
+		k := 0
+		for c := range row {
+			at := mid(c)
+			for k < len(t.Segments) && float64(t.Segments[k].Stop) <= at {
+				k++
+			}
+			row[c] = ' '
+			if k < len(t.Segments) && float64(t.Segments[k].Start) <= at {
+				row[c] = stateMarks[t.Segments[k].State]
+			}
+		}
+		_, _ = fmt.Fprintf(w, "%6s |%s|\n", t.Name, row)
+	}
+	var legend []string
+	for _, state := range []State{Working, Critical, Blocked, Idle} {
+		if labels[state] != "" {
+			legend = append(legend, fmt.Sprintf("%q %s", stateMarks[state], labels[state]))
+		}
+	}
+	_, _ = fmt.Fprintf(w, "%6s %s\n\n", "", strings.Join(legend, ", "))
+}
+
+// RenderBuffer writes r's buffer occupancy over time, a row per number of
+// items, and then tables of each thread's time blocked on each semaphore
+// and of the throughput and occupancy.
+func RenderBuffer(w io.Writer, r *BufferResult) {
+	_, _ = fmt.Fprintln(w, "Buffer occupancy")
+	width, mid := columns(r.Config.Duration)
+	for level := r.Config.Size; level >= 1; level-- {
+		row := make([]byte, width)
+		for c := range row {
+			row[c] = ' '
+			if r.ItemsAt(int(mid(c))) >= level {
+				row[c] = '#'
+			}
+		}
+		_, _ = fmt.Fprintf(w, "%6d |%s|\n", level, row)
+	}
+	_, _ = fmt.Fprintf(w, "%6s +%s+\n\n", "", strings.Repeat("-", width))
+
+	semaphores := []string{"empty", "full", "mutex"}
+	table := tablewriter.NewWriter(w)
+	header := []string{"Thread", "Working", "Critical"}
+	for _, name := range semaphores {
+		header = append(header, "Blocked on "+name)
+	}
+	table.SetHeader(append(header, "Blocked"))
+	for _, t := range r.Threads {
+		row := []string{t.Name, strconv.Itoa(t.TimeIn(Working)), strconv.Itoa(t.TimeIn(Critical))}
+		for _, name := range semaphores {
+			row = append(row, strconv.Itoa(t.Blocked[name]))
+		}
+		table.Append(append(row, percent(t.BlockedTime(), r.Config.Duration)))
+	}
+	table.Render()
+	_, _ = fmt.Fprintln(w)
+
+	table = tablewriter.NewWriter(w)
+	table.SetHeader([]string{"Produced", "Consumed", "Throughput", "Mean occupancy", "Time full", "Time empty"})
+	table.Append([]string{
+		strconv.Itoa(r.Produced),
+		strconv.Itoa(r.Consumed),
+		fmt.Sprintf("%.3f", r.Throughput()),
+		fmt.Sprintf("%.2f", r.MeanOccupancy()),
+		percent(r.TimeAt(r.Config.Size), r.Config.Duration),
+		percent(r.TimeAt(0), r.Config.Duration),
+	})
+	table.Render()
+	_, _ = fmt.Fprintln(w)
+}
+
+func percent(part, whole int) string {
+	if whole == 0 {
+		return "0.00%"
+	}
+	return fmt.Sprintf("%.2f%%", 100*float64(part)/float64(whole))
+}
+
+// philosopherLabels name the states of a philosopher's timeline.
+var philosopherLabels = map[State]string{Working: "eating", Blocked: "hungry", Idle: "thinking"}
+
+// RenderPhilosophers writes r's timeline, a row per philosopher, and when
+// its philosophers deadlocked, if they did.
+func RenderPhilosophers(w io.Writer, r *PhilosophersResult) {
+	_, _ = fmt.Fprintln(w, r.Strategy.Title)
+	RenderTimeline(w, r.Threads, r.Config.Duration, philosopherLabels)
+	if r.DeadlockAt >= 0 {
+		_, _ = fmt.Fprintf(w, "Deadlock at %d: every philosopher waits for a fork its neighbor holds\n\n", r.DeadlockAt)
+	}
+}
+
+// RenderPhilosophersComparison writes a table comparing the results of
+// each strategy: whether it deadlocked, each philosopher's meals, the
+// longest any went hungry and those who went hungry for starvation or
+// longer, and the fairness of the meals.
+func RenderPhilosophersComparison(w io.Writer, results []*PhilosophersResult, starvation int) {
+	_, _ = fmt.Fprintln(w, "Strategies")
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"Strategy", "Deadlock", "Meals", "Longest wait", "Starving", "Fairness"})
+	for _, r := range results {
+		deadlock := "none"
+		if r.DeadlockAt >= 0 {
+			deadlock = fmt.Sprintf("at %d", r.DeadlockAt)
+		}
+		meals := make([]string, len(r.Meals))
+		for p, m := range r.Meals {
+			meals[p] = strconv.Itoa(m)
+		}
+		longest := 0
+		for _, wait := range r.LongestWait {
+			if wait > longest {
+				longest = wait
+			}
+		}
+		var starving []string
+		for _, p := range r.Starving(starvation) {
+			starving = append(starving, r.Threads[p].Name)
+		}
+		if len(starving) == 0 {
+			starving = []string{"-"}
+		}
+		table.Append([]string{
+			r.Strategy.Name,
+			deadlock,
+			strings.Join(meals, " "),
+			strconv.Itoa(longest),
+			strings.Join(starving, " "),
+			fmt.Sprintf("%.3f", r.Fairness()),
+		})
+	}
+	table.Render()
+	_, _ = fmt.Fprintln(w)
+}
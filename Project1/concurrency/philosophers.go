@@ -0,0 +1,344 @@
+package concurrency
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+// Strategy is a solution to the dining philosophers problem.
+type Strategy struct {
+	Name  string
+	Title string
+}
+
+// Strategies are the dining philosophers strategies, in the order reports
+// list them.
+var Strategies = []Strategy{
+	{Name: "naive", Title: "Naive, left fork first"},
+	{Name: "ordering", Title: "Resource ordering"},
+	{Name: "waiter", Title: "Waiter"},
+	{Name: "chandy-misra", Title: "Chandy-Misra"},
+}
+
+// FindStrategy returns the strategy named name.
+func FindStrategy(name string) (Strategy, bool) {
+	for _, s := range Strategies {
+		if s.Name == name {
+			return s, true
+		}
+	}
+	return Strategy{}, false
+}
+
+// PhilosophersConfig sets up a dining philosophers simulation.
+type PhilosophersConfig struct {
+	Philosophers int
+	// Think and Eat are how long a philosopher thinks and eats for.
+	Think, Eat Range
+	// Reach is how long a philosopher who picks up forks one at a time
+	// takes to reach for the second once holding the first.
+	Reach int
+	// Duration is how long to simulate.
+	Duration int
+}
+
+// PhilosophersResult is a dining philosophers simulation's outcome.
+type PhilosophersResult struct {
+	Config   PhilosophersConfig
+	Strategy Strategy
+	// Threads are the philosophers, thinking while idle, hungry while
+	// blocked, and eating while working.
+	Threads []*Thread
+	// Meals counts each philosopher's meals.
+	Meals []int
+	// LongestWait is each philosopher's longest time hungry, counting a
+	// wait unfinished at the end.
+	LongestWait []int
+	// DeadlockAt is when every philosopher came to wait on another for
+	// good, or -1.
+	DeadlockAt int
+}
+
+// Fairness is Jain's fairness index of the philosophers' meals: 1 when all
+// ate equally often, down to 1/n when one ate every meal, and 0 if none
+// ate.
+func (r *PhilosophersResult) Fairness() float64 {
+	var sum, squares float64
+	for _, m := range r.Meals {
+		sum += float64(m)
+		squares += float64(m) * float64(m)
+	}
+	if squares == 0 {
+		return 0
+	}
+	return sum * sum / (float64(len(r.Meals)) * squares)
+}
+
+// Starving returns the philosophers whose longest wait reached limit.
+func (r *PhilosophersResult) Starving(limit int) []int {
+	var starving []int
+	for p, wait := range r.LongestWait {
+		if wait >= limit {
+			starving = append(starving, p)
+		}
+	}
+	return starving
+}
+
+// table is the dining philosophers' shared state for a strategy to act on.
+type table struct {
+	s      *sim
+	cfg    PhilosophersConfig
+	rng    *rand.Rand
+	r      *PhilosophersResult
+	p      []*thread
+	hungry []int
+}
+
+// forks returns the forks philosopher p eats with, its left and then its
+// right.
+func (t *table) forks(p int) (int, int) {
+	return p, (p + 1) % t.cfg.Philosophers
+}
+
+// think starts philosopher p thinking, calling hungry once it is done.
+func (t *table) think(p int, hungry func()) {
+	t.s.set(t.p[p], Idle)
+	t.s.after(t.cfg.Think.draw(t.rng), func() {
+		t.hungry[p] = t.s.now
+		t.p[p].waitingOn = "forks"
+		t.s.set(t.p[p], Blocked)
+		hungry()
+	})
+}
+
+// eat starts philosopher p eating, calling done once it is finished.
+func (t *table) eat(p int, done func()) {
+	if wait := t.s.now - t.hungry[p]; wait > t.r.LongestWait[p] {
+		t.r.LongestWait[p] = wait
+	}
+	t.hungry[p] = -1
+	t.r.Meals[p]++
+	t.s.set(t.p[p], Working)
+	t.s.after(t.cfg.Eat.draw(t.rng), done)
+}
+
+// SimulatePhilosophers seats philosophers around a table with a fork
+// between each pair, each thinking, growing hungry, and eating with both
+// its forks, as strategy has them take the forks:
+//
+//   - naive picks up the left fork and then the right, each guarded by a
+//     semaphore, which deadlocks once every philosopher holds its left;
+//   - ordering picks up the lower-numbered fork first, so the last
+//     philosopher reaches right first and no cycle of waits can form;
+//   - waiter has a waiter hand out both forks at once, serving hungry
+//     philosophers in the order they asked whenever both their forks are
+//     free, which passes over a philosopher whose neighbors keep eating;
+//   - chandy-misra passes forks as messages: a hungry philosopher asks
+//     its neighbors for the forks it lacks, and a neighbor gives up a fork
+//     it has eaten with, cleaning it, unless eating, and a clean one only
+//     once it has eaten.
+//
+// Durations are drawn from rng.
+func SimulatePhilosophers(strategy Strategy, cfg PhilosophersConfig, rng *rand.Rand) (*PhilosophersResult, error) {
+	switch {
+	case cfg.Philosophers < 2:
+		return nil, fmt.Errorf("need at least two philosophers, not %d", cfg.Philosophers)
+	case cfg.Duration < 1:
+		return nil, fmt.Errorf("need a positive duration, not %d", cfg.Duration)
+	case cfg.Reach < 0:
+		return nil, fmt.Errorf("need a reach of at least 0, not %d", cfg.Reach)
+	}
+	for _, d := range []Range{cfg.Think, cfg.Eat} {
+		if d.Min < 1 || d.Max < d.Min {
+			return nil, fmt.Errorf("invalid range of durations %d to %d, want them positive", d.Min, d.Max)
+		}
+	}
+	n := cfg.Philosophers
+	r := &PhilosophersResult{Config: cfg, Strategy: strategy, Meals: make([]int, n), LongestWait: make([]int, n)}
+	t := &table{s: &sim{}, cfg: cfg, rng: rng, r: r, hungry: make([]int, n)}
+	for p := 0; p < n; p++ {
+		t.p = append(t.p, t.s.newThread(fmt.Sprintf("P%d", p+1)))
+		t.hungry[p] = -1
+	}
+
+	switch strategy.Name {
+	case "naive", "ordering":
+		t.semaphores(strategy.Name == "ordering")
+	case "waiter":
+		t.waiter()
+	case "chandy-misra":
+		t.chandyMisra()
+	default:
+		return nil, fmt.Errorf("unknown strategy %q", strategy.Name)
+	}
+	r.DeadlockAt = t.s.run(cfg.Duration)
+
+	for p, th := range t.p {
+		if t.hungry[p] >= 0 && cfg.Duration-t.hungry[p] > r.LongestWait[p] {
+			r.LongestWait[p] = cfg.Duration - t.hungry[p]
+		}
+		r.Threads = append(r.Threads, th.Thread)
+	}
+	return r, nil
+}
+
+// semaphores has each philosopher wait on its forks' semaphores one at a
+// time, the left first, or the lower-numbered first if ordered.
+func (t *table) semaphores(ordered bool) {
+	forks := make([]*semaphore, t.cfg.Philosophers)
+	for f := range forks {
+		forks[f] = &semaphore{s: t.s, name: "forks", value: 1}
+	}
+	for p := range t.p {
+		p := p
+		first, second := t.forks(p)
+		if ordered && second < first {
+			first, second = second, first
+		}
+		var dine func()
+		dine = func() {
+			t.think(p, func() {
+				forks[first].wait(t.p[p], func() {
+					t.s.after(t.cfg.Reach, func() {
+						forks[second].wait(t.p[p], func() {
+							t.eat(p, func() {
+								forks[first].signal()
+								forks[second].signal()
+								dine()
+							})
+						})
+					})
+				})
+			})
+		}
+		t.s.after(0, dine)
+	}
+}
+
+// waiter has a waiter hand each hungry philosopher both forks at once.
+func (t *table) waiter() {
+	free := make([]bool, t.cfg.Philosophers)
+	for f := range free {
+		free[f] = true
+	}
+	var queue []int
+	var dine func(p int)
+	var serve func()
+	serve = func() {
+		waiting := queue[:0]
+		for _, p := range queue {
+			left, right := t.forks(p)
+			if !free[left] || !free[right] {
+				waiting = append(waiting, p)
+				continue
+			}
+			free[left], free[right] = false, false
+			p := p
+			t.eat(p, func() {
+				free[left], free[right] = true, true
+				dine(p)
+				serve()
+			})
+		}
+		queue = waiting
+	}
+	dine = func(p int) {
+		t.think(p, func() {
+			queue = append(queue, p)
+			serve()
+		})
+	}
+	for p := range t.p {
+		p := p
+		t.s.after(0, func() { dine(p) })
+	}
+}
+
+// chandyMisra passes forks between neighbors by request, each dirty once
+// eaten with.
+func (t *table) chandyMisra() {
+	n := t.cfg.Philosophers
+	// Fork f lies between philosophers f-1 and f, and starts dirty with
+	// the lower-numbered of them, so no cycle of requests can form.
+	holder := make([]int, n)
+	dirty := make([]bool, n)
+	// deferred[f] is whether the holder of fork f owes it to its neighbor
+	// once done eating.
+	deferred := make([]bool, n)
+	requested := make([]bool, n)
+	eating := make([]bool, n)
+	for f := range holder {
+		holder[f] = f
+		if other := (f - 1 + n) % n; other < f {
+			holder[f] = other
+		}
+		dirty[f] = true
+	}
+	neighbor := func(f, p int) int {
+		if p == f {
+			return (f - 1 + n) % n
+		}
+		return f
+	}
+
+	var dine, tryEat func(p int)
+	var request func(f, p int)
+	// give hands fork f to the neighbor of its holder, clean, and has the
+	// holder ask for it back if hungry.
+	give := func(f int) {
+		from := holder[f]
+		to := neighbor(f, from)
+		holder[f], dirty[f], deferred[f], requested[f] = to, false, false, false
+		tryEat(to)
+		if t.hungry[from] >= 0 {
+			request(f, from)
+		}
+	}
+	request = func(f, p int) {
+		if holder[f] == p || requested[f] {
+			return
+		}
+		requested[f] = true
+		t.s.after(0, func() {
+			if holder[f] == p {
+				requested[f] = false
+				return
+			}
+			if h := holder[f]; eating[h] || !dirty[f] {
+				deferred[f] = true
+				return
+			}
+			give(f)
+		})
+	}
+	tryEat = func(p int) {
+		left, right := t.forks(p)
+		if t.hungry[p] < 0 || holder[left] != p || holder[right] != p {
+			return
+		}
+		eating[p] = true
+		t.eat(p, func() {
+			eating[p] = false
+			dirty[left], dirty[right] = true, true
+			for _, f := range []int{left, right} {
+				if deferred[f] {
+					give(f)
+				}
+			}
+			dine(p)
+		})
+	}
+	dine = func(p int) {
+		t.think(p, func() {
+			left, right := t.forks(p)
+			request(left, p)
+			request(right, p)
+			tryEat(p)
+		})
+	}
+	for p := range t.p {
+		p := p
+		t.s.after(0, func() { dine(p) })
+	}
+}
@@ -0,0 +1,88 @@
+package concurrency
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestSimulatePhilosophers(t *testing.T) {
+	t.Parallel()
+	// Short thoughts and a slow reach for the second fork make the naive
+	// philosophers deadlock quickly.
+	cfg := PhilosophersConfig{Philosophers: 5, Think: Range{1, 3}, Eat: Range{2, 5}, Reach: 2, Duration: 500}
+	for _, strategy := range Strategies {
+		strategy := strategy
+		t.Run(strategy.Name, func(t *testing.T) {
+			t.Parallel()
+			r, err := SimulatePhilosophers(strategy, cfg, rand.New(rand.NewSource(1)))
+			if err != nil {
+				t.Fatalf("SimulatePhilosophers() error = %v", err)
+			}
+			if deadlocked := r.DeadlockAt >= 0; deadlocked != (strategy.Name == "naive") {
+				t.Errorf("SimulatePhilosophers() deadlocked at %d, want a deadlock only for naive", r.DeadlockAt)
+			}
+			if strategy.Name != "naive" {
+				for p, m := range r.Meals {
+					if m == 0 {
+						t.Errorf("SimulatePhilosophers() P%d never ate, meals %v", p+1, r.Meals)
+					}
+				}
+			}
+			for _, th := range r.Threads {
+				total := 0
+				for _, s := range th.Segments {
+					total += s.Stop - s.Start
+				}
+				if total != cfg.Duration {
+					t.Errorf("SimulatePhilosophers() timeline of %s spans %d, want %d", th.Name, total, cfg.Duration)
+				}
+			}
+			assertNoNeighborsEat(t, r)
+
+			again, _ := SimulatePhilosophers(strategy, cfg, rand.New(rand.NewSource(1)))
+			if !reflect.DeepEqual(r, again) {
+				t.Error("SimulatePhilosophers() differs with the same seed")
+			}
+		})
+	}
+}
+
+// assertNoNeighborsEat fails unless no two neighbors ever eat at once,
+// sharing a fork.
+func assertNoNeighborsEat(t *testing.T, r *PhilosophersResult) {
+	t.Helper()
+	n := len(r.Threads)
+	for p, th := range r.Threads {
+		next := r.Threads[(p+1)%n]
+		for _, a := range th.Segments {
+			for _, b := range next.Segments {
+				if a.State == Working && b.State == Working && a.Start < b.Stop && b.Start < a.Stop {
+					t.Fatalf("%s eats over %+v while %s eats over %+v", th.Name, a, next.Name, b)
+				}
+			}
+		}
+	}
+}
+
+func TestPhilosophersFairness(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		meals []int
+		want  float64
+	}{
+		{meals: []int{3, 3, 3, 3}, want: 1},
+		{meals: []int{4, 0, 0, 0}, want: 0.25},
+		{meals: []int{0, 0}, want: 0},
+	}
+	for _, tt := range tests {
+		r := &PhilosophersResult{Meals: tt.meals}
+		if got := r.Fairness(); got != tt.want {
+			t.Errorf("Fairness() of %v = %v, want %v", tt.meals, got, tt.want)
+		}
+	}
+	r := &PhilosophersResult{LongestWait: []int{5, 40, 12}}
+	if got := r.Starving(12); !reflect.DeepEqual(got, []int{1, 2}) {
+		t.Errorf("Starving(12) = %v, want [1 2]", got)
+	}
+}
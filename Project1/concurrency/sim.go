@@ -0,0 +1,198 @@
+// Package concurrency simulates classic synchronization problems, threads
+// coordinating through semaphores, in discrete time so every run with the
+// same seed is the same.
+package concurrency
+
+import (
+	"container/heap"
+)
+
+// State is what a thread is doing over a segment of its timeline.
+type State string
+
+// The states of a thread.
+const (
+	// Working is producing, consuming, thinking, or eating: time spent
+	// outside any wait.
+	Working State = "work"
+	// Critical is time spent holding a lock, in a critical section.
+	Critical State = "critical"
+	// Blocked is time spent waiting on a semaphore.
+	Blocked State = "blocked"
+	// Idle is time spent with nothing to do, such as a thinking philosopher.
+	Idle State = "idle"
+)
+
+// Segment is a span of a thread's timeline in one state.
+type Segment struct {
+	Start, Stop int
+	State       State
+}
+
+// Thread is a simulated thread's timeline.
+type Thread struct {
+	Name     string
+	Segments []Segment
+	// Blocked sums the time the thread spent blocked, by the name of the
+	// semaphore it waited on.
+	Blocked map[string]int
+}
+
+// BlockedTime sums the time t spent blocked on any semaphore.
+func (t *Thread) BlockedTime() int {
+	total := 0
+	for _, d := range t.Blocked {
+		total += d
+	}
+	return total
+}
+
+// TimeIn sums the time t spent in state.
+func (t *Thread) TimeIn(state State) int {
+	total := 0
+	for _, s := range t.Segments {
+		if s.State == state {
+			total += s.Stop - s.Start
+		}
+	}
+	return total
+}
+
+// event is a callback due at a time, seq keeping events due at the same
+// time in the order they were scheduled.
+type event struct {
+	at, seq int
+	fn      func()
+}
+
+type events []event
+
+func (e events) Len() int { return len(e) }
+func (e events) Less(i, j int) bool {
+	if e[i].at != e[j].at {
+		return e[i].at < e[j].at
+	}
+	return e[i].seq < e[j].seq
+}
+func (e events) Swap(i, j int)       { e[i], e[j] = e[j], e[i] }
+func (e *events) Push(x interface{}) { *e = append(*e, x.(event)) }
+func (e *events) Pop() interface{} {
+	old := *e
+	x := old[len(old)-1]
+	*e = old[:len(old)-1]
+	return x
+}
+
+// sim is a discrete event simulation of threads.
+type sim struct {
+	now     int
+	seq     int
+	queue   events
+	threads []*thread
+}
+
+// thread is a thread as the simulation tracks it.
+type thread struct {
+	*Thread
+	state State
+	since int
+	// waitingOn names the semaphore the thread is blocked on.
+	waitingOn string
+}
+
+func (s *sim) newThread(name string) *thread {
+	t := &thread{Thread: &Thread{Name: name, Blocked: make(map[string]int)}, state: Idle}
+	s.threads = append(s.threads, t)
+	return t
+}
+
+// after schedules fn d time units from now.
+func (s *sim) after(d int, fn func()) {
+	s.seq++
+	heap.Push(&s.queue, event{at: s.now + d, seq: s.seq, fn: fn})
+}
+
+// set moves t into state as of now, ending its current segment.
+func (s *sim) set(t *thread, state State) {
+	if t.state == state {
+		return
+	}
+	s.end(t, s.now)
+	t.state, t.since = state, s.now
+}
+
+// end closes t's current segment at the time at.
+func (s *sim) end(t *thread, at int) {
+	if at > t.since {
+		t.Segments = append(t.Segments, Segment{Start: t.since, Stop: at, State: t.state})
+		if t.state == Blocked {
+			t.Blocked[t.waitingOn] += at - t.since
+		}
+	}
+	t.since = at
+}
+
+// run runs events until none is left or the next is due after until, then
+// closes every thread's timeline at until. It returns the time the last
+// event ran if none was left before until, every thread then being blocked
+// for good, or -1.
+func (s *sim) run(until int) int {
+	for s.queue.Len() > 0 && s.queue[0].at <= until {
+		e := heap.Pop(&s.queue).(event)
+		s.now = e.at
+		e.fn()
+	}
+	stalled := -1
+	if s.queue.Len() == 0 {
+		stalled = s.now
+	}
+	s.now = until
+	for _, t := range s.threads {
+		s.end(t, until)
+	}
+	return stalled
+}
+
+// semaphore is a counting semaphore whose waiters wake in the order they
+// began waiting.
+type semaphore struct {
+	s       *sim
+	name    string
+	value   int
+	waiters []waiter
+}
+
+type waiter struct {
+	t  *thread
+	fn func()
+}
+
+// wait decrements the semaphore and calls fn, blocking t first until the
+// semaphore is signaled if its value is 0.
+func (m *semaphore) wait(t *thread, fn func()) {
+	if m.value > 0 {
+		m.value--
+		fn()
+		return
+	}
+	// A thread woken by one semaphore may block straight away on another,
+	// whose wait is a segment of its own.
+	if t.state == Blocked {
+		m.s.end(t, m.s.now)
+	}
+	t.waitingOn = m.name
+	m.s.set(t, Blocked)
+	m.waiters = append(m.waiters, waiter{t: t, fn: fn})
+}
+
+// signal wakes the longest waiter, which goes on at the current time, or
+// increments the semaphore if there is none.
+func (m *semaphore) signal() {
+	if len(m.waiters) == 0 {
+		m.value++
+		return
+	}
+	w := m.waiters[0]
+	m.waiters = m.waiters[1:]
+	m.s.after(0, w.fn)
+}
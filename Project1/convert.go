@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/rks0134/CSCE4600/Project1/scheduler"
+)
+
+// convertCmd writes a scheduling file as a binary workload, or as a
+// protobuf Workload when the file to write is named .pb or .binpb.
+type convertCmd struct{}
+
+func (c *convertCmd) defineFlags(*flag.FlagSet) {}
+
+func (c *convertCmd) run(_ context.Context, _ io.Writer, args []string) (err error) {
+	if len(args) != 2 {
+		return fmt.Errorf("%w: must give a scheduling file and the binary file to write", ErrInvalidArgs)
+	}
+	f, closeFile, err := openProcessingFile(args[0])
+	if err != nil {
+		return err
+	}
+	processes, ticks, err := scheduler.LoadProcessesFractional(f)
+	if closeErr := closeFile(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return &fileError{path: args[0], err: err}
+	}
+	if err := scheduler.ValidateProcesses(processes); err != nil {
+		return &fileError{path: args[0], err: err}
+	}
+
+	out, err := os.Create(args[1])
+	if err != nil {
+		return fmt.Errorf("%w: error creating binary workload", err)
+	}
+	defer func() {
+		if closeErr := out.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+	write := scheduler.WriteBinary
+	if isProto(args[1]) {
+		write = scheduler.WriteProto
+	}
+	if err := write(out, processes, ticks); err != nil {
+		return &fileError{path: args[0], err: err}
+	}
+
+	return nil
+}
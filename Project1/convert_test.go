@@ -0,0 +1,27 @@
+package main
+
+import (
+	"context"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func Test_runConvert(t *testing.T) {
+	t.Parallel()
+	wl := filepath.Join(t.TempDir(), "example.wl")
+	if err := run(context.Background(), io.Discard, "binary_name", "convert", "example_processes.csv", wl); err != nil {
+		t.Fatalf("convert error = %v", err)
+	}
+	var fromCSV, fromBinary strings.Builder
+	if err := run(context.Background(), &fromCSV, "binary_name", "-seed", "1", "example_processes.csv"); err != nil {
+		t.Fatal(err)
+	}
+	if err := run(context.Background(), &fromBinary, "binary_name", "-seed", "1", wl); err != nil {
+		t.Fatalf("schedule of a binary workload error = %v", err)
+	}
+	if fromBinary.String() != fromCSV.String() {
+		t.Errorf("binary workload schedules differently:\n%s\nwant:\n%s", fromBinary.String(), fromCSV.String())
+	}
+}
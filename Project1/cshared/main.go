@@ -0,0 +1,45 @@
+// Command cshared exposes the scheduler package through a small C ABI, so
+// course tooling in Python, R, or anything else with a C foreign function
+// interface can run the exact implementation used for grading. Build it
+// as a shared library with
+//
+//	go build -buildmode=c-shared -o libscheduler.so ./cshared
+//
+// which also writes libscheduler.h declaring the two exported functions:
+//
+//	char* ScheduleJSON(char* request);
+//	void FreeString(char* s);
+//
+// ScheduleJSON runs a request as scheduler.RunJSON does, taking and
+// returning JSON:
+//
+//	{"algorithm": "rr", "processes": [...], "options": {"Quantum": 4}}
+//
+// The caller owns the returned string and must free it with FreeString.
+package main
+
+// #include <stdlib.h>
+import "C"
+
+import (
+	"unsafe"
+
+	"github.com/rks0134/CSCE4600/Project1/scheduler"
+)
+
+// ScheduleJSON runs the scheduling request in the JSON string in and
+// returns the JSON response, which the caller must free with FreeString.
+//
+//export ScheduleJSON
+func ScheduleJSON(in *C.char) *C.char {
+	return C.CString(string(scheduler.RunJSON([]byte(C.GoString(in)))))
+}
+
+// FreeString frees a string returned by ScheduleJSON.
+//
+//export FreeString
+func FreeString(s *C.char) {
+	C.free(unsafe.Pointer(s))
+}
+
+func main() {}
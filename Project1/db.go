@@ -0,0 +1,162 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"math"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rks0134/CSCE4600/Project1/scheduler"
+)
+
+// dbSchema creates the tables of a results database: a row per command
+// run, per schedule of a workload by an algorithm in a run, and per
+// process in a schedule. Times are in time units.
+const dbSchema = `CREATE TABLE IF NOT EXISTS runs (
+	id INTEGER PRIMARY KEY,
+	started TEXT NOT NULL,
+	command TEXT NOT NULL,
+	seed INTEGER NOT NULL
+);
+CREATE TABLE IF NOT EXISTS schedules (
+	id INTEGER PRIMARY KEY,
+	run_id INTEGER NOT NULL REFERENCES runs(id),
+	workload TEXT NOT NULL,
+	algorithm TEXT NOT NULL,
+	average_wait REAL,
+	average_turnaround REAL,
+	throughput REAL,
+	completed INTEGER NOT NULL,
+	total INTEGER NOT NULL,
+	makespan REAL,
+	context_switches INTEGER NOT NULL,
+	utilization REAL
+);
+CREATE TABLE IF NOT EXISTS processes (
+	schedule_id INTEGER NOT NULL REFERENCES schedules(id),
+	pid INTEGER NOT NULL,
+	priority INTEGER NOT NULL,
+	arrival REAL NOT NULL,
+	burst REAL NOT NULL,
+	wait REAL NOT NULL,
+	turnaround REAL NOT NULL,
+	exit REAL NOT NULL,
+	killed INTEGER NOT NULL
+);
+`
+
+// dbFlags let a command record its schedules in a SQLite database, so many
+// experiments can be queried together with SQL.
+type dbFlags struct {
+	db string
+}
+
+func (f *dbFlags) define(flags *flag.FlagSet) {
+	flags.StringVar(&f.db, "db", "", "record the run, each schedule's metrics, and each process's times in the SQLite database `file`, created if need be (needs the sqlite3 command)")
+}
+
+// dbRun collects the records of one run of a command, for writing to the
+// database in one transaction. Schedules may be added from any goroutine.
+type dbRun struct {
+	path string
+	mu   sync.Mutex
+	sql  strings.Builder
+}
+
+// open starts recording a run of command, or returns nil if there is no
+// database to record it in. The sqlite3 command is looked for up front, so
+// a run does not go to waste for want of it.
+func (f *dbFlags) open(command string, seed int64) (*dbRun, error) {
+	if f.db == "" {
+		return nil, nil
+	}
+	if _, err := exec.LookPath("sqlite3"); err != nil {
+		return nil, fmt.Errorf("%w: -db needs the sqlite3 command: %v", ErrInvalidArgs, err)
+	}
+	r := &dbRun{path: f.db}
+	r.sql.WriteString(dbSchema)
+	fmt.Fprintf(&r.sql, "INSERT INTO runs (started, command, seed) VALUES (%s, %s, %d);\n",
+		sqlQuote(time.Now().UTC().Format(time.RFC3339)), sqlQuote(command), seed)
+
+	return r, nil
+}
+
+// add records the schedule of workload by algorithm, if r is recording.
+func (r *dbRun) add(workload, algorithm string, s *scheduler.ScheduleResult) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	m := s.UnitMetrics()
+	fmt.Fprintf(&r.sql, "INSERT INTO schedules (run_id, workload, algorithm, average_wait, average_turnaround, throughput, completed, total, makespan, context_switches, utilization) "+
+		"VALUES ((SELECT max(id) FROM runs), %s, %s, %s, %s, %s, %d, %d, %s, %d, %s);\n",
+		sqlQuote(workload), sqlQuote(algorithm), sqlReal(m.AverageWait), sqlReal(m.AverageTurnaround), sqlReal(m.Throughput),
+		m.Completed, s.Total, sqlReal(m.Makespan), m.ContextSwitches, sqlReal(m.Utilization))
+	if len(s.Rows) == 0 {
+		return
+	}
+	u := float64(s.TicksPerUnit)
+	if u < 1 {
+		u = 1
+	}
+	r.sql.WriteString("INSERT INTO processes (schedule_id, pid, priority, arrival, burst, wait, turnaround, exit, killed) VALUES\n")
+	for k, row := range s.Rows {
+		if k > 0 {
+			r.sql.WriteString(",\n")
+		}
+		killed := 0
+		if row.Killed {
+			killed = 1
+		}
+		fmt.Fprintf(&r.sql, "((SELECT max(id) FROM schedules), %d, %d, %s, %s, %s, %s, %s, %d)",
+			row.ProcessID, row.Priority, sqlReal(float64(row.ArrivalTime)/u), sqlReal(float64(row.BurstDuration)/u),
+			sqlReal(float64(row.Wait)/u), sqlReal(float64(row.Turnaround)/u), sqlReal(float64(row.Exit)/u), killed)
+	}
+	r.sql.WriteString(";\n")
+}
+
+// commit writes the run to the database in one transaction, if r is
+// recording. It is not tied to the command's context, so what an
+// interrupted run completed is still recorded.
+func (r *dbRun) commit() error {
+	if r == nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, "sqlite3", "-bail", r.path)
+	// IMMEDIATE takes the write lock up front, so the max(id) lookups see
+	// this run's rows even with other runs writing to the same database.
+	cmd.Stdin = strings.NewReader("BEGIN IMMEDIATE;\n" + r.sql.String() + "COMMIT;\n")
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			err = errors.New(msg)
+		}
+		return fmt.Errorf("recording in %s: %w", r.path, err)
+	}
+
+	return nil
+}
+
+// sqlQuote quotes s as a SQL string literal.
+func sqlQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// sqlReal formats f as a SQL real literal, or NULL if it is not a number.
+func sqlReal(f float64) string {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return "NULL"
+	}
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func Test_runDB(t *testing.T) {
+	t.Parallel()
+	if _, err := exec.LookPath("sqlite3"); err != nil {
+		t.Skip("no sqlite3 command")
+	}
+	db := filepath.Join(t.TempDir(), "results.sqlite")
+	if err := run(context.Background(), io.Discard, "binary_name", "-seed", "1", "schedule", "-db", db, "example_processes.csv"); err != nil {
+		t.Fatal(err)
+	}
+	if err := run(context.Background(), io.Discard, "binary_name", "-seed", "1", "batch", "-progress", "never", "-db", db, "-algorithms", "fcfs,rr",
+		"example_processes.csv", "missing.csv"); exitCode(err) != ExitFileNotFound {
+		t.Fatalf("batch with a missing file error = %v, want it not found", err)
+	}
+	out, err := exec.Command("sqlite3", db,
+		"SELECT r.command, count(DISTINCT s.id), count(*) FROM runs r JOIN schedules s ON s.run_id = r.id JOIN processes p ON p.schedule_id = s.id GROUP BY r.id ORDER BY r.id;"+
+			"SELECT wait FROM processes p JOIN schedules s ON p.schedule_id = s.id WHERE s.algorithm = 'fcfs' AND p.pid = 3 ORDER BY s.id;").Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := fmt.Sprintf("schedule|%d|%d\nbatch|2|6\n8.0\n8.0\n", len(forWorkload(algorithms(), "all", nil)), 3*len(forWorkload(algorithms(), "all", nil)))
+	if string(out) != want {
+		t.Errorf("database holds\n%s\nwant\n%s", out, want)
+	}
+}
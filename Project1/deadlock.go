@@ -0,0 +1,49 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/rks0134/CSCE4600/Project1/deadlock"
+	"github.com/rks0134/CSCE4600/Project1/scheduler"
+)
+
+// deadlockCmd detects deadlock on a resource allocation graph.
+type deadlockCmd struct {
+	format string
+}
+
+func (c *deadlockCmd) defineFlags(flags *flag.FlagSet) {
+	flags.StringVar(&c.format, "format", "text", "output `format`: text, or dot for the graph in Graphviz's DOT language")
+}
+
+func (c *deadlockCmd) run(_ context.Context, w io.Writer, args []string) error {
+	if c.format != "text" && c.format != "dot" {
+		return fmt.Errorf("%w: -format must be text or dot, not %q", ErrInvalidArgs, c.format)
+	}
+	if len(args) != 1 {
+		return fmt.Errorf("%w: must give a resource allocation graph file", ErrInvalidArgs)
+	}
+	var g *deadlock.Graph
+	if err := readInputFile(args[0], func(r io.Reader) (err error) {
+		g, err = deadlock.ParseGraph(r)
+		return err
+	}); err != nil {
+		return err
+	}
+	if err := g.Validate(); err != nil {
+		return &fileError{path: args[0], err: err}
+	}
+
+	d := g.Detect()
+	if c.format == "dot" {
+		deadlock.RenderDOT(w, g, d.Deadlocked)
+		return nil
+	}
+	scheduler.RenderTitle(w, fmt.Sprintf("Deadlock detection, %d processes and %d resources", len(g.Processes), len(g.Resources)))
+	deadlock.RenderGraph(w, g, d, g.Cycles(), g.Victims())
+
+	return nil
+}
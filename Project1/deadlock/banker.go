@@ -0,0 +1,337 @@
+// Package deadlock simulates how an operating system avoids and detects
+// deadlock among processes holding and requesting resources.
+package deadlock
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/rks0134/CSCE4600/Project1/scheduler"
+)
+
+// State is the resource allocation state the banker's algorithm works on:
+// per process, the instances of each resource it holds and the most it may
+// claim, and the instances free.
+type State struct {
+	Resources  []string
+	Processes  []string
+	Available  []int
+	Allocation [][]int
+	Max        [][]int
+}
+
+// Need is the instances of each resource each process may still request,
+// its maximum claim less its allocation.
+func (s *State) Need() [][]int {
+	need := make([][]int, len(s.Processes))
+	for p := range need {
+		need[p] = sub(s.Max[p], s.Allocation[p])
+	}
+	return need
+}
+
+// Validate returns an error wrapping scheduler.ErrValidation unless every
+// process's allocation is within its maximum claim and no count is
+// negative.
+func (s *State) Validate() error {
+	for r, n := range s.Available {
+		if n < 0 {
+			return fmt.Errorf("%w: %d %s available", scheduler.ErrValidation, n, s.Resources[r])
+		}
+	}
+	for p, name := range s.Processes {
+		for r, n := range s.Allocation[p] {
+			switch {
+			case n < 0 || s.Max[p][r] < 0:
+				return fmt.Errorf("%w: %s has a negative count of %s", scheduler.ErrValidation, name, s.Resources[r])
+			case n > s.Max[p][r]:
+				return fmt.Errorf("%w: %s holds %d %s, more than its maximum claim of %d", scheduler.ErrValidation, name, n, s.Resources[r], s.Max[p][r])
+			}
+		}
+	}
+	return nil
+}
+
+// Process returns the index of the process named name.
+func (s *State) Process(name string) (int, bool) {
+	for p, n := range s.Processes {
+		if n == name {
+			return p, true
+		}
+	}
+	return 0, false
+}
+
+// SafetyStep is one process the safety algorithm lets finish, with the work
+// available before it does and after it releases its allocation.
+type SafetyStep struct {
+	Process     int
+	Work, After []int
+}
+
+// Safety is the outcome of the safety algorithm.
+type Safety struct {
+	Steps []SafetyStep
+	// Stuck are the processes that could not finish, in order, none for a
+	// safe state.
+	Stuck []int
+}
+
+// Safe is whether every process could finish.
+func (s Safety) Safe() bool { return len(s.Stuck) == 0 }
+
+// Sequence is the order the processes could finish in.
+func (s Safety) Sequence() []int {
+	seq := make([]int, len(s.Steps))
+	for k, step := range s.Steps {
+		seq[k] = step.Process
+	}
+	return seq
+}
+
+// Safety runs the safety algorithm: starting from the available instances
+// as work, it repeatedly finds a process whose need fits in the work and
+// lets it finish, adding its allocation back, scanning onward from the
+// process it last chose and wrapping around, until every process has
+// finished or none can.
+func (s *State) Safety() Safety {
+	need := s.Need()
+	work := append([]int(nil), s.Available...)
+	finished := make([]bool, len(s.Processes))
+	var safety Safety
+	for p, idle := 0, 0; idle < len(s.Processes); p = (p + 1) % len(s.Processes) {
+		if finished[p] || !fits(need[p], work) {
+			idle++
+			continue
+		}
+		after := add(work, s.Allocation[p])
+		safety.Steps = append(safety.Steps, SafetyStep{Process: p, Work: work, After: after})
+		work, finished[p], idle = after, true, 0
+	}
+	for p, done := range finished {
+		if !done {
+			safety.Stuck = append(safety.Stuck, p)
+		}
+	}
+	return safety
+}
+
+// Verdict is what the banker decides about a request.
+type Verdict string
+
+// The verdicts on a request.
+const (
+	// Granted requests leave the state safe, and are allocated.
+	Granted Verdict = "granted"
+	// Wait requests are for more than is available; the process waits.
+	Wait Verdict = "wait"
+	// Unsafe requests would leave the state unsafe; the process waits.
+	Unsafe Verdict = "unsafe"
+	// ExceedsClaim requests are for more than the process's remaining need,
+	// an error on its part.
+	ExceedsClaim Verdict = "exceeds claim"
+)
+
+// Decision is the banker's decision on a request, with the safety of the
+// state granting it would leave for Granted and Unsafe verdicts.
+type Decision struct {
+	Process int
+	Request []int
+	Verdict Verdict
+	Safety  Safety
+}
+
+// Request decides process p's request for request instances of each
+// resource, allocating them if granted.
+func (s *State) Request(p int, request []int) (Decision, error) {
+	if p < 0 || p >= len(s.Processes) {
+		return Decision{}, fmt.Errorf("no process %d", p)
+	}
+	if len(request) != len(s.Resources) {
+		return Decision{}, fmt.Errorf("request for %d resources, not the %d there are", len(request), len(s.Resources))
+	}
+	for _, n := range request {
+		if n < 0 {
+			return Decision{}, errors.New("request for a negative count")
+		}
+	}
+	d := Decision{Process: p, Request: request}
+	switch {
+	case !fits(request, s.Need()[p]):
+		d.Verdict = ExceedsClaim
+	case !fits(request, s.Available):
+		d.Verdict = Wait
+	default:
+		available, allocation := s.Available, s.Allocation[p]
+		s.Available, s.Allocation[p] = sub(available, request), add(allocation, request)
+		d.Safety = s.Safety()
+		d.Verdict = Granted
+		if !d.Safety.Safe() {
+			d.Verdict = Unsafe
+			s.Available, s.Allocation[p] = available, allocation
+		}
+	}
+	return d, nil
+}
+
+// Release returns release instances of each resource from process p's
+// allocation to those available.
+func (s *State) Release(p int, release []int) error {
+	if p < 0 || p >= len(s.Processes) {
+		return fmt.Errorf("no process %d", p)
+	}
+	if len(release) != len(s.Resources) {
+		return fmt.Errorf("release of %d resources, not the %d there are", len(release), len(s.Resources))
+	}
+	for r, n := range release {
+		if n < 0 || n > s.Allocation[p][r] {
+			return fmt.Errorf("%s holds %d %s, cannot release %d", s.Processes[p], s.Allocation[p][r], s.Resources[r], n)
+		}
+	}
+	s.Available, s.Allocation[p] = add(s.Available, release), sub(s.Allocation[p], release)
+	return nil
+}
+
+func fits(a, b []int) bool {
+	for k := range a {
+		if a[k] > b[k] {
+			return false
+		}
+	}
+	return true
+}
+
+func add(a, b []int) []int {
+	sum := make([]int, len(a))
+	for k := range a {
+		sum[k] = a[k] + b[k]
+	}
+	return sum
+}
+
+func sub(a, b []int) []int {
+	diff := make([]int, len(a))
+	for k := range a {
+		diff[k] = a[k] - b[k]
+	}
+	return diff
+}
+
+// ParseState reads a state for the banker's algorithm:
+//
+//	resources A B C
+//	available 3 3 2
+//	allocation
+//	P0 0 1 0
+//	P1 2 0 0
+//	max
+//	P0 7 5 3
+//	P1 3 2 2
+//
+// with comments from # to the end of a line. The resources line is
+// optional, naming the resources A, B, and so on without it, and max must
+// list the processes of allocation, in any order.
+func ParseState(r io.Reader) (*State, error) {
+	s := &State{}
+	var section string
+	maxes := make(map[string][]int)
+	sc := bufio.NewScanner(r)
+	line := 0
+	fail := func(format string, args ...interface{}) error {
+		return &scheduler.ParseError{Line: line, Err: fmt.Errorf(format, args...)}
+	}
+	counts := func(fields []string) ([]int, error) {
+		if s.Available != nil && len(fields) != len(s.Available) {
+			return nil, fail("%d counts, not one for each of the %d resources", len(fields), len(s.Available))
+		}
+		values := make([]int, len(fields))
+		for k, f := range fields {
+			n, err := strconv.Atoi(f)
+			if err != nil {
+				return nil, fail("invalid count %q", f)
+			}
+			values[k] = n
+		}
+		return values, nil
+	}
+	for sc.Scan() {
+		line++
+		text, _, _ := strings.Cut(sc.Text(), "#")
+		fields := strings.Fields(text)
+		if len(fields) == 0 {
+			continue
+		}
+		switch fields[0] {
+		case "resources":
+			if s.Available != nil {
+				return nil, fail("resources must come before available")
+			}
+			s.Resources = fields[1:]
+			continue
+		case "available":
+			values, err := counts(fields[1:])
+			if err != nil {
+				return nil, err
+			}
+			if s.Resources != nil && len(values) != len(s.Resources) {
+				return nil, fail("%d counts, not one for each of the %d resources", len(values), len(s.Resources))
+			}
+			s.Available = values
+			continue
+		case "allocation", "max":
+			if len(fields) != 1 {
+				return nil, fail("%s takes no values on its own line", fields[0])
+			}
+			if s.Available == nil {
+				return nil, fail("available must come before %s", fields[0])
+			}
+			section = fields[0]
+			continue
+		}
+		if section == "" {
+			return nil, fail("unexpected %q before allocation", fields[0])
+		}
+		name := fields[0]
+		values, err := counts(fields[1:])
+		if err != nil {
+			return nil, err
+		}
+		if section == "allocation" {
+			if _, ok := s.Process(name); ok {
+				return nil, fail("%s listed twice", name)
+			}
+			s.Processes = append(s.Processes, name)
+			s.Allocation = append(s.Allocation, values)
+			continue
+		}
+		if _, ok := s.Process(name); !ok {
+			return nil, fail("%s has a maximum but no allocation", name)
+		}
+		if maxes[name] != nil {
+			return nil, fail("%s listed twice", name)
+		}
+		maxes[name] = values
+	}
+	if err := sc.Err(); err != nil {
+		return nil, &scheduler.ParseError{Err: err}
+	}
+	if len(s.Processes) == 0 {
+		return nil, &scheduler.ParseError{Err: errors.New("no processes")}
+	}
+	if s.Resources == nil {
+		for r := range s.Available {
+			s.Resources = append(s.Resources, string(rune('A'+r%26))+strings.Repeat("'", r/26))
+		}
+	}
+	for _, name := range s.Processes {
+		if maxes[name] == nil {
+			return nil, &scheduler.ParseError{Err: fmt.Errorf("%s has no maximum", name)}
+		}
+		s.Max = append(s.Max, maxes[name])
+	}
+	return s, nil
+}
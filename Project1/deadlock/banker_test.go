@@ -0,0 +1,141 @@
+package deadlock
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/rks0134/CSCE4600/Project1/scheduler"
+)
+
+// textbook is Silberschatz's banker's algorithm example.
+const textbook = `# Silberschatz's example
+resources A B C
+available 3 3 2
+allocation
+P0 0 1 0
+P1 2 0 0
+P2 3 0 2
+P3 2 1 1
+P4 0 0 2
+max
+P0 7 5 3
+P1 3 2 2
+P2 9 0 2
+P3 2 2 2
+P4 4 3 3
+`
+
+func loadTextbook(t *testing.T) *State {
+	t.Helper()
+	s, err := ParseState(strings.NewReader(textbook))
+	if err != nil {
+		t.Fatalf("ParseState() error = %v", err)
+	}
+	return s
+}
+
+func TestSafety(t *testing.T) {
+	t.Parallel()
+	s := loadTextbook(t)
+	if err := s.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	if want := []int{7, 4, 3}; !reflect.DeepEqual(s.Need()[0], want) {
+		t.Errorf("Need() of P0 = %v, want %v", s.Need()[0], want)
+	}
+	safety := s.Safety()
+	if want := []int{1, 3, 4, 0, 2}; !safety.Safe() || !reflect.DeepEqual(safety.Sequence(), want) {
+		t.Errorf("Safety() = %+v, want the safe sequence %v", safety, want)
+	}
+	if last := safety.Steps[len(safety.Steps)-1].After; !reflect.DeepEqual(last, []int{10, 5, 7}) {
+		t.Errorf("Safety() ends with work %v, want every instance, 10 5 7", last)
+	}
+
+	s.Available = []int{0, 1, 1}
+	if safety := s.Safety(); safety.Safe() || !reflect.DeepEqual(safety.Sequence(), []int{3, 1}) || !reflect.DeepEqual(safety.Stuck, []int{0, 2, 4}) {
+		t.Errorf("Safety() with 0 1 1 available = %+v, want P3 and P1 to finish and P0, P2, and P4 stuck", safety)
+	}
+}
+
+func TestRequest(t *testing.T) {
+	t.Parallel()
+	s := loadTextbook(t)
+	tests := []struct {
+		process       int
+		request       []int
+		want          Verdict
+		wantAvailable []int
+	}{
+		{process: 1, request: []int{1, 0, 2}, want: Granted, wantAvailable: []int{2, 3, 0}},
+		{process: 4, request: []int{3, 3, 0}, want: Wait, wantAvailable: []int{2, 3, 0}},
+		{process: 0, request: []int{0, 2, 0}, want: Unsafe, wantAvailable: []int{2, 3, 0}},
+		{process: 3, request: []int{0, 2, 0}, want: ExceedsClaim, wantAvailable: []int{2, 3, 0}},
+		{process: 3, request: []int{0, 1, 0}, want: Granted, wantAvailable: []int{2, 2, 0}},
+	}
+	// The requests follow on from one another, so run in order.
+	for _, tt := range tests {
+		d, err := s.Request(tt.process, tt.request)
+		if err != nil {
+			t.Fatalf("Request(%d, %v) error = %v", tt.process, tt.request, err)
+		}
+		if d.Verdict != tt.want || !reflect.DeepEqual(s.Available, tt.wantAvailable) {
+			t.Errorf("Request(%d, %v) = %v leaving %v available, want %v leaving %v", tt.process, tt.request, d.Verdict, s.Available, tt.want, tt.wantAvailable)
+		}
+	}
+	if want := []int{3, 0, 2}; !reflect.DeepEqual(s.Allocation[1], want) {
+		t.Errorf("Request() left P1 holding %v, want %v", s.Allocation[1], want)
+	}
+
+	if err := s.Release(1, []int{1, 0, 2}); err != nil || !reflect.DeepEqual(s.Available, []int{3, 2, 2}) {
+		t.Errorf("Release() = %v, leaving %v available, want 3 2 2", err, s.Available)
+	}
+	if err := s.Release(1, []int{9, 0, 0}); err == nil {
+		t.Error("Release() of more than held succeeded")
+	}
+	if _, err := s.Request(1, []int{1, 0}); err == nil {
+		t.Error("Request() of too few resources succeeded")
+	}
+}
+
+func TestParseState(t *testing.T) {
+	t.Parallel()
+	s, err := ParseState(strings.NewReader("available 1 0\nallocation\nQ 1 1\nmax\nQ 2 1\n"))
+	if err != nil {
+		t.Fatalf("ParseState() error = %v", err)
+	}
+	if want := []string{"A", "B"}; !reflect.DeepEqual(s.Resources, want) {
+		t.Errorf("ParseState() resources = %v, want %v", s.Resources, want)
+	}
+
+	tests := []struct {
+		name     string
+		in       string
+		wantLine int
+	}{
+		{name: "short row", in: "available 1 0\nallocation\nQ 1\n", wantLine: 3},
+		{name: "bad count", in: "available 1 x\n", wantLine: 1},
+		{name: "no available", in: "allocation\nQ 1\n", wantLine: 1},
+		{name: "unknown max", in: "available 1\nallocation\nQ 1\nmax\nR 1\n", wantLine: 5},
+		{name: "missing max", in: "available 1\nallocation\nQ 1\n"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var pe *scheduler.ParseError
+			if _, err := ParseState(strings.NewReader(tt.in)); !errors.As(err, &pe) || pe.Line != tt.wantLine {
+				t.Errorf("ParseState() error = %v, want a parse error on line %d", err, tt.wantLine)
+			}
+		})
+	}
+
+	over, err := ParseState(strings.NewReader("available 1\nallocation\nQ 2\nmax\nQ 1\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := over.Validate(); !errors.Is(err, scheduler.ErrValidation) {
+		t.Errorf("Validate() of an allocation over the maximum error = %v, want %v", err, scheduler.ErrValidation)
+	}
+}
@@ -0,0 +1,361 @@
+package deadlock
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/rks0134/CSCE4600/Project1/scheduler"
+)
+
+// Graph is a resource allocation graph: per process, the instances of each
+// resource it has been assigned and the instances it is waiting for.
+type Graph struct {
+	Processes []string
+	Resources []string
+	// Instances counts the instances of each resource.
+	Instances  []int
+	Assignment [][]int
+	Request    [][]int
+}
+
+// Validate returns an error wrapping scheduler.ErrValidation if more
+// instances of a resource are assigned than exist, or a process requests
+// more than could ever be free for it.
+func (g *Graph) Validate() error {
+	for r, name := range g.Resources {
+		assigned := 0
+		for p := range g.Processes {
+			assigned += g.Assignment[p][r]
+		}
+		if assigned > g.Instances[r] {
+			return fmt.Errorf("%w: %d instances of %s assigned, but it has %d", scheduler.ErrValidation, assigned, name, g.Instances[r])
+		}
+		for p, process := range g.Processes {
+			if g.Assignment[p][r]+g.Request[p][r] > g.Instances[r] {
+				return fmt.Errorf("%w: %s holds and requests %d instances of %s, but it has %d", scheduler.ErrValidation, process, g.Assignment[p][r]+g.Request[p][r], name, g.Instances[r])
+			}
+		}
+	}
+	return nil
+}
+
+// Available is the instances of each resource assigned to no process.
+func (g *Graph) Available() []int {
+	available := append([]int(nil), g.Instances...)
+	for p := range g.Processes {
+		available = sub(available, g.Assignment[p])
+	}
+	return available
+}
+
+// Detection is the outcome of deadlock detection.
+type Detection struct {
+	// Finish is an order the processes outside the deadlock could finish
+	// in, each once the instances it requests are free.
+	Finish []int
+	// Deadlocked are the processes that could never finish.
+	Deadlocked []int
+}
+
+// Detect runs the detection algorithm, reducing the graph: any process whose
+// requests the free instances meet could finish and free its assignment,
+// until none can. The processes left are deadlocked. With one instance of
+// every resource, they are those on a cycle, or waiting for one.
+func (g *Graph) Detect() Detection {
+	return g.detect(nil)
+}
+
+// detect runs the detection algorithm as if the processes in terminated
+// had been terminated, freeing their assignment and dropping their
+// requests.
+func (g *Graph) detect(terminated map[int]bool) Detection {
+	work := g.Available()
+	finished := make([]bool, len(g.Processes))
+	for p := range terminated {
+		work, finished[p] = add(work, g.Assignment[p]), true
+	}
+	var d Detection
+	for progress := true; progress; {
+		progress = false
+		for p := range g.Processes {
+			if !finished[p] && fits(g.Request[p], work) {
+				work, finished[p], progress = add(work, g.Assignment[p]), true, true
+				d.Finish = append(d.Finish, p)
+			}
+		}
+	}
+	for p, done := range finished {
+		if !done {
+			d.Deadlocked = append(d.Deadlocked, p)
+		}
+	}
+	return d
+}
+
+// maxExactVictims is the most deadlocked processes Victims searches every
+// subset of for the smallest victim set.
+const maxExactVictims = 20
+
+// Victims returns a smallest set of deadlocked processes whose termination
+// lets every other process finish, favoring, among sets of that size, the
+// earliest processes. Beyond 20 deadlocked processes it instead terminates,
+// one at a time, the one holding the most instances until the rest can
+// finish, which need not be smallest.
+func (g *Graph) Victims() []int {
+	deadlocked := g.Detect().Deadlocked
+	if len(deadlocked) == 0 {
+		return nil
+	}
+	if len(deadlocked) > maxExactVictims {
+		terminated := make(map[int]bool)
+		var victims []int
+		for left := deadlocked; len(left) > 0; left = g.detect(terminated).Deadlocked {
+			victim := left[0]
+			for _, p := range left {
+				if sum(g.Assignment[p]) > sum(g.Assignment[victim]) {
+					victim = p
+				}
+			}
+			terminated[victim] = true
+			victims = append(victims, victim)
+		}
+		return victims
+	}
+	for size := 1; size <= len(deadlocked); size++ {
+		pick := make([]int, size)
+		for k := range pick {
+			pick[k] = k
+		}
+		for {
+			terminated := make(map[int]bool, size)
+			for _, k := range pick {
+				terminated[deadlocked[k]] = true
+			}
+			if len(g.detect(terminated).Deadlocked) == 0 {
+				victims := make([]int, size)
+				for i, k := range pick {
+					victims[i] = deadlocked[k]
+				}
+				return victims
+			}
+			if !nextCombination(pick, len(deadlocked)) {
+				break
+			}
+		}
+	}
+	return deadlocked
+}
+
+// nextCombination advances pick, increasing indexes below n, to the next
+// combination in lexicographic order, reporting false after the last.
+func nextCombination(pick []int, n int) bool {
+	k := len(pick) - 1
+	for k >= 0 && pick[k] == n-len(pick)+k {
+		k--
+	}
+	if k < 0 {
+		return false
+	}
+	pick[k]++
+	for i := k + 1; i < len(pick); i++ {
+		pick[i] = pick[i-1] + 1
+	}
+	return true
+}
+
+func sum(values []int) int {
+	total := 0
+	for _, n := range values {
+		total += n
+	}
+	return total
+}
+
+// maxCycles is the most cycles Cycles returns.
+const maxCycles = 100
+
+// Cycles returns up to 100 of the graph's elementary cycles, each the names
+// of its nodes in order, starting from its earliest process and alternating
+// between processes and the resources they request and are waiting on.
+func (g *Graph) Cycles() [][]string {
+	// Nodes are the processes and then the resources.
+	n := len(g.Processes) + len(g.Resources)
+	edges := make([][]int, n)
+	for p := range g.Processes {
+		for r := range g.Resources {
+			if g.Request[p][r] > 0 {
+				edges[p] = append(edges[p], len(g.Processes)+r)
+			}
+			if g.Assignment[p][r] > 0 {
+				edges[len(g.Processes)+r] = append(edges[len(g.Processes)+r], p)
+			}
+		}
+	}
+	name := func(v int) string {
+		if v < len(g.Processes) {
+			return g.Processes[v]
+		}
+		return g.Resources[v-len(g.Processes)]
+	}
+
+	// Every cycle passes through a process, so it is found once, from its
+	// earliest process, going only through later nodes.
+	var cycles [][]string
+	onPath := make([]bool, n)
+	var path []int
+	var visit func(start, v int)
+	visit = func(start, v int) {
+		path, onPath[v] = append(path, v), true
+		for _, next := range edges[v] {
+			if len(cycles) == maxCycles {
+				break
+			}
+			switch {
+			case next == start:
+				cycle := make([]string, 0, len(path)+1)
+				for _, u := range path {
+					cycle = append(cycle, name(u))
+				}
+				cycles = append(cycles, append(cycle, name(start)))
+			case next > start && !onPath[next]:
+				visit(start, next)
+			}
+		}
+		path, onPath[v] = path[:len(path)-1], false
+	}
+	for p := range g.Processes {
+		visit(p, p)
+	}
+	return cycles
+}
+
+// ParseGraph reads a resource allocation graph:
+//
+//	processes P1 P2 P3
+//	resources R1 R2:2
+//	P1 -> R1
+//	R1 -> P2
+//	R2 -> P1 2
+//
+// with comments from # to the end of a line. A resource has one instance
+// unless a count follows its name and a colon. An edge from a
+// process to a resource is a request, and from a resource to a process an
+// assignment, each for one instance or the count following it; repeating
+// an edge adds to its count.
+func ParseGraph(r io.Reader) (*Graph, error) {
+	g := &Graph{}
+	processes := make(map[string]int)
+	resources := make(map[string]int)
+	s := bufio.NewScanner(r)
+	line := 0
+	fail := func(format string, args ...interface{}) error {
+		return &scheduler.ParseError{Line: line, Err: fmt.Errorf(format, args...)}
+	}
+	declare := func(name string) error {
+		if _, ok := processes[name]; ok {
+			return fail("%s declared twice", name)
+		}
+		if _, ok := resources[name]; ok {
+			return fail("%s declared twice", name)
+		}
+		return nil
+	}
+	for s.Scan() {
+		line++
+		text, _, _ := strings.Cut(s.Text(), "#")
+		fields := strings.Fields(text)
+		if len(fields) == 0 {
+			continue
+		}
+		if (fields[0] == "processes" || fields[0] == "resources") && g.Assignment != nil {
+			return nil, fail("%s must come before the edges", fields[0])
+		}
+		switch fields[0] {
+		case "processes":
+			if len(g.Resources) > 0 {
+				return nil, fail("processes must come before resources")
+			}
+			for _, name := range fields[1:] {
+				if err := declare(name); err != nil {
+					return nil, err
+				}
+				processes[name] = len(g.Processes)
+				g.Processes = append(g.Processes, name)
+			}
+			continue
+		case "resources":
+			for _, field := range fields[1:] {
+				name, count, hasCount := strings.Cut(field, ":")
+				instances := 1
+				if hasCount {
+					n, err := strconv.Atoi(count)
+					if err != nil || n < 1 {
+						return nil, fail("invalid count of instances %q", count)
+					}
+					instances = n
+				}
+				if err := declare(name); err != nil {
+					return nil, err
+				}
+				resources[name] = len(g.Resources)
+				g.Resources = append(g.Resources, name)
+				g.Instances = append(g.Instances, instances)
+			}
+			continue
+		}
+		if g.Assignment == nil {
+			g.allocate()
+		}
+		if (len(fields) != 3 && len(fields) != 4) || fields[1] != "->" {
+			return nil, fail("want an edge, such as P1 -> R1, not %q", strings.TrimSpace(text))
+		}
+		count := 1
+		if len(fields) == 4 {
+			n, err := strconv.Atoi(fields[3])
+			if err != nil || n < 1 {
+				return nil, fail("invalid count %q", fields[3])
+			}
+			count = n
+		}
+		if p, ok := processes[fields[0]]; ok {
+			r, ok := resources[fields[2]]
+			if !ok {
+				return nil, fail("%s requests %s, which is not a resource", fields[0], fields[2])
+			}
+			g.Request[p][r] += count
+		} else if r, ok := resources[fields[0]]; ok {
+			p, ok := processes[fields[2]]
+			if !ok {
+				return nil, fail("%s is assigned to %s, which is not a process", fields[0], fields[2])
+			}
+			g.Assignment[p][r] += count
+		} else {
+			return nil, fail("%s is neither a process nor a resource", fields[0])
+		}
+	}
+	if err := s.Err(); err != nil {
+		return nil, &scheduler.ParseError{Err: err}
+	}
+	if len(g.Processes) == 0 {
+		return nil, &scheduler.ParseError{Err: errors.New("no processes")}
+	}
+	if g.Assignment == nil {
+		g.allocate()
+	}
+	return g, nil
+}
+
+// allocate makes g's assignment and request matrices, once its processes
+// and resources are declared.
+func (g *Graph) allocate() {
+	g.Assignment = make([][]int, len(g.Processes))
+	g.Request = make([][]int, len(g.Processes))
+	for p := range g.Processes {
+		g.Assignment[p] = make([]int, len(g.Resources))
+		g.Request[p] = make([]int, len(g.Resources))
+	}
+}
@@ -0,0 +1,150 @@
+package deadlock
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/rks0134/CSCE4600/Project1/scheduler"
+)
+
+// deadlocked is Silberschatz's resource allocation graph with a deadlock.
+const deadlocked = `processes P1 P2 P3
+resources R1 R2:2 R3 R4:3
+R1 -> P2
+R2 -> P1
+R2 -> P2
+R3 -> P3
+R4 -> P3
+P1 -> R1
+P2 -> R3
+P3 -> R2
+`
+
+func TestDetect(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name           string
+		in             string
+		wantDeadlocked []int
+		wantFinish     []int
+		wantCycles     [][]string
+		wantVictims    []int
+	}{
+		{
+			name:           "deadlock",
+			in:             deadlocked,
+			wantDeadlocked: []int{0, 1, 2},
+			wantCycles: [][]string{
+				{"P1", "R1", "P2", "R3", "P3", "R2", "P1"},
+				{"P2", "R3", "P3", "R2", "P2"},
+			},
+			wantVictims: []int{0},
+		},
+		{
+			// The cycle P1 R1 P3 R2 P1 is broken by P2 and P4 holding
+			// the other instances without waiting.
+			name:       "cycle without deadlock",
+			in:         "processes P1 P2 P3 P4\nresources R1:2 R2:2\nR1 -> P2\nR1 -> P3\nR2 -> P1\nR2 -> P4\nP1 -> R1\nP3 -> R2\n",
+			wantFinish: []int{1, 3, 0, 2},
+			wantCycles: [][]string{{"P1", "R1", "P3", "R2", "P1"}},
+		},
+		{
+			// P3 waits on the deadlock without being on its cycle, so
+			// is deadlocked too, though terminating it would not help.
+			name:           "waiting on a cycle",
+			in:             "processes P1 P2 P3\nresources R1 R2\nR1 -> P1\nR2 -> P2\nP1 -> R2\nP2 -> R1\nP3 -> R1\n",
+			wantDeadlocked: []int{0, 1, 2},
+			wantCycles:     [][]string{{"P1", "R2", "P2", "R1", "P1"}},
+			wantVictims:    []int{0},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			g, err := ParseGraph(strings.NewReader(tt.in))
+			if err != nil {
+				t.Fatalf("ParseGraph() error = %v", err)
+			}
+			if err := g.Validate(); err != nil {
+				t.Fatalf("Validate() error = %v", err)
+			}
+			d := g.Detect()
+			if !reflect.DeepEqual(d.Deadlocked, tt.wantDeadlocked) || (tt.wantFinish != nil && !reflect.DeepEqual(d.Finish, tt.wantFinish)) {
+				t.Errorf("Detect() = %+v, want %v deadlocked, finishing %v", d, tt.wantDeadlocked, tt.wantFinish)
+			}
+			if got := g.Cycles(); !reflect.DeepEqual(got, tt.wantCycles) {
+				t.Errorf("Cycles() = %v, want %v", got, tt.wantCycles)
+			}
+			if got := g.Victims(); !reflect.DeepEqual(got, tt.wantVictims) {
+				t.Errorf("Victims() = %v, want %v", got, tt.wantVictims)
+			}
+		})
+	}
+}
+
+func TestVictimsPair(t *testing.T) {
+	t.Parallel()
+	// Two separate deadlocks take a victim each.
+	g, err := ParseGraph(strings.NewReader(`processes A B C D
+resources R S T U
+R -> A
+S -> B
+A -> S
+B -> R
+T -> C
+U -> D
+C -> U
+D -> T
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := g.Victims(), []int{0, 2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Victims() = %v, want %v", got, want)
+	}
+}
+
+func TestParseGraph(t *testing.T) {
+	t.Parallel()
+	g, err := ParseGraph(strings.NewReader("processes P\nresources R:3\nR -> P 2\nR -> P\n"))
+	if err != nil {
+		t.Fatalf("ParseGraph() error = %v", err)
+	}
+	if g.Assignment[0][0] != 3 || !reflect.DeepEqual(g.Available(), []int{0}) {
+		t.Errorf("ParseGraph() assignment = %v, want all 3 instances of R assigned", g.Assignment)
+	}
+
+	tests := []struct {
+		name     string
+		in       string
+		wantLine int
+	}{
+		{name: "unknown node", in: "processes P\nresources R\nQ -> R\n", wantLine: 3},
+		{name: "request of a process", in: "processes P Q\nresources R\nP -> Q\n", wantLine: 3},
+		{name: "not an edge", in: "processes P\nresources R\nP R\n", wantLine: 3},
+		{name: "declared twice", in: "processes P\nresources P\n", wantLine: 2},
+		{name: "bad instances", in: "processes P\nresources R:0\n", wantLine: 2},
+		{name: "late resources", in: "processes P\nresources R\nP -> R\nresources S\n", wantLine: 4},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var pe *scheduler.ParseError
+			if _, err := ParseGraph(strings.NewReader(tt.in)); !errors.As(err, &pe) || pe.Line != tt.wantLine {
+				t.Errorf("ParseGraph() error = %v, want a parse error on line %d", err, tt.wantLine)
+			}
+		})
+	}
+
+	over, err := ParseGraph(strings.NewReader("processes P Q\nresources R\nR -> P\nR -> Q\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := over.Validate(); !errors.Is(err, scheduler.ErrValidation) {
+		t.Errorf("Validate() of an overassigned resource error = %v, want %v", err, scheduler.ErrValidation)
+	}
+}
@@ -0,0 +1,194 @@
+package deadlock
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+)
+
+// RenderState writes s's allocation, maximum, and need matrices, a row per
+// process, and the instances available.
+func RenderState(w io.Writer, s *State) {
+	columns := strings.Join(s.Resources, " ")
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"Process", "Allocation " + columns, "Max " + columns, "Need " + columns})
+	need := s.Need()
+	for p, name := range s.Processes {
+		table.Append([]string{name, counts(s.Allocation[p]), counts(s.Max[p]), counts(need[p])})
+	}
+	table.Render()
+	_, _ = fmt.Fprintf(w, "Available %s: %s\n\n", columns, counts(s.Available))
+}
+
+// RenderSafety writes the steps of the safety algorithm on s, and then its
+// safe sequence, or the processes that could not finish.
+func RenderSafety(w io.Writer, s *State, safety Safety) {
+	columns := strings.Join(s.Resources, " ")
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"Step", "Process", "Work " + columns, "Need " + columns, "Work after " + columns})
+	need := s.Need()
+	for k, step := range safety.Steps {
+		table.Append([]string{strconv.Itoa(k + 1), s.Processes[step.Process], counts(step.Work), counts(need[step.Process]), counts(step.After)})
+	}
+	table.Render()
+	if safety.Safe() {
+		_, _ = fmt.Fprintf(w, "Safe, with the safe sequence <%s>\n\n", names(s.Processes, safety.Sequence()))
+		return
+	}
+	work := s.Available
+	if len(safety.Steps) > 0 {
+		work = safety.Steps[len(safety.Steps)-1].After
+	}
+	_, _ = fmt.Fprintf(w, "Unsafe: %s cannot finish, each needing more than the work of %s\n\n", names(s.Processes, safety.Stuck), counts(work))
+}
+
+// RenderDecision writes the banker's decision d on a request in s.
+func RenderDecision(w io.Writer, s *State, d Decision) {
+	name := s.Processes[d.Process]
+	_, _ = fmt.Fprintf(w, "Request by %s for %s %s: ", name, strings.Join(s.Resources, " "), counts(d.Request))
+	switch d.Verdict {
+	case Granted:
+		_, _ = fmt.Fprintf(w, "granted, leaving a safe state with the safe sequence <%s>\n\n", names(s.Processes, d.Safety.Sequence()))
+	case Unsafe:
+		_, _ = fmt.Fprintf(w, "denied, %s waits: granting it would leave %s unable to finish\n\n", name, names(s.Processes, d.Safety.Stuck))
+	case Wait:
+		_, _ = fmt.Fprintf(w, "%s waits, only %s being available\n\n", name, counts(s.Available))
+	case ExceedsClaim:
+		_, _ = fmt.Fprintf(w, "error, exceeding the need of %s left in %s's maximum claim\n\n", counts(s.Need()[d.Process]), name)
+	}
+}
+
+func counts(values []int) string {
+	text := make([]string, len(values))
+	for k, n := range values {
+		text[k] = strconv.Itoa(n)
+	}
+	return strings.Join(text, " ")
+}
+
+// RenderGraph writes what each process of g holds and requests, the
+// cycles, and then the deadlock d found and the victims whose termination
+// would end it, or the order the processes could finish in.
+func RenderGraph(w io.Writer, g *Graph, d Detection, cycles [][]string, victims []int) {
+	deadlocked := make(map[int]bool)
+	for _, p := range d.Deadlocked {
+		deadlocked[p] = true
+	}
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"Process", "Holds", "Requests", "Deadlocked"})
+	for p, name := range g.Processes {
+		mark := ""
+		if deadlocked[p] {
+			mark = "yes"
+		}
+		table.Append([]string{name, instances(g, g.Assignment[p]), instances(g, g.Request[p]), mark})
+	}
+	table.Render()
+	_, _ = fmt.Fprintf(w, "Free: %s\n\n", instances(g, g.Available()))
+
+	if len(cycles) == 0 {
+		_, _ = fmt.Fprint(w, "No cycles\n\n")
+	} else {
+		_, _ = fmt.Fprintln(w, "Cycles")
+		for _, c := range cycles {
+			_, _ = fmt.Fprintf(w, "  %s\n", strings.Join(c, " -> "))
+		}
+		if len(cycles) == maxCycles {
+			_, _ = fmt.Fprintf(w, "  (the first %d)\n", maxCycles)
+		}
+		_, _ = fmt.Fprintln(w)
+	}
+
+	if len(d.Deadlocked) == 0 {
+		_, _ = fmt.Fprintf(w, "No deadlock: the processes could finish in the order %s\n", names(g.Processes, d.Finish))
+		if len(cycles) > 0 {
+			_, _ = fmt.Fprintln(w, "The cycles are through resources with instances enough to break them.")
+		}
+		_, _ = fmt.Fprintln(w)
+		return
+	}
+	_, _ = fmt.Fprintf(w, "Deadlock: %s can never finish\n", names(g.Processes, d.Deadlocked))
+	_, _ = fmt.Fprintf(w, "Terminating %s would let every other process finish\n\n", names(g.Processes, victims))
+}
+
+// RenderDOT writes g in Graphviz's DOT language: processes as circles,
+// resources as boxes with a dot per instance, request edges from process to
+// resource dashed, and assignment edges from resource to process solid,
+// with the deadlocked processes and the edges among them in red.
+func RenderDOT(w io.Writer, g *Graph, deadlocked []int) {
+	red := make(map[int]bool)
+	for _, p := range deadlocked {
+		red[p] = true
+	}
+	color := func(on bool) string {
+		if on {
+			return `, color="red", fontcolor="red"`
+		}
+		return ""
+	}
+	_, _ = fmt.Fprintln(w, "digraph rag {")
+	_, _ = fmt.Fprintln(w, "  rankdir=LR;")
+	for p, name := range g.Processes {
+		_, _ = fmt.Fprintf(w, "  %q [shape=circle%s];\n", name, color(red[p]))
+	}
+	for r, name := range g.Resources {
+		_, _ = fmt.Fprintf(w, "  %q [shape=box, label=%q];\n", name, name+"\n"+strings.Repeat("•", g.Instances[r]))
+	}
+	for p, process := range g.Processes {
+		for r, resource := range g.Resources {
+			for k := 0; k < g.Request[p][r]; k++ {
+				_, _ = fmt.Fprintf(w, "  %q -> %q [style=dashed%s];\n", process, resource, color(red[p]))
+			}
+		}
+	}
+	for r, resource := range g.Resources {
+		for p, process := range g.Processes {
+			for k := 0; k < g.Assignment[p][r]; k++ {
+				attrs := ""
+				if red[p] && waitedOn(g, r, red) {
+					attrs = ` [color="red"]`
+				}
+				_, _ = fmt.Fprintf(w, "  %q -> %q%s;\n", resource, process, attrs)
+			}
+		}
+	}
+	_, _ = fmt.Fprintln(w, "}")
+}
+
+// waitedOn is whether a process in red requests resource r.
+func waitedOn(g *Graph, r int, red map[int]bool) bool {
+	for p := range g.Processes {
+		if red[p] && g.Request[p][r] > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// instances describes counts of g's resources, such as "R1, 2 R2".
+func instances(g *Graph, counts []int) string {
+	var held []string
+	for r, n := range counts {
+		switch {
+		case n == 1:
+			held = append(held, g.Resources[r])
+		case n > 1:
+			held = append(held, fmt.Sprintf("%d %s", n, g.Resources[r]))
+		}
+	}
+	if len(held) == 0 {
+		return "-"
+	}
+	return strings.Join(held, ", ")
+}
+
+func names(all []string, indexes []int) string {
+	picked := make([]string, len(indexes))
+	for k, i := range indexes {
+		picked[k] = all[i]
+	}
+	return strings.Join(picked, ", ")
+}
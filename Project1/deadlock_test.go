@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func Test_runDeadlock(t *testing.T) {
+	t.Parallel()
+	graph := filepath.Join(t.TempDir(), "graph.txt")
+	if err := os.WriteFile(graph, []byte(`processes P1 P2 P3
+resources R1 R2:2 R3 R4:3
+R1 -> P2
+R2 -> P1
+R2 -> P2
+R3 -> P3
+R4 -> P3
+P1 -> R1
+P2 -> R3
+P3 -> R2
+`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	var w strings.Builder
+	if err := run(context.Background(), &w, "binary_name", "deadlock", graph); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"| P2      | R1, R2 | R3       | yes        |\n",
+		"Free: 2 R4\n",
+		"  P1 -> R1 -> P2 -> R3 -> P3 -> R2 -> P1\n",
+		"Deadlock: P1, P2, P3 can never finish\nTerminating P1 would let every other process finish\n",
+	} {
+		if !strings.Contains(w.String(), want) {
+			t.Errorf("deadlock wrote:\n%s\nwant it to contain %q", w.String(), want)
+		}
+	}
+
+	w.Reset()
+	if err := run(context.Background(), &w, "binary_name", "deadlock", "-format", "dot", graph); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"digraph rag {\n",
+		"  \"R2\" [shape=box, label=\"R2\\n••\"];\n",
+		"  \"P1\" -> \"R1\" [style=dashed, color=\"red\", fontcolor=\"red\"];\n",
+		"  \"R4\" -> \"P3\";\n",
+	} {
+		if !strings.Contains(w.String(), want) {
+			t.Errorf("deadlock -format dot wrote:\n%s\nwant it to contain %q", w.String(), want)
+		}
+	}
+
+	if err := run(context.Background(), io.Discard, "binary_name", "deadlock", "-format", "png", graph); !errors.Is(err, ErrInvalidArgs) {
+		t.Errorf("deadlock -format png error = %v, want %v", err, ErrInvalidArgs)
+	}
+}
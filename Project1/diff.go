@@ -0,0 +1,166 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"strings"
+
+	"github.com/rks0134/CSCE4600/Project1/scheduler"
+)
+
+// ErrDifferent is returned by diff when the results differ.
+var ErrDifferent = errors.New("results differ")
+
+// diffCmd compares two files of results schedule by schedule.
+type diffCmd struct{}
+
+func (c *diffCmd) defineFlags(*flag.FlagSet) {}
+
+func (c *diffCmd) run(_ context.Context, w io.Writer, args []string) error {
+	if len(args) != 2 {
+		return fmt.Errorf("%w: must give the two files of results to compare", ErrInvalidArgs)
+	}
+	old, err := loadResults(args[0])
+	if err != nil {
+		return err
+	}
+	cur, err := loadResults(args[1])
+	if err != nil {
+		return err
+	}
+
+	// Schedules are matched by title, in the order of the old file and then
+	// of the new schedules it lacks.
+	var titles []string
+	olds := make(map[string]*scheduler.ScheduleResult)
+	curs := make(map[string]*scheduler.ScheduleResult)
+	for _, r := range old {
+		if olds[r.Title] == nil {
+			titles = append(titles, r.Title)
+		}
+		olds[r.Title] = r.ScheduleResult
+	}
+	for _, r := range cur {
+		if olds[r.Title] == nil && curs[r.Title] == nil {
+			titles = append(titles, r.Title)
+		}
+		curs[r.Title] = r.ScheduleResult
+	}
+	differ := 0
+	for _, title := range titles {
+		lines := diffResults(olds[title], curs[title])
+		if len(lines) == 0 {
+			_, _ = fmt.Fprintf(w, "%s: identical\n", title)
+			continue
+		}
+		differ++
+		_, _ = fmt.Fprintf(w, "%s:\n", title)
+		for _, line := range lines {
+			_, _ = fmt.Fprintf(w, "  %s\n", line)
+		}
+	}
+	if differ > 0 {
+		return fmt.Errorf("%w: %d of %d schedules", ErrDifferent, differ, len(titles))
+	}
+
+	return nil
+}
+
+// diffResults describes how b differs from a: the first slice at which
+// their Gantt charts diverge and every metric that changed, in time units,
+// along with their seeds if those differ. Either may be nil, for a schedule
+// only one file has.
+func diffResults(a, b *scheduler.ScheduleResult) []string {
+	switch {
+	case a == nil:
+		return []string{"only in the new results"}
+	case b == nil:
+		return []string{"only in the old results"}
+	}
+	var lines []string
+	if len(a.Gantt) > 0 && len(b.Gantt) > 0 {
+		if line := divergence(a, b); line != "" {
+			lines = append(lines, line)
+		}
+	} else if len(a.Gantt) != len(b.Gantt) {
+		lines = append(lines, "only one result has a Gantt chart to compare")
+	}
+
+	ma, mb := reflect.ValueOf(a.UnitMetrics()), reflect.ValueOf(b.UnitMetrics())
+	for k := 0; k < ma.NumField(); k++ {
+		name, _, _ := strings.Cut(ma.Type().Field(k).Tag.Get("json"), ",")
+		va, vb := ma.Field(k), mb.Field(k)
+		if reflect.DeepEqual(va.Interface(), vb.Interface()) {
+			continue
+		}
+		switch va.Kind() {
+		case reflect.Float64, reflect.Int, reflect.Bool:
+			lines = append(lines, fmt.Sprintf("%s %v -> %v", name, va.Interface(), vb.Interface()))
+		default:
+			lines = append(lines, name+" differ")
+		}
+	}
+	// Runs without -seed get a random seed, which only matters when the
+	// schedules differ too.
+	if len(lines) > 0 && !reflect.DeepEqual(a.Seed, b.Seed) {
+		lines = append([]string{fmt.Sprintf("seed %s -> %s", seedText(a.Seed), seedText(b.Seed))}, lines...)
+	}
+
+	return lines
+}
+
+// divergence describes the first slice at which the Gantt charts of a and
+// b differ, comparing times in time units, or returns "" if none does.
+func divergence(a, b *scheduler.ScheduleResult) string {
+	ua, ub := unitsPer(a), unitsPer(b)
+	for i := 0; i < len(a.Gantt) || i < len(b.Gantt); i++ {
+		switch {
+		case i == len(a.Gantt):
+			return fmt.Sprintf("diverges at slice %d: the old schedule ends, the new one has %s", i+1, describeSlice(b.Gantt[i], ub))
+		case i == len(b.Gantt):
+			return fmt.Sprintf("diverges at slice %d: the new schedule ends, the old one has %s", i+1, describeSlice(a.Gantt[i], ua))
+		}
+		sa, sb := a.Gantt[i], b.Gantt[i]
+		startA, startB := float64(sa.Start)/ua, float64(sb.Start)/ub
+		if startA == startB && float64(sa.Stop)/ua == float64(sb.Stop)/ub && sa.PID == sb.PID && sa.Kind == sb.Kind && sa.CPU == sb.CPU {
+			continue
+		}
+		return fmt.Sprintf("diverges at slice %d, time %g: %s -> %s", i+1, math.Min(startA, startB), describeSlice(sa, ua), describeSlice(sb, ub))
+	}
+
+	return ""
+}
+
+// unitsPer returns the ticks per time unit of r's times.
+func unitsPer(r *scheduler.ScheduleResult) float64 {
+	if r.TicksPerUnit < 1 {
+		return 1
+	}
+	return float64(r.TicksPerUnit)
+}
+
+func seedText(seed *int64) string {
+	if seed == nil {
+		return "none"
+	}
+	return fmt.Sprint(*seed)
+}
+
+// describeSlice describes ts with its times in units of u ticks.
+func describeSlice(ts scheduler.TimeSlice, u float64) string {
+	what := fmt.Sprintf("process %d", ts.PID)
+	switch ts.Kind {
+	case scheduler.SliceRun:
+	case scheduler.SliceIdle:
+		what = "idle"
+	default:
+		what += " " + string(ts.Kind)
+	}
+
+	return fmt.Sprintf("%s on CPU %d from %g to %g", what, ts.CPU, float64(ts.Start)/u, float64(ts.Stop)/u)
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"context"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func Test_runDiff(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	results := func(name string, args ...string) string {
+		path := filepath.Join(dir, name)
+		args = append([]string{"binary_name", "-seed", "1", "-output", path, "schedule", "-format", "json"}, args...)
+		if err := run(context.Background(), io.Discard, append(args, "example_processes.csv")...); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	old, same, slower := results("old.json"), results("same.json"), results("slower.json", "-switch-cost", "1")
+
+	var w strings.Builder
+	if err := run(context.Background(), &w, "binary_name", "diff", old, same); err != nil || strings.Contains(w.String(), "diverges") {
+		t.Errorf("diff of identical results error = %v, output:\n%s", err, w.String())
+	}
+	w.Reset()
+	err := run(context.Background(), &w, "binary_name", "diff", old, slower)
+	if exitCode(err) != ExitDifferent {
+		t.Errorf("diff of different results error = %v, want %v", err, ErrDifferent)
+	}
+	for _, want := range []string{
+		"First-come, first-serve:\n  diverges at slice 2, time 5: process 2 on CPU 0 from 5 to 14 -> process 2 switch on CPU 0 from 5 to 6\n",
+		"  average_wait 3.3333333333333335 -> 4.333333333333333\n",
+	} {
+		if !strings.Contains(w.String(), want) {
+			t.Errorf("diff wrote:\n%s\nwant it to contain %q", w.String(), want)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/rks0134/CSCE4600/Project1/disk"
+	"github.com/rks0134/CSCE4600/Project1/scheduler"
+)
+
+// diskCmd compares disk scheduling algorithms on a request queue.
+type diskCmd struct {
+	*globalFlags
+	head       int
+	cylinders  int
+	direction  string
+	algorithms string
+	waits      bool
+	generate   int
+}
+
+func (c *diskCmd) defineFlags(flags *flag.FlagSet) {
+	flags.IntVar(&c.head, "head", 0, "`cylinder` the head starts at")
+	flags.IntVar(&c.cylinders, "cylinders", 200, "number of `cylinders` on the disk, numbered from 0")
+	flags.StringVar(&c.direction, "direction", "up", "`direction` the head is moving in, up toward higher cylinders or down")
+	flags.StringVar(&c.algorithms, "algorithms", "all", "comma separated `algorithms` to compare (fcfs, sstf, scan, cscan, look, clook), or all")
+	flags.BoolVar(&c.waits, "waits", false, "also print each request's wait under each algorithm")
+	flags.IntVar(&c.generate, "generate", 0, "generate a queue of `n` requests instead of reading one (reproducible with -seed)")
+}
+
+func (c *diskCmd) run(_ context.Context, w io.Writer, args []string) error {
+	var algs []disk.Algorithm
+	if c.algorithms == "all" {
+		algs = disk.Algorithms
+	} else {
+		for _, name := range strings.Split(c.algorithms, ",") {
+			alg, ok := disk.Find(strings.TrimSpace(name))
+			if !ok {
+				return fmt.Errorf("%w: unknown disk scheduling algorithm %q", ErrInvalidArgs, name)
+			}
+			algs = append(algs, alg)
+		}
+	}
+	d := disk.Disk{Cylinders: c.cylinders, Head: c.head}
+	switch c.direction {
+	case "up":
+		d.Up = true
+	case "down":
+	default:
+		return fmt.Errorf("%w: -direction must be up or down, not %q", ErrInvalidArgs, c.direction)
+	}
+	if c.cylinders < 1 {
+		return fmt.Errorf("%w: -cylinders must be positive", ErrInvalidArgs)
+	}
+	if c.head < 0 || c.head >= c.cylinders {
+		return fmt.Errorf("%w: -head must be a cylinder from 0 to %d", ErrInvalidArgs, c.cylinders-1)
+	}
+
+	var requests []int
+	switch {
+	case c.generate > 0:
+		if len(args) > 0 {
+			return fmt.Errorf("%w: -generate takes no request file", ErrInvalidArgs)
+		}
+		requests = disk.Generate(c.rand(), c.generate, c.cylinders)
+	case len(args) == 1:
+		if err := readInputFile(args[0], func(r io.Reader) (err error) {
+			requests, err = disk.ParseRequests(r)
+			return err
+		}); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("%w: must give a request file, or -generate", ErrInvalidArgs)
+	}
+
+	var results []*disk.Result
+	for _, alg := range algs {
+		r, err := disk.Schedule(alg, d, requests)
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidArgs, err)
+		}
+		results = append(results, r)
+	}
+
+	scheduler.RenderTitle(w, fmt.Sprintf("Disk scheduling, %d requests", len(requests)))
+	if c.generate > 0 {
+		_, _ = fmt.Fprintf(w, "Seed: %d\n\n", c.seed.value)
+	}
+	_, _ = fmt.Fprintf(w, "Head at cylinder %d of 0 to %d, moving %s\n\n", c.head, c.cylinders-1, c.direction)
+	for _, r := range results {
+		disk.RenderChart(w, r)
+	}
+	disk.RenderComparison(w, results)
+	if c.waits {
+		disk.RenderWaits(w, results)
+	}
+
+	return nil
+}
@@ -0,0 +1,272 @@
+// Package disk simulates disk scheduling: the order in which each algorithm
+// services a queue of requests for cylinders, and how far it moves the head
+// to do so.
+package disk
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/rks0134/CSCE4600/Project1/scheduler"
+)
+
+// Algorithm is a disk scheduling algorithm.
+type Algorithm struct {
+	Name  string
+	Title string
+}
+
+// Algorithms are the disk scheduling algorithms, in the order reports list
+// them.
+var Algorithms = []Algorithm{
+	{Name: "fcfs", Title: "First-come, first-served"},
+	{Name: "sstf", Title: "Shortest seek time first"},
+	{Name: "scan", Title: "SCAN"},
+	{Name: "cscan", Title: "C-SCAN"},
+	{Name: "look", Title: "LOOK"},
+	{Name: "clook", Title: "C-LOOK"},
+}
+
+// Find returns the algorithm named name.
+func Find(name string) (Algorithm, bool) {
+	for _, a := range Algorithms {
+		if a.Name == name {
+			return a, true
+		}
+	}
+	return Algorithm{}, false
+}
+
+// Disk is the disk the requests are for, and where its head starts.
+type Disk struct {
+	// Cylinders counts the cylinders, numbered from 0.
+	Cylinders int
+	Head      int
+	// Up is whether the head is moving toward higher cylinders, the way
+	// SCAN, C-SCAN, LOOK, and C-LOOK sweep first.
+	Up bool
+}
+
+// None is the request of a move that services none.
+const None = -1
+
+// Move is one movement of the head, servicing the request with index
+// Request at To, or None for a move to the edge of the disk. A Return is
+// C-SCAN and C-LOOK's seek back to the other end of the disk.
+type Move struct {
+	From, To int
+	Request  int
+	Return   bool
+}
+
+// Distance is how many cylinders m moves the head.
+func (m Move) Distance() int {
+	if m.To < m.From {
+		return m.From - m.To
+	}
+	return m.To - m.From
+}
+
+// Result is an algorithm's run over a request queue.
+type Result struct {
+	Algorithm Algorithm
+	Disk      Disk
+	Requests  []int
+	Moves     []Move
+	// Movement is the total head movement in cylinders, returns included.
+	Movement int
+	// Waits holds, per request, the cylinders the head moved before
+	// servicing it, all requests having been queued at the start.
+	Waits []int
+}
+
+// MeanWait is the mean of r's waits.
+func (r *Result) MeanWait() float64 {
+	if len(r.Waits) == 0 {
+		return 0
+	}
+	total := 0
+	for _, w := range r.Waits {
+		total += w
+	}
+	return float64(total) / float64(len(r.Waits))
+}
+
+// MaxWait is the longest of r's waits.
+func (r *Result) MaxWait() int {
+	longest := 0
+	for _, w := range r.Waits {
+		if w > longest {
+			longest = w
+		}
+	}
+	return longest
+}
+
+// Schedule runs alg over requests, the cylinders queued at the start, on d.
+// Requests at the cylinder under the head are serviced first, without
+// moving it; SCAN and C-SCAN travel to the edge of the disk only when
+// requests remain behind the head, and C-SCAN and C-LOOK service requests
+// only while sweeping in d's direction, counting the seek back toward the
+// other end as movement.
+func Schedule(alg Algorithm, d Disk, requests []int) (*Result, error) {
+	if d.Cylinders < 1 {
+		return nil, fmt.Errorf("need at least one cylinder, not %d", d.Cylinders)
+	}
+	if d.Head < 0 || d.Head >= d.Cylinders {
+		return nil, fmt.Errorf("head at cylinder %d is off the disk of cylinders 0 to %d", d.Head, d.Cylinders-1)
+	}
+	for _, c := range requests {
+		if c < 0 || c >= d.Cylinders {
+			return nil, fmt.Errorf("request for cylinder %d is off the disk of cylinders 0 to %d", c, d.Cylinders-1)
+		}
+	}
+	r := &Result{Algorithm: alg, Disk: d, Requests: requests, Waits: make([]int, len(requests))}
+	head := d.Head
+	move := func(to, request int, ret bool) {
+		m := Move{From: head, To: to, Request: request, Return: ret}
+		r.Moves = append(r.Moves, m)
+		r.Movement += m.Distance()
+		if request != None {
+			r.Waits[request] = r.Movement
+		}
+		head = to
+	}
+	serve := func(order []int) {
+		for _, i := range order {
+			move(requests[i], i, false)
+		}
+	}
+
+	switch alg.Name {
+	case "fcfs":
+		for i := range requests {
+			move(requests[i], i, false)
+		}
+	case "sstf":
+		done := make([]bool, len(requests))
+		for range requests {
+			next := None
+			for i, c := range requests {
+				if !done[i] && (next == None || distance(head, c) < distance(head, requests[next])) {
+					next = i
+				}
+			}
+			done[next] = true
+			move(requests[next], next, false)
+		}
+	default:
+		// ahead are the requests in d's direction, nearest first, and
+		// behind the rest, nearest first too.
+		order := make([]int, len(requests))
+		for i := range order {
+			order[i] = i
+		}
+		sort.SliceStable(order, func(a, b int) bool {
+			if d.Up {
+				return requests[order[a]] < requests[order[b]]
+			}
+			return requests[order[a]] > requests[order[b]]
+		})
+		split := sort.Search(len(order), func(k int) bool {
+			if d.Up {
+				return requests[order[k]] >= d.Head
+			}
+			return requests[order[k]] <= d.Head
+		})
+		ahead, behind := order[split:], reverse(order[:split])
+		edge, far := d.Cylinders-1, 0
+		if !d.Up {
+			edge, far = 0, d.Cylinders-1
+		}
+		serve(ahead)
+		if len(behind) == 0 {
+			break
+		}
+		switch alg.Name {
+		case "scan":
+			if head != edge {
+				move(edge, None, false)
+			}
+			serve(behind)
+		case "look":
+			serve(behind)
+		case "cscan":
+			if head != edge {
+				move(edge, None, false)
+			}
+			move(far, None, true)
+			serve(reverse(behind))
+		case "clook":
+			rest := reverse(behind)
+			move(requests[rest[0]], rest[0], true)
+			serve(rest[1:])
+		}
+	}
+
+	return r, nil
+}
+
+func distance(a, b int) int {
+	if a < b {
+		return b - a
+	}
+	return a - b
+}
+
+func reverse(order []int) []int {
+	reversed := make([]int, len(order))
+	for k, i := range order {
+		reversed[len(order)-1-k] = i
+	}
+	return reversed
+}
+
+// ParseRequests reads a request queue: cylinder numbers separated by
+// spaces, commas, or newlines, with comments from # to the end of a line.
+func ParseRequests(r io.Reader) ([]int, error) {
+	var requests []int
+	s := bufio.NewScanner(r)
+	for line := 1; s.Scan(); line++ {
+		text, _, _ := strings.Cut(s.Text(), "#")
+		for start := 0; start < len(text); {
+			end := strings.IndexAny(text[start:], ", \t\r")
+			if end < 0 {
+				end = len(text)
+			} else {
+				end += start
+			}
+			if field := text[start:end]; field != "" {
+				n, err := strconv.Atoi(field)
+				if err != nil || n < 0 {
+					return nil, &scheduler.ParseError{Line: line, Column: start + 1, Err: fmt.Errorf("invalid cylinder %q", field)}
+				}
+				requests = append(requests, n)
+			}
+			start = end + 1
+		}
+	}
+	if err := s.Err(); err != nil {
+		return nil, &scheduler.ParseError{Err: err}
+	}
+	if len(requests) == 0 {
+		return nil, &scheduler.ParseError{Err: errors.New("no requests")}
+	}
+	return requests, nil
+}
+
+// Generate returns n requests for cylinders drawn uniformly from 0 to
+// cylinders-1.
+func Generate(rng *rand.Rand, n, cylinders int) []int {
+	requests := make([]int, n)
+	for i := range requests {
+		requests[i] = rng.Intn(cylinders)
+	}
+	return requests
+}
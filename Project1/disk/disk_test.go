@@ -0,0 +1,107 @@
+package disk
+
+import (
+	"errors"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/rks0134/CSCE4600/Project1/scheduler"
+)
+
+// textbook is the request queue of Silberschatz's disk scheduling examples,
+// with the head at cylinder 53 of 200.
+var textbook = []int{98, 183, 37, 122, 14, 124, 65, 67}
+
+func TestSchedule(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		alg          string
+		up           bool
+		wantMovement int
+		wantOrder    []int
+	}{
+		{alg: "fcfs", up: true, wantMovement: 640, wantOrder: textbook},
+		{alg: "sstf", up: true, wantMovement: 236, wantOrder: []int{65, 67, 37, 14, 98, 122, 124, 183}},
+		{alg: "scan", up: false, wantMovement: 236, wantOrder: []int{37, 14, 0, 65, 67, 98, 122, 124, 183}},
+		{alg: "scan", up: true, wantMovement: 331, wantOrder: []int{65, 67, 98, 122, 124, 183, 199, 37, 14}},
+		{alg: "cscan", up: true, wantMovement: 382, wantOrder: []int{65, 67, 98, 122, 124, 183, 199, 0, 14, 37}},
+		{alg: "look", up: false, wantMovement: 208, wantOrder: []int{37, 14, 65, 67, 98, 122, 124, 183}},
+		{alg: "clook", up: true, wantMovement: 322, wantOrder: []int{65, 67, 98, 122, 124, 183, 14, 37}},
+		{alg: "clook", up: false, wantMovement: 326, wantOrder: []int{37, 14, 183, 124, 122, 98, 67, 65}},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.alg, func(t *testing.T) {
+			t.Parallel()
+			alg, ok := Find(tt.alg)
+			if !ok {
+				t.Fatalf("Find(%q) found nothing", tt.alg)
+			}
+			r, err := Schedule(alg, Disk{Cylinders: 200, Head: 53, Up: tt.up}, textbook)
+			if err != nil {
+				t.Fatalf("Schedule() error = %v", err)
+			}
+			var order []int
+			moved := 0
+			for _, m := range r.Moves {
+				order = append(order, m.To)
+				moved += m.Distance()
+			}
+			if r.Movement != tt.wantMovement || moved != r.Movement || !reflect.DeepEqual(order, tt.wantOrder) {
+				t.Errorf("Schedule() movement = %d over %v, want %d over %v", r.Movement, order, tt.wantMovement, tt.wantOrder)
+			}
+			if r.MaxWait() != r.Waits[indexOf(textbook, r.Moves[len(r.Moves)-1].To)] {
+				t.Errorf("Schedule() waits = %v, want the last request serviced to wait longest", r.Waits)
+			}
+		})
+	}
+}
+
+func indexOf(requests []int, c int) int {
+	for i, r := range requests {
+		if r == c {
+			return i
+		}
+	}
+	return None
+}
+
+func TestScheduleInvalid(t *testing.T) {
+	t.Parallel()
+	fcfs, _ := Find("fcfs")
+	for _, d := range []Disk{{Cylinders: 0}, {Cylinders: 100, Head: 100}, {Cylinders: 150, Head: 53}} {
+		if _, err := Schedule(fcfs, d, textbook); err == nil {
+			t.Errorf("Schedule() on %+v succeeded", d)
+		}
+	}
+}
+
+func TestParseRequests(t *testing.T) {
+	t.Parallel()
+	got, err := ParseRequests(strings.NewReader("98, 183 37\n# comment\n122\n"))
+	if want := []int{98, 183, 37, 122}; err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseRequests() = %v, %v, want %v", got, err, want)
+	}
+	var pe *scheduler.ParseError
+	if _, err := ParseRequests(strings.NewReader("1\n2 three\n")); !errors.As(err, &pe) || pe.Line != 2 || pe.Column != 3 {
+		t.Errorf("ParseRequests() error = %v, want a parse error at line 2, column 3", err)
+	}
+	if _, err := ParseRequests(strings.NewReader("")); !errors.As(err, &pe) {
+		t.Errorf("ParseRequests() of nothing error = %v, want a parse error", err)
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	t.Parallel()
+	requests := Generate(rand.New(rand.NewSource(1)), 100, 50)
+	if len(requests) != 100 {
+		t.Fatalf("Generate() made %d requests, want 100", len(requests))
+	}
+	for _, c := range requests {
+		if c < 0 || c >= 50 {
+			t.Fatalf("Generate() requested cylinder %d, want 0 to 49", c)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package disk
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+)
+
+// chartWidth is how many columns a head movement chart spans the disk with.
+const chartWidth = 60
+
+// RenderChart writes r's head movement as the textbook chart, turned into
+// text: the disk across the top and a row per move, drawing the path the
+// head took to the cylinder the row is labeled with. A request serviced is
+// marked o, the edge of the disk |, and a return's path is dotted.
+func RenderChart(w io.Writer, r *Result) {
+	_, _ = fmt.Fprintf(w, "%s: %d cylinders of head movement\n", r.Algorithm.Title, r.Movement)
+	last := r.Disk.Cylinders - 1
+	column := func(c int) int {
+		if last == 0 {
+			return 0
+		}
+		return c * chartWidth / last
+	}
+	label := strconv.Itoa(last)
+	_, _ = fmt.Fprintf(w, "%6s 0%s%s\n", "", strings.Repeat(" ", chartWidth-len(label)), label)
+	_, _ = fmt.Fprintf(w, "%6s +%s+\n", "", strings.Repeat("-", chartWidth-1))
+
+	row := []byte(strings.Repeat(" ", chartWidth+1))
+	row[column(r.Disk.Head)] = '*'
+	_, _ = fmt.Fprintf(w, "%6d %s\n", r.Disk.Head, strings.TrimRight(string(row), " "))
+	for _, m := range r.Moves {
+		row := []byte(strings.Repeat(" ", chartWidth+1))
+		from, to := column(m.From), column(m.To)
+		path := byte('-')
+		if m.Return {
+			path = '.'
+		}
+		lo, hi := from, to
+		if lo > hi {
+			lo, hi = hi, lo
+		}
+		for k := lo; k <= hi; k++ {
+			row[k] = path
+		}
+		row[to] = 'o'
+		if m.Request == None {
+			row[to] = '|'
+		}
+		_, _ = fmt.Fprintf(w, "%6d %s\n", m.To, strings.TrimRight(string(row), " "))
+	}
+	_, _ = fmt.Fprintln(w)
+}
+
+// RenderComparison writes a table of each result's total head movement,
+// mean and longest wait, and the order it serviced its requests in.
+func RenderComparison(w io.Writer, results []*Result) {
+	_, _ = fmt.Fprintln(w, "Head movement")
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"Algorithm", "Movement", "Mean wait", "Max wait", "Order"})
+	table.SetAutoWrapText(false)
+	for _, r := range results {
+		var order []string
+		for _, m := range r.Moves {
+			if m.Request != None {
+				order = append(order, strconv.Itoa(m.To))
+			}
+		}
+		table.Append([]string{
+			r.Algorithm.Name,
+			strconv.Itoa(r.Movement),
+			fmt.Sprintf("%.2f", r.MeanWait()),
+			strconv.Itoa(r.MaxWait()),
+			strings.Join(order, " "),
+		})
+	}
+	table.Render()
+	_, _ = fmt.Fprintln(w)
+}
+
+// RenderWaits writes a table of each request's wait, in cylinders the head
+// moved before servicing it, under each result, which must all be of the
+// same requests.
+func RenderWaits(w io.Writer, results []*Result) {
+	if len(results) == 0 {
+		return
+	}
+	_, _ = fmt.Fprintln(w, "Wait by request (cylinders of head movement before service)")
+	table := tablewriter.NewWriter(w)
+	header := []string{"Request", "Cylinder"}
+	for _, r := range results {
+		header = append(header, r.Algorithm.Name)
+	}
+	table.SetHeader(header)
+	for i, c := range results[0].Requests {
+		row := []string{strconv.Itoa(i + 1), strconv.Itoa(c)}
+		for _, r := range results {
+			row = append(row, strconv.Itoa(r.Waits[i]))
+		}
+		table.Append(row)
+	}
+	table.Render()
+	_, _ = fmt.Fprintln(w)
+}
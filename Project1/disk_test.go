@@ -0,0 +1,50 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func Test_runDisk(t *testing.T) {
+	t.Parallel()
+	requests := filepath.Join(t.TempDir(), "requests.txt")
+	if err := os.WriteFile(requests, []byte("98, 183, 37, 122, 14, 124, 65, 67\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	var w strings.Builder
+	if err := run(context.Background(), &w, "binary_name", "disk", "-head", "53", "-algorithms", "sstf,cscan", "-waits", requests); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"Head at cylinder 53 of 0 to 199, moving up\n",
+		"Shortest seek time first: 236 cylinders of head movement\n",
+		"   199                                                        -----|\n" +
+			"     0 |............................................................\n" +
+			"    14 ----o\n",
+		"| sstf      |      236 |    109.50 |      236 | 65 67 37 14 98 122 124 183 |\n",
+		"| cscan     |      382 |    135.25 |      382 | 65 67 98 122 124 183 14 37 |\n",
+		"|       3 |       37 |   44 |   382 |\n",
+	} {
+		if !strings.Contains(w.String(), want) {
+			t.Errorf("disk wrote:\n%s\nwant it to contain %q", w.String(), want)
+		}
+	}
+
+	for _, args := range [][]string{
+		{"-head", "200", requests},
+		{"-direction", "left", requests},
+		{"-algorithms", "elevator", requests},
+		{"-cylinders", "100", requests},
+		{},
+	} {
+		err := run(context.Background(), io.Discard, append([]string{"binary_name", "disk"}, args...)...)
+		if !errors.Is(err, ErrInvalidArgs) {
+			t.Errorf("disk %v error = %v, want %v", args, err, ErrInvalidArgs)
+		}
+	}
+}
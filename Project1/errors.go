@@ -0,0 +1,105 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+
+	"github.com/rks0134/CSCE4600/Project1/scheduler"
+)
+
+// fileError attributes an error to the scheduling file it came from.
+type fileError struct {
+	path string
+	err  error
+}
+
+func (e *fileError) Error() string { return fmt.Sprintf("%s: %v", e.path, e.err) }
+
+func (e *fileError) Unwrap() error { return e.err }
+
+// jsonError marks an error to be reported on stderr as JSON (-errors json).
+type jsonError struct{ err error }
+
+func (e *jsonError) Error() string { return e.err.Error() }
+
+func (e *jsonError) Unwrap() error { return e.err }
+
+// errorReport is the -errors json form of an error. Position fields are
+// omitted when the error does not point into a scheduling file.
+type errorReport struct {
+	File    string `json:"file,omitempty"`
+	Line    int    `json:"line,omitempty"`
+	Column  int    `json:"column,omitempty"`
+	Message string `json:"message"`
+}
+
+// reportError writes err to w as a log line, or as one JSON object when it
+// was marked by -errors json.
+func reportError(w io.Writer, err error) {
+	var je *jsonError
+	if !errors.As(err, &je) {
+		log.New(w, "", log.LstdFlags).Print(err)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(newErrorReport(err))
+}
+
+func newErrorReport(err error) errorReport {
+	r := errorReport{Message: err.Error()}
+	var (
+		fe      *fileError
+		pathErr *fs.PathError
+	)
+	switch {
+	case errors.As(err, &fe):
+		r.File = fe.path
+		r.Message = fe.err.Error()
+	case errors.As(err, &pathErr):
+		r.File = pathErr.Path
+	}
+
+	var (
+		parseErr      *scheduler.ParseError
+		validationErr *scheduler.ValidationError
+	)
+	switch {
+	case errors.As(err, &parseErr):
+		r.Line, r.Column = parseErr.Line, parseErr.Column
+		r.Message = parseErr.Err.Error()
+	case errors.As(err, &validationErr):
+		if validationErr.Row > 0 && r.File != "" {
+			r.Line = recordLine(r.File, validationErr.Row)
+		}
+		r.Message = validationErr.Err.Error()
+	}
+
+	return r
+}
+
+// recordLine returns the line the 1-based CSV record row starts on in the
+// file at path, which differs from row when the file has blank lines. It
+// returns 0 if the record cannot be found.
+func recordLine(path string, row int) int {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0
+	}
+	defer func() { _ = f.Close() }()
+
+	r := csv.NewReader(f)
+	for i := 1; ; i++ {
+		if _, err := r.Read(); err != nil {
+			return 0
+		}
+		if i == row {
+			line, _ := r.FieldPos(0)
+			return line
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/rks0134/CSCE4600/Project1/scheduler"
+)
+
+func Test_newErrorReport(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		file := path.Join(dir, name)
+		if err := os.WriteFile(file, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return file
+	}
+	badInt := write("bad_int.csv", "1,5,0,2\n2,x,3,1\n")
+	badBurst := write("bad_burst.csv", "1,5,0,2\n\n2,0,3,1\n")
+
+	tests := []struct {
+		name string
+		args []string
+		want errorReport
+	}{
+		{
+			name: "parse",
+			args: []string{badInt},
+			want: errorReport{File: badInt, Line: 2, Column: 3, Message: `strconv.ParseInt: parsing "x": invalid syntax`},
+		},
+		{
+			name: "validation after blank line",
+			args: []string{badBurst},
+			want: errorReport{File: badBurst, Line: 3, Message: "burst duration 0 must be positive"},
+		},
+		{
+			name: "missing file",
+			args: []string{"missing.csv"},
+			want: errorReport{File: "missing.csv", Message: "open missing.csv: no such file or directory: error opening scheduling file"},
+		},
+		{
+			name: "usage",
+			args: nil,
+			want: errorReport{Message: "invalid args: must give a scheduling file to process"},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := run(context.Background(), io.Discard, append([]string{"binary_name", "-errors", "json", "compare"}, tt.args...)...)
+			var je *jsonError
+			if !errors.As(err, &je) {
+				t.Fatalf("run() error = %v, want a *jsonError", err)
+			}
+			if got := newErrorReport(err); got != tt.want {
+				t.Errorf("newErrorReport() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_reportError(t *testing.T) {
+	t.Parallel()
+	var w strings.Builder
+	reportError(&w, &jsonError{err: &fileError{path: "x.csv", err: &scheduler.ParseError{Line: 1, Column: 3, Err: errors.New("bad")}}})
+	if want := `{"file":"x.csv","line":1,"column":3,"message":"bad"}` + "\n"; w.String() != want {
+		t.Errorf("reportError() json = %q, want %q", w.String(), want)
+	}
+
+	w.Reset()
+	reportError(&w, ErrInvalidArgs)
+	if !strings.HasSuffix(w.String(), " invalid args\n") {
+		t.Errorf("reportError() text = %q, want a log line", w.String())
+	}
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/rks0134/CSCE4600/Project1/scheduler"
+)
+
+// generateCmd writes a random workload as a scheduling file.
+type generateCmd struct {
+	*globalFlags
+	count  string
+	binary bool
+}
+
+func (c *generateCmd) defineFlags(flags *flag.FlagSet) {
+	flags.StringVar(&c.count, "n", "1k", "number of processes to generate, with an optional k or M `suffix`")
+	flags.BoolVar(&c.binary, "binary", false, "write a binary workload instead of a scheduling file")
+}
+
+func (c *generateCmd) run(_ context.Context, w io.Writer, args []string) (err error) {
+	if len(args) > 1 {
+		return fmt.Errorf("%w: must give at most the file to write", ErrInvalidArgs)
+	}
+	sizes, err := parseSizes(c.count)
+	if err != nil {
+		return err
+	}
+	if len(sizes) != 1 {
+		return fmt.Errorf("%w: -n must be a single count", ErrInvalidArgs)
+	}
+	processes := scheduler.GenerateParallel(c.seed.value, sizes[0])
+
+	out := w
+	if len(args) == 1 {
+		f, err := os.Create(args[0])
+		if err != nil {
+			return fmt.Errorf("%w: error creating workload", err)
+		}
+		bw := bufio.NewWriterSize(f, 1<<20)
+		defer func() {
+			if flushErr := bw.Flush(); err == nil {
+				err = flushErr
+			}
+			if closeErr := f.Close(); err == nil {
+				err = closeErr
+			}
+			if err == nil {
+				_, _ = fmt.Fprintf(w, "Wrote %d processes to %s with seed %d\n", len(processes), args[0], c.seed.value)
+			}
+		}()
+		out = bw
+	}
+	if c.binary {
+		return scheduler.WriteBinary(out, processes, 1)
+	}
+
+	return writeWorkload(out, processes)
+}
+
+// writeWorkload writes processes to w as a scheduling file, one plain
+// record each, with a nice= field for those with a nice value.
+func writeWorkload(w io.Writer, processes []scheduler.Process) error {
+	var line []byte
+	for _, p := range processes {
+		line = strconv.AppendInt(line[:0], p.ProcessID, 10)
+		line = append(line, ',')
+		line = strconv.AppendInt(line, p.BurstDuration, 10)
+		line = append(line, ',')
+		line = strconv.AppendInt(line, p.ArrivalTime, 10)
+		line = append(line, ',')
+		line = strconv.AppendInt(line, p.Priority, 10)
+		if p.Nice != 0 {
+			line = append(line, ",nice="...)
+			line = strconv.AppendInt(line, p.Nice, 10)
+		}
+		line = append(line, '\n')
+		if _, err := w.Write(line); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
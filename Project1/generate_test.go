@@ -0,0 +1,54 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/rks0134/CSCE4600/Project1/scheduler"
+)
+
+func Test_runGenerate(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	csvPath := filepath.Join(dir, "generated.csv")
+	if err := run(context.Background(), io.Discard, "binary_name", "-seed", "4", "generate", "-n", "2k", csvPath); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.Open(csvPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	processes, err := scheduler.LoadProcesses(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := scheduler.GenerateParallel(4, 2000); !reflect.DeepEqual(processes, want) {
+		t.Errorf("generate wrote %d processes that differ from GenerateParallel()", len(processes))
+	}
+
+	binPath := filepath.Join(dir, "generated.wl")
+	if err := run(context.Background(), io.Discard, "binary_name", "-seed", "4", "generate", "-n", "2k", "-binary", binPath); err != nil {
+		t.Fatal(err)
+	}
+	var fromCSV, fromBinary strings.Builder
+	if err := run(context.Background(), &fromCSV, "binary_name", "-seed", "1", "compare", csvPath); err != nil {
+		t.Fatal(err)
+	}
+	if err := run(context.Background(), &fromBinary, "binary_name", "-seed", "1", "compare", binPath); err != nil {
+		t.Fatal(err)
+	}
+	if fromCSV.String() != fromBinary.String() {
+		t.Errorf("comparison of the binary workload\n%s\ndiffers from that of the scheduling file\n%s", fromBinary.String(), fromCSV.String())
+	}
+
+	if err := run(context.Background(), io.Discard, "binary_name", "generate", "-n", "1k,2k"); !errors.Is(err, ErrInvalidArgs) {
+		t.Errorf("generate -n 1k,2k error = %v, want %v", err, ErrInvalidArgs)
+	}
+}
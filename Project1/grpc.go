@@ -0,0 +1,536 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protowire"
+
+	"github.com/rks0134/CSCE4600/Project1/scheduler"
+)
+
+// The gRPC Simulator service of proto/simulator.proto is described here by
+// hand, as protoc-gen-go-grpc would describe it, and its messages are
+// encoded directly in the protobuf wire format, as the scheduler package
+// encodes workloads, so the module needs no generated code.
+
+// simulatorName is the full name of the Simulator service.
+const simulatorName = "csce4600.simulator.v1.Simulator"
+
+// simulatorService describes the Simulator service to a grpc.Server whose
+// handlers are those of a *serveCmd.
+var simulatorService = grpc.ServiceDesc{
+	ServiceName: simulatorName,
+	HandlerType: (*interface{})(nil),
+	Methods: []grpc.MethodDesc{
+		{MethodName: "Generate", Handler: generateHandler},
+		{MethodName: "Compare", Handler: compareHandler},
+	},
+	Streams: []grpc.StreamDesc{
+		{StreamName: "Schedule", Handler: scheduleHandler, ServerStreams: true},
+		{StreamName: "Watch", Handler: watchHandler, ServerStreams: true},
+	},
+	Metadata: "proto/simulator.proto",
+}
+
+// grpcServer returns a gRPC server running the Simulator service, which
+// takes workloads as large as the HTTP API does.
+func (c *serveCmd) grpcServer() *grpc.Server {
+	s := grpc.NewServer(grpc.ForceServerCodec(rpcCodec{}), grpc.MaxRecvMsgSize(maxWorkload))
+	s.RegisterService(&simulatorService, c)
+
+	return s
+}
+
+// The server has no interceptors, so the handlers call the service's
+// methods directly.
+
+func generateHandler(srv interface{}, _ context.Context, dec func(interface{}) error, _ grpc.UnaryServerInterceptor) (interface{}, error) {
+	req := &generateRPC{}
+	if err := decodeRequest(dec, req); err != nil {
+		return nil, err
+	}
+	return srv.(*serveCmd).generate(req)
+}
+
+func compareHandler(srv interface{}, ctx context.Context, dec func(interface{}) error, _ grpc.UnaryServerInterceptor) (interface{}, error) {
+	req := &compareRPC{}
+	if err := decodeRequest(dec, req); err != nil {
+		return nil, err
+	}
+	return srv.(*serveCmd).compare(ctx, req)
+}
+
+func scheduleHandler(srv interface{}, stream grpc.ServerStream) error {
+	req := &scheduleRPC{}
+	if err := decodeRequest(stream.RecvMsg, req); err != nil {
+		return err
+	}
+	return srv.(*serveCmd).schedule(req, stream, false)
+}
+
+func watchHandler(srv interface{}, stream grpc.ServerStream) error {
+	req := &scheduleRPC{}
+	if err := decodeRequest(stream.RecvMsg, req); err != nil {
+		return err
+	}
+	return srv.(*serveCmd).schedule(req, stream, true)
+}
+
+// decodeRequest receives a request with recv and decodes it into req,
+// failing with InvalidArgument if it is malformed or asks for too much.
+func decodeRequest(recv func(interface{}) error, req interface{ unmarshal([]byte) error }) error {
+	var msg rpcMessage
+	if err := recv(&msg); err != nil {
+		return err
+	}
+	if err := req.unmarshal(msg); err != nil {
+		if !errors.Is(err, ErrInvalidArgs) && !errors.Is(err, scheduler.ErrParse) {
+			err = &scheduler.ParseError{Err: err}
+		}
+		return rpcError(err)
+	}
+	return nil
+}
+
+// generate draws a random workload as the generate command does.
+func (c *serveCmd) generate(req *generateRPC) (rpcMessage, error) {
+	if req.count < 1 || req.count > maxServeProcesses {
+		return nil, rpcError(fmt.Errorf("%w: count must be from 1 to %d", ErrInvalidArgs, maxServeProcesses))
+	}
+	var resp rpcMessage
+	for _, p := range scheduler.GenerateParallel(req.seed, int(req.count)) {
+		resp = appendMessage(resp, 1, scheduler.MarshalProtoProcess(p))
+	}
+
+	return resp, nil
+}
+
+// schedule runs the requested algorithm over the request's workload,
+// sending each slice of its Gantt chart once it is final, with watch the
+// running metrics each time a process terminates, and then the result, as
+// ScheduleEvents or, with watch, WatchEvents. The client going away
+// cancels the simulation.
+func (c *serveCmd) schedule(req *scheduleRPC, stream grpc.ServerStream, watch bool) error {
+	alg, ok := findAlgorithm(req.algorithm)
+	if !ok {
+		return rpcError(fmt.Errorf("%w: unknown algorithm %q (known: %s)", ErrInvalidArgs, req.algorithm, strings.Join(scheduler.Names(), ", ")))
+	}
+	processes, err := checkWorkload(req.processes)
+	if err != nil {
+		return rpcError(err)
+	}
+	ctx, cancel := context.WithCancel(stream.Context())
+	defer cancel()
+	var sendErr error
+	send := func(field protowire.Number, msg []byte) {
+		if sendErr == nil {
+			if sendErr = stream.SendMsg(rpcMessage(appendMessage(nil, field, msg))); sendErr != nil {
+				cancel()
+			}
+		}
+	}
+	extra := []scheduler.Option{scheduler.WithSlices(func(ts scheduler.TimeSlice) {
+		send(1, scheduler.MarshalProtoSlice(ts))
+	})}
+	result := protowire.Number(2)
+	if watch {
+		extra = append(extra, scheduler.WithUpdates(func(u scheduler.MetricsUpdate) {
+			send(2, scheduler.MarshalProtoUpdate(u))
+		}))
+		result = 3
+	}
+	opts, err := req.options.options(extra...)
+	if err != nil {
+		return rpcError(err)
+	}
+	r, err := alg.new(opts...).Schedule(ctx, processes)
+	switch {
+	case sendErr != nil:
+		return sendErr
+	case err != nil:
+		return rpcError(err)
+	}
+	defer r.Release()
+	send(result, scheduler.MarshalProtoResult(r))
+
+	return sendErr
+}
+
+// compare runs the requested algorithms over the request's workload and
+// ranks them as the compare command does, on their metrics in ticks.
+func (c *serveCmd) compare(ctx context.Context, req *compareRPC) (rpcMessage, error) {
+	algs := algorithms()
+	if len(req.algorithms) > 0 {
+		var err error
+		if algs, err = selectAlgorithms(strings.Join(req.algorithms, ",")); err != nil {
+			return nil, rpcError(err)
+		}
+	}
+	// Metrics left out get no weight, unless all are.
+	weights := make([]float64, len(compareMetrics))
+	for i := range weights {
+		if len(req.weights) == 0 {
+			weights[i] = 1
+		}
+	}
+	for name, weight := range req.weights {
+		i := metricIndex(name)
+		if i < 0 {
+			return nil, rpcError(fmt.Errorf("%w: unknown metric %q", ErrInvalidArgs, name))
+		}
+		if weight < 0 || math.IsNaN(weight) {
+			return nil, rpcError(fmt.Errorf("%w: invalid weight %v for %s", ErrInvalidArgs, weight, name))
+		}
+		weights[i] = weight
+	}
+	processes, err := checkWorkload(req.processes)
+	if err != nil {
+		return nil, rpcError(err)
+	}
+	opts, err := req.options.options()
+	if err != nil {
+		return nil, rpcError(err)
+	}
+
+	if len(req.algorithms) == 0 {
+		algs = forWorkload(algs, "all", processes)
+	}
+	jobs := make([]job, 0, len(algs))
+	for _, alg := range algs {
+		jobs = append(jobs, job{alg: alg, opts: opts})
+	}
+	scheduleAll(ctx, c.resultCache(), jobs, processes)
+	standings := make([]standing, 0, len(jobs))
+	for _, j := range jobs {
+		if j.err != nil {
+			return nil, rpcError(fmt.Errorf("%s: %w", j.alg.name, j.err))
+		}
+		standings = append(standings, standing{alg: j.alg, metrics: j.r.Metrics, total: j.r.Total, violations: len(j.r.Violations)})
+		j.r.Release()
+	}
+	rank(standings, weights)
+
+	var resp rpcMessage
+	for _, s := range standings {
+		var m []byte
+		m = protowire.AppendTag(m, 1, protowire.BytesType)
+		m = protowire.AppendString(m, s.alg.name)
+		m = appendMessage(m, 2, scheduler.MarshalProtoMetrics(s.metrics))
+		var ranks []byte
+		for _, r := range s.ranks {
+			ranks = protowire.AppendVarint(ranks, uint64(r))
+		}
+		m = appendMessage(m, 3, ranks)
+		if s.score != 0 {
+			m = protowire.AppendTag(m, 4, protowire.Fixed64Type)
+			m = protowire.AppendFixed64(m, math.Float64bits(s.score))
+		}
+		if s.violations != 0 {
+			m = protowire.AppendTag(m, 5, protowire.VarintType)
+			m = protowire.AppendVarint(m, uint64(s.violations))
+		}
+		resp = appendMessage(resp, 1, m)
+	}
+	resp = protowire.AppendTag(resp, 2, protowire.BytesType)
+
+	return protowire.AppendString(resp, summarize(standings)), nil
+}
+
+// rpcError is the gRPC status of a failed request, with the code matching
+// the HTTP status errorStatus gives it.
+func rpcError(err error) error {
+	switch {
+	case errors.Is(err, ErrInvalidArgs), errors.Is(err, scheduler.ErrParse), errors.Is(err, scheduler.ErrValidation):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, err.Error())
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	}
+
+	return status.Error(codes.Internal, err.Error())
+}
+
+// rpcCodec marshals the service's messages, which encode and decode
+// themselves.
+type rpcCodec struct{}
+
+func (rpcCodec) Name() string { return "proto" }
+
+func (rpcCodec) Marshal(v interface{}) ([]byte, error) {
+	switch m := v.(type) {
+	case rpcMessage:
+		return m, nil
+	case *rpcMessage:
+		return *m, nil
+	}
+	return nil, fmt.Errorf("cannot marshal %T as a protobuf message", v)
+}
+
+func (rpcCodec) Unmarshal(data []byte, v interface{}) error {
+	m, ok := v.(*rpcMessage)
+	if !ok {
+		return fmt.Errorf("cannot unmarshal a protobuf message into %T", v)
+	}
+	*m = append((*m)[:0], data...)
+	return nil
+}
+
+// rpcMessage is an encoded protobuf message. The service receives its
+// requests as they are, decoding them itself, and sends its responses so.
+type rpcMessage []byte
+
+// appendMessage appends the length-delimited field, even when empty.
+func appendMessage(b []byte, field protowire.Number, msg []byte) []byte {
+	b = protowire.AppendTag(b, field, protowire.BytesType)
+	return protowire.AppendBytes(b, msg)
+}
+
+// rangeFields calls fn with each field of the protobuf message in data: a
+// varint or fixed value in v, or the contents of a length-delimited field
+// in b.
+func rangeFields(data []byte, fn func(field protowire.Number, wire protowire.Type, v uint64, b []byte) error) error {
+	for len(data) > 0 {
+		field, wire, n := protowire.ConsumeTag(data)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		data = data[n:]
+		var v uint64
+		var b []byte
+		switch wire {
+		case protowire.VarintType:
+			v, n = protowire.ConsumeVarint(data)
+		case protowire.Fixed64Type:
+			v, n = protowire.ConsumeFixed64(data)
+		case protowire.Fixed32Type:
+			var v32 uint32
+			v32, n = protowire.ConsumeFixed32(data)
+			v = uint64(v32)
+		case protowire.BytesType:
+			b, n = protowire.ConsumeBytes(data)
+		default:
+			n = protowire.ConsumeFieldValue(field, wire, data)
+		}
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		data = data[n:]
+		if err := fn(field, wire, v, b); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// generateRPC is a GenerateRequest.
+type generateRPC struct {
+	seed, count int64
+}
+
+func (req *generateRPC) unmarshal(data []byte) error {
+	return rangeFields(data, func(field protowire.Number, wire protowire.Type, v uint64, _ []byte) error {
+		switch {
+		case field == 1 && wire == protowire.VarintType:
+			req.seed = int64(v)
+		case field == 2 && wire == protowire.VarintType:
+			req.count = int64(v)
+		}
+		return nil
+	})
+}
+
+// scheduleRPC is a ScheduleRequest.
+type scheduleRPC struct {
+	algorithm string
+	processes []scheduler.Process
+	options   rpcOptions
+}
+
+func (req *scheduleRPC) unmarshal(data []byte) error {
+	return rangeFields(data, func(field protowire.Number, wire protowire.Type, _ uint64, b []byte) error {
+		if wire != protowire.BytesType {
+			return nil
+		}
+		switch field {
+		case 1:
+			req.algorithm = string(b)
+		case 2:
+			return appendProcess(&req.processes, b)
+		case 3:
+			return req.options.unmarshal(b)
+		}
+		return nil
+	})
+}
+
+// compareRPC is a CompareRequest.
+type compareRPC struct {
+	algorithms []string
+	processes  []scheduler.Process
+	options    rpcOptions
+	weights    map[string]float64
+}
+
+func (req *compareRPC) unmarshal(data []byte) error {
+	return rangeFields(data, func(field protowire.Number, wire protowire.Type, _ uint64, b []byte) error {
+		if wire != protowire.BytesType {
+			return nil
+		}
+		switch field {
+		case 1:
+			req.algorithms = append(req.algorithms, string(b))
+		case 2:
+			return appendProcess(&req.processes, b)
+		case 3:
+			return req.options.unmarshal(b)
+		case 4:
+			var name string
+			var weight float64
+			err := rangeFields(b, func(field protowire.Number, wire protowire.Type, v uint64, b []byte) error {
+				switch {
+				case field == 1 && wire == protowire.BytesType:
+					name = string(b)
+				case field == 2 && wire == protowire.Fixed64Type:
+					weight = math.Float64frombits(v)
+				}
+				return nil
+			})
+			if req.weights == nil {
+				req.weights = make(map[string]float64)
+			}
+			req.weights[name] = weight
+			return err
+		}
+		return nil
+	})
+}
+
+// appendProcess decodes the Process in data onto processes, failing once
+// they are more than a request may simulate.
+func appendProcess(processes *[]scheduler.Process, data []byte) error {
+	if len(*processes) == maxServeProcesses {
+		return fmt.Errorf("%w: workload has more than the %d processes a request may simulate", ErrInvalidArgs, maxServeProcesses)
+	}
+	p, err := scheduler.UnmarshalProtoProcess(data)
+	if err != nil {
+		return fmt.Errorf("process %d: %w", len(*processes)+1, err)
+	}
+	*processes = append(*processes, p)
+
+	return nil
+}
+
+// rpcOptions is an Options message, whose times are already in ticks.
+type rpcOptions struct {
+	quantum, switchCost, dispatchLatency, migrationCost int64
+	cpus                                                int32
+	speeds                                              []float64
+	warmUp, coolDown, maxTime, memory                   int64
+	aging                                               float64
+	inherit                                             bool
+	tieBreak                                            string
+	seed, ticks                                         int64
+	metricsOnly                                         bool
+}
+
+func (o *rpcOptions) unmarshal(data []byte) error {
+	ints := map[protowire.Number]*int64{
+		1: &o.quantum, 2: &o.switchCost, 3: &o.dispatchLatency, 4: &o.migrationCost,
+		7: &o.warmUp, 8: &o.coolDown, 9: &o.maxTime, 10: &o.memory, 14: &o.seed, 15: &o.ticks,
+	}
+	return rangeFields(data, func(field protowire.Number, wire protowire.Type, v uint64, b []byte) error {
+		if dst, ok := ints[field]; ok && wire == protowire.VarintType {
+			*dst = int64(v)
+			return nil
+		}
+		switch {
+		case field == 5 && wire == protowire.VarintType:
+			o.cpus = int32(v)
+		case field == 6 && wire == protowire.Fixed64Type:
+			o.speeds = append(o.speeds, math.Float64frombits(v))
+		case field == 6 && wire == protowire.BytesType:
+			for len(b) > 0 {
+				bits, n := protowire.ConsumeFixed64(b)
+				if n < 0 {
+					return protowire.ParseError(n)
+				}
+				o.speeds, b = append(o.speeds, math.Float64frombits(bits)), b[n:]
+			}
+		case field == 11 && wire == protowire.Fixed64Type:
+			o.aging = math.Float64frombits(v)
+		case field == 12 && wire == protowire.VarintType:
+			o.inherit = v != 0
+		case field == 13 && wire == protowire.BytesType:
+			o.tieBreak = string(b)
+		case field == 16 && wire == protowire.VarintType:
+			o.metricsOnly = v != 0
+		}
+		return nil
+	})
+}
+
+// options returns the scheduler options o asks for, followed by extra.
+// Fields left unset take the defaults the HTTP API's parameters do; the
+// scheduler rejects those set to invalid values.
+func (o *rpcOptions) options(extra ...scheduler.Option) ([]scheduler.Option, error) {
+	switch {
+	case o.cpus > maxServeCPUs:
+		return nil, fmt.Errorf("%w: cpus must be at most %d", ErrInvalidArgs, maxServeCPUs)
+	case len(o.speeds) > maxServeCPUs:
+		return nil, fmt.Errorf("%w: speeds must give at most %d speeds", ErrInvalidArgs, maxServeCPUs)
+	case o.maxTime > maxServeTime:
+		return nil, fmt.Errorf("%w: max_time must be at most %d", ErrInvalidArgs, maxServeTime)
+	}
+	tb := scheduler.DefaultOptions().TieBreak
+	if o.tieBreak != "" {
+		var err error
+		if tb, err = scheduler.ParseTieBreak(o.tieBreak); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidArgs, err)
+		}
+	}
+	ticks := o.ticks
+	if ticks == 0 {
+		ticks = 1
+	}
+	quantum := o.quantum
+	if quantum == 0 {
+		quantum = scheduler.DefaultOptions().Quantum * ticks
+	}
+	opts := []scheduler.Option{
+		scheduler.WithQuantum(quantum),
+		scheduler.WithSwitchCost(o.switchCost),
+		scheduler.WithDispatchLatency(o.dispatchLatency),
+		scheduler.WithMigrationCost(o.migrationCost),
+		scheduler.WithWarmUp(o.warmUp),
+		scheduler.WithCoolDown(o.coolDown),
+		scheduler.WithMaxTime(o.maxTime),
+		scheduler.WithMemory(o.memory),
+		scheduler.WithTieBreak(tb),
+		scheduler.WithSpeeds(o.speeds...),
+		scheduler.WithAging(o.aging),
+		scheduler.WithTicksPerUnit(ticks),
+	}
+	if o.cpus != 0 {
+		opts = append(opts, scheduler.WithCPUs(int(o.cpus)))
+	}
+	if o.inherit {
+		opts = append(opts, scheduler.WithPriorityInheritance())
+	}
+	if o.seed != 0 {
+		opts = append(opts, scheduler.WithSeed(o.seed))
+	}
+	if o.metricsOnly {
+		opts = append(opts, scheduler.WithMetricsOnly())
+	}
+
+	return append(opts, extra...), nil
+}
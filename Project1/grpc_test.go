@@ -0,0 +1,238 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"math"
+	"net"
+	"os"
+	"reflect"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protowire"
+
+	"github.com/rks0134/CSCE4600/Project1/scheduler"
+)
+
+func Test_serveGRPC(t *testing.T) {
+	t.Parallel()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	gs := (&serveCmd{globalFlags: &globalFlags{}}).grpcServer()
+	go func() { _ = gs.Serve(l) }()
+	defer gs.Stop()
+	conn, err := grpc.Dial(l.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()), grpc.WithDefaultCallOptions(grpc.ForceCodec(rpcCodec{})))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	ctx := context.Background()
+
+	csv, err := os.ReadFile("example_processes.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	processes, err := scheduler.LoadProcesses(bytes.NewReader(csv))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var workload []byte
+	for _, p := range processes {
+		workload = appendMessage(workload, 2, scheduler.MarshalProtoProcess(p))
+	}
+	// options encodes an Options message of varint fields.
+	options := func(fields ...uint64) []byte {
+		var o []byte
+		for k := 0; k < len(fields); k += 2 {
+			o = protowire.AppendTag(o, protowire.Number(fields[k]), protowire.VarintType)
+			o = protowire.AppendVarint(o, fields[k+1])
+		}
+		return appendMessage(nil, 3, o)
+	}
+	scheduleRequest := func(alg string, opts []byte) rpcMessage {
+		req := protowire.AppendTag(nil, 1, protowire.BytesType)
+		req = protowire.AppendString(req, alg)
+		return append(append(req, workload...), opts...)
+	}
+	// schedule calls method with req, returning the slices and result it
+	// streams, and the running metrics for Watch.
+	schedule := func(method string, req rpcMessage) ([]scheduler.TimeSlice, []scheduler.MetricsUpdate, *scheduler.ScheduleResult, error) {
+		t.Helper()
+		stream, err := conn.NewStream(ctx, &grpc.StreamDesc{ServerStreams: true}, "/"+simulatorName+"/"+method)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := stream.SendMsg(req); err != nil {
+			t.Fatal(err)
+		}
+		if err := stream.CloseSend(); err != nil {
+			t.Fatal(err)
+		}
+		var gantt []scheduler.TimeSlice
+		var updates []scheduler.MetricsUpdate
+		var r *scheduler.ScheduleResult
+		result := protowire.Number(2)
+		if method == "Watch" {
+			result = 3
+		}
+		for {
+			var m rpcMessage
+			if err := stream.RecvMsg(&m); err == io.EOF {
+				return gantt, updates, r, nil
+			} else if err != nil {
+				return nil, nil, nil, err
+			}
+			if r != nil {
+				t.Fatalf("%s streamed an event after the result", method)
+			}
+			err := rangeFields(m, func(field protowire.Number, _ protowire.Type, _ uint64, b []byte) error {
+				var err error
+				switch {
+				case field == 1:
+					var ts scheduler.TimeSlice
+					ts, err = scheduler.UnmarshalProtoSlice(b)
+					gantt = append(gantt, ts)
+				case field == result:
+					r, _, err = scheduler.UnmarshalProtoResult(b)
+				case field == 2:
+					var u scheduler.MetricsUpdate
+					u, err = scheduler.UnmarshalProtoUpdate(b)
+					updates = append(updates, u)
+				}
+				return err
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	var wantUpdates []scheduler.MetricsUpdate
+	want, err := scheduler.NewRR(scheduler.WithQuantum(3), scheduler.WithCPUs(2), scheduler.WithSeed(1), scheduler.WithUpdates(func(u scheduler.MetricsUpdate) {
+		wantUpdates = append(wantUpdates, u)
+	})).Schedule(ctx, processes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantGantt := want.Gantt
+	want.Gantt = nil
+	if want, _, err = scheduler.UnmarshalProtoResult(scheduler.MarshalProtoResult(want)); err != nil {
+		t.Fatal(err)
+	}
+	for _, method := range []string{"Schedule", "Watch"} {
+		gantt, updates, got, err := schedule(method, scheduleRequest("rr", options(1, 3, 5, 2, 14, 1)))
+		if err != nil {
+			t.Fatalf("%s() error = %v", method, err)
+		}
+		if !reflect.DeepEqual(gantt, wantGantt) {
+			t.Errorf("%s() streamed %+v, want %+v", method, gantt, wantGantt)
+		}
+		if method == "Watch" && (len(updates) != len(processes) || !reflect.DeepEqual(updates, wantUpdates)) || method == "Schedule" && updates != nil {
+			t.Errorf("%s() streamed updates %+v, want %+v", method, updates, wantUpdates)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s() result = %+v, want %+v", method, got, want)
+		}
+	}
+	for _, tt := range []struct {
+		name string
+		req  rpcMessage
+	}{
+		{name: "unknown algorithm", req: scheduleRequest("lottery", nil)},
+		{name: "no CPUs", req: scheduleRequest("fcfs", options(5, uint64(1<<64-1)))},
+		{name: "too many CPUs", req: scheduleRequest("fcfs", options(5, maxServeCPUs+1))},
+		{name: "late max time", req: scheduleRequest("fcfs", options(9, maxServeTime+1))},
+		{name: "malformed", req: rpcMessage{0x12, 0x05}},
+	} {
+		if _, _, _, err := schedule("Watch", tt.req); status.Code(err) != codes.InvalidArgument {
+			t.Errorf("Watch() with %s error = %v, want %v", tt.name, err, codes.InvalidArgument)
+		}
+	}
+
+	generate := func(seed, count uint64) (rpcMessage, error) {
+		req := protowire.AppendTag(nil, 1, protowire.VarintType)
+		req = protowire.AppendVarint(req, seed)
+		req = protowire.AppendTag(req, 2, protowire.VarintType)
+		req = protowire.AppendVarint(req, count)
+		var resp rpcMessage
+		err := conn.Invoke(ctx, "/"+simulatorName+"/Generate", rpcMessage(req), &resp)
+		return resp, err
+	}
+	resp, err := generate(7, 50)
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	var generated []scheduler.Process
+	err = rangeFields(resp, func(field protowire.Number, _ protowire.Type, _ uint64, b []byte) error {
+		p, err := scheduler.UnmarshalProtoProcess(b)
+		generated = append(generated, p)
+		return err
+	})
+	if err != nil || !reflect.DeepEqual(generated, scheduler.GenerateParallel(7, 50)) {
+		t.Errorf("Generate() = %+v, %v, want the generate command's workload", generated, err)
+	}
+	if _, err := generate(7, maxServeProcesses+1); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Generate() of too many processes error = %v, want %v", err, codes.InvalidArgument)
+	}
+
+	compare := func(weight string) (rpcMessage, error) {
+		var req []byte
+		for _, alg := range []string{"fcfs", "sjf", "rr"} {
+			req = protowire.AppendTag(req, 1, protowire.BytesType)
+			req = protowire.AppendString(req, alg)
+		}
+		req = append(req, workload...)
+		if weight != "" {
+			entry := protowire.AppendTag(nil, 1, protowire.BytesType)
+			entry = protowire.AppendString(entry, weight)
+			entry = protowire.AppendTag(entry, 2, protowire.Fixed64Type)
+			entry = protowire.AppendFixed64(entry, math.Float64bits(1))
+			req = appendMessage(req, 4, entry)
+		}
+		var resp rpcMessage
+		err := conn.Invoke(ctx, "/"+simulatorName+"/Compare", rpcMessage(req), &resp)
+		return resp, err
+	}
+	if resp, err = compare("wait"); err != nil {
+		t.Fatalf("Compare() error = %v", err)
+	}
+	var standings []standing
+	for _, name := range []string{"fcfs", "sjf", "rr"} {
+		alg, _ := findAlgorithm(name)
+		r, err := alg.new().Schedule(ctx, append([]scheduler.Process(nil), processes...))
+		if err != nil {
+			t.Fatal(err)
+		}
+		standings = append(standings, standing{alg: alg, metrics: r.Metrics, total: r.Total})
+	}
+	rank(standings, []float64{1, 0, 0})
+	var names []string
+	var summary string
+	err = rangeFields(resp, func(field protowire.Number, _ protowire.Type, _ uint64, b []byte) error {
+		switch field {
+		case 1:
+			return rangeFields(b, func(field protowire.Number, _ protowire.Type, _ uint64, b []byte) error {
+				if field == 1 {
+					names = append(names, string(b))
+				}
+				return nil
+			})
+		case 2:
+			summary = string(b)
+		}
+		return nil
+	})
+	if err != nil || len(names) != 3 || names[0] != standings[0].alg.name || summary != summarize(standings) {
+		t.Errorf("Compare() = %v, %q, %v, want the standings and summary of the compare command", names, summary, err)
+	}
+	if _, err := compare("latency"); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Compare() weighting an unknown metric error = %v, want %v", err, codes.InvalidArgument)
+	}
+}
@@ -1,444 +1,474 @@
 package main
 
 import (
-	"encoding/csv"
+	"bufio"
+	"context"
 	"errors"
+	"flag"
 	"fmt"
 	"io"
+	"io/fs"
 	"log"
 	"os"
-	"strconv"
+	"os/signal"
+	"path/filepath"
 	"strings"
+	"syscall"
 
-	"github.com/olekukonko/tablewriter"
+	"github.com/rks0134/CSCE4600/Project1/scheduler"
 )
 
-func main() {
-	// CLI args
-	f, closeFile, err := openProcessingFile(os.Args...)
-	if err != nil {
-		log.Fatal(err)
-	}
-	defer closeFile()
-
-	// Load and parse processes
-	processes, err := loadProcesses(f)
-	if err != nil {
-		log.Fatal(err)
-	}
-
-	// First-come, first-serve scheduling
-	FCFSSchedule(os.Stdout, "First-come, first-serve", processes)
+// Exit codes returned by the program, so scripts and graders can branch on
+// the failure category without parsing stderr.
+const (
+	ExitOK           = 0   // success
+	ExitInternal     = 1   // unexpected internal error
+	ExitInvalidArgs  = 2   // bad command line usage
+	ExitFileNotFound = 3   // scheduling file does not exist
+	ExitParse        = 4   // scheduling file could not be parsed
+	ExitValidation   = 5   // processes failed validation
+	ExitRegression   = 6   // bench results regressed from the baseline
+	ExitDifferent    = 7   // diff found the results differ
+	ExitCancelled    = 130 // interrupted (SIGINT), partial results reported
+)
 
-	SJFSchedule(os.Stdout, "Shortest-job-first", processes)
-	//
-	SJFPrioritySchedule(os.Stdout, "Priority", processes)
-	//
-	RRSchedule(os.Stdout, "Round-robin", processes)
+// exitCodes documents the Exit* codes for the help text and man page.
+var exitCodes = []struct {
+	code    int
+	meaning string
+}{
+	{ExitOK, "success"},
+	{ExitInternal, "internal error"},
+	{ExitInvalidArgs, "invalid arguments"},
+	{ExitFileNotFound, "scheduling file not found"},
+	{ExitParse, "scheduling file could not be parsed"},
+	{ExitValidation, "processes failed validation"},
+	{ExitRegression, "bench results regressed from the -baseline by more than -threshold"},
+	{ExitDifferent, "diff found the results differ"},
+	{ExitCancelled, "interrupted; the schedule completed so far is still printed"},
 }
 
-func openProcessingFile(args ...string) (*os.File, func(), error) {
-	if len(args) != 2 {
-		return nil, nil, fmt.Errorf("%w: must give a scheduling file to process", ErrInvalidArgs)
-	}
-	// Read in CSV process CSV file
-	f, err := os.Open(args[1])
+var (
+	ErrInvalidArgs = errors.New("invalid args")
+	ErrRegression  = errors.New("performance regression")
+)
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	err := run(ctx, os.Stdout, os.Args...)
+	stop()
 	if err != nil {
-		return nil, nil, fmt.Errorf("%v: error opening scheduling file", err)
+		reportError(os.Stderr, err)
+		os.Exit(exitCode(err))
 	}
-	closeFn := func() {
-		if err := f.Close(); err != nil {
-			log.Fatalf("%v: error closing scheduling file", err)
+}
+
+// run runs the command named by args, writing its output to w through a
+// buffer that commands flush as each section of a report is complete, and
+// once more when they return.
+func run(ctx context.Context, w io.Writer, args ...string) (err error) {
+	bw := bufio.NewWriter(w)
+	defer func() {
+		if flushErr := bw.Flush(); err == nil {
+			err = flushErr
 		}
-	}
+	}()
 
-	return f, closeFn, nil
+	return dispatch(ctx, bw, args[1:])
 }
 
-type (
-	Process struct {
-		ProcessID     int64
-		ArrivalTime   int64
-		BurstDuration int64
-		Priority      int64
+// flush writes out what w has buffered, if it buffers, so a report appears
+// section by section rather than all at once when the command returns.
+func flush(w io.Writer) error {
+	if f, ok := w.(interface{ Flush() error }); ok {
+		return f.Flush()
 	}
-	TimeSlice struct {
-		PID   int64
-		Start int64
-		Stop  int64
-	}
-)
-
-//region Schedulers
-
-// FCFSSchedule outputs a schedule of processes in a GANTT chart and a table of timing given:
-// • an output writer
-// • a title for the chart
-// • a slice of processes
-func FCFSSchedule(w io.Writer, title string, processes []Process) {
-	var (
-		serviceTime     int64
-		totalWait       float64
-		totalTurnaround float64
-		lastCompletion  float64
-		waitingTime     int64
-		schedule        = make([][]string, len(processes))
-		gantt           = make([]TimeSlice, 0)
-	)
-	for i := range processes {
-		if processes[i].ArrivalTime > 0 {
-			waitingTime = serviceTime - processes[i].ArrivalTime
-		}
-		totalWait += float64(waitingTime)
-
-		start := waitingTime + processes[i].ArrivalTime
+	return nil
+}
 
-		turnaround := processes[i].BurstDuration + waitingTime
-		totalTurnaround += float64(turnaround)
+// scheduleCmd runs every algorithm over the processes in a scheduling file.
+type scheduleCmd struct {
+	*globalFlags
+	simulationFlags
+	reportFlags
+	dbFlags
+	// checkpointAt and checkpoint pause every simulation and save them.
+	checkpointAt int64
+	checkpoint   string
+	// stream writes each Gantt chart as it is simulated.
+	stream bool
+}
 
-		completion := processes[i].BurstDuration + processes[i].ArrivalTime + waitingTime
-		lastCompletion = float64(completion)
+// reportFlags choose how schedules are reported.
+type reportFlags struct {
+	format  string
+	states  bool
+	verbose bool
+	// quiet reports only each schedule's metrics, which are then all
+	// that is computed.
+	quiet bool
+	// parquet collects the schedules of the parquet formats, which are
+	// written together as one file.
+	parquet *scheduler.ParquetWriter
+}
 
-		schedule[i] = []string{
-			fmt.Sprint(processes[i].ProcessID),
-			fmt.Sprint(processes[i].Priority),
-			fmt.Sprint(processes[i].BurstDuration),
-			fmt.Sprint(processes[i].ArrivalTime),
-			fmt.Sprint(waitingTime),
-			fmt.Sprint(turnaround),
-			fmt.Sprint(completion),
-		}
-		serviceTime += processes[i].BurstDuration
+func (c *reportFlags) define(flags *flag.FlagSet) {
+	flags.StringVar(&c.format, "format", "text", "output `format`: text, json (one object per line), html, trace (a letter per process per tick), grafana (a JSON datasource query response per line), csv (a row per process per schedule), proto (length-prefixed protobuf Report messages), msgpack (a MessagePack map per schedule), or parquet or parquet-slices (a Parquet file of a row per process, or per Gantt slice, per schedule)")
+	flags.BoolVar(&c.states, "states", false, "also print how long each process spent ready, running, and blocked (text format)")
+	flags.BoolVar(&c.verbose, "verbose", false, "also print per-process details such as nice values and time slices, and an event log (text format)")
+	flags.BoolVar(&c.quiet, "quiet", false, "print only the metrics of each schedule, skipping the analysis nothing else would show (text or json format)")
+}
 
-		gantt = append(gantt, TimeSlice{
-			PID:   processes[i].ProcessID,
-			Start: start,
-			Stop:  serviceTime,
-		})
+// renderer returns the renderer the -format and -quiet flags ask for.
+func (c *reportFlags) renderer() (func(io.Writer, string, *scheduler.ScheduleResult) error, error) {
+	render, ok := renderers[c.format]
+	if _, table := parquetTables[c.format]; table {
+		render, ok = func(_ io.Writer, title string, r *scheduler.ScheduleResult) error { return c.parquet.Add(title, r) }, true
+	}
+	switch {
+	case !ok:
+		return nil, fmt.Errorf("%w: unknown format %q", ErrInvalidArgs, c.format)
+	case c.quiet && c.format != "text" && c.format != "json":
+		return nil, fmt.Errorf("%w: -quiet needs the text or json format", ErrInvalidArgs)
+	case c.quiet && (c.states || c.verbose):
+		return nil, fmt.Errorf("%w: -quiet cannot be combined with -states or -verbose", ErrInvalidArgs)
+	case c.quiet && c.format == "text":
+		return scheduler.RenderMetrics, nil
 	}
 
-	count := float64(len(processes))
-	aveWait := totalWait / count
-	aveTurnaround := totalTurnaround / count
-	aveThroughput := count / lastCompletion
+	return render, nil
+}
 
-	outputTitle(w, title)
-	outputGantt(w, gantt)
-	outputSchedule(w, schedule, aveWait, aveTurnaround, aveThroughput)
+func (c *scheduleCmd) defineFlags(flags *flag.FlagSet) {
+	c.reportFlags.define(flags)
+	flags.Int64Var(&c.checkpointAt, "checkpoint-at", 0, "pause every simulation at `time`, saving it to -checkpoint to continue with the resume command")
+	flags.StringVar(&c.checkpoint, "checkpoint", "", "`file` to save the -checkpoint-at checkpoints to")
+	flags.BoolVar(&c.stream, "stream", false, "write each Gantt chart as it is simulated, ahead of the rest of its report, rather than keeping it in memory (text format)")
+	c.defineJitter(flags, "shift each arrival by `n` time units drawn from -jitter-dist before scheduling, for robustness experiments and workload variants (reproducible with -seed)")
+	c.dbFlags.define(flags)
+	c.simulationFlags.define(flags)
 }
 
-// func SJFPrioritySchedule(w io.Writer, title string, processes []Process) { }
-//
-// SJFPrioritySchedule implements Shortest Job First Priority (SJF Priority) scheduling algorithm.
-func SJFPrioritySchedule(w io.Writer, title string, processes []Process) {
-	var (
-		serviceTime     int64
-		totalWait       float64
-		totalTurnaround float64
-		lastCompletion  float64
-		waitingTime     int64
-		schedule        = make([][]string, len(processes))
-		gantt           = make([]TimeSlice, 0)
-	)
-
-	remainingBurst := make([]int64, len(processes))
-	copy(remainingBurst, getBurstDurations(processes))
-
-	completion := int64(0)
-	for completion < int64(len(processes)) {
-		nextProcess := -1
-
-		for i, p := range processes {
-			if remainingBurst[i] > 0 && p.ArrivalTime <= completion {
-				if nextProcess == -1 || remainingBurst[i] < remainingBurst[nextProcess] {
-					nextProcess = i
-				}
-			}
+func (c *scheduleCmd) run(ctx context.Context, w io.Writer, args []string) (err error) {
+	render, err := c.renderer()
+	if err != nil {
+		return err
+	}
+	switch {
+	case c.checkpointAt < 0:
+		return fmt.Errorf("%w: -checkpoint-at must not be negative", ErrInvalidArgs)
+	case (c.checkpointAt > 0) != (c.checkpoint != ""):
+		return fmt.Errorf("%w: -checkpoint-at and -checkpoint must be given together", ErrInvalidArgs)
+	case c.stream && c.format != "text":
+		return fmt.Errorf("%w: -stream needs the text format", ErrInvalidArgs)
+	case c.stream && c.checkpointAt > 0:
+		return fmt.Errorf("%w: -stream and -checkpoint-at cannot be combined", ErrInvalidArgs)
+	case c.stream && c.quiet:
+		return fmt.Errorf("%w: -stream and -quiet cannot be combined", ErrInvalidArgs)
+	}
+	records, err := c.dbFlags.open("schedule", c.seed.value)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if commitErr := records.commit(); err == nil {
+			err = commitErr
 		}
-
-		if nextProcess == -1 {
-			completion++
-			continue
+	}()
+	path, err := workloadPath(args)
+	if err != nil {
+		return err
+	}
+	processes, err := c.load(path)
+	if err != nil {
+		return err
+	}
+	if processes, err = c.jittered(c.rand(), processes); err != nil {
+		return err
+	}
+	var checkpoints *checkpointWriter
+	if c.checkpoint != "" {
+		if checkpoints, err = createCheckpoints(c.checkpoint); err != nil {
+			return err
 		}
+		defer func() {
+			if closeErr := checkpoints.close(); err == nil {
+				err = closeErr
+			}
+		}()
+	}
 
-		start := completion
-		serviceTime++
-		remainingBurst[nextProcess]--
-
-		if remainingBurst[nextProcess] == 0 {
-			turnaround := completion - processes[nextProcess].ArrivalTime + 1
-			totalTurnaround += float64(turnaround)
-			waitingTime = turnaround - processes[nextProcess].BurstDuration
-			totalWait += float64(waitingTime)
-			lastCompletion = float64(completion) + 1
-
-			schedule[nextProcess] = []string{
-				fmt.Sprint(processes[nextProcess].ProcessID),
-				fmt.Sprint(processes[nextProcess].Priority),
-				fmt.Sprint(processes[nextProcess].BurstDuration),
-				fmt.Sprint(processes[nextProcess].ArrivalTime),
-				fmt.Sprint(waitingTime),
-				fmt.Sprint(turnaround),
-				fmt.Sprint(completion + processes[nextProcess].BurstDuration),
+	end := c.begin(w)
+	defer func() {
+		if endErr := end(); err == nil {
+			err = endErr
+		}
+	}()
+	if c.stream {
+		return c.runStreamed(ctx, w, path, processes, records)
+	}
+	var jobs []job
+	for _, alg := range forWorkload(algorithms(), "all", processes) {
+		progress, err := c.progressOption(alg.name)
+		if err != nil {
+			return err
+		}
+		extra := []scheduler.Option{scheduler.WithSeed(c.seed.value), progress, scheduler.WithCheckpoint(c.checkpointAt * c.unit())}
+		if c.quiet {
+			extra = append(extra, scheduler.WithMetricsOnly())
+		}
+		opts, err := c.options(extra...)
+		if err != nil {
+			return err
+		}
+		jobs = append(jobs, job{alg: alg, opts: opts})
+	}
+	scheduleAll(ctx, c.resultCache(), jobs, processes)
+	for _, j := range jobs {
+		r, err := j.r, j.err
+		if r != nil && err == nil && r.Checkpoint == nil {
+			records.add(path, j.alg.name, r)
+		}
+		if r != nil && r.Checkpoint != nil {
+			r.Checkpoint.Algorithm = j.alg.name
+			if saveErr := checkpoints.save(r.Checkpoint); err == nil {
+				err = saveErr
 			}
-			completion += int64(processes[nextProcess].BurstDuration)
-			gantt = append(gantt, TimeSlice{
-				PID:   processes[nextProcess].ProcessID,
-				Start: start,
-				Stop:  completion,
-			})
+		}
+		if renderErr := c.render(w, render, j.alg.title, r); err == nil {
+			err = renderErr
+		}
+		if err != nil {
+			return err
 		}
 	}
 
-	count := float64(len(processes))
-	aveWait := totalWait / count
-	aveTurnaround := totalTurnaround / count
-	aveThroughput := count / lastCompletion
-
-	outputTitle(w, title)
-	outputGantt(w, gantt)
-	outputSchedule(w, schedule, aveWait, aveTurnaround, aveThroughput)
+	return nil
 }
 
-// SJFSchedule implements Shortest Job First (SJF) scheduling algorithm.
-// SJFSchedule implements Shortest Job First (SJF) scheduling algorithm.
-func SJFSchedule(w io.Writer, title string, processes []Process) {
-	var (
-		serviceTime     int64
-		totalWait       float64
-		totalTurnaround float64
-		lastCompletion  float64
-		waitingTime     int64
-		schedule        = make([][]string, len(processes))
-		gantt           = make([]TimeSlice, 0)
-	)
-
-	remainingBurst := make([]int64, len(processes))
-	copy(remainingBurst, getBurstDurations(processes))
-
-	completion := int64(0)
-	for completion < int64(len(processes)) {
-		nextProcess := -1
-
-		for i, p := range processes {
-			if remainingBurst[i] > 0 && p.ArrivalTime <= completion {
-				if nextProcess == -1 || remainingBurst[i] < remainingBurst[nextProcess] {
-					nextProcess = i
-				}
-			}
+// runStreamed runs the algorithms one after another, writing each Gantt
+// chart as the simulation produces it and then the rest of the report, and
+// adding each complete schedule to records.
+func (c *scheduleCmd) runStreamed(ctx context.Context, w io.Writer, path string, processes []scheduler.Process, records *dbRun) error {
+	for _, alg := range forWorkload(algorithms(), "all", processes) {
+		progress, err := c.progressOption(alg.name)
+		if err != nil {
+			return err
 		}
-
-		if nextProcess == -1 {
-			completion++
-			continue
+		gw := scheduler.NewGanttWriter(w, c.cpus, c.unit())
+		opts, err := c.options(scheduler.WithSeed(c.seed.value), progress, scheduler.WithSlices(gw.Add))
+		if err != nil {
+			return err
 		}
-
-		start := completion
-		serviceTime++
-		remainingBurst[nextProcess]--
-
-		if remainingBurst[nextProcess] == 0 {
-			turnaround := completion - processes[nextProcess].ArrivalTime + 1
-			totalTurnaround += float64(turnaround)
-			waitingTime = turnaround - processes[nextProcess].BurstDuration
-			totalWait += float64(waitingTime)
-			lastCompletion = float64(completion) + 1
-
-			schedule[nextProcess] = []string{
-				fmt.Sprint(processes[nextProcess].ProcessID),
-				fmt.Sprint(processes[nextProcess].Priority),
-				fmt.Sprint(processes[nextProcess].BurstDuration),
-				fmt.Sprint(processes[nextProcess].ArrivalTime),
-				fmt.Sprint(waitingTime),
-				fmt.Sprint(turnaround),
-				fmt.Sprint(completion + processes[nextProcess].BurstDuration),
-			}
-			completion += int64(processes[nextProcess].BurstDuration)
-			gantt = append(gantt, TimeSlice{
-				PID:   processes[nextProcess].ProcessID,
-				Start: start,
-				Stop:  completion,
-			})
+		j := []job{{alg: alg, opts: opts}}
+		// Not cached: a cached result would have no chart to stream.
+		scheduleAll(ctx, nil, j, processes)
+		r, err := j[0].r, j[0].err
+		if r == nil {
+			return err
+		}
+		if err == nil {
+			records.add(path, alg.name, r)
+		}
+		if closeErr := gw.Close(); err == nil {
+			err = closeErr
+		}
+		if renderErr := c.render(w, scheduler.RenderText, alg.title, r); err == nil {
+			err = renderErr
+		}
+		if err != nil {
+			return err
 		}
 	}
 
-	count := float64(len(processes))
-	aveWait := totalWait / count
-	aveTurnaround := totalTurnaround / count
-	aveThroughput := count / lastCompletion
-
-	outputTitle(w, title)
-	outputGantt(w, gantt)
-	outputSchedule(w, schedule, aveWait, aveTurnaround, aveThroughput)
+	return nil
 }
 
-// func SJFSchedule(w io.Writer, title string, processes []Process) { }
-//
-// func RRSchedule(w io.Writer, title string, processes []Process) { }
-// RRSchedule implements Round-Robin (RR) scheduling algorithm.
-func RRSchedule(w io.Writer, title string, processes []Process) {
-	var (
-		serviceTime     int64
-		totalWait       float64
-		totalTurnaround float64
-		lastCompletion  float64
-		waitingTime     int64
-		schedule        = make([][]string, len(processes))
-		gantt           = make([]TimeSlice, 0)
-	)
-
-	remainingBurst := make([]int64, len(processes))
-	copy(remainingBurst, getBurstDurations(processes))
-
-	quantum := int64(2) // Adjust the quantum as needed
-
-	completion := int64(0)                   // Change to int64
-	for completion < int64(len(processes)) { // Change to int64
-		for i := range processes {
-			if remainingBurst[i] > 0 && processes[i].ArrivalTime <= completion { // Change to int64
-				start := completion // Change to int64
-				serviceTime++
-				if remainingBurst[i] <= quantum {
-					waitingTime = completion - processes[i].ArrivalTime
-					turnaround := waitingTime + remainingBurst[i]
-					totalWait += float64(waitingTime)
-					totalTurnaround += float64(turnaround)
-					lastCompletion = float64(completion) + float64(remainingBurst[i])
-
-					schedule[i] = []string{
-						fmt.Sprint(processes[i].ProcessID),
-						fmt.Sprint(processes[i].Priority),
-						fmt.Sprint(processes[i].BurstDuration),
-						fmt.Sprint(processes[i].ArrivalTime),
-						fmt.Sprint(waitingTime),
-						fmt.Sprint(turnaround),
-						fmt.Sprint(completion + remainingBurst[i]),
-					}
-					completion += int64(processes[i].BurstDuration)
-					gantt = append(gantt, TimeSlice{
-						PID:   processes[i].ProcessID,
-						Start: start,
-						Stop:  completion, // Change to int64
-					})
-					remainingBurst[i] = 0
-				} else {
-					waitingTime = completion - processes[i].ArrivalTime
-					turnaround := waitingTime + quantum
-					totalWait += float64(waitingTime)
-					totalTurnaround += float64(turnaround)
-					lastCompletion = float64(completion) + float64(quantum)
-
-					schedule[i] = []string{
-						fmt.Sprint(processes[i].ProcessID),
-						fmt.Sprint(processes[i].Priority),
-						fmt.Sprint(processes[i].BurstDuration),
-						fmt.Sprint(processes[i].ArrivalTime),
-						fmt.Sprint(waitingTime),
-						fmt.Sprint(turnaround),
-						fmt.Sprint(completion + quantum),
-					}
-					completion += quantum
-					gantt = append(gantt, TimeSlice{
-						PID:   processes[i].ProcessID,
-						Start: start,
-						Stop:  completion, // Change to int64
-					})
-					remainingBurst[i] -= quantum
-				}
-			}
+// begin writes what the -format flag puts ahead of the schedules, the
+// start of an HTML page or a CSV header, and returns a function that writes
+// what goes after them, such as the footer of a Parquet file.
+func (c *reportFlags) begin(w io.Writer) (end func() error) {
+	switch c.format {
+	case "html":
+		_, _ = fmt.Fprintln(w, "<!DOCTYPE html>\n<html>\n<body>")
+		return func() error {
+			_, err := fmt.Fprintln(w, "</body>\n</html>")
+			return err
 		}
+	case "csv":
+		_, _ = fmt.Fprintln(w, strings.Join(scheduler.CSVHeader, ","))
+	case "parquet", "parquet-slices":
+		c.parquet = scheduler.NewParquetWriter(w, parquetTables[c.format])
+		return c.parquet.Close
 	}
 
-	count := float64(len(processes))
-	aveWait := totalWait / count
-	aveTurnaround := totalTurnaround / count
-	aveThroughput := count / lastCompletion
-
-	outputTitle(w, title)
-	outputGantt(w, gantt)
-	outputSchedule(w, schedule, aveWait, aveTurnaround, aveThroughput)
+	return func() error { return nil }
 }
-func getBurstDurations(processes []Process) []int64 {
-	bursts := make([]int64, len(processes))
-	for i, p := range processes {
-		bursts[i] = p.BurstDuration
+
+// render writes r, if any, under title with the -states and -verbose
+// reports it asks for, and flushes it.
+func (c *reportFlags) render(w io.Writer, render func(io.Writer, string, *scheduler.ScheduleResult) error, title string, r *scheduler.ScheduleResult) error {
+	if r == nil {
+		return nil
+	}
+	err := render(w, title, r)
+	if c.states && c.format == "text" {
+		if renderErr := scheduler.RenderStates(w, r); err == nil {
+			err = renderErr
+		}
 	}
-	return bursts
+	if c.verbose && c.format == "text" {
+		if renderErr := scheduler.RenderVerbose(w, r); err == nil {
+			err = renderErr
+		}
+	}
+	if flushErr := flush(w); err == nil {
+		err = flushErr
+	}
+
+	return err
+}
+
+// parquetTables maps the -format values written as Parquet files to the
+// tables they hold.
+var parquetTables = map[string]scheduler.ParquetTable{
+	"parquet":        scheduler.ParquetProcesses,
+	"parquet-slices": scheduler.ParquetSlices,
 }
 
-//endregion
+// renderers maps the -format values to the scheduler renderers.
+var renderers = map[string]func(io.Writer, string, *scheduler.ScheduleResult) error{
+	"text":    scheduler.RenderText,
+	"json":    scheduler.RenderJSON,
+	"html":    scheduler.RenderHTML,
+	"trace":   scheduler.RenderTrace,
+	"grafana": scheduler.RenderGrafana,
+	"csv":     scheduler.RenderCSV,
+	"proto":   scheduler.RenderProto,
+	"msgpack": scheduler.RenderMsgpack,
+}
 
-//region Output helpers
+// workloadPath returns the scheduling file named by a command's positional
+// arguments, which must be exactly one path.
+func workloadPath(args []string) (string, error) {
+	if len(args) != 1 {
+		return "", fmt.Errorf("%w: must give a scheduling file to process", ErrInvalidArgs)
+	}
 
-func outputTitle(w io.Writer, title string) {
-	_, _ = fmt.Fprintln(w, strings.Repeat("-", len(title)*2))
-	_, _ = fmt.Fprintln(w, strings.Repeat(" ", len(title)/2), title)
-	_, _ = fmt.Fprintln(w, strings.Repeat("-", len(title)*2))
+	return args[0], nil
 }
 
-func outputGantt(w io.Writer, gantt []TimeSlice) {
-	_, _ = fmt.Fprintln(w, "Gantt schedule")
-	_, _ = fmt.Fprint(w, "|")
-	for i := range gantt {
-		pid := fmt.Sprint(gantt[i].PID)
-		padding := strings.Repeat(" ", (8-len(pid))/2)
-		_, _ = fmt.Fprint(w, padding, pid, padding, "|")
-	}
-	_, _ = fmt.Fprintln(w)
-	for i := range gantt {
-		_, _ = fmt.Fprint(w, fmt.Sprint(gantt[i].Start), "\t")
-		if len(gantt)-1 == i {
-			_, _ = fmt.Fprint(w, fmt.Sprint(gantt[i].Stop))
+// loadWorkload loads and validates the processes in the scheduling file at
+// path, whose times may be fractional, and returns them in whole ticks along
+// with the number of ticks per unit of time in the file. The file may also be
+// a binary workload written by the convert command, or a protobuf Workload
+// named .pb or .binpb.
+func loadWorkload(path string) ([]scheduler.Process, int64, error) {
+	f, closeFile, err := openProcessingFile(path)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer func() {
+		if err := closeFile(); err != nil {
+			log.Print(err)
 		}
+	}()
+
+	// Load and parse processes
+	load := scheduler.LoadProcessesFractional
+	switch {
+	case scheduler.IsBinary(f):
+		load = func(io.Reader) ([]scheduler.Process, int64, error) { return scheduler.LoadBinary(f) }
+	case isProto(path):
+		load = scheduler.LoadProto
+	}
+	processes, ticks, err := load(f)
+	if err != nil {
+		return nil, 0, &fileError{path: path, err: err}
+	}
+	if err := scheduler.ValidateProcesses(processes); err != nil {
+		return nil, 0, &fileError{path: path, err: err}
+	}
+	if processes, err = scheduler.ExpandPeriodic(processes); err != nil {
+		return nil, 0, &fileError{path: path, err: err}
 	}
-	_, _ = fmt.Fprintf(w, "\n\n")
-}
 
-func outputSchedule(w io.Writer, rows [][]string, wait, turnaround, throughput float64) {
-	_, _ = fmt.Fprintln(w, "Schedule table")
-	table := tablewriter.NewWriter(w)
-	table.SetHeader([]string{"ID", "Priority", "Burst", "Arrival", "Wait", "Turnaround", "Exit"})
-	table.AppendBulk(rows)
-	table.SetFooter([]string{"", "", "", "",
-		fmt.Sprintf("Average\n%.2f", wait),
-		fmt.Sprintf("Average\n%.2f", turnaround),
-		fmt.Sprintf("Throughput\n%.2f/t", throughput)})
-	table.Render()
+	return processes, ticks, nil
 }
 
-//endregion
-
-//region Loading processes.
+// isProto reports whether path, a file or object URI, is named as a
+// protobuf workload.
+func isProto(path string) bool {
+	switch filepath.Ext(path) {
+	case ".pb", ".binpb":
+		return true
+	}
+	return false
+}
 
-var ErrInvalidArgs = errors.New("invalid args")
+// exitCode maps an error returned by run to one of the Exit* codes.
+func exitCode(err error) int {
+	switch {
+	case err == nil:
+		return ExitOK
+	case errors.Is(err, context.Canceled):
+		return ExitCancelled
+	case errors.Is(err, ErrInvalidArgs):
+		return ExitInvalidArgs
+	case errors.Is(err, fs.ErrNotExist):
+		return ExitFileNotFound
+	case errors.Is(err, scheduler.ErrParse):
+		return ExitParse
+	case errors.Is(err, scheduler.ErrValidation):
+		return ExitValidation
+	case errors.Is(err, ErrRegression):
+		return ExitRegression
+	case errors.Is(err, ErrDifferent):
+		return ExitDifferent
+	default:
+		return ExitInternal
+	}
+}
 
-func loadProcesses(r io.Reader) ([]Process, error) {
-	rows, err := csv.NewReader(r).ReadAll()
+func openProcessingFile(path string) (*os.File, func() error, error) {
+	if isObjectURI(path) {
+		f, closeFn, err := downloadObject(context.Background(), path)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: error opening scheduling file", err)
+		}
+		return f, closeFn, nil
+	}
+	// Read in CSV process CSV file
+	f, err := os.Open(path)
 	if err != nil {
-		return nil, fmt.Errorf("%w: reading CSV", err)
+		return nil, nil, fmt.Errorf("%w: error opening scheduling file", err)
 	}
-
-	processes := make([]Process, len(rows))
-	for i := range rows {
-		processes[i].ProcessID = mustStrToInt(rows[i][0])
-		processes[i].BurstDuration = mustStrToInt(rows[i][1])
-		processes[i].ArrivalTime = mustStrToInt(rows[i][2])
-		if len(rows[i]) == 4 {
-			processes[i].Priority = mustStrToInt(rows[i][3])
+	closeFn := func() error {
+		if err := f.Close(); err != nil {
+			return fmt.Errorf("%w: error closing scheduling file", err)
 		}
+		return nil
 	}
 
-	return processes, nil
+	return f, closeFn, nil
 }
 
-func mustStrToInt(s string) int64 {
-	i, err := strconv.ParseInt(s, 10, 64)
+// readInputFile opens path and hands it to parse, for the inputs of
+// the simulators other than the scheduler, reporting parse errors against
+// path.
+func readInputFile(path string, parse func(io.Reader) error) error {
+	f, closeFile, err := openProcessingFile(path)
 	if err != nil {
-		_, _ = fmt.Fprintln(os.Stderr, err)
-		os.Exit(1)
+		return err
+	}
+	defer func() {
+		if err := closeFile(); err != nil {
+			log.Print(err)
+		}
+	}()
+	if err := parse(f); err != nil {
+		return &fileError{path: path, err: err}
 	}
 
-	return i
+	return nil
 }
-
-//endregion
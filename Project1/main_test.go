@@ -2,138 +2,21 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"errors"
+	"fmt"
 	"io"
+	"io/fs"
 	"os"
 	"path"
+	"path/filepath"
 	"reflect"
+	"sort"
 	"strings"
 	"testing"
-	"testing/iotest"
-)
 
-func TestFCFSSchedule(t *testing.T) {
-	t.Parallel()
-	type args struct {
-		processes []Process
-		title     string
-	}
-	tests := []struct {
-		name    string
-		args    args
-		wantOut string
-	}{
-		{
-			name: "default",
-			args: args{
-				processes: []Process{
-					{
-						ProcessID:     1,
-						ArrivalTime:   0,
-						BurstDuration: 5,
-						Priority:      2,
-					},
-					{
-						ProcessID:     2,
-						ArrivalTime:   3,
-						BurstDuration: 9,
-						Priority:      1,
-					},
-					{
-						ProcessID:     3,
-						ArrivalTime:   6,
-						BurstDuration: 6,
-						Priority:      3,
-					},
-				},
-				title: "First-come, First-serve",
-			},
-			wantOut: loadFixture(t, "fcfs_test.txt"),
-		},
-	}
-	for _, tt := range tests {
-		tt := tt
-		t.Run(tt.name, func(t *testing.T) {
-			t.Parallel()
-			var w bytes.Buffer
-			FCFSSchedule(&w, tt.args.title, tt.args.processes)
-			if got := w.String(); got != tt.wantOut {
-				t.Errorf("FCFSSchedule() = %v, want %v", got, tt.wantOut)
-			}
-		})
-	}
-}
-
-func Test_loadProcesses(t *testing.T) {
-	t.Parallel()
-	type args struct {
-		r io.Reader
-	}
-	tests := []struct {
-		name    string
-		args    args
-		want    []Process
-		wantErr error
-	}{
-		{
-			name: "bad CSV",
-			args: args{
-				r: iotest.ErrReader(io.ErrUnexpectedEOF),
-			},
-			wantErr: io.ErrUnexpectedEOF,
-		},
-		{
-			name: "success",
-			args: args{
-				r: strings.NewReader(`1,5,0,2
-2,9,3,1
-3,6,3,3`),
-			},
-			want: []Process{
-				{
-					ProcessID:     1,
-					ArrivalTime:   0,
-					BurstDuration: 5,
-					Priority:      2,
-				},
-				{
-					ProcessID:     2,
-					ArrivalTime:   3,
-					BurstDuration: 9,
-					Priority:      1,
-				},
-				{
-					ProcessID:     3,
-					ArrivalTime:   3,
-					BurstDuration: 6,
-					Priority:      3,
-				},
-			},
-		},
-	}
-	for _, tt := range tests {
-		tt := tt
-		t.Run(tt.name, func(t *testing.T) {
-			t.Parallel()
-			got, err := loadProcesses(tt.args.r)
-			if !reflect.DeepEqual(got, tt.want) {
-				t.Errorf("loadProcesses() = %v, want %v", got, tt.want)
-			}
-			if !errors.Is(err, tt.wantErr) {
-				t.Errorf("error = %v, want %v", err, tt.wantErr)
-			}
-		})
-	}
-}
-
-func loadFixture(t *testing.T, p ...string) string {
-	b, err := os.ReadFile(path.Join(p...))
-	if err != nil {
-		t.Fail()
-	}
-
-	return string(b)
-}
+	"github.com/rks0134/CSCE4600/Project1/scheduler"
+)
 
 func Test_openProcessingFile1(t *testing.T) {
 	tmpFile, tErr := os.CreateTemp(t.TempDir(), "")
@@ -142,7 +25,7 @@ func Test_openProcessingFile1(t *testing.T) {
 	}
 
 	type args struct {
-		args []string
+		path string
 	}
 	tests := []struct {
 		name    string
@@ -153,28 +36,21 @@ func Test_openProcessingFile1(t *testing.T) {
 		{
 			name: "success",
 			args: args{
-				args: []string{"binary_name", tmpFile.Name()},
+				path: tmpFile.Name(),
 			},
 			want: tmpFile,
 		},
-		{
-			name: "not enough args",
-			args: args{
-				args: []string{"binary_name"},
-			},
-			wantErr: true,
-		},
 		{
 			name: "bad file",
 			args: args{
-				args: []string{"binary_name", "bad_file_name"},
+				path: "bad_file_name",
 			},
 			wantErr: true,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, closeFn, err := openProcessingFile(tt.args.args...)
+			got, closeFn, err := openProcessingFile(tt.args.path)
 			if (err != nil) != tt.wantErr {
 				t.Fatalf("openProcessingFile() error = %v, wantErr %v", err, tt.wantErr)
 			}
@@ -188,7 +64,11 @@ func Test_openProcessingFile1(t *testing.T) {
 			if closeFn == nil {
 				t.Fatal("closeFn is unexpectedly nil")
 			}
-			t.Cleanup(closeFn)
+			t.Cleanup(func() {
+				if err := closeFn(); err != nil {
+					t.Error(err)
+				}
+			})
 
 			f1, err := os.Stat(got.Name())
 			if err != nil {
@@ -205,3 +85,312 @@ func Test_openProcessingFile1(t *testing.T) {
 		})
 	}
 }
+
+func Test_workloadPath(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		args    []string
+		want    string
+		wantErr error
+	}{
+		{name: "one path", args: []string{"example_processes.csv"}, want: "example_processes.csv"},
+		{name: "no path", args: nil, wantErr: ErrInvalidArgs},
+		{name: "too many", args: []string{"a.csv", "b.csv"}, wantErr: ErrInvalidArgs},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := workloadPath(tt.args)
+			if got != tt.want {
+				t.Errorf("workloadPath() = %q, want %q", got, tt.want)
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_exitCode(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{name: "nil", err: nil, want: ExitOK},
+		{name: "invalid args", err: fmt.Errorf("%w: usage", ErrInvalidArgs), want: ExitInvalidArgs},
+		{name: "file not found", err: fmt.Errorf("%w: opening", fs.ErrNotExist), want: ExitFileNotFound},
+		{name: "parse", err: &scheduler.ParseError{Err: io.ErrUnexpectedEOF}, want: ExitParse},
+		{name: "validation", err: fmt.Errorf("%w: bad row", scheduler.ErrValidation), want: ExitValidation},
+		{name: "regression", err: fmt.Errorf("%w: fcfs on 100", ErrRegression), want: ExitRegression},
+		{name: "different", err: fmt.Errorf("%w: 1 of 6 schedules", ErrDifferent), want: ExitDifferent},
+		{name: "internal", err: errors.New("boom"), want: ExitInternal},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := exitCode(tt.err); got != tt.want {
+				t.Errorf("exitCode() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_run(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name     string
+		args     []string
+		wantCode int
+	}{
+		{name: "example", args: []string{"binary_name", "example_processes.csv"}, wantCode: ExitOK},
+		{name: "no file", args: []string{"binary_name"}, wantCode: ExitInvalidArgs},
+		{name: "missing file", args: []string{"binary_name", "bad_file_name"}, wantCode: ExitFileNotFound},
+		{name: "bench", args: []string{"binary_name", "bench", "-sizes", "10", "-algorithms", "fcfs", "-benchtime", "10ms"}, wantCode: ExitOK},
+		{name: "compare", args: []string{"binary_name", "compare", "-weights", "wait=2,throughput=1", "example_processes.csv"}, wantCode: ExitOK},
+		{name: "compare unknown metric", args: []string{"binary_name", "compare", "-weights", "fairness=1", "example_processes.csv"}, wantCode: ExitInvalidArgs},
+		{name: "help", args: []string{"binary_name", "help"}, wantCode: ExitOK},
+		{name: "help flag", args: []string{"binary_name", "--help"}, wantCode: ExitOK},
+		{name: "help command", args: []string{"binary_name", "help", "compare"}, wantCode: ExitOK},
+		{name: "help unknown command", args: []string{"binary_name", "help", "bogus"}, wantCode: ExitInvalidArgs},
+		{name: "list algorithms", args: []string{"binary_name", "list-algorithms"}, wantCode: ExitOK},
+		{name: "default command flags", args: []string{"binary_name", "-format", "json", "example_processes.csv"}, wantCode: ExitOK},
+		{name: "default command flags before command", args: []string{"binary_name", "-format", "json", "bench"}, wantCode: ExitInvalidArgs},
+		{name: "trace format", args: []string{"binary_name", "schedule", "-format", "trace", "example_processes.csv"}, wantCode: ExitOK},
+		{name: "grafana format", args: []string{"binary_name", "schedule", "-format", "grafana", "example_processes.csv"}, wantCode: ExitOK},
+		{name: "csv format", args: []string{"binary_name", "schedule", "-format", "csv", "example_processes.csv"}, wantCode: ExitOK},
+		{name: "msgpack format", args: []string{"binary_name", "schedule", "-format", "msgpack", "example_processes.csv"}, wantCode: ExitOK},
+		{name: "parquet format", args: []string{"binary_name", "schedule", "-format", "parquet", "example_processes.csv"}, wantCode: ExitOK},
+		{name: "parquet slices format", args: []string{"binary_name", "schedule", "-format", "parquet-slices", "example_processes.csv"}, wantCode: ExitOK},
+		{name: "unknown format", args: []string{"binary_name", "schedule", "-format", "yaml", "example_processes.csv"}, wantCode: ExitInvalidArgs},
+		{name: "state residency", args: []string{"binary_name", "schedule", "-states", "example_processes.csv"}, wantCode: ExitOK},
+		{name: "verbose", args: []string{"binary_name", "schedule", "-verbose", "example_processes.csv"}, wantCode: ExitOK},
+		{name: "quiet", args: []string{"binary_name", "schedule", "-quiet", "-switch-cost", "1", "example_processes.csv"}, wantCode: ExitOK},
+		{name: "quiet json", args: []string{"binary_name", "schedule", "-quiet", "-format", "json", "example_processes.csv"}, wantCode: ExitOK},
+		{name: "quiet html", args: []string{"binary_name", "schedule", "-quiet", "-format", "html", "example_processes.csv"}, wantCode: ExitInvalidArgs},
+		{name: "quiet verbose", args: []string{"binary_name", "schedule", "-quiet", "-verbose", "example_processes.csv"}, wantCode: ExitInvalidArgs},
+		{name: "multiple CPUs", args: []string{"binary_name", "schedule", "-cpus", "2", "example_processes.csv"}, wantCode: ExitOK},
+		{name: "no CPUs", args: []string{"binary_name", "schedule", "-cpus", "0", "example_processes.csv"}, wantCode: ExitInvalidArgs},
+		{name: "CPU speeds", args: []string{"binary_name", "schedule", "-cpus", "2", "-speeds", "2,0.5", "example_processes.csv"}, wantCode: ExitOK},
+		{name: "speeds for the wrong number of CPUs", args: []string{"binary_name", "schedule", "-speeds", "2,1", "example_processes.csv"}, wantCode: ExitInvalidArgs},
+		{name: "aging", args: []string{"binary_name", "schedule", "-aging", "0.5", "-verbose", "example_processes.csv"}, wantCode: ExitOK},
+		{name: "warm-up and cool-down", args: []string{"binary_name", "schedule", "-warm-up", "2", "-cool-down", "3", "example_processes.csv"}, wantCode: ExitOK},
+		{name: "negative cool-down", args: []string{"binary_name", "compare", "-cool-down", "-1", "example_processes.csv"}, wantCode: ExitInvalidArgs},
+		{name: "max time", args: []string{"binary_name", "schedule", "-max-time", "8", "example_processes.csv"}, wantCode: ExitOK},
+		{name: "negative max time", args: []string{"binary_name", "schedule", "-max-time", "-8", "example_processes.csv"}, wantCode: ExitInvalidArgs},
+		{name: "memory", args: []string{"binary_name", "schedule", "-memory", "64", "example_processes.csv"}, wantCode: ExitOK},
+		{name: "negative memory", args: []string{"binary_name", "schedule", "-memory", "-1", "example_processes.csv"}, wantCode: ExitInvalidArgs},
+		{name: "negative aging", args: []string{"binary_name", "schedule", "-aging", "-1", "example_processes.csv"}, wantCode: ExitInvalidArgs},
+		{name: "priority inheritance", args: []string{"binary_name", "schedule", "-inherit", "example_processes.csv"}, wantCode: ExitOK},
+		{name: "invalid speed", args: []string{"binary_name", "schedule", "-speeds", "fast", "example_processes.csv"}, wantCode: ExitInvalidArgs},
+		{name: "sweep", args: []string{"binary_name", "sweep", "-quantum", "1..4", "example_processes.csv"}, wantCode: ExitOK},
+		{name: "sweep all", args: []string{"binary_name", "sweep", "-quantum", "1..4", "-algorithms", "all", "example_processes.csv"}, wantCode: ExitOK},
+		{name: "sweep without quantum", args: []string{"binary_name", "sweep", "-algorithms", "fcfs", "example_processes.csv"}, wantCode: ExitInvalidArgs},
+		{name: "sweep jitter", args: []string{"binary_name", "-seed", "3", "sweep", "-jitter", "2", "-trials", "5", "-algorithms", "fcfs,rr", "example_processes.csv"}, wantCode: ExitOK},
+		{name: "sweep normal jitter", args: []string{"binary_name", "-seed", "3", "sweep", "-jitter", "2", "-jitter-dist", "normal", "-trials", "5", "example_processes.csv"}, wantCode: ExitOK},
+		{name: "schedule jitter", args: []string{"binary_name", "-seed", "3", "schedule", "-jitter", "2", "-jitter-dist", "exponential", "example_processes.csv"}, wantCode: ExitOK},
+		{name: "compare jitter", args: []string{"binary_name", "-seed", "3", "compare", "-jitter", "2", "example_processes.csv"}, wantCode: ExitOK},
+		{name: "negative jitter", args: []string{"binary_name", "schedule", "-jitter", "-2", "example_processes.csv"}, wantCode: ExitInvalidArgs},
+		{name: "unknown jitter distribution", args: []string{"binary_name", "schedule", "-jitter", "2", "-jitter-dist", "poisson", "example_processes.csv"}, wantCode: ExitInvalidArgs},
+		{name: "sweep jitter quantum range", args: []string{"binary_name", "-seed", "3", "sweep", "-jitter", "2", "-quantum", "1..3", "example_processes.csv"}, wantCode: ExitInvalidArgs},
+		{name: "sweep bad range", args: []string{"binary_name", "sweep", "-quantum", "4..1", "example_processes.csv"}, wantCode: ExitInvalidArgs},
+		{name: "unknown error format", args: []string{"binary_name", "-errors", "xml", "example_processes.csv"}, wantCode: ExitInvalidArgs},
+		{name: "switch cost", args: []string{"binary_name", "-switch-cost", "1", "example_processes.csv"}, wantCode: ExitOK},
+		{name: "dispatch latency", args: []string{"binary_name", "sweep", "-dispatch-latency", "1", "-quantum", "1..3", "example_processes.csv"}, wantCode: ExitOK},
+		{name: "random tie-break", args: []string{"binary_name", "-seed", "1", "-tie-break", "random", "example_processes.csv"}, wantCode: ExitOK},
+		{name: "unknown tie-break", args: []string{"binary_name", "compare", "-tie-break", "coin", "example_processes.csv"}, wantCode: ExitInvalidArgs},
+		{name: "negative switch cost", args: []string{"binary_name", "compare", "-switch-cost", "-1", "example_processes.csv"}, wantCode: ExitInvalidArgs},
+		{name: "migration cost", args: []string{"binary_name", "compare", "-cpus", "2", "-migration-cost", "1", "example_processes.csv"}, wantCode: ExitOK},
+		{name: "power", args: []string{"binary_name", "compare", "-cpus", "2", "-speeds", "2,1", "-power", "active=4,static=1,idle=0.2", "example_processes.csv"}, wantCode: ExitOK},
+		{name: "unknown power", args: []string{"binary_name", "schedule", "-power", "dynamic=1", "example_processes.csv"}, wantCode: ExitInvalidArgs},
+		{name: "negative power", args: []string{"binary_name", "schedule", "-power", "idle=-1", "example_processes.csv"}, wantCode: ExitInvalidArgs},
+		{name: "negative migration cost", args: []string{"binary_name", "schedule", "-cpus", "2", "-migration-cost", "-1", "example_processes.csv"}, wantCode: ExitInvalidArgs},
+		{name: "man", args: []string{"binary_name", "man"}, wantCode: ExitOK},
+		{name: "bench unknown algorithm", args: []string{"binary_name", "bench", "-algorithms", "lottery"}, wantCode: ExitInvalidArgs},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := exitCode(run(context.Background(), io.Discard, tt.args...)); got != tt.wantCode {
+				t.Errorf("run() exit code = %v, want %v", got, tt.wantCode)
+			}
+		})
+	}
+}
+
+func Test_runCancelled(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	for _, args := range [][]string{
+		{"binary_name", "example_processes.csv"},
+		{"binary_name", "compare", "example_processes.csv"},
+		{"binary_name", "sweep", "-quantum", "1..2", "example_processes.csv"},
+	} {
+		if got := exitCode(run(ctx, io.Discard, args...)); got != ExitCancelled {
+			t.Errorf("run(%v) exit code = %v, want %v", args[1:], got, ExitCancelled)
+		}
+	}
+}
+
+// writeCounter counts the writes made to it.
+type writeCounter struct {
+	strings.Builder
+	writes int
+}
+
+func (w *writeCounter) Write(p []byte) (int, error) {
+	w.writes++
+	return w.Builder.Write(p)
+}
+
+func Test_runBuffered(t *testing.T) {
+	t.Parallel()
+	var w writeCounter
+	if err := run(context.Background(), &w, "binary_name", "-seed", "1", "example_processes.csv"); err != nil {
+		t.Fatal(err)
+	}
+	// One flush per algorithm's report, and a final one with nothing left.
+	if max := len(algorithms()); w.writes > max {
+		t.Errorf("schedule made %d writes, want at most one per algorithm (%d)", w.writes, max)
+	}
+	if !strings.Contains(w.String(), "Schedule table") {
+		t.Errorf("schedule output is missing its tables:\n%s", w.String())
+	}
+}
+
+func Test_runStreamed(t *testing.T) {
+	t.Parallel()
+	var kept, streamed strings.Builder
+	if err := run(context.Background(), &kept, "binary_name", "-seed", "1", "example_processes.csv"); err != nil {
+		t.Fatal(err)
+	}
+	if err := run(context.Background(), &streamed, "binary_name", "schedule", "-stream", "-seed", "1", "example_processes.csv"); err != nil {
+		t.Fatal(err)
+	}
+	// The charts come ahead of their reports' titles, but nothing else
+	// changes.
+	if !strings.HasPrefix(streamed.String(), "Gantt schedule\n") {
+		t.Errorf("streamed output does not start with a chart:\n%s", streamed.String())
+	}
+	lines := func(s string) []string {
+		l := strings.Split(s, "\n")
+		sort.Strings(l)
+		return l
+	}
+	if !reflect.DeepEqual(lines(streamed.String()), lines(kept.String())) {
+		t.Errorf("streamed output\n%s\nhas different lines than\n%s", streamed.String(), kept.String())
+	}
+
+	err := run(context.Background(), &streamed, "binary_name", "schedule", "-stream", "-format", "json", "example_processes.csv")
+	if !errors.Is(err, ErrInvalidArgs) {
+		t.Errorf("schedule -stream -format json error = %v, want %v", err, ErrInvalidArgs)
+	}
+}
+
+func Test_runSeed(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{name: "before command", args: []string{"binary_name", "-seed", "42", "bench", "-sizes", "5", "-algorithms", "fcfs", "-benchtime", "10ms"}},
+		{name: "among command flags", args: []string{"binary_name", "bench", "-sizes", "5", "-seed", "42", "-algorithms", "fcfs", "-benchtime", "10ms"}},
+		{name: "default command", args: []string{"binary_name", "-seed", "42", "example_processes.csv"}},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var w strings.Builder
+			if err := run(context.Background(), &w, tt.args...); err != nil {
+				t.Fatalf("run() error = %v", err)
+			}
+			if !strings.Contains(w.String(), "Seed: 42") {
+				t.Errorf("output does not report the seed:\n%s", w.String())
+			}
+		})
+	}
+}
+
+func Test_runFractional(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	tests := []struct {
+		name     string
+		csv      string
+		args     []string
+		want     string
+		wantCode int
+	}{
+		{name: "schedule", csv: "1,2.5,0,1\n2,1.5,0.5,2\n", args: []string{"schedule"}, want: "|  2 |        2 |   1.5 |     0.5 |", wantCode: ExitOK},
+		{name: "switch cost in file units", csv: "1,2.5,0,1\n2,1.5,0.5,2\n", args: []string{"schedule", "-switch-cost", "1"}, want: "0\t2.5\t3.5\t5", wantCode: ExitOK},
+		{name: "sweep", csv: "1,2.5,0,1\n2,1.5,0.5,2\n", args: []string{"sweep", "-quantum", "1..2"}, wantCode: ExitOK},
+		{name: "trace", csv: "1,1,0,1\n2,0.5,0,2\n", args: []string{"schedule", "-format", "trace"}, want: "First-come, first-serve (seed", wantCode: ExitOK},
+		{name: "too many decimals", csv: "1,2.5001,0,1\n", args: []string{"schedule"}, wantCode: ExitParse},
+		{name: "periodic tasks", csv: "1,0.5,0,0,period=1.5\n2,1,0,0,period=3\n", args: []string{"schedule"}, want: "|    1 |    1.5 |  0.5 |      1.5 |    2 |", wantCode: ExitOK},
+	}
+	for k, tt := range tests {
+		tt := tt
+		file := path.Join(dir, fmt.Sprintf("%d.csv", k))
+		if err := os.WriteFile(file, []byte(tt.csv), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var w strings.Builder
+			args := append(append([]string{"binary_name"}, tt.args...), file)
+			if got := exitCode(run(context.Background(), &w, args...)); got != tt.wantCode {
+				t.Errorf("run() exit code = %v, want %v", got, tt.wantCode)
+			}
+			if !strings.Contains(w.String(), tt.want) {
+				t.Errorf("run() output is missing %q:\n%s", tt.want, w.String())
+			}
+		})
+	}
+}
+
+func Test_runProto(t *testing.T) {
+	t.Parallel()
+	pb := filepath.Join(t.TempDir(), "example.pb")
+	if err := run(context.Background(), io.Discard, "binary_name", "convert", "example_processes.csv", pb); err != nil {
+		t.Fatalf("convert error = %v", err)
+	}
+	var fromCSV, fromProto bytes.Buffer
+	if err := run(context.Background(), &fromCSV, "binary_name", "-seed", "1", "schedule", "-format", "proto", "example_processes.csv"); err != nil {
+		t.Fatal(err)
+	}
+	if err := run(context.Background(), &fromProto, "binary_name", "-seed", "1", "schedule", "-format", "proto", pb); err != nil {
+		t.Fatalf("schedule of a protobuf workload error = %v", err)
+	}
+	if !bytes.Equal(fromProto.Bytes(), fromCSV.Bytes()) {
+		t.Error("protobuf workload schedules differently")
+	}
+	reports, err := scheduler.ReadProto(&fromProto)
+	if err != nil {
+		t.Fatalf("ReadProto() of schedule -format proto error = %v", err)
+	}
+	if len(reports) == 0 || reports[0].Title == "" || reports[0].Result.Metrics.Completed == 0 {
+		t.Errorf("schedule -format proto wrote %+v, want titled, completed schedules", reports)
+	}
+}
+
+func Test_runParquet(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "sweep.parquet")
+	if err := run(context.Background(), io.Discard, "binary_name", "-output", path, "schedule", "-format", "parquet", "example_processes.csv"); err != nil {
+		t.Fatalf("schedule -format parquet error = %v", err)
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(b, []byte("PAR1")) || !bytes.HasSuffix(b, []byte("PAR1")) || len(b) < 100 {
+		t.Errorf("schedule -format parquet wrote %d bytes, want a Parquet file", len(b))
+	}
+}
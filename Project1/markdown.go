@@ -0,0 +1,208 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/rks0134/CSCE4600/Project1/scheduler"
+)
+
+// markdownMetrics are the metrics the markdown command reports, with the
+// direction that counts as an improvement.
+var markdownMetrics = append(compareMetrics[:len(compareMetrics):len(compareMetrics)],
+	metric{name: "makespan", label: "makespan", value: func(m scheduler.Metrics) float64 { return m.Makespan }},
+	metric{name: "switches", label: "context switches", value: func(m scheduler.Metrics) float64 { return float64(m.ContextSwitches) }},
+)
+
+// markdownCmd runs the selected algorithms on one workload and writes their
+// metrics as a markdown block to post on a pull request, with the change
+// from a baseline of earlier results.
+type markdownCmd struct {
+	*globalFlags
+	simulationFlags
+	algorithms string
+	baseline   string
+}
+
+func (c *markdownCmd) defineFlags(flags *flag.FlagSet) {
+	flags.StringVar(&c.algorithms, "algorithms", "all", "comma separated `algorithms` to report, or all")
+	flags.StringVar(&c.baseline, "baseline", "", "`file` of results written by schedule -format json to show the change from")
+	c.simulationFlags.define(flags)
+}
+
+func (c *markdownCmd) run(ctx context.Context, w io.Writer, args []string) error {
+	algs, err := selectAlgorithms(c.algorithms)
+	if err != nil {
+		return err
+	}
+	path, err := workloadPath(args)
+	if err != nil {
+		return err
+	}
+	var baseline []titledResult
+	if c.baseline != "" {
+		if baseline, err = loadResults(c.baseline); err != nil {
+			return err
+		}
+	}
+	processes, err := c.load(path)
+	if err != nil {
+		return err
+	}
+
+	algs = forWorkload(algs, c.algorithms, processes)
+	jobs := make([]job, 0, len(algs))
+	for _, alg := range algs {
+		progress, err := c.progressOption(alg.name)
+		if err != nil {
+			return err
+		}
+		opts, err := c.options(scheduler.WithSeed(c.seed.value), scheduler.WithMetricsOnly(), progress)
+		if err != nil {
+			return err
+		}
+		jobs = append(jobs, job{alg: alg, opts: opts})
+	}
+	scheduleAll(ctx, c.resultCache(), jobs, processes)
+	for _, j := range jobs {
+		if j.err != nil {
+			return j.err
+		}
+	}
+
+	writeMarkdown(w, filepath.Base(path), c.seed.value, jobs, baseline, c.baseline)
+	return nil
+}
+
+// titledResult is a schedule's result as schedule -format json writes it.
+type titledResult struct {
+	Title string `json:"title"`
+	*scheduler.ScheduleResult
+}
+
+// loadResults reads the results in a file written by schedule -format json.
+func loadResults(path string) ([]titledResult, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("%w: error opening results", err)
+	}
+	defer func() {
+		if err := f.Close(); err != nil {
+			log.Print(err)
+		}
+	}()
+
+	var results []titledResult
+	d := json.NewDecoder(f)
+	for {
+		var r titledResult
+		err := d.Decode(&r)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil || r.ScheduleResult == nil {
+			if err == nil {
+				err = errors.New("not a result")
+			}
+			return nil, &fileError{path: path, err: &scheduler.ParseError{Err: fmt.Errorf("result %d: %w", len(results)+1, err)}}
+		}
+		results = append(results, r)
+	}
+
+	return results, nil
+}
+
+// writeMarkdown writes a heading, a table of each job's metrics with their
+// change from the result of the same title in baseline, if any, and a line
+// naming the algorithms worse on any metric.
+func writeMarkdown(w io.Writer, workload string, seed int64, jobs []job, baseline []titledResult, baselineName string) {
+	_, _ = fmt.Fprintf(w, "### Scheduling results for `%s` (seed %d)\n\n", workload, seed)
+	header := []string{"Algorithm"}
+	align := []string{":--"}
+	for _, mt := range markdownMetrics {
+		header = append(header, mt.label)
+		align = append(align, "--:")
+	}
+	_, _ = fmt.Fprintf(w, "| %s |\n|%s|\n", strings.Join(header, " | "), strings.Join(align, "|"))
+
+	var regressed []string
+	seen := make(map[string]bool)
+	for _, j := range jobs {
+		m := j.r.UnitMetrics()
+		var old *scheduler.Metrics
+		for _, b := range baseline {
+			if b.Title == j.alg.title {
+				bm := b.UnitMetrics()
+				old, seen[b.Title] = &bm, true
+				break
+			}
+		}
+		name := j.alg.name
+		switch {
+		case len(j.r.Violations) > 0:
+			name += " (invalid schedule)"
+		case m.Completed+m.Killed != j.r.Total:
+			name += fmt.Sprintf(" (%d of %d completed)", m.Completed, j.r.Total)
+		case baseline != nil && old == nil:
+			name += " (new)"
+		}
+		row := []string{name}
+		worse := false
+		for _, mt := range markdownMetrics {
+			v := mt.value(m)
+			cell := markdownNumber(v)
+			// Changes too small to show are no change.
+			if old != nil {
+				if delta := math.Round((v-mt.value(*old))*100) / 100; delta != 0 {
+					verdict, sign := "better", ""
+					if !better(mt, delta, 0) {
+						verdict, worse = "**worse**", true
+					}
+					if delta > 0 {
+						sign = "+"
+					}
+					cell += fmt.Sprintf(" (%s%s, %s)", sign, markdownNumber(delta), verdict)
+				}
+			}
+			row = append(row, cell)
+		}
+		if worse {
+			regressed = append(regressed, j.alg.name)
+		}
+		_, _ = fmt.Fprintf(w, "| %s |\n", strings.Join(row, " | "))
+	}
+	if baseline == nil {
+		return
+	}
+
+	_, _ = fmt.Fprintln(w)
+	if len(regressed) == 0 {
+		_, _ = fmt.Fprintf(w, "No regressions from `%s`.\n", filepath.Base(baselineName))
+	} else {
+		_, _ = fmt.Fprintf(w, "**Worse than `%s`:** %s.\n", filepath.Base(baselineName), joinList(regressed, "and"))
+	}
+	var missing []string
+	for _, b := range baseline {
+		if !seen[b.Title] {
+			missing = append(missing, b.Title)
+		}
+	}
+	if len(missing) > 0 {
+		_, _ = fmt.Fprintf(w, "Not run, but in the baseline: %s.\n", joinList(missing, "and"))
+	}
+}
+
+// markdownNumber formats v to at most two decimals, without trailing zeros.
+func markdownNumber(v float64) string {
+	return strconv.FormatFloat(math.Round(v*100)/100, 'f', -1, 64)
+}
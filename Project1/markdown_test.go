@@ -0,0 +1,45 @@
+package main
+
+import (
+	"context"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func Test_runMarkdown(t *testing.T) {
+	t.Parallel()
+	base := filepath.Join(t.TempDir(), "main.json")
+	if err := run(context.Background(), io.Discard, "binary_name", "-output", base, "schedule", "-format", "json", "example_processes.csv"); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name     string
+		args     []string
+		want     []string
+		wantCode int
+	}{
+		{name: "no baseline", args: []string{"markdown", "-algorithms", "fcfs", "example_processes.csv"}, want: []string{"### Scheduling results for `example_processes.csv`", "| fcfs | 3.33 | 10 | 0.15 | 20 | 2 |"}, wantCode: ExitOK},
+		{name: "unchanged", args: []string{"markdown", "-algorithms", "fcfs,sjf", "-baseline", base, "example_processes.csv"}, want: []string{"| fcfs | 3.33 | 10 |", "No regressions from `main.json`.", "Not run, but in the baseline:"}, wantCode: ExitOK},
+		{name: "regressed", args: []string{"markdown", "-algorithms", "fcfs", "-switch-cost", "1", "-baseline", base, "example_processes.csv"}, want: []string{"| fcfs | 4.33 (+1, **worse**) |", "**Worse than `main.json`:** fcfs."}, wantCode: ExitOK},
+		{name: "missing baseline", args: []string{"markdown", "-baseline", "missing.json", "example_processes.csv"}, wantCode: ExitFileNotFound},
+		{name: "invalid baseline", args: []string{"markdown", "-baseline", "example_processes.csv", "example_processes.csv"}, wantCode: ExitParse},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var w strings.Builder
+			err := run(context.Background(), &w, append([]string{"binary_name"}, tt.args...)...)
+			if code := exitCode(err); code != tt.wantCode {
+				t.Fatalf("run() error = %v, exit code %d, want %d", err, code, tt.wantCode)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(w.String(), want) {
+					t.Errorf("markdown wrote:\n%s\nwant it to contain %q", w.String(), want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/rks0134/CSCE4600/Project1/memory"
+	"github.com/rks0134/CSCE4600/Project1/scheduler"
+)
+
+// memoryCmd compares contiguous memory allocation algorithms on a trace.
+type memoryCmd struct {
+	*globalFlags
+	size       int
+	unit       int
+	minBlock   int
+	algorithms string
+	generate   int
+}
+
+func (c *memoryCmd) defineFlags(flags *flag.FlagSet) {
+	flags.IntVar(&c.size, "size", 1024, "`units` of memory, a power of two for the buddy system")
+	flags.IntVar(&c.unit, "unit", 1, "`units` first, best, and worst fit round each request up to a multiple of")
+	flags.IntVar(&c.minBlock, "min-block", 16, "smallest block, in `units`, the buddy system splits memory into")
+	flags.StringVar(&c.algorithms, "algorithms", "all", "comma separated `algorithms` to compare (first, best, worst, buddy), or all")
+	flags.IntVar(&c.generate, "generate", 0, "generate a trace of `n` requests and releases instead of reading one (reproducible with -seed)")
+}
+
+func (c *memoryCmd) run(_ context.Context, w io.Writer, args []string) error {
+	var algs []memory.Algorithm
+	if c.algorithms == "all" {
+		algs = memory.Algorithms
+	} else {
+		for _, name := range strings.Split(c.algorithms, ",") {
+			alg, ok := memory.Find(strings.TrimSpace(name))
+			if !ok {
+				return fmt.Errorf("%w: unknown memory allocation algorithm %q", ErrInvalidArgs, name)
+			}
+			algs = append(algs, alg)
+		}
+	}
+	cfg := memory.Config{Size: c.size, Unit: c.unit, MinBlock: c.minBlock}
+	for _, alg := range algs {
+		if err := cfg.Validate(alg); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidArgs, err)
+		}
+	}
+
+	var trace []memory.Op
+	switch {
+	case c.generate > 0:
+		if len(args) > 0 {
+			return fmt.Errorf("%w: -generate takes no trace file", ErrInvalidArgs)
+		}
+		trace = memory.Generate(c.rand(), c.generate, c.size)
+	case len(args) == 1:
+		if err := readInputFile(args[0], func(r io.Reader) (err error) {
+			trace, err = memory.ParseTrace(r)
+			return err
+		}); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("%w: must give a trace file, or -generate", ErrInvalidArgs)
+	}
+
+	var results []*memory.Result
+	for _, alg := range algs {
+		r, err := memory.Simulate(alg, cfg, trace)
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidArgs, err)
+		}
+		results = append(results, r)
+	}
+
+	scheduler.RenderTitle(w, fmt.Sprintf("Memory allocation, %d operations", len(trace)))
+	if c.generate > 0 {
+		_, _ = fmt.Fprintf(w, "Seed: %d\n\n", c.seed.value)
+	}
+	_, _ = fmt.Fprintf(w, "%d units of memory; fits allocate in units of %d, the buddy system in blocks of at least %d\n\n", c.size, c.unit, c.minBlock)
+	marks := memory.Marks(trace)
+	for _, r := range results {
+		memory.RenderMap(w, r, marks)
+	}
+	memory.RenderComparison(w, results)
+
+	return nil
+}
@@ -0,0 +1,468 @@
+// Package memory simulates contiguous memory allocation: where each
+// algorithm places the blocks a trace of requests and releases asks for,
+// and how fragmented memory becomes as it does.
+package memory
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"strconv"
+	"strings"
+
+	"github.com/rks0134/CSCE4600/Project1/scheduler"
+)
+
+// Algorithm is a contiguous memory allocation algorithm.
+type Algorithm struct {
+	Name  string
+	Title string
+}
+
+// Algorithms are the memory allocation algorithms, in the order reports
+// list them.
+var Algorithms = []Algorithm{
+	{Name: "first", Title: "First fit"},
+	{Name: "best", Title: "Best fit"},
+	{Name: "worst", Title: "Worst fit"},
+	{Name: "buddy", Title: "Buddy system"},
+}
+
+// Find returns the algorithm named name.
+func Find(name string) (Algorithm, bool) {
+	for _, a := range Algorithms {
+		if a.Name == name {
+			return a, true
+		}
+	}
+	return Algorithm{}, false
+}
+
+// Config is the memory to allocate from.
+type Config struct {
+	// Size is how many units of memory there are, addressed from 0.
+	Size int
+	// Unit is the granularity first, best, and worst fit allocate in, each
+	// request rounded up to a multiple of it.
+	Unit int
+	// MinBlock is the smallest block the buddy system splits memory into.
+	// It and Size must be powers of two.
+	MinBlock int
+}
+
+// Op is an operation of a trace: a request for Size units for Name, or,
+// with Release, freeing the block Name was given.
+type Op struct {
+	Name    string
+	Size    int
+	Release bool
+}
+
+func (o Op) String() string {
+	if o.Release {
+		return "free " + o.Name
+	}
+	return fmt.Sprintf("%s %d", o.Name, o.Size)
+}
+
+// Block is a contiguous span of memory, free if Owner is empty. Used is how
+// much of an allocated block its owner asked for, the rest being internal
+// fragmentation.
+type Block struct {
+	Start, Size int
+	Owner       string
+	Used        int
+}
+
+// Free is whether b belongs to no one.
+func (b Block) Free() bool { return b.Owner == "" }
+
+// Step is memory after an operation of a trace.
+type Step struct {
+	Op Op
+	// Address is where a request was placed, or -1 if it failed or the
+	// operation was a release.
+	Address int
+	// Blocks are every block of memory, free or not, in address order.
+	Blocks []Block
+	// Free sums the free blocks and Largest is the largest of them.
+	Free, Largest int
+	// Internal sums the space allocated blocks hold beyond what their owners
+	// asked for.
+	Internal int
+}
+
+// External is the fraction of free memory outside the largest free block,
+// which no request can be given at once: 0 when the free memory is one
+// block, or there is none, toward 1 as it splinters.
+func (s Step) External() float64 {
+	if s.Free == 0 {
+		return 0
+	}
+	return 1 - float64(s.Largest)/float64(s.Free)
+}
+
+// Result is an algorithm's run over a trace.
+type Result struct {
+	Algorithm Algorithm
+	Config    Config
+	Steps     []Step
+	// Failed counts the requests that found no block large enough, and
+	// Fragmented those of them that would have fit in the free memory as a
+	// whole.
+	Failed, Fragmented int
+}
+
+// MeanExternal is the mean external fragmentation after each operation.
+func (r *Result) MeanExternal() float64 {
+	if len(r.Steps) == 0 {
+		return 0
+	}
+	var sum float64
+	for _, s := range r.Steps {
+		sum += s.External()
+	}
+	return sum / float64(len(r.Steps))
+}
+
+// PeakExternal is the most external fragmentation after any operation.
+func (r *Result) PeakExternal() float64 {
+	peak := 0.0
+	for _, s := range r.Steps {
+		if e := s.External(); e > peak {
+			peak = e
+		}
+	}
+	return peak
+}
+
+// PeakInternal is the most internal fragmentation after any operation.
+func (r *Result) PeakInternal() int {
+	peak := 0
+	for _, s := range r.Steps {
+		if s.Internal > peak {
+			peak = s.Internal
+		}
+	}
+	return peak
+}
+
+func isPowerOfTwo(n int) bool { return n > 0 && n&(n-1) == 0 }
+
+// Validate returns an error if c cannot be allocated from with alg.
+func (c Config) Validate(alg Algorithm) error {
+	switch {
+	case c.Size < 1:
+		return fmt.Errorf("need a positive size of memory, not %d", c.Size)
+	case alg.Name == "buddy" && !isPowerOfTwo(c.Size):
+		return fmt.Errorf("the buddy system needs a size of memory that is a power of two, not %d", c.Size)
+	case alg.Name == "buddy" && (!isPowerOfTwo(c.MinBlock) || c.MinBlock > c.Size):
+		return fmt.Errorf("the buddy system needs a smallest block that is a power of two up to the size of memory, not %d", c.MinBlock)
+	case alg.Name != "buddy" && c.Unit < 1:
+		return fmt.Errorf("need a positive unit of allocation, not %d", c.Unit)
+	}
+	return nil
+}
+
+// Simulate runs trace against memory of cfg allocated by alg:
+//
+//   - first fit places a request in the lowest free block large enough;
+//   - best fit in the smallest, leaving the smallest leftover;
+//   - worst fit in the largest, leaving the largest leftover;
+//   - buddy rounds a request up to a power of two, at least cfg.MinBlock,
+//     and halves the smallest free block large enough until it fits,
+//     merging a freed block with its buddy whenever both are free.
+//
+// First, best, and worst fit split off what a block does not need as a new
+// free block, merging free neighbors on release. Ties go to the lowest
+// address. A request that fits nowhere fails, and releasing it does
+// nothing.
+func Simulate(alg Algorithm, cfg Config, trace []Op) (*Result, error) {
+	if err := cfg.Validate(alg); err != nil {
+		return nil, err
+	}
+	var place func(blocks []Block, size int) ([]Block, int)
+	switch alg.Name {
+	case "first", "best", "worst":
+		place = func(blocks []Block, size int) ([]Block, int) {
+			return fit(alg.Name, blocks, roundUp(size, cfg.Unit))
+		}
+	case "buddy":
+		place = func(blocks []Block, size int) ([]Block, int) {
+			return buddy(blocks, size, cfg.MinBlock)
+		}
+	default:
+		return nil, fmt.Errorf("unknown algorithm %q", alg.Name)
+	}
+
+	r := &Result{Algorithm: alg, Config: cfg}
+	blocks := []Block{{Start: 0, Size: cfg.Size}}
+	for _, op := range trace {
+		step := Step{Op: op, Address: -1}
+		if op.Release {
+			blocks = release(blocks, op.Name, alg.Name == "buddy")
+		} else {
+			var at int
+			if blocks, at = place(blocks, op.Size); at >= 0 {
+				blocks[at].Owner, blocks[at].Used = op.Name, op.Size
+				step.Address = blocks[at].Start
+			} else {
+				r.Failed++
+				if free(blocks) >= op.Size {
+					r.Fragmented++
+				}
+			}
+		}
+		step.Blocks = append([]Block(nil), blocks...)
+		for _, b := range blocks {
+			if b.Free() {
+				step.Free += b.Size
+				if b.Size > step.Largest {
+					step.Largest = b.Size
+				}
+			} else {
+				step.Internal += b.Size - b.Used
+			}
+		}
+		r.Steps = append(r.Steps, step)
+	}
+	return r, nil
+}
+
+func roundUp(n, unit int) int {
+	return (n + unit - 1) / unit * unit
+}
+
+func free(blocks []Block) int {
+	total := 0
+	for _, b := range blocks {
+		if b.Free() {
+			total += b.Size
+		}
+	}
+	return total
+}
+
+// fit picks the free block alg places size units in, splitting off the
+// rest, and returns the blocks and the index of the one picked, or -1.
+func fit(alg string, blocks []Block, size int) ([]Block, int) {
+	at := -1
+	for i, b := range blocks {
+		if !b.Free() || b.Size < size {
+			continue
+		}
+		switch {
+		case at < 0,
+			alg == "best" && b.Size < blocks[at].Size,
+			alg == "worst" && b.Size > blocks[at].Size:
+			at = i
+		}
+		if alg == "first" {
+			break
+		}
+	}
+	if at < 0 {
+		return blocks, -1
+	}
+	return split(blocks, at, size), at
+}
+
+// split cuts blocks[at] down to size, the rest following it as a free
+// block.
+func split(blocks []Block, at, size int) []Block {
+	b := blocks[at]
+	if b.Size == size {
+		return blocks
+	}
+	blocks = append(blocks, Block{})
+	copy(blocks[at+2:], blocks[at+1:])
+	blocks[at] = Block{Start: b.Start, Size: size}
+	blocks[at+1] = Block{Start: b.Start + size, Size: b.Size - size}
+	return blocks
+}
+
+// buddy halves the smallest free block that holds size until halving again
+// would not, and returns the blocks and the index of the one left, or -1.
+func buddy(blocks []Block, size, minBlock int) ([]Block, int) {
+	want := minBlock
+	for want < size {
+		want *= 2
+	}
+	at := -1
+	for i, b := range blocks {
+		if b.Free() && b.Size >= want && (at < 0 || b.Size < blocks[at].Size) {
+			at = i
+		}
+	}
+	if at < 0 {
+		return blocks, -1
+	}
+	for blocks[at].Size > want {
+		blocks = split(blocks, at, blocks[at].Size/2)
+	}
+	return blocks, at
+}
+
+// release frees name's block, merging it with the free blocks beside it, or
+// for the buddy system with its free buddy of the same size for as long as
+// there is one.
+func release(blocks []Block, name string, buddies bool) []Block {
+	at := -1
+	for i, b := range blocks {
+		if b.Owner == name {
+			at = i
+			break
+		}
+	}
+	if at < 0 {
+		return blocks
+	}
+	blocks[at].Owner, blocks[at].Used = "", 0
+	merge := func(i int) {
+		blocks[i].Size += blocks[i+1].Size
+		blocks = append(blocks[:i+1], blocks[i+2:]...)
+	}
+	if !buddies {
+		if at+1 < len(blocks) && blocks[at+1].Free() {
+			merge(at)
+		}
+		if at > 0 && blocks[at-1].Free() {
+			merge(at - 1)
+		}
+		return blocks
+	}
+	for {
+		b := blocks[at]
+		// A block's buddy is the other half of the block it was split
+		// from, found by flipping the bit of its size in its address.
+		if b.Start^b.Size < b.Start {
+			if at > 0 && blocks[at-1].Free() && blocks[at-1].Size == b.Size {
+				at--
+				merge(at)
+				continue
+			}
+		} else if at+1 < len(blocks) && blocks[at+1].Free() && blocks[at+1].Size == b.Size {
+			merge(at)
+			continue
+		}
+		return blocks
+	}
+}
+
+// ParseTrace reads a trace of requests and releases, one to a line:
+//
+//	A 212
+//	B 417
+//	free A
+//
+// with comments from # to the end of a line. A name may be requested again
+// once freed, but not while it holds a block, and only a name holding one
+// may be freed.
+func ParseTrace(r io.Reader) ([]Op, error) {
+	var trace []Op
+	live := make(map[string]bool)
+	s := bufio.NewScanner(r)
+	line := 0
+	fail := func(format string, args ...interface{}) error {
+		return &scheduler.ParseError{Line: line, Err: fmt.Errorf(format, args...)}
+	}
+	for s.Scan() {
+		line++
+		text, _, _ := strings.Cut(s.Text(), "#")
+		fields := strings.Fields(text)
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) != 2 {
+			return nil, fail("want a request, such as A 212, or a release, such as free A, not %q", strings.TrimSpace(text))
+		}
+		if fields[0] == "free" {
+			if !live[fields[1]] {
+				return nil, fail("%s is freed but holds no block", fields[1])
+			}
+			live[fields[1]] = false
+			trace = append(trace, Op{Name: fields[1], Release: true})
+			continue
+		}
+		size, err := strconv.Atoi(fields[1])
+		if err != nil || size < 1 {
+			return nil, fail("invalid size %q", fields[1])
+		}
+		if live[fields[0]] {
+			return nil, fail("%s is requested again before being freed", fields[0])
+		}
+		live[fields[0]] = true
+		trace = append(trace, Op{Name: fields[0], Size: size})
+	}
+	if err := s.Err(); err != nil {
+		return nil, &scheduler.ParseError{Err: err}
+	}
+	if len(trace) == 0 {
+		return nil, &scheduler.ParseError{Err: errors.New("no requests")}
+	}
+	return trace, nil
+}
+
+// Generate returns a trace of n operations on memory of size units: while
+// any block is held, two in five free one of them at random, and the rest
+// request from 1/32 to 1/4 of memory, each for a new name from A to Z and
+// then on to AA, AB, and so on.
+func Generate(rng *rand.Rand, n, size int) []Op {
+	lo, hi := size/32, size/4
+	if lo < 1 {
+		lo = 1
+	}
+	if hi < lo {
+		hi = lo
+	}
+	trace := make([]Op, 0, n)
+	var live []string
+	for requested := 0; len(trace) < n; {
+		if len(live) > 0 && rng.Intn(5) < 2 {
+			k := rng.Intn(len(live))
+			trace = append(trace, Op{Name: live[k], Release: true})
+			live = append(live[:k], live[k+1:]...)
+			continue
+		}
+		name := ""
+		for k := requested; ; k = k/26 - 1 {
+			name = string(rune('A'+k%26)) + name
+			if k < 26 {
+				break
+			}
+		}
+		requested++
+		trace = append(trace, Op{Name: name, Size: lo + rng.Intn(hi-lo+1)})
+		live = append(live, name)
+	}
+	return trace
+}
+
+// Marks returns the character each name requested in trace is drawn with:
+// the name itself if it is a single letter or digit, or else the next
+// letter or digit no name has.
+func Marks(trace []Op) map[string]byte {
+	const pool = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789"
+	marks := make(map[string]byte)
+	taken := make(map[byte]bool)
+	for _, op := range trace {
+		if len(op.Name) == 1 && strings.Contains(pool, op.Name) {
+			marks[op.Name], taken[op.Name[0]] = op.Name[0], true
+		}
+	}
+	for _, op := range trace {
+		if _, ok := marks[op.Name]; ok || op.Release {
+			continue
+		}
+		marks[op.Name] = '@'
+		for k := 0; k < len(pool); k++ {
+			if !taken[pool[k]] {
+				marks[op.Name], taken[pool[k]] = pool[k], true
+				break
+			}
+		}
+	}
+	return marks
+}
@@ -0,0 +1,225 @@
+package memory
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/rks0134/CSCE4600/Project1/scheduler"
+)
+
+// holes leaves free blocks of 30 units at 10, 12 at 50, and 56 at 72 in
+// 128 units of memory.
+var holes = []Op{
+	{Name: "A", Size: 10},
+	{Name: "B", Size: 30},
+	{Name: "C", Size: 10},
+	{Name: "D", Size: 12},
+	{Name: "E", Size: 10},
+	{Name: "B", Release: true},
+	{Name: "D", Release: true},
+}
+
+func TestSimulateFits(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		alg  string
+		want int
+	}{
+		{alg: "first", want: 10},
+		{alg: "best", want: 50},
+		{alg: "worst", want: 72},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.alg, func(t *testing.T) {
+			t.Parallel()
+			alg, ok := Find(tt.alg)
+			if !ok {
+				t.Fatalf("Find(%q) found nothing", tt.alg)
+			}
+			trace := append(append([]Op(nil), holes...), Op{Name: "F", Size: 11}, Op{Name: "G", Size: 60})
+			r, err := Simulate(alg, Config{Size: 128, Unit: 1}, trace)
+			if err != nil {
+				t.Fatalf("Simulate() error = %v", err)
+			}
+			f, g := r.Steps[len(holes)], r.Steps[len(holes)+1]
+			if f.Address != tt.want {
+				t.Errorf("Simulate() placed F at %d, want %d", f.Address, tt.want)
+			}
+			if g.Address != -1 || r.Failed != 1 || r.Fragmented != 1 {
+				t.Errorf("Simulate() placed G at %d with %d failed, %d fragmented, want it to fail for fragmentation", g.Address, r.Failed, r.Fragmented)
+			}
+			covered := 0
+			for _, b := range g.Blocks {
+				if b.Start != covered {
+					t.Fatalf("Simulate() blocks %v leave a gap at %d", g.Blocks, covered)
+				}
+				covered += b.Size
+			}
+			if covered != 128 {
+				t.Errorf("Simulate() blocks %v cover %d units, want 128", g.Blocks, covered)
+			}
+		})
+	}
+}
+
+func TestStepExternal(t *testing.T) {
+	t.Parallel()
+	alg, _ := Find("first")
+	r, err := Simulate(alg, Config{Size: 128, Unit: 1}, holes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := r.Steps[len(r.Steps)-1]
+	if s.Free != 98 || s.Largest != 56 {
+		t.Errorf("free = %d, largest = %d, want 98 and 56", s.Free, s.Largest)
+	}
+	if got, want := s.External(), 42.0/98; math.Abs(got-want) > 1e-9 {
+		t.Errorf("External() = %v, want %v", got, want)
+	}
+	if got := r.Steps[0].External(); got != 0 {
+		t.Errorf("External() with one free block = %v, want 0", got)
+	}
+	if got := (Step{}).External(); got != 0 {
+		t.Errorf("External() with nothing free = %v, want 0", got)
+	}
+}
+
+func TestSimulateUnit(t *testing.T) {
+	t.Parallel()
+	alg, _ := Find("best")
+	r, err := Simulate(alg, Config{Size: 100, Unit: 8}, []Op{{Name: "A", Size: 13}, {Name: "B", Size: 16}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := r.Steps[1]; got.Address != 16 || got.Internal != 3 {
+		t.Errorf("Simulate() placed B at %d with %d internal, want 16 and 3", got.Address, got.Internal)
+	}
+}
+
+func TestSimulateBuddy(t *testing.T) {
+	t.Parallel()
+	alg, _ := Find("buddy")
+	// The textbook's example: 21 KB of a 256 KB segment is given 32 KB.
+	trace := []Op{{Name: "A", Size: 21}, {Name: "B", Size: 60}, {Name: "A", Release: true}, {Name: "B", Release: true}}
+	r, err := Simulate(alg, Config{Size: 256, MinBlock: 1}, trace)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Block{
+		{Start: 0, Size: 32, Owner: "A", Used: 21},
+		{Start: 32, Size: 32},
+		{Start: 64, Size: 64},
+		{Start: 128, Size: 128},
+	}
+	if got := r.Steps[0].Blocks; !reflect.DeepEqual(got, want) {
+		t.Errorf("Simulate() blocks = %v, want %v", got, want)
+	}
+	if got := r.Steps[0]; got.Internal != 11 || got.Largest != 128 {
+		t.Errorf("Simulate() internal = %d, largest = %d, want 11 and 128", got.Internal, got.Largest)
+	}
+	if got := r.Steps[1].Address; got != 64 {
+		t.Errorf("Simulate() placed B at %d, want 64", got)
+	}
+	// Freeing A leaves it beside a free buddy, but B holds their parent's
+	// buddy, so they merge only to 64 units.
+	if got := r.Steps[2].Blocks; len(got) != 3 || got[0].Size != 64 {
+		t.Errorf("Simulate() blocks after freeing A = %v, want A merged with its buddy", got)
+	}
+	if got := r.Steps[3].Blocks; !reflect.DeepEqual(got, []Block{{Size: 256}}) {
+		t.Errorf("Simulate() blocks after freeing everything = %v, want one free block", got)
+	}
+
+	if _, err := Simulate(alg, Config{Size: 100, MinBlock: 4}, trace); err == nil {
+		t.Error("Simulate() of 100 units with the buddy system succeeded, want an error")
+	}
+	if _, err := Simulate(alg, Config{Size: 256, MinBlock: 3}, trace); err == nil {
+		t.Error("Simulate() with a smallest block of 3 succeeded, want an error")
+	}
+}
+
+func TestSimulateReleaseFailed(t *testing.T) {
+	t.Parallel()
+	alg, _ := Find("first")
+	r, err := Simulate(alg, Config{Size: 10, Unit: 1}, []Op{{Name: "A", Size: 20}, {Name: "A", Release: true}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Failed != 1 || r.Fragmented != 0 || !reflect.DeepEqual(r.Steps[1].Blocks, []Block{{Size: 10}}) {
+		t.Errorf("Simulate() = %+v, want the request to fail and its release do nothing", r)
+	}
+}
+
+func TestParseTrace(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name     string
+		in       string
+		want     []Op
+		wantLine int
+	}{
+		{
+			name: "trace",
+			in:   "A 212 # a request\nB 417\n\nfree A\nA 8\n",
+			want: []Op{{Name: "A", Size: 212}, {Name: "B", Size: 417}, {Name: "A", Release: true}, {Name: "A", Size: 8}},
+		},
+		{name: "bad size", in: "A 212\nB x\n", wantLine: 2},
+		{name: "zero size", in: "A 0\n", wantLine: 1},
+		{name: "too many fields", in: "A 1 2\n", wantLine: 1},
+		{name: "free unknown", in: "A 1\nfree B\n", wantLine: 2},
+		{name: "free twice", in: "A 1\nfree A\nfree A\n", wantLine: 3},
+		{name: "request twice", in: "A 1\nA 2\n", wantLine: 2},
+		{name: "empty", in: "# nothing\n"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := ParseTrace(strings.NewReader(tt.in))
+			if tt.want != nil {
+				if err != nil || !reflect.DeepEqual(got, tt.want) {
+					t.Errorf("ParseTrace() = %v, %v, want %v", got, err, tt.want)
+				}
+				return
+			}
+			var perr *scheduler.ParseError
+			if !errors.As(err, &perr) || perr.Line != tt.wantLine {
+				t.Errorf("ParseTrace() error = %v, want a parse error on line %d", err, tt.wantLine)
+			}
+		})
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	t.Parallel()
+	trace := Generate(rand.New(rand.NewSource(1)), 200, 1024)
+	if len(trace) != 200 {
+		t.Fatalf("Generate() made %d operations, want 200", len(trace))
+	}
+	live := make(map[string]bool)
+	for _, op := range trace {
+		switch {
+		case op.Release && !live[op.Name]:
+			t.Fatalf("Generate() frees %s, which holds no block", op.Name)
+		case !op.Release && (live[op.Name] || op.Size < 32 || op.Size > 256):
+			t.Fatalf("Generate() requests %v, want a new name and 32 to 256 units", op)
+		}
+		live[op.Name] = !op.Release
+	}
+	if !reflect.DeepEqual(Generate(rand.New(rand.NewSource(1)), 200, 1024), trace) {
+		t.Error("Generate() with the same seed made a different trace")
+	}
+}
+
+func TestMarks(t *testing.T) {
+	t.Parallel()
+	got := Marks([]Op{{Name: "P1", Size: 1}, {Name: "A", Size: 1}, {Name: "P2", Size: 1}, {Name: "P1", Release: true}})
+	want := map[string]byte{"A": 'A', "P1": 'B', "P2": 'C'}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Marks() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,102 @@
+package memory
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+)
+
+// mapWidth is the most columns a memory map spans memory with.
+const mapWidth = 60
+
+// RenderMap writes memory after each operation of r as a map, a row per
+// operation, each column showing what holds the address at its middle:
+// the mark of the name holding it, '+' for space its holder did not ask
+// for, or '.' if free. Blocks narrower than a column may not show. Each row
+// ends with the external and internal fragmentation, and whether the
+// request failed.
+func RenderMap(w io.Writer, r *Result, marks map[string]byte) {
+	requests := 0
+	for _, s := range r.Steps {
+		if !s.Op.Release {
+			requests++
+		}
+	}
+	_, _ = fmt.Fprintf(w, "%s: %d of %d requests failed, %d with enough memory free in all\n", r.Algorithm.Title, r.Failed, requests, r.Fragmented)
+	width := r.Config.Size
+	if width > mapWidth {
+		width = mapWidth
+	}
+	labelWidth := 0
+	for _, s := range r.Steps {
+		if n := len(s.Op.String()); n > labelWidth {
+			labelWidth = n
+		}
+	}
+	label := strconv.Itoa(r.Config.Size - 1)
+	_, _ = fmt.Fprintf(w, "%-*s 0%s%s  %8s %8s\n", labelWidth, "", strings.Repeat(" ", width-len(label)), label, "External", "Internal")
+	for _, s := range r.Steps {
+		row := make([]byte, width)
+		k := 0
+		for c := range row {
+			at := int((float64(c) + 0.5) * float64(r.Config.Size) / float64(width))
+			for s.Blocks[k].Start+s.Blocks[k].Size <= at {
+				k++
+			}
+			switch b := s.Blocks[k]; {
+			case b.Free():
+				row[c] = '.'
+			case at-b.Start < b.Used:
+				row[c] = marks[b.Owner]
+			default:
+				row[c] = '+'
+			}
+		}
+		status := ""
+		if !s.Op.Release && s.Address < 0 {
+			status = " failed"
+		}
+		_, _ = fmt.Fprintf(w, "%-*s |%s| %7.2f%% %8d%s\n", labelWidth, s.Op, row, 100*s.External(), s.Internal, status)
+	}
+
+	legend := []string{"'.' free", "'+' internal fragmentation"}
+	var renamed []string
+	for name, mark := range marks {
+		if name != string(mark) {
+			renamed = append(renamed, fmt.Sprintf("%q %s", mark, name))
+		}
+	}
+	sort.Strings(renamed)
+	_, _ = fmt.Fprintf(w, "%-*s %s\n\n", labelWidth, "", strings.Join(append(legend, renamed...), ", "))
+}
+
+// RenderComparison writes a table of each result's failed requests, its
+// mean and peak external fragmentation, its peak internal fragmentation,
+// and the free memory and largest free block it ended with.
+func RenderComparison(w io.Writer, results []*Result) {
+	_, _ = fmt.Fprintln(w, "Fragmentation")
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"Algorithm", "Failed", "Fragmented", "Mean external", "Peak external", "Peak internal", "Free", "Largest free"})
+	for _, r := range results {
+		var last Step
+		if len(r.Steps) > 0 {
+			last = r.Steps[len(r.Steps)-1]
+		}
+		table.Append([]string{
+			r.Algorithm.Name,
+			strconv.Itoa(r.Failed),
+			strconv.Itoa(r.Fragmented),
+			fmt.Sprintf("%.2f%%", 100*r.MeanExternal()),
+			fmt.Sprintf("%.2f%%", 100*r.PeakExternal()),
+			strconv.Itoa(r.PeakInternal()),
+			strconv.Itoa(last.Free),
+			strconv.Itoa(last.Largest),
+		})
+	}
+	table.Render()
+	_, _ = fmt.Fprintln(w)
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func Test_runMemory(t *testing.T) {
+	t.Parallel()
+	trace := filepath.Join(t.TempDir(), "trace.txt")
+	if err := os.WriteFile(trace, []byte("A 212\nB 417\nC 112\nfree A\nD 426\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	var w strings.Builder
+	if err := run(context.Background(), &w, "binary_name", "memory", "-algorithms", "worst,buddy", trace); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"Memory allocation, 5 operations",
+		"1024 units of memory; fits allocate in units of 1, the buddy system in blocks of at least 16\n",
+		"Worst fit: 1 of 4 requests failed, 1 with enough memory free in all\n",
+		"free A |............BBBBBBBBBBBBBBBBBBBBBBBBBCCCCCC.................|   42.83%        0\n",
+		"A 212  |AAAAAAAAAAAA+++.............................................|   33.33%       44\n",
+		"D 426  |...............CCCCCCC........BBBBBBBBBBBBBBBBBBBBBBBB++++++|   33.33%      111 failed\n",
+		"| buddy     |      1 |          0 |",
+	} {
+		if !strings.Contains(w.String(), want) {
+			t.Errorf("memory wrote:\n%s\nwant it to contain %q", w.String(), want)
+		}
+	}
+
+	var a, b strings.Builder
+	for _, w := range []*strings.Builder{&a, &b} {
+		if err := run(context.Background(), w, "binary_name", "-seed", "3", "memory", "-generate", "20", "-size", "256"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if a.String() != b.String() || !strings.Contains(a.String(), "Seed: 3\n") {
+		t.Errorf("memory -generate with the same seed wrote:\n%s\nand:\n%s", a.String(), b.String())
+	}
+
+	for _, args := range [][]string{
+		{"-size", "1000", trace},
+		{"-min-block", "12", trace},
+		{"-unit", "0", trace},
+		{"-algorithms", "next", trace},
+		{"-generate", "5", trace},
+		{},
+	} {
+		err := run(context.Background(), io.Discard, append([]string{"binary_name", "memory"}, args...)...)
+		if !errors.Is(err, ErrInvalidArgs) {
+			t.Errorf("memory %v error = %v, want %v", args, err, ErrInvalidArgs)
+		}
+	}
+	if err := run(context.Background(), io.Discard, "binary_name", "memory", "-size", "1000", "-algorithms", "first,best", trace); err != nil {
+		t.Errorf("memory of 1000 units without the buddy system error = %v, want none", err)
+	}
+}
@@ -0,0 +1,374 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// gcsMetadataToken is where Google Cloud machines get an access token for
+// their service account.
+const gcsMetadataToken = "http://metadata.google.internal/computeMetadata/v1/instance/service-accounts/default/token"
+
+// objectStore reads and writes objects named by s3:// and gs:// URIs
+// through the stores' HTTP APIs, so workloads and reports can live in
+// cloud storage without staging them locally.
+type objectStore struct {
+	client *http.Client
+	// s3Endpoint, when set, is an S3-compatible endpoint such as MinIO's,
+	// addressed path-style; otherwise buckets are AWS virtual hosts.
+	s3Endpoint   string
+	region       string
+	accessKey    string
+	secretKey    string
+	sessionToken string
+	// gcsEndpoint is the Cloud Storage XML API, or an emulator's.
+	gcsEndpoint string
+	// gcsToken is the OAuth access token for Cloud Storage; when empty and
+	// not emulated, one is fetched from the metadata server if there is
+	// one, and requests go unauthenticated otherwise.
+	gcsToken string
+	emulated bool
+	now      func() time.Time
+	// metadataURL is where the metadata server hands out tokens.
+	metadataURL string
+	// metadata is the token last fetched from the metadata server, kept
+	// until it expires, or its failure, kept for good, so hosts off Google
+	// Cloud wait for it only once.
+	metadata struct {
+		sync.Mutex
+		token  string
+		expiry time.Time
+		failed bool
+	}
+}
+
+// objectStoreFromEnv configures an objectStore from the environment
+// variables the AWS and Google Cloud tools use.
+func objectStoreFromEnv() *objectStore {
+	s := &objectStore{
+		client:       http.DefaultClient,
+		s3Endpoint:   os.Getenv("AWS_ENDPOINT_URL_S3"),
+		region:       os.Getenv("AWS_REGION"),
+		accessKey:    os.Getenv("AWS_ACCESS_KEY_ID"),
+		secretKey:    os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		sessionToken: os.Getenv("AWS_SESSION_TOKEN"),
+		gcsEndpoint:  "https://storage.googleapis.com",
+		gcsToken:     os.Getenv("GOOGLE_OAUTH_ACCESS_TOKEN"),
+		now:          time.Now,
+		metadataURL:  gcsMetadataToken,
+	}
+	if s.s3Endpoint == "" {
+		s.s3Endpoint = os.Getenv("AWS_ENDPOINT_URL")
+	}
+	if s.region == "" {
+		s.region = os.Getenv("AWS_DEFAULT_REGION")
+	}
+	if s.region == "" {
+		s.region = "us-east-1"
+	}
+	if host := os.Getenv("STORAGE_EMULATOR_HOST"); host != "" {
+		if !strings.Contains(host, "://") {
+			host = "http://" + host
+		}
+		s.gcsEndpoint, s.emulated = host, true
+	}
+
+	return s
+}
+
+// isObjectURI reports whether path names an object in cloud storage.
+func isObjectURI(path string) bool {
+	return strings.HasPrefix(path, "s3://") || strings.HasPrefix(path, "gs://")
+}
+
+// get returns the contents of the object at uri.
+func (s *objectStore) get(ctx context.Context, uri string) (io.ReadCloser, error) {
+	resp, err := s.do(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.Body, nil
+}
+
+// put stores body as the object at uri.
+func (s *objectStore) put(ctx context.Context, uri string, body []byte) error {
+	resp, err := s.do(ctx, http.MethodPut, uri, body)
+	if err != nil {
+		return err
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	return resp.Body.Close()
+}
+
+// do sends an authenticated request for the object at uri, failing unless
+// it succeeds. A missing object is reported as fs.ErrNotExist.
+func (s *objectStore) do(ctx context.Context, method, uri string, body []byte) (*http.Response, error) {
+	u, err := url.Parse(uri)
+	if err != nil || u.Host == "" || strings.Trim(u.Path, "/") == "" {
+		return nil, fmt.Errorf("%w: %q is not a bucket and object", ErrInvalidArgs, uri)
+	}
+	bucket, key := u.Host, strings.TrimPrefix(u.Path, "/")
+	var req *http.Request
+	switch u.Scheme {
+	case "s3":
+		req, err = s.s3Request(ctx, method, bucket, key, body)
+	case "gs":
+		req, err = s.gcsRequest(ctx, method, bucket, key, body)
+	}
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%s %s: %w", method, uri, withoutURL(err))
+	}
+	if resp.StatusCode/100 != 2 {
+		_ = resp.Body.Close()
+		if resp.StatusCode == http.StatusNotFound {
+			return nil, fmt.Errorf("%s %s: %w", method, uri, os.ErrNotExist)
+		}
+		return nil, fmt.Errorf("%s %s: %s", method, uri, resp.Status)
+	}
+
+	return resp, nil
+}
+
+// s3Request builds a request for the object key in bucket, signed with
+// AWS Signature Version 4 when there are credentials.
+func (s *objectStore) s3Request(ctx context.Context, method, bucket, key string, body []byte) (*http.Request, error) {
+	u := &url.URL{Scheme: "https", Host: bucket + ".s3." + s.region + ".amazonaws.com", Path: "/" + key}
+	if s.s3Endpoint != "" {
+		endpoint, err := url.Parse(s.s3Endpoint)
+		if err != nil {
+			return nil, fmt.Errorf("%w: S3 endpoint: %v", ErrInvalidArgs, err)
+		}
+		u = &url.URL{Scheme: endpoint.Scheme, Host: endpoint.Host, Path: strings.TrimSuffix(endpoint.Path, "/") + "/" + bucket + "/" + key}
+	}
+	u.RawPath = awsEscape(u.Path)
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	if s.accessKey == "" {
+		return req, nil
+	}
+
+	now := s.now().UTC()
+	amzDate := now.Format("20060102T150405Z")
+	payload := sha256.Sum256(body)
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", hex.EncodeToString(payload[:]))
+	headers := []string{"host", "x-amz-content-sha256", "x-amz-date"}
+	if s.sessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", s.sessionToken)
+		headers = append(headers, "x-amz-security-token")
+	}
+	var canonical strings.Builder
+	fmt.Fprintf(&canonical, "%s\n%s\n\n", method, u.RawPath)
+	for _, h := range headers {
+		v := req.Header.Get(h)
+		if h == "host" {
+			v = u.Host
+		}
+		fmt.Fprintf(&canonical, "%s:%s\n", h, v)
+	}
+	signed := strings.Join(headers, ";")
+	fmt.Fprintf(&canonical, "\n%s\n%x", signed, payload)
+
+	scope := now.Format("20060102") + "/" + s.region + "/s3/aws4_request"
+	hash := sha256.Sum256([]byte(canonical.String()))
+	toSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(hash[:])
+	signature := hmacSHA256(awsSigningKey(s.secretKey, now.Format("20060102"), s.region, "s3"), toSign)
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%x", s.accessKey, scope, signed, signature))
+
+	return req, nil
+}
+
+// awsSigningKey derives the Signature Version 4 key for a day, region, and
+// service from a secret key.
+func awsSigningKey(secret, day, region, service string) []byte {
+	k := hmacSHA256([]byte("AWS4"+secret), day)
+	k = hmacSHA256(k, region)
+	k = hmacSHA256(k, service)
+
+	return hmacSHA256(k, "aws4_request")
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+// awsEscape percent-encodes path as Signature Version 4 requires, keeping
+// only unreserved characters and slashes.
+func awsEscape(path string) string {
+	var b strings.Builder
+	for i := 0; i < len(path); i++ {
+		switch c := path[i]; {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9', strings.IndexByte("-._~/", c) >= 0:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+
+	return b.String()
+}
+
+// gcsRequest builds a request to the Cloud Storage XML API for object in
+// bucket.
+func (s *objectStore) gcsRequest(ctx context.Context, method, bucket, object string, body []byte) (*http.Request, error) {
+	u, err := url.Parse(s.gcsEndpoint)
+	if err != nil {
+		return nil, fmt.Errorf("%w: Cloud Storage endpoint: %v", ErrInvalidArgs, err)
+	}
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/" + bucket + "/" + object
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	token := s.gcsToken
+	if token == "" && !s.emulated {
+		token = s.metadataToken(ctx)
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	return req, nil
+}
+
+// metadataToken returns an access token from the Google Cloud metadata
+// server, or "" off Google Cloud. It asks again only once the last token
+// it got is about to expire.
+func (s *objectStore) metadataToken(ctx context.Context) string {
+	m := &s.metadata
+	m.Lock()
+	defer m.Unlock()
+	if m.failed || m.token != "" && s.now().Before(m.expiry) {
+		return m.token
+	}
+	token, expiresIn, err := s.fetchMetadataToken(ctx)
+	if err != nil {
+		// A request cancelled by the caller says nothing of the server.
+		m.failed = ctx.Err() == nil
+		return ""
+	}
+	// Renewed a minute early, so no request goes out with a token that
+	// expires on the way.
+	m.token, m.expiry = token, s.now().Add(time.Duration(expiresIn)*time.Second-time.Minute)
+
+	return m.token
+}
+
+// fetchMetadataToken asks the metadata server for an access token, and
+// how many seconds it lasts.
+func (s *objectStore) fetchMetadataToken(ctx context.Context) (string, int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.metadataURL, nil)
+	if err != nil {
+		return "", 0, err
+	}
+	req.Header.Set("Metadata-Flavor", "Google")
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return "", 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", 0, fmt.Errorf("metadata server: %s", resp.Status)
+	}
+	var token struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return "", 0, err
+	}
+	if token.AccessToken == "" {
+		return "", 0, errors.New("metadata server gave no token")
+	}
+
+	return token.AccessToken, token.ExpiresIn, nil
+}
+
+// downloadObject copies the object at uri to a temporary file, so it can
+// be loaded as a local scheduling file, and returns the file and a
+// function that closes and removes it.
+func downloadObject(ctx context.Context, uri string) (*os.File, func() error, error) {
+	body, err := objectStoreFromEnv().get(ctx, uri)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer body.Close()
+	f, err := os.CreateTemp("", "workload-*")
+	if err != nil {
+		return nil, nil, err
+	}
+	remove := func() error {
+		err := f.Close()
+		if removeErr := os.Remove(f.Name()); err == nil {
+			err = removeErr
+		}
+		return err
+	}
+	if _, err := io.Copy(f, body); err != nil {
+		_ = remove()
+		return nil, nil, fmt.Errorf("GET %s: %w", uri, err)
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		_ = remove()
+		return nil, nil, err
+	}
+
+	return f, remove, nil
+}
+
+// createOutput returns a writer for a report to go to path, a local file
+// or an object URI, and a function that finishes writing it given the
+// command's error. An object is stored once the command ends, whether or
+// not it failed, unless the report is empty.
+func createOutput(path string) (io.Writer, func(err error) error, error) {
+	if isObjectURI(path) {
+		var report bytes.Buffer
+		return &report, func(err error) error {
+			if err != nil && report.Len() == 0 {
+				return nil
+			}
+			// Not tied to the command's context, so an interrupted run's
+			// report is still stored.
+			return objectStoreFromEnv().put(context.Background(), path, report.Bytes())
+		}, nil
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: error creating output file", err)
+	}
+	bw := bufio.NewWriter(f)
+
+	return bw, func(error) error {
+		err := bw.Flush()
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		return err
+	}, nil
+}
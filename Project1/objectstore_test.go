@@ -0,0 +1,151 @@
+package main
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func Test_awsSigningKey(t *testing.T) {
+	t.Parallel()
+	// The example from the AWS Signature Version 4 documentation.
+	got := hex.EncodeToString(awsSigningKey("wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY", "20120215", "us-east-1", "iam"))
+	if want := "f4780e2d9f65fa895f9c67b32ce1baf0b0d8a43505a000a1a9e090d414db404d"; got != want {
+		t.Errorf("awsSigningKey() = %s, want %s", got, want)
+	}
+	if got, want := awsEscape("/bucket/runs/a b+c.csv"), "/bucket/runs/a%20b%2Bc.csv"; got != want {
+		t.Errorf("awsEscape() = %s, want %s", got, want)
+	}
+}
+
+// fakeObjectStore serves objects by path from memory, recording the
+// Authorization header of each request.
+func fakeObjectStore(t *testing.T) (*httptest.Server, map[string][]byte, *[]string) {
+	t.Helper()
+	var mu sync.Mutex
+	objects := make(map[string][]byte)
+	var auth []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		auth = append(auth, r.Header.Get("Authorization"))
+		switch r.Method {
+		case http.MethodGet:
+			data, ok := objects[r.URL.Path]
+			if !ok {
+				http.NotFound(w, r)
+				return
+			}
+			_, _ = w.Write(data)
+		case http.MethodPut:
+			data, err := io.ReadAll(r.Body)
+			if err != nil {
+				t.Error(err)
+			}
+			objects[r.URL.Path] = data
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv, objects, &auth
+}
+
+func Test_runObjectStorage(t *testing.T) {
+	s3, s3Objects, s3Auth := fakeObjectStore(t)
+	gcs, gcsObjects, gcsAuth := fakeObjectStore(t)
+	t.Setenv("AWS_ENDPOINT_URL_S3", s3.URL)
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKIDEXAMPLE")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+	t.Setenv("STORAGE_EMULATOR_HOST", strings.TrimPrefix(gcs.URL, "http://"))
+	t.Setenv("GOOGLE_OAUTH_ACCESS_TOKEN", "token")
+	csv, err := os.ReadFile("example_processes.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s3Objects["/workloads/runs/example.csv"] = csv
+
+	if err := run(context.Background(), io.Discard, "binary_name", "-seed", "1", "-output", "gs://reports/example.txt", "schedule", "s3://workloads/runs/example.csv"); err != nil {
+		t.Fatal(err)
+	}
+	var want strings.Builder
+	if err := run(context.Background(), &want, "binary_name", "-seed", "1", "schedule", "example_processes.csv"); err != nil {
+		t.Fatal(err)
+	}
+	if got := string(gcsObjects["/reports/example.txt"]); got != want.String() {
+		t.Errorf("report stored in Cloud Storage = %q, want %q", got, want.String())
+	}
+	if len(*s3Auth) != 1 || !strings.HasPrefix((*s3Auth)[0], "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/") {
+		t.Errorf("S3 requests were authorized with %q, want a signature", *s3Auth)
+	}
+	if len(*gcsAuth) != 1 || (*gcsAuth)[0] != "Bearer token" {
+		t.Errorf("Cloud Storage requests were authorized with %q, want the token", *gcsAuth)
+	}
+
+	err = run(context.Background(), io.Discard, "binary_name", "schedule", "s3://workloads/missing.csv")
+	if got := exitCode(err); got != ExitFileNotFound {
+		t.Errorf("schedule of a missing object exit code = %v (%v), want %v", got, err, ExitFileNotFound)
+	}
+
+	path := filepath.Join(t.TempDir(), "report.txt")
+	if err := run(context.Background(), io.Discard, "binary_name", "-seed", "1", "-output", path, "schedule", "example_processes.csv"); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := os.ReadFile(path); err != nil || string(got) != want.String() {
+		t.Errorf("report written to %s = %q, %v, want %q", path, got, err, want.String())
+	}
+}
+
+func Test_metadataToken(t *testing.T) {
+	t.Parallel()
+	var mu sync.Mutex
+	var asked []string
+	token, status := "first", http.StatusOK
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		asked = append(asked, r.Header.Get("Metadata-Flavor"))
+		w.WriteHeader(status)
+		_, _ = fmt.Fprintf(w, `{"access_token":%q,"expires_in":3600}`, token)
+	}))
+	defer srv.Close()
+	now := time.Unix(0, 0)
+	s := &objectStore{client: srv.Client(), metadataURL: srv.URL, now: func() time.Time { return now }}
+
+	for _, want := range []string{"first", "first"} {
+		if got := s.metadataToken(context.Background()); got != want {
+			t.Errorf("metadataToken() = %q, want %q", got, want)
+		}
+	}
+	mu.Lock()
+	token = "second"
+	mu.Unlock()
+	now = now.Add(time.Hour)
+	if got := s.metadataToken(context.Background()); got != "second" {
+		t.Errorf("metadataToken() once expired = %q, want a new token", got)
+	}
+	if len(asked) != 2 || asked[0] != "Google" {
+		t.Errorf("metadata server asked with %q, want twice, as Google", asked)
+	}
+
+	off := &objectStore{client: srv.Client(), metadataURL: srv.URL, now: time.Now}
+	mu.Lock()
+	status = http.StatusNotFound
+	mu.Unlock()
+	for i := 0; i < 3; i++ {
+		if got := off.metadataToken(context.Background()); got != "" {
+			t.Errorf("metadataToken() off Google Cloud = %q, want none", got)
+		}
+	}
+	if len(asked) != 3 {
+		t.Errorf("metadata server asked %d times, want once more after failing", len(asked))
+	}
+}
@@ -0,0 +1,320 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rks0134/CSCE4600/Project1/scheduler"
+)
+
+// Tracing sends spans in batches, every otlpInterval while a command runs
+// and once more as it ends, and at most otlpSlices slice spans per run.
+const (
+	otlpInterval = time.Second
+	otlpSlices   = 1000
+)
+
+// otlpEndpoint returns the OTLP/HTTP traces endpoint of the -otlp flag,
+// or of the environment variables OpenTelemetry SDKs read, or "" for none.
+func otlpEndpoint(flag string) string {
+	switch {
+	case flag != "":
+		return flag
+	case os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT") != "":
+		return os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT")
+	case os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") != "":
+		return strings.TrimSuffix(os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT"), "/") + "/v1/traces"
+	}
+	return ""
+}
+
+// tracer records OpenTelemetry spans of a command and its simulations and
+// exports them to a collector over OTLP/HTTP, encoded as JSON: a span for
+// the command, a child span per simulation, and, if slices is set, a
+// child of that per Gantt slice.
+type tracer struct {
+	endpoint string
+	slices   bool
+	client   *http.Client
+	trace    string
+	root     *span
+
+	mu      sync.Mutex
+	pending []otlpSpan
+	stop    chan struct{}
+	done    chan struct{}
+}
+
+// span is a span being recorded.
+type span struct {
+	t      *tracer
+	id     string
+	parent string
+	name   string
+	start  time.Time
+	attrs  []otlpAttribute
+}
+
+// tracerKey is the context key of the command's tracer.
+type tracerKey struct{}
+
+// startTracer starts tracing command to endpoint, exporting in the
+// background until close.
+func startTracer(endpoint, command string, slices bool) *tracer {
+	t := &tracer{
+		endpoint: endpoint,
+		slices:   slices,
+		client:   &http.Client{Timeout: 10 * time.Second},
+		trace:    randomID(16),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	t.root = t.start("", command)
+	go func() {
+		defer close(t.done)
+		tick := time.NewTicker(otlpInterval)
+		defer tick.Stop()
+		for {
+			select {
+			case <-tick.C:
+				t.export()
+			case <-t.stop:
+				return
+			}
+		}
+	}()
+
+	return t
+}
+
+// withTracer returns ctx carrying t.
+func withTracer(ctx context.Context, t *tracer) context.Context {
+	return context.WithValue(ctx, tracerKey{}, t)
+}
+
+// tracerFrom returns the tracer ctx carries, or nil.
+func tracerFrom(ctx context.Context) *tracer {
+	t, _ := ctx.Value(tracerKey{}).(*tracer)
+	return t
+}
+
+// close ends the command's span with err and exports what is left. Export
+// failures are logged rather than failing the command.
+func (t *tracer) close(err error) {
+	t.root.end(err)
+	close(t.stop)
+	<-t.done
+	t.export()
+}
+
+func (t *tracer) start(parent, name string) *span {
+	return &span{t: t, id: randomID(8), parent: parent, name: name, start: time.Now()}
+}
+
+// traceRun records the simulation of alg that began at start and ended
+// with r and err, and its slices if they are traced.
+func (t *tracer) traceRun(alg algorithm, start time.Time, processes int, cached bool, r *scheduler.ScheduleResult, err error) {
+	if t == nil {
+		return
+	}
+	s := t.start(t.root.id, "schedule "+alg.name)
+	s.start = start
+	s.attrs = append(s.attrs,
+		stringAttribute("scheduler.algorithm", alg.name),
+		intAttribute("scheduler.processes", int64(processes)),
+		boolAttribute("scheduler.cached", cached))
+	if r == nil {
+		s.end(err)
+		return
+	}
+	m := r.UnitMetrics()
+	s.attrs = append(s.attrs,
+		intAttribute("scheduler.completed", int64(m.Completed)),
+		doubleAttribute("scheduler.average_wait", m.AverageWait),
+		doubleAttribute("scheduler.average_turnaround", m.AverageTurnaround),
+		doubleAttribute("scheduler.throughput", m.Throughput),
+		doubleAttribute("scheduler.makespan", m.Makespan),
+		intAttribute("scheduler.context_switches", int64(m.ContextSwitches)))
+	end := time.Now()
+	s.endAt(end, err)
+	if !t.slices || len(r.Gantt) == 0 {
+		return
+	}
+
+	// Slices happen in simulated time, so they are laid out in proportion
+	// within the simulation's span, for trace viewers to draw the chart.
+	var horizon int64
+	for _, ts := range r.Gantt {
+		if ts.Stop > horizon {
+			horizon = ts.Stop
+		}
+	}
+	at := func(tick int64) time.Time {
+		if horizon == 0 {
+			return start
+		}
+		return start.Add(time.Duration(float64(end.Sub(start)) * float64(tick) / float64(horizon)))
+	}
+	for k, ts := range r.Gantt {
+		if k == otlpSlices {
+			break
+		}
+		kind := string(ts.Kind)
+		if kind == "" {
+			kind = "run"
+		}
+		c := t.start(s.id, fmt.Sprintf("%s P%d", kind, ts.PID))
+		c.start = at(ts.Start)
+		c.attrs = []otlpAttribute{
+			stringAttribute("scheduler.slice.kind", kind),
+			intAttribute("scheduler.slice.pid", ts.PID),
+			intAttribute("scheduler.slice.cpu", int64(ts.CPU)),
+			intAttribute("scheduler.slice.start", ts.Start),
+			intAttribute("scheduler.slice.stop", ts.Stop),
+		}
+		c.endAt(at(ts.Stop), nil)
+	}
+}
+
+func (s *span) end(err error) { s.endAt(time.Now(), err) }
+
+// endAt ends s at end, failed if err is set, and queues it for export.
+func (s *span) endAt(end time.Time, err error) {
+	o := otlpSpan{
+		TraceID:      s.t.trace,
+		SpanID:       s.id,
+		ParentSpanID: s.parent,
+		Name:         s.name,
+		Kind:         1, // internal
+		Start:        strconv.FormatInt(s.start.UnixNano(), 10),
+		End:          strconv.FormatInt(end.UnixNano(), 10),
+		Attributes:   s.attrs,
+	}
+	if err != nil {
+		o.Status = &otlpStatus{Code: 2, Message: err.Error()}
+	}
+	s.t.mu.Lock()
+	s.t.pending = append(s.t.pending, o)
+	s.t.mu.Unlock()
+}
+
+// export sends the spans ended since the last export.
+func (t *tracer) export() {
+	t.mu.Lock()
+	spans := t.pending
+	t.pending = nil
+	t.mu.Unlock()
+	if len(spans) == 0 {
+		return
+	}
+	body, err := json.Marshal(otlpTraces{ResourceSpans: []otlpResourceSpans{{
+		Resource: otlpResource{Attributes: []otlpAttribute{stringAttribute("service.name", programName)}},
+		ScopeSpans: []otlpScopeSpans{{
+			Scope: otlpScope{Name: "github.com/rks0134/CSCE4600/Project1"},
+			Spans: spans,
+		}},
+	}}})
+	if err != nil {
+		log.Print(err)
+		return
+	}
+	resp, err := t.client.Post(t.endpoint, "application/json", bytes.NewReader(body))
+	if err != nil {
+		log.Printf("exporting traces: %v", withoutURL(err))
+		return
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	_ = resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		log.Printf("exporting traces: collector responded %s", resp.Status)
+	}
+}
+
+// randomID returns n random bytes in hex, as trace and span IDs are
+// written in OTLP JSON.
+func randomID(n int) string {
+	b := make([]byte, n)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// The OTLP/HTTP JSON encoding of ExportTraceServiceRequest, as much of it
+// as the tracer uses.
+type (
+	otlpTraces struct {
+		ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+	}
+	otlpResourceSpans struct {
+		Resource   otlpResource     `json:"resource"`
+		ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+	}
+	otlpResource struct {
+		Attributes []otlpAttribute `json:"attributes"`
+	}
+	otlpScopeSpans struct {
+		Scope otlpScope  `json:"scope"`
+		Spans []otlpSpan `json:"spans"`
+	}
+	otlpScope struct {
+		Name string `json:"name"`
+	}
+	otlpSpan struct {
+		TraceID      string          `json:"traceId"`
+		SpanID       string          `json:"spanId"`
+		ParentSpanID string          `json:"parentSpanId,omitempty"`
+		Name         string          `json:"name"`
+		Kind         int             `json:"kind"`
+		Start        string          `json:"startTimeUnixNano"`
+		End          string          `json:"endTimeUnixNano"`
+		Attributes   []otlpAttribute `json:"attributes,omitempty"`
+		Status       *otlpStatus     `json:"status,omitempty"`
+	}
+	otlpStatus struct {
+		Code    int    `json:"code"`
+		Message string `json:"message,omitempty"`
+	}
+	otlpAttribute struct {
+		Key   string    `json:"key"`
+		Value otlpValue `json:"value"`
+	}
+	otlpValue struct {
+		String *string  `json:"stringValue,omitempty"`
+		Int    *string  `json:"intValue,omitempty"`
+		Double *float64 `json:"doubleValue,omitempty"`
+		Bool   *bool    `json:"boolValue,omitempty"`
+	}
+)
+
+func stringAttribute(key, v string) otlpAttribute {
+	return otlpAttribute{Key: key, Value: otlpValue{String: &v}}
+}
+
+func intAttribute(key string, v int64) otlpAttribute {
+	s := strconv.FormatInt(v, 10)
+	return otlpAttribute{Key: key, Value: otlpValue{Int: &s}}
+}
+
+func doubleAttribute(key string, v float64) otlpAttribute {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		// JSON has no such numbers.
+		return stringAttribute(key, strconv.FormatFloat(v, 'g', -1, 64))
+	}
+	return otlpAttribute{Key: key, Value: otlpValue{Double: &v}}
+}
+
+func boolAttribute(key string, v bool) otlpAttribute {
+	return otlpAttribute{Key: key, Value: otlpValue{Bool: &v}}
+}
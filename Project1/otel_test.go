@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+)
+
+func Test_runOTLP(t *testing.T) {
+	t.Parallel()
+	var (
+		mu    sync.Mutex
+		spans []otlpSpan
+	)
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var traces otlpTraces
+		if err := json.NewDecoder(r.Body).Decode(&traces); err != nil {
+			t.Error(err)
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		for _, rs := range traces.ResourceSpans {
+			for _, ss := range rs.ScopeSpans {
+				spans = append(spans, ss.Spans...)
+			}
+		}
+	}))
+	defer collector.Close()
+
+	if err := run(context.Background(), io.Discard, "binary_name", "-otlp", collector.URL, "-otlp-slices", "schedule", "example_processes.csv"); err != nil {
+		t.Fatal(err)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	var root otlpSpan
+	for _, s := range spans {
+		if s.ParentSpanID == "" {
+			root = s
+		}
+	}
+	if root.Name != "schedule" {
+		t.Fatalf("root span = %+v, want the schedule command", root)
+	}
+	runs, slices := map[string]string{}, 0
+	for _, s := range spans {
+		if s.TraceID != root.TraceID {
+			t.Errorf("span %q is in trace %s, want %s", s.Name, s.TraceID, root.TraceID)
+		}
+		if s.ParentSpanID == root.SpanID {
+			runs[s.SpanID] = s.Name
+		}
+	}
+	for _, s := range spans {
+		if _, ok := runs[s.ParentSpanID]; ok {
+			slices++
+		}
+	}
+	names := []string{}
+	for _, name := range runs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	want := []string{}
+	for _, alg := range forWorkload(algorithms(), "all", nil) {
+		want = append(want, "schedule "+alg.name)
+	}
+	sort.Strings(want)
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("simulation spans = %q, want %q", names, want)
+	}
+	if slices == 0 {
+		t.Error("no slice spans")
+	}
+}
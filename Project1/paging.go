@@ -0,0 +1,142 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/rks0134/CSCE4600/Project1/paging"
+	"github.com/rks0134/CSCE4600/Project1/scheduler"
+)
+
+// pagingCmd compares page replacement algorithms on a reference string.
+type pagingCmd struct {
+	*globalFlags
+	frames     int
+	algorithms string
+	sweep      string
+	steps      bool
+	generate   int
+	pages      int
+}
+
+func (c *pagingCmd) defineFlags(flags *flag.FlagSet) {
+	flags.IntVar(&c.frames, "frames", 3, "number of page `frames`")
+	flags.StringVar(&c.algorithms, "algorithms", "all", "comma separated `algorithms` to compare (fifo, lru, opt, clock, lfu), or all")
+	flags.StringVar(&c.sweep, "sweep", "auto", "`range` of frame counts to sweep, such as 1..8, auto for 1 to the number of distinct pages (at most 16), or none")
+	flags.BoolVar(&c.steps, "steps", false, "also print each algorithm's frames after every reference, with its faults marked")
+	flags.IntVar(&c.generate, "generate", 0, "generate a reference string of `n` references instead of reading one (reproducible with -seed)")
+	flags.IntVar(&c.pages, "pages", 16, "number of distinct `pages` a generated reference string refers to")
+}
+
+func (c *pagingCmd) run(_ context.Context, w io.Writer, args []string) error {
+	algs, err := selectPagingAlgorithms(c.algorithms)
+	if err != nil {
+		return err
+	}
+	if c.frames < 1 {
+		return fmt.Errorf("%w: -frames must be positive", ErrInvalidArgs)
+	}
+	refs, err := c.references(args)
+	if err != nil {
+		return err
+	}
+	var frames []int
+	switch c.sweep {
+	case "none":
+	case "auto":
+		n := paging.Distinct(refs)
+		if n > 16 {
+			n = 16
+		}
+		for f := 1; f <= n; f++ {
+			frames = append(frames, f)
+		}
+	default:
+		values, err := parseRange(c.sweep)
+		if err != nil {
+			return err
+		}
+		for _, v := range values {
+			frames = append(frames, int(v))
+		}
+	}
+
+	title := fmt.Sprintf("Page replacement, %d references to %d pages", len(refs), paging.Distinct(refs))
+	scheduler.RenderTitle(w, title)
+	if c.generate > 0 {
+		_, _ = fmt.Fprintf(w, "Seed: %d\n\n", c.seed.value)
+	}
+	var results []*paging.Result
+	for _, alg := range algs {
+		r, err := paging.Simulate(alg, refs, c.frames)
+		if err != nil {
+			return err
+		}
+		if c.steps {
+			paging.RenderSteps(w, r)
+		}
+		results = append(results, r)
+	}
+	paging.RenderComparison(w, results)
+	if len(frames) == 0 {
+		return nil
+	}
+	faults := make([][]int, len(algs))
+	for k, alg := range algs {
+		if faults[k], err = paging.Sweep(alg, refs, frames); err != nil {
+			return err
+		}
+	}
+	paging.RenderSweep(w, algs, frames, faults)
+
+	return nil
+}
+
+// references reads the reference string in the file args name, or
+// generates one with -generate.
+func (c *pagingCmd) references(args []string) ([]int, error) {
+	if c.generate > 0 {
+		if len(args) > 0 {
+			return nil, fmt.Errorf("%w: -generate takes no reference string file", ErrInvalidArgs)
+		}
+		if c.pages < 1 {
+			return nil, fmt.Errorf("%w: -pages must be positive", ErrInvalidArgs)
+		}
+		return paging.Generate(c.rand(), c.generate, c.pages), nil
+	}
+	if len(args) != 1 {
+		return nil, fmt.Errorf("%w: must give a reference string file, or -generate", ErrInvalidArgs)
+	}
+	var refs []int
+	err := readInputFile(args[0], func(r io.Reader) (err error) {
+		refs, err = paging.ParseReferences(r)
+		return err
+	})
+
+	return refs, err
+}
+
+// selectPagingAlgorithms resolves a comma separated list of page
+// replacement algorithm names, or "all".
+func selectPagingAlgorithms(list string) ([]paging.Algorithm, error) {
+	if list == "all" {
+		return paging.Algorithms, nil
+	}
+	var selected []paging.Algorithm
+	for _, name := range strings.Split(list, ",") {
+		alg, ok := paging.Find(strings.TrimSpace(name))
+		if !ok {
+			var known []string
+			for _, a := range paging.Algorithms {
+				known = append(known, a.Name)
+			}
+			return nil, fmt.Errorf("%w: unknown page replacement algorithm %q (known: %s)", ErrInvalidArgs, name, strings.Join(known, ", "))
+		}
+		selected = append(selected, alg)
+	}
+
+	return selected, nil
+}
@@ -0,0 +1,148 @@
+package paging
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/olekukonko/tablewriter"
+)
+
+// stepColumns is how many references each block of a step table shows.
+const stepColumns = 20
+
+// RenderSteps writes r's steps as the textbook table: a column per
+// reference with the page in each frame after it, and a row marking its
+// faults, in blocks of 20 references.
+func RenderSteps(w io.Writer, r *Result) {
+	_, _ = fmt.Fprintf(w, "%s, %d frames: %d faults\n", r.Algorithm.Title, r.Frames, r.Faults)
+	for start := 0; start < len(r.Steps); start += stepColumns {
+		end := start + stepColumns
+		if end > len(r.Steps) {
+			end = len(r.Steps)
+		}
+		steps := r.Steps[start:end]
+		table := tablewriter.NewWriter(w)
+		header := []string{"Reference"}
+		for _, s := range steps {
+			header = append(header, strconv.Itoa(s.Page))
+		}
+		table.SetHeader(header)
+		table.SetAutoFormatHeaders(false)
+		for f := 0; f < r.Frames; f++ {
+			row := []string{fmt.Sprintf("Frame %d", f)}
+			for _, s := range steps {
+				cell := ""
+				if s.Frames[f] != Empty {
+					cell = strconv.Itoa(s.Frames[f])
+				}
+				row = append(row, cell)
+			}
+			table.Append(row)
+		}
+		faults := []string{"Fault"}
+		for _, s := range steps {
+			cell := ""
+			if s.Fault {
+				cell = "F"
+			}
+			faults = append(faults, cell)
+		}
+		table.SetFooter(faults)
+		table.Render()
+	}
+	_, _ = fmt.Fprintln(w)
+}
+
+// RenderComparison writes a table of the faults, hits, and fault rate of
+// each result.
+func RenderComparison(w io.Writer, results []*Result) {
+	_, _ = fmt.Fprintln(w, "Page faults")
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"Algorithm", "Frames", "Faults", "Hits", "Fault rate"})
+	for _, r := range results {
+		table.Append([]string{
+			r.Algorithm.Name,
+			strconv.Itoa(r.Frames),
+			strconv.Itoa(r.Faults),
+			strconv.Itoa(r.Hits()),
+			fmt.Sprintf("%.2f%%", 100*r.FaultRate()),
+		})
+	}
+	table.Render()
+	_, _ = fmt.Fprintln(w)
+}
+
+// RenderSweep writes the faults of each algorithm with each number of
+// frames, faults[k] being algs[k]'s, marking with a ! every count that
+// faulted more than the one before it, and then describes each instance of
+// Belady's anomaly, or notes there were none.
+func RenderSweep(w io.Writer, algs []Algorithm, frames []int, faults [][]int) {
+	_, _ = fmt.Fprintln(w, "Faults by frame count")
+	table := tablewriter.NewWriter(w)
+	header := []string{"Frames"}
+	for _, a := range algs {
+		header = append(header, a.Name)
+	}
+	table.SetHeader(header)
+	for i, n := range frames {
+		row := []string{strconv.Itoa(n)}
+		for k := range algs {
+			cell := strconv.Itoa(faults[k][i])
+			if i > 0 && faults[k][i] > faults[k][i-1] {
+				cell += " !"
+			}
+			row = append(row, cell)
+		}
+		table.Append(row)
+	}
+	table.Render()
+
+	var notes []string
+	for k, a := range algs {
+		for _, an := range Anomalies(frames, faults[k]) {
+			notes = append(notes, fmt.Sprintf("Belady's anomaly: %s takes %d faults with %d frames but %d with %d.",
+				a.Name, an.Faults, an.Frames, an.MoreFaults, an.MoreFrames))
+		}
+	}
+	if len(notes) == 0 {
+		notes = append(notes, "No Belady's anomaly: no algorithm faulted more with more frames.")
+	}
+	_, _ = fmt.Fprintf(w, "%s\n\n", strings.Join(notes, "\n"))
+}
+
+// RenderTranslations writes a table of each translation's page, offset,
+// frame, and physical address, in hex when hex is set, marking its faults,
+// followed by the fault rate and the effective access time it gives with
+// memory accesses taking memory and faults fault.
+func RenderTranslations(w io.Writer, r *TranslationResult, hex bool, memory, fault time.Duration) {
+	address := func(a uint64) string {
+		if hex {
+			return fmt.Sprintf("0x%x", a)
+		}
+		return strconv.FormatUint(a, 10)
+	}
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"Virtual address", "Page", "Offset", "Frame", "Physical address", "Fault"})
+	for _, t := range r.Translations {
+		frame, physical, note := "-", "-", ""
+		if !t.Fault || t.Frame != Empty {
+			frame, physical = strconv.Itoa(t.Frame), address(t.Physical)
+		}
+		if t.Fault {
+			note = "fault"
+			if t.Evicted != Empty {
+				note += fmt.Sprintf(", evicts page %d", t.Evicted)
+			}
+		}
+		table.Append([]string{address(t.Address), strconv.FormatUint(t.Page, 10), address(t.Offset), frame, physical, note})
+	}
+	table.Render()
+
+	p := r.FaultRate()
+	_, _ = fmt.Fprintf(w, "Page faults: %d of %d translations (%.2f%%)\n", r.Faults, len(r.Translations), 100*p)
+	_, _ = fmt.Fprintf(w, "Effective access time: (1 - %.4g) × %v + %.4g × %v = %v\n\n",
+		p, memory, p, fault, EffectiveAccessTime(p, memory, fault))
+}
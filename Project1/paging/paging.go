@@ -0,0 +1,301 @@
+// Package paging simulates page replacement: which of a process's page
+// references find their page in its frames, and which page each algorithm
+// evicts to make room for those that do not.
+package paging
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"strconv"
+	"strings"
+
+	"github.com/rks0134/CSCE4600/Project1/scheduler"
+)
+
+// Algorithm is a page replacement algorithm.
+type Algorithm struct {
+	Name  string
+	Title string
+}
+
+// Algorithms are the page replacement algorithms, in the order reports list
+// them.
+var Algorithms = []Algorithm{
+	{Name: "fifo", Title: "First-in, first-out"},
+	{Name: "lru", Title: "Least recently used"},
+	{Name: "opt", Title: "Optimal"},
+	{Name: "clock", Title: "Clock"},
+	{Name: "lfu", Title: "Least frequently used"},
+}
+
+// Find returns the algorithm named name.
+func Find(name string) (Algorithm, bool) {
+	for _, a := range Algorithms {
+		if a.Name == name {
+			return a, true
+		}
+	}
+	return Algorithm{}, false
+}
+
+// Empty marks a frame holding no page, and a step that evicted none.
+const Empty = -1
+
+// Step is what one reference did: the page in each frame after it, whether
+// it faulted, and the page it evicted, if any.
+type Step struct {
+	Page    int
+	Frames  []int
+	Fault   bool
+	Evicted int
+}
+
+// Result is an algorithm's run over a reference string.
+type Result struct {
+	Algorithm Algorithm
+	Frames    int
+	Faults    int
+	// Steps has a step per reference when the run recorded them.
+	Steps []Step
+	// References counts the references.
+	References int
+}
+
+// Hits counts the references that found their page in a frame.
+func (r *Result) Hits() int { return r.References - r.Faults }
+
+// FaultRate is the share of references that faulted.
+func (r *Result) FaultRate() float64 {
+	if r.References == 0 {
+		return 0
+	}
+	return float64(r.Faults) / float64(r.References)
+}
+
+// Simulate runs alg over refs with frames frames, recording every step.
+func Simulate(alg Algorithm, refs []int, frames int) (*Result, error) {
+	return simulate(alg, refs, frames, true)
+}
+
+// Sweep returns the faults alg takes over refs with each number of frames.
+func Sweep(alg Algorithm, refs []int, frames []int) ([]int, error) {
+	faults := make([]int, len(frames))
+	for k, n := range frames {
+		r, err := simulate(alg, refs, n, false)
+		if err != nil {
+			return nil, err
+		}
+		faults[k] = r.Faults
+	}
+	return faults, nil
+}
+
+// Anomaly is an instance of Belady's anomaly: more frames, more faults.
+type Anomaly struct {
+	Frames, Faults         int
+	MoreFrames, MoreFaults int
+}
+
+// Anomalies returns where faults, a sweep over the increasing frame counts
+// frames, grew from one count to the next.
+func Anomalies(frames, faults []int) []Anomaly {
+	var anomalies []Anomaly
+	for k := 1; k < len(frames); k++ {
+		if faults[k] > faults[k-1] {
+			anomalies = append(anomalies, Anomaly{Frames: frames[k-1], Faults: faults[k-1], MoreFrames: frames[k], MoreFaults: faults[k]})
+		}
+	}
+	return anomalies
+}
+
+// simulate runs alg over refs with frames frames. Free frames fill in
+// order; once all are full, each fault evicts the page alg chooses, ties
+// going to the frame loaded first:
+//
+//   - fifo evicts the page loaded longest ago;
+//   - lru the page used longest ago;
+//   - opt the page next used furthest in the future, or never;
+//   - clock the first page the hand finds with its reference bit clear,
+//     clearing the bits it passes, with a bit set on every use;
+//   - lfu the page used least often since it was loaded.
+func simulate(alg Algorithm, refs []int, frames int, record bool) (*Result, error) {
+	if frames < 1 {
+		return nil, fmt.Errorf("need at least one frame, not %d", frames)
+	}
+	r := &Result{Algorithm: alg, Frames: frames, References: len(refs)}
+	if record {
+		r.Steps = make([]Step, 0, len(refs))
+	}
+	// next[i] is the index of the next reference to the page of refs[i].
+	var next []int
+	if alg.Name == "opt" {
+		next = make([]int, len(refs))
+		seen := make(map[int]int)
+		for i := len(refs) - 1; i >= 0; i-- {
+			n, ok := seen[refs[i]]
+			if !ok {
+				n = len(refs)
+			}
+			next[i], seen[refs[i]] = n, i
+		}
+	}
+	page := make([]int, frames)
+	loaded := make([]int, frames)
+	// used is, per frame, the last use for lru, the next use for opt, the
+	// reference bit for clock, and the use count for lfu.
+	used := make([]int, frames)
+	resident := make(map[int]int, frames)
+	for f := range page {
+		page[f] = Empty
+	}
+	hand, filled := 0, 0
+	for i, p := range refs {
+		f, hit := resident[p]
+		evicted := Empty
+		if !hit {
+			r.Faults++
+			if filled < frames {
+				f = filled
+				filled++
+			} else {
+				f = victim(alg.Name, page, loaded, used, &hand)
+				evicted = page[f]
+				delete(resident, evicted)
+			}
+			page[f], loaded[f], used[f] = p, i, 0
+			resident[p] = f
+			if alg.Name == "clock" && f == hand {
+				hand = (hand + 1) % frames
+			}
+		}
+		switch alg.Name {
+		case "lru":
+			used[f] = i
+		case "opt":
+			used[f] = next[i]
+		case "clock":
+			used[f] = 1
+		case "lfu":
+			used[f]++
+		}
+		if record {
+			r.Steps = append(r.Steps, Step{Page: p, Frames: append([]int(nil), page...), Fault: !hit, Evicted: evicted})
+		}
+	}
+
+	return r, nil
+}
+
+// victim returns the frame alg evicts from among full frames.
+func victim(alg string, page, loaded, used []int, hand *int) int {
+	if alg == "clock" {
+		for used[*hand] != 0 {
+			used[*hand] = 0
+			*hand = (*hand + 1) % len(page)
+		}
+		return *hand
+	}
+	v := 0
+	for f := 1; f < len(page); f++ {
+		var better bool
+		switch alg {
+		case "fifo":
+			better = loaded[f] < loaded[v]
+		case "lru":
+			better = used[f] < used[v]
+		case "opt":
+			better = used[f] > used[v] || used[f] == used[v] && loaded[f] < loaded[v]
+		case "lfu":
+			better = used[f] < used[v] || used[f] == used[v] && loaded[f] < loaded[v]
+		}
+		if better {
+			v = f
+		}
+	}
+	return v
+}
+
+// ParseReferences reads a reference string: page numbers separated by
+// spaces, commas, or newlines, with comments from # to the end of a line.
+func ParseReferences(r io.Reader) ([]int, error) {
+	var refs []int
+	err := scanFields(r, func(field string) error {
+		n, err := strconv.Atoi(field)
+		if err != nil || n < 0 {
+			return fmt.Errorf("invalid page %q", field)
+		}
+		refs = append(refs, n)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(refs) == 0 {
+		return nil, &scheduler.ParseError{Err: errors.New("no page references")}
+	}
+	return refs, nil
+}
+
+// scanFields calls fn with each field of r, separated by spaces, commas, or
+// newlines, with comments from # to the end of a line, and returns the
+// first error fn returns as a parse error at its field.
+func scanFields(r io.Reader, fn func(field string) error) error {
+	s := bufio.NewScanner(r)
+	for line := 1; s.Scan(); line++ {
+		text, _, _ := strings.Cut(s.Text(), "#")
+		for start := 0; start < len(text); {
+			end := strings.IndexAny(text[start:], ", \t\r")
+			if end < 0 {
+				end = len(text)
+			} else {
+				end += start
+			}
+			if field := text[start:end]; field != "" {
+				if err := fn(field); err != nil {
+					return &scheduler.ParseError{Line: line, Column: start + 1, Err: err}
+				}
+			}
+			start = end + 1
+		}
+	}
+	if err := s.Err(); err != nil {
+		return &scheduler.ParseError{Err: err}
+	}
+	return nil
+}
+
+// Generate returns n references to pages 0 to pages-1 with locality of
+// reference: nine in ten fall in a working set of a quarter of the pages,
+// which moves to a random place every 50 references or so, and the rest
+// anywhere.
+func Generate(rng *rand.Rand, n, pages int) []int {
+	window := pages / 4
+	if window < 1 {
+		window = 1
+	}
+	base := rng.Intn(pages - window + 1)
+	refs := make([]int, n)
+	for i := range refs {
+		if rng.Intn(50) == 0 {
+			base = rng.Intn(pages - window + 1)
+		}
+		if rng.Intn(10) == 0 {
+			refs[i] = rng.Intn(pages)
+		} else {
+			refs[i] = base + rng.Intn(window)
+		}
+	}
+	return refs
+}
+
+// Distinct counts the distinct pages in refs.
+func Distinct(refs []int) int {
+	seen := make(map[int]bool)
+	for _, p := range refs {
+		seen[p] = true
+	}
+	return len(seen)
+}
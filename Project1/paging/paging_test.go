@@ -0,0 +1,134 @@
+package paging
+
+import (
+	"errors"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/rks0134/CSCE4600/Project1/scheduler"
+)
+
+// textbook is the reference string of Silberschatz's page replacement
+// examples.
+var textbook = []int{7, 0, 1, 2, 0, 3, 0, 4, 2, 3, 0, 3, 2, 1, 2, 0, 1, 7, 0, 1}
+
+func TestSimulate(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		alg        string
+		wantFaults int
+		// wantFrames are the frames after the eighth reference, to page 4.
+		wantFrames []int
+	}{
+		{alg: "fifo", wantFaults: 15, wantFrames: []int{4, 3, 0}},
+		{alg: "lru", wantFaults: 12, wantFrames: []int{4, 0, 3}},
+		{alg: "opt", wantFaults: 9, wantFrames: []int{2, 4, 3}},
+		{alg: "clock", wantFaults: 14, wantFrames: []int{4, 0, 3}},
+		{alg: "lfu", wantFaults: 13, wantFrames: []int{4, 0, 3}},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.alg, func(t *testing.T) {
+			t.Parallel()
+			alg, ok := Find(tt.alg)
+			if !ok {
+				t.Fatalf("Find(%q) found nothing", tt.alg)
+			}
+			r, err := Simulate(alg, textbook, 3)
+			if err != nil {
+				t.Fatalf("Simulate() error = %v", err)
+			}
+			if r.Faults != tt.wantFaults || r.Hits() != len(textbook)-tt.wantFaults {
+				t.Errorf("Simulate() faults = %d, hits = %d, want %d faults", r.Faults, r.Hits(), tt.wantFaults)
+			}
+			if got := r.Steps[7].Frames; !reflect.DeepEqual(got, tt.wantFrames) {
+				t.Errorf("Simulate() frames after page 4 = %v, want %v", got, tt.wantFrames)
+			}
+			if s := r.Steps[0]; !s.Fault || s.Evicted != Empty || !reflect.DeepEqual(s.Frames, []int{7, Empty, Empty}) {
+				t.Errorf("Simulate() first step = %+v, want a fault into the first free frame", s)
+			}
+		})
+	}
+
+	if _, err := Simulate(Algorithms[0], textbook, 0); err == nil {
+		t.Error("Simulate() with no frames succeeded")
+	}
+}
+
+func TestSweep(t *testing.T) {
+	t.Parallel()
+	belady := []int{1, 2, 3, 4, 1, 2, 5, 1, 2, 3, 4, 5}
+	frames := []int{1, 2, 3, 4, 5}
+	fifo, _ := Find("fifo")
+	faults, err := Sweep(fifo, belady, frames)
+	if err != nil {
+		t.Fatalf("Sweep() error = %v", err)
+	}
+	if want := []int{12, 12, 9, 10, 5}; !reflect.DeepEqual(faults, want) {
+		t.Errorf("Sweep() = %v, want %v", faults, want)
+	}
+	want := []Anomaly{{Frames: 3, Faults: 9, MoreFrames: 4, MoreFaults: 10}}
+	if got := Anomalies(frames, faults); !reflect.DeepEqual(got, want) {
+		t.Errorf("Anomalies() = %+v, want %+v", got, want)
+	}
+
+	// LRU is a stack algorithm, which never suffers the anomaly.
+	lru, _ := Find("lru")
+	if faults, _ := Sweep(lru, belady, frames); Anomalies(frames, faults) != nil {
+		t.Errorf("Anomalies() of LRU = %+v, want none", Anomalies(frames, faults))
+	}
+}
+
+func TestParseReferences(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name     string
+		in       string
+		want     []int
+		wantLine int
+		wantCol  int
+	}{
+		{name: "commas and spaces", in: "7, 0,1 2\n# comment\n0 3 # trailing\n", want: []int{7, 0, 1, 2, 0, 3}},
+		{name: "invalid page", in: "1 2\n3 x 4\n", wantLine: 2, wantCol: 3},
+		{name: "negative page", in: "1 -2\n", wantLine: 1, wantCol: 3},
+		{name: "empty", in: "# nothing\n"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := ParseReferences(strings.NewReader(tt.in))
+			if tt.want != nil {
+				if err != nil || !reflect.DeepEqual(got, tt.want) {
+					t.Errorf("ParseReferences() = %v, %v, want %v", got, err, tt.want)
+				}
+				return
+			}
+			var pe *scheduler.ParseError
+			if !errors.As(err, &pe) || pe.Line != tt.wantLine || pe.Column != tt.wantCol {
+				t.Errorf("ParseReferences() error = %v, want a parse error at line %d, column %d", err, tt.wantLine, tt.wantCol)
+			}
+		})
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	t.Parallel()
+	refs := Generate(rand.New(rand.NewSource(1)), 1000, 16)
+	if len(refs) != 1000 {
+		t.Fatalf("Generate() made %d references, want 1000", len(refs))
+	}
+	for _, p := range refs {
+		if p < 0 || p >= 16 {
+			t.Fatalf("Generate() referenced page %d, want 0 to 15", p)
+		}
+	}
+	if !reflect.DeepEqual(refs, Generate(rand.New(rand.NewSource(1)), 1000, 16)) {
+		t.Error("Generate() differs with the same seed")
+	}
+	if n := Distinct(refs); n < 8 {
+		t.Errorf("Generate() referenced %d distinct pages, want most of the 16", n)
+	}
+}
@@ -0,0 +1,176 @@
+package paging
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math/bits"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rks0134/CSCE4600/Project1/scheduler"
+)
+
+// PageTable maps pages to the frames holding them, Empty for a page marked
+// invalid. Pages it lacks are invalid too.
+type PageTable map[uint64]int
+
+// Translation is what translating one virtual address found: its page and
+// offset, and, unless it faulted with nowhere to load the page, the frame
+// and physical address.
+type Translation struct {
+	Address  uint64
+	Page     uint64
+	Offset   uint64
+	Fault    bool
+	Frame    int
+	Physical uint64
+	// Evicted is the page demand paging replaced to load this one, or Empty.
+	Evicted int
+}
+
+// TranslationResult is a run of address translations.
+type TranslationResult struct {
+	PageSize     uint64
+	Translations []Translation
+	Faults       int
+}
+
+// FaultRate is the share of translations that faulted.
+func (r *TranslationResult) FaultRate() float64 {
+	if len(r.Translations) == 0 {
+		return 0
+	}
+	return float64(r.Faults) / float64(len(r.Translations))
+}
+
+// EffectiveAccessTime is the mean time of a memory access with a fault rate
+// of p, each access taking memory and each fault fault more to service:
+// (1-p) × memory + p × fault.
+func EffectiveAccessTime(p float64, memory, fault time.Duration) time.Duration {
+	return time.Duration((1-p)*float64(memory) + p*float64(fault))
+}
+
+// checkPageSize returns an error unless size is a power of two, as page
+// sizes are so the offset is the address's low bits.
+func checkPageSize(size uint64) error {
+	if size == 0 || size&(size-1) != 0 {
+		return fmt.Errorf("page size must be a power of two, not %d", size)
+	}
+	return nil
+}
+
+// OffsetBits is the number of an address's low bits that are its offset
+// within a page of size bytes.
+func OffsetBits(size uint64) int { return bits.TrailingZeros64(size) }
+
+// Translate splits each address into its page and offset and looks the page
+// up in table, each page table miss being a fault with no frame.
+func Translate(addrs []uint64, pageSize uint64, table PageTable) (*TranslationResult, error) {
+	if err := checkPageSize(pageSize); err != nil {
+		return nil, err
+	}
+	r := &TranslationResult{PageSize: pageSize, Translations: make([]Translation, len(addrs))}
+	for i, a := range addrs {
+		t := Translation{Address: a, Page: a / pageSize, Offset: a % pageSize, Frame: Empty, Evicted: Empty}
+		if f, ok := table[t.Page]; ok && f != Empty {
+			t.Frame, t.Physical = f, uint64(f)*pageSize+t.Offset
+		} else {
+			t.Fault = true
+			r.Faults++
+		}
+		r.Translations[i] = t
+	}
+	return r, nil
+}
+
+// TranslateDemand translates addrs with demand paging: memory starts with
+// frames empty frames, and each fault loads its page into one, replacing a
+// page as alg chooses once all are full.
+func TranslateDemand(addrs []uint64, pageSize uint64, alg Algorithm, frames int) (*TranslationResult, error) {
+	if err := checkPageSize(pageSize); err != nil {
+		return nil, err
+	}
+	refs := make([]int, len(addrs))
+	for i, a := range addrs {
+		refs[i] = int(a / pageSize)
+	}
+	run, err := Simulate(alg, refs, frames)
+	if err != nil {
+		return nil, err
+	}
+	r := &TranslationResult{PageSize: pageSize, Translations: make([]Translation, len(addrs)), Faults: run.Faults}
+	for i, a := range addrs {
+		s := run.Steps[i]
+		t := Translation{Address: a, Page: a / pageSize, Offset: a % pageSize, Fault: s.Fault, Evicted: s.Evicted}
+		for f, p := range s.Frames {
+			if p == s.Page {
+				t.Frame = f
+			}
+		}
+		t.Physical = uint64(t.Frame)*pageSize + t.Offset
+		r.Translations[i] = t
+	}
+	return r, nil
+}
+
+// ParseAddresses reads virtual addresses, in decimal or 0x-prefixed hex,
+// separated as ParseReferences's pages are.
+func ParseAddresses(r io.Reader) ([]uint64, error) {
+	var addrs []uint64
+	err := scanFields(r, func(field string) error {
+		a, err := strconv.ParseUint(field, 0, 64)
+		if err != nil {
+			return fmt.Errorf("invalid address %q", field)
+		}
+		addrs = append(addrs, a)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(addrs) == 0 {
+		return nil, &scheduler.ParseError{Err: errors.New("no addresses")}
+	}
+	return addrs, nil
+}
+
+// ParsePageTable reads a page table: a line per page giving the page and
+// its frame, or - for a page marked invalid, with comments from # to the
+// end of a line.
+func ParsePageTable(r io.Reader) (PageTable, error) {
+	table := make(PageTable)
+	s := bufio.NewScanner(r)
+	for line := 1; s.Scan(); line++ {
+		text, _, _ := strings.Cut(s.Text(), "#")
+		fields := strings.Fields(text)
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) != 2 {
+			return nil, &scheduler.ParseError{Line: line, Err: fmt.Errorf("want a page and a frame, not %d fields", len(fields))}
+		}
+		page, err := strconv.ParseUint(fields[0], 0, 64)
+		if err != nil {
+			return nil, &scheduler.ParseError{Line: line, Column: strings.Index(text, fields[0]) + 1, Err: fmt.Errorf("invalid page %q", fields[0])}
+		}
+		if _, ok := table[page]; ok {
+			return nil, &scheduler.ParseError{Line: line, Err: fmt.Errorf("page %d listed twice", page)}
+		}
+		frame := Empty
+		if fields[1] != "-" {
+			n, err := strconv.Atoi(fields[1])
+			if err != nil || n < 0 {
+				return nil, &scheduler.ParseError{Line: line, Column: strings.LastIndex(text, fields[1]) + 1, Err: fmt.Errorf("invalid frame %q", fields[1])}
+			}
+			frame = n
+		}
+		table[page] = frame
+	}
+	if err := s.Err(); err != nil {
+		return nil, &scheduler.ParseError{Err: err}
+	}
+	return table, nil
+}
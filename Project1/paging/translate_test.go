@@ -0,0 +1,89 @@
+package paging
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rks0134/CSCE4600/Project1/scheduler"
+)
+
+func TestTranslate(t *testing.T) {
+	t.Parallel()
+	table := PageTable{0: 5, 1: 6, 2: 1, 3: Empty}
+	r, err := Translate([]uint64{1052, 2221, 5499, 3100}, 1024, table)
+	if err != nil {
+		t.Fatalf("Translate() error = %v", err)
+	}
+	want := []Translation{
+		{Address: 1052, Page: 1, Offset: 28, Frame: 6, Physical: 6172, Evicted: Empty},
+		{Address: 2221, Page: 2, Offset: 173, Frame: 1, Physical: 1197, Evicted: Empty},
+		{Address: 5499, Page: 5, Offset: 379, Fault: true, Frame: Empty, Evicted: Empty},
+		{Address: 3100, Page: 3, Offset: 28, Fault: true, Frame: Empty, Evicted: Empty},
+	}
+	if !reflect.DeepEqual(r.Translations, want) || r.Faults != 2 {
+		t.Errorf("Translate() = %+v, %d faults, want %+v, 2 faults", r.Translations, r.Faults, want)
+	}
+	if _, err := Translate(nil, 1000, table); err == nil {
+		t.Error("Translate() with a page size of 1000 succeeded")
+	}
+}
+
+func TestTranslateDemand(t *testing.T) {
+	t.Parallel()
+	fifo, _ := Find("fifo")
+	r, err := TranslateDemand([]uint64{0x1010, 0x2020, 0x1030, 0x3040}, 0x1000, fifo, 2)
+	if err != nil {
+		t.Fatalf("TranslateDemand() error = %v", err)
+	}
+	want := []Translation{
+		{Address: 0x1010, Page: 1, Offset: 0x10, Fault: true, Frame: 0, Physical: 0x10, Evicted: Empty},
+		{Address: 0x2020, Page: 2, Offset: 0x20, Fault: true, Frame: 1, Physical: 0x1020, Evicted: Empty},
+		{Address: 0x1030, Page: 1, Offset: 0x30, Frame: 0, Physical: 0x30, Evicted: Empty},
+		{Address: 0x3040, Page: 3, Offset: 0x40, Fault: true, Frame: 0, Physical: 0x40, Evicted: 1},
+	}
+	if !reflect.DeepEqual(r.Translations, want) || r.Faults != 3 {
+		t.Errorf("TranslateDemand() = %+v, %d faults, want %+v, 3 faults", r.Translations, r.Faults, want)
+	}
+	if got := r.FaultRate(); got != 0.75 {
+		t.Errorf("FaultRate() = %v, want 0.75", got)
+	}
+}
+
+func TestEffectiveAccessTime(t *testing.T) {
+	t.Parallel()
+	// Silberschatz's example: one fault in a thousand accesses slows memory
+	// by a factor of 40, to 8.1998 microseconds.
+	if got, want := EffectiveAccessTime(0.001, 200*time.Nanosecond, 8*time.Millisecond), 8199*time.Nanosecond; got != want {
+		t.Errorf("EffectiveAccessTime() = %v, want %v", got, want)
+	}
+}
+
+func TestParsePageTable(t *testing.T) {
+	t.Parallel()
+	got, err := ParsePageTable(strings.NewReader("# page frame\n0 5\n0x1 6 # hex\n\n3 -\n"))
+	if want := (PageTable{0: 5, 1: 6, 3: Empty}); err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("ParsePageTable() = %v, %v, want %v", got, err, want)
+	}
+
+	for _, in := range []string{"0 5\n1\n", "0 5\n1 x\n", "0 5\n0 6\n"} {
+		var pe *scheduler.ParseError
+		if _, err := ParsePageTable(strings.NewReader(in)); !errors.As(err, &pe) || pe.Line != 2 {
+			t.Errorf("ParsePageTable(%q) error = %v, want a parse error on line 2", in, err)
+		}
+	}
+}
+
+func TestParseAddresses(t *testing.T) {
+	t.Parallel()
+	got, err := ParseAddresses(strings.NewReader("1052, 0x8ad\n0 # zero\n"))
+	if want := []uint64{1052, 0x8ad, 0}; err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseAddresses() = %v, %v, want %v", got, err, want)
+	}
+	var pe *scheduler.ParseError
+	if _, err := ParseAddresses(strings.NewReader("1 -2\n")); !errors.As(err, &pe) || pe.Column != 3 {
+		t.Errorf("ParseAddresses() error = %v, want a parse error at column 3", err)
+	}
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func Test_runPaging(t *testing.T) {
+	t.Parallel()
+	refs := filepath.Join(t.TempDir(), "references.txt")
+	if err := os.WriteFile(refs, []byte("1 2 3 4 1 2 5 1 2 3 4 5\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	var w strings.Builder
+	if err := run(context.Background(), &w, "binary_name", "paging", "-algorithms", "fifo,opt", "-steps", refs); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"Page replacement, 12 references to 5 pages",
+		"First-in, first-out, 3 frames: 9 faults\n",
+		"| opt       |      3 |      7 |    5 | 58.33%     |\n",
+		"|      4 | 10 ! |   6 |\n",
+		"Belady's anomaly: fifo takes 9 faults with 3 frames but 10 with 4.\n",
+	} {
+		if !strings.Contains(w.String(), want) {
+			t.Errorf("paging wrote:\n%s\nwant it to contain %q", w.String(), want)
+		}
+	}
+
+	var a, b strings.Builder
+	for _, w := range []*strings.Builder{&a, &b} {
+		if err := run(context.Background(), w, "binary_name", "-seed", "3", "paging", "-generate", "200", "-sweep", "none"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if a.String() != b.String() || !strings.Contains(a.String(), "Seed: 3\n") {
+		t.Errorf("paging -generate with the same seed wrote:\n%s\nand:\n%s", a.String(), b.String())
+	}
+
+	for _, args := range [][]string{
+		{"-frames", "0", refs},
+		{"-algorithms", "mru", refs},
+		{"-generate", "10", refs},
+		{},
+	} {
+		err := run(context.Background(), io.Discard, append([]string{"binary_name", "paging"}, args...)...)
+		if !errors.Is(err, ErrInvalidArgs) {
+			t.Errorf("paging %v error = %v, want %v", args, err, ErrInvalidArgs)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/rks0134/CSCE4600/Project1/concurrency"
+	"github.com/rks0134/CSCE4600/Project1/scheduler"
+)
+
+// philosophersCmd compares strategies for the dining philosophers.
+type philosophersCmd struct {
+	*globalFlags
+	philosophers int
+	strategies   string
+	think        string
+	eat          string
+	reach        int
+	duration     int
+	starvation   int
+}
+
+func (c *philosophersCmd) defineFlags(flags *flag.FlagSet) {
+	flags.IntVar(&c.philosophers, "philosophers", 5, "number of `philosophers`")
+	flags.StringVar(&c.strategies, "strategies", "all", "comma separated `strategies` to compare (naive, ordering, waiter, chandy-misra), or all")
+	flags.StringVar(&c.think, "think", "1..10", "time a philosopher thinks, a `duration` or a range lo..hi to draw from")
+	flags.StringVar(&c.eat, "eat", "2..6", "time a philosopher eats, a `duration` or a range lo..hi to draw from")
+	flags.IntVar(&c.reach, "reach", 1, "`time` a philosopher taking one fork at a time takes to reach for the second")
+	flags.IntVar(&c.duration, "duration", 200, "`time` to simulate")
+	flags.IntVar(&c.starvation, "starvation", 50, "`time` hungry that counts as starving")
+}
+
+func (c *philosophersCmd) run(_ context.Context, w io.Writer, args []string) error {
+	if len(args) > 0 {
+		return fmt.Errorf("%w: philosophers takes no arguments", ErrInvalidArgs)
+	}
+	var strategies []concurrency.Strategy
+	if c.strategies == "all" {
+		strategies = concurrency.Strategies
+	} else {
+		for _, name := range strings.Split(c.strategies, ",") {
+			s, ok := concurrency.FindStrategy(strings.TrimSpace(name))
+			if !ok {
+				return fmt.Errorf("%w: unknown dining philosophers strategy %q", ErrInvalidArgs, name)
+			}
+			strategies = append(strategies, s)
+		}
+	}
+	cfg := concurrency.PhilosophersConfig{Philosophers: c.philosophers, Reach: c.reach, Duration: c.duration}
+	var err error
+	if cfg.Think, err = parseDurationRange(c.think); err != nil {
+		return fmt.Errorf("%w: -think: %v", ErrInvalidArgs, err)
+	}
+	if cfg.Eat, err = parseDurationRange(c.eat); err != nil {
+		return fmt.Errorf("%w: -eat: %v", ErrInvalidArgs, err)
+	}
+	if c.starvation < 1 {
+		return fmt.Errorf("%w: -starvation must be positive", ErrInvalidArgs)
+	}
+
+	// Every strategy draws the same durations from the same seed, so they
+	// are compared on the same appetites.
+	var results []*concurrency.PhilosophersResult
+	for _, s := range strategies {
+		r, err := concurrency.SimulatePhilosophers(s, cfg, c.rand())
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidArgs, err)
+		}
+		results = append(results, r)
+	}
+
+	scheduler.RenderTitle(w, fmt.Sprintf("Dining philosophers, %d at the table", c.philosophers))
+	_, _ = fmt.Fprintf(w, "Seed: %d\n\n", c.seed.value)
+	_, _ = fmt.Fprintf(w, "Thinking takes %s, eating %s, and reaching for a second fork %d\n\n", cfg.Think, cfg.Eat, cfg.Reach)
+	for _, r := range results {
+		concurrency.RenderPhilosophers(w, r)
+	}
+	concurrency.RenderPhilosophersComparison(w, results, c.starvation)
+
+	return nil
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func Test_runPhilosophers(t *testing.T) {
+	t.Parallel()
+	var a, b strings.Builder
+	for _, w := range []*strings.Builder{&a, &b} {
+		if err := run(context.Background(), w, "binary_name", "-seed", "1", "philosophers", "-duration", "80"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if a.String() != b.String() {
+		t.Errorf("philosophers with the same seed wrote:\n%s\nand:\n%s", a.String(), b.String())
+	}
+	for _, want := range []string{
+		"Dining philosophers, 5 at the table",
+		"Seed: 1\n",
+		"Thinking takes 1 to 10, eating 2 to 6, and reaching for a second fork 1\n",
+		"Naive, left fork first\n",
+		"Chandy-Misra\n",
+		"    P5 |",
+		"       '=' eating, '.' hungry, ' ' thinking\n",
+		"| naive        | none     |",
+	} {
+		if !strings.Contains(a.String(), want) {
+			t.Errorf("philosophers wrote:\n%s\nwant it to contain %q", a.String(), want)
+		}
+	}
+
+	var naive strings.Builder
+	if err := run(context.Background(), &naive, "binary_name", "-seed", "1", "philosophers", "-strategies", "naive", "-think", "1..3", "-eat", "2..5", "-reach", "2", "-duration", "100"); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(naive.String(), "Deadlock at ") || strings.Contains(naive.String(), "Resource ordering") {
+		t.Errorf("naive philosophers wrote:\n%s\nwant only naive, deadlocked", naive.String())
+	}
+
+	for _, args := range [][]string{
+		{"-philosophers", "1"},
+		{"-strategies", "naive,bogus"},
+		{"-think", "0"},
+		{"-eat", "5..2"},
+		{"-starvation", "0"},
+		{"extra"},
+	} {
+		err := run(context.Background(), io.Discard, append([]string{"binary_name", "philosophers"}, args...)...)
+		if !errors.Is(err, ErrInvalidArgs) {
+			t.Errorf("philosophers %v error = %v, want %v", args, err, ErrInvalidArgs)
+		}
+	}
+}
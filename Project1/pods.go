@@ -0,0 +1,389 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/rks0134/CSCE4600/Project1/scheduler"
+)
+
+// Kubernetes' built-in priority classes, which need no manifest.
+var builtinPriorityClasses = map[string]int64{
+	"system-node-critical":    2000001000,
+	"system-cluster-critical": 2000000000,
+}
+
+// podsCmd writes a workload of one process per pod in Kubernetes manifests,
+// weighted by the pods' CPU requests and ranked by their priority classes.
+type podsCmd struct {
+	burst int64
+}
+
+func (c *podsCmd) defineFlags(flags *flag.FlagSet) {
+	flags.Int64Var(&c.burst, "burst", 10, "the burst of every pod's process, so only their requests and priorities tell them apart")
+}
+
+func (c *podsCmd) run(_ context.Context, w io.Writer, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("%w: must give the manifests or directories of manifests to import", ErrInvalidArgs)
+	}
+	if c.burst <= 0 {
+		return fmt.Errorf("%w: -burst must be positive", ErrInvalidArgs)
+	}
+	var objects []kubeObject
+	for _, arg := range args {
+		err := filepath.WalkDir(arg, func(path string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() {
+				return err
+			}
+			if path != arg && !isManifest(path) {
+				return nil
+			}
+			found, err := readManifests(path)
+			if err != nil {
+				return &fileError{path: path, err: err}
+			}
+			objects = append(objects, found...)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	pods, err := kubePods(objects)
+	if err != nil {
+		return err
+	}
+	if len(pods) == 0 {
+		return fmt.Errorf("%w: no pods or workloads in %s", ErrInvalidArgs, strings.Join(args, ", "))
+	}
+
+	return writeWorkload(w, podWorkload(pods, c.burst))
+}
+
+// isManifest reports whether path is named as a JSON or YAML manifest.
+func isManifest(path string) bool {
+	switch filepath.Ext(path) {
+	case ".json", ".yaml", ".yml":
+		return true
+	}
+	return false
+}
+
+// kubeObject is as much of a Kubernetes object as importing pods needs.
+type kubeObject struct {
+	Kind     string `json:"kind"`
+	Metadata struct {
+		Name      string `json:"name"`
+		Namespace string `json:"namespace"`
+	} `json:"metadata"`
+	Spec json.RawMessage `json:"spec"`
+	// Items are the objects of a List.
+	Items []kubeObject `json:"items"`
+	// Value and GlobalDefault are a PriorityClass's.
+	Value         int64 `json:"value"`
+	GlobalDefault bool  `json:"globalDefault"`
+}
+
+// kubePodSpec is a pod's spec, or a workload's pod template's.
+type kubePodSpec struct {
+	Containers []struct {
+		Resources struct {
+			Requests map[string]kubeQuantity `json:"requests"`
+			Limits   map[string]kubeQuantity `json:"limits"`
+		} `json:"resources"`
+	} `json:"containers"`
+	PriorityClassName string `json:"priorityClassName"`
+	Priority          *int64 `json:"priority"`
+}
+
+// kubeWorkload is the spec of a Deployment, StatefulSet, ReplicaSet, Job,
+// or the like: pods made from a template.
+type kubeWorkload struct {
+	Replicas    *int64 `json:"replicas"`
+	Parallelism *int64 `json:"parallelism"`
+	Template    *struct {
+		Spec kubePodSpec `json:"spec"`
+	} `json:"template"`
+	// JobTemplate is a CronJob's.
+	JobTemplate *struct {
+		Spec kubeWorkload `json:"spec"`
+	} `json:"jobTemplate"`
+}
+
+// kubeQuantity is a resource quantity, which manifests write as a string
+// such as "500m" or as a number.
+type kubeQuantity string
+
+func (q *kubeQuantity) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		var n json.Number
+		if err := json.Unmarshal(data, &n); err != nil {
+			return fmt.Errorf("quantity %s is neither a string nor a number", data)
+		}
+		s = n.String()
+	}
+	*q = kubeQuantity(s)
+	return nil
+}
+
+// millicores returns a CPU quantity in thousandths of a CPU, rounded up as
+// Kubernetes does.
+func (q kubeQuantity) millicores() (int64, error) {
+	s := string(q)
+	scale := 1000.0
+	if strings.HasSuffix(s, "m") {
+		s, scale = strings.TrimSuffix(s, "m"), 1
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil || f < 0 {
+		return 0, fmt.Errorf("malformed CPU quantity %q", string(q))
+	}
+	milli := f * scale
+	if n := int64(milli); float64(n) < milli {
+		return n + 1, nil
+	}
+	return int64(milli), nil
+}
+
+// readManifests reads the objects in the manifest at path: JSON, such as
+// kubectl get -o json writes, or YAML documents.
+func readManifests(path string) ([]kubeObject, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var objects []kubeObject
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && (trimmed[0] == '{' || trimmed[0] == '[') {
+		d := json.NewDecoder(bytes.NewReader(trimmed))
+		for d.More() {
+			var o kubeObject
+			if err := d.Decode(&o); err != nil {
+				return nil, &scheduler.ParseError{Err: err}
+			}
+			objects = append(objects, o)
+		}
+		return objects, nil
+	}
+	d := yaml.NewDecoder(bytes.NewReader(data))
+	for {
+		var doc interface{}
+		if err := d.Decode(&doc); err == io.EOF {
+			return objects, nil
+		} else if err != nil {
+			return nil, &scheduler.ParseError{Err: err}
+		}
+		if doc == nil {
+			continue
+		}
+		// Through JSON, so the objects decode as kubectl's output does.
+		j, err := json.Marshal(jsonValue(doc))
+		if err != nil {
+			return nil, &scheduler.ParseError{Err: err}
+		}
+		var o kubeObject
+		if err := json.Unmarshal(j, &o); err != nil {
+			return nil, &scheduler.ParseError{Err: err}
+		}
+		objects = append(objects, o)
+	}
+}
+
+// jsonValue returns the YAML value v with the keys of its mappings as
+// strings, as JSON needs them.
+func jsonValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, e := range v {
+			v[k] = jsonValue(e)
+		}
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for k, e := range v {
+			m[fmt.Sprint(k)] = jsonValue(e)
+		}
+		return m
+	case []interface{}:
+		for i, e := range v {
+			v[i] = jsonValue(e)
+		}
+	}
+	return v
+}
+
+// kubePod is a pod to schedule, with its replicas: its name, CPU request
+// in millicores, and priority value.
+type kubePod struct {
+	name     string
+	cpu      int64
+	priority int64
+	replicas int64
+	// explicit is set when the spec gives the priority value itself
+	// rather than its class.
+	explicit  bool
+	className string
+}
+
+// kubePods returns the pods objects run, each workload's pods as one with
+// its replica count, resolving priority classes among the objects.
+func kubePods(objects []kubeObject) ([]kubePod, error) {
+	classes := make(map[string]int64, len(builtinPriorityClasses))
+	for name, value := range builtinPriorityClasses {
+		classes[name] = value
+	}
+	var defaultPriority int64
+	var pods []kubePod
+	var walk func(objects []kubeObject) error
+	walk = func(objects []kubeObject) error {
+		for _, o := range objects {
+			name := o.Kind + "/" + o.Metadata.Name
+			if o.Metadata.Namespace != "" {
+				name = o.Metadata.Namespace + "/" + name
+			}
+			var spec kubePodSpec
+			replicas := int64(1)
+			switch o.Kind {
+			case "List", "PodList":
+				if err := walk(o.Items); err != nil {
+					return err
+				}
+				continue
+			case "PriorityClass":
+				classes[o.Metadata.Name] = o.Value
+				if o.GlobalDefault {
+					defaultPriority = o.Value
+				}
+				continue
+			case "Pod":
+				if len(o.Spec) > 0 {
+					if err := json.Unmarshal(o.Spec, &spec); err != nil {
+						return fmt.Errorf("%s: %w", name, err)
+					}
+				}
+			default:
+				var wl kubeWorkload
+				if len(o.Spec) > 0 {
+					if err := json.Unmarshal(o.Spec, &wl); err != nil {
+						return fmt.Errorf("%s: %w", name, err)
+					}
+				}
+				if wl.JobTemplate != nil {
+					wl = wl.JobTemplate.Spec
+				}
+				if wl.Template == nil {
+					continue // not a workload
+				}
+				spec = wl.Template.Spec
+				switch {
+				case wl.Replicas != nil:
+					replicas = *wl.Replicas
+				case wl.Parallelism != nil:
+					replicas = *wl.Parallelism
+				}
+			}
+			pod := kubePod{name: name, replicas: replicas, className: spec.PriorityClassName}
+			for _, c := range spec.Containers {
+				q, ok := c.Resources.Requests["cpu"]
+				if !ok {
+					// Without a request, a container requests its limit.
+					q, ok = c.Resources.Limits["cpu"]
+				}
+				if !ok {
+					continue
+				}
+				milli, err := q.millicores()
+				if err != nil {
+					return fmt.Errorf("%s: %w", name, err)
+				}
+				pod.cpu += milli
+			}
+			if spec.Priority != nil {
+				pod.priority, pod.explicit = *spec.Priority, true
+			}
+			pods = append(pods, pod)
+		}
+		return nil
+	}
+	if err := walk(objects); err != nil {
+		return nil, err
+	}
+	for k := range pods {
+		p := &pods[k]
+		switch {
+		case p.explicit:
+		case p.className == "":
+			p.priority = defaultPriority
+		default:
+			value, ok := classes[p.className]
+			if !ok {
+				return nil, fmt.Errorf("%w: %s: unknown priority class %q", ErrInvalidArgs, p.name, p.className)
+			}
+			p.priority = value
+		}
+	}
+	sort.SliceStable(pods, func(i, j int) bool { return pods[i].name < pods[j].name })
+
+	return pods, nil
+}
+
+// podWorkload makes a process of every replica of pods, numbered from 1,
+// each with the given burst. Its nice value gives it the share of the CPU
+// its request gets, by way of the cgroup weight the kubelet sets for it,
+// and priorities rank the pods' priority values from 1, the highest, to 50.
+func podWorkload(pods []kubePod, burst int64) []scheduler.Process {
+	var values []int64
+	for _, p := range pods {
+		values = append(values, p.priority)
+	}
+	sort.Slice(values, func(i, j int) bool { return values[i] > values[j] })
+	rank := make(map[int64]int64)
+	for _, v := range values {
+		if _, ok := rank[v]; !ok {
+			rank[v] = int64(len(rank)) + 1
+		}
+	}
+	var processes []scheduler.Process
+	for _, p := range pods {
+		priority := rank[p.priority]
+		if priority > 50 {
+			priority = 50
+		}
+		for r := int64(0); r < p.replicas; r++ {
+			processes = append(processes, scheduler.Process{
+				ProcessID:     int64(len(processes)) + 1,
+				BurstDuration: burst,
+				Priority:      priority,
+				Nice:          weightNice(requestWeight(p.cpu)),
+			})
+		}
+	}
+
+	return processes
+}
+
+// requestWeight returns the cgroup v2 cpu.weight the kubelet gives a
+// container requesting milli thousandths of a CPU: its cpu.shares of 1024
+// per CPU, at least 2, converted to a weight as runc does.
+func requestWeight(milli int64) int64 {
+	shares := milli * 1024 / 1000
+	if shares < 2 {
+		shares = 2
+	}
+	if shares > 262144 {
+		shares = 262144
+	}
+	return 1 + (shares-2)*9999/262142
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/rks0134/CSCE4600/Project1/scheduler"
+)
+
+func Test_readManifests(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		yaml    string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "anchors, aliases, and merge keys",
+			yaml: "kind: Pod\nmetadata: &meta {name: a, namespace: shop}\nspec: {}\n---\nkind: Pod\nmetadata:\n  <<: *meta\n  name: b\nspec: {}\n",
+			want: []string{"shop/Pod/a", "shop/Pod/b"},
+		},
+		{
+			name: "multi-line plain scalars",
+			yaml: "kind: Pod\nmetadata:\n  name: web\n  annotations:\n    note: a long\n      note on two lines\nspec: {}\n",
+			want: []string{"/Pod/web"},
+		},
+		{
+			name: "tags and non-string keys",
+			yaml: "kind: !!str Pod\nmetadata: {name: !custom tagged, 1: one}\nspec: {}\n",
+			want: []string{"/Pod/tagged"},
+		},
+		{name: "empty documents", yaml: "---\n# nothing\n---\n...\n", want: []string{}},
+		{name: "bad indentation", yaml: "kind: Pod\n  name: x\n", wantErr: true},
+		{name: "undefined alias", yaml: "kind: *missing\n", wantErr: true},
+		{name: "not an object", yaml: "- a\n- b\n", wantErr: true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			path := filepath.Join(t.TempDir(), "manifest.yaml")
+			if err := os.WriteFile(path, []byte(tt.yaml), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			objects, err := readManifests(path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("readManifests() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				if !errors.Is(err, scheduler.ErrParse) {
+					t.Errorf("readManifests() error = %v, want a parse error", err)
+				}
+				return
+			}
+			got := []string{}
+			for _, o := range objects {
+				got = append(got, o.Metadata.Namespace+"/"+o.Kind+"/"+o.Metadata.Name)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("readManifests() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_runPods(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	files := map[string]string{
+		"web.yaml": `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  namespace: shop
+spec:
+  replicas: 2
+  template:
+    spec:
+      priorityClassName: high
+      containers:
+      - name: nginx
+        resources:
+          requests:
+            cpu: 500m
+      - name: sidecar
+        resources:
+          limits: {cpu: 0.5}
+---
+apiVersion: scheduling.k8s.io/v1
+kind: PriorityClass
+metadata:
+  name: high
+value: 1000
+`,
+		"nested/jobs.json": `{"kind": "List", "items": [
+	{"kind": "CronJob", "metadata": {"name": "report", "namespace": "shop"}, "spec": {"jobTemplate": {"spec": {"parallelism": 2, "template": {"spec": {"containers": [{"resources": {"requests": {"cpu": "2"}}}]}}}}}},
+	{"kind": "Service", "metadata": {"name": "web"}, "spec": {"ports": [{"port": 80}]}}
+]}
+{"kind": "Pod", "metadata": {"name": "dns", "namespace": "kube-system"}, "spec": {"priorityClassName": "system-cluster-critical", "containers": [{"resources": {"requests": {"cpu": "100m"}}}]}}`,
+		"README.md": "not a manifest",
+	}
+	for name, data := range files {
+		if err := os.MkdirAll(filepath.Dir(filepath.Join(dir, name)), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var b strings.Builder
+	if err := run(context.Background(), &b, "binary_name", "pods", dir); err != nil {
+		t.Fatal(err)
+	}
+	// kube-system/Pod/dns, shop/CronJob/report twice, shop/Deployment/web
+	// twice: 100m is weight 4, 2 CPUs 78, and 1 CPU 39.
+	want := "1,10,0,1,nice=14\n2,10,0,3,nice=1\n3,10,0,3,nice=1\n4,10,0,2,nice=4\n5,10,0,2,nice=4\n"
+	if b.String() != want {
+		t.Errorf("pods wrote\n%s\nwant\n%s", b.String(), want)
+	}
+
+	unknown := filepath.Join(dir, "unknown.yaml")
+	if err := os.WriteFile(unknown, []byte("kind: Pod\nmetadata: {name: x}\nspec:\n  priorityClassName: nope\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := run(context.Background(), io.Discard, "binary_name", "pods", unknown); !errors.Is(err, ErrInvalidArgs) || !strings.Contains(err.Error(), `"nope"`) {
+		t.Errorf("pods with an unknown priority class error = %v, want invalid args naming it", err)
+	}
+	if err := run(context.Background(), io.Discard, "binary_name", "pods", filepath.Join(dir, "README.md")); exitCode(err) != ExitParse {
+		t.Errorf("pods of a file that is not a manifest error = %v, want a parse error", err)
+	}
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"runtime/pprof"
+)
+
+// startProfiling starts CPU profiling into cpuFile, if set, and returns a
+// function that stops it and writes a heap profile into memFile, if set.
+func startProfiling(cpuFile, memFile string) (stop func() error, err error) {
+	stopCPU := func() error { return nil }
+	if cpuFile != "" {
+		f, err := os.Create(cpuFile)
+		if err != nil {
+			return nil, fmt.Errorf("%w: creating CPU profile", err)
+		}
+		if err := pprof.StartCPUProfile(f); err != nil {
+			_ = f.Close()
+			return nil, fmt.Errorf("%w: starting CPU profile", err)
+		}
+		stopCPU = func() error {
+			pprof.StopCPUProfile()
+			return f.Close()
+		}
+	}
+
+	return func() error {
+		if err := stopCPU(); err != nil {
+			return fmt.Errorf("%w: writing CPU profile", err)
+		}
+		if memFile == "" {
+			return nil
+		}
+		f, err := os.Create(memFile)
+		if err != nil {
+			return fmt.Errorf("%w: creating heap profile", err)
+		}
+		runtime.GC() // get up-to-date statistics
+		if err := pprof.WriteHeapProfile(f); err != nil {
+			_ = f.Close()
+			return fmt.Errorf("%w: writing heap profile", err)
+		}
+		return f.Close()
+	}, nil
+}
@@ -0,0 +1,23 @@
+package main
+
+import (
+	"context"
+	"io"
+	"os"
+	"path"
+	"testing"
+)
+
+func Test_runProfiles(t *testing.T) {
+	dir := t.TempDir()
+	cpu, mem := path.Join(dir, "cpu.pprof"), path.Join(dir, "mem.pprof")
+	err := run(context.Background(), io.Discard, "binary_name", "-cpuprofile", cpu, "-memprofile", mem, "example_processes.csv")
+	if err != nil {
+		t.Fatalf("run() error = %v", err)
+	}
+	for _, p := range []string{cpu, mem} {
+		if fi, err := os.Stat(p); err != nil || fi.Size() == 0 {
+			t.Errorf("profile %s was not written: %v", p, err)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// progressMu serializes progress bars, which algorithms running at once
+// redraw from their own goroutines.
+var progressMu sync.Mutex
+
+// progressBar prints a periodic percentage for long runs.
+type progressBar struct {
+	w     io.Writer
+	label string
+	last  int
+}
+
+func newProgressBar(w io.Writer, label string) *progressBar {
+	return &progressBar{w: w, label: label, last: -1}
+}
+
+// update redraws the percentage when it changes and ends the line once done.
+func (p *progressBar) update(done, total int) {
+	if total <= 0 {
+		return
+	}
+	pct := done * 100 / total
+	if pct == p.last {
+		return
+	}
+	p.last = pct
+	progressMu.Lock()
+	defer progressMu.Unlock()
+	_, _ = fmt.Fprintf(p.w, "\r%s %3d%%", p.label, pct)
+	if done >= total {
+		_, _ = fmt.Fprintln(p.w)
+	}
+}
+
+// progressOutput returns where progress is reported for the -progress mode,
+// or nil when it is disabled. In auto mode progress is only shown when stderr
+// is a terminal, so it never ends up in redirected logs.
+func progressOutput(mode string) (io.Writer, error) {
+	switch mode {
+	case "always":
+		return os.Stderr, nil
+	case "never":
+		return nil, nil
+	case "auto":
+		if isTerminal(os.Stderr) {
+			return os.Stderr, nil
+		}
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("%w: unknown progress mode %q", ErrInvalidArgs, mode)
+	}
+}
+
+func isTerminal(f *os.File) bool {
+	fi, err := f.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,29 @@
+package main
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func Test_progressBar(t *testing.T) {
+	t.Parallel()
+	var w strings.Builder
+	bar := newProgressBar(&w, "rr")
+	for done := 0; done <= 200; done++ {
+		bar.update(done, 200)
+	}
+	if got := strings.Count(w.String(), "\r"); got != 101 {
+		t.Errorf("progress redrawn %d times, want once per percent (101)", got)
+	}
+	if !strings.HasSuffix(w.String(), "\rrr 100%\n") {
+		t.Errorf("progress did not finish at 100%%: %q", w.String())
+	}
+
+	if _, err := progressOutput("sometimes"); !errors.Is(err, ErrInvalidArgs) {
+		t.Errorf("progressOutput(sometimes) error = %v, want %v", err, ErrInvalidArgs)
+	}
+	if w, err := progressOutput("never"); w != nil || err != nil {
+		t.Errorf("progressOutput(never) = %v, %v, want nil, nil", w, err)
+	}
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"github.com/rks0134/CSCE4600/Project1/scheduler"
+)
+
+// checkpointWriter saves checkpoints to a file, one per line.
+type checkpointWriter struct {
+	path string
+	f    *os.File
+}
+
+// createCheckpoints creates the checkpoint file at path.
+func createCheckpoints(path string) (*checkpointWriter, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("%w: error creating checkpoint file", err)
+	}
+
+	return &checkpointWriter{path: path, f: f}, nil
+}
+
+func (cw *checkpointWriter) save(cp *scheduler.Checkpoint) error {
+	if err := cp.Save(cw.f); err != nil {
+		return &fileError{path: cw.path, err: err}
+	}
+	return nil
+}
+
+func (cw *checkpointWriter) close() error {
+	if err := cw.f.Close(); err != nil {
+		return fmt.Errorf("%w: error closing checkpoint file", err)
+	}
+	return nil
+}
+
+// resumeCmd continues the simulations saved by schedule -checkpoint.
+type resumeCmd struct {
+	*globalFlags
+	reportFlags
+}
+
+func (c *resumeCmd) defineFlags(flags *flag.FlagSet) {
+	c.reportFlags.define(flags)
+}
+
+func (c *resumeCmd) run(ctx context.Context, w io.Writer, args []string) (err error) {
+	render, err := c.renderer()
+	if err != nil {
+		return err
+	}
+	if len(args) != 1 {
+		return fmt.Errorf("%w: must give a checkpoint file to resume", ErrInvalidArgs)
+	}
+	cps, err := loadCheckpoints(args[0])
+	if err != nil {
+		return err
+	}
+
+	end := c.begin(w)
+	defer func() {
+		if endErr := end(); err == nil {
+			err = endErr
+		}
+	}()
+	for _, cp := range cps {
+		alg, ok := findAlgorithm(cp.Algorithm)
+		if !ok {
+			return &fileError{path: args[0], err: fmt.Errorf("%w: unknown algorithm %q", ErrInvalidArgs, cp.Algorithm)}
+		}
+		progress, err := c.progressOption(alg.name)
+		if err != nil {
+			return err
+		}
+		s, ok := alg.new(progress).(scheduler.Resumer)
+		if !ok {
+			return fmt.Errorf("%w: %s cannot resume checkpoints", ErrInvalidArgs, alg.name)
+		}
+		r, err := s.Resume(ctx, cp)
+		if renderErr := c.render(w, render, alg.title, r); err == nil {
+			err = renderErr
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// loadCheckpoints loads every checkpoint saved in the file at path.
+func loadCheckpoints(path string) ([]*scheduler.Checkpoint, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("%w: error opening checkpoint file", err)
+	}
+	defer func() {
+		if err := f.Close(); err != nil {
+			log.Print(err)
+		}
+	}()
+
+	cps, err := scheduler.LoadCheckpoints(f)
+	if err != nil {
+		return nil, &fileError{path: path, err: err}
+	}
+
+	return cps, nil
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"context"
+	"io"
+	"path"
+	"strings"
+	"testing"
+)
+
+func Test_runCheckpoint(t *testing.T) {
+	t.Parallel()
+	file := path.Join(t.TempDir(), "run.ckpt")
+	var want, paused, got strings.Builder
+	if err := run(context.Background(), &want, "binary_name", "schedule", "-seed", "3", "example_processes.csv"); err != nil {
+		t.Fatalf("schedule error = %v", err)
+	}
+	if err := run(context.Background(), &paused, "binary_name", "schedule", "-seed", "3", "-checkpoint-at", "5", "-checkpoint", file, "example_processes.csv"); err != nil {
+		t.Fatalf("schedule -checkpoint error = %v", err)
+	}
+	if !strings.Contains(paused.String(), "(stopped at 5, 1 of 3 processes completed)") {
+		t.Errorf("paused schedule does not say where it stopped:\n%s", paused.String())
+	}
+	if err := run(context.Background(), &got, "binary_name", "resume", file); err != nil {
+		t.Fatalf("resume error = %v", err)
+	}
+	if got.String() != want.String() {
+		t.Errorf("resume output =\n%s\nwant\n%s", got.String(), want.String())
+	}
+
+	for _, args := range [][]string{
+		{"schedule", "-checkpoint-at", "5", "example_processes.csv"},
+		{"schedule", "-checkpoint", file, "example_processes.csv"},
+		{"resume"},
+		{"resume", "example_processes.csv"},
+	} {
+		err := run(context.Background(), io.Discard, append([]string{"binary_name"}, args...)...)
+		if code := exitCode(err); code != ExitInvalidArgs && code != ExitParse {
+			t.Errorf("run(%v) exit code = %d, want %d or %d", args, code, ExitInvalidArgs, ExitParse)
+		}
+	}
+}
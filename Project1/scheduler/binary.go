@@ -0,0 +1,102 @@
+package scheduler
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// The binary workload format stores processes as fixed-size records, so a
+// file of millions of them loads by decoding memory-mapped records in place
+// rather than parsing text. It is a header of binaryMagic, the ticks per
+// unit of time, and the number of records, followed by the records, every
+// field a little-endian int64:
+//
+//	ProcessID, BurstDuration, ArrivalTime, Priority, Nice, Memory, KillAt, Deadline, Period
+//
+// Only those fields are stored, so processes with I/O cycles, affinity,
+// locks, a parent, or suspensions cannot be written in it.
+const (
+	binaryMagic  = "CSCEWL\x00\x01"
+	binaryHeader = len(binaryMagic) + 2*8
+	binaryFields = 9
+	binaryRecord = binaryFields * 8
+)
+
+// IsBinary reports whether r starts with the header of a binary workload.
+func IsBinary(r io.ReaderAt) bool {
+	magic := make([]byte, len(binaryMagic))
+	_, err := r.ReadAt(magic, 0)
+	return err == nil && string(magic) == binaryMagic
+}
+
+// WriteBinary writes processes, whose times are in ticks of which ticks make
+// one unit of time, to w in the binary workload format.
+func WriteBinary(w io.Writer, processes []Process, ticks int64) error {
+	for i, p := range processes {
+		if len(p.Cycles) > 0 || len(p.Affinity) > 0 || len(p.Locks) > 0 || p.Parent != 0 || len(p.Suspensions) > 0 || p.Job > 0 {
+			return &ValidationError{Row: i + 1, Err: errors.New("I/O cycles, affinity, locks, parents, suspensions, and periodic jobs cannot be stored in a binary workload")}
+		}
+	}
+	buf := make([]byte, binaryHeader, binaryHeader+binaryRecord*len(processes))
+	copy(buf, binaryMagic)
+	binary.LittleEndian.PutUint64(buf[len(binaryMagic):], uint64(ticks))
+	binary.LittleEndian.PutUint64(buf[len(binaryMagic)+8:], uint64(len(processes)))
+	for _, p := range processes {
+		for _, v := range [binaryFields]int64{p.ProcessID, p.BurstDuration, p.ArrivalTime, p.Priority, p.Nice, p.Memory, p.KillAt, p.Deadline, p.Period} {
+			buf = binary.LittleEndian.AppendUint64(buf, uint64(v))
+		}
+	}
+	_, err := w.Write(buf)
+
+	return err
+}
+
+// LoadBinary reads the processes of the binary workload in f, along with the
+// number of ticks per unit of time their times are in. The file is mapped
+// into memory where the platform allows, so only the pages decoded are ever
+// read, and unmapped again before LoadBinary returns.
+func LoadBinary(f *os.File) ([]Process, int64, error) {
+	fi, err := f.Stat()
+	if err != nil {
+		return nil, 0, err
+	}
+	data, unmap, err := mapFile(f, fi.Size())
+	if err != nil {
+		return nil, 0, &ParseError{Err: fmt.Errorf("%w: mapping binary workload", err)}
+	}
+	defer func() { _ = unmap() }()
+
+	return decodeBinary(data)
+}
+
+// decodeBinary decodes a binary workload.
+func decodeBinary(data []byte) ([]Process, int64, error) {
+	if len(data) < binaryHeader || !bytes.Equal(data[:len(binaryMagic)], []byte(binaryMagic)) {
+		return nil, 0, &ParseError{Err: errors.New("not a binary workload")}
+	}
+	ticks := int64(binary.LittleEndian.Uint64(data[len(binaryMagic):]))
+	n := binary.LittleEndian.Uint64(data[len(binaryMagic)+8:])
+	records := data[binaryHeader:]
+	if ticks < 1 || n != uint64(len(records)/binaryRecord) || len(records)%binaryRecord != 0 {
+		return nil, 0, &ParseError{Err: fmt.Errorf("binary workload header gives %d ticks per unit and %d records for %d bytes of records", ticks, n, len(records))}
+	}
+
+	processes := make([]Process, n)
+	for i := range processes {
+		r := records[i*binaryRecord : (i+1)*binaryRecord]
+		var v [binaryFields]int64
+		for k := range v {
+			v[k] = int64(binary.LittleEndian.Uint64(r[k*8:]))
+		}
+		processes[i] = Process{
+			ProcessID: v[0], BurstDuration: v[1], ArrivalTime: v[2], Priority: v[3],
+			Nice: v[4], Memory: v[5], KillAt: v[6], Deadline: v[7], Period: v[8],
+		}
+	}
+
+	return processes, ticks, nil
+}
@@ -0,0 +1,18 @@
+//go:build !unix
+
+package scheduler
+
+import (
+	"io"
+	"os"
+)
+
+// mapFile reads the size bytes of f, on platforms without mmap.
+func mapFile(f *os.File, size int64) ([]byte, func() error, error) {
+	data := make([]byte, size)
+	if _, err := io.ReadFull(io.NewSectionReader(f, 0, size), data); err != nil {
+		return nil, nil, err
+	}
+
+	return data, func() error { return nil }, nil
+}
@@ -0,0 +1,52 @@
+package scheduler
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestBinary(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: 1, BurstDuration: 25, ArrivalTime: 0, Priority: 3, Nice: -5, Memory: 64},
+		{ProcessID: 9, BurstDuration: 10, ArrivalTime: 5, KillAt: 40, Deadline: 20, Period: 30},
+	}
+	path := filepath.Join(t.TempDir(), "w.wl")
+	var buf bytes.Buffer
+	if err := WriteBinary(&buf, processes, 10); err != nil {
+		t.Fatalf("WriteBinary() error = %v", err)
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if !IsBinary(f) {
+		t.Error("IsBinary() = false for a binary workload")
+	}
+	got, ticks, err := LoadBinary(f)
+	if err != nil {
+		t.Fatalf("LoadBinary() error = %v", err)
+	}
+	if ticks != 10 || !reflect.DeepEqual(got, processes) {
+		t.Errorf("LoadBinary() = %v, %d, want %v, 10", got, ticks, processes)
+	}
+
+	if IsBinary(bytes.NewReader([]byte("1,5,0,2\n"))) {
+		t.Error("IsBinary() = true for a scheduling file")
+	}
+	if _, _, err := decodeBinary(buf.Bytes()[:buf.Len()-1]); !errors.Is(err, ErrParse) {
+		t.Errorf("decodeBinary() of a truncated workload error = %v, want %v", err, ErrParse)
+	}
+	err = WriteBinary(&buf, []Process{{ProcessID: 1, BurstDuration: 1, Cycles: []Cycle{{IO: 1, CPU: 1}}}}, 1)
+	if !errors.Is(err, ErrValidation) {
+		t.Errorf("WriteBinary() of a process with cycles error = %v, want %v", err, ErrValidation)
+	}
+}
@@ -0,0 +1,341 @@
+package scheduler
+
+import (
+	"container/heap"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Checkpoint is the full state of a simulation paused by WithCheckpoint:
+// the clock, the pending events, the ready queue, every core's run, and the
+// remaining work and history of every process. A Resumer continues it as if
+// it had never stopped: the result is the one an uninterrupted run gives,
+// without its Ideal metrics.
+type Checkpoint struct {
+	// Algorithm names the scheduler that was paused, for callers saving
+	// checkpoints of several; schedulers leave it empty.
+	Algorithm string
+	// At is the time the simulation was paused at.
+	At int64
+
+	state json.RawMessage
+}
+
+// Resumer is a Scheduler that can continue a simulation from a checkpoint
+// it, or a scheduler of the same algorithm, took. Every scheduler returned
+// by the New* constructors is one.
+type Resumer interface {
+	Scheduler
+	// Resume continues the simulation saved in cp with the options it was
+	// started with, except for WithProgress, WithMaxTime, and
+	// WithCheckpoint, which are taken from the Resumer so a resumed
+	// simulation can be paused again. The result covers the whole
+	// simulation, from before the checkpoint too, but has no Ideal metrics.
+	Resume(ctx context.Context, cp *Checkpoint) (*ScheduleResult, error)
+}
+
+var (
+	_ Resumer = (*fcfs)(nil)
+	_ Resumer = (*sjf)(nil)
+	_ Resumer = (*priority)(nil)
+	_ Resumer = (*rr)(nil)
+	_ Resumer = (*edf)(nil)
+	_ Resumer = (*rm)(nil)
+)
+
+// checkpointFile is how a Checkpoint is saved.
+type checkpointFile struct {
+	Algorithm string          `json:"algorithm,omitempty"`
+	At        int64           `json:"at"`
+	State     json.RawMessage `json:"state"`
+}
+
+// Save writes cp to w as a single JSON object followed by a newline, so a
+// file may hold the checkpoints of several schedulers one per line.
+func (cp *Checkpoint) Save(w io.Writer) error {
+	return json.NewEncoder(w).Encode(checkpointFile{Algorithm: cp.Algorithm, At: cp.At, State: cp.state})
+}
+
+// LoadCheckpoints reads every checkpoint saved to r, in order.
+func LoadCheckpoints(r io.Reader) ([]*Checkpoint, error) {
+	var cps []*Checkpoint
+	dec := json.NewDecoder(r)
+	for {
+		var f checkpointFile
+		if err := dec.Decode(&f); errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, &ParseError{Err: fmt.Errorf("checkpoint %d: %w", len(cps)+1, err)}
+		}
+		if len(f.State) == 0 {
+			return nil, &ParseError{Err: fmt.Errorf("checkpoint %d has no state", len(cps)+1)}
+		}
+		cps = append(cps, &Checkpoint{Algorithm: f.Algorithm, At: f.At, state: f.State})
+	}
+	if len(cps) == 0 {
+		return nil, &ParseError{Err: errors.New("no checkpoints")}
+	}
+
+	return cps, nil
+}
+
+// snapshot is the state of an engine as it is checkpointed, along with the
+// ready processes of its policy in the order it holds them.
+type snapshot struct {
+	Options Options `json:"options"`
+	Seeded  bool    `json:"seeded,omitempty"`
+	Now     int64   `json:"now"`
+
+	Processes    []Process    `json:"processes"`
+	Remaining    []int64      `json:"remaining"`
+	Cycle        []int        `json:"cycle"`
+	State        []State      `json:"state"`
+	Since        []int64      `json:"since"`
+	Residency    []Residency  `json:"residency"`
+	Section      []int        `json:"section"`
+	Holding      []bool       `json:"holding"`
+	BlockedSince []int64      `json:"blocked_since"`
+	LockWait     []int64      `json:"lock_wait"`
+	LastCore     []int        `json:"last_core"`
+	Admitted     []int64      `json:"admitted"`
+	Forked       []int        `json:"forked,omitempty"`
+	Rows         []*Row       `json:"rows"`
+	Transitions  []Transition `json:"transitions"`
+
+	Locks       map[string]mutexState `json:"locks,omitempty"`
+	Inversions  []Inversion           `json:"inversions,omitempty"`
+	Inverted    map[int]int           `json:"inverted,omitempty"`
+	Suspended   map[int]int           `json:"suspended,omitempty"`
+	Suspensions []TimeSlice           `json:"suspensions,omitempty"`
+	Memory      int64                 `json:"memory,omitempty"`
+	Admission   []int                 `json:"admission,omitempty"`
+
+	Ready    []int        `json:"ready"`
+	Events   []eventState `json:"events"`
+	EventSeq int          `json:"event_seq"`
+	Cores    []coreState  `json:"cores"`
+
+	TotalWait       float64 `json:"total_wait"`
+	TotalTurnaround float64 `json:"total_turnaround"`
+	Done            int     `json:"done"`
+}
+
+type mutexState struct {
+	Holder  int   `json:"holder"`
+	Waiters []int `json:"waiters,omitempty"`
+}
+
+type eventState struct {
+	At   int64     `json:"at"`
+	Kind eventKind `json:"kind"`
+	Proc int       `json:"proc"`
+	Seq  int       `json:"seq"`
+}
+
+type coreState struct {
+	Gantt   []TimeSlice `json:"gantt"`
+	Proc    int         `json:"proc"`
+	Busy    bool        `json:"busy,omitempty"`
+	Resumed bool        `json:"resumed,omitempty"`
+	Expires bool        `json:"expires,omitempty"`
+	Start   int64       `json:"start"`
+	Stop    int64       `json:"stop"`
+	Expiry  int64       `json:"expiry,omitempty"`
+	Limit   int64       `json:"limit"`
+	Carry   int64       `json:"carry,omitempty"`
+	Free    int64       `json:"free"`
+	From    int         `json:"from"`
+	Work    int64       `json:"work"`
+}
+
+// checkpoint saves the state of e, paused at now with the ready processes
+// of p, before anything is chosen to run at now.
+func (e *engine) checkpoint(p policy) (*Checkpoint, error) {
+	s := snapshot{
+		Options:         e.opts,
+		Seeded:          e.opts.seeded,
+		Now:             e.now,
+		Processes:       e.processes,
+		Remaining:       e.remaining,
+		Cycle:           e.cycle,
+		State:           e.state,
+		Since:           e.since,
+		Residency:       e.residency,
+		Section:         e.section,
+		Holding:         e.holding,
+		BlockedSince:    e.blockedSince,
+		LockWait:        e.lockWait,
+		LastCore:        e.lastCore,
+		Admitted:        e.admitted,
+		Forked:          e.forked,
+		Rows:            e.schedule,
+		Transitions:     e.transitions,
+		Locks:           make(map[string]mutexState, len(e.locks)),
+		Inversions:      e.inversions,
+		Inverted:        e.inverted,
+		Suspended:       e.suspended,
+		Suspensions:     e.suspensions,
+		Memory:          e.memory,
+		Admission:       e.admission,
+		Ready:           p.queued(),
+		EventSeq:        e.events.seq,
+		TotalWait:       e.totalWait,
+		TotalTurnaround: e.totalTurnaround,
+		Done:            e.done,
+	}
+	for name, m := range e.locks {
+		s.Locks[name] = mutexState{Holder: m.holder, Waiters: m.waiters}
+	}
+	for _, ev := range e.events.pending() {
+		s.Events = append(s.Events, eventState{At: ev.at, Kind: ev.kind, Proc: ev.proc, Seq: ev.seq})
+	}
+	for _, k := range e.cores {
+		s.Cores = append(s.Cores, coreState{
+			Gantt: k.gantt, Proc: k.proc, Busy: k.busy, Resumed: k.resumed, Expires: k.expires,
+			Start: k.start, Stop: k.stop, Expiry: k.expiry, Limit: k.limit, Carry: k.carry, Free: k.free, From: k.from, Work: k.work,
+		})
+	}
+	// Encoding copies the state, which the partial result then changes.
+	state, err := json.Marshal(s)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Checkpoint{At: e.now, state: state}, nil
+}
+
+// errCheckpoint reports a checkpoint whose state does not hang together.
+var errCheckpoint = errors.New("inconsistent checkpoint")
+
+// check verifies that the per-process and per-core state of s is complete
+// and that every process index in it is in range, so a damaged checkpoint
+// is rejected rather than resumed.
+func (s *snapshot) check() error {
+	n := len(s.Processes)
+	for _, l := range []int{len(s.Remaining), len(s.Cycle), len(s.State), len(s.Since), len(s.Residency), len(s.Section),
+		len(s.Holding), len(s.BlockedSince), len(s.LockWait), len(s.LastCore), len(s.Admitted), len(s.Rows)} {
+		if l != n {
+			return errCheckpoint
+		}
+	}
+	if (len(s.Forked) != 0 && len(s.Forked) != n) || len(s.Cores) != s.Options.CPUs {
+		return errCheckpoint
+	}
+	indices := append(append([]int(nil), s.Ready...), s.Admission...)
+	for _, ev := range s.Events {
+		indices = append(indices, ev.Proc)
+	}
+	for _, m := range s.Locks {
+		indices = append(indices, m.Waiters...)
+	}
+	for _, k := range s.Cores {
+		if k.Proc >= 0 {
+			indices = append(indices, k.Proc)
+		}
+	}
+	for i := range s.Inverted {
+		indices = append(indices, i)
+	}
+	for i := range s.Suspended {
+		indices = append(indices, i)
+	}
+	for _, i := range indices {
+		if i < 0 || i >= n {
+			return errCheckpoint
+		}
+	}
+	return nil
+}
+
+// resume continues the simulation saved in cp under the policy newPolicy
+// returns for its options, with the progress and updates callbacks, maximum
+// time, and next checkpoint of opts.
+func resume(ctx context.Context, cp *Checkpoint, opts Options, newPolicy func(o Options) policy) (*ScheduleResult, error) {
+	var s snapshot
+	if err := json.Unmarshal(cp.state, &s); err != nil {
+		return nil, &ParseError{Err: fmt.Errorf("checkpoint: %w", err)}
+	}
+	if err := s.check(); err != nil {
+		return nil, &ValidationError{Err: err}
+	}
+	o := s.Options
+	o.seeded = s.Seeded
+	o.Progress, o.Updates, o.MaxTime, o.CheckpointAt = opts.Progress, opts.Updates, opts.MaxTime, opts.CheckpointAt
+	if err := o.validate(); err != nil {
+		return nil, err
+	}
+	if o.CheckpointAt > 0 && o.CheckpointAt <= s.Now {
+		return nil, fmt.Errorf("%w: checkpoint at %d is not after the resumed time %d", ErrValidation, o.CheckpointAt, s.Now)
+	}
+
+	e := newEngine(o)
+	e.now = s.Now
+	e.processes, e.remaining, e.cycle, e.state, e.since = s.Processes, s.Remaining, s.Cycle, s.State, s.Since
+	e.index = indexByID(s.Processes)
+	e.residency, e.section, e.holding, e.blockedSince, e.lockWait = s.Residency, s.Section, s.Holding, s.BlockedSince, s.LockWait
+	e.lastCore, e.admitted, e.schedule, e.transitions = s.LastCore, s.Admitted, s.Rows, s.Transitions
+	e.inversions, e.suspensions, e.memory, e.admission = s.Inversions, s.Suspensions, s.Memory, s.Admission
+	for name, m := range s.Locks {
+		e.locks[name] = &mutex{holder: m.Holder, waiters: m.Waiters}
+	}
+	for i, k := range s.Inverted {
+		e.inverted[i] = k
+	}
+	for i, k := range s.Suspended {
+		e.suspended[i] = k
+	}
+	if len(s.Forked) > 0 {
+		e.trackForks()
+		e.forked = s.Forked
+	}
+	for _, ev := range s.Events {
+		e.events.events = append(e.events.events, event{at: ev.At, kind: ev.Kind, proc: ev.Proc, seq: ev.Seq})
+	}
+	heap.Init(&e.events)
+	e.events.seq = s.EventSeq
+	for c, k := range s.Cores {
+		e.cores[c].gantt = append(e.cores[c].gantt, k.Gantt...)
+		e.cores[c].proc, e.cores[c].busy, e.cores[c].resumed, e.cores[c].expires = k.Proc, k.Busy, k.Resumed, k.Expires
+		e.cores[c].start, e.cores[c].stop, e.cores[c].expiry = k.Start, k.Stop, k.Expiry
+		e.cores[c].limit, e.cores[c].carry, e.cores[c].free, e.cores[c].from, e.cores[c].work = k.Limit, k.Carry, k.Free, k.From, k.Work
+	}
+	e.totalWait, e.totalTurnaround, e.done = s.TotalWait, s.TotalTurnaround, s.Done
+	for _, row := range e.schedule {
+		if row != nil && row.Killed {
+			e.killed++
+		}
+	}
+	p := newPolicy(o)
+	for _, i := range s.Ready {
+		p.add(i)
+	}
+
+	return e.run(ctx, p)
+}
+
+func (s *fcfs) Resume(ctx context.Context, cp *Checkpoint) (*ScheduleResult, error) {
+	return resume(ctx, cp, s.opts, func(Options) policy { return &fifo{} })
+}
+
+func (s *sjf) Resume(ctx context.Context, cp *Checkpoint) (*ScheduleResult, error) {
+	return resume(ctx, cp, s.opts, func(o Options) policy { return &ranked{before: (&sjf{opts: o}).before} })
+}
+
+func (s *priority) Resume(ctx context.Context, cp *Checkpoint) (*ScheduleResult, error) {
+	return resume(ctx, cp, s.opts, func(o Options) policy { return &ranked{before: (&priority{opts: o}).before} })
+}
+
+func (s *rr) Resume(ctx context.Context, cp *Checkpoint) (*ScheduleResult, error) {
+	return resume(ctx, cp, s.opts, func(o Options) policy { return &fifo{slice: o.Quantum} })
+}
+
+func (s *edf) Resume(ctx context.Context, cp *Checkpoint) (*ScheduleResult, error) {
+	return resume(ctx, cp, s.opts, func(o Options) policy { return &mixed{before: (&edf{opts: o}).before, slice: o.Quantum} })
+}
+
+func (s *rm) Resume(ctx context.Context, cp *Checkpoint) (*ScheduleResult, error) {
+	return resume(ctx, cp, s.opts, func(o Options) policy { return &mixed{before: (&rm{opts: o}).before, slice: o.Quantum} })
+}
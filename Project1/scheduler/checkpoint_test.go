@@ -0,0 +1,186 @@
+package scheduler
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCheckpointResume(t *testing.T) {
+	t.Parallel()
+	// Locks, I/O, kills, suspensions, memory, and forks all carry state
+	// across the checkpoint.
+	processes := []Process{
+		{ProcessID: 1, BurstDuration: 5, Priority: 3, Cycles: []Cycle{{IO: 2, CPU: 2}}, Locks: []Lock{{Name: "R", Start: 1, Duration: 2}}, Memory: 40},
+		{ProcessID: 2, BurstDuration: 3, ArrivalTime: 1, Priority: 1, Locks: []Lock{{Name: "R", Start: 0, Duration: 1}}, Memory: 40},
+		{ProcessID: 3, BurstDuration: 4, ArrivalTime: 2, Priority: 2, Suspensions: []Suspension{{From: 4, To: 7}}, Memory: 40},
+		{ProcessID: 4, BurstDuration: 6, ArrivalTime: 3, KillAt: 12},
+		{ProcessID: 5, BurstDuration: 2, Parent: 4, ForkAt: 1},
+		{ProcessID: 6, BurstDuration: 2, ArrivalTime: 5, Deadline: 4},
+	}
+	schedulers := map[string]func(opts ...Option) Scheduler{
+		"fcfs": NewFCFS, "sjf": NewSJF, "priority": NewPriority, "rr": NewRR, "edf": NewEDF, "rm": NewRM,
+	}
+	for name, newScheduler := range schedulers {
+		name, newScheduler := name, newScheduler
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			opts := []Option{WithCPUs(2), WithMemory(100), WithDispatchLatency(1), WithSeed(5)}
+			want, err := newScheduler(opts...).Schedule(context.Background(), processes)
+			if err != nil {
+				t.Fatalf("Schedule() error = %v", err)
+			}
+			want.Ideal = nil
+			for _, at := range []int64{1, 3, 4, 6, 9} {
+				paused, err := newScheduler(append(opts, WithCheckpoint(at))...).Schedule(context.Background(), processes)
+				if err != nil {
+					t.Fatalf("Schedule() error = %v", err)
+				}
+				if paused.Checkpoint == nil || !paused.Truncated || paused.Checkpoint.At != at {
+					t.Fatalf("paused at %d: Checkpoint = %+v, Truncated = %v", at, paused.Checkpoint, paused.Truncated)
+				}
+				var file bytes.Buffer
+				if err := paused.Checkpoint.Save(&file); err != nil {
+					t.Fatalf("Save() error = %v", err)
+				}
+				cps, err := LoadCheckpoints(&file)
+				if err != nil {
+					t.Fatalf("LoadCheckpoints() error = %v", err)
+				}
+				got, err := newScheduler().(Resumer).Resume(context.Background(), cps[0])
+				if err != nil {
+					t.Fatalf("Resume() error = %v", err)
+				}
+				if !reflect.DeepEqual(got, want) {
+					t.Errorf("resumed from %d:\n got %+v\nwant %+v", at, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestCheckpointResume_random(t *testing.T) {
+	t.Parallel()
+	names := []string{"fcfs", "sjf", "priority", "rr", "edf", "rm"}
+	for seed := int64(0); seed < 3000; seed++ {
+		rng := rand.New(rand.NewSource(seed))
+		processes := Generate(rng, 2+rng.Intn(8))
+		cpus := 1 + rng.Intn(3)
+		opts := []Option{WithCPUs(cpus), WithSwitchCost(int64(rng.Intn(3))), WithDispatchLatency(int64(rng.Intn(3))), WithQuantum(int64(1 + rng.Intn(4)))}
+		if cpus > 1 {
+			opts = append(opts, WithMigrationCost(int64(rng.Intn(3))))
+			speeds := make([]float64, cpus)
+			for c := range speeds {
+				speeds[c] = []float64{0.5, 1, 1.5}[rng.Intn(3)]
+			}
+			opts = append(opts, WithSpeeds(speeds...))
+			for i := range processes {
+				if rng.Intn(3) == 0 {
+					processes[i].Affinity = []int{rng.Intn(cpus)}
+				}
+			}
+		}
+		for i := range processes {
+			switch rng.Intn(4) {
+			case 0:
+				processes[i].KillAt = processes[i].ArrivalTime + 1 + rng.Int63n(10)
+			case 1:
+				from := processes[i].ArrivalTime + rng.Int63n(5)
+				processes[i].Suspensions = []Suspension{{From: from, To: from + 1 + rng.Int63n(5)}}
+			case 2:
+				if processes[i].BurstDuration > 1 {
+					processes[i].Locks = []Lock{{Name: "m", Start: rng.Int63n(processes[i].BurstDuration - 1), Duration: 1}}
+				}
+			}
+		}
+		newScheduler, _ := Lookup(names[rng.Intn(len(names))])
+		want, err := newScheduler(opts...).Schedule(context.Background(), processes)
+		if err != nil {
+			t.Fatalf("seed %d: Schedule() error = %v", seed, err)
+		}
+		want.Ideal = nil
+		at := 1 + rng.Int63n(int64(want.Metrics.Makespan))
+		paused, err := newScheduler(append(opts, WithCheckpoint(at))...).Schedule(context.Background(), processes)
+		if err != nil {
+			t.Fatalf("seed %d: Schedule() error = %v", seed, err)
+		}
+		if paused.Checkpoint == nil {
+			continue
+		}
+		got, err := newScheduler().(Resumer).Resume(context.Background(), paused.Checkpoint)
+		if err != nil {
+			t.Fatalf("seed %d: Resume() error = %v", seed, err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("seed %d: resumed from %d:\n got %+v\nwant %+v", seed, at, got, want)
+		}
+	}
+}
+
+func TestCheckpoint_pauseAgain(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: 1, BurstDuration: 4},
+		{ProcessID: 2, BurstDuration: 3, ArrivalTime: 1},
+	}
+	want, err := NewRR().Schedule(context.Background(), processes)
+	if err != nil {
+		t.Fatalf("Schedule() error = %v", err)
+	}
+	r, err := NewRR(WithCheckpoint(2)).Schedule(context.Background(), processes)
+	if err != nil {
+		t.Fatalf("Schedule() error = %v", err)
+	}
+	if _, err := NewRR(WithCheckpoint(2)).(Resumer).Resume(context.Background(), r.Checkpoint); !errors.Is(err, ErrValidation) {
+		t.Errorf("Resume() at the checkpoint time error = %v, want %v", err, ErrValidation)
+	}
+	if r, err = NewRR(WithCheckpoint(5)).(Resumer).Resume(context.Background(), r.Checkpoint); err != nil || r.Checkpoint == nil {
+		t.Fatalf("Resume() = %+v, %v, want another checkpoint", r, err)
+	}
+	if r, err = NewRR().(Resumer).Resume(context.Background(), r.Checkpoint); err != nil {
+		t.Fatalf("Resume() error = %v", err)
+	}
+	if !reflect.DeepEqual(r, want) {
+		t.Errorf("Resume() = %+v, want %+v", r, want)
+	}
+}
+
+func TestLoadCheckpoints_invalid(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		file string
+		want error
+	}{
+		{name: "empty", file: "", want: ErrParse},
+		{name: "not JSON", file: "{", want: ErrParse},
+		{name: "no state", file: `{"at":3}`, want: ErrParse},
+		{name: "inconsistent", file: `{"at":3,"state":{"options":{"CPUs":1,"Quantum":2,"TicksPerUnit":1},"processes":[{}]}}`, want: ErrValidation},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			cps, err := LoadCheckpoints(strings.NewReader(tt.file))
+			if err == nil {
+				_, err = NewFCFS().(Resumer).Resume(context.Background(), cps[0])
+			}
+			if !errors.Is(err, tt.want) {
+				t.Errorf("error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckpoint_live(t *testing.T) {
+	t.Parallel()
+	arrivals := make(chan Process)
+	close(arrivals)
+	if _, err := NewFCFS(WithCheckpoint(1)).(LiveScheduler).ScheduleLive(context.Background(), arrivals); !errors.Is(err, ErrValidation) {
+		t.Errorf("ScheduleLive() error = %v, want %v", err, ErrValidation)
+	}
+}
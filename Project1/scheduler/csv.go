@@ -0,0 +1,50 @@
+package scheduler
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+)
+
+// CSVHeader is the header line of the rows RenderCSV writes.
+var CSVHeader = []string{
+	"schedule", "seed", "pid", "priority", "arrival", "burst", "io", "wait", "turnaround", "exit",
+	"ready", "running", "blocked", "killed", "excluded", "missed",
+}
+
+// RenderCSV writes r to w as CSV rows in tidy long format, one per process,
+// with columns as CSVHeader names them and times in time units, so data
+// frames in pandas, R, or Gonum can load the rows of many schedules
+// written one after another and group them by schedule with no reshaping.
+// The seed is empty for a run that drew no random numbers.
+func RenderCSV(w io.Writer, title string, r *ScheduleResult) error {
+	u := r.ticksPerUnit()
+	var seed string
+	if r.Seed != nil {
+		seed = strconv.FormatInt(*r.Seed, 10)
+	}
+	cw := csv.NewWriter(w)
+	for _, row := range r.Rows {
+		_ = cw.Write([]string{
+			title,
+			seed,
+			strconv.FormatInt(row.ProcessID, 10),
+			strconv.FormatInt(row.Priority, 10),
+			unitTime(row.ArrivalTime, u),
+			unitTime(row.BurstDuration, u),
+			unitTime(row.IO, u),
+			unitTime(row.Wait, u),
+			unitTime(row.Turnaround, u),
+			unitTime(row.Exit, u),
+			unitTime(row.Residency.Ready, u),
+			unitTime(row.Residency.Running, u),
+			unitTime(row.Residency.Blocked, u),
+			strconv.FormatBool(row.Killed),
+			strconv.FormatBool(row.Excluded),
+			strconv.FormatBool(row.Missed),
+		})
+	}
+	cw.Flush()
+
+	return cw.Error()
+}
@@ -0,0 +1,34 @@
+package scheduler
+
+import (
+	"encoding/csv"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRenderCSV(t *testing.T) {
+	t.Parallel()
+	r := testResult()
+	r.TicksPerUnit = 2
+	var w strings.Builder
+	if err := RenderCSV(&w, "First-come, first-served", r); err != nil {
+		t.Fatalf("RenderCSV() error = %v", err)
+	}
+	got, err := csv.NewReader(strings.NewReader(w.String())).ReadAll()
+	if err != nil {
+		t.Fatalf("RenderCSV() wrote invalid CSV: %v\n%s", err, w.String())
+	}
+	want := [][]string{
+		{"First-come, first-served", "7", "1", "2", "0", "2.5", "0", "0", "2.5", "2.5", "0", "0", "0", "false", "false", "false"},
+		{"First-come, first-served", "7", "2", "1", "1.5", "4.5", "0", "1", "5.5", "7", "0", "0", "0", "false", "false", "false"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("RenderCSV() = %q, want %q", got, want)
+	}
+	for _, row := range got {
+		if len(row) != len(CSVHeader) {
+			t.Errorf("RenderCSV() row %q has %d columns, want %d", row, len(row), len(CSVHeader))
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package scheduler
+
+import (
+	"fmt"
+	"math"
+)
+
+// Power is an energy model of the CPUs, in units of energy per unit of time.
+// A CPU running at speed s, whether a process or overhead, draws Static plus
+// Active*s³, since its voltage must rise with its frequency; an idle CPU
+// draws Idle. Running fast and then idling (race to idle) saves energy over
+// running slowly when Static is high and Idle low, and costs energy when
+// Active dominates.
+type Power struct {
+	// Active is the dynamic power of a CPU running at speed 1.
+	Active float64 `json:"active"`
+	// Static is the leakage power of a running CPU at any speed.
+	Static float64 `json:"static"`
+	// Idle is the power of an idle CPU.
+	Idle float64 `json:"idle"`
+}
+
+// validate checks that no power is negative.
+func (p Power) validate() error {
+	if p.Active < 0 || p.Static < 0 || p.Idle < 0 {
+		return fmt.Errorf("%w: active %v, static %v, and idle %v power must not be negative", ErrValidation, p.Active, p.Static, p.Idle)
+	}
+	return nil
+}
+
+// energy is the energy the CPUs use up to makespan, given the time each was
+// busy running or on overhead, under o's power model, and how much of it
+// they used idle.
+func (o Options) energy(busy []int64, makespan float64) (total, idle float64) {
+	for c := range busy {
+		s := float64(o.speed(c)) / speedScale
+		total += float64(busy[c]) * (o.Power.Static + o.Power.Active*math.Pow(s, 3))
+		if free := makespan - float64(busy[c]); free > 0 {
+			idle += free * o.Power.Idle
+		}
+	}
+
+	return total + idle, idle
+}
@@ -0,0 +1,63 @@
+package scheduler
+
+import (
+	"context"
+	"math"
+	"testing"
+)
+
+func TestSchedule_energy(t *testing.T) {
+	t.Parallel()
+	// Two processes of 4 on one CPU, the second arriving at 6, leave the
+	// CPU idle from 4 to 6 at speed 1; at speed 2 each runs for 2 and the
+	// CPU idles from 2 to 6.
+	processes := []Process{
+		{ProcessID: 1, BurstDuration: 4},
+		{ProcessID: 2, BurstDuration: 4, ArrivalTime: 6},
+	}
+	tests := []struct {
+		name     string
+		opts     []Option
+		wantIdle float64
+		want     float64
+	}{
+		{
+			name:     "no model",
+			opts:     nil,
+			wantIdle: 0,
+			want:     0,
+		},
+		{
+			name:     "speed 1",
+			opts:     []Option{WithPower(Power{Active: 2, Static: 1, Idle: 0.5})},
+			wantIdle: 2 * 0.5,
+			want:     8*(1+2) + 2*0.5,
+		},
+		{
+			name:     "race to idle",
+			opts:     []Option{WithSpeeds(2), WithPower(Power{Active: 2, Static: 1, Idle: 0.5})},
+			wantIdle: 4 * 0.5,
+			want:     4*(1+2*8) + 4*0.5,
+		},
+		{
+			// The switch to the second process, from 6 to 7, is busy.
+			name:     "switch cost draws power",
+			opts:     []Option{WithSwitchCost(1), WithPower(Power{Static: 1})},
+			wantIdle: 0,
+			want:     9,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			r, err := NewFCFS(tt.opts...).Schedule(context.Background(), processes)
+			if err != nil {
+				t.Fatalf("Schedule() error = %v", err)
+			}
+			if math.Abs(r.Metrics.Energy-tt.want) > 1e-9 || math.Abs(r.Metrics.IdleEnergy-tt.wantIdle) > 1e-9 {
+				t.Errorf("Energy = %v, idle %v, want %v, idle %v", r.Metrics.Energy, r.Metrics.IdleEnergy, tt.want, tt.wantIdle)
+			}
+		})
+	}
+}
@@ -0,0 +1,881 @@
+package scheduler
+
+import (
+	"context"
+	"math"
+	"sort"
+)
+
+// policy is the part of a scheduler that decides which ready process runs
+// next and for how long. The engine owns the clock, arrivals, overheads, and
+// bookkeeping, and consults the policy at every scheduling decision.
+type policy interface {
+	// add makes process i ready, on arrival or after it was preempted.
+	add(i int)
+	// drop takes process i off the ready processes, if it is on them.
+	drop(i int)
+	// rerank tells the policy that ready process i may rank differently,
+	// since the process stopped running or its inherited priority changed.
+	rerank(i int)
+	// len is the number of ready processes.
+	len() int
+	// pick removes and returns the ready process to run next among those
+	// allowed, or returns -1 if none is.
+	pick(e *engine, allowed func(i int) bool) int
+	// quantum is how long process p runs, once picked, before it is
+	// preempted and made ready again; 0 lets it run until it completes.
+	quantum(p Process) int64
+	// preemptive reports whether an event such as an arrival interrupts the
+	// running process so the policy can choose again.
+	preemptive() bool
+	// queued returns the ready processes in the order the policy holds
+	// them, so adding them in that order restores it.
+	queued() []int
+}
+
+// engine is the state of one simulation, shared by every scheduler.
+type engine struct {
+	processes []Process
+	opts      Options
+	// remaining is the work each process still has to do for its current
+	// burst, in units of 1/speedScale of a tick at speed 1.
+	remaining []int64
+	// cycle is the index of the next I/O cycle of each process.
+	cycle []int
+	// state is each process's current state, entered at since.
+	state       []State
+	since       []int64
+	residency   []Residency
+	transitions []Transition
+	// locks are the named locks, section is the index of each process's
+	// next or current critical section, and holding is set while it is in
+	// it. blockedSince is when a process last blocked on a lock, and
+	// lockWait the total time it has spent blocked on locks.
+	locks        map[string]*mutex
+	section      []int
+	holding      []bool
+	blockedSince []int64
+	lockWait     []int64
+	// inversions are the priority inversion intervals, and inverted the
+	// index of each blocked process's open interval.
+	inversions []Inversion
+	inverted   map[int]int
+	// index is the index of each process by its process ID, so processes
+	// are found by ID however their IDs are numbered.
+	index map[int64]int
+	// arrivals, when not nil, delivers processes that arrive while the
+	// simulation runs; see ScheduleLive.
+	arrivals <-chan Process
+	// suspended holds the index in suspensions of the open suspension of
+	// each suspended process.
+	suspended   map[int]int
+	suspensions []TimeSlice
+	// lastCore is the CPU each process last started a run on, or -1.
+	lastCore []int
+	// memory is the memory admitted processes hold, admitted when each
+	// process was admitted (-1 until it is), and admission the processes
+	// waiting for memory, in the order they arrived.
+	memory    int64
+	admitted  []int64
+	admission []int
+	// children are the processes each process spawns, in the order they
+	// fork, and forked how many it has spawned; nil without any.
+	children [][]int
+	forked   []int
+	// events are the pending events, such as arrivals still to come.
+	events eventQueue
+	cores  []core
+	now    int64
+	// slices streams the Gantt chart to Options.Slices, if set.
+	slices *sliceStream
+
+	schedule        []*Row
+	totalWait       float64
+	totalTurnaround float64
+	done            int
+	killed          int
+}
+
+// core is one CPU and the run it is in the middle of, if any.
+type core struct {
+	cpu   int
+	gantt []TimeSlice
+	// proc is the process on the core, or -1. A process interrupted by an
+	// event stays on it until the policy has chosen again, so it can resume
+	// without being dispatched.
+	proc int
+	// busy is set while proc runs from start to stop, stop being a quantum
+	// expiry when expires is set.
+	busy        bool
+	resumed     bool
+	expires     bool
+	start, stop int64
+	// expiry is when the quantum of proc ends, 0 for none; a process
+	// resuming on the core carries on with the same quantum.
+	expiry int64
+	// limit is the most work the run may do, which is less than the
+	// process's remaining work when it stops at a lock.
+	limit int64
+	// carry is the work the last tick of a run had to spare once it reached
+	// its limit, which proc does first if it resumes on the core, so a run
+	// split at locks takes no longer than a whole one.
+	carry int64
+	// free is when the core last stopped running, for idle accounting.
+	free int64
+	// from is the CPU proc last started a run on before this one, which it
+	// gets back if it is taken off before it starts.
+	from int
+	// speed is the work the core does per tick, speedScale at speed 1, and
+	// work is the total it has done.
+	speed, work int64
+}
+
+// speedScale is the work a core of speed 1 does per tick, so core speeds
+// that are a fraction of another's still do whole units of work per tick.
+const speedScale = 100
+
+// simulate runs processes under p as a discrete-event simulation on
+// opts.CPUs identical CPUs: the clock jumps straight from event to event, and
+// a process runs until it completes, its quantum expires, or (for preemptive
+// policies) the next event is due. Processes that arrive while others run
+// become ready before the processes they preempted, so they queue ahead.
+//
+// When opts charges switch cost, dispatch latency, or migration cost, a
+// finished simulation is run again without them for the ideal metrics,
+// unless opts asks for the metrics only. One stopped at the maximum time is
+// not, since p still holds the processes it left ready.
+func simulate(ctx context.Context, processes []Process, opts Options, p policy) (*ScheduleResult, error) {
+	r, err := simulateOnce(ctx, processes, opts, p)
+	if err != nil || r.Truncated || opts.MetricsOnly || (opts.SwitchCost == 0 && opts.DispatchLatency == 0 && opts.MigrationCost == 0) {
+		return r, err
+	}
+	// p is empty again now that every process has finished.
+	ideal := opts
+	ideal.SwitchCost, ideal.DispatchLatency, ideal.MigrationCost, ideal.Progress, ideal.Slices, ideal.Transitions, ideal.Updates = 0, 0, 0, nil, nil, nil, nil
+	if ir, err := simulateOnce(ctx, processes, ideal, p); err == nil {
+		r.Ideal = &ir.Metrics
+		ir.Release()
+	}
+
+	return r, nil
+}
+
+// simulateOnce runs processes under p with exactly the given options.
+func simulateOnce(ctx context.Context, processes []Process, opts Options, p policy) (*ScheduleResult, error) {
+	e := newEngine(opts)
+	for _, proc := range processes {
+		e.track(proc)
+	}
+	if !e.trackForks() {
+		e.children, e.forked = nil, nil
+	}
+	for _, i := range arrivalOrder(processes, opts.less) {
+		if processes[i].Parent != 0 {
+			// Spawned by its parent instead.
+			continue
+		}
+		e.since[i] = processes[i].ArrivalTime
+		e.events.scheduleSorted(processes[i].ArrivalTime, eventArrival, i)
+	}
+	for i, p := range processes {
+		if p.KillAt > 0 {
+			e.events.schedule(p.KillAt, eventKill, i)
+		}
+		for _, s := range p.Suspensions {
+			e.events.schedule(s.From, eventSuspend, i)
+			e.events.schedule(s.To, eventResume, i)
+		}
+	}
+
+	return e.run(ctx, p)
+}
+
+// newEngine returns an engine for a simulation with no processes yet.
+func newEngine(opts Options) *engine {
+	e := &engine{
+		opts:      opts,
+		cores:     make([]core, opts.CPUs),
+		locks:     make(map[string]*mutex),
+		index:     make(map[int64]int),
+		inverted:  make(map[int]int),
+		suspended: make(map[int]int),
+		slices:    newSliceStream(opts),
+	}
+	for c := range e.cores {
+		e.cores[c] = core{cpu: c, gantt: newGantt(), proc: -1, speed: opts.speed(c)}
+	}
+
+	return e
+}
+
+// track adds process p to the simulation, before it arrives, and returns
+// its index.
+func (e *engine) track(p Process) int {
+	e.index[p.ProcessID] = len(e.processes)
+	e.processes = append(e.processes, p)
+	e.remaining = append(e.remaining, p.BurstDuration*speedScale)
+	e.cycle = append(e.cycle, 0)
+	e.state = append(e.state, StateNew)
+	e.since = append(e.since, p.ArrivalTime)
+	e.residency = append(e.residency, Residency{})
+	e.schedule = append(e.schedule, nil)
+	e.section = append(e.section, 0)
+	e.holding = append(e.holding, false)
+	e.blockedSince = append(e.blockedSince, 0)
+	e.lockWait = append(e.lockWait, 0)
+	e.admitted = append(e.admitted, -1)
+	e.lastCore = append(e.lastCore, -1)
+
+	return len(e.processes) - 1
+}
+
+// run simulates until every process has finished, or until ctx is
+// cancelled, a live arrival is invalid, or the clock reaches the maximum
+// time or the checkpoint time, which stops the simulation with the partial
+// result.
+func (e *engine) run(ctx context.Context, p policy) (*ScheduleResult, error) {
+	var err error
+	var truncated bool
+	var cp *Checkpoint
+	for ctx.Err() == nil {
+		if err = e.receive(ctx); err != nil {
+			break
+		}
+		if e.done == len(e.processes) {
+			if e.arrivals == nil {
+				break
+			}
+			continue
+		}
+		// A preemptive policy chooses again whenever an event is handled or
+		// a run ends, even for a process still being dispatched.
+		changed := e.admit(p)
+		for c := range e.cores {
+			if k := &e.cores[c]; k.busy && k.stop <= e.now {
+				changed = true
+			}
+		}
+		for c := range e.cores {
+			if k := &e.cores[c]; k.busy && (k.stop <= e.now || (p.preemptive() && changed)) {
+				e.end(k, p)
+			}
+		}
+		if e.done == len(e.processes) {
+			continue
+		}
+		if e.opts.MaxTime > 0 && e.now >= e.opts.MaxTime {
+			truncated = true
+			break
+		}
+		if e.opts.CheckpointAt > 0 && e.now >= e.opts.CheckpointAt {
+			if cp, err = e.checkpoint(p); err == nil {
+				truncated = true
+			}
+			break
+		}
+		e.fill(p)
+		e.stream(false)
+
+		// Jump to the next event or the end of the next run.
+		next, ok := e.events.peek()
+		at := next.at
+		for c := range e.cores {
+			if k := &e.cores[c]; k.busy && (!ok || k.stop < at) {
+				at, ok = k.stop, true
+			}
+		}
+		if !ok {
+			break
+		}
+		if e.opts.MaxTime > 0 && at > e.opts.MaxTime {
+			at = e.opts.MaxTime
+		}
+		if e.opts.CheckpointAt > e.now && at > e.opts.CheckpointAt {
+			at = e.opts.CheckpointAt
+		}
+		e.now = at
+	}
+	if err == nil {
+		err = ctx.Err()
+	}
+	if err != nil || truncated {
+		// Record what ran before the simulation stopped.
+		for c := range e.cores {
+			if k := &e.cores[c]; k.busy && k.start < e.now {
+				k.stop, k.expires = e.now, false
+				e.end(k, p)
+			}
+		}
+	}
+
+	var gantt []TimeSlice
+	var t *tally
+	if e.slices != nil {
+		e.stream(true)
+		gantt, t = make([]TimeSlice, 0), e.slices.tally
+		for c := range e.cores {
+			putGantt(e.cores[c].gantt)
+		}
+	} else {
+		gantt = e.gantt()
+	}
+	r, err := e.opts.result(gantt, t, e.schedule, e.totalWait, e.totalTurnaround, float64(e.now), err)
+	for c := range r.Metrics.Cores {
+		r.Metrics.Cores[c].Speed = float64(e.cores[c].speed) / speedScale
+		r.Metrics.Cores[c].Work = float64(e.cores[c].work) / speedScale
+	}
+	// Events handled after they were due are recorded at the time they
+	// happened, so they may be out of order.
+	sort.SliceStable(e.transitions, func(a, b int) bool { return e.transitions[a].At < e.transitions[b].At })
+	r.Transitions = e.transitions
+	for i := range e.inverted {
+		e.revert(i)
+	}
+	r.Inversions = e.inversions
+	for i := range e.suspended {
+		e.unsuspend(i)
+	}
+	r.Suspensions = e.suspensions
+	r.Tasks = tasks(e.processes, r.Rows)
+	if truncated || (err == nil && e.done < len(e.processes)) {
+		// Stopped at the maximum time or a checkpoint, or with children
+		// never spawned.
+		r.Truncated, r.Unfinished = truncated, e.unfinished()
+	}
+	r.Checkpoint = cp
+	switch {
+	case e.opts.MetricsOnly:
+	case e.slices != nil:
+		r.Violations = e.slices.verify.check(r.Rows)
+	default:
+		r.Violations = verify(r, e.opts)
+	}
+
+	return r, err
+}
+
+// unfinished lists the processes that have not finished by now.
+func (e *engine) unfinished() []Unfinished {
+	var left []Unfinished
+	for i, p := range e.processes {
+		if e.schedule[i] != nil {
+			continue
+		}
+		remaining := (e.remaining[i] + speedScale - 1) / speedScale
+		for _, c := range p.Cycles[e.cycle[i]:] {
+			remaining += c.CPU
+		}
+		left = append(left, Unfinished{
+			ProcessID:     p.ProcessID,
+			Priority:      p.Priority,
+			BurstDuration: p.CPUTime(),
+			ArrivalTime:   p.ArrivalTime,
+			Remaining:     remaining,
+			State:         e.state[i],
+		})
+	}
+
+	return left
+}
+
+// admit handles every event due by now, making arriving processes and
+// processes whose I/O completed ready, and killing processes due to die. It
+// reports whether there were any.
+func (e *engine) admit(p policy) bool {
+	handled := false
+	for ev, ok := e.events.peek(); ok && ev.at <= e.now; ev, ok = e.events.peek() {
+		e.events.next()
+		handled = true
+		switch ev.kind {
+		case eventArrival:
+			e.arrive(ev.proc, ev.at, p)
+		case eventFork:
+			e.fork(ev.proc, p)
+		case eventIODone:
+			if e.state[ev.proc] == StateTerminated {
+				// Killed during its I/O.
+				continue
+			}
+			e.ready(ev.proc, ev.at, p)
+		case eventKill:
+			e.kill(ev.proc, p)
+		case eventSuspend:
+			e.suspend(ev.proc, p)
+		case eventResume:
+			e.resume(ev.proc, p)
+		}
+	}
+	return handled
+}
+
+// arrive makes process i, arriving at at, ready, or has it wait for memory
+// if it does not fit.
+func (e *engine) arrive(i int, at int64, p policy) {
+	e.record(i, StateNew, at, 0)
+	if !e.fits(i) {
+		e.admission = append(e.admission, i)
+		return
+	}
+	e.allocate(i, at, p)
+}
+
+// end stops the run on k at now, or at its stop if earlier. The process then
+// blocks on a lock, completes, blocks for I/O, or becomes ready again: behind the arrivals
+// already admitted if its quantum expired, or still on the core if it was
+// interrupted, until the policy chooses again. A process interrupted before
+// it starts, during its switch, dispatch, or migration, has not run at all.
+func (e *engine) end(k *core, p policy) {
+	i := k.proc
+	if e.now < k.start {
+		k.busy = false
+		p.add(i)
+		return
+	}
+	stop := k.stop
+	if e.now < stop {
+		stop = e.now
+	}
+	if stop > k.start {
+		pid := e.processes[i].ProcessID
+		last := len(k.gantt) - 1
+		if k.resumed && last >= 0 && k.gantt[last].Kind == SliceRun && k.gantt[last].PID == pid && k.gantt[last].Stop == k.start {
+			k.gantt[last].Stop = stop
+		} else {
+			k.gantt = append(k.gantt, TimeSlice{PID: pid, Start: k.start, Stop: stop})
+		}
+	}
+	work := k.carry + (stop-k.start)*k.speed
+	k.carry = 0
+	if work > k.limit {
+		k.carry, work = work-k.limit, k.limit
+	}
+	e.remaining[i] -= work
+	k.work += work
+	k.busy, k.free = false, stop
+	if e.lockPoint(i, p) {
+		k.proc = -1
+		return
+	}
+
+	switch {
+	case e.remaining[i] == 0 && e.cycle[i] < len(e.processes[i].Cycles):
+		k.proc = -1
+		e.block(i)
+	case e.remaining[i] == 0:
+		k.proc = -1
+		e.complete(i, p)
+	case stop == k.stop && k.expires:
+		k.proc = -1
+		e.enter(i, StateReady)
+		p.add(i)
+	default:
+		p.add(i)
+	}
+}
+
+// fill gives every free core a process, if any are ready. The policy
+// chooses the processes, each of which must be allowed on one of the free
+// cores left and runs on the one place picks for it. Interrupted processes
+// it chooses again resume on their own core, and the rest go to idle cores
+// before displacing an interrupted process, so processes only migrate when
+// they must.
+func (e *engine) fill(p policy) {
+	var free []int
+	for c := range e.cores {
+		if !e.cores[c].busy {
+			free = append(free, c)
+		}
+	}
+	assigned := make(map[int]int, len(free))
+	avail := append([]int(nil), free...)
+	for len(avail) > 0 {
+		i := p.pick(e, func(i int) bool {
+			for _, c := range avail {
+				if e.processes[i].allows(c) {
+					return true
+				}
+			}
+			return false
+		})
+		if i < 0 {
+			break
+		}
+		c := e.place(i, avail)
+		assigned[c] = i
+		for a := range avail {
+			if avail[a] == c {
+				avail = append(avail[:a], avail[a+1:]...)
+				break
+			}
+		}
+	}
+
+	// A process chosen before an interrupted one may have taken its core.
+	// Swap them when the first may run on the core the interrupted one got,
+	// which puts the interrupted one back for good.
+	for swapped := true; swapped; {
+		swapped = false
+		for _, c := range free {
+			i, ok := assigned[c]
+			if !ok || e.cores[c].proc == i {
+				continue
+			}
+			for _, d := range free {
+				if j, ok := assigned[d]; ok && e.cores[d].proc == i && e.processes[j].allows(c) {
+					assigned[c], assigned[d] = j, i
+					swapped = true
+					break
+				}
+			}
+		}
+	}
+
+	for _, c := range free {
+		k := &e.cores[c]
+		if i, ok := assigned[c]; k.proc >= 0 && (!ok || i != k.proc) {
+			// The interrupted process was not chosen again for this core.
+			if k.start > e.now {
+				e.undispatch(k)
+			} else {
+				e.enter(k.proc, StateReady)
+			}
+			p.rerank(k.proc)
+			k.proc = -1
+		}
+	}
+	for _, c := range free {
+		if i, ok := assigned[c]; ok {
+			k := &e.cores[c]
+			e.start(k, i, k.proc == i, p)
+		}
+	}
+}
+
+// place picks which of the avail cores process i runs on: its own core if it
+// was interrupted there, else the fastest idle core it may run on, else the
+// fastest core it may run on. Equally good cores go in order.
+func (e *engine) place(i int, avail []int) int {
+	best := -1
+	for _, c := range avail {
+		k := &e.cores[c]
+		switch {
+		case k.proc == i:
+			return c
+		case !e.processes[i].allows(c):
+		case best < 0:
+			best = c
+		case (k.proc < 0) != (e.cores[best].proc < 0):
+			if k.proc < 0 {
+				best = c
+			}
+		case k.speed > e.cores[best].speed:
+			best = c
+		}
+	}
+
+	return best
+}
+
+// start runs process i on k from now, after idling from when k was last free
+// and dispatching i, unless i is resuming on k, which it does from now or
+// once the dispatch it was interrupted in is done.
+func (e *engine) start(k *core, i int, resumed bool, p policy) {
+	if !resumed || k.start < e.now {
+		k.start = e.now
+	}
+	if !resumed {
+		idle(&k.gantt, k.free, e.now)
+		k.start = e.opts.dispatch(&k.gantt, e.processes[i].ProcessID, e.now)
+		k.from = e.lastCore[i]
+		if last := e.lastCore[i]; last >= 0 && last != k.cpu {
+			k.start = e.opts.migrate(&k.gantt, e.processes[i].ProcessID, k.start)
+		}
+		e.lastCore[i] = k.cpu
+		e.enterAt(i, StateRunning, k.start)
+	}
+	k.proc, k.busy, k.resumed = i, true, resumed
+	// A core of speed s does s units of work per tick, so the burst takes
+	// the remaining work divided by s, rounded up, less any work carried
+	// over from the run it resumes. It stops early to acquire or release a
+	// lock.
+	k.limit = e.remaining[i]
+	if d, ok := e.untilLock(i); ok && d < k.limit {
+		k.limit = d
+	}
+	if !resumed {
+		k.carry = 0
+	}
+	k.stop, k.expires = k.start, false
+	if left := k.limit - k.carry; left > 0 {
+		k.stop += (left + k.speed - 1) / k.speed
+	}
+	if !resumed {
+		k.expiry = 0
+		if q := p.quantum(e.processes[i]); q > 0 {
+			k.expiry = k.start + q
+		}
+	}
+	if k.expiry > 0 && k.expiry < k.stop {
+		k.stop, k.expires = k.expiry, true
+	}
+	e.scheduleForks(k)
+}
+
+// undispatch takes the process on k off it before it started running: its
+// switch, dispatch, or migration stops at now, when the core is free again,
+// and the process is ready as though it had never been dispatched.
+func (e *engine) undispatch(k *core) {
+	i := k.proc
+	for n := len(k.gantt) - 1; n >= 0 && k.gantt[n].Stop > e.now; n-- {
+		if k.gantt[n].Start < e.now {
+			k.gantt[n].Stop = e.now
+			break
+		}
+		k.gantt = k.gantt[:n]
+	}
+	k.free = e.now
+	e.lastCore[i] = k.from
+
+	// It entered running as of k.start, so it waited until then.
+	e.residency[i].Ready -= k.start - e.now
+	e.state[i], e.since[i] = StateReady, e.now
+	pid := e.processes[i].ProcessID
+	for t := len(e.transitions) - 1; t >= 0; t-- {
+		if tr := e.transitions[t]; tr.PID == pid && tr.State == StateRunning && tr.At == k.start {
+			e.transitions = append(e.transitions[:t], e.transitions[t+1:]...)
+			break
+		}
+	}
+	if e.opts.Transitions != nil {
+		e.opts.Transitions(Transition{PID: pid, At: e.now, State: StateReady, Priority: e.agedPriority(i, e.residency[i].Ready)})
+	}
+}
+
+// gantt merges the Gantt charts of every core, ordered by start time, with
+// each slice labelled by its core. The cores' own charts go back to the
+// pool once merged, unless a checkpoint may share them.
+func (e *engine) gantt() []TimeSlice {
+	if len(e.cores) == 1 {
+		return e.cores[0].gantt
+	}
+	gantt := newGantt()
+	for c := range e.cores {
+		for _, ts := range e.cores[c].gantt {
+			ts.CPU = c
+			gantt = append(gantt, ts)
+		}
+		// A checkpoint shares the cores' charts.
+		if e.opts.CheckpointAt == 0 {
+			putGantt(e.cores[c].gantt)
+			e.cores[c].gantt = nil
+		}
+	}
+	sort.SliceStable(gantt, func(a, b int) bool { return gantt[a].Start < gantt[b].Start })
+
+	return gantt
+}
+
+// block takes process i, which finished a CPU burst, off the CPU for its next
+// I/O burst, after which it is ready for the following CPU burst.
+func (e *engine) block(i int) {
+	c := e.processes[i].Cycles[e.cycle[i]]
+	e.cycle[i]++
+	e.remaining[i] = c.CPU * speedScale
+	e.events.schedule(e.now+c.IO, eventIODone, i)
+	e.enter(i, StateBlocked)
+}
+
+// agedPriority is the priority process i is scheduled at after waiting for
+// waited ticks: lowered (improved) by the aging rate for every tick.
+func (e *engine) agedPriority(i int, waited int64) float64 {
+	return float64(e.priority(i)) - e.opts.Aging*float64(waited)
+}
+
+// waited is how long process i has spent waiting to run.
+func (e *engine) waited(i int) int64 {
+	if e.state[i] != StateReady {
+		return e.residency[i].Ready
+	}
+	return e.residency[i].Ready + e.now - e.since[i]
+}
+
+// complete records the timing of process i, which finished at now.
+func (e *engine) complete(i int, pol policy) {
+	row := e.terminate(i, pol)
+	e.totalWait += float64(row.Wait)
+	e.totalTurnaround += float64(row.Turnaround)
+	e.update()
+}
+
+// kill terminates process i at now unless it already completed: it comes off
+// its CPU, the ready processes, or the lock it waits on, and gives up any
+// lock it holds. A run due to end now ends first, so a process completing
+// as it is killed counts as completed, and one still being dispatched stops
+// being dispatched.
+func (e *engine) kill(i int, p policy) {
+	for c := range e.cores {
+		if k := &e.cores[c]; k.busy && k.proc == i {
+			if k.stop > e.now {
+				k.stop, k.expires = e.now, false
+			}
+			e.end(k, p)
+			if k.proc == i {
+				if k.start > e.now {
+					e.undispatch(k)
+				}
+				k.proc = -1
+			}
+		}
+	}
+	if e.state[i] == StateTerminated {
+		return
+	}
+	p.drop(i)
+	if locks := e.processes[i].Locks; e.section[i] < len(locks) {
+		m := e.locks[locks[e.section[i]].Name]
+		switch {
+		case e.holding[i]:
+			e.holding[i] = false
+			e.release(locks[e.section[i]].Name, p)
+		case m != nil:
+			for k, w := range m.waiters {
+				if w == i {
+					m.waiters = append(m.waiters[:k], m.waiters[k+1:]...)
+					e.lockWait[i] += e.now - e.blockedSince[i]
+					if m.holder >= 0 {
+						p.rerank(m.holder)
+					}
+					break
+				}
+			}
+		}
+		e.revert(i)
+	}
+	e.terminate(i, p).Killed = true
+	e.killed++
+	e.update()
+}
+
+// terminate records the timing of process i, which left the system at now,
+// and returns its row.
+func (e *engine) terminate(i int, pol policy) *Row {
+	p := e.processes[i]
+	e.unsuspend(i)
+	e.enter(i, StateTerminated)
+	turnaround := e.now - p.ArrivalTime
+	waitingTime := e.residency[i].Ready + e.lockWait[i]
+	admission := e.now - p.ArrivalTime
+	if e.admitted[i] >= 0 {
+		admission = e.admitted[i] - p.ArrivalTime
+	}
+	e.schedule[i] = &Row{
+		ProcessID:     p.ProcessID,
+		Priority:      p.Priority,
+		BurstDuration: p.CPUTime(),
+		IO:            p.IOTime(),
+		ArrivalTime:   p.ArrivalTime,
+		Wait:          waitingTime,
+		Turnaround:    turnaround,
+		Exit:          e.now,
+		Nice:          p.Nice,
+		Memory:        p.Memory,
+		Admission:     admission,
+		Deadline:      p.deadline(),
+		Task:          p.Task,
+		Job:           p.Job,
+		Parent:        p.Parent,
+		Slice:         pol.quantum(p),
+		Residency:     e.residency[i],
+	}
+	e.done++
+	e.opts.progress(e.done, len(e.processes))
+	e.free(i, pol)
+
+	return e.schedule[i]
+}
+
+// update hands Options.Updates the metrics of the processes terminated so
+// far.
+func (e *engine) update() {
+	if e.opts.Updates == nil {
+		return
+	}
+	u := MetricsUpdate{At: e.now, Completed: e.done - e.killed, Killed: e.killed, Total: len(e.processes)}
+	if u.Completed > 0 {
+		u.AverageWait = e.totalWait / float64(u.Completed)
+		u.AverageTurnaround = e.totalTurnaround / float64(u.Completed)
+		if e.now > 0 {
+			u.Throughput = float64(u.Completed) / float64(e.now)
+		}
+	}
+	e.opts.Updates(u)
+}
+
+// fifo serves ready processes in the order they became ready, each for up
+// to slice (0 for no limit): first-come, first-serve and round-robin.
+type fifo struct {
+	readyQueue
+	slice int64
+}
+
+func (f *fifo) add(i int) { f.push(i) }
+func (f *fifo) drop(i int) {
+	for k := 0; k < f.len(); k++ {
+		if f.at(k) == i {
+			f.readyQueue.remove(k)
+			return
+		}
+	}
+}
+func (f *fifo) rerank(int) {}
+func (f *fifo) pick(_ *engine, allowed func(i int) bool) int {
+	for k := 0; k < f.len(); k++ {
+		if i := f.at(k); allowed(i) {
+			return f.remove(k)
+		}
+	}
+	return -1
+}
+
+// quantum scales the slice by the process's nice value.
+func (f *fifo) quantum(p Process) int64 {
+	if f.slice == 0 {
+		return 0
+	}
+	return niceSlice(f.slice, p.Nice)
+}
+
+func (f *fifo) preemptive() bool { return false }
+func (f *fifo) queued() []int    { return append([]int(nil), f.items[f.head:]...) }
+
+// niceSlice scales a time slice by a nice value the way CFS weights shares:
+// each step of nice changes the slice by about 25%, and every slice is at
+// least one tick.
+func niceSlice(slice, nice int64) int64 {
+	scaled := int64(math.Round(float64(slice) * math.Pow(1.25, float64(-nice))))
+	if scaled < 1 {
+		return 1
+	}
+	return scaled
+}
+
+// ranked always runs the ready processes ranked first by before, choosing
+// again whenever an event is due. On a tie a running process keeps its CPU,
+// so equal candidates never cause a needless switch.
+type ranked struct {
+	ready  rankedQueue
+	before func(e *engine, i, j int) bool
+}
+
+func (r *ranked) add(i int)    { r.ready.push(i) }
+func (r *ranked) drop(i int)   { r.ready.remove(i) }
+func (r *ranked) rerank(i int) { r.ready.rerank(i) }
+func (r *ranked) len() int     { return r.ready.len() }
+
+func (r *ranked) pick(e *engine, allowed func(i int) bool) int {
+	return r.ready.pop(e, r.before, allowed)
+}
+
+func (r *ranked) quantum(Process) int64 { return 0 }
+func (r *ranked) preemptive() bool      { return true }
+func (r *ranked) queued() []int         { return r.ready.items() }
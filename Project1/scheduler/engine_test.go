@@ -0,0 +1,339 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestSimulate(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: 1, ArrivalTime: 0, BurstDuration: 4},
+		{ProcessID: 2, ArrivalTime: 1, BurstDuration: 3},
+	}
+	shorter := func(e *engine, i, j int) bool { return e.remaining[i] < e.remaining[j] }
+	tests := []struct {
+		name   string
+		policy policy
+		opts   []Option
+		want   []TimeSlice
+	}{
+		{
+			name:   "fifo without a quantum runs to completion",
+			policy: &fifo{},
+			want: []TimeSlice{
+				{PID: 1, Start: 0, Stop: 4},
+				{PID: 2, Start: 4, Stop: 7},
+			},
+		},
+		{
+			name:   "fifo quantum preempts",
+			policy: &fifo{slice: 3},
+			want: []TimeSlice{
+				{PID: 1, Start: 0, Stop: 3},
+				{PID: 2, Start: 3, Stop: 6},
+				{PID: 1, Start: 6, Stop: 7},
+			},
+		},
+		{
+			name:   "ranked tie keeps the running process",
+			policy: &ranked{before: shorter},
+			opts:   []Option{WithSwitchCost(1)},
+			want: []TimeSlice{
+				{PID: 1, Start: 0, Stop: 4},
+				{PID: 2, Start: 4, Stop: 5, Kind: SliceSwitch},
+				{PID: 2, Start: 5, Stop: 8},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			r, err := simulate(context.Background(), processes, newOptions(tt.opts), tt.policy)
+			if err != nil {
+				t.Fatalf("simulate() error = %v", err)
+			}
+			if !reflect.DeepEqual(r.Gantt, tt.want) {
+				t.Errorf("Gantt = %v, want %v", r.Gantt, tt.want)
+			}
+			if r.Metrics.Completed != len(processes) {
+				t.Errorf("Completed = %d, want %d", r.Metrics.Completed, len(processes))
+			}
+		})
+	}
+}
+
+func TestSimulateSparse(t *testing.T) {
+	t.Parallel()
+	// The clock jumps between events, so huge gaps and bursts cost nothing.
+	processes := []Process{
+		{ProcessID: 1, ArrivalTime: 0, BurstDuration: 1 << 40},
+		{ProcessID: 2, ArrivalTime: 1 << 50, BurstDuration: 1 << 40},
+	}
+	r, err := NewSJF().Schedule(context.Background(), processes)
+	if err != nil {
+		t.Fatalf("Schedule() error = %v", err)
+	}
+	want := []TimeSlice{
+		{PID: 1, Start: 0, Stop: 1 << 40},
+		{Start: 1 << 40, Stop: 1 << 50, Kind: SliceIdle},
+		{PID: 2, Start: 1 << 50, Stop: 1<<50 + 1<<40},
+	}
+	if !reflect.DeepEqual(r.Gantt, want) {
+		t.Errorf("Gantt = %v, want %v", r.Gantt, want)
+	}
+}
+
+func TestSimulateSparseArrivals(t *testing.T) {
+	t.Parallel()
+	// With nothing ready, every scheduler jumps the clock straight to the
+	// next arrival rather than idling through the gap.
+	processes := []Process{
+		{ProcessID: 1, ArrivalTime: 0, BurstDuration: 3},
+		{ProcessID: 2, ArrivalTime: 1 << 50, BurstDuration: 3},
+		{ProcessID: 3, ArrivalTime: 1 << 55, BurstDuration: 3},
+	}
+	for _, name := range []string{"fcfs", "sjf", "priority", "rr", "edf", "rm"} {
+		factory, _ := Lookup(name)
+		r, err := factory(WithQuantum(4)).Schedule(context.Background(), processes)
+		if err != nil {
+			t.Fatalf("%s: Schedule() error = %v", name, err)
+		}
+		want := []TimeSlice{
+			{PID: 1, Start: 0, Stop: 3},
+			{Start: 3, Stop: 1 << 50, Kind: SliceIdle},
+			{PID: 2, Start: 1 << 50, Stop: 1<<50 + 3},
+			{Start: 1<<50 + 3, Stop: 1 << 55, Kind: SliceIdle},
+			{PID: 3, Start: 1 << 55, Stop: 1<<55 + 3},
+		}
+		if !reflect.DeepEqual(r.Gantt, want) {
+			t.Errorf("%s: Gantt = %v, want %v", name, r.Gantt, want)
+		}
+	}
+}
+
+func TestSimulateCPUs(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name          string
+		scheduler     Scheduler
+		processes     []Process
+		wantGantt     []TimeSlice
+		wantExits     []int64
+		wantCores     []Core
+		wantImbalance float64
+	}{
+		{
+			name:      "FCFS fills the free core",
+			scheduler: NewFCFS(WithCPUs(2)),
+			processes: []Process{
+				{ProcessID: 1, BurstDuration: 4},
+				{ProcessID: 2, BurstDuration: 2},
+				{ProcessID: 3, ArrivalTime: 1, BurstDuration: 3},
+			},
+			wantGantt: []TimeSlice{
+				{PID: 1, Start: 0, Stop: 4},
+				{PID: 2, Start: 0, Stop: 2, CPU: 1},
+				{PID: 3, Start: 2, Stop: 5, CPU: 1},
+			},
+			wantExits:     []int64{4, 2, 5},
+			wantCores:     []Core{{CPU: 0, Speed: 1, Busy: 4, Work: 4, Utilization: 0.8}, {CPU: 1, Speed: 1, Busy: 5, Work: 5, Utilization: 1}},
+			wantImbalance: 5/4.5 - 1,
+		},
+		{
+			name:      "faster cores go first",
+			scheduler: NewFCFS(WithCPUs(2), WithSpeeds(0.5, 2)),
+			processes: []Process{
+				{ProcessID: 1, BurstDuration: 4},
+				{ProcessID: 2, BurstDuration: 4},
+			},
+			wantGantt: []TimeSlice{
+				{PID: 2, Start: 0, Stop: 8},
+				{PID: 1, Start: 0, Stop: 2, CPU: 1},
+			},
+			wantExits:     []int64{2, 8},
+			wantCores:     []Core{{CPU: 0, Speed: 0.5, Busy: 8, Work: 4, Utilization: 1}, {CPU: 1, Speed: 2, Busy: 2, Work: 4, Utilization: 0.25}},
+			wantImbalance: 8/5.0 - 1,
+		},
+		{
+			name:          "slow core rounds the burst up to whole ticks",
+			scheduler:     NewSJF(WithCPUs(2), WithSpeeds(0.3, 0.3)),
+			processes:     []Process{{ProcessID: 1, BurstDuration: 1}},
+			wantGantt:     []TimeSlice{{PID: 1, Start: 0, Stop: 4}},
+			wantExits:     []int64{4},
+			wantCores:     []Core{{CPU: 0, Speed: 0.3, Busy: 4, Work: 1, Utilization: 1}, {CPU: 1, Speed: 0.3}},
+			wantImbalance: 1,
+		},
+		{
+			name:      "FCFS honours affinity",
+			scheduler: NewFCFS(WithCPUs(2)),
+			processes: []Process{
+				{ProcessID: 1, BurstDuration: 4, Affinity: []int{0}},
+				{ProcessID: 2, BurstDuration: 2, Affinity: []int{0}},
+				{ProcessID: 3, BurstDuration: 1},
+			},
+			wantGantt: []TimeSlice{
+				{PID: 1, Start: 0, Stop: 4},
+				{PID: 3, Start: 0, Stop: 1, CPU: 1},
+				{PID: 2, Start: 4, Stop: 6},
+			},
+			wantExits:     []int64{4, 6, 1},
+			wantCores:     []Core{{CPU: 0, Speed: 1, Busy: 6, Work: 6, Utilization: 1}, {CPU: 1, Speed: 1, Busy: 1, Work: 1, Utilization: 1.0 / 6}},
+			wantImbalance: 6/3.5 - 1,
+		},
+		{
+			name:      "SJF preempts the longer of two running processes",
+			scheduler: NewSJF(WithCPUs(2)),
+			processes: []Process{
+				{ProcessID: 1, BurstDuration: 10},
+				{ProcessID: 2, BurstDuration: 10},
+				{ProcessID: 3, ArrivalTime: 1, BurstDuration: 1},
+			},
+			wantGantt: []TimeSlice{
+				{PID: 1, Start: 0, Stop: 10},
+				{PID: 2, Start: 0, Stop: 1, CPU: 1},
+				{PID: 3, Start: 1, Stop: 2, CPU: 1},
+				{PID: 2, Start: 2, Stop: 11, CPU: 1},
+			},
+			wantExits:     []int64{10, 11, 2},
+			wantCores:     []Core{{CPU: 0, Speed: 1, Busy: 10, Work: 10, Utilization: 10.0 / 11}, {CPU: 1, Speed: 1, Busy: 11, Work: 11, Utilization: 1}},
+			wantImbalance: 11/10.5 - 1,
+		},
+		{
+			name:      "SJF moves an interrupted process rather than idle a core",
+			scheduler: NewSJF(WithCPUs(3)),
+			processes: []Process{
+				{ProcessID: 1, BurstDuration: 8, Affinity: []int{0}},
+				{ProcessID: 2, BurstDuration: 9},
+				{ProcessID: 3, ArrivalTime: 1, BurstDuration: 2, Affinity: []int{1}},
+				{ProcessID: 4, ArrivalTime: 1, BurstDuration: 9, Affinity: []int{2}},
+			},
+			wantGantt: []TimeSlice{
+				{PID: 1, Start: 0, Stop: 8},
+				{PID: 2, Start: 0, Stop: 1, CPU: 1},
+				{Start: 0, Stop: 1, CPU: 2, Kind: SliceIdle},
+				{PID: 3, Start: 1, Stop: 3, CPU: 1},
+				{PID: 2, Start: 1, Stop: 9, CPU: 2},
+				{PID: 4, Start: 9, Stop: 18, CPU: 2},
+			},
+			wantExits: []int64{8, 9, 3, 18},
+			wantCores: []Core{
+				{CPU: 0, Speed: 1, Busy: 8, Work: 8, Utilization: 8.0 / 18},
+				{CPU: 1, Speed: 1, Busy: 3, Work: 3, Utilization: 3.0 / 18},
+				{CPU: 2, Speed: 1, Busy: 17, Work: 17, Utilization: 17.0 / 18},
+			},
+			wantImbalance: 17/(28.0/3) - 1,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			r, err := tt.scheduler.Schedule(context.Background(), tt.processes)
+			if err != nil {
+				t.Fatalf("Schedule() error = %v", err)
+			}
+			if !reflect.DeepEqual(r.Gantt, tt.wantGantt) {
+				t.Errorf("Gantt = %v, want %v", r.Gantt, tt.wantGantt)
+			}
+			for k, row := range r.Rows {
+				if row.Exit != tt.wantExits[k] {
+					t.Errorf("process %d exit = %d, want %d", row.ProcessID, row.Exit, tt.wantExits[k])
+				}
+			}
+			if !reflect.DeepEqual(r.Metrics.Cores, tt.wantCores) {
+				t.Errorf("Cores = %+v, want %+v", r.Metrics.Cores, tt.wantCores)
+			}
+			if math.Abs(r.Metrics.LoadImbalance-tt.wantImbalance) > 1e-9 {
+				t.Errorf("LoadImbalance = %v, want %v", r.Metrics.LoadImbalance, tt.wantImbalance)
+			}
+		})
+	}
+}
+
+func TestSimulate_affinityOutOfRange(t *testing.T) {
+	t.Parallel()
+	_, err := NewRR(WithCPUs(2)).Schedule(context.Background(), []Process{
+		{ProcessID: 1, BurstDuration: 1},
+		{ProcessID: 2, BurstDuration: 1, Affinity: []int{2}},
+	})
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) || validationErr.Row != 2 {
+		t.Errorf("error = %v, want a validation error for row 2", err)
+	}
+}
+
+func TestSimulate_migrationCost(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: 1, BurstDuration: 4},
+		{ProcessID: 2, BurstDuration: 2},
+		{ProcessID: 3, BurstDuration: 2},
+	}
+	// 1 and 2 start on CPUs 0 and 1; 1 then resumes on CPU 1 after 3 takes
+	// CPU 0, paying to move.
+	r, err := NewRR(WithQuantum(2), WithCPUs(2), WithMigrationCost(1)).Schedule(context.Background(), processes)
+	if err != nil {
+		t.Fatalf("Schedule() error = %v", err)
+	}
+	want := []TimeSlice{
+		{PID: 1, Start: 0, Stop: 2},
+		{PID: 2, Start: 0, Stop: 2, CPU: 1},
+		{PID: 3, Start: 2, Stop: 4},
+		{PID: 1, Start: 2, Stop: 3, CPU: 1, Kind: SliceMigrate},
+		{PID: 1, Start: 3, Stop: 5, CPU: 1},
+	}
+	if !reflect.DeepEqual(r.Gantt, want) {
+		t.Errorf("Gantt = %v, want %v", r.Gantt, want)
+	}
+	if r.Metrics.Migrations != 1 || r.Metrics.MigrationPenalty != 1 || r.Metrics.Overhead != 1 {
+		t.Errorf("Migrations = %d, MigrationPenalty = %v, Overhead = %v, want 1, 1 and 1",
+			r.Metrics.Migrations, r.Metrics.MigrationPenalty, r.Metrics.Overhead)
+	}
+	if r.Ideal == nil || r.Ideal.MigrationPenalty != 0 || r.Ideal.Makespan != 4 {
+		t.Errorf("Ideal = %+v, want no migration penalty and makespan 4", r.Ideal)
+	}
+}
+
+func Test_niceSlice(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		slice, nice, want int64
+	}{
+		{slice: 2, nice: 0, want: 2},
+		{slice: 2, nice: -5, want: 6},
+		{slice: 4, nice: 3, want: 2},
+		{slice: 2, nice: 19, want: 1},
+	}
+	for _, tt := range tests {
+		if got := niceSlice(tt.slice, tt.nice); got != tt.want {
+			t.Errorf("niceSlice(%d, %d) = %d, want %d", tt.slice, tt.nice, got, tt.want)
+		}
+	}
+}
+
+func TestRR_nice(t *testing.T) {
+	t.Parallel()
+	r, err := NewRR(WithQuantum(2)).Schedule(context.Background(), []Process{
+		{ProcessID: 1, BurstDuration: 6, Nice: -5},
+		{ProcessID: 2, BurstDuration: 4},
+	})
+	if err != nil {
+		t.Fatalf("Schedule() error = %v", err)
+	}
+	want := []TimeSlice{
+		{PID: 1, Start: 0, Stop: 6},
+		{PID: 2, Start: 6, Stop: 8},
+		{PID: 2, Start: 8, Stop: 10},
+	}
+	if !reflect.DeepEqual(r.Gantt, want) {
+		t.Errorf("Gantt = %v, want %v", r.Gantt, want)
+	}
+	if r.Rows[0].Slice != 6 || r.Rows[1].Slice != 2 {
+		t.Errorf("slices = %d, %d, want 6, 2", r.Rows[0].Slice, r.Rows[1].Slice)
+	}
+}
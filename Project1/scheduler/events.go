@@ -0,0 +1,105 @@
+package scheduler
+
+import "container/heap"
+
+// eventKind is what happens to a process at an event.
+type eventKind int
+
+const (
+	// eventArrival makes a process ready for the first time.
+	eventArrival eventKind = iota
+	// eventIODone makes a blocked process ready again for its next CPU burst.
+	eventIODone
+	// eventKill terminates a process that has not completed yet.
+	eventKill
+	// eventSuspend and eventResume begin and end a suspension.
+	eventSuspend
+	eventResume
+	// eventFork spawns the children of a process that has reached their
+	// fork points.
+	eventFork
+)
+
+// event is something scheduled to happen to process proc at time at.
+type event struct {
+	at   int64
+	kind eventKind
+	proc int
+	seq  int // order the event was scheduled in, breaking ties on at
+}
+
+// eventQueue is a min-heap of pending events ordered by time, so the engine
+// can jump straight to the next one. Events due at the same time come out in
+// the order they were scheduled.
+//
+// Events scheduled in time order, such as the arrivals of a whole workload,
+// bypass the heap: they wait in sorted from head on, and the queue takes the
+// earlier of its front and the heap's.
+type eventQueue struct {
+	events []event
+	sorted []event
+	head   int
+	seq    int
+}
+
+// before reports whether event a is due before event b.
+func (a event) before(b event) bool {
+	if a.at != b.at {
+		return a.at < b.at
+	}
+	return a.seq < b.seq
+}
+
+func (q *eventQueue) Len() int           { return len(q.events) }
+func (q *eventQueue) Less(a, b int) bool { return q.events[a].before(q.events[b]) }
+func (q *eventQueue) Swap(a, b int)      { q.events[a], q.events[b] = q.events[b], q.events[a] }
+func (q *eventQueue) Push(x interface{}) { q.events = append(q.events, x.(event)) }
+func (q *eventQueue) Pop() interface{} {
+	last := len(q.events) - 1
+	ev := q.events[last]
+	q.events = q.events[:last]
+	return ev
+}
+
+// schedule adds an event for process proc at time at.
+func (q *eventQueue) schedule(at int64, kind eventKind, proc int) {
+	heap.Push(q, event{at: at, kind: kind, proc: proc, seq: q.seq})
+	q.seq++
+}
+
+// scheduleSorted adds an event for process proc at time at, which is
+// expected to be no earlier than the last event it added, so the event
+// can skip the heap; an earlier one is scheduled as usual.
+func (q *eventQueue) scheduleSorted(at int64, kind eventKind, proc int) {
+	if n := len(q.sorted); n > q.head && at < q.sorted[n-1].at {
+		q.schedule(at, kind, proc)
+		return
+	}
+	q.sorted = append(q.sorted, event{at: at, kind: kind, proc: proc, seq: q.seq})
+	q.seq++
+}
+
+// peek returns the next pending event without removing it.
+func (q *eventQueue) peek() (event, bool) {
+	switch {
+	case q.head < len(q.sorted) && (len(q.events) == 0 || q.sorted[q.head].before(q.events[0])):
+		return q.sorted[q.head], true
+	case len(q.events) > 0:
+		return q.events[0], true
+	}
+	return event{}, false
+}
+
+// next removes and returns the next pending event.
+func (q *eventQueue) next() event {
+	if q.head < len(q.sorted) && (len(q.events) == 0 || q.sorted[q.head].before(q.events[0])) {
+		q.head++
+		return q.sorted[q.head-1]
+	}
+	return heap.Pop(q).(event)
+}
+
+// pending returns every pending event, in no particular order.
+func (q *eventQueue) pending() []event {
+	return append(append([]event(nil), q.events...), q.sorted[q.head:]...)
+}
@@ -0,0 +1,52 @@
+package scheduler
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestEventQueue(t *testing.T) {
+	t.Parallel()
+	var q eventQueue
+	q.schedule(5, eventArrival, 0)
+	q.schedule(1, eventArrival, 1)
+	q.schedule(5, eventArrival, 2)
+	q.schedule(3, eventArrival, 3)
+	if ev, ok := q.peek(); !ok || ev.proc != 1 {
+		t.Errorf("peek() = %v, %v, want process 1", ev, ok)
+	}
+	var got []int
+	for q.Len() > 0 {
+		got = append(got, q.next().proc)
+	}
+	if want := []int{1, 3, 0, 2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("event order = %v, want %v", got, want)
+	}
+	if _, ok := q.peek(); ok {
+		t.Error("peek() on an empty queue reported an event")
+	}
+}
+
+func TestEventQueue_scheduleSorted(t *testing.T) {
+	t.Parallel()
+	var q eventQueue
+	q.scheduleSorted(1, eventArrival, 0)
+	q.scheduleSorted(4, eventArrival, 1)
+	q.schedule(4, eventKill, 2)
+	q.scheduleSorted(2, eventArrival, 3)
+	q.schedule(0, eventKill, 4)
+	q.scheduleSorted(4, eventArrival, 5)
+	if n := len(q.sorted); n != 3 {
+		t.Errorf("%d events skipped the heap, want 3", n)
+	}
+	if n := len(q.pending()); n != 6 {
+		t.Errorf("pending() has %d events, want 6", n)
+	}
+	var got []int
+	for _, ok := q.peek(); ok; _, ok = q.peek() {
+		got = append(got, q.next().proc)
+	}
+	if want := []int{4, 0, 3, 1, 2, 5}; !reflect.DeepEqual(got, want) {
+		t.Errorf("event order = %v, want %v", got, want)
+	}
+}
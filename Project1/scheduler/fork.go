@@ -0,0 +1,275 @@
+package scheduler
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+)
+
+// Tree summarizes a process that spawned children and every process it
+// spawned, directly or through its children.
+type Tree struct {
+	Root      int64 `json:"root"`
+	Processes int   `json:"processes"`
+	// AverageWait and AverageTurnaround cover the completed processes of
+	// the tree, and Exit is when the last of them left the system.
+	AverageWait       float64 `json:"average_wait"`
+	AverageTurnaround float64 `json:"average_turnaround"`
+	Exit              int64   `json:"exit"`
+}
+
+// parseParent parses a parent field value of the form pid:offset, parsing
+// the offset with parseTime.
+func parseParent(s string, parseTime func(string) (int64, error)) (int64, int64, error) {
+	pid, offset, ok := strings.Cut(s, ":")
+	if !ok {
+		return 0, 0, fmt.Errorf("parent %q is not pid:offset", s)
+	}
+	parent, err := strToInt(pid)
+	if err != nil {
+		return 0, 0, err
+	}
+	at, err := parseTime(offset)
+	if err != nil {
+		return 0, 0, err
+	}
+	return parent, at, nil
+}
+
+// validateForks checks that every parent exists, that children fork within
+// their parent's first burst, and that no process is its own ancestor.
+func validateForks(processes []Process) error {
+	byID := indexByID(processes)
+	for i, p := range processes {
+		if p.Parent == 0 {
+			continue
+		}
+		var err error
+		parent, ok := byID[p.Parent]
+		switch {
+		case !ok:
+			err = fmt.Errorf("parent %d does not exist", p.Parent)
+		case p.ForkAt < 0 || p.ForkAt > processes[parent].BurstDuration:
+			err = fmt.Errorf("fork at %d is outside parent %d's first burst", p.ForkAt, p.Parent)
+		case p.KillAt != 0:
+			err = fmt.Errorf("child of %d cannot have a kill time", p.Parent)
+		}
+		// Walk up the ancestors; a cycle not through i is caught at its own rows.
+		for j, steps := parent, 0; err == nil && ok && steps < len(processes); steps++ {
+			if j == i {
+				err = fmt.Errorf("process %d is its own ancestor", p.ProcessID)
+			} else if processes[j].Parent == 0 {
+				break
+			}
+			j, ok = byID[processes[j].Parent]
+		}
+		if err != nil {
+			return &ValidationError{Row: i + 1, Err: err}
+		}
+	}
+
+	return nil
+}
+
+// trackForks records the children of every process, in the order they
+// fork, and returns whether there are any.
+func (e *engine) trackForks() bool {
+	e.children = make([][]int, len(e.processes))
+	e.forked = make([]int, len(e.processes))
+	found := false
+	for i, p := range e.processes {
+		if p.Parent != 0 {
+			parent := e.index[p.Parent]
+			e.children[parent] = append(e.children[parent], i)
+			found = true
+		}
+	}
+	for _, children := range e.children {
+		sort.SliceStable(children, func(a, b int) bool {
+			return e.processes[children[a]].ForkAt < e.processes[children[b]].ForkAt
+		})
+	}
+
+	return found
+}
+
+// scheduleForks schedules a fork event at the time the run starting on k
+// reaches each fork point of its process it will reach. A run cut short
+// leaves its events behind, which fork nothing.
+func (e *engine) scheduleForks(k *core) {
+	if e.children == nil {
+		return
+	}
+	i := k.proc
+	done := e.progress(i)
+	if done < 0 {
+		return
+	}
+	for _, c := range e.children[i][e.forked[i]:] {
+		d := e.processes[c].ForkAt*speedScale - done
+		if d > k.limit {
+			return
+		}
+		at := k.start
+		if d > k.carry {
+			at += (d - k.carry + k.speed - 1) / k.speed
+		}
+		if at > k.stop {
+			return
+		}
+		e.events.schedule(at, eventFork, i)
+	}
+}
+
+// fork spawns the children of process i whose fork points it has reached by
+// now: they arrive now, and ones without a priority take i's.
+func (e *engine) fork(i int, p policy) {
+	done := e.progress(i)
+	for c := range e.cores {
+		if k := &e.cores[c]; k.busy && k.proc == i && done >= 0 && e.now >= k.start {
+			work := k.carry + (e.now-k.start)*k.speed
+			if work > k.limit {
+				work = k.limit
+			}
+			done += work
+		}
+	}
+	for ; e.forked[i] < len(e.children[i]); e.forked[i]++ {
+		c := e.children[i][e.forked[i]]
+		if done >= 0 && e.processes[c].ForkAt*speedScale > done {
+			return
+		}
+		if e.processes[c].Priority == 0 {
+			e.processes[c].Priority = e.processes[i].Priority
+		}
+		e.processes[c].ArrivalTime = e.now
+		e.since[c] = e.now
+		e.arrive(c, e.now, p)
+	}
+}
+
+// trees summarizes the process trees among rows, in the order of their
+// roots, if any process has a parent.
+func trees(rows []Row) []Tree {
+	byID := make(map[int64]int, len(rows))
+	for k, row := range rows {
+		byID[row.ProcessID] = k
+	}
+	roots := make([]int, len(rows))
+	spawned := make(map[int]bool)
+	for k := range rows {
+		r := k
+		for rows[r].Parent != 0 {
+			parent, ok := byID[rows[r].Parent]
+			if !ok {
+				break
+			}
+			r = parent
+		}
+		roots[k] = r
+		if r != k {
+			spawned[r] = true
+		}
+	}
+
+	var ts []Tree
+	for r := range rows {
+		if !spawned[r] {
+			continue
+		}
+		t := Tree{Root: rows[r].ProcessID}
+		completed := 0
+		for k, row := range rows {
+			if roots[k] != r {
+				continue
+			}
+			t.Processes++
+			if row.Exit > t.Exit {
+				t.Exit = row.Exit
+			}
+			if !row.Killed {
+				completed++
+				t.AverageWait += float64(row.Wait)
+				t.AverageTurnaround += float64(row.Turnaround)
+			}
+		}
+		if completed > 0 {
+			t.AverageWait /= float64(completed)
+			t.AverageTurnaround /= float64(completed)
+		}
+		ts = append(ts, t)
+	}
+
+	return ts
+}
+
+// treeRows lists rows depth first from each root, each ID indented under
+// its parent.
+func treeRows(rows []Row, u float64) [][]string {
+	children := make(map[int64][]int)
+	present := make(map[int64]bool, len(rows))
+	for _, row := range rows {
+		present[row.ProcessID] = true
+	}
+	var roots []int
+	for k, row := range rows {
+		if row.Parent != 0 && present[row.Parent] {
+			children[row.Parent] = append(children[row.Parent], k)
+		} else {
+			roots = append(roots, k)
+		}
+	}
+	var text [][]string
+	var walk func(k, depth int)
+	walk = func(k, depth int) {
+		row := rows[k]
+		id := fmt.Sprint(row.ProcessID)
+		if depth > 0 {
+			id = strings.Repeat("  ", depth-1) + "└ " + id
+		}
+		exit := unitTime(row.Exit, u)
+		if row.Killed {
+			exit += " (killed)"
+		}
+		text = append(text, []string{id, unitTime(row.ArrivalTime, u), unitTime(row.Wait, u), unitTime(row.Turnaround, u), exit})
+		for _, c := range children[row.ProcessID] {
+			walk(c, depth+1)
+		}
+	}
+	for _, k := range roots {
+		if len(children[rows[k].ProcessID]) > 0 {
+			walk(k, 0)
+		}
+	}
+
+	return text
+}
+
+// outputTrees writes the process trees, if any process has a parent.
+func outputTrees(w io.Writer, rows []Row, ts []Tree, u float64) {
+	if len(ts) == 0 {
+		return
+	}
+	_, _ = fmt.Fprintln(w, "Process tree")
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"ID", "Arrival", "Wait", "Turnaround", "Exit"})
+	table.SetColumnAlignment([]int{tablewriter.ALIGN_LEFT, tablewriter.ALIGN_RIGHT, tablewriter.ALIGN_RIGHT, tablewriter.ALIGN_RIGHT, tablewriter.ALIGN_RIGHT})
+	table.AppendBulk(treeRows(rows, u))
+	table.Render()
+
+	table = tablewriter.NewWriter(w)
+	table.SetHeader([]string{"Root", "Processes", "Average wait", "Average turnaround", "Exit"})
+	for _, t := range ts {
+		table.Append([]string{
+			fmt.Sprint(t.Root),
+			fmt.Sprint(t.Processes),
+			fmt.Sprintf("%.2f", t.AverageWait/u),
+			fmt.Sprintf("%.2f", t.AverageTurnaround/u),
+			unitTime(t.Exit, u),
+		})
+	}
+	table.Render()
+}
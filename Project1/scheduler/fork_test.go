@@ -0,0 +1,103 @@
+package scheduler
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFork(t *testing.T) {
+	t.Parallel()
+	// 1 spawns 2, which inherits its priority, and 3, which keeps its own;
+	// 2 spawns 4 a tick into its own burst.
+	processes := []Process{
+		{ProcessID: 1, BurstDuration: 6, Priority: 2},
+		{ProcessID: 2, BurstDuration: 3, Parent: 1, ForkAt: 2},
+		{ProcessID: 3, BurstDuration: 2, Priority: 5, Parent: 1, ForkAt: 4},
+		{ProcessID: 4, BurstDuration: 1, Parent: 2, ForkAt: 1},
+		{ProcessID: 5, BurstDuration: 4, ArrivalTime: 1, Priority: 1},
+	}
+	r, err := NewFCFS().Schedule(context.Background(), processes)
+	if err != nil {
+		t.Fatalf("Schedule() error = %v", err)
+	}
+	gantt := []TimeSlice{
+		{PID: 1, Start: 0, Stop: 6},
+		{PID: 5, Start: 6, Stop: 10},
+		{PID: 2, Start: 10, Stop: 13},
+		{PID: 3, Start: 13, Stop: 15},
+		{PID: 4, Start: 15, Stop: 16},
+	}
+	if !reflect.DeepEqual(r.Gantt, gantt) {
+		t.Errorf("Gantt = %v, want %v", r.Gantt, gantt)
+	}
+	for k, want := range []struct{ arrival, priority, parent int64 }{{0, 2, 0}, {2, 2, 1}, {4, 5, 1}, {11, 2, 2}, {1, 1, 0}} {
+		row := r.Rows[k]
+		if row.ArrivalTime != want.arrival || row.Priority != want.priority || row.Parent != want.parent {
+			t.Errorf("process %d arrival, priority, parent = %d, %d, %d, want %d, %d, %d",
+				row.ProcessID, row.ArrivalTime, row.Priority, row.Parent, want.arrival, want.priority, want.parent)
+		}
+	}
+	trees := []Tree{{Root: 1, Processes: 4, AverageWait: 5.25, AverageTurnaround: 8.25, Exit: 16}}
+	if !reflect.DeepEqual(r.Trees, trees) {
+		t.Errorf("Trees = %+v, want %+v", r.Trees, trees)
+	}
+	if processes[1].ArrivalTime != 0 || processes[1].Priority != 0 {
+		t.Errorf("Schedule() modified its input: %+v", processes[1])
+	}
+}
+
+func TestFork_preempted(t *testing.T) {
+	t.Parallel()
+	// 2 preempts 1 before its fork point, so the child is spawned only once
+	// 1 resumes and reaches it.
+	r, err := NewSJF().Schedule(context.Background(), []Process{
+		{ProcessID: 1, BurstDuration: 5},
+		{ProcessID: 2, BurstDuration: 1, ArrivalTime: 1},
+		{ProcessID: 3, BurstDuration: 1, Parent: 1, ForkAt: 3},
+	})
+	if err != nil {
+		t.Fatalf("Schedule() error = %v", err)
+	}
+	if got := r.Rows[2].ArrivalTime; got != 4 {
+		t.Errorf("child arrival = %d, want 4", got)
+	}
+}
+
+func TestFork_killedParent(t *testing.T) {
+	t.Parallel()
+	r, err := NewFCFS().Schedule(context.Background(), []Process{
+		{ProcessID: 1, BurstDuration: 5, KillAt: 2},
+		{ProcessID: 2, BurstDuration: 1, Parent: 1, ForkAt: 3},
+	})
+	if err != nil {
+		t.Fatalf("Schedule() error = %v", err)
+	}
+	want := []Unfinished{{ProcessID: 2, BurstDuration: 1, Remaining: 1, State: StateNew}}
+	if r.Truncated || !reflect.DeepEqual(r.Unfinished, want) {
+		t.Errorf("Truncated = %v, Unfinished = %+v, want false and %+v", r.Truncated, r.Unfinished, want)
+	}
+}
+
+func TestRenderText_trees(t *testing.T) {
+	t.Parallel()
+	r := &ScheduleResult{
+		Rows: []Row{
+			{ProcessID: 1, Exit: 6, Turnaround: 6},
+			{ProcessID: 2, ArrivalTime: 2, Wait: 8, Turnaround: 11, Exit: 13, Parent: 1},
+			{ProcessID: 3, ArrivalTime: 11, Wait: 4, Turnaround: 5, Exit: 16, Parent: 2},
+		},
+	}
+	r.Trees = trees(r.Rows)
+	var w bytes.Buffer
+	if err := RenderText(&w, "t", r); err != nil {
+		t.Fatalf("RenderText() error = %v", err)
+	}
+	for _, want := range []string{"Process tree", "| └ 2   |       2 |    8 |         11 |   13 |", "|   └ 3 |      11 |", "|    1 |         3 |         4.00 |               7.33 |   16 |"} {
+		if !strings.Contains(w.String(), want) {
+			t.Errorf("RenderText() is missing %q:\n%s", want, w.String())
+		}
+	}
+}
@@ -0,0 +1,191 @@
+package scheduler
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// Generate returns n processes with IDs 1..n, burst durations in [1,20],
+// arrival gaps in [0,4], and priorities in [1,50], all drawn from rng.
+// Processes are returned in arrival order.
+func Generate(rng *rand.Rand, n int) []Process {
+	processes := make([]Process, n)
+	var arrival int64
+	for i := range processes {
+		processes[i] = Process{
+			ProcessID:     int64(i + 1),
+			ArrivalTime:   arrival,
+			BurstDuration: rng.Int63n(20) + 1,
+			Priority:      rng.Int63n(50) + 1,
+		}
+		arrival += rng.Int63n(5)
+	}
+
+	return processes
+}
+
+// generateShard is the number of processes each random stream of
+// GenerateParallel draws.
+const generateShard = 1 << 16
+
+// GenerateParallel returns n processes distributed as Generate's are, drawn
+// by as many goroutines as GOMAXPROCS allows. The processes are cut into
+// shards of a fixed size, each drawn from its own stream seeded from seed
+// and the shard's index, so the result depends only on seed and n, never on
+// how many goroutines drew it. It is not what Generate draws from a source
+// seeded with seed.
+func GenerateParallel(seed int64, n int) []Process {
+	processes := make([]Process, n)
+	shards := (n + generateShard - 1) / generateShard
+	// spans[s] is the arrival gap shard s adds up to, counted from its
+	// first arrival to the arrival after its last.
+	spans := make([]int64, shards)
+	eachShard(shards, func(s int) {
+		rng := rand.New(rand.NewSource(shardSeed(seed, s)))
+		lo, hi := shardBounds(s, n)
+		shard := processes[lo:hi]
+		var arrival int64
+		for i := range shard {
+			shard[i] = Process{
+				ProcessID:     int64(lo + i + 1),
+				ArrivalTime:   arrival,
+				BurstDuration: rng.Int63n(20) + 1,
+				Priority:      rng.Int63n(50) + 1,
+			}
+			arrival += rng.Int63n(5)
+		}
+		spans[s] = arrival
+	})
+	// Each shard then starts where the one before it left off.
+	for s := 1; s < shards; s++ {
+		spans[s] += spans[s-1]
+	}
+	eachShard(shards, func(s int) {
+		if s == 0 {
+			return
+		}
+		lo, hi := shardBounds(s, n)
+		for i := lo; i < hi; i++ {
+			processes[i].ArrivalTime += spans[s-1]
+		}
+	})
+
+	return processes
+}
+
+// shardBounds returns the indexes of the first process of shard s and of
+// the one after its last, of n processes.
+func shardBounds(s, n int) (lo, hi int) {
+	lo, hi = s*generateShard, (s+1)*generateShard
+	if hi > n {
+		hi = n
+	}
+	return lo, hi
+}
+
+// eachShard calls fn with every shard index below shards, from up to
+// GOMAXPROCS goroutines at once.
+func eachShard(shards int, fn func(s int)) {
+	var next atomic.Int64
+	var wg sync.WaitGroup
+	workers := runtime.GOMAXPROCS(0)
+	if shards < workers {
+		workers = shards
+	}
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for s := int(next.Add(1) - 1); s < shards; s = int(next.Add(1) - 1) {
+				fn(s)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// shardSeed derives the seed of shard s's stream from seed with SplitMix64,
+// so neighbouring shards and seeds get unrelated streams.
+func shardSeed(seed int64, s int) int64 {
+	z := uint64(seed) + uint64(s+1)*0x9e3779b97f4a7c15
+	z = (z ^ z>>30) * 0xbf58476d1ce4e5b9
+	z = (z ^ z>>27) * 0x94d049bb133111eb
+	return int64(z ^ z>>31)
+}
+
+// Distribution is how JitterWith draws the shift of each arrival time.
+type Distribution int
+
+const (
+	// Uniform shifts each arrival by a whole number drawn uniformly from
+	// [-amount, amount].
+	Uniform Distribution = iota
+	// Normal shifts each arrival by a normally distributed amount with a
+	// standard deviation of amount, rounded to a whole number.
+	Normal
+	// Exponential delays each arrival by an exponentially distributed amount
+	// with a mean of amount, rounded to a whole number, so processes only
+	// ever arrive late.
+	Exponential
+)
+
+var distributionNames = []string{"uniform", "normal", "exponential"}
+
+func (d Distribution) String() string {
+	if d < 0 || int(d) >= len(distributionNames) {
+		return fmt.Sprintf("Distribution(%d)", int(d))
+	}
+	return distributionNames[d]
+}
+
+// ParseDistribution returns the Distribution named uniform, normal, or
+// exponential.
+func ParseDistribution(name string) (Distribution, error) {
+	for d, n := range distributionNames {
+		if n == name {
+			return Distribution(d), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown distribution %q, want one of %s", name, strings.Join(distributionNames, ", "))
+}
+
+// Jitter returns a copy of processes with each arrival time shifted by a
+// value drawn uniformly from [-amount, amount] and clamped at zero.
+// The input slice is not modified.
+func Jitter(rng *rand.Rand, processes []Process, amount int64) []Process {
+	return JitterWith(rng, processes, amount, Uniform)
+}
+
+// JitterWith returns a copy of processes with each arrival time shifted by a
+// value of scale amount drawn from d, and clamped at zero. The input slice
+// is not modified.
+func JitterWith(rng *rand.Rand, processes []Process, amount int64, d Distribution) []Process {
+	jittered := make([]Process, len(processes))
+	copy(jittered, processes)
+	if amount <= 0 {
+		return jittered
+	}
+	for i := range jittered {
+		var shift int64
+		switch d {
+		case Normal:
+			shift = int64(math.Round(rng.NormFloat64() * float64(amount)))
+		case Exponential:
+			shift = int64(math.Round(rng.ExpFloat64() * float64(amount)))
+		default:
+			shift = rng.Int63n(2*amount+1) - amount
+		}
+		arrival := jittered[i].ArrivalTime + shift
+		if arrival < 0 {
+			arrival = 0
+		}
+		jittered[i].ArrivalTime = arrival
+	}
+
+	return jittered
+}
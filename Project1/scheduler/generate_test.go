@@ -0,0 +1,123 @@
+package scheduler
+
+import (
+	"math/rand"
+	"reflect"
+	"runtime"
+	"testing"
+)
+
+func TestGenerate(t *testing.T) {
+	t.Parallel()
+	processes := Generate(rand.New(rand.NewSource(1)), 100)
+	if len(processes) != 100 {
+		t.Fatalf("len(Generate()) = %d, want 100", len(processes))
+	}
+	if err := ValidateProcesses(processes); err != nil {
+		t.Errorf("Generate() produced invalid processes: %v", err)
+	}
+	for i := 1; i < len(processes); i++ {
+		if processes[i].ArrivalTime < processes[i-1].ArrivalTime {
+			t.Fatalf("process %d arrives before process %d", processes[i].ProcessID, processes[i-1].ProcessID)
+		}
+	}
+	if again := Generate(rand.New(rand.NewSource(1)), 100); !reflect.DeepEqual(processes, again) {
+		t.Error("Generate() is not deterministic for the same seed")
+	}
+}
+
+func TestGenerateParallel(t *testing.T) {
+	// Not parallel: it changes GOMAXPROCS.
+	n := 3*generateShard + 17
+	processes := GenerateParallel(1, n)
+	if len(processes) != n {
+		t.Fatalf("len(GenerateParallel()) = %d, want %d", len(processes), n)
+	}
+	if err := ValidateProcesses(processes); err != nil {
+		t.Errorf("GenerateParallel() produced invalid processes: %v", err)
+	}
+	for i, p := range processes {
+		if p.ProcessID != int64(i+1) || p.BurstDuration < 1 || p.BurstDuration > 20 || p.Priority < 1 || p.Priority > 50 {
+			t.Fatalf("process %d = %+v, out of range", i, p)
+		}
+		if i > 0 && p.ArrivalTime-processes[i-1].ArrivalTime > 4 || i > 0 && p.ArrivalTime < processes[i-1].ArrivalTime {
+			t.Fatalf("process %d arrives at %d after process %d at %d", p.ProcessID, p.ArrivalTime, processes[i-1].ProcessID, processes[i-1].ArrivalTime)
+		}
+	}
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(1))
+	if again := GenerateParallel(1, n); !reflect.DeepEqual(processes, again) {
+		t.Error("GenerateParallel() depends on GOMAXPROCS")
+	}
+	if other := GenerateParallel(2, n); reflect.DeepEqual(processes, other) {
+		t.Error("GenerateParallel() ignores the seed")
+	}
+}
+
+func TestJitter(t *testing.T) {
+	t.Parallel()
+	processes := Generate(rand.New(rand.NewSource(1)), 100)
+	original := append([]Process(nil), processes...)
+	jittered := Jitter(rand.New(rand.NewSource(2)), processes, 3)
+	if !reflect.DeepEqual(processes, original) {
+		t.Fatal("Jitter() modified its input")
+	}
+	moved := false
+	for i, p := range jittered {
+		delta := p.ArrivalTime - original[i].ArrivalTime
+		if p.ArrivalTime < 0 || (delta < -3 || delta > 3) {
+			t.Fatalf("process %d arrival %d out of range of %d±3", p.ProcessID, p.ArrivalTime, original[i].ArrivalTime)
+		}
+		moved = moved || delta != 0
+	}
+	if !moved {
+		t.Error("Jitter() did not move any arrival")
+	}
+	if got := Jitter(rand.New(rand.NewSource(2)), processes, 0); !reflect.DeepEqual(got, original) {
+		t.Error("Jitter() with zero amount changed arrivals")
+	}
+}
+
+func TestJitterWith(t *testing.T) {
+	t.Parallel()
+	processes := Generate(rand.New(rand.NewSource(1)), 100)
+	tests := []struct {
+		dist  Distribution
+		check func(delta int64) bool
+	}{
+		{dist: Normal, check: func(int64) bool { return true }},
+		{dist: Exponential, check: func(delta int64) bool { return delta >= 0 }},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.dist.String(), func(t *testing.T) {
+			t.Parallel()
+			jittered := JitterWith(rand.New(rand.NewSource(2)), processes, 3, tt.dist)
+			moved := false
+			for i, p := range jittered {
+				delta := p.ArrivalTime - processes[i].ArrivalTime
+				if p.ArrivalTime < 0 || !tt.check(delta) {
+					t.Fatalf("process %d arrival %d, was %d", p.ProcessID, p.ArrivalTime, processes[i].ArrivalTime)
+				}
+				moved = moved || delta != 0
+			}
+			if !moved {
+				t.Error("JitterWith() did not move any arrival")
+			}
+			if again := JitterWith(rand.New(rand.NewSource(2)), processes, 3, tt.dist); !reflect.DeepEqual(jittered, again) {
+				t.Error("JitterWith() is not deterministic for the same seed")
+			}
+		})
+	}
+}
+
+func TestParseDistribution(t *testing.T) {
+	t.Parallel()
+	for _, d := range []Distribution{Uniform, Normal, Exponential} {
+		if got, err := ParseDistribution(d.String()); err != nil || got != d {
+			t.Errorf("ParseDistribution(%q) = %v, %v, want %v", d, got, err, d)
+		}
+	}
+	if _, err := ParseDistribution("poisson"); err == nil {
+		t.Error("ParseDistribution(\"poisson\") succeeded")
+	}
+}
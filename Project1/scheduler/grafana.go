@@ -0,0 +1,105 @@
+package scheduler
+
+import (
+	"encoding/json"
+	"io"
+	"sort"
+)
+
+// grafanaWindows is how many windows throughput is counted over, at most.
+const grafanaWindows = 100
+
+// grafanaSeries is a time series as a Grafana JSON datasource returns it:
+// pairs of a value and a time in milliseconds since the Unix epoch.
+type grafanaSeries struct {
+	Target     string       `json:"target"`
+	Datapoints [][2]float64 `json:"datapoints"`
+}
+
+// grafanaTable is a table as a Grafana JSON datasource returns it.
+type grafanaTable struct {
+	Type    string          `json:"type"`
+	Columns []grafanaColumn `json:"columns"`
+	Rows    [][]interface{} `json:"rows"`
+}
+
+type grafanaColumn struct {
+	Text string `json:"text"`
+	Type string `json:"type"`
+}
+
+// RenderGrafana writes r to w as a single JSON array followed by a newline,
+// in the form a Grafana JSON datasource answers a query with, so dashboards
+// can chart runs collected from many simulations: the ready queue length
+// over time, throughput over time in up to 100 windows, and a table of the
+// metrics, each series named after title. Simulated time is placed at the
+// Unix epoch, one time unit to the second. The queue length needs the
+// transitions, so it is empty for a run with WithMetricsOnly.
+func RenderGrafana(w io.Writer, title string, r *ScheduleResult) error {
+	u := r.ticksPerUnit()
+	ms := func(t int64) float64 { return float64(t) * 1000 / u }
+
+	queue := grafanaSeries{Target: title + " ready queue", Datapoints: [][2]float64{}}
+	states := make(map[int64]State)
+	ready := 0
+	for i, tr := range r.Transitions {
+		if states[tr.PID] == StateReady {
+			ready--
+		}
+		if tr.State == StateReady {
+			ready++
+		}
+		states[tr.PID] = tr.State
+		if i+1 == len(r.Transitions) || r.Transitions[i+1].At != tr.At {
+			queue.Datapoints = append(queue.Datapoints, [2]float64{float64(ready), ms(tr.At)})
+		}
+	}
+
+	throughput := grafanaSeries{Target: title + " throughput", Datapoints: [][2]float64{}}
+	var exits []int64
+	var end int64
+	for _, row := range r.Rows {
+		if !row.Killed && !row.Excluded {
+			exits = append(exits, row.Exit)
+		}
+		if row.Exit > end {
+			end = row.Exit
+		}
+	}
+	sort.Slice(exits, func(a, b int) bool { return exits[a] < exits[b] })
+	// Windows are whole time units wide, so their throughputs compare.
+	width := int64(u)
+	if windows := (end + width - 1) / width; windows > grafanaWindows {
+		width *= (windows + grafanaWindows - 1) / grafanaWindows
+	}
+	for from, k := int64(0), 0; from < end; from += width {
+		count := 0
+		// The last window includes the end, where the last process exits.
+		for ; k < len(exits) && (exits[k] < from+width || from+width >= end); k++ {
+			count++
+		}
+		throughput.Datapoints = append(throughput.Datapoints, [2]float64{float64(count) * u / float64(width), ms(from)})
+	}
+
+	m := r.Metrics
+	metrics := grafanaTable{
+		Type:    "table",
+		Columns: []grafanaColumn{{Text: "Schedule", Type: "string"}, {Text: "Metric", Type: "string"}, {Text: "Value", Type: "number"}},
+	}
+	for _, v := range []struct {
+		name  string
+		value float64
+	}{
+		{"average wait", m.AverageWait},
+		{"average turnaround", m.AverageTurnaround},
+		{"throughput", m.Throughput},
+		{"completed", float64(m.Completed)},
+		{"makespan", m.Makespan},
+		{"context switches", float64(m.ContextSwitches)},
+		{"utilization", m.Utilization},
+	} {
+		metrics.Rows = append(metrics.Rows, []interface{}{title, v.name, v.value})
+	}
+
+	return json.NewEncoder(w).Encode([]interface{}{queue, throughput, metrics})
+}
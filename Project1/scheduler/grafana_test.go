@@ -0,0 +1,53 @@
+package scheduler
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestRenderGrafana(t *testing.T) {
+	t.Parallel()
+	r := testResult()
+	r.Transitions = []Transition{
+		{PID: 1, At: 0, State: StateReady},
+		{PID: 1, At: 0, State: StateRunning},
+		{PID: 2, At: 3, State: StateReady},
+		{PID: 1, At: 5, State: StateTerminated},
+		{PID: 2, At: 5, State: StateRunning},
+		{PID: 2, At: 14, State: StateTerminated},
+	}
+	var w bytes.Buffer
+	if err := RenderGrafana(&w, "FCFS", r); err != nil {
+		t.Fatalf("RenderGrafana() error = %v", err)
+	}
+	var got []json.RawMessage
+	if err := json.Unmarshal(w.Bytes(), &got); err != nil || len(got) != 3 {
+		t.Fatalf("RenderGrafana() = %s, %v, want an array of 3", w.String(), err)
+	}
+
+	var queue, throughput grafanaSeries
+	if err := json.Unmarshal(got[0], &queue); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(got[1], &throughput); err != nil {
+		t.Fatal(err)
+	}
+	wantQueue := grafanaSeries{Target: "FCFS ready queue", Datapoints: [][2]float64{{0, 0}, {1, 3000}, {0, 5000}, {0, 14000}}}
+	if !reflect.DeepEqual(queue, wantQueue) {
+		t.Errorf("queue = %+v, want %+v", queue, wantQueue)
+	}
+	// 14 one-unit windows, with the exits at 5 and 14 in the 6th and last.
+	if len(throughput.Datapoints) != 14 || throughput.Datapoints[5] != [2]float64{1, 5000} || throughput.Datapoints[13] != [2]float64{1, 13000} {
+		t.Errorf("throughput = %+v, want 14 windows with one exit in the 6th and last", throughput)
+	}
+
+	var metrics grafanaTable
+	if err := json.Unmarshal(got[2], &metrics); err != nil {
+		t.Fatal(err)
+	}
+	if metrics.Type != "table" || len(metrics.Columns) != 3 || !reflect.DeepEqual(metrics.Rows[0], []interface{}{"FCFS", "average wait", 1.0}) {
+		t.Errorf("metrics = %+v, want a table starting with the average wait", metrics)
+	}
+}
@@ -0,0 +1,139 @@
+package scheduler
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestKill(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name      string
+		s         Scheduler
+		processes []Process
+		gantt     []TimeSlice
+		killed    []bool
+		exit      []int64
+	}{
+		{
+			name: "running",
+			s:    NewFCFS(),
+			processes: []Process{
+				{ProcessID: 1, BurstDuration: 5, KillAt: 3},
+				{ProcessID: 2, BurstDuration: 2},
+			},
+			gantt:  []TimeSlice{{PID: 1, Start: 0, Stop: 3}, {PID: 2, Start: 3, Stop: 5}},
+			killed: []bool{true, false},
+			exit:   []int64{3, 5},
+		},
+		{
+			name: "ready",
+			s:    NewSJF(),
+			processes: []Process{
+				{ProcessID: 1, BurstDuration: 2},
+				{ProcessID: 2, BurstDuration: 3, KillAt: 1},
+			},
+			gantt:  []TimeSlice{{PID: 1, Start: 0, Stop: 2}},
+			killed: []bool{false, true},
+			exit:   []int64{2, 1},
+		},
+		{
+			name: "blocked on I/O",
+			s:    NewFCFS(),
+			processes: []Process{
+				{ProcessID: 1, BurstDuration: 1, Cycles: []Cycle{{IO: 5, CPU: 1}}, KillAt: 3},
+				{ProcessID: 2, BurstDuration: 1, ArrivalTime: 6},
+			},
+			gantt: []TimeSlice{
+				{PID: 1, Start: 0, Stop: 1},
+				{Start: 1, Stop: 6, Kind: SliceIdle},
+				{PID: 2, Start: 6, Stop: 7},
+			},
+			killed: []bool{true, false},
+			exit:   []int64{3, 7},
+		},
+		{
+			name: "completing as killed",
+			s:    NewFCFS(),
+			processes: []Process{
+				{ProcessID: 1, BurstDuration: 2, KillAt: 2},
+			},
+			gantt:  []TimeSlice{{PID: 1, Start: 0, Stop: 2}},
+			killed: []bool{false},
+			exit:   []int64{2},
+		},
+		{
+			name: "lock holder",
+			s:    NewFCFS(),
+			processes: []Process{
+				{ProcessID: 1, BurstDuration: 4, Locks: []Lock{{Name: "R", Duration: 4}}, KillAt: 2},
+				{ProcessID: 2, BurstDuration: 2, Locks: []Lock{{Name: "R", Duration: 1}}},
+			},
+			gantt:  []TimeSlice{{PID: 1, Start: 0, Stop: 2}, {PID: 2, Start: 2, Stop: 4}},
+			killed: []bool{true, false},
+			exit:   []int64{2, 4},
+		},
+		{
+			name: "switching",
+			s:    NewFCFS(WithSwitchCost(2)),
+			processes: []Process{
+				{ProcessID: 1, BurstDuration: 3},
+				{ProcessID: 2, BurstDuration: 3, KillAt: 4},
+				{ProcessID: 3, BurstDuration: 2, ArrivalTime: 1},
+			},
+			gantt: []TimeSlice{
+				{PID: 1, Start: 0, Stop: 3},
+				{PID: 2, Start: 3, Stop: 4, Kind: SliceSwitch},
+				{PID: 3, Start: 4, Stop: 6, Kind: SliceSwitch},
+				{PID: 3, Start: 6, Stop: 8},
+			},
+			killed: []bool{false, true, false},
+			exit:   []int64{3, 4, 8},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			r, err := tt.s.Schedule(context.Background(), tt.processes)
+			if err != nil {
+				t.Fatalf("Schedule() error = %v", err)
+			}
+			if !reflect.DeepEqual(r.Gantt, tt.gantt) {
+				t.Errorf("Gantt = %v, want %v", r.Gantt, tt.gantt)
+			}
+			var killed []bool
+			var exit []int64
+			for _, row := range r.Rows {
+				killed = append(killed, row.Killed)
+				exit = append(exit, row.Exit)
+				if rs := row.Residency; rs.Ready < 0 || rs.Running < 0 || rs.Ready+rs.Running+rs.Blocked != row.Turnaround {
+					t.Errorf("process %d Residency = %+v, want it to add up to the turnaround %d", row.ProcessID, rs, row.Turnaround)
+				}
+			}
+			if !reflect.DeepEqual(killed, tt.killed) || !reflect.DeepEqual(exit, tt.exit) {
+				t.Errorf("Killed = %v, Exit = %v, want %v, %v", killed, exit, tt.killed, tt.exit)
+			}
+			if len(r.Violations) != 0 {
+				t.Errorf("Violations = %v, want none", r.Violations)
+			}
+		})
+	}
+}
+
+func TestKill_metrics(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: 1, BurstDuration: 5, KillAt: 3},
+		{ProcessID: 2, BurstDuration: 2},
+	}
+	r, err := NewFCFS().Schedule(context.Background(), processes)
+	if err != nil {
+		t.Fatalf("Schedule() error = %v", err)
+	}
+	// The killed process is left out of the averages.
+	if got, want := r.Metrics, (Metrics{AverageWait: 3, AverageTurnaround: 5, Throughput: 0.2, Completed: 1, Killed: 1, Makespan: 5, ContextSwitches: 1, Utilization: 1}); !reflect.DeepEqual(got, want) {
+		t.Errorf("Metrics = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,133 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+)
+
+// LiveScheduler is a Scheduler that can also take processes while it runs,
+// for interactive demos and online scheduling experiments. Every scheduler
+// returned by the New* constructors is one:
+//
+//	live := scheduler.NewRR().(scheduler.LiveScheduler)
+type LiveScheduler interface {
+	Scheduler
+	// ScheduleLive schedules the processes received from arrivals until the
+	// channel is closed and every process received has finished. The
+	// simulation runs as fast as it can rather than in real time: when every
+	// process received so far has finished the clock stops and it waits for
+	// the next one, and otherwise a process that arrives after the clock has
+	// passed its ArrivalTime arrives at the current time instead, skipping
+	// any of its suspensions over by then.
+	//
+	// Each process is validated as it is received. An invalid one, like a
+	// cancelled ctx, stops the simulation with the partial result of the
+	// processes finished so far. Since the arrivals cannot be replayed, the
+	// result has no Ideal metrics.
+	ScheduleLive(ctx context.Context, arrivals <-chan Process) (*ScheduleResult, error)
+}
+
+var (
+	_ LiveScheduler = (*fcfs)(nil)
+	_ LiveScheduler = (*sjf)(nil)
+	_ LiveScheduler = (*priority)(nil)
+	_ LiveScheduler = (*rr)(nil)
+	_ LiveScheduler = (*edf)(nil)
+	_ LiveScheduler = (*rm)(nil)
+)
+
+// simulateLive runs processes received from arrivals under p.
+func simulateLive(ctx context.Context, arrivals <-chan Process, opts Options, p policy) (*ScheduleResult, error) {
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
+	if opts.CheckpointAt > 0 {
+		// Processes still to be received could not be resumed.
+		return nil, fmt.Errorf("%w: live simulations cannot be checkpointed", ErrValidation)
+	}
+	e := newEngine(opts)
+	e.arrivals = arrivals
+
+	return e.run(ctx, p)
+}
+
+// receive admits the processes waiting on e.arrivals into the simulation,
+// if it is live. With nothing left to simulate it first waits for one to
+// arrive, for the channel to close, or for ctx to be cancelled.
+func (e *engine) receive(ctx context.Context) error {
+	wait := e.done == len(e.processes)
+	for e.arrivals != nil {
+		var p Process
+		var ok bool
+		if wait {
+			select {
+			case p, ok = <-e.arrivals:
+			case <-ctx.Done():
+				return nil
+			}
+		} else {
+			select {
+			case p, ok = <-e.arrivals:
+			default:
+				return nil
+			}
+		}
+		if !ok {
+			e.arrivals = nil
+			return nil
+		}
+		if err := e.validateLive(p); err != nil {
+			return err
+		}
+		if p.ArrivalTime < e.now {
+			p.ArrivalTime = e.now
+		}
+		i := e.track(p)
+		e.events.schedule(p.ArrivalTime, eventArrival, i)
+		if p.KillAt > 0 {
+			e.events.schedule(p.KillAt, eventKill, i)
+		}
+		for _, s := range p.Suspensions {
+			if s.To <= p.ArrivalTime {
+				// Over before it arrived late.
+				continue
+			}
+			e.events.schedule(s.From, eventSuspend, i)
+			e.events.schedule(s.To, eventResume, i)
+		}
+		wait = false
+	}
+	return nil
+}
+
+// validateLive checks process p, received live, as validate would check it
+// as the next row of a scheduling file.
+func (e *engine) validateLive(p Process) error {
+	row := len(e.processes) + 1
+	if _, ok := e.index[p.ProcessID]; ok {
+		return &ValidationError{Row: row, Err: fmt.Errorf("duplicate process ID %d", p.ProcessID)}
+	}
+	if err := validate([]Process{p}, e.opts); err != nil {
+		if ve, ok := err.(*ValidationError); ok {
+			ve.Row = row
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *fcfs) ScheduleLive(ctx context.Context, arrivals <-chan Process) (*ScheduleResult, error) {
+	return simulateLive(ctx, arrivals, s.opts, &fifo{})
+}
+
+func (s *sjf) ScheduleLive(ctx context.Context, arrivals <-chan Process) (*ScheduleResult, error) {
+	return simulateLive(ctx, arrivals, s.opts, &ranked{before: s.before})
+}
+
+func (s *priority) ScheduleLive(ctx context.Context, arrivals <-chan Process) (*ScheduleResult, error) {
+	return simulateLive(ctx, arrivals, s.opts, &ranked{before: s.before})
+}
+
+func (s *rr) ScheduleLive(ctx context.Context, arrivals <-chan Process) (*ScheduleResult, error) {
+	return simulateLive(ctx, arrivals, s.opts, &fifo{slice: s.opts.Quantum})
+}
@@ -0,0 +1,138 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestScheduleLive(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: 1, BurstDuration: 5, Priority: 2},
+		{ProcessID: 2, BurstDuration: 9, ArrivalTime: 3, Priority: 1},
+		{ProcessID: 3, BurstDuration: 6, ArrivalTime: 3, Priority: 3},
+	}
+	for _, name := range []string{"fcfs", "sjf", "priority", "rr", "edf", "rm"} {
+		name := name
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			s, _ := Lookup(name)
+			want, err := s().Schedule(context.Background(), processes)
+			if err != nil {
+				t.Fatalf("Schedule() error = %v", err)
+			}
+			// Processes already waiting when the simulation starts arrive on
+			// time, so the schedule is the same as from a file.
+			arrivals := make(chan Process, len(processes))
+			for _, p := range processes {
+				arrivals <- p
+			}
+			close(arrivals)
+			got, err := s().(LiveScheduler).ScheduleLive(context.Background(), arrivals)
+			if err != nil {
+				t.Fatalf("ScheduleLive() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("ScheduleLive() = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestScheduleLive_suspensions(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: 1, BurstDuration: 5, Priority: 2, Suspensions: []Suspension{{From: 2, To: 5}}},
+		{ProcessID: 2, BurstDuration: 4, ArrivalTime: 1, Priority: 1, Suspensions: []Suspension{{From: 7, To: 9}, {From: 10, To: 11}}},
+		{ProcessID: 3, BurstDuration: 6, ArrivalTime: 3, Priority: 3, KillAt: 13, Suspensions: []Suspension{{From: 4, To: 6}}},
+	}
+	for _, name := range []string{"fcfs", "sjf", "priority", "rr", "edf", "rm"} {
+		name := name
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			s, _ := Lookup(name)
+			want, err := s(WithDispatchLatency(1)).Schedule(context.Background(), processes)
+			if err != nil {
+				t.Fatalf("Schedule() error = %v", err)
+			}
+			want.Ideal = nil
+			arrivals := make(chan Process, len(processes))
+			for _, p := range processes {
+				arrivals <- p
+			}
+			close(arrivals)
+			got, err := s(WithDispatchLatency(1)).(LiveScheduler).ScheduleLive(context.Background(), arrivals)
+			if err != nil {
+				t.Fatalf("ScheduleLive() error = %v", err)
+			}
+			if len(got.Suspensions) == 0 || !reflect.DeepEqual(got, want) {
+				t.Errorf("ScheduleLive() = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestScheduleLive_late(t *testing.T) {
+	t.Parallel()
+	arrivals := make(chan Process)
+	completed := make(chan int, 2)
+	s := NewFCFS(WithProgress(func(done, _ int) { completed <- done })).(LiveScheduler)
+	go func() {
+		arrivals <- Process{ProcessID: 1, BurstDuration: 3}
+		// Sent only once the first process has finished, when the clock has
+		// passed its arrival time.
+		<-completed
+		arrivals <- Process{ProcessID: 2, BurstDuration: 2, ArrivalTime: 1}
+		close(arrivals)
+	}()
+	r, err := s.ScheduleLive(context.Background(), arrivals)
+	if err != nil {
+		t.Fatalf("ScheduleLive() error = %v", err)
+	}
+	want := []TimeSlice{{PID: 1, Start: 0, Stop: 3}, {PID: 2, Start: 3, Stop: 5}}
+	if !reflect.DeepEqual(r.Gantt, want) {
+		t.Errorf("Gantt = %v, want %v", r.Gantt, want)
+	}
+	if got := r.Rows[1].ArrivalTime; got != 3 {
+		t.Errorf("late arrival = %d, want 3", got)
+	}
+}
+
+func TestScheduleLive_errors(t *testing.T) {
+	t.Parallel()
+	t.Run("invalid process", func(t *testing.T) {
+		t.Parallel()
+		arrivals := make(chan Process, 2)
+		arrivals <- Process{ProcessID: 1, BurstDuration: 3}
+		arrivals <- Process{ProcessID: 1, BurstDuration: 2}
+		close(arrivals)
+		r, err := NewFCFS().(LiveScheduler).ScheduleLive(context.Background(), arrivals)
+		var ve *ValidationError
+		if !errors.As(err, &ve) || ve.Row != 2 {
+			t.Fatalf("ScheduleLive() error = %v, want a validation error for row 2", err)
+		}
+		if r == nil || r.Total != 1 {
+			t.Errorf("ScheduleLive() = %+v, want the partial result", r)
+		}
+	})
+	t.Run("cancelled while waiting", func(t *testing.T) {
+		t.Parallel()
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		r, err := NewRR().(LiveScheduler).ScheduleLive(ctx, make(chan Process))
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("ScheduleLive() error = %v, want %v", err, context.Canceled)
+		}
+		if r == nil || !r.Cancelled {
+			t.Errorf("ScheduleLive() = %+v, want a cancelled result", r)
+		}
+	})
+	t.Run("invalid options", func(t *testing.T) {
+		t.Parallel()
+		if _, err := NewFCFS(WithCPUs(0)).(LiveScheduler).ScheduleLive(context.Background(), nil); !errors.Is(err, ErrValidation) {
+			t.Errorf("ScheduleLive() error = %v, want %v", err, ErrValidation)
+		}
+	})
+}
@@ -0,0 +1,222 @@
+package scheduler
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+)
+
+// Lock is a critical section of a process's first CPU burst, during which
+// it holds the named lock. A process that reaches a lock another holds blocks
+// until it is released; locks are not nested.
+type Lock struct {
+	Name string `json:"name"`
+	// Start is how far into the burst the lock is acquired, and Duration how
+	// much CPU time the process then runs before releasing it.
+	Start    int64 `json:"start"`
+	Duration int64 `json:"duration"`
+}
+
+// Inversion is an interval during which process PID was blocked on a lock
+// held by Holder, a process of lower priority.
+type Inversion struct {
+	PID    int64  `json:"pid"`
+	Holder int64  `json:"holder"`
+	Lock   string `json:"lock"`
+	Start  int64  `json:"start"`
+	Stop   int64  `json:"stop"`
+}
+
+// parseLock parses a lock field value of the form name:start:duration,
+// parsing its times with parseTime.
+func parseLock(s string, parseTime func(string) (int64, error)) (Lock, error) {
+	f := strings.Split(s, ":")
+	if len(f) != 3 || f[0] == "" {
+		return Lock{}, fmt.Errorf("lock %q is not name:start:duration", s)
+	}
+	l := Lock{Name: f[0]}
+	var err error
+	if l.Start, err = parseTime(f[1]); err != nil {
+		return Lock{}, err
+	}
+	if l.Duration, err = parseTime(f[2]); err != nil {
+		return Lock{}, err
+	}
+	return l, nil
+}
+
+// validateLocks checks that the critical sections of p are in order, do not
+// overlap, and fit inside its first burst.
+func validateLocks(p Process) error {
+	var end int64
+	for _, l := range p.Locks {
+		switch {
+		case l.Start < end:
+			return fmt.Errorf("lock %s at %d overlaps the previous lock", l.Name, l.Start)
+		case l.Duration <= 0:
+			return fmt.Errorf("lock %s duration %d must be positive", l.Name, l.Duration)
+		case l.Start+l.Duration > p.BurstDuration:
+			return fmt.Errorf("lock %s runs past the end of the first burst", l.Name)
+		}
+		end = l.Start + l.Duration
+	}
+	return nil
+}
+
+// mutex is the state of one named lock during a simulation.
+type mutex struct {
+	holder  int
+	waiters []int
+}
+
+// progress is how much of its first burst process i has run, or -1 once it
+// is past the first burst.
+func (e *engine) progress(i int) int64 {
+	if e.cycle[i] != 0 {
+		return -1
+	}
+	return e.processes[i].BurstDuration*speedScale - e.remaining[i]
+}
+
+// untilLock is the work process i can do before it next acquires or
+// releases a lock, if it will.
+func (e *engine) untilLock(i int) (int64, bool) {
+	locks := e.processes[i].Locks
+	if e.progress(i) < 0 || e.section[i] >= len(locks) {
+		return 0, false
+	}
+	l := locks[e.section[i]]
+	at := l.Start
+	if e.holding[i] {
+		at += l.Duration
+	}
+	return at*speedScale - e.progress(i), true
+}
+
+// lockPoint acquires and releases the locks process i has reached, and
+// reports whether it blocked on a lock another process holds.
+func (e *engine) lockPoint(i int, p policy) bool {
+	for d, ok := e.untilLock(i); ok && d == 0; d, ok = e.untilLock(i) {
+		l := e.processes[i].Locks[e.section[i]]
+		if e.holding[i] {
+			e.holding[i] = false
+			e.section[i]++
+			e.release(l.Name, p)
+			continue
+		}
+		m := e.locks[l.Name]
+		if m == nil {
+			m = &mutex{holder: -1}
+			e.locks[l.Name] = m
+		}
+		if m.holder >= 0 {
+			m.waiters = append(m.waiters, i)
+			p.rerank(m.holder)
+			e.enter(i, StateBlocked)
+			e.blockedSince[i] = e.now
+			e.invert(i, m.holder, l.Name)
+			return true
+		}
+		m.holder, e.holding[i] = i, true
+	}
+	return false
+}
+
+// release gives the named lock to its most urgent waiter, if any, which
+// becomes ready.
+func (e *engine) release(name string, p policy) {
+	m := e.locks[name]
+	m.holder = -1
+	if len(m.waiters) == 0 {
+		return
+	}
+	best := 0
+	for k, w := range m.waiters {
+		if urgency(e.processes[w]) < urgency(e.processes[m.waiters[best]]) {
+			best = k
+		}
+	}
+	w := m.waiters[best]
+	m.waiters = append(m.waiters[:best], m.waiters[best+1:]...)
+	m.holder, e.holding[w] = w, true
+	e.lockWait[w] += e.now - e.blockedSince[w]
+	for _, v := range append(m.waiters, w) {
+		e.revert(v)
+	}
+	for _, v := range m.waiters {
+		e.invert(v, w, name)
+	}
+	e.ready(w, e.now, p)
+}
+
+// urgency is the priority of p for lock hand-off and inversion, where a
+// process without a priority is the least urgent.
+func urgency(p Process) int64 {
+	if p.Priority == 0 {
+		return 51
+	}
+	return p.Priority
+}
+
+// invert opens an inversion interval if process i is blocked on a lock held
+// by a process of lower priority.
+func (e *engine) invert(i, holder int, name string) {
+	if urgency(e.processes[i]) >= urgency(e.processes[holder]) {
+		return
+	}
+	e.inverted[i] = len(e.inversions)
+	e.inversions = append(e.inversions, Inversion{
+		PID:    e.processes[i].ProcessID,
+		Holder: e.processes[holder].ProcessID,
+		Lock:   name,
+		Start:  e.now,
+		Stop:   -1,
+	})
+}
+
+// revert closes process i's open inversion interval, if any.
+func (e *engine) revert(i int) {
+	if k, ok := e.inverted[i]; ok {
+		e.inversions[k].Stop = e.now
+		delete(e.inverted, i)
+	}
+}
+
+// priority is the priority process i is scheduled at: its own, or with
+// priority inheritance, that of the most urgent process blocked on the lock
+// it holds, if better.
+func (e *engine) priority(i int) int64 {
+	prio := e.processes[i].Priority
+	if !e.opts.Inheritance || !e.holding[i] {
+		return prio
+	}
+	m := e.locks[e.processes[i].Locks[e.section[i]].Name]
+	for _, w := range m.waiters {
+		if wp := e.processes[w].Priority; wp != 0 && prio != 0 && wp < prio {
+			prio = wp
+		}
+	}
+	return prio
+}
+
+// outputInversions writes the priority inversion intervals, if any.
+func outputInversions(w io.Writer, inversions []Inversion, u float64) {
+	if len(inversions) == 0 {
+		return
+	}
+	_, _ = fmt.Fprintln(w, "Priority inversions")
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"ID", "Holder", "Lock", "From", "To"})
+	for _, inv := range inversions {
+		table.Append([]string{
+			fmt.Sprint(inv.PID),
+			fmt.Sprint(inv.Holder),
+			inv.Lock,
+			unitTime(inv.Start, u),
+			unitTime(inv.Stop, u),
+		})
+	}
+	table.Render()
+}
@@ -0,0 +1,136 @@
+package scheduler
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestPriorityInversion(t *testing.T) {
+	t.Parallel()
+	// Low takes the lock, High blocks on it, and Medium, needing as long as
+	// Low has left, runs ahead of Low unless Low inherits High's priority.
+	processes := []Process{
+		{ProcessID: 1, BurstDuration: 4, Priority: 3, Locks: []Lock{{Name: "R", Duration: 4}}},
+		{ProcessID: 2, BurstDuration: 3, ArrivalTime: 1, Priority: 1, Locks: []Lock{{Name: "R", Duration: 1}}},
+		{ProcessID: 3, BurstDuration: 3, ArrivalTime: 1, Priority: 2},
+	}
+	tests := []struct {
+		name       string
+		opts       []Option
+		gantt      []TimeSlice
+		inversions []Inversion
+		wait       []int64
+	}{
+		{
+			name: "no inheritance",
+			gantt: []TimeSlice{
+				{PID: 1, Start: 0, Stop: 1},
+				{PID: 3, Start: 1, Stop: 4},
+				{PID: 1, Start: 4, Stop: 7},
+				{PID: 2, Start: 7, Stop: 10},
+			},
+			inversions: []Inversion{{PID: 2, Holder: 1, Lock: "R", Start: 1, Stop: 7}},
+			wait:       []int64{3, 6, 0},
+		},
+		{
+			name: "inheritance",
+			opts: []Option{WithPriorityInheritance()},
+			gantt: []TimeSlice{
+				{PID: 1, Start: 0, Stop: 4},
+				{PID: 2, Start: 4, Stop: 7},
+				{PID: 3, Start: 7, Stop: 10},
+			},
+			inversions: []Inversion{{PID: 2, Holder: 1, Lock: "R", Start: 1, Stop: 4}},
+			wait:       []int64{0, 3, 6},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			r, err := NewPriority(tt.opts...).Schedule(context.Background(), processes)
+			if err != nil {
+				t.Fatalf("Schedule() error = %v", err)
+			}
+			if !reflect.DeepEqual(r.Gantt, tt.gantt) {
+				t.Errorf("Gantt = %v, want %v", r.Gantt, tt.gantt)
+			}
+			if !reflect.DeepEqual(r.Inversions, tt.inversions) {
+				t.Errorf("Inversions = %v, want %v", r.Inversions, tt.inversions)
+			}
+			for k, row := range r.Rows {
+				if row.Wait != tt.wait[k] {
+					t.Errorf("process %d Wait = %d, want %d", row.ProcessID, row.Wait, tt.wait[k])
+				}
+			}
+		})
+	}
+}
+
+func TestLocks_midBurst(t *testing.T) {
+	t.Parallel()
+	// The second process runs up to its lock, blocks until the first
+	// releases it at the end of its first burst, and never counts as an
+	// inversion without priorities.
+	processes := []Process{
+		{ProcessID: 1, BurstDuration: 3, Cycles: []Cycle{{IO: 1, CPU: 1}}, Locks: []Lock{{Name: "R", Start: 1, Duration: 2}}},
+		{ProcessID: 2, BurstDuration: 3, ArrivalTime: 1, Locks: []Lock{{Name: "R", Start: 1, Duration: 1}}},
+	}
+	r, err := NewRR(WithQuantum(1)).Schedule(context.Background(), processes)
+	if err != nil {
+		t.Fatalf("Schedule() error = %v", err)
+	}
+	want := []TimeSlice{
+		{PID: 1, Start: 0, Stop: 1},
+		{PID: 2, Start: 1, Stop: 2},
+		{PID: 1, Start: 2, Stop: 3},
+		{PID: 1, Start: 3, Stop: 4},
+		{PID: 2, Start: 4, Stop: 5},
+		{PID: 1, Start: 5, Stop: 6},
+		{PID: 2, Start: 6, Stop: 7},
+	}
+	if !reflect.DeepEqual(r.Gantt, want) {
+		t.Errorf("Gantt = %v, want %v", r.Gantt, want)
+	}
+	if got, want := r.Rows[1].Residency.Blocked, int64(2); got != want {
+		t.Errorf("Blocked = %d, want %d", got, want)
+	}
+	if len(r.Inversions) != 0 {
+		t.Errorf("Inversions = %v, want none", r.Inversions)
+	}
+}
+
+func TestLocks_fastCore(t *testing.T) {
+	t.Parallel()
+	// At speed 2 the burst takes 2 ticks, though its lock splits it into
+	// runs of 1 unit each: the first tick's spare unit runs the lock.
+	r, err := NewFCFS(WithSpeeds(2)).Schedule(context.Background(), []Process{
+		{ProcessID: 1, BurstDuration: 3, Locks: []Lock{{Name: "m", Start: 1, Duration: 1}}},
+	})
+	if err != nil {
+		t.Fatalf("Schedule() error = %v", err)
+	}
+	if want := []TimeSlice{{PID: 1, Start: 0, Stop: 2}}; !reflect.DeepEqual(r.Gantt, want) {
+		t.Errorf("Gantt = %v, want %v", r.Gantt, want)
+	}
+	if len(r.Violations) != 0 {
+		t.Errorf("Violations = %v, want none", r.Violations)
+	}
+}
+
+func TestRenderText_inversions(t *testing.T) {
+	t.Parallel()
+	r := &ScheduleResult{Inversions: []Inversion{{PID: 2, Holder: 1, Lock: "R", Start: 1, Stop: 7}}}
+	var w bytes.Buffer
+	if err := RenderText(&w, "t", r); err != nil {
+		t.Fatalf("RenderText() error = %v", err)
+	}
+	for _, want := range []string{"Priority inversions", "|  2 |      1 | R    |    1 |  7 |"} {
+		if !strings.Contains(w.String(), want) {
+			t.Errorf("RenderText() is missing %q:\n%s", want, w.String())
+		}
+	}
+}
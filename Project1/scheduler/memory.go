@@ -0,0 +1,80 @@
+package scheduler
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/olekukonko/tablewriter"
+)
+
+// fits reports whether process i can be admitted now: there is no memory
+// limit, or its memory fits in what is free and no process that arrived
+// before it is still waiting.
+func (e *engine) fits(i int) bool {
+	if e.opts.Memory == 0 {
+		return true
+	}
+	return len(e.admission) == 0 && e.memory+e.processes[i].Memory <= e.opts.Memory
+}
+
+// allocate admits process i at at, taking its memory, and makes it ready.
+func (e *engine) allocate(i int, at int64, p policy) {
+	e.memory += e.processes[i].Memory
+	e.admitted[i] = at
+	if _, ok := e.suspended[i]; ok {
+		e.enterAt(i, StateSuspended, at)
+		return
+	}
+	e.enterAt(i, StateReady, at)
+	if !e.lockPoint(i, p) {
+		p.add(i)
+	}
+}
+
+// free gives back the memory of process i, which terminated at now, and
+// admits the processes waiting for memory, in the order they arrived, for
+// as long as the next one fits.
+func (e *engine) free(i int, p policy) {
+	if e.opts.Memory == 0 {
+		return
+	}
+	if e.admitted[i] < 0 {
+		// Killed while waiting to be admitted.
+		for k, w := range e.admission {
+			if w == i {
+				e.admission = append(e.admission[:k], e.admission[k+1:]...)
+				break
+			}
+		}
+	} else {
+		e.memory -= e.processes[i].Memory
+	}
+	for len(e.admission) > 0 {
+		w := e.admission[0]
+		if e.memory+e.processes[w].Memory > e.opts.Memory {
+			return
+		}
+		e.admission = e.admission[1:]
+		e.allocate(w, e.now, p)
+	}
+}
+
+// outputAdmission writes how long each process waited for memory beside how
+// long it waited for the CPU, when memory is limited.
+func outputAdmission(w io.Writer, rows []Row, m Metrics, u float64) {
+	_, _ = fmt.Fprintln(w, "Memory admission")
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"ID", "Memory", "Admission delay", "Wait"})
+	for _, row := range rows {
+		table.Append([]string{
+			fmt.Sprint(row.ProcessID),
+			fmt.Sprint(row.Memory),
+			unitTime(row.Admission, u),
+			unitTime(row.Wait, u),
+		})
+	}
+	table.SetFooter([]string{"", "",
+		fmt.Sprintf("Average\n%.2f", m.AverageAdmission),
+		fmt.Sprintf("Average\n%.2f", m.AverageWait)})
+	table.Render()
+}
@@ -0,0 +1,86 @@
+package scheduler
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMemory(t *testing.T) {
+	t.Parallel()
+	// 2 waits for 1's memory, and 3 and 4, which would fit, wait behind it.
+	processes := []Process{
+		{ProcessID: 1, BurstDuration: 5, Memory: 60},
+		{ProcessID: 2, BurstDuration: 3, ArrivalTime: 1, Memory: 50},
+		{ProcessID: 3, BurstDuration: 2, ArrivalTime: 2, Memory: 30},
+		{ProcessID: 4, BurstDuration: 2, ArrivalTime: 3, Memory: 10},
+	}
+	r, err := NewFCFS(WithMemory(100)).Schedule(context.Background(), processes)
+	if err != nil {
+		t.Fatalf("Schedule() error = %v", err)
+	}
+	for k, want := range []struct{ admission, wait int64 }{{0, 0}, {4, 0}, {3, 3}, {2, 5}} {
+		if row := r.Rows[k]; row.Admission != want.admission || row.Wait != want.wait {
+			t.Errorf("process %d Admission, Wait = %d, %d, want %d, %d", row.ProcessID, row.Admission, row.Wait, want.admission, want.wait)
+		}
+	}
+	if got, want := r.Metrics.AverageAdmission, 2.25; got != want {
+		t.Errorf("AverageAdmission = %v, want %v", got, want)
+	}
+
+	// Without a limit every process is admitted as it arrives.
+	if r, err = NewFCFS().Schedule(context.Background(), processes); err != nil {
+		t.Fatalf("Schedule() error = %v", err)
+	}
+	if r.Metrics.AverageAdmission != 0 || r.Memory != 0 {
+		t.Errorf("AverageAdmission = %v, Memory = %d without a limit, want 0", r.Metrics.AverageAdmission, r.Memory)
+	}
+}
+
+func TestMemory_killedWaiting(t *testing.T) {
+	t.Parallel()
+	r, err := NewFCFS(WithMemory(10)).Schedule(context.Background(), []Process{
+		{ProcessID: 1, BurstDuration: 5, Memory: 10},
+		{ProcessID: 2, BurstDuration: 1, Memory: 10, KillAt: 2},
+		{ProcessID: 3, BurstDuration: 1, Memory: 10},
+	})
+	if err != nil {
+		t.Fatalf("Schedule() error = %v", err)
+	}
+	want := []TimeSlice{{PID: 1, Start: 0, Stop: 5}, {PID: 3, Start: 5, Stop: 6}}
+	if !reflect.DeepEqual(r.Gantt, want) {
+		t.Errorf("Gantt = %v, want %v", r.Gantt, want)
+	}
+	if row := r.Rows[1]; !row.Killed || row.Admission != 2 {
+		t.Errorf("killed row = %+v, want killed after waiting 2 for memory", row)
+	}
+}
+
+func TestMemory_tooLarge(t *testing.T) {
+	t.Parallel()
+	_, err := NewFCFS(WithMemory(10)).Schedule(context.Background(), []Process{{ProcessID: 1, BurstDuration: 1, Memory: 11}})
+	if !errors.Is(err, ErrValidation) {
+		t.Errorf("Schedule() error = %v, want %v", err, ErrValidation)
+	}
+}
+
+func TestRenderText_admission(t *testing.T) {
+	t.Parallel()
+	r := &ScheduleResult{
+		Memory:  100,
+		Rows:    []Row{{ProcessID: 2, Memory: 50, Admission: 4, Wait: 1}},
+		Metrics: Metrics{AverageAdmission: 4, AverageWait: 1, Completed: 1},
+	}
+	var w bytes.Buffer
+	if err := RenderText(&w, "t", r); err != nil {
+		t.Fatalf("RenderText() error = %v", err)
+	}
+	for _, want := range []string{"Memory admission", "|  2 |     50 |               4 |       1 |"} {
+		if !strings.Contains(w.String(), want) {
+			t.Errorf("RenderText() is missing %q:\n%s", want, w.String())
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package scheduler
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+)
+
+// RenderMsgpack writes r to w as a single MessagePack map holding what
+// RenderJSON writes, with the same keys. Maps are self-delimiting, so the
+// results of many schedules can be written one after another and read back
+// as a stream. Whole numbers are encoded as integers in as few bytes as
+// they fit, and map keys in sorted order, so equal results encode equally.
+func RenderMsgpack(w io.Writer, title string, r *ScheduleResult) error {
+	var buf bytes.Buffer
+	if err := RenderJSON(&buf, title, r); err != nil {
+		return err
+	}
+	d := json.NewDecoder(&buf)
+	d.UseNumber()
+	var v interface{}
+	if err := d.Decode(&v); err != nil {
+		return err
+	}
+	b, err := appendMsgpack(make([]byte, 0, buf.Cap()/2), v)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(b)
+
+	return err
+}
+
+// appendMsgpack appends v, a value decoded from JSON with UseNumber, to b
+// in MessagePack.
+func appendMsgpack(b []byte, v interface{}) ([]byte, error) {
+	switch v := v.(type) {
+	case nil:
+		return append(b, 0xc0), nil
+	case bool:
+		if v {
+			return append(b, 0xc3), nil
+		}
+		return append(b, 0xc2), nil
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			return appendMsgpackInt(b, n), nil
+		}
+		f, err := v.Float64()
+		if err != nil {
+			return nil, err
+		}
+		return binary.BigEndian.AppendUint64(append(b, 0xcb), math.Float64bits(f)), nil
+	case string:
+		b = appendMsgpackHeader(b, len(v), msgpackStr)
+		return append(b, v...), nil
+	case []interface{}:
+		b = appendMsgpackHeader(b, len(v), msgpackArray)
+		for _, e := range v {
+			var err error
+			if b, err = appendMsgpack(b, e); err != nil {
+				return nil, err
+			}
+		}
+		return b, nil
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		b = appendMsgpackHeader(b, len(v), msgpackMap)
+		for _, k := range keys {
+			b = appendMsgpackHeader(b, len(k), msgpackStr)
+			b = append(b, k...)
+			var err error
+			if b, err = appendMsgpack(b, v[k]); err != nil {
+				return nil, err
+			}
+		}
+		return b, nil
+	default:
+		return nil, fmt.Errorf("cannot encode %T in MessagePack", v)
+	}
+}
+
+// Types of MessagePack strings, arrays, and maps: the type that holds the
+// length in its low bits, the length under which it does, and the type with
+// an 8-bit length, if any, and 16-bit one, which the 32-bit one follows.
+var (
+	msgpackStr   = [4]byte{0xa0, 32, 0xd9, 0xda}
+	msgpackArray = [4]byte{0x90, 16, 0, 0xdc}
+	msgpackMap   = [4]byte{0x80, 16, 0, 0xde}
+)
+
+// appendMsgpackHeader appends the header of a string, array, or map of n
+// elements, of type t, in the fewest bytes.
+func appendMsgpackHeader(b []byte, n int, t [4]byte) []byte {
+	switch {
+	case n < int(t[1]):
+		return append(b, t[0]|byte(n))
+	case t[2] != 0 && n <= math.MaxUint8:
+		return append(b, t[2], byte(n))
+	case n <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(b, t[3]), uint16(n))
+	default:
+		return binary.BigEndian.AppendUint32(append(b, t[3]+1), uint32(n))
+	}
+}
+
+// appendMsgpackInt appends n in the fewest bytes MessagePack allows.
+func appendMsgpackInt(b []byte, n int64) []byte {
+	switch {
+	case n >= 0 && n <= math.MaxInt8, n < 0 && n >= -32:
+		return append(b, byte(n))
+	case n >= 0 && n <= math.MaxUint8:
+		return append(b, 0xcc, byte(n))
+	case n >= 0 && n <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(b, 0xcd), uint16(n))
+	case n >= 0 && n <= math.MaxUint32:
+		return binary.BigEndian.AppendUint32(append(b, 0xce), uint32(n))
+	case n >= 0:
+		return binary.BigEndian.AppendUint64(append(b, 0xcf), uint64(n))
+	case n >= math.MinInt8:
+		return append(b, 0xd0, byte(n))
+	case n >= math.MinInt16:
+		return binary.BigEndian.AppendUint16(append(b, 0xd1), uint16(n))
+	case n >= math.MinInt32:
+		return binary.BigEndian.AppendUint32(append(b, 0xd2), uint32(n))
+	default:
+		return binary.BigEndian.AppendUint64(append(b, 0xd3), uint64(n))
+	}
+}
@@ -0,0 +1,152 @@
+package scheduler
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_appendMsgpack(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		v    interface{}
+		want []byte
+	}{
+		{name: "nil", v: nil, want: []byte{0xc0}},
+		{name: "true", v: true, want: []byte{0xc3}},
+		{name: "positive fixint", v: json.Number("127"), want: []byte{0x7f}},
+		{name: "negative fixint", v: json.Number("-32"), want: []byte{0xe0}},
+		{name: "uint8", v: json.Number("200"), want: []byte{0xcc, 200}},
+		{name: "uint16", v: json.Number("1000"), want: []byte{0xcd, 0x03, 0xe8}},
+		{name: "uint32", v: json.Number("100000"), want: []byte{0xce, 0, 0x01, 0x86, 0xa0}},
+		{name: "int8", v: json.Number("-100"), want: []byte{0xd0, 0x9c}},
+		{name: "int16", v: json.Number("-1000"), want: []byte{0xd1, 0xfc, 0x18}},
+		{name: "int64", v: json.Number("-9223372036854775808"), want: []byte{0xd3, 0x80, 0, 0, 0, 0, 0, 0, 0}},
+		{name: "float", v: json.Number("0.5"), want: []byte{0xcb, 0x3f, 0xe0, 0, 0, 0, 0, 0, 0}},
+		{name: "fixstr", v: "ab", want: []byte{0xa2, 'a', 'b'}},
+		{name: "str8", v: strings.Repeat("x", 32), want: append([]byte{0xd9, 32}, strings.Repeat("x", 32)...)},
+		{name: "fixarray", v: []interface{}{false, "a"}, want: []byte{0x92, 0xc2, 0xa1, 'a'}},
+		{name: "sorted fixmap", v: map[string]interface{}{"b": json.Number("2"), "a": json.Number("1")}, want: []byte{0x82, 0xa1, 'a', 0x01, 0xa1, 'b', 0x02}},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := appendMsgpack(nil, tt.v)
+			if err != nil || !bytes.Equal(got, tt.want) {
+				t.Errorf("appendMsgpack() = % x, %v, want % x", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderMsgpack(t *testing.T) {
+	t.Parallel()
+	var w bytes.Buffer
+	for _, title := range []string{"FCFS", "RR"} {
+		if err := RenderMsgpack(&w, title, testResult()); err != nil {
+			t.Fatalf("RenderMsgpack() error = %v", err)
+		}
+	}
+	var js bytes.Buffer
+	if err := RenderJSON(&js, "RR", testResult()); err != nil {
+		t.Fatal(err)
+	}
+	var want interface{}
+	if err := json.Unmarshal(js.Bytes(), &want); err != nil {
+		t.Fatal(err)
+	}
+	b := w.Bytes()
+	for _, title := range []string{"FCFS", "RR"} {
+		var got interface{}
+		got, b = readMsgpack(t, b)
+		if m, ok := got.(map[string]interface{}); !ok || m["title"] != title {
+			t.Fatalf("RenderMsgpack() wrote %v, want the %s result", got, title)
+		}
+		if title == "RR" && !reflect.DeepEqual(got, want) {
+			t.Errorf("RenderMsgpack() = %v, want what RenderJSON writes, %v", got, want)
+		}
+	}
+	if len(b) != 0 {
+		t.Errorf("RenderMsgpack() wrote % x after the results", b)
+	}
+}
+
+// readMsgpack decodes the MessagePack value at the start of b, as
+// json.Unmarshal decodes the same value in JSON, and returns the rest of b.
+func readMsgpack(t *testing.T, b []byte) (interface{}, []byte) {
+	t.Helper()
+	if len(b) == 0 {
+		t.Fatal("readMsgpack() of nothing")
+	}
+	c, b := b[0], b[1:]
+	length := func(n int) int {
+		var v uint64
+		for _, x := range b[:n] {
+			v = v<<8 | uint64(x)
+		}
+		b = b[n:]
+		return int(v)
+	}
+	var n int
+	switch {
+	case c <= 0x7f:
+		return float64(c), b
+	case c >= 0xe0:
+		return float64(int8(c)), b
+	case c == 0xc0:
+		return nil, b
+	case c == 0xc2, c == 0xc3:
+		return c == 0xc3, b
+	case c == 0xcc, c == 0xcd, c == 0xce, c == 0xcf:
+		return float64(length(1 << (c - 0xcc))), b
+	case c >= 0xd0 && c <= 0xd3:
+		size := 1 << (c - 0xd0)
+		v := int64(length(size)) << (64 - 8*size) >> (64 - 8*size)
+		return float64(v), b
+	case c == 0xcb:
+		return math.Float64frombits(binary.BigEndian.Uint64(b)), b[8:]
+	case c&0xe0 == 0xa0, c == 0xd9, c == 0xda, c == 0xdb:
+		switch c {
+		case 0xd9, 0xda, 0xdb:
+			n = length(1 << (c - 0xd9))
+		default:
+			n = int(c & 0x1f)
+		}
+		return string(b[:n]), b[n:]
+	case c&0xf0 == 0x90, c == 0xdc, c == 0xdd:
+		switch c {
+		case 0xdc, 0xdd:
+			n = length(2 << (c - 0xdc))
+		default:
+			n = int(c & 0x0f)
+		}
+		a := make([]interface{}, n)
+		for i := range a {
+			a[i], b = readMsgpack(t, b)
+		}
+		return a, b
+	case c&0xf0 == 0x80, c == 0xde, c == 0xdf:
+		switch c {
+		case 0xde, 0xdf:
+			n = length(2 << (c - 0xde))
+		default:
+			n = int(c & 0x0f)
+		}
+		m := make(map[string]interface{}, n)
+		for i := 0; i < n; i++ {
+			var k, v interface{}
+			k, b = readMsgpack(t, b)
+			v, b = readMsgpack(t, b)
+			m[k.(string)] = v
+		}
+		return m, b
+	}
+	t.Fatalf("readMsgpack() of unknown type %#x", c)
+	return nil, nil
+}
@@ -0,0 +1,432 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Scheduler schedules a set of processes. Rendering the result is left to
+// RenderText, RenderJSON, and RenderHTML.
+//
+// If ctx is cancelled mid-simulation, Schedule returns the partial result of
+// the processes completed so far together with the context's error.
+//
+// The schedulers returned by the New* constructors are safe for concurrent
+// use: Schedule reads the scheduler's options and the processes but modifies
+// neither. The Progress, Slices, Transitions, and Updates callbacks are called from
+// the goroutine running the simulation, so callbacks shared by simulations
+// running at the same time must synchronize themselves. Custom schedulers
+// should make the same guarantee.
+type Scheduler interface {
+	Schedule(ctx context.Context, processes []Process) (*ScheduleResult, error)
+}
+
+// Options holds the tunable parameters shared by the schedulers.
+// Each scheduler only reads the options that apply to it.
+type Options struct {
+	// Quantum is the time slice given to each process by round-robin.
+	Quantum int64
+	// SwitchCost is the time spent switching the CPU from one process to
+	// another, charged by every scheduler before the incoming process runs.
+	SwitchCost int64
+	// DispatchLatency is the time the dispatcher takes to start a process,
+	// charged on every dispatch including the first and including a process
+	// resuming after its own quantum expired.
+	DispatchLatency int64
+	// MigrationCost is the time a process takes to warm its cache back up
+	// when it starts on a different CPU than it last ran on, charged after
+	// dispatching it.
+	MigrationCost int64
+	// CPUs is the number of CPUs processes are dispatched onto.
+	CPUs int
+	// Speeds are per-CPU speed multipliers: a burst of t takes t/s on a CPU
+	// of speed s. Every CPU runs at speed 1 when empty.
+	Speeds []float64
+	// Power, when any of its powers is positive, is the energy model the
+	// schedule's energy use is reported under.
+	Power Power
+	// Aging is how much a process's priority improves per tick spent waiting,
+	// used by priority-ordered schedulers to prevent starvation. Every
+	// scheduler records the aged priority in its transitions.
+	Aging float64
+	// Inheritance lets a process holding a lock run at the priority of the
+	// most urgent process blocked on it, bounding priority inversion.
+	Inheritance bool
+	// TicksPerUnit is how many ticks, the unit of every time given to and
+	// reported by a scheduler, make up one unit of time in text and HTML
+	// reports, so workloads with fractional times can be simulated in whole
+	// ticks. See LoadProcessesFractional.
+	TicksPerUnit int64
+	// WarmUp and CoolDown exclude the start and the final drain of a
+	// schedule from its aggregate metrics: processes arriving in the first
+	// WarmUp ticks or completing in the last CoolDown ticks still get rows
+	// but are left out of the averages, and throughput, utilization, and
+	// overhead cover only the time in between.
+	WarmUp   int64
+	CoolDown int64
+	// Memory, when positive, is the total memory processes are admitted
+	// into: a process that arrives when its Memory does not fit in what is
+	// free waits, in arrival order, for processes to terminate.
+	Memory int64
+	// MaxTime, when positive, is the horizon at which the simulation stops
+	// even if processes are left unfinished, so pathological workloads
+	// cannot run forever. Custom schedulers should honour it too.
+	MaxTime int64
+	// CheckpointAt, when positive, pauses the simulation at that time,
+	// returning the partial result with a Checkpoint to resume it from.
+	CheckpointAt int64
+	// TieBreak orders processes that are otherwise equal candidates.
+	TieBreak TieBreak
+	// Progress, when non-nil, is called with the number of completed processes
+	// after each process completes, for reporting progress of long simulations.
+	Progress func(done, total int) `json:"-"`
+	// Slices, when non-nil, is handed each Gantt slice once it can no
+	// longer change, in the order of ScheduleResult.Gantt, instead of the
+	// chart being kept, so memory stays flat however long the timeline.
+	// The result's Gantt is then empty and Streamed is set. Slices cannot
+	// be combined with CoolDown, which needs the whole chart, or with
+	// CheckpointAt, which saves it.
+	Slices func(ts TimeSlice) `json:"-"`
+	// Transitions, when non-nil, is handed each state change of every
+	// process as the simulation makes it, for animating a schedule while it
+	// runs. Changes the simulation handles after they were due, such as
+	// I/O completing mid-slice, come with their earlier time, so times may
+	// step back a little. A process taken off its CPU while still being
+	// dispatched has its running transition, which would have come at the
+	// end of the dispatch, followed by a ready one at the time it was.
+	Transitions func(tr Transition) `json:"-"`
+	// Updates, when non-nil, is handed the running metrics each time a
+	// process completes or is killed, so clients can show the results of a
+	// long simulation as they form.
+	Updates func(u MetricsUpdate) `json:"-"`
+	// MetricsOnly skips the parts of a result that only detailed reports
+	// show: the transitions, the ideal metrics, the verification, and,
+	// unless there is a cool-down or a checkpoint that needs it, the Gantt
+	// chart, which is totalled as it is produced instead of kept. Metrics,
+	// rows, and the summaries built from rows are unaffected.
+	MetricsOnly bool
+	// Seed seeds every random choice a scheduler makes, so runs are reproducible.
+	Seed int64
+
+	seeded bool // Seed was given, so reports print it
+}
+
+// Option configures a scheduler built by one of the New* constructors.
+type Option func(*Options)
+
+// DefaultOptions returns the options used when none are given.
+func DefaultOptions() Options {
+	return Options{
+		Quantum:      2,
+		CPUs:         1,
+		TicksPerUnit: 1,
+		TieBreak:     ByArrival,
+	}
+}
+
+func newOptions(opts []Option) Options {
+	o := DefaultOptions()
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return o
+}
+
+func (o Options) validate() error {
+	switch {
+	case o.Quantum <= 0:
+		return fmt.Errorf("%w: quantum %d must be positive", ErrValidation, o.Quantum)
+	case o.SwitchCost < 0:
+		return fmt.Errorf("%w: switch cost %d must not be negative", ErrValidation, o.SwitchCost)
+	case o.DispatchLatency < 0:
+		return fmt.Errorf("%w: dispatch latency %d must not be negative", ErrValidation, o.DispatchLatency)
+	case o.CheckpointAt < 0:
+		return fmt.Errorf("%w: checkpoint time %d must not be negative", ErrValidation, o.CheckpointAt)
+	case o.MigrationCost < 0:
+		return fmt.Errorf("%w: migration cost %d must not be negative", ErrValidation, o.MigrationCost)
+	case o.CPUs < 1:
+		return fmt.Errorf("%w: %d CPUs, need at least one", ErrValidation, o.CPUs)
+	case len(o.Speeds) != 0 && len(o.Speeds) != o.CPUs:
+		return fmt.Errorf("%w: %d speeds for %d CPUs", ErrValidation, len(o.Speeds), o.CPUs)
+	case o.TicksPerUnit < 1:
+		return fmt.Errorf("%w: %d ticks per unit, need at least one", ErrValidation, o.TicksPerUnit)
+	case o.Aging < 0:
+		return fmt.Errorf("%w: aging rate %v must not be negative", ErrValidation, o.Aging)
+	case o.Memory < 0:
+		return fmt.Errorf("%w: memory %d must not be negative", ErrValidation, o.Memory)
+	case o.MaxTime < 0:
+		return fmt.Errorf("%w: max time %d must not be negative", ErrValidation, o.MaxTime)
+	case o.WarmUp < 0 || o.CoolDown < 0:
+		return fmt.Errorf("%w: warm-up %d and cool-down %d must not be negative", ErrValidation, o.WarmUp, o.CoolDown)
+	case o.Slices != nil && o.CoolDown > 0:
+		return fmt.Errorf("%w: streamed Gantt charts cannot have a cool-down", ErrValidation)
+	case o.Slices != nil && o.CheckpointAt > 0:
+		return fmt.Errorf("%w: streamed Gantt charts cannot be checkpointed", ErrValidation)
+	}
+
+	if err := o.Power.validate(); err != nil {
+		return err
+	}
+	for c := range o.Speeds {
+		if o.speed(c) < 1 {
+			return fmt.Errorf("%w: CPU %d speed %v must be at least %v", ErrValidation, c, o.Speeds[c], 1.0/speedScale)
+		}
+	}
+
+	return nil
+}
+
+// speed is the work CPU c does per tick, in units of 1/speedScale.
+func (o Options) speed(c int) int64 {
+	if len(o.Speeds) == 0 {
+		return speedScale
+	}
+	return int64(math.Round(o.Speeds[c] * speedScale))
+}
+
+// WithQuantum sets the round-robin time slice.
+func WithQuantum(q int64) Option {
+	return func(o *Options) { o.Quantum = q }
+}
+
+// WithSwitchCost sets the context-switch overhead between slices of
+// different processes.
+func WithSwitchCost(t int64) Option {
+	return func(o *Options) { o.SwitchCost = t }
+}
+
+// WithDispatchLatency sets the dispatcher latency charged on every dispatch.
+func WithDispatchLatency(t int64) Option {
+	return func(o *Options) { o.DispatchLatency = t }
+}
+
+// WithWarmUp excludes the first t ticks of a schedule from its metrics.
+func WithWarmUp(t int64) Option {
+	return func(o *Options) { o.WarmUp = t }
+}
+
+// WithCoolDown excludes the last t ticks of a schedule from its metrics.
+func WithCoolDown(t int64) Option {
+	return func(o *Options) { o.CoolDown = t }
+}
+
+// WithMemory limits the total memory of the processes admitted at once.
+func WithMemory(n int64) Option {
+	return func(o *Options) { o.Memory = n }
+}
+
+// WithMaxTime stops the simulation at time t, leaving any processes that
+// have not finished by then unfinished.
+func WithMaxTime(t int64) Option {
+	return func(o *Options) { o.MaxTime = t }
+}
+
+// WithCheckpoint pauses the simulation at time at, so it can be saved and
+// resumed later with a Resumer.
+func WithCheckpoint(at int64) Option {
+	return func(o *Options) { o.CheckpointAt = at }
+}
+
+// WithMigrationCost sets the penalty charged when a process moves to a
+// different CPU.
+func WithMigrationCost(t int64) Option {
+	return func(o *Options) { o.MigrationCost = t }
+}
+
+// WithCPUs sets the number of CPUs to schedule onto.
+func WithCPUs(n int) Option {
+	return func(o *Options) { o.CPUs = n }
+}
+
+// WithSpeeds sets the speed multiplier of each CPU, one per CPU, for
+// heterogeneous (big.LITTLE style) cores.
+func WithSpeeds(speeds ...float64) Option {
+	// Copied, so the caller's slice can change without racing simulations.
+	speeds = append([]float64(nil), speeds...)
+	return func(o *Options) { o.Speeds = speeds }
+}
+
+// WithPower sets the energy model of the CPUs, so results report the energy
+// each schedule uses.
+func WithPower(p Power) Option {
+	return func(o *Options) { o.Power = p }
+}
+
+// WithAging sets the per-tick priority boost given to waiting processes.
+func WithAging(rate float64) Option {
+	return func(o *Options) { o.Aging = rate }
+}
+
+// WithPriorityInheritance enables priority inheritance for processes
+// holding locks.
+func WithPriorityInheritance() Option {
+	return func(o *Options) { o.Inheritance = true }
+}
+
+// WithTicksPerUnit sets how many ticks make one unit of time in reports.
+func WithTicksPerUnit(n int64) Option {
+	return func(o *Options) { o.TicksPerUnit = n }
+}
+
+// WithTieBreak sets how equal candidates are ordered.
+func WithTieBreak(tb TieBreak) Option {
+	return func(o *Options) { o.TieBreak = tb }
+}
+
+// WithSeed seeds all randomness used by the scheduler and records the seed
+// in the result, so the run can be reproduced.
+func WithSeed(seed int64) Option {
+	return func(o *Options) { o.Seed, o.seeded = seed, true }
+}
+
+// WithProgress calls fn with the number of completed processes and the total
+// after each process completes.
+func WithProgress(fn func(done, total int)) Option {
+	return func(o *Options) { o.Progress = fn }
+}
+
+// WithSlices hands fn each slice of the Gantt chart as soon as it is final
+// rather than keeping the chart in the result; see Options.Slices.
+func WithSlices(fn func(ts TimeSlice)) Option {
+	return func(o *Options) { o.Slices = fn }
+}
+
+// WithTransitions hands fn each state change as it happens; see
+// Options.Transitions.
+func WithTransitions(fn func(tr Transition)) Option {
+	return func(o *Options) { o.Transitions = fn }
+}
+
+// WithUpdates hands fn the running metrics after each process completes or
+// is killed; see MetricsUpdate.
+func WithUpdates(fn func(u MetricsUpdate)) Option {
+	return func(o *Options) { o.Updates = fn }
+}
+
+// WithMetricsOnly computes only what the metrics need; see
+// Options.MetricsOnly.
+func WithMetricsOnly() Option {
+	return func(o *Options) { o.MetricsOnly = true }
+}
+
+func (o Options) progress(done, total int) {
+	if o.Progress != nil {
+		o.Progress(done, total)
+	}
+}
+
+// dispatch gives the CPU to pid at now, charging any switch cost and then the
+// dispatch latency, and returns when pid starts running.
+func (o Options) dispatch(gantt *[]TimeSlice, pid, now int64) int64 {
+	now = o.switchTo(gantt, pid, now)
+	if o.DispatchLatency == 0 {
+		return now
+	}
+	*gantt = append(*gantt, TimeSlice{PID: pid, Start: now, Stop: now + o.DispatchLatency, Kind: SliceDispatch})
+
+	return now + o.DispatchLatency
+}
+
+// migrate charges the migration cost for pid, which moved to this CPU,
+// recording it in gantt, and returns when pid can start.
+func (o Options) migrate(gantt *[]TimeSlice, pid, now int64) int64 {
+	if o.MigrationCost == 0 {
+		return now
+	}
+	*gantt = append(*gantt, TimeSlice{PID: pid, Start: now, Stop: now + o.MigrationCost, Kind: SliceMigrate})
+
+	return now + o.MigrationCost
+}
+
+// idle records the CPU idling from now until the given time, if later, and
+// returns the time the CPU is next busy.
+func idle(gantt *[]TimeSlice, now, until int64) int64 {
+	if until <= now {
+		return now
+	}
+	*gantt = append(*gantt, TimeSlice{Start: now, Stop: until, Kind: SliceIdle})
+
+	return until
+}
+
+// switchTo charges the switch cost when pid takes the CPU from a different
+// process, recording the overhead in gantt, and returns when pid can start.
+// The first dispatch is free and the running process keeps the CPU at no cost.
+func (o Options) switchTo(gantt *[]TimeSlice, pid, now int64) int64 {
+	if o.SwitchCost == 0 {
+		return now
+	}
+	for k := len(*gantt) - 1; k >= 0; k-- {
+		if (*gantt)[k].Kind != SliceRun {
+			continue
+		}
+		if (*gantt)[k].PID == pid {
+			return now
+		}
+		*gantt = append(*gantt, TimeSlice{PID: pid, Start: now, Stop: now + o.SwitchCost, Kind: SliceSwitch})
+		return now + o.SwitchCost
+	}
+
+	return now
+}
+
+// TieBreak decides which of two equally ranked processes runs first.
+type TieBreak int
+
+const (
+	// ByArrival prefers the process that arrived first.
+	ByArrival TieBreak = iota
+	// ByPID prefers the lower process ID.
+	ByPID
+	// ByPriority prefers the higher priority (lower number).
+	ByPriority
+	// ByRandom orders equal processes by a shuffle drawn from the seed, so
+	// ties are broken arbitrarily but reproducibly.
+	ByRandom
+)
+
+var tieBreakNames = []string{ByArrival: "arrival", ByPID: "pid", ByPriority: "priority", ByRandom: "random"}
+
+func (tb TieBreak) String() string {
+	if tb < 0 || int(tb) >= len(tieBreakNames) {
+		return fmt.Sprintf("TieBreak(%d)", int(tb))
+	}
+	return tieBreakNames[tb]
+}
+
+// ParseTieBreak returns the TieBreak named arrival, pid, priority, or random.
+func ParseTieBreak(name string) (TieBreak, error) {
+	for tb, n := range tieBreakNames {
+		if n == name {
+			return TieBreak(tb), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown tie-break %q, want one of %s", name, strings.Join(tieBreakNames, ", "))
+}
+
+// less reports whether a should be preferred over b when they are otherwise equal.
+// Processes that are still equal keep their input order.
+func (o Options) less(a, b Process) bool {
+	switch o.TieBreak {
+	case ByPID:
+		return a.ProcessID < b.ProcessID
+	case ByPriority:
+		return a.Priority < b.Priority
+	case ByRandom:
+		return shuffleKey(o.Seed, a.ProcessID) < shuffleKey(o.Seed, b.ProcessID)
+	default:
+		return a.ArrivalTime < b.ArrivalTime
+	}
+}
+
+// shuffleKey is a seeded pseudo-random key for a process ID (splitmix64),
+// so sorting by it shuffles processes the same way for the same seed.
+func shuffleKey(seed, pid int64) uint64 {
+	z := uint64(seed) + uint64(pid)*0x9e3779b97f4a7c15
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	return z ^ (z >> 31)
+}
@@ -0,0 +1,233 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func Test_newOptions(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		opts []Option
+		want Options
+	}{
+		{
+			name: "defaults",
+			want: Options{Quantum: 2, CPUs: 1, TicksPerUnit: 1, TieBreak: ByArrival},
+		},
+		{
+			name: "all options",
+			opts: []Option{WithQuantum(4), WithCPUs(2), WithAging(0.5), WithTicksPerUnit(10), WithWarmUp(3), WithCoolDown(5), WithMaxTime(100), WithMemory(64), WithMigrationCost(2), WithTieBreak(ByPID)},
+			want: Options{Quantum: 4, CPUs: 2, Aging: 0.5, TicksPerUnit: 10, WarmUp: 3, CoolDown: 5, MaxTime: 100, Memory: 64, MigrationCost: 2, TieBreak: ByPID},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := newOptions(tt.opts); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("newOptions() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSchedulerInvalidOptions(t *testing.T) {
+	t.Parallel()
+	processes := []Process{{ProcessID: 1, BurstDuration: 5}}
+	tests := []struct {
+		name      string
+		scheduler Scheduler
+	}{
+		{name: "zero quantum", scheduler: NewRR(WithQuantum(0))},
+		{name: "negative aging", scheduler: NewPriority(WithAging(-1))},
+		{name: "negative switch cost", scheduler: NewFCFS(WithSwitchCost(-1))},
+		{name: "negative dispatch latency", scheduler: NewRR(WithDispatchLatency(-1))},
+		{name: "negative migration cost", scheduler: NewRR(WithCPUs(2), WithMigrationCost(-1))},
+		{name: "negative power", scheduler: NewFCFS(WithPower(Power{Active: 1, Idle: -0.5}))},
+		{name: "no CPUs", scheduler: NewSJF(WithCPUs(0))},
+		{name: "speeds for the wrong number of CPUs", scheduler: NewFCFS(WithCPUs(2), WithSpeeds(1))},
+		{name: "zero speed", scheduler: NewFCFS(WithSpeeds(0))},
+		{name: "no ticks per unit", scheduler: NewFCFS(WithTicksPerUnit(0))},
+		{name: "negative warm-up", scheduler: NewFCFS(WithWarmUp(-1))},
+		{name: "negative max time", scheduler: NewFCFS(WithMaxTime(-1))},
+		{name: "negative memory", scheduler: NewFCFS(WithMemory(-1))},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if _, err := tt.scheduler.Schedule(context.Background(), processes); !errors.Is(err, ErrValidation) {
+				t.Errorf("Schedule() error = %v, want %v", err, ErrValidation)
+			}
+		})
+	}
+}
+
+func TestTieBreak_less(t *testing.T) {
+	t.Parallel()
+	a := Process{ProcessID: 2, ArrivalTime: 1, Priority: 3}
+	b := Process{ProcessID: 1, ArrivalTime: 2, Priority: 1}
+	tests := []struct {
+		name string
+		tb   TieBreak
+		want bool
+	}{
+		{name: "arrival", tb: ByArrival, want: true},
+		{name: "pid", tb: ByPID, want: false},
+		{name: "priority", tb: ByPriority, want: false},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := newOptions([]Option{WithTieBreak(tt.tb)}).less(a, b); got != tt.want {
+				t.Errorf("less() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithProgress(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: 1, ArrivalTime: 0, BurstDuration: 5, Priority: 2},
+		{ProcessID: 2, ArrivalTime: 3, BurstDuration: 9, Priority: 1},
+		{ProcessID: 3, ArrivalTime: 6, BurstDuration: 6, Priority: 3},
+	}
+	var calls [][2]int
+	progress := WithProgress(func(done, total int) { calls = append(calls, [2]int{done, total}) })
+	if _, err := NewFCFS(progress).Schedule(context.Background(), processes); err != nil {
+		t.Fatal(err)
+	}
+	if want := [][2]int{{1, 3}, {2, 3}, {3, 3}}; !reflect.DeepEqual(calls, want) {
+		t.Errorf("progress calls = %v, want %v", calls, want)
+	}
+}
+
+func TestWithUpdates(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: 1, ArrivalTime: 0, BurstDuration: 5, Priority: 2},
+		{ProcessID: 2, ArrivalTime: 3, BurstDuration: 9, Priority: 1},
+		{ProcessID: 3, ArrivalTime: 6, BurstDuration: 6, Priority: 3},
+		{ProcessID: 4, ArrivalTime: 0, BurstDuration: 50, KillAt: 1},
+	}
+	var got []MetricsUpdate
+	updates := WithUpdates(func(u MetricsUpdate) { got = append(got, u) })
+	r, err := NewFCFS(updates).Schedule(context.Background(), processes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []MetricsUpdate{
+		{At: 1, Killed: 1, Total: 4},
+		{At: 5, Completed: 1, Killed: 1, Total: 4, AverageWait: 0, AverageTurnaround: 5, Throughput: 1.0 / 5},
+		{At: 14, Completed: 2, Killed: 1, Total: 4, AverageWait: 1, AverageTurnaround: 8, Throughput: 2.0 / 14},
+		{At: 20, Completed: 3, Killed: 1, Total: 4, AverageWait: 10.0 / 3, AverageTurnaround: 30.0 / 3, Throughput: 3.0 / 20},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("updates = %+v, want %+v", got, want)
+	}
+	last := got[len(got)-1]
+	if last.AverageWait != r.Metrics.AverageWait || last.AverageTurnaround != r.Metrics.AverageTurnaround {
+		t.Errorf("last update = %+v, want the averages of %+v", last, r.Metrics)
+	}
+}
+
+func TestTieBreak_random(t *testing.T) {
+	t.Parallel()
+	order := func(seed int64) []int64 {
+		o := newOptions([]Option{WithTieBreak(ByRandom), WithSeed(seed)})
+		pids := []int64{1, 2, 3, 4, 5, 6, 7, 8}
+		sort.Slice(pids, func(i, j int) bool { return o.less(Process{ProcessID: pids[i]}, Process{ProcessID: pids[j]}) })
+		return pids
+	}
+	shuffled := order(1)
+	if !reflect.DeepEqual(shuffled, order(1)) {
+		t.Error("random tie-break is not reproducible for the same seed")
+	}
+	if reflect.DeepEqual(shuffled, order(2)) {
+		t.Error("random tie-break ignores the seed")
+	}
+	if sort.SliceIsSorted(shuffled, func(i, j int) bool { return shuffled[i] < shuffled[j] }) {
+		t.Error("random tie-break kept PID order")
+	}
+}
+
+func TestParseTieBreak(t *testing.T) {
+	t.Parallel()
+	for _, tb := range []TieBreak{ByArrival, ByPID, ByPriority, ByRandom} {
+		got, err := ParseTieBreak(tb.String())
+		if err != nil || got != tb {
+			t.Errorf("ParseTieBreak(%q) = %v, %v, want %v", tb.String(), got, err, tb)
+		}
+	}
+	if _, err := ParseTieBreak("coin"); err == nil {
+		t.Error("ParseTieBreak(coin) succeeded")
+	}
+}
+
+func TestWithMetricsOnly(t *testing.T) {
+	t.Parallel()
+	processes := Generate(rand.New(rand.NewSource(3)), 100)
+	configs := map[string][]Option{
+		"one CPU":   {WithSwitchCost(1)},
+		"four CPUs": {WithCPUs(4), WithMigrationCost(2), WithPower(Power{Active: 2, Idle: 0.5})},
+		"cool-down": {WithCoolDown(10), WithDispatchLatency(1)},
+	}
+	for _, name := range []string{"fcfs", "sjf", "rr"} {
+		factory, _ := Lookup(name)
+		for config, opts := range configs {
+			full, err := factory(opts...).Schedule(context.Background(), processes)
+			if err != nil {
+				t.Fatalf("%s, %s: Schedule() error = %v", name, config, err)
+			}
+			lean, err := factory(append(opts, WithMetricsOnly())...).Schedule(context.Background(), processes)
+			if err != nil {
+				t.Fatalf("%s, %s: metrics-only Schedule() error = %v", name, config, err)
+			}
+			if !reflect.DeepEqual(lean.Metrics, full.Metrics) || !reflect.DeepEqual(lean.Rows, full.Rows) {
+				t.Errorf("%s, %s: metrics-only metrics = %+v, want %+v", name, config, lean.Metrics, full.Metrics)
+			}
+			if lean.Transitions != nil || lean.Ideal != nil || lean.Streamed {
+				t.Errorf("%s, %s: metrics-only result has %d transitions, ideal metrics %v, and Streamed = %v, want none", name, config, len(lean.Transitions), lean.Ideal, lean.Streamed)
+			}
+			// Only a cool-down needs the chart kept.
+			if kept := len(lean.Gantt) > 0; kept != (config == "cool-down") {
+				t.Errorf("%s, %s: metrics-only result has %d slices", name, config, len(lean.Gantt))
+			}
+		}
+	}
+}
+
+func TestWithTransitions(t *testing.T) {
+	t.Parallel()
+	processes := Generate(rand.New(rand.NewSource(4)), 50)
+	processes[2].Cycles = []Cycle{{IO: 3, CPU: 2}}
+	opts := []Option{WithCPUs(2), WithSwitchCost(1)}
+	want, err := NewRR(opts...).Schedule(context.Background(), processes)
+	if err != nil {
+		t.Fatalf("Schedule() error = %v", err)
+	}
+	for _, extra := range []Option{WithSeed(1), WithMetricsOnly()} {
+		var got []Transition
+		r, err := NewRR(append(opts, extra, WithTransitions(func(tr Transition) { got = append(got, tr) }))...).Schedule(context.Background(), processes)
+		if err != nil {
+			t.Fatalf("Schedule() error = %v", err)
+		}
+		// Handed out as they happen, the transitions are those of the
+		// result before it sorts them by time.
+		sort.SliceStable(got, func(a, b int) bool { return got[a].At < got[b].At })
+		if !reflect.DeepEqual(got, want.Transitions) {
+			t.Errorf("WithTransitions() handed out %v, want %v", got, want.Transitions)
+		}
+		if !reflect.DeepEqual(r.Metrics, want.Metrics) {
+			t.Errorf("WithTransitions() changed the metrics to %+v, want %+v", r.Metrics, want.Metrics)
+		}
+	}
+}
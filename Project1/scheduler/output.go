@@ -0,0 +1,477 @@
+package scheduler
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+)
+
+// errWriter remembers the first error returned by the underlying writer, so
+// the output helpers can write freely and the caller reports a single error.
+type errWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (ew *errWriter) Write(p []byte) (int, error) {
+	if ew.err != nil {
+		return 0, ew.err
+	}
+	var n int
+	n, ew.err = ew.w.Write(p)
+
+	return n, ew.err
+}
+
+//region Renderers
+
+// RenderText writes the title, Gantt chart, and schedule table of r to w.
+func RenderText(w io.Writer, title string, r *ScheduleResult) error {
+	ew := &errWriter{w: w}
+	m, u := r.UnitMetrics(), r.ticksPerUnit()
+	outputHeading(ew, title, r, m)
+	outputViolations(ew, r.Violations, u)
+	if !r.Streamed {
+		outputGantt(ew, r.Gantt, u)
+	}
+	outputSuspensions(ew, r.Suspensions, u)
+	outputSchedule(ew, textRows(r.Rows, u), m.AverageWait, m.AverageTurnaround, m.Throughput)
+	if ideal := r.UnitIdeal(); ideal != nil {
+		outputOverhead(ew, overheadRows(*ideal, m), m.Overhead)
+	}
+	if len(m.Cores) > 0 {
+		outputCores(ew, m.Cores, m.LoadImbalance, u)
+	}
+	if m.Migrations > 0 {
+		_, _ = fmt.Fprintf(ew, "Migrations: %d, penalty %.2f\n\n", m.Migrations, m.MigrationPenalty)
+	}
+	if m.Energy > 0 {
+		_, _ = fmt.Fprintf(ew, "Energy: %.2f, idle %.2f\n\n", m.Energy, m.IdleEnergy)
+	}
+	if r.Memory > 0 {
+		outputAdmission(ew, r.Rows, m, u)
+	}
+	outputClasses(ew, r.Classes, u)
+	outputTasks(ew, r.Tasks, u)
+	outputTrees(ew, r.Rows, r.Trees, u)
+	outputUnfinished(ew, r.Unfinished, u)
+	outputInversions(ew, r.Inversions, u)
+
+	return ew.err
+}
+
+// RenderMetrics writes the title and the averages of r to w, and nothing
+// per process, for results of simulations run WithMetricsOnly.
+func RenderMetrics(w io.Writer, title string, r *ScheduleResult) error {
+	ew := &errWriter{w: w}
+	m := r.UnitMetrics()
+	outputHeading(ew, title, r, m)
+	_, _ = fmt.Fprintf(ew, "Average wait %.2f, average turnaround %.2f, throughput %.2f/t, %d of %d processes completed\n\n",
+		m.AverageWait, m.AverageTurnaround, m.Throughput, m.Completed, r.Total)
+
+	return ew.err
+}
+
+// RenderJSON writes r to w as a single JSON object followed by a newline.
+func RenderJSON(w io.Writer, title string, r *ScheduleResult) error {
+	return json.NewEncoder(w).Encode(struct {
+		Title string `json:"title"`
+		*ScheduleResult
+	}{title, r})
+}
+
+// RenderHTML writes r to w as an HTML <section> fragment, with the Gantt
+// chart drawn as proportionally sized boxes.
+func RenderHTML(w io.Writer, title string, r *ScheduleResult) error {
+	return htmlTemplate.Execute(w, struct {
+		Title string
+		*ScheduleResult
+	}{title, r})
+}
+
+var htmlTemplate = template.Must(template.New("schedule").Funcs(template.FuncMap{
+	"duration":     func(ts TimeSlice) int64 { return ts.Stop - ts.Start },
+	"time":         func(r *ScheduleResult, t int64) string { return unitTime(t, r.ticksPerUnit()) },
+	"label":        sliceLabel,
+	"cores":        coreGantts,
+	"overheadRows": overheadRows,
+	"violation":    func(r *ScheduleResult, v Violation) string { return v.describe(r.ticksPerUnit()) },
+}).Parse(`<section class="schedule">
+<h2>{{.Title}}{{if .Cancelled}} (cancelled, {{.Metrics.Completed}} of {{.Total}} processes completed){{else if .Truncated}} (stopped at {{printf "%g" .UnitMetrics.Makespan}}, {{.Metrics.Completed}} of {{.Total}} processes completed){{end}}</h2>
+{{- if .Seed}}
+<p>Seed: {{.Seed}}</p>
+{{- end}}
+{{- with .Violations}}
+<div class="violations"><strong>Invalid schedule: {{len .}} violations</strong>
+<ul>
+{{- range .}}
+<li>{{violation $.ScheduleResult .}}</li>
+{{- end}}
+</ul>
+</div>
+{{- end}}
+{{- range cores .Gantt}}
+<div class="gantt" style="display:flex">
+{{- range .}}
+<div style="flex:{{duration .}};border:1px solid;text-align:center" title="{{time $.ScheduleResult .Start}}-{{time $.ScheduleResult .Stop}}">{{label .}}</div>
+{{- end}}
+</div>
+{{- end}}
+<table>
+<thead><tr><th>ID</th><th>Priority</th><th>Burst</th><th>Arrival</th><th>Wait</th><th>Turnaround</th><th>Exit</th></tr></thead>
+<tbody>
+{{- range .Rows}}
+<tr><td>{{.ProcessID}}</td><td>{{.Priority}}</td><td>{{time $.ScheduleResult .BurstDuration}}</td><td>{{time $.ScheduleResult .ArrivalTime}}</td><td>{{time $.ScheduleResult .Wait}}</td><td>{{time $.ScheduleResult .Turnaround}}</td><td>{{time $.ScheduleResult .Exit}}{{if .Killed}} (killed){{else if .Missed}} (late){{else if .Excluded}} (excluded){{end}}</td></tr>
+{{- end}}
+</tbody>
+<tfoot><tr><td colspan="4"></td><td>Average {{printf "%.2f" $.UnitMetrics.AverageWait}}</td><td>Average {{printf "%.2f" $.UnitMetrics.AverageTurnaround}}</td><td>Throughput {{printf "%.2f" $.UnitMetrics.Throughput}}/t</td></tr></tfoot>
+</table>
+{{- with .UnitIdeal}}
+<table class="overhead">
+<thead><tr><th>Metric</th><th>Ideal</th><th>With overhead</th><th>Change</th></tr></thead>
+<tbody>
+{{- range overheadRows . $.UnitMetrics}}
+<tr>{{range .}}<td>{{.}}</td>{{end}}</tr>
+{{- end}}
+</tbody>
+<tfoot><tr><td colspan="2"></td><td>Overhead</td><td>{{printf "%g" $.UnitMetrics.Overhead}}</td></tr></tfoot>
+</table>
+{{- end}}
+</section>
+`))
+
+// textRows formats rows for the schedule table. Every cell is appended to
+// one buffer and sliced out of a single string made from it, so a table of
+// many processes costs a handful of allocations rather than several per row.
+func textRows(rows []Row, u float64) [][]string {
+	const columns = 7
+	var buf []byte
+	ends := make([]int, 0, len(rows)*columns)
+	for _, row := range rows {
+		buf = strconv.AppendInt(buf, row.ProcessID, 10)
+		if row.Job > 0 {
+			// A job of a periodic task: task#job.
+			buf = append(buf, " ("...)
+			buf = strconv.AppendInt(buf, row.Task, 10)
+			buf = append(buf, '#')
+			buf = strconv.AppendInt(buf, int64(row.Job), 10)
+			buf = append(buf, ')')
+		}
+		ends = append(ends, len(buf))
+		buf = strconv.AppendInt(buf, row.Priority, 10)
+		ends = append(ends, len(buf))
+		for _, t := range [...]int64{row.BurstDuration, row.ArrivalTime, row.Wait, row.Turnaround, row.Exit} {
+			buf = appendUnitTime(buf, t, u)
+			ends = append(ends, len(buf))
+		}
+		switch {
+		case row.Killed:
+			buf = append(buf, " (killed)"...)
+		case row.Missed:
+			buf = append(buf, " (late)"...)
+		case row.Excluded:
+			buf = append(buf, " (excluded)"...)
+		}
+		ends[len(ends)-1] = len(buf)
+	}
+
+	all := string(buf)
+	cells := make([]string, len(ends))
+	start := 0
+	for k, end := range ends {
+		cells[k], start = all[start:end], end
+	}
+	text := make([][]string, len(rows))
+	for i := range text {
+		text[i] = cells[i*columns : (i+1)*columns : (i+1)*columns]
+	}
+
+	return text
+}
+
+//endregion
+
+//region Output helpers
+
+// ticksPerUnit is how many ticks of r make one unit of time in reports.
+func (r *ScheduleResult) ticksPerUnit() float64 {
+	if r.TicksPerUnit > 1 {
+		return float64(r.TicksPerUnit)
+	}
+	return 1
+}
+
+// unitTime formats t ticks in units of u ticks, as a decimal if need be.
+func unitTime(t int64, u float64) string {
+	if u == 1 {
+		return strconv.FormatInt(t, 10)
+	}
+	return strconv.FormatFloat(float64(t)/u, 'f', -1, 64)
+}
+
+// appendUnitTime appends t formatted as unitTime formats it to b.
+func appendUnitTime(b []byte, t int64, u float64) []byte {
+	if u == 1 {
+		return strconv.AppendInt(b, t, 10)
+	}
+	return strconv.AppendFloat(b, float64(t)/u, 'f', -1, 64)
+}
+
+// outputHeading writes the title of r, noting whether it is partial, its
+// seed, and whether its metrics m are rounded.
+func outputHeading(w io.Writer, title string, r *ScheduleResult, m Metrics) {
+	switch {
+	case r.Cancelled:
+		title = fmt.Sprintf("%s (cancelled, %d of %d processes completed)", title, r.Metrics.Completed, r.Total)
+	case r.Truncated:
+		title = fmt.Sprintf("%s (stopped at %s, %d of %d processes completed)", title, unitTime(int64(r.Metrics.Makespan), r.ticksPerUnit()), r.Metrics.Completed, r.Total)
+	}
+	outputTitle(w, title)
+	if r.Seed != nil {
+		outputSeed(w, *r.Seed)
+	}
+	if m.Imprecise {
+		_, _ = fmt.Fprintln(w, "Warning: totals exceed 2^53 ticks, so metrics are rounded.")
+		_, _ = fmt.Fprintln(w)
+	}
+}
+
+// RenderTitle writes title as the heading every text report starts with,
+// for the reports of the other simulators to match.
+func RenderTitle(w io.Writer, title string) { outputTitle(w, title) }
+
+func outputTitle(w io.Writer, title string) {
+	_, _ = fmt.Fprintln(w, strings.Repeat("-", len(title)*2))
+	_, _ = fmt.Fprintln(w, strings.Repeat(" ", len(title)/2), title)
+	_, _ = fmt.Fprintln(w, strings.Repeat("-", len(title)*2))
+}
+
+func outputSeed(w io.Writer, seed int64) {
+	_, _ = fmt.Fprintf(w, "Seed: %d\n\n", seed)
+}
+
+// outputGantt writes the Gantt chart, one row per core when there are several.
+func outputGantt(w io.Writer, gantt []TimeSlice, u float64) {
+	_, _ = fmt.Fprintln(w, "Gantt schedule")
+	cores := coreGantts(gantt)
+	for c, gantt := range cores {
+		if len(cores) > 1 {
+			_, _ = fmt.Fprintf(w, "CPU %d\n", c)
+		}
+		outputGanttRow(w, gantt, u)
+	}
+}
+
+// outputGanttRow writes one row of a Gantt chart: a box per slice, and the
+// times between them.
+func outputGanttRow(w io.Writer, gantt []TimeSlice, u float64) {
+	_, _ = fmt.Fprint(w, "|")
+	for i := range gantt {
+		label := sliceLabel(gantt[i])
+		padding := strings.Repeat(" ", (8-len(label))/2)
+		_, _ = fmt.Fprint(w, padding, label, padding, "|")
+	}
+	_, _ = fmt.Fprintln(w)
+	for i := range gantt {
+		_, _ = fmt.Fprint(w, unitTime(gantt[i].Start, u), "\t")
+		if len(gantt)-1 == i {
+			_, _ = fmt.Fprint(w, unitTime(gantt[i].Stop, u))
+		}
+	}
+	_, _ = fmt.Fprintf(w, "\n\n")
+}
+
+// ganttRow is how many slices a GanttWriter puts in each row of a chart.
+const ganttRow = 16
+
+// GanttWriter writes a Gantt chart in text as its slices stream in from
+// WithSlices, a row of ganttRow slices of a CPU at a time, so the chart of
+// however long a timeline is written in constant memory:
+//
+//	gw := scheduler.NewGanttWriter(w, 1, 1)
+//	r, err := scheduler.NewRR(scheduler.WithSlices(gw.Add)).Schedule(ctx, processes)
+//	err = gw.Close()
+//
+// RenderText leaves the chart out of the report of a streamed result.
+type GanttWriter struct {
+	w       *errWriter
+	u       float64
+	rows    [][]TimeSlice
+	started bool
+}
+
+// NewGanttWriter returns a GanttWriter of the chart of a simulation on cpus
+// CPUs, with times in units of ticksPerUnit ticks.
+func NewGanttWriter(w io.Writer, cpus int, ticksPerUnit int64) *GanttWriter {
+	if ticksPerUnit < 1 {
+		ticksPerUnit = 1
+	}
+	return &GanttWriter{w: &errWriter{w: w}, u: float64(ticksPerUnit), rows: make([][]TimeSlice, cpus)}
+}
+
+// Add adds the next slice to the chart, writing out the row of its CPU
+// once it is full.
+func (g *GanttWriter) Add(ts TimeSlice) {
+	for ts.CPU >= len(g.rows) {
+		g.rows = append(g.rows, nil)
+	}
+	g.rows[ts.CPU] = append(g.rows[ts.CPU], ts)
+	if len(g.rows[ts.CPU]) == ganttRow {
+		g.writeRow(ts.CPU)
+	}
+}
+
+// Close writes out the rows left and returns the first error writing the
+// chart.
+func (g *GanttWriter) Close() error {
+	for c := range g.rows {
+		if len(g.rows[c]) > 0 || !g.started {
+			g.writeRow(c)
+		}
+	}
+	return g.w.err
+}
+
+func (g *GanttWriter) writeRow(c int) {
+	if !g.started {
+		_, _ = fmt.Fprintln(g.w, "Gantt schedule")
+		g.started = true
+	}
+	if len(g.rows) > 1 {
+		_, _ = fmt.Fprintf(g.w, "CPU %d\n", c)
+	}
+	outputGanttRow(g.w, g.rows[c], g.u)
+	g.rows[c] = g.rows[c][:0]
+}
+
+// coreGantts splits a Gantt chart into the slices of each core.
+func coreGantts(gantt []TimeSlice) [][]TimeSlice {
+	cores := [][]TimeSlice{nil}
+	for _, ts := range gantt {
+		for ts.CPU >= len(cores) {
+			cores = append(cores, nil)
+		}
+		cores[ts.CPU] = append(cores[ts.CPU], ts)
+	}
+
+	return cores
+}
+
+// sliceLabel is how a slice is labelled in Gantt charts: the running
+// process ID, or the kind of overhead.
+func sliceLabel(ts TimeSlice) string {
+	switch ts.Kind {
+	case SliceSwitch:
+		return "cs"
+	case SliceDispatch:
+		return "disp"
+	case SliceMigrate:
+		return "mig"
+	case SliceSuspended:
+		return "susp"
+	case SliceIdle:
+		return "idle"
+	default:
+		return strconv.FormatInt(ts.PID, 10)
+	}
+}
+
+func outputCores(w io.Writer, cores []Core, imbalance, u float64) {
+	_, _ = fmt.Fprintln(w, "CPU utilization")
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"CPU", "Speed", "Busy", "Work", "Utilization"})
+	for _, c := range cores {
+		table.Append([]string{
+			fmt.Sprint(c.CPU),
+			fmt.Sprintf("%gx", c.Speed),
+			unitTime(c.Busy, u),
+			fmt.Sprintf("%g", c.Work),
+			fmt.Sprintf("%.0f%%", 100*c.Utilization),
+		})
+	}
+	table.SetFooter([]string{"", "", "", "Load imbalance", fmt.Sprintf("%.0f%%", 100*imbalance)})
+	table.Render()
+}
+
+// outputUnfinished writes the processes left unfinished at the maximum
+// time, if any.
+func outputUnfinished(w io.Writer, unfinished []Unfinished, u float64) {
+	if len(unfinished) == 0 {
+		return
+	}
+	_, _ = fmt.Fprintln(w, "Unfinished processes")
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"ID", "Priority", "Burst", "Arrival", "Remaining", "State"})
+	for _, p := range unfinished {
+		table.Append([]string{
+			fmt.Sprint(p.ProcessID),
+			fmt.Sprint(p.Priority),
+			unitTime(p.BurstDuration, u),
+			unitTime(p.ArrivalTime, u),
+			unitTime(p.Remaining, u),
+			p.State.String(),
+		})
+	}
+	table.Render()
+}
+
+// overheadRows compares the ideal metrics of a schedule with the metrics m
+// it had with overhead, one row per metric.
+func overheadRows(ideal, m Metrics) [][]string {
+	type line struct {
+		name       string
+		format     string
+		ideal, got float64
+	}
+	rows := []line{
+		{"Average wait", "%.2f", ideal.AverageWait, m.AverageWait},
+		{"Average turnaround", "%.2f", ideal.AverageTurnaround, m.AverageTurnaround},
+		{"Throughput", "%.2f/t", ideal.Throughput, m.Throughput},
+		{"Utilization", "%.0f%%", 100 * ideal.Utilization, 100 * m.Utilization},
+		{"Makespan", "%g", ideal.Makespan, m.Makespan},
+	}
+	if m.Energy > 0 {
+		rows = append(rows, line{"Energy", "%.2f", ideal.Energy, m.Energy})
+	}
+	text := make([][]string, len(rows))
+	for k, row := range rows {
+		change := ""
+		if row.ideal != 0 {
+			change = fmt.Sprintf("%+.0f%%", 100*(row.got-row.ideal)/row.ideal)
+		}
+		text[k] = []string{row.name, fmt.Sprintf(row.format, row.ideal), fmt.Sprintf(row.format, row.got), change}
+	}
+
+	return text
+}
+
+// outputOverhead writes how the metrics of a schedule compare with its
+// ideal metrics, and the total overhead.
+func outputOverhead(w io.Writer, rows [][]string, overhead float64) {
+	_, _ = fmt.Fprintln(w, "Scheduling overhead")
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"Metric", "Ideal", "With overhead", "Change"})
+	table.SetColumnAlignment([]int{tablewriter.ALIGN_LEFT, tablewriter.ALIGN_RIGHT, tablewriter.ALIGN_RIGHT, tablewriter.ALIGN_RIGHT})
+	table.AppendBulk(rows)
+	table.SetFooter([]string{"", "", "Overhead", fmt.Sprintf("%g", overhead)})
+	table.Render()
+}
+
+func outputSchedule(w io.Writer, rows [][]string, wait, turnaround, throughput float64) {
+	_, _ = fmt.Fprintln(w, "Schedule table")
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"ID", "Priority", "Burst", "Arrival", "Wait", "Turnaround", "Exit"})
+	table.AppendBulk(rows)
+	table.SetFooter([]string{"", "", "", "",
+		fmt.Sprintf("Average\n%.2f", wait),
+		fmt.Sprintf("Average\n%.2f", turnaround),
+		fmt.Sprintf("Throughput\n%.2f/t", throughput)})
+	table.Render()
+}
+
+//endregion
@@ -0,0 +1,192 @@
+package scheduler
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func testResult() *ScheduleResult {
+	seed := int64(7)
+	return &ScheduleResult{
+		Seed:  &seed,
+		Gantt: []TimeSlice{{PID: 1, Start: 0, Stop: 5}, {PID: 2, Start: 5, Stop: 14}},
+		Rows: []Row{
+			{ProcessID: 1, Priority: 2, BurstDuration: 5, ArrivalTime: 0, Wait: 0, Turnaround: 5, Exit: 5},
+			{ProcessID: 2, Priority: 1, BurstDuration: 9, ArrivalTime: 3, Wait: 2, Turnaround: 11, Exit: 14},
+		},
+		Metrics: Metrics{AverageWait: 1, AverageTurnaround: 8, Throughput: 2.0 / 14, Completed: 2, Makespan: 14},
+		Total:   2,
+	}
+}
+
+func TestRenderJSON(t *testing.T) {
+	t.Parallel()
+	var w bytes.Buffer
+	if err := RenderJSON(&w, "FCFS", testResult()); err != nil {
+		t.Fatalf("RenderJSON() error = %v", err)
+	}
+	var got struct {
+		Title string `json:"title"`
+		ScheduleResult
+	}
+	if err := json.Unmarshal(w.Bytes(), &got); err != nil {
+		t.Fatalf("RenderJSON() wrote invalid JSON: %v\n%s", err, w.String())
+	}
+	if got.Title != "FCFS" || !reflect.DeepEqual(&got.ScheduleResult, testResult()) {
+		t.Errorf("RenderJSON() round trip = %+v, want %+v", got.ScheduleResult, testResult())
+	}
+}
+
+func TestRenderHTML(t *testing.T) {
+	t.Parallel()
+	var w bytes.Buffer
+	if err := RenderHTML(&w, "<FCFS>", testResult()); err != nil {
+		t.Fatalf("RenderHTML() error = %v", err)
+	}
+	for _, want := range []string{"<h2>&lt;FCFS&gt;</h2>", "<p>Seed: 7</p>", `style="flex:9;`, "<td>11</td>", "Average 8.00"} {
+		if !strings.Contains(w.String(), want) {
+			t.Errorf("RenderHTML() is missing %q:\n%s", want, w.String())
+		}
+	}
+}
+
+func TestRender_ticksPerUnit(t *testing.T) {
+	t.Parallel()
+	r := testResult()
+	r.TicksPerUnit = 10
+	var text, html bytes.Buffer
+	if err := RenderText(&text, "FCFS", r); err != nil {
+		t.Fatalf("RenderText() error = %v", err)
+	}
+	for _, want := range []string{"0\t0.5\t1.4", "|  2 |        1 |   0.9 |     0.3 |     0.2 |        1.1 |        1.4 |", "0.10", "1.43/T"} {
+		if !strings.Contains(text.String(), want) {
+			t.Errorf("RenderText() is missing %q:\n%s", want, text.String())
+		}
+	}
+	if err := RenderHTML(&html, "FCFS", r); err != nil {
+		t.Fatalf("RenderHTML() error = %v", err)
+	}
+	for _, want := range []string{`title="0.5-1.4"`, "<td>1.1</td>", "Average 0.80", "Throughput 1.43/t"} {
+		if !strings.Contains(html.String(), want) {
+			t.Errorf("RenderHTML() is missing %q:\n%s", want, html.String())
+		}
+	}
+}
+
+func TestRender_overhead(t *testing.T) {
+	t.Parallel()
+	r := testResult()
+	ideal := r.Metrics
+	ideal.AverageWait, ideal.Utilization = 0.5, 1
+	r.Metrics.Utilization, r.Metrics.Overhead = 0.8, 3
+	r.Ideal = &ideal
+	var text, html bytes.Buffer
+	if err := RenderText(&text, "FCFS", r); err != nil {
+		t.Fatalf("RenderText() error = %v", err)
+	}
+	for _, want := range []string{"Scheduling overhead", "| Average wait       |   0.50 |          1.00 |  +100% |", "| Utilization        |   100% |           80% |   -20% |", "|   3    |"} {
+		if !strings.Contains(text.String(), want) {
+			t.Errorf("RenderText() is missing %q:\n%s", want, text.String())
+		}
+	}
+	if err := RenderHTML(&html, "FCFS", r); err != nil {
+		t.Fatalf("RenderHTML() error = %v", err)
+	}
+	if want := "<tr><td>Utilization</td><td>100%</td><td>80%</td><td>-20%</td></tr>"; !strings.Contains(html.String(), want) {
+		t.Errorf("RenderHTML() is missing %q:\n%s", want, html.String())
+	}
+}
+
+func TestRenderText_unfinished(t *testing.T) {
+	t.Parallel()
+	r := &ScheduleResult{
+		Truncated:  true,
+		Total:      2,
+		Metrics:    Metrics{Completed: 1, Makespan: 8},
+		Unfinished: []Unfinished{{ProcessID: 2, Priority: 1, BurstDuration: 9, ArrivalTime: 3, Remaining: 6, State: StateRunning}},
+	}
+	var w bytes.Buffer
+	if err := RenderText(&w, "t", r); err != nil {
+		t.Fatalf("RenderText() error = %v", err)
+	}
+	for _, want := range []string{"t (stopped at 8, 1 of 2 processes completed)", "Unfinished processes", "|  2 |        1 |     9 |       3 |         6 | running |"} {
+		if !strings.Contains(w.String(), want) {
+			t.Errorf("RenderText() is missing %q:\n%s", want, w.String())
+		}
+	}
+}
+
+func Test_textRows(t *testing.T) {
+	// Not parallel, so AllocsPerRun counts only textRows.
+	rows := []Row{
+		{ProcessID: 1, Priority: 2, BurstDuration: 25, ArrivalTime: 0, Wait: 5, Turnaround: 30, Exit: 30, Killed: true},
+		{ProcessID: 7, Task: 2, Job: 3, BurstDuration: 10, ArrivalTime: 15, Turnaround: 10, Exit: 25},
+	}
+	want := [][]string{
+		{"1", "2", "2.5", "0", "0.5", "3", "3 (killed)"},
+		{"7 (2#3)", "0", "1", "1.5", "0", "1", "2.5"},
+	}
+	if got := textRows(rows, 10); !reflect.DeepEqual(got, want) {
+		t.Errorf("textRows() = %q, want %q", got, want)
+	}
+
+	many := make([]Row, 1000)
+	for i := range many {
+		many[i] = Row{ProcessID: int64(i), BurstDuration: 3, Turnaround: int64(i), Exit: int64(i)}
+	}
+	if allocs := testing.AllocsPerRun(10, func() { textRows(many, 1) }); allocs > 30 {
+		t.Errorf("textRows() of %d rows made %.0f allocations, want at most 30", len(many), allocs)
+	}
+}
+
+func TestRenderTrace(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		r    *ScheduleResult
+		want string
+	}{
+		{
+			name: "one CPU",
+			r:    testResult(),
+			want: "FCFS (seed 7)\n  AAAAABBBBBBBBB\n  A=1 B=2\n\n",
+		},
+		{
+			name: "idle and overhead",
+			r: &ScheduleResult{Gantt: []TimeSlice{
+				{PID: 3, Start: 1, Stop: 3},
+				{Start: 3, Stop: 4, Kind: SliceIdle},
+				{PID: 1, Start: 4, Stop: 5, Kind: SliceSwitch},
+				{PID: 1, Start: 5, Stop: 6},
+				{PID: 3, Start: 6, Stop: 7},
+			}},
+			want: "FCFS\n  .BB.-AB\n  A=1 B=3\n\n",
+		},
+		{
+			name: "CPUs",
+			r: &ScheduleResult{Gantt: []TimeSlice{
+				{PID: 1, Start: 0, Stop: 2},
+				{PID: 2, Start: 0, Stop: 1, CPU: 1},
+				{PID: 1, Start: 2, Stop: 3, Kind: SliceMigrate, CPU: 1},
+				{PID: 1, Start: 3, Stop: 4, CPU: 1},
+			}, TicksPerUnit: 2},
+			want: "FCFS\n  one character per 1/2 of a time unit\n  CPU 0 AA\n  CPU 1 B.-A\n  A=1 B=2\n\n",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var w bytes.Buffer
+			if err := RenderTrace(&w, "FCFS", tt.r); err != nil {
+				t.Fatalf("RenderTrace() error = %v", err)
+			}
+			if w.String() != tt.want {
+				t.Errorf("RenderTrace() =\n%q\nwant\n%q", w.String(), tt.want)
+			}
+		})
+	}
+}
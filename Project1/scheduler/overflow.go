@@ -0,0 +1,83 @@
+package scheduler
+
+import "math"
+
+// maxExact is the largest integer every smaller one of which a float64 holds
+// exactly. Metrics total ticks in float64s, so totals beyond it are rounded.
+const maxExact = 1 << 53
+
+// addTime returns a+b, and whether the sum fits in an int64.
+func addTime(a, b int64) (int64, bool) {
+	s := a + b
+	return s, (s > a) == (b > 0)
+}
+
+// mulTime returns a*b for non-negative a and b, and whether the product
+// fits in an int64.
+func mulTime(a, b int64) (int64, bool) {
+	if a != 0 && b > math.MaxInt64/a {
+		return 0, false
+	}
+	return a * b, true
+}
+
+// absTime returns the magnitude of t, saturating at math.MaxInt64.
+func absTime(t int64) int64 {
+	switch {
+	case t == math.MinInt64:
+		return math.MaxInt64
+	case t < 0:
+		return -t
+	}
+	return t
+}
+
+// horizon bounds when a simulation of processes under opts can end, before
+// scheduling overhead: the last time any process is given, plus the time
+// every burst takes on the slowest CPU and every I/O. It returns false if
+// the bound, or any time the engine derives from the processes on the way,
+// overflows an int64, when the simulation's arithmetic could too.
+func horizon(processes []Process, opts Options) (int64, bool) {
+	slowest := int64(speedScale)
+	for c := 0; c < opts.CPUs; c++ {
+		if s := opts.speed(c); s > 0 && s < slowest {
+			slowest = s
+		}
+	}
+	var last, total int64
+	ok := true
+	add := func(sum *int64, t int64) {
+		var fits bool
+		*sum, fits = addTime(*sum, t)
+		ok = ok && fits
+	}
+	burst := func(cpu int64) {
+		work, fits := mulTime(cpu, speedScale)
+		ok = ok && fits
+		add(&total, work/slowest+1)
+	}
+	for _, p := range processes {
+		times := []int64{p.ArrivalTime, p.KillAt}
+		for _, s := range p.Suspensions {
+			times = append(times, s.To)
+		}
+		for _, d := range []int64{p.Deadline, p.Period} {
+			at, fits := addTime(p.ArrivalTime, d)
+			ok = ok && fits
+			times = append(times, at)
+		}
+		for _, t := range times {
+			if t > last {
+				last = t
+			}
+		}
+		burst(p.BurstDuration)
+		for _, c := range p.Cycles {
+			add(&total, c.IO)
+			burst(c.CPU)
+		}
+	}
+	add(&total, last)
+
+	return total, ok
+}
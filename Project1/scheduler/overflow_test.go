@@ -0,0 +1,83 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+)
+
+func Test_addTime(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		a, b int64
+		ok   bool
+	}{
+		{1, 2, true},
+		{math.MaxInt64, 0, true},
+		{math.MaxInt64, 1, false},
+		{math.MinInt64, -1, false},
+		{math.MinInt64, math.MaxInt64, true},
+	}
+	for _, tt := range tests {
+		if _, ok := addTime(tt.a, tt.b); ok != tt.ok {
+			t.Errorf("addTime(%d, %d) ok = %v, want %v", tt.a, tt.b, ok, tt.ok)
+		}
+	}
+	if got, ok := mulTime(math.MaxInt64/speedScale, speedScale); !ok || got != math.MaxInt64/speedScale*speedScale {
+		t.Errorf("mulTime() = %d, %v, want the exact product", got, ok)
+	}
+	if _, ok := mulTime(math.MaxInt64/speedScale+1, speedScale); ok {
+		t.Error("mulTime() of an overflowing product reported it fits")
+	}
+}
+
+func TestOverflowingTimes(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name      string
+		processes []Process
+	}{
+		{name: "burst", processes: []Process{{ProcessID: 1, BurstDuration: math.MaxInt64 / 10}}},
+		{name: "io", processes: []Process{{ProcessID: 1, BurstDuration: 1, Cycles: []Cycle{
+			{IO: math.MaxInt64 / 2, CPU: 1},
+			{IO: math.MaxInt64 / 2, CPU: 1},
+		}}}},
+		{name: "late arrival", processes: []Process{{ProcessID: 1, ArrivalTime: math.MaxInt64 - 5, BurstDuration: 10}}},
+		{name: "deadline", processes: []Process{{ProcessID: 1, ArrivalTime: 1 << 62, BurstDuration: 1, Deadline: 1 << 62}}},
+	}
+	for _, tt := range tests {
+		if _, err := NewFCFS().Schedule(context.Background(), tt.processes); !errors.Is(err, ErrValidation) {
+			t.Errorf("%s: Schedule() error = %v, want %v", tt.name, err, ErrValidation)
+		}
+	}
+
+	if _, _, err := LoadProcessesFractional(strings.NewReader("1,92233720368547758,0.5,1")); !errors.Is(err, ErrParse) {
+		t.Errorf("LoadProcessesFractional() of a time overflowing in ticks error = %v, want %v", err, ErrParse)
+	}
+}
+
+func TestImpreciseMetrics(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: 1, BurstDuration: 3},
+		{ProcessID: 2, ArrivalTime: 1 << 54, BurstDuration: 3},
+	}
+	r, err := NewFCFS().Schedule(context.Background(), processes)
+	if err != nil {
+		t.Fatalf("Schedule() error = %v", err)
+	}
+	if !r.Metrics.Imprecise {
+		t.Errorf("Imprecise = false with a makespan of %g", r.Metrics.Makespan)
+	}
+	var w strings.Builder
+	if err := RenderText(&w, "t", r); err != nil || !strings.Contains(w.String(), "Warning: totals exceed 2^53 ticks") {
+		t.Errorf("RenderText() = %v, want a precision warning:\n%s", err, w.String())
+	}
+
+	r, err = NewFCFS().Schedule(context.Background(), processes[:1])
+	if err != nil || r.Metrics.Imprecise {
+		t.Errorf("Schedule() of a short workload = %+v, %v, want precise metrics", r.Metrics, err)
+	}
+}
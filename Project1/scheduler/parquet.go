@@ -0,0 +1,375 @@
+package scheduler
+
+import (
+	"encoding/binary"
+	"io"
+	"math"
+)
+
+// ParquetTable chooses the table a ParquetWriter writes.
+type ParquetTable int
+
+const (
+	// ParquetProcesses is a row per process per schedule, with the columns
+	// CSVHeader names.
+	ParquetProcesses ParquetTable = iota
+	// ParquetSlices is a row per Gantt slice per schedule: the schedule,
+	// seed, process ID, CPU, kind of slice, and start and stop.
+	ParquetSlices
+)
+
+// parquetRowGroup is how many rows a ParquetWriter buffers before writing
+// them as a row group, which bounds its memory however many schedules it
+// is given.
+const parquetRowGroup = 1 << 16
+
+// Parquet's physical types, and the encodings used.
+const (
+	parquetBoolean   = 0
+	parquetInt64     = 2
+	parquetDouble    = 5
+	parquetByteArray = 6
+
+	parquetPlain = 0
+	parquetRLE   = 3
+)
+
+// ParquetWriter writes the results of schedules as one Parquet file, which
+// DuckDB, Spark, and pandas query in place: uncompressed, PLAIN-encoded
+// columns with times in time units, in row groups of up to 65536 rows. The
+// file is only complete once Close writes its footer.
+type ParquetWriter struct {
+	w       io.Writer
+	table   ParquetTable
+	columns []*parquetColumn
+	rows    int64
+	offset  int64
+	groups  [][]byte
+	total   int64
+	err     error
+}
+
+// NewParquetWriter returns a ParquetWriter writing table to w.
+func NewParquetWriter(w io.Writer, table ParquetTable) *ParquetWriter {
+	p := &ParquetWriter{w: w, table: table}
+	names := CSVHeader
+	if table == ParquetSlices {
+		names = []string{"schedule", "seed", "pid", "cpu", "kind", "start", "stop"}
+	}
+	for _, name := range names {
+		c := &parquetColumn{name: name, typ: parquetDouble}
+		switch name {
+		case "schedule", "kind":
+			c.typ = parquetByteArray
+		case "seed":
+			c.typ, c.optional = parquetInt64, true
+		case "pid", "priority", "cpu":
+			c.typ = parquetInt64
+		case "killed", "excluded", "missed":
+			c.typ = parquetBoolean
+		}
+		p.columns = append(p.columns, c)
+	}
+
+	return p
+}
+
+// Add adds the rows of r, titled title, writing a row group when enough
+// rows have been added.
+func (p *ParquetWriter) Add(title string, r *ScheduleResult) error {
+	if p.err != nil {
+		return p.err
+	}
+	u := r.ticksPerUnit()
+	seed := func(c *parquetColumn) {
+		if r.Seed == nil {
+			c.null()
+		} else {
+			c.int(*r.Seed)
+		}
+	}
+	add := func(values ...func(c *parquetColumn)) {
+		for k, v := range values {
+			v(p.columns[k])
+		}
+		if p.rows++; p.rows == parquetRowGroup {
+			p.flush()
+		}
+	}
+	str := func(s string) func(*parquetColumn) { return func(c *parquetColumn) { c.str(s) } }
+	num := func(n int64) func(*parquetColumn) { return func(c *parquetColumn) { c.int(n) } }
+	tim := func(t int64) func(*parquetColumn) {
+		return func(c *parquetColumn) { c.double(float64(t) / u) }
+	}
+	flag := func(b bool) func(*parquetColumn) { return func(c *parquetColumn) { c.bool(b) } }
+	if p.table == ParquetSlices {
+		for _, ts := range r.Gantt {
+			kind := string(ts.Kind)
+			if ts.Kind == SliceRun {
+				kind = "run"
+			}
+			add(str(title), seed, num(ts.PID), num(int64(ts.CPU)), str(kind), tim(ts.Start), tim(ts.Stop))
+		}
+		return p.err
+	}
+	for _, row := range r.Rows {
+		add(str(title), seed, num(row.ProcessID), num(row.Priority),
+			tim(row.ArrivalTime), tim(row.BurstDuration), tim(row.IO), tim(row.Wait), tim(row.Turnaround), tim(row.Exit),
+			tim(row.Residency.Ready), tim(row.Residency.Running), tim(row.Residency.Blocked),
+			flag(row.Killed), flag(row.Excluded), flag(row.Missed))
+	}
+
+	return p.err
+}
+
+// Close writes the rows not yet written and the file's footer. It does not
+// close the underlying writer.
+func (p *ParquetWriter) Close() error {
+	if p.rows > 0 || p.offset == 0 {
+		p.flush()
+	}
+	if p.err != nil {
+		return p.err
+	}
+	var schema [][]byte
+	var root thriftStruct
+	root.str(4, "schema")
+	root.i32(5, int32(len(p.columns)))
+	schema = append(schema, root.end())
+	for _, c := range p.columns {
+		var e thriftStruct
+		e.i32(1, c.typ)
+		if c.optional {
+			e.i32(3, 1)
+		} else {
+			e.i32(3, 0)
+		}
+		e.str(4, c.name)
+		if c.typ == parquetByteArray {
+			e.i32(6, 0) // UTF8
+		}
+		schema = append(schema, e.end())
+	}
+	var meta thriftStruct
+	meta.i32(1, 1)
+	meta.list(2, thriftStructType, schema)
+	meta.i64(3, p.total)
+	meta.list(4, thriftStructType, p.groups)
+	meta.str(6, "CSCE4600 scheduler")
+	footer := meta.end()
+	footer = binary.LittleEndian.AppendUint32(footer, uint32(len(footer)))
+	p.write(append(footer, "PAR1"...))
+
+	return p.err
+}
+
+// flush writes the buffered rows as a row group of a page per column,
+// after the file's magic number if it is the first.
+func (p *ParquetWriter) flush() {
+	if p.offset == 0 {
+		p.write([]byte("PAR1"))
+	}
+	if p.rows == 0 {
+		return
+	}
+	var chunks [][]byte
+	var size int64
+	for _, c := range p.columns {
+		page := c.page()
+		var dph thriftStruct
+		dph.i32(1, int32(c.n))
+		dph.i32(2, parquetPlain)
+		dph.i32(3, parquetRLE)
+		dph.i32(4, parquetRLE)
+		var header thriftStruct
+		header.i32(1, 0) // DATA_PAGE
+		header.i32(2, int32(len(page)))
+		header.i32(3, int32(len(page)))
+		header.structField(5, dph.end())
+		data := append(header.end(), page...)
+
+		var cmd thriftStruct
+		cmd.i32(1, c.typ)
+		cmd.list(2, thriftI32Type, [][]byte{thriftInt(parquetPlain), thriftInt(parquetRLE)})
+		cmd.list(3, thriftBinaryType, [][]byte{thriftBinary(c.name)})
+		cmd.i32(4, 0) // UNCOMPRESSED
+		cmd.i64(5, int64(c.n))
+		cmd.i64(6, int64(len(data)))
+		cmd.i64(7, int64(len(data)))
+		cmd.i64(9, p.offset)
+		var chunk thriftStruct
+		chunk.i64(2, p.offset)
+		chunk.structField(3, cmd.end())
+		chunks = append(chunks, chunk.end())
+
+		size += int64(len(data))
+		p.write(data)
+		c.reset()
+	}
+	var group thriftStruct
+	group.list(1, thriftStructType, chunks)
+	group.i64(2, size)
+	group.i64(3, p.rows)
+	p.groups = append(p.groups, group.end())
+	p.total += p.rows
+	p.rows = 0
+}
+
+func (p *ParquetWriter) write(b []byte) {
+	if p.err != nil {
+		return
+	}
+	n, err := p.w.Write(b)
+	p.offset += int64(n)
+	p.err = err
+}
+
+// parquetColumn buffers the values of a column of the row group being
+// written, PLAIN-encoded, and which of them are null.
+type parquetColumn struct {
+	name     string
+	typ      int32
+	optional bool
+	values   []byte
+	// present is the definition level of each value of an optional
+	// column: whether it is not null.
+	present []bool
+	// n counts the values, and bits the booleans packed into values.
+	n, bits int
+}
+
+func (c *parquetColumn) int(v int64) {
+	c.values = binary.LittleEndian.AppendUint64(c.values, uint64(v))
+	c.added(true)
+}
+
+func (c *parquetColumn) double(v float64) {
+	c.values = binary.LittleEndian.AppendUint64(c.values, math.Float64bits(v))
+	c.added(true)
+}
+
+func (c *parquetColumn) str(s string) {
+	c.values = binary.LittleEndian.AppendUint32(c.values, uint32(len(s)))
+	c.values = append(c.values, s...)
+	c.added(true)
+}
+
+// bool packs v into the values a bit at a time, from the lowest bit.
+func (c *parquetColumn) bool(v bool) {
+	if c.bits%8 == 0 {
+		c.values = append(c.values, 0)
+	}
+	if v {
+		c.values[len(c.values)-1] |= 1 << (c.bits % 8)
+	}
+	c.bits++
+	c.added(true)
+}
+
+func (c *parquetColumn) null() { c.added(false) }
+
+func (c *parquetColumn) added(present bool) {
+	if c.optional {
+		c.present = append(c.present, present)
+	}
+	c.n++
+}
+
+// page returns the column's data page: for an optional column, the
+// definition levels, run-length encoded after their length, and then the
+// values.
+func (c *parquetColumn) page() []byte {
+	if !c.optional {
+		return c.values
+	}
+	var levels []byte
+	for k := 0; k < len(c.present); {
+		run := 1
+		for k+run < len(c.present) && c.present[k+run] == c.present[k] {
+			run++
+		}
+		levels = binary.AppendUvarint(levels, uint64(run)<<1)
+		if c.present[k] {
+			levels = append(levels, 1)
+		} else {
+			levels = append(levels, 0)
+		}
+		k += run
+	}
+	page := binary.LittleEndian.AppendUint32(make([]byte, 0, 4+len(levels)+len(c.values)), uint32(len(levels)))
+	return append(append(page, levels...), c.values...)
+}
+
+func (c *parquetColumn) reset() {
+	c.values, c.present, c.n, c.bits = c.values[:0], c.present[:0], 0, 0
+}
+
+// Types of Thrift compact protocol fields.
+const (
+	thriftI32Type    = 5
+	thriftI64Type    = 6
+	thriftBinaryType = 8
+	thriftListType   = 9
+	thriftStructType = 12
+)
+
+// thriftStruct encodes a struct in the Thrift compact protocol, in which
+// Parquet's metadata is written. Fields must be added in increasing order.
+type thriftStruct struct {
+	b    []byte
+	last int
+}
+
+func (s *thriftStruct) field(id int, typ byte) {
+	if delta := id - s.last; delta > 0 && delta <= 15 {
+		s.b = append(s.b, byte(delta)<<4|typ)
+	} else {
+		s.b = binary.AppendVarint(append(s.b, typ), int64(id))
+	}
+	s.last = id
+}
+
+func (s *thriftStruct) i32(id int, v int32) {
+	s.field(id, thriftI32Type)
+	s.b = binary.AppendVarint(s.b, int64(v))
+}
+
+func (s *thriftStruct) i64(id int, v int64) {
+	s.field(id, thriftI64Type)
+	s.b = binary.AppendVarint(s.b, v)
+}
+
+func (s *thriftStruct) str(id int, v string) {
+	s.field(id, thriftBinaryType)
+	s.b = append(s.b, thriftBinary(v)...)
+}
+
+// structField adds a struct encoded by end.
+func (s *thriftStruct) structField(id int, v []byte) {
+	s.field(id, thriftStructType)
+	s.b = append(s.b, v...)
+}
+
+// list adds a list of elements of type typ, each already encoded.
+func (s *thriftStruct) list(id int, typ byte, elems [][]byte) {
+	s.field(id, thriftListType)
+	if len(elems) < 15 {
+		s.b = append(s.b, byte(len(elems))<<4|typ)
+	} else {
+		s.b = binary.AppendUvarint(append(s.b, 0xf0|typ), uint64(len(elems)))
+	}
+	for _, e := range elems {
+		s.b = append(s.b, e...)
+	}
+}
+
+// end returns the struct, ended by its stop field.
+func (s *thriftStruct) end() []byte { return append(s.b, 0) }
+
+// thriftInt encodes an i32 list element.
+func thriftInt(v int32) []byte { return binary.AppendVarint(nil, int64(v)) }
+
+// thriftBinary encodes a string list element.
+func thriftBinary(v string) []byte {
+	return append(binary.AppendUvarint(nil, uint64(len(v))), v...)
+}
@@ -0,0 +1,148 @@
+package scheduler
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestParquetWriter(t *testing.T) {
+	t.Parallel()
+	r := testResult()
+	r.TicksPerUnit = 2
+	r.Rows[1].Killed = true
+	unseeded := testResult()
+	unseeded.Seed = nil
+	tests := []struct {
+		table   ParquetTable
+		columns []string
+		rows    int64
+		// column and its values, PLAIN-encoded, in the first row group.
+		column string
+		want   []byte
+	}{
+		{table: ParquetProcesses, columns: CSVHeader, rows: 4, column: "wait", want: parquetDoubles(0, 1, 0, 2)},
+		{table: ParquetSlices, columns: []string{"schedule", "seed", "pid", "cpu", "kind", "start", "stop"}, rows: 4, column: "start", want: parquetDoubles(0, 2.5, 0, 5)},
+		{table: ParquetProcesses, columns: CSVHeader, rows: 4, column: "killed", want: []byte{0b0010}},
+		// Seeds are optional: the definition levels, a run of 2 seeds and
+		// then of 2 nulls, precede the values of those present.
+		{table: ParquetProcesses, columns: CSVHeader, rows: 4, column: "seed", want: append([]byte{4, 0, 0, 0, 4, 1, 4, 0}, parquetInts(7, 7)...)},
+	}
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		p := NewParquetWriter(&buf, tt.table)
+		if err := p.Add("FCFS", r); err != nil {
+			t.Fatalf("Add() error = %v", err)
+		}
+		if err := p.Add("RR", unseeded); err != nil {
+			t.Fatalf("Add() error = %v", err)
+		}
+		if err := p.Close(); err != nil {
+			t.Fatalf("Close() error = %v", err)
+		}
+		b := buf.Bytes()
+		if !bytes.HasPrefix(b, []byte("PAR1")) || !bytes.HasSuffix(b, []byte("PAR1")) {
+			t.Fatalf("ParquetWriter wrote % x, want a file between PAR1 magic numbers", b)
+		}
+		size := binary.LittleEndian.Uint32(b[len(b)-8:])
+		meta, _ := readThrift(t, b[len(b)-8-int(size):len(b)-8])
+		if meta[3] != int64(tt.rows) {
+			t.Errorf("ParquetWriter num_rows = %v, want %d", meta[3], tt.rows)
+		}
+		var names []string
+		for _, e := range meta[2].([]interface{})[1:] {
+			names = append(names, e.(map[int16]interface{})[4].(string))
+		}
+		if !reflect.DeepEqual(names, tt.columns) {
+			t.Errorf("ParquetWriter schema = %q, want %q", names, tt.columns)
+		}
+		group := meta[4].([]interface{})[0].(map[int16]interface{})
+		for _, c := range group[1].([]interface{}) {
+			cmd := c.(map[int16]interface{})[3].(map[int16]interface{})
+			if cmd[3].([]interface{})[0] != tt.column {
+				continue
+			}
+			offset := cmd[9].(int64)
+			header, page := readThrift(t, b[offset:])
+			if size := header[2].(int64); int(size) <= len(page) {
+				page = page[:size]
+			}
+			if !bytes.Equal(page, tt.want) {
+				t.Errorf("ParquetWriter %s page = % x, want % x", tt.column, page, tt.want)
+			}
+		}
+	}
+}
+
+func parquetDoubles(v ...float64) []byte {
+	var b []byte
+	for _, f := range v {
+		b = binary.LittleEndian.AppendUint64(b, math.Float64bits(f))
+	}
+	return b
+}
+
+func parquetInts(v ...int64) []byte {
+	var b []byte
+	for _, n := range v {
+		b = binary.LittleEndian.AppendUint64(b, uint64(n))
+	}
+	return b
+}
+
+// readThrift decodes the Thrift compact protocol struct at the start of b
+// by field ID, with integers as int64, and returns the rest of b.
+func readThrift(t *testing.T, b []byte) (map[int16]interface{}, []byte) {
+	t.Helper()
+	fields := make(map[int16]interface{})
+	var last int16
+	for {
+		if len(b) == 0 {
+			t.Fatal("readThrift() of a truncated struct")
+		}
+		c := b[0]
+		b = b[1:]
+		if c == 0 {
+			return fields, b
+		}
+		if delta := int16(c >> 4); delta != 0 {
+			last += delta
+		} else {
+			id, n := binary.Varint(b)
+			last, b = int16(id), b[n:]
+		}
+		fields[last], b = readThriftValue(t, c&0x0f, b)
+	}
+}
+
+func readThriftValue(t *testing.T, typ byte, b []byte) (interface{}, []byte) {
+	t.Helper()
+	switch typ {
+	case 1, 2:
+		return typ == 1, b
+	case thriftI32Type, thriftI64Type:
+		v, n := binary.Varint(b)
+		return v, b[n:]
+	case thriftBinaryType:
+		size, n := binary.Uvarint(b)
+		return string(b[n : n+int(size)]), b[n+int(size):]
+	case thriftListType:
+		size, elem := int(b[0]>>4), b[0]&0x0f
+		b = b[1:]
+		if size == 15 {
+			n, k := binary.Uvarint(b)
+			size, b = int(n), b[k:]
+		}
+		list := make([]interface{}, size)
+		for i := range list {
+			list[i], b = readThriftValue(t, elem, b)
+		}
+		return list, b
+	case thriftStructType:
+		return readThrift(t, b)
+	}
+	t.Fatalf("readThrift() of unknown type %d", typ)
+	return nil, nil
+}
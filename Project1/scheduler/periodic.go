@@ -0,0 +1,205 @@
+package scheduler
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/olekukonko/tablewriter"
+)
+
+// maxJobs is the most jobs ExpandPeriodic releases, so a workload whose
+// periods have a huge least common multiple is rejected rather than
+// simulated for hours.
+const maxJobs = 100000
+
+// Task summarizes the jobs of one periodic task.
+type Task struct {
+	Task   int64 `json:"task"`
+	Period int64 `json:"period"`
+	// WCET is the CPU time of each job, and Deadline their relative
+	// deadline.
+	WCET     int64 `json:"wcet"`
+	Deadline int64 `json:"deadline"`
+	// Jobs is the number of jobs released, Completed how many completed,
+	// and DeadlineMisses how many completed late or were killed.
+	Jobs           int `json:"jobs"`
+	Completed      int `json:"completed"`
+	DeadlineMisses int `json:"deadline_misses,omitempty"`
+	// MaxResponse and AverageResponse are the worst and mean times from a
+	// job's release to its completion.
+	MaxResponse     int64   `json:"max_response"`
+	AverageResponse float64 `json:"average_response"`
+}
+
+// ExpandPeriodic returns processes with every periodic task, a process with
+// a period, replaced by its jobs: one released every period from its
+// arrival time, over one hyperperiod (the least common multiple of the
+// periods) after the last task's first release. Jobs take new process IDs
+// after the highest one in processes, and record their task and 1-based
+// job number. Processes without a period are kept as they are.
+//
+// The processes must already be valid. A periodic task cannot be killed,
+// suspended, or spawned, nor spawn children, and ExpandPeriodic fails if
+// the tasks would release more than 100000 jobs, or their hyperperiod would
+// end past the largest time.
+func ExpandPeriodic(processes []Process) ([]Process, error) {
+	var hyperperiod, last, next int64
+	parents := make(map[int64]bool)
+	for _, p := range processes {
+		if p.Parent != 0 {
+			parents[p.Parent] = true
+		}
+		if p.ProcessID >= next {
+			next = p.ProcessID + 1
+		}
+	}
+	for i, p := range processes {
+		if p.Period == 0 {
+			continue
+		}
+		var err error
+		switch {
+		case p.KillAt != 0 || len(p.Suspensions) > 0:
+			err = errors.New("periodic task cannot be killed or suspended")
+		case p.Parent != 0 || parents[p.ProcessID]:
+			err = errors.New("periodic task cannot be spawned or spawn children")
+		}
+		if err != nil {
+			return nil, &ValidationError{Row: i + 1, Err: err}
+		}
+		if hyperperiod == 0 {
+			hyperperiod = p.Period
+		} else if hyperperiod = lcm(hyperperiod, p.Period); hyperperiod < 0 {
+			return nil, &ValidationError{Row: i + 1, Err: errors.New("hyperperiod overflows")}
+		}
+		if p.ArrivalTime > last {
+			last = p.ArrivalTime
+		}
+	}
+	if hyperperiod == 0 {
+		return processes, nil
+	}
+	if last > math.MaxInt64-hyperperiod {
+		return nil, &ValidationError{Err: fmt.Errorf("hyperperiod of %d after the first release at %d overflows", hyperperiod, last)}
+	}
+	end := last + hyperperiod
+	// released is how many jobs p releases before end, counted so as not
+	// to overflow near it.
+	released := func(p Process) int64 { return (end-p.ArrivalTime-1)/p.Period + 1 }
+
+	jobs := 0
+	for _, p := range processes {
+		if p.Period > 0 {
+			jobs += int(released(p))
+		}
+		if jobs > maxJobs {
+			return nil, &ValidationError{Err: fmt.Errorf("periodic tasks release more than %d jobs over the hyperperiod of %d", maxJobs, hyperperiod)}
+		}
+	}
+	expanded := make([]Process, 0, len(processes)+jobs)
+	for _, p := range processes {
+		if p.Period == 0 {
+			expanded = append(expanded, p)
+			continue
+		}
+		for k := int64(0); k < released(p); k++ {
+			job := p
+			job.ProcessID, job.ArrivalTime = next, p.ArrivalTime+k*p.Period
+			job.Task, job.Job = p.ProcessID, int(k)+1
+			expanded = append(expanded, job)
+			next++
+		}
+	}
+
+	return expanded, nil
+}
+
+// lcm is the least common multiple of positive a and b, or -1 if it
+// overflows.
+func lcm(a, b int64) int64 {
+	x, y := a, b
+	for y != 0 {
+		x, y = y, x%y
+	}
+	if a/x > math.MaxInt64/b {
+		return -1
+	}
+	return a / x * b
+}
+
+// tasks summarizes the jobs of every periodic task among processes, in the
+// order of their first jobs, from the rows of those that finished.
+func tasks(processes []Process, rows []Row) []Task {
+	finished := make(map[int64]*Row, len(rows))
+	for k := range rows {
+		finished[rows[k].ProcessID] = &rows[k]
+	}
+	var ts []Task
+	index := make(map[int64]int)
+	for _, p := range processes {
+		if p.Job == 0 {
+			continue
+		}
+		k, ok := index[p.Task]
+		if !ok {
+			k = len(ts)
+			index[p.Task] = k
+			ts = append(ts, Task{Task: p.Task, Period: p.Period, WCET: p.CPUTime(), Deadline: p.deadline() - p.ArrivalTime})
+		}
+		t := &ts[k]
+		t.Jobs++
+		row := finished[p.ProcessID]
+		if row == nil {
+			continue
+		}
+		if row.Missed {
+			t.DeadlineMisses++
+		}
+		if row.Killed {
+			continue
+		}
+		t.Completed++
+		t.AverageResponse += float64(row.Turnaround)
+		if row.Turnaround > t.MaxResponse {
+			t.MaxResponse = row.Turnaround
+		}
+	}
+	for k := range ts {
+		if ts[k].Completed > 0 {
+			ts[k].AverageResponse /= float64(ts[k].Completed)
+		}
+	}
+
+	return ts
+}
+
+// outputTasks writes the response times of each periodic task, and how the
+// tasks' utilization compares with the rate-monotonic and EDF bounds, if
+// there are any.
+func outputTasks(w io.Writer, ts []Task, u float64) {
+	if len(ts) == 0 {
+		return
+	}
+	_, _ = fmt.Fprintln(w, "Periodic tasks")
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"Task", "Period", "WCET", "Deadline", "Jobs", "Deadline misses", "Max response", "Average response"})
+	var utilization float64
+	for _, t := range ts {
+		utilization += float64(t.WCET) / float64(t.Period)
+		table.Append([]string{
+			fmt.Sprint(t.Task),
+			unitTime(t.Period, u),
+			unitTime(t.WCET, u),
+			unitTime(t.Deadline, u),
+			fmt.Sprint(t.Jobs),
+			fmt.Sprint(t.DeadlineMisses),
+			unitTime(t.MaxResponse, u),
+			fmt.Sprintf("%.2f", t.AverageResponse/u),
+		})
+	}
+	table.Render()
+	n := float64(len(ts))
+	_, _ = fmt.Fprintf(w, "Utilization %.2f (rate-monotonic bound %.2f, EDF bound 1)\n\n", utilization, n*(math.Pow(2, 1/n)-1))
+}
@@ -0,0 +1,128 @@
+package scheduler
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestExpandPeriodic(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name      string
+		processes []Process
+		want      []Process
+		wantErr   bool
+	}{
+		{
+			name:      "no periodic tasks",
+			processes: []Process{{ProcessID: 1, BurstDuration: 2}},
+			want:      []Process{{ProcessID: 1, BurstDuration: 2}},
+		},
+		{
+			name: "hyperperiod",
+			processes: []Process{
+				{ProcessID: 1, BurstDuration: 1, Period: 2},
+				{ProcessID: 2, BurstDuration: 1, Period: 3, Deadline: 2},
+				{ProcessID: 7, BurstDuration: 4},
+			},
+			want: []Process{
+				{ProcessID: 8, BurstDuration: 1, Period: 2, Task: 1, Job: 1},
+				{ProcessID: 9, BurstDuration: 1, ArrivalTime: 2, Period: 2, Task: 1, Job: 2},
+				{ProcessID: 10, BurstDuration: 1, ArrivalTime: 4, Period: 2, Task: 1, Job: 3},
+				{ProcessID: 11, BurstDuration: 1, Period: 3, Deadline: 2, Task: 2, Job: 1},
+				{ProcessID: 12, BurstDuration: 1, ArrivalTime: 3, Period: 3, Deadline: 2, Task: 2, Job: 2},
+				{ProcessID: 7, BurstDuration: 4},
+			},
+		},
+		{
+			name: "offset",
+			processes: []Process{
+				{ProcessID: 1, BurstDuration: 1, Period: 2},
+				{ProcessID: 2, BurstDuration: 1, Period: 2, ArrivalTime: 1},
+			},
+			want: []Process{
+				{ProcessID: 3, BurstDuration: 1, Period: 2, Task: 1, Job: 1},
+				{ProcessID: 4, BurstDuration: 1, ArrivalTime: 2, Period: 2, Task: 1, Job: 2},
+				{ProcessID: 5, BurstDuration: 1, ArrivalTime: 1, Period: 2, Task: 2, Job: 1},
+			},
+		},
+		{
+			name:      "killed task",
+			processes: []Process{{ProcessID: 1, BurstDuration: 1, Period: 2, KillAt: 1}},
+			wantErr:   true,
+		},
+		{
+			name:      "too many jobs",
+			processes: []Process{{ProcessID: 1, BurstDuration: 1, Period: 1}, {ProcessID: 2, BurstDuration: 1, Period: 100003}},
+			wantErr:   true,
+		},
+		{
+			name:      "end overflows",
+			processes: []Process{{ProcessID: 1, BurstDuration: 1, Period: 4, ArrivalTime: math.MaxInt64 - 2}},
+			wantErr:   true,
+		},
+		{
+			name:      "end is the largest time",
+			processes: []Process{{ProcessID: 1, BurstDuration: 1, Period: 4, ArrivalTime: math.MaxInt64 - 4}},
+			want:      []Process{{ProcessID: 2, BurstDuration: 1, ArrivalTime: math.MaxInt64 - 4, Period: 4, Task: 1, Job: 1}},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := ExpandPeriodic(tt.processes)
+			if tt.wantErr {
+				if !errors.Is(err, ErrValidation) {
+					t.Errorf("ExpandPeriodic() error = %v, want %v", err, ErrValidation)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ExpandPeriodic() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ExpandPeriodic() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPeriodicTasks(t *testing.T) {
+	t.Parallel()
+	// Under RM task 1 (period 3) always preempts task 2 (period 4), so
+	// task 2's jobs respond in 3, 2, and 3.
+	jobs, err := ExpandPeriodic([]Process{
+		{ProcessID: 1, BurstDuration: 1, Period: 3},
+		{ProcessID: 2, BurstDuration: 2, Period: 4},
+	})
+	if err != nil {
+		t.Fatalf("ExpandPeriodic() error = %v", err)
+	}
+	r, err := NewRM().Schedule(context.Background(), jobs)
+	if err != nil {
+		t.Fatalf("Schedule() error = %v", err)
+	}
+	want := []Task{
+		{Task: 1, Period: 3, WCET: 1, Deadline: 3, Jobs: 4, Completed: 4, MaxResponse: 1, AverageResponse: 1},
+		{Task: 2, Period: 4, WCET: 2, Deadline: 4, Jobs: 3, Completed: 3, MaxResponse: 3, AverageResponse: 8.0 / 3},
+	}
+	if !reflect.DeepEqual(r.Tasks, want) {
+		t.Errorf("Tasks = %+v, want %+v", r.Tasks, want)
+	}
+
+	var out bytes.Buffer
+	if err := RenderText(&out, "rm", r); err != nil {
+		t.Fatalf("RenderText() error = %v", err)
+	}
+	for _, s := range []string{"Periodic tasks", "Utilization 0.83 (rate-monotonic bound 0.83, EDF bound 1)", "3 (1#1)"} {
+		if !strings.Contains(out.String(), s) {
+			t.Errorf("RenderText() missing %q in\n%s", s, out.String())
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package scheduler
+
+import "sync"
+
+// ganttPool and rowPool hold Gantt charts and row buffers handed back with
+// ScheduleResult.Release, or left over from merging the charts of several
+// cores, for later simulations to fill instead of growing new ones. They
+// hold pointers to the slices so putting one back does not allocate.
+var (
+	ganttPool sync.Pool
+	rowPool   sync.Pool
+)
+
+// newGantt returns an empty Gantt chart, reusing a pooled one if any.
+func newGantt() []TimeSlice {
+	if p, ok := ganttPool.Get().(*[]TimeSlice); ok {
+		return (*p)[:0]
+	}
+	return make([]TimeSlice, 0)
+}
+
+// putGantt pools gantt for reuse; gantt must not be used afterwards.
+func putGantt(gantt []TimeSlice) {
+	if cap(gantt) > 0 {
+		ganttPool.Put(&gantt)
+	}
+}
+
+// newRows returns an empty row buffer with room for n rows, reusing a
+// pooled one if it is large enough.
+func newRows(n int) []Row {
+	if p, ok := rowPool.Get().(*[]Row); ok {
+		if cap(*p) >= n {
+			return (*p)[:0]
+		}
+		rowPool.Put(p)
+	}
+	return make([]Row, 0, n)
+}
+
+// Release hands r's Gantt chart and rows back to be reused by later
+// simulations, which cuts the steady-state allocations of a service or
+// batch that simulates continuously and keeps only what it reports. r's
+// Gantt and Rows are emptied, and slices taken from them earlier must not
+// be used afterwards. The rest of r is left as it was. Results with a
+// checkpoint, which may still share the chart, are left untouched.
+func (r *ScheduleResult) Release() {
+	if r == nil || r.Checkpoint != nil {
+		return
+	}
+	putGantt(r.Gantt)
+	if cap(r.Rows) > 0 {
+		rows := r.Rows
+		rowPool.Put(&rows)
+	}
+	r.Gantt, r.Rows = nil, nil
+}
@@ -0,0 +1,43 @@
+package scheduler
+
+import (
+	"context"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestRelease(t *testing.T) {
+	t.Parallel()
+	processes := Generate(rand.New(rand.NewSource(5)), 200)
+	for _, opts := range [][]Option{{}, {WithCPUs(3), WithSwitchCost(1)}} {
+		want, err := NewRR(opts...).Schedule(context.Background(), processes)
+		if err != nil {
+			t.Fatalf("Schedule() error = %v", err)
+		}
+		// Later runs fill the buffers released by earlier ones, which must
+		// not leak into their results.
+		for run := 0; run < 5; run++ {
+			got, err := NewRR(opts...).Schedule(context.Background(), processes)
+			if err != nil {
+				t.Fatalf("Schedule() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Fatalf("run %d after Release() = %+v, want %+v", run, got, want)
+			}
+			got.Release()
+			if got.Gantt != nil || got.Rows != nil || !reflect.DeepEqual(got.Metrics, want.Metrics) {
+				t.Errorf("Release() left Gantt %v and Rows %v, or changed the metrics", got.Gantt, got.Rows)
+			}
+		}
+	}
+
+	r, err := NewFCFS(WithCheckpoint(5)).Schedule(context.Background(), processes)
+	if err != nil {
+		t.Fatalf("Schedule() error = %v", err)
+	}
+	r.Release()
+	if r.Gantt == nil || r.Rows == nil {
+		t.Error("Release() emptied a result with a checkpoint")
+	}
+}
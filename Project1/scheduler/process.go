@@ -0,0 +1,487 @@
+// Package scheduler implements the CPU scheduling algorithms of Project 1,
+// along with loading processes from a scheduling file and rendering the
+// resulting schedules.
+//
+// The package keeps no mutable state of its own beyond the registry of
+// algorithms, which is guarded. Each simulation runs on state of its own,
+// so one Scheduler may run any number of simulations at the same time, as
+// a web service or an auto-grader would, and they may share the processes
+// they are given, which are never modified.
+package scheduler
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"math"
+	"strconv"
+	"strings"
+)
+
+type (
+	Process struct {
+		ProcessID     int64
+		ArrivalTime   int64
+		BurstDuration int64
+		Priority      int64
+		// Cycles are the I/O and CPU bursts that follow the first CPU burst,
+		// in order. A process with no cycles is purely CPU-bound.
+		Cycles []Cycle
+		// Affinity lists the 0-based CPUs the process may run on; any CPU
+		// when empty.
+		Affinity []int
+		// Nice scales the process's time slice, from -20 (longest) to 19
+		// (shortest); 0 gets the scheduler's quantum.
+		Nice int64
+		// Locks are the critical sections of the first CPU burst, in order.
+		Locks []Lock
+		// KillAt is when the process is terminated if it has not completed
+		// by then; 0 lets it run to completion.
+		KillAt int64
+		// Parent, when not 0, is the process that spawns this one ForkAt
+		// into its first burst. A child arrives when it is spawned rather
+		// than at ArrivalTime, and takes its parent's priority if it has
+		// none of its own.
+		Parent int64
+		ForkAt int64
+		// Suspensions are the intervals the process is suspended, in order.
+		Suspensions []Suspension
+		// Memory is how much memory the process needs to be admitted, when
+		// the scheduler limits memory.
+		Memory int64
+		// Deadline, when not 0, makes the process real-time: it must
+		// complete within Deadline of arriving. Period, for rate-monotonic
+		// scheduling, is how often it recurs, and doubles as its deadline
+		// when it has none.
+		Deadline int64
+		Period   int64
+		// Task and Job are set on the jobs ExpandPeriodic releases: the
+		// periodic task's process ID and the 1-based job number.
+		Task int64
+		Job  int
+	}
+	// Cycle is an I/O burst, during which the process is blocked and off the
+	// ready queue, followed by the CPU burst it needs once the I/O completes.
+	Cycle struct {
+		IO  int64
+		CPU int64
+	}
+	TimeSlice struct {
+		PID   int64     `json:"pid"`
+		Start int64     `json:"start"`
+		Stop  int64     `json:"stop"`
+		Kind  SliceKind `json:"kind,omitempty"`
+		// CPU is the 0-based core the slice ran on.
+		CPU int `json:"cpu,omitempty"`
+	}
+)
+
+// SliceKind says what the CPU was doing during a TimeSlice.
+type SliceKind string
+
+const (
+	// SliceRun is process PID running.
+	SliceRun SliceKind = ""
+	// SliceSwitch is context-switch overhead before PID runs.
+	SliceSwitch SliceKind = "switch"
+	// SliceDispatch is dispatcher latency before PID runs.
+	SliceDispatch SliceKind = "dispatch"
+	// SliceMigrate is the cache penalty of PID moving to this CPU.
+	SliceMigrate SliceKind = "migrate"
+	// SliceIdle is the CPU idle with nothing ready to run; PID is zero.
+	SliceIdle SliceKind = "idle"
+	// SliceSuspended is process PID suspended. It is not CPU time, so it
+	// is charted apart from the Gantt chart.
+	SliceSuspended SliceKind = "suspended"
+)
+
+//region Loading processes.
+
+var (
+	ErrParse      = errors.New("parse error")
+	ErrValidation = errors.New("validation error")
+)
+
+// ParseError reports a problem reading the scheduling file at a 1-based
+// line and column, either of which is zero when unknown.
+// It matches ErrParse with errors.Is and unwraps to the underlying cause.
+type ParseError struct {
+	Line, Column int
+	Err          error
+}
+
+func (e *ParseError) Error() string {
+	switch {
+	case e.Line > 0 && e.Column > 0:
+		return fmt.Sprintf("%v: line %d, column %d: %v", ErrParse, e.Line, e.Column, e.Err)
+	case e.Line > 0:
+		return fmt.Sprintf("%v: line %d: %v", ErrParse, e.Line, e.Err)
+	default:
+		return fmt.Sprintf("%v: %v", ErrParse, e.Err)
+	}
+}
+
+func (e *ParseError) Unwrap() error { return e.Err }
+
+func (e *ParseError) Is(target error) bool { return target == ErrParse }
+
+// ValidationError reports a process that breaks the input format. Row is the
+// 1-based index of the offending process, or zero for the workload as a whole.
+// It matches ErrValidation with errors.Is.
+type ValidationError struct {
+	Row int
+	Err error
+}
+
+func (e *ValidationError) Error() string {
+	if e.Row > 0 {
+		return fmt.Sprintf("%v: row %d: %v", ErrValidation, e.Row, e.Err)
+	}
+	return fmt.Sprintf("%v: %v", ErrValidation, e.Err)
+}
+
+func (e *ValidationError) Unwrap() error { return e.Err }
+
+func (e *ValidationError) Is(target error) bool { return target == ErrValidation }
+
+// LoadProcesses reads processes from CSV records of the form
+// <ProcessID>,<Burst Duration>,<Arrival Time>[,<Priority>[,<I/O>,<Burst>]...][,<key>=<value>]...,
+// where each trailing <I/O>,<Burst> pair is a Cycle. The key=value fields
+// come last, in any order: affinity=<CPU>[;<CPU>]... lists the CPUs the
+// process may run on, nice=<n> sets its nice value, mem=<n> the memory it
+// needs, lock=<name>:<start>:<duration> adds a critical section (repeatable,
+// in order), kill=<time> sets when the process is killed, parent=<pid>:<offset>
+// has process pid fork it offset into its first burst, suspend=<from>:<to>
+// adds a suspension (repeatable, in order), and deadline=<time> and
+// period=<time> make it real-time.
+func LoadProcesses(r io.Reader) ([]Process, error) {
+	return loadProcesses(r, 1, strToInt)
+}
+
+// maxDecimals is the most decimal places LoadProcessesFractional accepts.
+const maxDecimals = 3
+
+// LoadProcessesFractional reads processes like LoadProcesses, but their
+// times (bursts, arrivals, I/O, and the times and durations of key=value
+// fields) may be decimals such as 2.5. The times are returned in whole
+// ticks, together with the number of ticks per unit of time in the file: the
+// smallest power of ten that makes every time whole, such as 10 for 2.5, and
+// 1 when every time is already whole.
+func LoadProcessesFractional(r io.Reader) ([]Process, int64, error) {
+	processes, err := loadProcesses(r, int64(math.Pow10(maxDecimals)), parseTicks)
+	if err != nil {
+		return nil, 0, err
+	}
+	ticks := int64(math.Pow10(maxDecimals))
+	for ticks > 1 && divisible(processes, 10) {
+		for i := range processes {
+			for _, t := range processes[i].times() {
+				*t /= 10
+			}
+		}
+		ticks /= 10
+	}
+
+	return processes, ticks, nil
+}
+
+// divisible reports whether every time of processes is a multiple of n.
+func divisible(processes []Process, n int64) bool {
+	for i := range processes {
+		for _, t := range processes[i].times() {
+			if *t%n != 0 {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// times returns pointers to every time of p.
+func (p *Process) times() []*int64 {
+	times := []*int64{&p.BurstDuration, &p.ArrivalTime, &p.KillAt, &p.ForkAt, &p.Deadline, &p.Period}
+	for k := range p.Cycles {
+		times = append(times, &p.Cycles[k].IO, &p.Cycles[k].CPU)
+	}
+	for k := range p.Locks {
+		times = append(times, &p.Locks[k].Start, &p.Locks[k].Duration)
+	}
+	for k := range p.Suspensions {
+		times = append(times, &p.Suspensions[k].From, &p.Suspensions[k].To)
+	}
+	return times
+}
+
+// parseTicks parses a decimal time with at most maxDecimals decimal places
+// into 1/10^maxDecimals ticks.
+func parseTicks(s string) (int64, error) {
+	whole, frac, _ := strings.Cut(s, ".")
+	if len(frac) > maxDecimals {
+		return 0, fmt.Errorf("time %q has more than %d decimal places", s, maxDecimals)
+	}
+	n, err := strToInt(whole)
+	if err != nil {
+		return 0, err
+	}
+	f := int64(0)
+	if frac != "" {
+		if f, err = strconv.ParseInt(frac+strings.Repeat("0", maxDecimals-len(frac)), 10, 64); err != nil || f < 0 {
+			return 0, fmt.Errorf("time %q is not a decimal", s)
+		}
+	}
+	scale := int64(math.Pow10(maxDecimals))
+	if _, ok := mulTime(absTime(n), scale); !ok {
+		return 0, fmt.Errorf("time %q is too large", s)
+	}
+	if strings.HasPrefix(whole, "-") {
+		return n*scale - f, nil
+	}
+	return n*scale + f, nil
+}
+
+// bytesPerRecord is the length of a typical record, such as "123,17,456,23",
+// for sizing the processes read from a file of known size up front.
+const bytesPerRecord = 16
+
+// sizeHint returns the number of bytes left in r, if r can tell, or 0.
+func sizeHint(r io.Reader) int64 {
+	switch r := r.(type) {
+	case interface{ Len() int }:
+		return int64(r.Len())
+	case interface{ Stat() (fs.FileInfo, error) }:
+		if fi, err := r.Stat(); err == nil && fi.Mode().IsRegular() {
+			return fi.Size()
+		}
+	}
+	return 0
+}
+
+// loadProcesses reads processes a record at a time, parsing their times
+// with parseTime, so even a file of millions of processes only ever holds
+// one record in memory besides the processes themselves. Times that are
+// plain integers, as nearly all are, are parsed straight from the record
+// as that many units of 1/scale, without going through parseTime.
+func loadProcesses(r io.Reader, scale int64, parseTime func(string) (int64, error)) ([]Process, error) {
+	processes := make([]Process, 0, sizeHint(r)/bytesPerRecord)
+	rr := newRecordReader(r)
+	for {
+		record, err := rr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			var csvErr *csv.ParseError
+			if errors.As(err, &csvErr) {
+				return nil, &ParseError{Line: csvErr.Line, Column: csvErr.Column, Err: csvErr.Err}
+			}
+			return nil, &ParseError{Err: fmt.Errorf("%w: reading CSV", err)}
+		}
+
+		var p Process
+		for last := len(record) - 1; last >= 0 && bytes.IndexByte(record[last], '=') >= 0; last-- {
+			if err := p.setAttribute(string(record[last]), parseTime); err != nil {
+				line, column := rr.FieldPos(last)
+				return nil, &ParseError{Line: line, Column: column, Err: err}
+			}
+			record = record[:last]
+		}
+		line, _ := rr.FieldPos(0)
+		if len(record) < 3 {
+			return nil, &ParseError{Line: line, Err: fmt.Errorf("expected at least 3 fields, got %d", len(record))}
+		}
+		fields := [...]*int64{
+			&p.ProcessID,
+			&p.BurstDuration,
+			&p.ArrivalTime,
+			&p.Priority,
+		}
+		if len(record) > len(fields) {
+			if (len(record)-len(fields))%2 != 0 {
+				line, column := rr.FieldPos(len(record) - 1)
+				return nil, &ParseError{Line: line, Column: column, Err: errors.New("I/O burst without a following CPU burst")}
+			}
+			p.Cycles = make([]Cycle, (len(record)-len(fields))/2)
+		}
+		for j := range record {
+			// The ID and priority are counts; every other field is a time.
+			field, unit, parse := (*int64)(nil), scale, parseTime
+			switch k := j - len(fields); {
+			case k < 0:
+				field = fields[j]
+				if j == 0 || j == 3 {
+					unit, parse = 1, strToInt
+				}
+			case k%2 == 0:
+				field = &p.Cycles[k/2].IO
+			default:
+				field = &p.Cycles[k/2].CPU
+			}
+			if *field, err = parseField(record[j], unit, parse); err != nil {
+				line, column := rr.FieldPos(j)
+				return nil, &ParseError{Line: line, Column: column, Err: err}
+			}
+		}
+		processes = append(processes, p)
+	}
+
+	return processes, nil
+}
+
+// setAttribute sets the process attribute given by a key=value field,
+// parsing times with parseTime.
+func (p *Process) setAttribute(field string, parseTime func(string) (int64, error)) error {
+	key, value, _ := strings.Cut(field, "=")
+	var err error
+	switch key {
+	case "affinity":
+		p.Affinity, err = parseAffinity(value)
+	case "nice":
+		p.Nice, err = strToInt(value)
+	case "mem":
+		p.Memory, err = strToInt(value)
+	case "kill":
+		p.KillAt, err = parseTime(value)
+	case "deadline":
+		p.Deadline, err = parseTime(value)
+	case "period":
+		p.Period, err = parseTime(value)
+	case "suspend":
+		// Like locks, each suspension goes before the ones already set.
+		var s Suspension
+		if s, err = parseSuspension(value, parseTime); err == nil {
+			p.Suspensions = append([]Suspension{s}, p.Suspensions...)
+		}
+	case "parent":
+		p.Parent, p.ForkAt, err = parseParent(value, parseTime)
+	case "lock":
+		// Fields are set from the last, so each lock goes before the ones
+		// already set.
+		var l Lock
+		if l, err = parseLock(value, parseTime); err == nil {
+			p.Locks = append([]Lock{l}, p.Locks...)
+		}
+	default:
+		err = fmt.Errorf("unknown field %q", key)
+	}
+	return err
+}
+
+// parseAffinity parses a ;-separated list of CPU numbers.
+func parseAffinity(s string) ([]int, error) {
+	var cpus []int
+	for _, f := range strings.Split(s, ";") {
+		cpu, err := strconv.Atoi(f)
+		if err != nil {
+			return nil, fmt.Errorf("%w: affinity", err)
+		}
+		cpus = append(cpus, cpu)
+	}
+	return cpus, nil
+}
+
+// allows reports whether the process may run on the given CPU.
+func (p Process) allows(cpu int) bool {
+	if len(p.Affinity) == 0 {
+		return true
+	}
+	for _, c := range p.Affinity {
+		if c == cpu {
+			return true
+		}
+	}
+	return false
+}
+
+// indexByID maps the process ID of each of processes to its index.
+func indexByID(processes []Process) map[int64]int {
+	index := make(map[int64]int, len(processes))
+	for i, p := range processes {
+		index[p.ProcessID] = i
+	}
+	return index
+}
+
+// CPUTime is the total CPU time the process needs, across all its bursts.
+func (p Process) CPUTime() int64 {
+	total := p.BurstDuration
+	for _, c := range p.Cycles {
+		total += c.CPU
+	}
+	return total
+}
+
+// IOTime is the total time the process spends blocked on I/O.
+func (p Process) IOTime() int64 {
+	var total int64
+	for _, c := range p.Cycles {
+		total += c.IO
+	}
+	return total
+}
+
+func strToInt(s string) (int64, error) {
+	return strconv.ParseInt(s, 10, 64)
+}
+
+// ValidateProcesses checks the loaded processes against the input format:
+// unique process IDs, non-negative arrival times, positive burst durations
+// and I/O times, and priorities in the range [1-50] (zero meaning no
+// priority was given).
+func ValidateProcesses(processes []Process) error {
+	if len(processes) == 0 {
+		return &ValidationError{Err: errors.New("no processes to schedule")}
+	}
+	seen := make(map[int64]bool, len(processes))
+	for i, p := range processes {
+		var err error
+		switch {
+		case seen[p.ProcessID]:
+			err = fmt.Errorf("duplicate process ID %d", p.ProcessID)
+		case p.ArrivalTime < 0:
+			err = fmt.Errorf("negative arrival time %d", p.ArrivalTime)
+		case p.BurstDuration <= 0:
+			err = fmt.Errorf("burst duration %d must be positive", p.BurstDuration)
+		case p.Priority != 0 && (p.Priority < 1 || p.Priority > 50):
+			err = fmt.Errorf("priority %d out of range [1-50]", p.Priority)
+		}
+		for k, c := range p.Cycles {
+			if err == nil && (c.IO <= 0 || c.CPU <= 0) {
+				err = fmt.Errorf("cycle %d: I/O %d and burst %d must be positive", k+1, c.IO, c.CPU)
+			}
+		}
+		if err == nil && p.Memory < 0 {
+			err = fmt.Errorf("memory %d must not be negative", p.Memory)
+		}
+		if err == nil && (p.Nice < -20 || p.Nice > 19) {
+			err = fmt.Errorf("nice %d out of range [-20,19]", p.Nice)
+		}
+		for _, cpu := range p.Affinity {
+			if err == nil && cpu < 0 {
+				err = fmt.Errorf("negative CPU %d in affinity", cpu)
+			}
+		}
+		if err == nil && p.KillAt != 0 && p.KillAt <= p.ArrivalTime {
+			err = fmt.Errorf("kill time %d must be after arrival %d", p.KillAt, p.ArrivalTime)
+		}
+		if err == nil {
+			err = validateRealTime(p)
+		}
+		if err == nil {
+			err = validateLocks(p)
+		}
+		if err == nil {
+			err = validateSuspensions(p)
+		}
+		if err != nil {
+			return &ValidationError{Row: i + 1, Err: err}
+		}
+		seen[p.ProcessID] = true
+	}
+
+	return validateForks(processes)
+}
+
+//endregion
@@ -0,0 +1,466 @@
+package scheduler
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestLoadProcesses(t *testing.T) {
+	t.Parallel()
+	type args struct {
+		r io.Reader
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    []Process
+		wantErr error
+	}{
+		{
+			name: "bad CSV",
+			args: args{
+				r: iotest.ErrReader(io.ErrUnexpectedEOF),
+			},
+			wantErr: io.ErrUnexpectedEOF,
+		},
+		{
+			name: "bad integer",
+			args: args{
+				r: strings.NewReader(`1,five,0,2`),
+			},
+			wantErr: ErrParse,
+		},
+		{
+			name: "too few fields",
+			args: args{
+				r: strings.NewReader(`1,5`),
+			},
+			wantErr: ErrParse,
+		},
+		{
+			name: "I/O cycles",
+			args: args{
+				r: strings.NewReader(`1,5,0,2,3,4,1,2
+2,9,3`),
+			},
+			want: []Process{
+				{ProcessID: 1, BurstDuration: 5, Priority: 2, Cycles: []Cycle{{IO: 3, CPU: 4}, {IO: 1, CPU: 2}}},
+				{ProcessID: 2, BurstDuration: 9, ArrivalTime: 3},
+			},
+		},
+		{
+			name: "key=value fields",
+			args: args{
+				r: strings.NewReader(`1,5,0,2,3,4,affinity=0;2
+2,9,3,nice=-3,affinity=1`),
+			},
+			want: []Process{
+				{ProcessID: 1, BurstDuration: 5, Priority: 2, Cycles: []Cycle{{IO: 3, CPU: 4}}, Affinity: []int{0, 2}},
+				{ProcessID: 2, BurstDuration: 9, ArrivalTime: 3, Affinity: []int{1}, Nice: -3},
+			},
+		},
+		{
+			name: "locks and kill time",
+			args: args{
+				r: strings.NewReader(`1,5,0,2,lock=R:0:2,kill=9,lock=S:3:1`),
+			},
+			want: []Process{
+				{ProcessID: 1, BurstDuration: 5, Priority: 2, Locks: []Lock{{Name: "R", Duration: 2}, {Name: "S", Start: 3, Duration: 1}}, KillAt: 9},
+			},
+		},
+		{
+			name: "memory",
+			args: args{
+				r: strings.NewReader("1,5,0,2,mem=64"),
+			},
+			want: []Process{{ProcessID: 1, BurstDuration: 5, Priority: 2, Memory: 64}},
+		},
+		{
+			name: "deadline and period",
+			args: args{
+				r: strings.NewReader("1,5,0,2,deadline=8,period=10"),
+			},
+			want: []Process{{ProcessID: 1, BurstDuration: 5, Priority: 2, Deadline: 8, Period: 10}},
+		},
+		{
+			name: "suspensions",
+			args: args{
+				r: strings.NewReader("1,5,0,2,suspend=1:3,suspend=4:6"),
+			},
+			want: []Process{
+				{ProcessID: 1, BurstDuration: 5, Priority: 2, Suspensions: []Suspension{{From: 1, To: 3}, {From: 4, To: 6}}},
+			},
+		},
+		{
+			name: "parent",
+			args: args{
+				r: strings.NewReader("1,5,0,2\n2,3,0,0,parent=1:2"),
+			},
+			want: []Process{
+				{ProcessID: 1, BurstDuration: 5, Priority: 2},
+				{ProcessID: 2, BurstDuration: 3, Parent: 1, ForkAt: 2},
+			},
+		},
+		{
+			name: "success",
+			args: args{
+				r: strings.NewReader(`1,5,0,2
+2,9,3,1
+3,6,3,3`),
+			},
+			want: []Process{
+				{
+					ProcessID:     1,
+					ArrivalTime:   0,
+					BurstDuration: 5,
+					Priority:      2,
+				},
+				{
+					ProcessID:     2,
+					ArrivalTime:   3,
+					BurstDuration: 9,
+					Priority:      1,
+				},
+				{
+					ProcessID:     3,
+					ArrivalTime:   3,
+					BurstDuration: 6,
+					Priority:      3,
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := LoadProcesses(tt.args.r)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("LoadProcesses() = %v, want %v", got, tt.want)
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadProcesses_large(t *testing.T) {
+	t.Parallel()
+	var b strings.Builder
+	const n = 100000
+	for i := 1; i <= n; i++ {
+		fmt.Fprintf(&b, "%d,%d,%d,%d,2,3\n", i, i%7+1, i, i%50+1)
+	}
+	got, err := LoadProcesses(strings.NewReader(b.String()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != n {
+		t.Fatalf("loaded %d processes, want %d", len(got), n)
+	}
+	want := Process{ProcessID: n, BurstDuration: n%7 + 1, ArrivalTime: n, Priority: n%50 + 1, Cycles: []Cycle{{IO: 2, CPU: 3}}}
+	if !reflect.DeepEqual(got[n-1], want) {
+		t.Errorf("last process = %+v, want %+v", got[n-1], want)
+	}
+	if got[0].Cycles[0] != (Cycle{IO: 2, CPU: 3}) {
+		t.Errorf("first process cycles = %v, overwritten by a later record", got[0].Cycles)
+	}
+}
+
+func TestLoadProcesses_allocs(t *testing.T) {
+	// Not parallel: AllocsPerRun counts the allocations of every goroutine.
+	var b strings.Builder
+	const n = 10000
+	for i := 1; i <= n; i++ {
+		fmt.Fprintf(&b, "%d,%d,%d,%d\r\n", i, i%7+1, i, i%50+1)
+	}
+	input := b.String()
+	allocs := testing.AllocsPerRun(5, func() {
+		if _, err := LoadProcesses(strings.NewReader(input)); err != nil {
+			t.Fatal(err)
+		}
+	})
+	// The processes, the read buffer, and the reused record as it grows,
+	// but nothing per field or per record.
+	if allocs > 20 {
+		t.Errorf("LoadProcesses() of %d records made %v allocations, want at most 20", n, allocs)
+	}
+}
+
+func TestLoadProcesses_quoted(t *testing.T) {
+	t.Parallel()
+	// From the first quote on the file is read as CSV, with lines still
+	// counted from the start of the file.
+	input := "1,5,0,2\n\n2,9,3,1\n\"3\",4,\"5\",1,\"lock=R:0:1\"\n4,1,6,1,\"lock=A\nB:0:1\"\n5,2,x,1\n"
+	_, err := LoadProcesses(strings.NewReader(input))
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) || parseErr.Line != 7 || parseErr.Column != 5 {
+		t.Fatalf("error = %v, want a parse error at 7:5", err)
+	}
+
+	got, err := LoadProcesses(strings.NewReader(strings.TrimSuffix(input, "5,2,x,1\n")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Process{
+		{ProcessID: 1, BurstDuration: 5, Priority: 2},
+		{ProcessID: 2, BurstDuration: 9, ArrivalTime: 3, Priority: 1},
+		{ProcessID: 3, BurstDuration: 4, ArrivalTime: 5, Priority: 1, Locks: []Lock{{Name: "R", Duration: 1}}},
+	}
+	if !reflect.DeepEqual(got[:3], want) {
+		t.Errorf("LoadProcesses() = %+v, want %+v", got[:3], want)
+	}
+	if len(got) != 4 || got[3].ArrivalTime != 6 || got[3].Locks[0].Name != "A\nB" {
+		t.Errorf("LoadProcesses() of a field quoted across lines = %+v", got[3:])
+	}
+
+	if _, err := LoadProcesses(strings.NewReader("1,5,0\n2,9\"x\",3\n")); !errors.As(err, &parseErr) || parseErr.Line != 2 {
+		t.Errorf("error of a bare quote = %v, want a parse error on line 2", err)
+	}
+}
+
+func TestLoadProcesses_longLine(t *testing.T) {
+	t.Parallel()
+	// Longer than the read buffer, so read in pieces.
+	line := "1,5,0,2" + strings.Repeat(",1,1", readBufferSize/3)
+	got, err := LoadProcesses(strings.NewReader(line + "\n2,1,1\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || len(got[0].Cycles) != readBufferSize/3 || got[1].ProcessID != 2 {
+		t.Errorf("LoadProcesses() read %d processes, the first with %d cycles", len(got), len(got[0].Cycles))
+	}
+}
+
+func Test_atoi(t *testing.T) {
+	t.Parallel()
+	for _, s := range []string{"0", "17", "-3", "+4", "999999999999999999", "", "-", "1.5", " 1", "x", "9223372036854775807", "1_000"} {
+		want, wantErr := strconv.ParseInt(s, 10, 64)
+		got, ok := atoi([]byte(s))
+		switch {
+		case ok && (wantErr != nil || got != want):
+			t.Errorf("atoi(%q) = %d, want %d, %v", s, got, want, wantErr)
+		case !ok && wantErr == nil && len(s) <= 18:
+			t.Errorf("atoi(%q) failed, want %d", s, want)
+		}
+	}
+}
+
+func TestLoadProcessesFractional(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name      string
+		input     string
+		want      []Process
+		wantTicks int64
+	}{
+		{
+			name:      "whole times",
+			input:     "1,5,0,2\n2,9,3,1",
+			want:      []Process{{ProcessID: 1, BurstDuration: 5, Priority: 2}, {ProcessID: 2, BurstDuration: 9, ArrivalTime: 3, Priority: 1}},
+			wantTicks: 1,
+		},
+		{
+			name:      "tenths",
+			input:     "1,2.5,0,2\n2,1,0.5,1,1.5,2",
+			want:      []Process{{ProcessID: 1, BurstDuration: 25, Priority: 2}, {ProcessID: 2, BurstDuration: 10, ArrivalTime: 5, Priority: 1, Cycles: []Cycle{{IO: 15, CPU: 20}}}},
+			wantTicks: 10,
+		},
+		{
+			name:      "key=value times",
+			input:     "1,3,0,2,lock=R:0.25:1,kill=2.5",
+			want:      []Process{{ProcessID: 1, BurstDuration: 300, Priority: 2, Locks: []Lock{{Name: "R", Start: 25, Duration: 100}}, KillAt: 250}},
+			wantTicks: 100,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, ticks, err := LoadProcessesFractional(strings.NewReader(tt.input))
+			if err != nil {
+				t.Fatalf("LoadProcessesFractional() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) || ticks != tt.wantTicks {
+				t.Errorf("LoadProcessesFractional() = %v, %d, want %v, %d", got, ticks, tt.want, tt.wantTicks)
+			}
+		})
+	}
+	for _, input := range []string{"1,2.5555,0", "1,2.x,0", "1.5,2,0"} {
+		if _, _, err := LoadProcessesFractional(strings.NewReader(input)); !errors.Is(err, ErrParse) {
+			t.Errorf("LoadProcessesFractional(%q) error = %v, want %v", input, err, ErrParse)
+		}
+	}
+	if _, err := LoadProcesses(strings.NewReader("1,2.5,0")); !errors.Is(err, ErrParse) {
+		t.Errorf("LoadProcesses() of a fractional time error = %v, want %v", err, ErrParse)
+	}
+}
+
+func TestLoadProcesses_errorPosition(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name              string
+		input             string
+		wantLine, wantCol int
+	}{
+		{name: "bad integer", input: "1,five,0,2", wantLine: 1, wantCol: 3},
+		{name: "after blank line", input: "1,5,0,2\n\n2,9,x,1", wantLine: 3, wantCol: 5},
+		{name: "too few fields", input: "1,5", wantLine: 1},
+		{name: "bad affinity", input: "1,5,0,2,affinity=0;x", wantLine: 1, wantCol: 9},
+		{name: "bad lock", input: "1,5,0,2,lock=R:1", wantLine: 1, wantCol: 9},
+		{name: "unknown field", input: "1,5,0,2,colour=red", wantLine: 1, wantCol: 9},
+		{name: "unpaired I/O burst", input: "1,5,0,2\n2,9,3,1,4", wantLine: 2, wantCol: 9},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			_, err := LoadProcesses(strings.NewReader(tt.input))
+			var parseErr *ParseError
+			if !errors.As(err, &parseErr) {
+				t.Fatalf("error = %v, want a *ParseError", err)
+			}
+			if parseErr.Line != tt.wantLine || parseErr.Column != tt.wantCol {
+				t.Errorf("position = %d:%d, want %d:%d", parseErr.Line, parseErr.Column, tt.wantLine, tt.wantCol)
+			}
+		})
+	}
+}
+
+func TestValidateProcesses(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name      string
+		processes []Process
+		wantErr   error
+	}{
+		{
+			name: "valid",
+			processes: []Process{
+				{ProcessID: 1, BurstDuration: 5, ArrivalTime: 0, Priority: 2},
+				{ProcessID: 2, BurstDuration: 9, ArrivalTime: 3},
+			},
+		},
+		{
+			name:    "empty",
+			wantErr: ErrValidation,
+		},
+		{
+			name: "duplicate ID",
+			processes: []Process{
+				{ProcessID: 1, BurstDuration: 5},
+				{ProcessID: 1, BurstDuration: 9},
+			},
+			wantErr: ErrValidation,
+		},
+		{
+			name:      "negative arrival",
+			processes: []Process{{ProcessID: 1, BurstDuration: 5, ArrivalTime: -1}},
+			wantErr:   ErrValidation,
+		},
+		{
+			name:      "zero burst",
+			processes: []Process{{ProcessID: 1}},
+			wantErr:   ErrValidation,
+		},
+		{
+			name:      "priority out of range",
+			processes: []Process{{ProcessID: 1, BurstDuration: 5, Priority: 51}},
+			wantErr:   ErrValidation,
+		},
+		{
+			name:      "nice out of range",
+			processes: []Process{{ProcessID: 1, BurstDuration: 5, Nice: 20}},
+			wantErr:   ErrValidation,
+		},
+		{
+			name:      "killed before arrival",
+			processes: []Process{{ProcessID: 1, BurstDuration: 5, ArrivalTime: 4, KillAt: 4}},
+			wantErr:   ErrValidation,
+		},
+		{
+			name:      "negative memory",
+			processes: []Process{{ProcessID: 1, BurstDuration: 5, Memory: -1}},
+			wantErr:   ErrValidation,
+		},
+		{
+			name:      "suspended before arrival",
+			processes: []Process{{ProcessID: 1, BurstDuration: 5, ArrivalTime: 2, Suspensions: []Suspension{{From: 1, To: 3}}}},
+			wantErr:   ErrValidation,
+		},
+		{
+			name:      "overlapping suspensions",
+			processes: []Process{{ProcessID: 1, BurstDuration: 5, Suspensions: []Suspension{{From: 1, To: 3}, {From: 2, To: 4}}}},
+			wantErr:   ErrValidation,
+		},
+		{
+			name:      "unknown parent",
+			processes: []Process{{ProcessID: 1, BurstDuration: 5, Parent: 2}},
+			wantErr:   ErrValidation,
+		},
+		{
+			name:      "fork past the parent's burst",
+			processes: []Process{{ProcessID: 1, BurstDuration: 5}, {ProcessID: 2, BurstDuration: 1, Parent: 1, ForkAt: 6}},
+			wantErr:   ErrValidation,
+		},
+		{
+			name:      "own ancestor",
+			processes: []Process{{ProcessID: 1, BurstDuration: 5, Parent: 2}, {ProcessID: 2, BurstDuration: 1, Parent: 1}},
+			wantErr:   ErrValidation,
+		},
+		{
+			name:      "overlapping locks",
+			processes: []Process{{ProcessID: 1, BurstDuration: 5, Locks: []Lock{{Name: "R", Duration: 2}, {Name: "S", Start: 1, Duration: 1}}}},
+			wantErr:   ErrValidation,
+		},
+		{
+			name:      "lock past the burst",
+			processes: []Process{{ProcessID: 1, BurstDuration: 5, Locks: []Lock{{Name: "R", Start: 4, Duration: 2}}}},
+			wantErr:   ErrValidation,
+		},
+		{
+			name:      "negative affinity",
+			processes: []Process{{ProcessID: 1, BurstDuration: 5, Affinity: []int{-1}}},
+			wantErr:   ErrValidation,
+		},
+		{
+			name:      "zero I/O",
+			processes: []Process{{ProcessID: 1, BurstDuration: 5, Cycles: []Cycle{{CPU: 2}}}},
+			wantErr:   ErrValidation,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if err := ValidateProcesses(tt.processes); !errors.Is(err, tt.wantErr) {
+				t.Errorf("ValidateProcesses() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateProcesses_row(t *testing.T) {
+	t.Parallel()
+	err := ValidateProcesses([]Process{
+		{ProcessID: 1, BurstDuration: 5},
+		{ProcessID: 2, BurstDuration: 0},
+	})
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("error = %v, want a *ValidationError", err)
+	}
+	if validationErr.Row != 2 {
+		t.Errorf("Row = %d, want 2", validationErr.Row)
+	}
+	if want := "validation error: row 2: burst duration 0 must be positive"; err.Error() != want {
+		t.Errorf("Error() = %q, want %q", err.Error(), want)
+	}
+}
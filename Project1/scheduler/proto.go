@@ -0,0 +1,591 @@
+package scheduler
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+)
+
+// The protobuf interchange format stores workloads and results as the
+// Workload and Report messages of proto/simulator.proto, encoded here
+// directly in the protobuf wire format so the module needs no generated
+// code. Fields a reader does not know are skipped, so files written by
+// later versions of the schema still load.
+const protoVersion = 1
+
+// Wire types of protobuf fields.
+const (
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+	wireFixed32 = 5
+)
+
+// Report is a schedule as written by RenderProto: its title and result,
+// whose violations are only kept as the text describing them.
+type Report struct {
+	Title      string
+	Result     *ScheduleResult
+	Violations []string
+}
+
+// WriteProto writes processes, whose times are in ticks of which ticks make
+// one unit of time, to w as a protobuf Workload. The Task and Job of
+// periodic jobs are not stored, as workloads hold the tasks themselves.
+func WriteProto(w io.Writer, processes []Process, ticks int64) error {
+	var e protoEncoder
+	e.uint(1, protoVersion)
+	e.int(2, ticks)
+	for _, p := range processes {
+		e.message(3, encodeProcess(p))
+	}
+	_, err := w.Write(e.buf)
+
+	return err
+}
+
+// LoadProto reads the processes of the protobuf Workload in r, along with
+// the number of ticks per unit of time their times are in.
+func LoadProto(r io.Reader) ([]Process, int64, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, 0, &ParseError{Err: err}
+	}
+	var processes []Process
+	var version uint64
+	ticks := int64(1)
+	err = decodeProto(data, func(field, wire int, v uint64, b []byte) error {
+		switch {
+		case field == 1 && wire == wireVarint:
+			version = v
+		case field == 2 && wire == wireVarint:
+			ticks = int64(v)
+		case field == 3 && wire == wireBytes:
+			p, err := decodeProcess(b)
+			if err != nil {
+				return fmt.Errorf("process %d: %w", len(processes)+1, err)
+			}
+			processes = append(processes, p)
+		}
+		return nil
+	})
+	switch {
+	case err != nil:
+		return nil, 0, &ParseError{Err: fmt.Errorf("protobuf workload: %w", err)}
+	case version != protoVersion:
+		return nil, 0, &ParseError{Err: fmt.Errorf("protobuf workload version %d, want %d", version, protoVersion)}
+	case ticks < 1:
+		return nil, 0, &ParseError{Err: fmt.Errorf("protobuf workload has %d ticks per unit", ticks)}
+	}
+
+	return processes, ticks, nil
+}
+
+// RenderProto writes r to w as a protobuf Report prefixed by its length as
+// a varint, so the reports of several schedules can follow one another.
+// Violations are written as the text reports describe them.
+func RenderProto(w io.Writer, title string, r *ScheduleResult) error {
+	var e protoEncoder
+	e.str(1, title)
+	e.message(2, encodeResult(r))
+	buf := binary.AppendUvarint(make([]byte, 0, len(e.buf)+binary.MaxVarintLen64), uint64(len(e.buf)))
+	_, err := w.Write(append(buf, e.buf...))
+
+	return err
+}
+
+// ReadProto reads the reports RenderProto wrote to r, one after another.
+func ReadProto(r io.Reader) ([]Report, error) {
+	br := bufio.NewReader(r)
+	var reports []Report
+	for {
+		n, err := binary.ReadUvarint(br)
+		if err == io.EOF {
+			return reports, nil
+		}
+		if err != nil {
+			return nil, &ParseError{Err: fmt.Errorf("protobuf report %d: %w", len(reports)+1, err)}
+		}
+		if n > math.MaxInt32 {
+			return nil, &ParseError{Err: fmt.Errorf("protobuf report %d of %d bytes is too large", len(reports)+1, n)}
+		}
+		data := make([]byte, n)
+		if _, err := io.ReadFull(br, data); err != nil {
+			return nil, &ParseError{Err: fmt.Errorf("protobuf report %d: %w", len(reports)+1, err)}
+		}
+		report := Report{Result: &ScheduleResult{}}
+		err = decodeProto(data, func(field, wire int, v uint64, b []byte) error {
+			switch {
+			case field == 1 && wire == wireBytes:
+				report.Title = string(b)
+			case field == 2 && wire == wireBytes:
+				return decodeResult(b, report.Result, &report.Violations)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, &ParseError{Err: fmt.Errorf("protobuf report %d: %w", len(reports)+1, err)}
+		}
+		reports = append(reports, report)
+	}
+}
+
+// The messages of the Simulator service, for servers and clients of it
+// that carry them in messages of their own. Decoding errors are ParseErrors.
+
+// MarshalProtoProcess encodes p as a protobuf Process.
+func MarshalProtoProcess(p Process) []byte { return encodeProcess(p) }
+
+// UnmarshalProtoProcess decodes the protobuf Process in data.
+func UnmarshalProtoProcess(data []byte) (Process, error) {
+	p, err := decodeProcess(data)
+	if err != nil {
+		return Process{}, &ParseError{Err: fmt.Errorf("protobuf process: %w", err)}
+	}
+	return p, nil
+}
+
+// MarshalProtoSlice encodes ts as a protobuf TimeSlice.
+func MarshalProtoSlice(ts TimeSlice) []byte { return encodeSlice(ts) }
+
+// UnmarshalProtoSlice decodes the protobuf TimeSlice in data.
+func UnmarshalProtoSlice(data []byte) (TimeSlice, error) {
+	ts, err := decodeSlice(data)
+	if err != nil {
+		return TimeSlice{}, &ParseError{Err: fmt.Errorf("protobuf time slice: %w", err)}
+	}
+	return ts, nil
+}
+
+// MarshalProtoResult encodes r as a protobuf ScheduleResult, its violations
+// as the text reports describe them.
+func MarshalProtoResult(r *ScheduleResult) []byte { return encodeResult(r) }
+
+// UnmarshalProtoResult decodes the protobuf ScheduleResult in data, along
+// with the text of its violations.
+func UnmarshalProtoResult(data []byte) (*ScheduleResult, []string, error) {
+	r := &ScheduleResult{}
+	var violations []string
+	if err := decodeResult(data, r, &violations); err != nil {
+		return nil, nil, &ParseError{Err: fmt.Errorf("protobuf result: %w", err)}
+	}
+	return r, violations, nil
+}
+
+// MarshalProtoMetrics encodes m as a protobuf Metrics.
+func MarshalProtoMetrics(m Metrics) []byte { return encodeMetrics(m) }
+
+// UnmarshalProtoMetrics decodes the protobuf Metrics in data.
+func UnmarshalProtoMetrics(data []byte) (Metrics, error) {
+	var m Metrics
+	if err := decodeMetrics(data, &m); err != nil {
+		return Metrics{}, &ParseError{Err: fmt.Errorf("protobuf metrics: %w", err)}
+	}
+	return m, nil
+}
+
+// MarshalProtoUpdate encodes u as a protobuf MetricsUpdate.
+func MarshalProtoUpdate(u MetricsUpdate) []byte {
+	var e protoEncoder
+	e.int(1, u.At)
+	e.int(2, int64(u.Completed))
+	e.int(3, int64(u.Killed))
+	e.int(4, int64(u.Total))
+	e.double(5, u.AverageWait)
+	e.double(6, u.AverageTurnaround)
+	e.double(7, u.Throughput)
+
+	return e.buf
+}
+
+// UnmarshalProtoUpdate decodes the protobuf MetricsUpdate in data.
+func UnmarshalProtoUpdate(data []byte) (MetricsUpdate, error) {
+	var u MetricsUpdate
+	doubles := map[int]*float64{5: &u.AverageWait, 6: &u.AverageTurnaround, 7: &u.Throughput}
+	ints := map[int]*int{2: &u.Completed, 3: &u.Killed, 4: &u.Total}
+	err := decodeProto(data, func(field, wire int, v uint64, b []byte) error {
+		if d, ok := doubles[field]; ok && wire == wireFixed64 {
+			*d = math.Float64frombits(v)
+		} else if n, ok := ints[field]; ok && wire == wireVarint {
+			*n = int(int32(v))
+		} else if field == 1 && wire == wireVarint {
+			u.At = int64(v)
+		}
+		return nil
+	})
+	if err != nil {
+		return MetricsUpdate{}, &ParseError{Err: fmt.Errorf("protobuf metrics update: %w", err)}
+	}
+	return u, nil
+}
+
+func encodeProcess(p Process) []byte {
+	var e protoEncoder
+	e.int(1, p.ProcessID)
+	e.int(2, p.ArrivalTime)
+	e.int(3, p.BurstDuration)
+	e.int(4, p.Priority)
+	for _, c := range p.Cycles {
+		var ce protoEncoder
+		ce.int(1, c.IO)
+		ce.int(2, c.CPU)
+		e.message(5, ce.buf)
+	}
+	if len(p.Affinity) > 0 {
+		var packed []byte
+		for _, cpu := range p.Affinity {
+			packed = binary.AppendUvarint(packed, uint64(int64(cpu)))
+		}
+		e.message(6, packed)
+	}
+	e.int(7, p.Nice)
+	e.int(8, p.KillAt)
+	e.int(9, p.Memory)
+	e.int(10, p.Deadline)
+	e.int(11, p.Period)
+	for _, l := range p.Locks {
+		var le protoEncoder
+		le.str(1, l.Name)
+		le.int(2, l.Start)
+		le.int(3, l.Duration)
+		e.message(12, le.buf)
+	}
+	e.int(13, p.Parent)
+	e.int(14, p.ForkAt)
+	for _, s := range p.Suspensions {
+		var se protoEncoder
+		se.int(1, s.From)
+		se.int(2, s.To)
+		e.message(15, se.buf)
+	}
+
+	return e.buf
+}
+
+func decodeProcess(data []byte) (Process, error) {
+	var p Process
+	ints := map[int]*int64{
+		1: &p.ProcessID, 2: &p.ArrivalTime, 3: &p.BurstDuration, 4: &p.Priority, 7: &p.Nice,
+		8: &p.KillAt, 9: &p.Memory, 10: &p.Deadline, 11: &p.Period, 13: &p.Parent, 14: &p.ForkAt,
+	}
+	err := decodeProto(data, func(field, wire int, v uint64, b []byte) error {
+		if dst, ok := ints[field]; ok && wire == wireVarint {
+			*dst = int64(v)
+			return nil
+		}
+		switch {
+		case field == 5 && wire == wireBytes:
+			var c Cycle
+			p.Cycles = append(p.Cycles, c)
+			return decodeInts(b, map[int]*int64{1: &p.Cycles[len(p.Cycles)-1].IO, 2: &p.Cycles[len(p.Cycles)-1].CPU})
+		case field == 6 && wire == wireVarint:
+			p.Affinity = append(p.Affinity, int(int32(v)))
+		case field == 6 && wire == wireBytes:
+			for len(b) > 0 {
+				cpu, n := binary.Uvarint(b)
+				if n <= 0 {
+					return errors.New("malformed packed affinity")
+				}
+				p.Affinity, b = append(p.Affinity, int(int32(cpu))), b[n:]
+			}
+		case field == 12 && wire == wireBytes:
+			var l Lock
+			err := decodeProto(b, func(field, wire int, v uint64, b []byte) error {
+				switch {
+				case field == 1 && wire == wireBytes:
+					l.Name = string(b)
+				case field == 2 && wire == wireVarint:
+					l.Start = int64(v)
+				case field == 3 && wire == wireVarint:
+					l.Duration = int64(v)
+				}
+				return nil
+			})
+			p.Locks = append(p.Locks, l)
+			return err
+		case field == 15 && wire == wireBytes:
+			var s Suspension
+			err := decodeInts(b, map[int]*int64{1: &s.From, 2: &s.To})
+			p.Suspensions = append(p.Suspensions, s)
+			return err
+		}
+		return nil
+	})
+
+	return p, err
+}
+
+func encodeResult(r *ScheduleResult) []byte {
+	var e protoEncoder
+	for _, ts := range r.Gantt {
+		e.message(1, encodeSlice(ts))
+	}
+	for _, row := range r.Rows {
+		var re protoEncoder
+		for k, v := range []int64{row.ProcessID, row.Priority, row.BurstDuration, row.IO, row.ArrivalTime, row.Wait, row.Turnaround, row.Exit} {
+			re.int(k+1, v)
+		}
+		re.bool(9, row.Killed)
+		re.bool(10, row.Excluded)
+		re.bool(11, row.Missed)
+		e.message(2, re.buf)
+	}
+	e.message(3, encodeMetrics(r.Metrics))
+	e.int(4, int64(r.Total))
+	e.bool(5, r.Cancelled)
+	e.bool(6, r.Truncated)
+	if r.Ideal != nil {
+		e.message(7, encodeMetrics(*r.Ideal))
+	}
+	e.int(8, r.TicksPerUnit)
+	for _, v := range r.Violations {
+		e.str(9, v.describe(r.ticksPerUnit()))
+	}
+	if r.Seed != nil {
+		e.uint(10, uint64(*r.Seed))
+	}
+
+	return e.buf
+}
+
+func decodeResult(data []byte, r *ScheduleResult, violations *[]string) error {
+	return decodeProto(data, func(field, wire int, v uint64, b []byte) error {
+		switch {
+		case field == 1 && wire == wireBytes:
+			ts, err := decodeSlice(b)
+			r.Gantt = append(r.Gantt, ts)
+			return err
+		case field == 2 && wire == wireBytes:
+			var row Row
+			err := decodeProto(b, func(field, wire int, v uint64, b []byte) error {
+				if wire != wireVarint {
+					return nil
+				}
+				switch field {
+				case 1, 2, 3, 4, 5, 6, 7, 8:
+					*[]*int64{&row.ProcessID, &row.Priority, &row.BurstDuration, &row.IO, &row.ArrivalTime, &row.Wait, &row.Turnaround, &row.Exit}[field-1] = int64(v)
+				case 9:
+					row.Killed = v != 0
+				case 10:
+					row.Excluded = v != 0
+				case 11:
+					row.Missed = v != 0
+				}
+				return nil
+			})
+			r.Rows = append(r.Rows, row)
+			return err
+		case field == 3 && wire == wireBytes:
+			return decodeMetrics(b, &r.Metrics)
+		case field == 4 && wire == wireVarint:
+			r.Total = int(int32(v))
+		case field == 5 && wire == wireVarint:
+			r.Cancelled = v != 0
+		case field == 6 && wire == wireVarint:
+			r.Truncated = v != 0
+		case field == 7 && wire == wireBytes:
+			r.Ideal = &Metrics{}
+			return decodeMetrics(b, r.Ideal)
+		case field == 8 && wire == wireVarint:
+			r.TicksPerUnit = int64(v)
+		case field == 9 && wire == wireBytes:
+			*violations = append(*violations, string(b))
+		case field == 10 && wire == wireVarint:
+			seed := int64(v)
+			r.Seed = &seed
+		}
+		return nil
+	})
+}
+
+func encodeSlice(ts TimeSlice) []byte {
+	var e protoEncoder
+	e.int(1, ts.PID)
+	e.int(2, ts.Start)
+	e.int(3, ts.Stop)
+	e.str(4, string(ts.Kind))
+	e.int(5, int64(ts.CPU))
+
+	return e.buf
+}
+
+func decodeSlice(data []byte) (TimeSlice, error) {
+	var ts TimeSlice
+	var cpu int64
+	err := decodeProto(data, func(field, wire int, v uint64, b []byte) error {
+		switch {
+		case field == 1 && wire == wireVarint:
+			ts.PID = int64(v)
+		case field == 2 && wire == wireVarint:
+			ts.Start = int64(v)
+		case field == 3 && wire == wireVarint:
+			ts.Stop = int64(v)
+		case field == 4 && wire == wireBytes:
+			ts.Kind = SliceKind(b)
+		case field == 5 && wire == wireVarint:
+			cpu = int64(v)
+		}
+		return nil
+	})
+	ts.CPU = int(cpu)
+
+	return ts, err
+}
+
+// metricsDoubles and metricsInts are the fields of the Metrics message, by
+// field number.
+func metricsDoubles(m *Metrics) map[int]*float64 {
+	return map[int]*float64{
+		1: &m.AverageWait, 2: &m.AverageTurnaround, 3: &m.AverageAdmission, 4: &m.Throughput,
+		9: &m.Makespan, 12: &m.MigrationPenalty, 13: &m.Overhead, 14: &m.Energy,
+		15: &m.IdleEnergy, 16: &m.Utilization, 17: &m.LoadImbalance,
+	}
+}
+
+func metricsInts(m *Metrics) map[int]*int {
+	return map[int]*int{5: &m.Completed, 6: &m.Killed, 7: &m.Excluded, 8: &m.DeadlineMisses, 10: &m.ContextSwitches, 11: &m.Migrations}
+}
+
+func encodeMetrics(m Metrics) []byte {
+	var e protoEncoder
+	// In field order, as protobuf encoders write them.
+	doubles, ints := metricsDoubles(&m), metricsInts(&m)
+	for field := 1; field <= 17; field++ {
+		if d, ok := doubles[field]; ok {
+			e.double(field, *d)
+		} else if n, ok := ints[field]; ok {
+			e.int(field, int64(*n))
+		}
+	}
+	e.bool(18, m.Imprecise)
+
+	return e.buf
+}
+
+func decodeMetrics(data []byte, m *Metrics) error {
+	doubles, ints := metricsDoubles(m), metricsInts(m)
+	return decodeProto(data, func(field, wire int, v uint64, b []byte) error {
+		if d, ok := doubles[field]; ok && wire == wireFixed64 {
+			*d = math.Float64frombits(v)
+		} else if n, ok := ints[field]; ok && wire == wireVarint {
+			*n = int(int32(v))
+		} else if field == 18 && wire == wireVarint {
+			m.Imprecise = v != 0
+		}
+		return nil
+	})
+}
+
+// decodeInts decodes a message of int64 fields into dst, by field number.
+func decodeInts(data []byte, dst map[int]*int64) error {
+	return decodeProto(data, func(field, wire int, v uint64, b []byte) error {
+		if d, ok := dst[field]; ok && wire == wireVarint {
+			*d = int64(v)
+		}
+		return nil
+	})
+}
+
+// decodeProto calls fn with each field of the protobuf message in data: a
+// varint or fixed value in v, or the contents of a length-delimited field
+// in b.
+func decodeProto(data []byte, fn func(field, wire int, v uint64, b []byte) error) error {
+	for len(data) > 0 {
+		key, n := binary.Uvarint(data)
+		if n <= 0 {
+			return errors.New("malformed field key")
+		}
+		data = data[n:]
+		field, wire := int(key>>3), int(key&7)
+		if field == 0 {
+			return errors.New("field number 0")
+		}
+		var v uint64
+		var b []byte
+		switch wire {
+		case wireVarint:
+			if v, n = binary.Uvarint(data); n <= 0 {
+				return fmt.Errorf("field %d: malformed varint", field)
+			}
+			data = data[n:]
+		case wireFixed64:
+			if len(data) < 8 {
+				return fmt.Errorf("field %d: truncated", field)
+			}
+			v, data = binary.LittleEndian.Uint64(data), data[8:]
+		case wireFixed32:
+			if len(data) < 4 {
+				return fmt.Errorf("field %d: truncated", field)
+			}
+			v, data = uint64(binary.LittleEndian.Uint32(data)), data[4:]
+		case wireBytes:
+			size, n := binary.Uvarint(data)
+			if n <= 0 || size > uint64(len(data)-n) {
+				return fmt.Errorf("field %d: truncated", field)
+			}
+			b, data = data[n:n+int(size)], data[n+int(size):]
+		default:
+			return fmt.Errorf("field %d: unsupported wire type %d", field, wire)
+		}
+		if err := fn(field, wire, v, b); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// protoEncoder appends fields to a protobuf message, leaving out those at
+// their default values as proto3 does.
+type protoEncoder struct {
+	buf []byte
+}
+
+func (e *protoEncoder) key(field, wire int) {
+	e.buf = binary.AppendUvarint(e.buf, uint64(field)<<3|uint64(wire))
+}
+
+// uint writes a varint field even when it is 0, for fields with presence.
+func (e *protoEncoder) uint(field int, v uint64) {
+	e.key(field, wireVarint)
+	e.buf = binary.AppendUvarint(e.buf, v)
+}
+
+// int writes an int64 or int32 field; negative values take ten bytes, as
+// in every protobuf encoder.
+func (e *protoEncoder) int(field int, v int64) {
+	if v != 0 {
+		e.uint(field, uint64(v))
+	}
+}
+
+func (e *protoEncoder) bool(field int, v bool) {
+	if v {
+		e.uint(field, 1)
+	}
+}
+
+func (e *protoEncoder) double(field int, v float64) {
+	if v != 0 || math.Signbit(v) {
+		e.key(field, wireFixed64)
+		e.buf = binary.LittleEndian.AppendUint64(e.buf, math.Float64bits(v))
+	}
+}
+
+func (e *protoEncoder) str(field int, s string) {
+	if s != "" {
+		e.key(field, wireBytes)
+		e.buf = binary.AppendUvarint(e.buf, uint64(len(s)))
+		e.buf = append(e.buf, s...)
+	}
+}
+
+// message writes a length-delimited field, even when it is empty.
+func (e *protoEncoder) message(field int, b []byte) {
+	e.key(field, wireBytes)
+	e.buf = binary.AppendUvarint(e.buf, uint64(len(b)))
+	e.buf = append(e.buf, b...)
+}
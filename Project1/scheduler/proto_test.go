@@ -0,0 +1,83 @@
+package scheduler
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestProtoWorkload(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: 1, BurstDuration: 25, Priority: 3, Nice: -5, Memory: 64, Affinity: []int{0, 2}},
+		{ProcessID: 2, BurstDuration: 4, ArrivalTime: 5, Cycles: []Cycle{{IO: 3, CPU: 2}}, Locks: []Lock{{Name: "db", Start: 1, Duration: 2}}},
+		{ProcessID: 3, BurstDuration: 6, ArrivalTime: 8, Parent: 1, ForkAt: 2, Suspensions: []Suspension{{From: 9, To: 12}}},
+		{ProcessID: 9, BurstDuration: 10, KillAt: 40, Deadline: 20, Period: 30},
+	}
+	var buf bytes.Buffer
+	if err := WriteProto(&buf, processes, 10); err != nil {
+		t.Fatalf("WriteProto() error = %v", err)
+	}
+	got, ticks, err := LoadProto(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("LoadProto() error = %v", err)
+	}
+	if ticks != 10 || !reflect.DeepEqual(got, processes) {
+		t.Errorf("LoadProto() = %+v, %d, want %+v, 10", got, ticks, processes)
+	}
+
+	// Fields of a later version of the schema are skipped.
+	unknown := append([]byte{0xf8, 0x01, 0x07}, buf.Bytes()...)
+	if got, _, err := LoadProto(bytes.NewReader(unknown)); err != nil || len(got) != len(processes) {
+		t.Errorf("LoadProto() with an unknown field = %d processes, %v, want %d", len(got), err, len(processes))
+	}
+
+	for _, tt := range []struct {
+		name string
+		data []byte
+	}{
+		{name: "truncated", data: buf.Bytes()[:buf.Len()-1]},
+		{name: "no version", data: []byte{0x10, 0x01}},
+		{name: "later version", data: []byte{0x08, 0x02}},
+	} {
+		if _, _, err := LoadProto(bytes.NewReader(tt.data)); !errors.Is(err, ErrParse) {
+			t.Errorf("LoadProto() of a %s workload error = %v, want %v", tt.name, err, ErrParse)
+		}
+	}
+}
+
+func TestRenderProto(t *testing.T) {
+	t.Parallel()
+	r := testResult()
+	r.TicksPerUnit = 2
+	r.Gantt[1].Kind, r.Gantt[1].CPU = SliceSwitch, 1
+	r.Rows[1].Killed = true
+	r.Ideal = &Metrics{AverageWait: 0.5, Completed: 2}
+	r.Violations = []Violation{{Rule: RuleOverlap, PID: 2, At: 5, Got: 4, Want: 5}}
+	var buf bytes.Buffer
+	for _, title := range []string{"FCFS", "RR"} {
+		if err := RenderProto(&buf, title, r); err != nil {
+			t.Fatalf("RenderProto() error = %v", err)
+		}
+	}
+	got, err := ReadProto(&buf)
+	if err != nil {
+		t.Fatalf("ReadProto() error = %v", err)
+	}
+	want := *r
+	want.Violations = nil
+	if len(got) != 2 || got[0].Title != "FCFS" || got[1].Title != "RR" {
+		t.Fatalf("ReadProto() = %+v, want the FCFS and RR reports", got)
+	}
+	if !reflect.DeepEqual(got[1].Result, &want) {
+		t.Errorf("ReadProto() result = %+v, want %+v", got[1].Result, &want)
+	}
+	if v := []string{r.Violations[0].describe(2)}; !reflect.DeepEqual(got[1].Violations, v) {
+		t.Errorf("ReadProto() violations = %q, want %q", got[1].Violations, v)
+	}
+
+	if _, err := ReadProto(bytes.NewReader([]byte{0x05, 0x0a})); !errors.Is(err, ErrParse) {
+		t.Errorf("ReadProto() of a truncated report error = %v, want %v", err, ErrParse)
+	}
+}
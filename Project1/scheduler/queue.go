@@ -0,0 +1,179 @@
+package scheduler
+
+import (
+	"container/heap"
+	"sort"
+)
+
+// readyQueue is a FIFO queue of process indices, the ready queue of
+// first-come, first-serve and round-robin scheduling.
+type readyQueue struct {
+	items []int
+	head  int
+}
+
+func (q *readyQueue) push(i int) { q.items = append(q.items, i) }
+
+// pop removes and returns the process at the front of the queue.
+// The queue must not be empty.
+func (q *readyQueue) pop() int {
+	i := q.items[q.head]
+	q.head++
+	if q.head == len(q.items) {
+		// Reuse the backing array once the queue drains.
+		q.items, q.head = q.items[:0], 0
+	}
+	return i
+}
+
+func (q *readyQueue) len() int { return len(q.items) - q.head }
+
+// at returns the process k places behind the front of the queue.
+func (q *readyQueue) at(k int) int { return q.items[q.head+k] }
+
+// remove removes and returns the process k places behind the front of the
+// queue, keeping the others in order.
+func (q *readyQueue) remove(k int) int {
+	if k == 0 {
+		return q.pop()
+	}
+	i := q.items[q.head+k]
+	q.items = append(q.items[:q.head+k], q.items[q.head+k+1:]...)
+	return i
+}
+
+const (
+	// notQueued and unranked are rankedQueue positions of a process that is
+	// not in the queue, and of one added since the queue was last ranked.
+	notQueued = -1
+	unranked  = -2
+)
+
+// rankedQueue is a priority queue of process indices, the ready queue of the
+// schedulers that always run the most urgent process. Ranking a process
+// needs the engine, so push only records it, and pop ranks the processes
+// pushed since with the engine it is given, on a container/heap heap.
+// Processes that rank equally come out in the order they were pushed, with
+// a running process, one interrupted to choose again, ahead of the rest.
+type rankedQueue struct {
+	heap    []int
+	pending []int
+	// pos is each process's index in heap, or notQueued or unranked, and
+	// seq orders equally ranked processes by when they were pushed.
+	pos  []int
+	seq  []int64
+	next int64
+	// e and before rank the heap, as of the last pop.
+	e      *engine
+	before func(e *engine, i, j int) bool
+	// skipped holds the processes a pop passed over as not allowed, kept
+	// to reuse its backing array.
+	skipped []int
+}
+
+func (q *rankedQueue) push(i int) {
+	for len(q.pos) <= i {
+		q.pos, q.seq = append(q.pos, notQueued), append(q.seq, 0)
+	}
+	q.pos[i], q.seq[i] = unranked, q.next
+	q.next++
+	q.pending = append(q.pending, i)
+}
+
+func (q *rankedQueue) len() int { return len(q.heap) + len(q.pending) }
+
+// contains reports whether process i is in the queue.
+func (q *rankedQueue) contains(i int) bool { return i < len(q.pos) && q.pos[i] != notQueued }
+
+// remove takes process i out of the queue, if it is in it.
+func (q *rankedQueue) remove(i int) {
+	if !q.contains(i) {
+		return
+	}
+	if q.pos[i] == unranked {
+		for k, j := range q.pending {
+			if j == i {
+				q.pending = append(q.pending[:k], q.pending[k+1:]...)
+				break
+			}
+		}
+		q.pos[i] = notQueued
+		return
+	}
+	heap.Remove(q, q.pos[i])
+}
+
+// rerank ranks process i again at the next pop, keeping its place among
+// equals, after a change such as an inherited priority that moves it.
+func (q *rankedQueue) rerank(i int) {
+	if !q.contains(i) || q.pos[i] == unranked {
+		return
+	}
+	heap.Remove(q, q.pos[i])
+	q.pos[i] = unranked
+	q.pending = append(q.pending, i)
+}
+
+// pop removes and returns the most urgent process by before among those
+// allowed, or returns -1 if none is.
+func (q *rankedQueue) pop(e *engine, before func(e *engine, i, j int) bool, allowed func(i int) bool) int {
+	q.e, q.before = e, before
+	for _, i := range q.pending {
+		heap.Push(q, i)
+	}
+	q.pending = q.pending[:0]
+	found := -1
+	q.skipped = q.skipped[:0]
+	for len(q.heap) > 0 {
+		i := heap.Pop(q).(int)
+		if allowed(i) {
+			found = i
+			break
+		}
+		q.skipped = append(q.skipped, i)
+	}
+	for _, i := range q.skipped {
+		heap.Push(q, i)
+	}
+
+	return found
+}
+
+// items returns the queued processes in the order they were pushed.
+func (q *rankedQueue) items() []int {
+	items := append(append([]int(nil), q.heap...), q.pending...)
+	sort.Slice(items, func(a, b int) bool { return q.seq[items[a]] < q.seq[items[b]] })
+	return items
+}
+
+// Len, Less, Swap, Push, and Pop implement heap.Interface over the ranked
+// processes.
+func (q *rankedQueue) Len() int { return len(q.heap) }
+func (q *rankedQueue) Less(a, b int) bool {
+	i, j := q.heap[a], q.heap[b]
+	switch {
+	case q.before(q.e, i, j):
+		return true
+	case q.before(q.e, j, i):
+		return false
+	}
+	if ri, rj := q.e.state[i] == StateRunning, q.e.state[j] == StateRunning; ri != rj {
+		return ri
+	}
+	return q.seq[i] < q.seq[j]
+}
+func (q *rankedQueue) Swap(a, b int) {
+	q.heap[a], q.heap[b] = q.heap[b], q.heap[a]
+	q.pos[q.heap[a]], q.pos[q.heap[b]] = a, b
+}
+func (q *rankedQueue) Push(x any) {
+	i := x.(int)
+	q.pos[i] = len(q.heap)
+	q.heap = append(q.heap, i)
+}
+func (q *rankedQueue) Pop() any {
+	i := q.heap[len(q.heap)-1]
+	q.heap = q.heap[:len(q.heap)-1]
+	q.pos[i] = notQueued
+	return i
+}
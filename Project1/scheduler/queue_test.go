@@ -0,0 +1,121 @@
+package scheduler
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestReadyQueue(t *testing.T) {
+	t.Parallel()
+	var q readyQueue
+	var got []int
+	q.push(1)
+	q.push(2)
+	got = append(got, q.pop())
+	q.push(3)
+	for q.len() > 0 {
+		got = append(got, q.pop())
+	}
+	q.push(4)
+	got = append(got, q.pop())
+	if want := []int{1, 2, 3, 4}; !reflect.DeepEqual(got, want) {
+		t.Errorf("pop order = %v, want %v", got, want)
+	}
+	if q.len() != 0 {
+		t.Errorf("len() = %d, want 0", q.len())
+	}
+}
+
+func TestReadyQueue_remove(t *testing.T) {
+	t.Parallel()
+	var q readyQueue
+	for i := 1; i <= 4; i++ {
+		q.push(i)
+	}
+	q.pop()
+	if got := q.remove(1); got != 3 {
+		t.Errorf("remove(1) = %d, want 3", got)
+	}
+	if got := q.remove(0); got != 2 {
+		t.Errorf("remove(0) = %d, want 2", got)
+	}
+	if q.len() != 1 || q.at(0) != 4 {
+		t.Errorf("queue = %v from %d, want [4]", q.items, q.head)
+	}
+}
+
+func TestRankedQueue(t *testing.T) {
+	t.Parallel()
+	e := &engine{state: make([]State, 6)}
+	rank := []int{3, 1, 2, 1, 0, 2}
+	before := func(_ *engine, i, j int) bool { return rank[i] < rank[j] }
+	all := func(int) bool { return true }
+	var q rankedQueue
+	for i := 0; i < 4; i++ {
+		q.push(i)
+	}
+	if got := q.pop(e, before, func(i int) bool { return i != 1 }); got != 3 {
+		t.Errorf("pop() skipping 1 = %d, want 3", got)
+	}
+	q.push(4)
+	q.push(5)
+	q.remove(2)
+	var got []int
+	for q.len() > 0 {
+		got = append(got, q.pop(e, before, all))
+	}
+	if want := []int{4, 1, 5, 0}; !reflect.DeepEqual(got, want) {
+		t.Errorf("pop order = %v, want %v", got, want)
+	}
+	if got := q.pop(e, before, all); got != -1 {
+		t.Errorf("pop() on empty queue = %d, want -1", got)
+	}
+}
+
+func TestRankedQueue_comparisons(t *testing.T) {
+	t.Parallel()
+	// Each push and pop costs O(log n) comparisons: the queue stays
+	// ranked rather than being sorted again at every decision.
+	const n = 1 << 12
+	e := &engine{state: make([]State, n)}
+	var compared int
+	before := func(_ *engine, i, j int) bool {
+		compared++
+		return (i*7919)%n < (j*7919)%n
+	}
+	all := func(int) bool { return true }
+	var q rankedQueue
+	for i := 0; i < n; i++ {
+		q.push(i)
+		if i%2 == 1 {
+			q.pop(e, before, all)
+		}
+	}
+	for q.len() > 0 {
+		q.pop(e, before, all)
+	}
+	// Each comparison of Less may call before twice.
+	if limit := 2 * 3 * n * 12; compared > limit {
+		t.Errorf("%d pushes and pops made %d comparisons, want at most %d", n, compared, limit)
+	}
+}
+
+func TestRankedQueue_rerank(t *testing.T) {
+	t.Parallel()
+	e := &engine{state: make([]State, 3)}
+	rank := []int{1, 2, 3}
+	before := func(_ *engine, i, j int) bool { return rank[i] < rank[j] }
+	var q rankedQueue
+	for i := 0; i < 3; i++ {
+		q.push(i)
+	}
+	q.pop(e, before, func(int) bool { return false })
+	rank[2] = 0
+	q.rerank(2)
+	if got := q.pop(e, before, func(int) bool { return true }); got != 2 {
+		t.Errorf("pop() after rerank = %d, want 2", got)
+	}
+	if got, want := q.items(), []int{0, 1}; !reflect.DeepEqual(got, want) {
+		t.Errorf("items() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,279 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/olekukonko/tablewriter"
+)
+
+// Class summarizes the processes of one scheduling class: real-time ones,
+// which have a deadline or a period, or normal ones.
+type Class struct {
+	Name      string `json:"name"`
+	Processes int    `json:"processes"`
+	// DeadlineMisses counts the real-time processes that completed after
+	// their deadline or were killed, and MaxLateness is how long after its
+	// deadline the latest one completed.
+	DeadlineMisses int   `json:"deadline_misses,omitempty"`
+	MaxLateness    int64 `json:"max_lateness,omitempty"`
+	// AverageWait and AverageTurnaround cover the completed processes of
+	// the class, and MaxWait is the longest any of them waited, which for
+	// normal processes shows how far real-time ones starved them.
+	AverageWait       float64 `json:"average_wait"`
+	MaxWait           int64   `json:"max_wait"`
+	AverageTurnaround float64 `json:"average_turnaround"`
+	// CPUShare is the fraction of the CPU time up to the makespan, across
+	// all CPUs, the class spent running.
+	CPUShare float64 `json:"cpu_share"`
+}
+
+// realTime reports whether p is a real-time process.
+func (p Process) realTime() bool { return p.Deadline > 0 || p.Period > 0 }
+
+// deadline is when p must complete by: its relative deadline, or failing
+// that its period, after it arrives. It is 0 for a normal process.
+func (p Process) deadline() int64 {
+	switch {
+	case p.Deadline > 0:
+		return p.ArrivalTime + p.Deadline
+	case p.Period > 0:
+		return p.ArrivalTime + p.Period
+	}
+	return 0
+}
+
+// validateRealTime checks the deadline and period of p.
+func validateRealTime(p Process) error {
+	switch {
+	case p.Deadline < 0:
+		return fmt.Errorf("deadline %d must not be negative", p.Deadline)
+	case p.Period < 0:
+		return fmt.Errorf("period %d must not be negative", p.Period)
+	}
+	return nil
+}
+
+type edf struct{ opts Options }
+
+// NewEDF returns an earliest-deadline-first scheduler for a mix of real-time
+// and normal processes. Real-time processes, those with a deadline or period,
+// always run first in order of their absolute deadlines, preempting any
+// other; normal processes share the CPU time left round-robin, with the
+// WithQuantum time slice scaled by their nice values.
+func NewEDF(opts ...Option) Scheduler { return &edf{opts: newOptions(opts)} }
+
+func (s *edf) Schedule(ctx context.Context, processes []Process) (*ScheduleResult, error) {
+	if err := validate(processes, s.opts); err != nil {
+		return nil, err
+	}
+
+	return simulate(ctx, processes, s.opts, &mixed{before: s.before, slice: s.opts.Quantum})
+}
+
+func (s *edf) ScheduleLive(ctx context.Context, arrivals <-chan Process) (*ScheduleResult, error) {
+	return simulateLive(ctx, arrivals, s.opts, &mixed{before: s.before, slice: s.opts.Quantum})
+}
+
+// before reports whether real-time process i should be picked over j: the
+// one with the earlier deadline, falling back to the tie-break policy.
+func (s *edf) before(e *engine, i, j int) bool {
+	if di, dj := e.processes[i].deadline(), e.processes[j].deadline(); di != dj {
+		return di < dj
+	}
+	return s.opts.less(e.processes[i], e.processes[j])
+}
+
+type rm struct{ opts Options }
+
+// NewRM returns a rate-monotonic scheduler for a mix of real-time and normal
+// processes. It is NewEDF with real-time processes ranked statically by
+// period, the shortest first, and by relative deadline for those without a
+// period.
+func NewRM(opts ...Option) Scheduler { return &rm{opts: newOptions(opts)} }
+
+func (s *rm) Schedule(ctx context.Context, processes []Process) (*ScheduleResult, error) {
+	if err := validate(processes, s.opts); err != nil {
+		return nil, err
+	}
+
+	return simulate(ctx, processes, s.opts, &mixed{before: s.before, slice: s.opts.Quantum})
+}
+
+func (s *rm) ScheduleLive(ctx context.Context, arrivals <-chan Process) (*ScheduleResult, error) {
+	return simulateLive(ctx, arrivals, s.opts, &mixed{before: s.before, slice: s.opts.Quantum})
+}
+
+// before reports whether real-time process i should be picked over j: the
+// one with the shorter period, falling back to the tie-break policy.
+func (s *rm) before(e *engine, i, j int) bool {
+	if pi, pj := rate(e.processes[i]), rate(e.processes[j]); pi != pj {
+		return pi < pj
+	}
+	return s.opts.less(e.processes[i], e.processes[j])
+}
+
+// rate is the period rate-monotonic scheduling ranks p by.
+func rate(p Process) int64 {
+	if p.Period > 0 {
+		return p.Period
+	}
+	return p.Deadline
+}
+
+// mixed runs the ready real-time processes ranked first by before,
+// choosing again whenever an event is due, and serves normal processes in
+// the order they became ready, each for up to slice, when no real-time one
+// is ready. A normal process interrupted by an event resumes its slice
+// unless a real-time process takes its CPU, when it rejoins the back of the
+// queue.
+type mixed struct {
+	// added holds the processes made ready since the last pick, which
+	// sorts them into the ranked real-time ones and the normal ones.
+	added    []int
+	realTime rankedQueue
+	normal   []int
+	before   func(e *engine, i, j int) bool
+	slice    int64
+}
+
+func (m *mixed) add(i int)    { m.added = append(m.added, i) }
+func (m *mixed) len() int     { return len(m.added) + m.realTime.len() + len(m.normal) }
+func (m *mixed) rerank(i int) { m.realTime.rerank(i) }
+func (m *mixed) drop(i int) {
+	m.added, m.normal = without(m.added, i), without(m.normal, i)
+	m.realTime.remove(i)
+}
+
+// without returns s with the first i in it removed.
+func without(s []int, i int) []int {
+	for k, j := range s {
+		if j == i {
+			return append(s[:k], s[k+1:]...)
+		}
+	}
+	return s
+}
+
+func (m *mixed) pick(e *engine, allowed func(i int) bool) int {
+	for _, i := range m.added {
+		if e.processes[i].realTime() {
+			m.realTime.push(i)
+		} else {
+			m.normal = append(m.normal, i)
+		}
+	}
+	m.added = m.added[:0]
+	if i := m.realTime.pop(e, m.before, allowed); i >= 0 {
+		return i
+	}
+	normal := -1
+	for k, i := range m.normal {
+		if allowed(i) && (normal < 0 || (e.state[i] == StateRunning && e.state[m.normal[normal]] != StateRunning)) {
+			normal = k
+		}
+	}
+	if normal < 0 {
+		return -1
+	}
+	i := m.normal[normal]
+	m.normal = append(m.normal[:normal], m.normal[normal+1:]...)
+
+	return i
+}
+
+// quantum lets real-time processes run until they complete or are
+// preempted, and scales the slice of normal ones by their nice values.
+func (m *mixed) quantum(p Process) int64 {
+	if p.realTime() || m.slice == 0 {
+		return 0
+	}
+	return niceSlice(m.slice, p.Nice)
+}
+
+func (m *mixed) preemptive() bool { return true }
+func (m *mixed) queued() []int {
+	return append(append(m.realTime.items(), m.normal...), m.added...)
+}
+
+// classes summarizes the real-time and normal processes among rows, if any
+// row is real-time, with CPU shares taken from the time each process ran
+// over makespan on cpus CPUs.
+func classes(rows []Row, ran map[int64]int64, makespan float64, cpus int) []Class {
+	realTime := make(map[int64]bool, len(rows))
+	for _, row := range rows {
+		if row.Deadline > 0 {
+			realTime[row.ProcessID] = true
+		}
+	}
+	if len(realTime) == 0 {
+		return nil
+	}
+	cs := []Class{{Name: "real-time"}, {Name: "normal"}}
+	class := func(pid int64) int {
+		if realTime[pid] {
+			return 0
+		}
+		return 1
+	}
+	completed := make([]int, len(cs))
+	for _, row := range rows {
+		k := class(row.ProcessID)
+		c := &cs[k]
+		c.Processes++
+		if row.Missed {
+			c.DeadlineMisses++
+			if late := row.Exit - row.Deadline; !row.Killed && late > c.MaxLateness {
+				c.MaxLateness = late
+			}
+		}
+		if row.Killed {
+			continue
+		}
+		completed[k]++
+		c.AverageWait += float64(row.Wait)
+		c.AverageTurnaround += float64(row.Turnaround)
+		if row.Wait > c.MaxWait {
+			c.MaxWait = row.Wait
+		}
+	}
+	for pid, d := range ran {
+		cs[class(pid)].CPUShare += float64(d)
+	}
+	for k := range cs {
+		if completed[k] > 0 {
+			cs[k].AverageWait /= float64(completed[k])
+			cs[k].AverageTurnaround /= float64(completed[k])
+		}
+		if makespan > 0 {
+			cs[k].CPUShare /= makespan * float64(cpus)
+		}
+	}
+
+	return cs
+}
+
+// outputClasses writes the scheduling class summary, if any process is
+// real-time.
+func outputClasses(w io.Writer, cs []Class, u float64) {
+	if len(cs) == 0 {
+		return
+	}
+	_, _ = fmt.Fprintln(w, "Scheduling classes")
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"Class", "Processes", "Deadline misses", "Max lateness", "Average wait", "Max wait", "Average turnaround", "CPU share"})
+	for _, c := range cs {
+		table.Append([]string{
+			c.Name,
+			fmt.Sprint(c.Processes),
+			fmt.Sprint(c.DeadlineMisses),
+			unitTime(c.MaxLateness, u),
+			fmt.Sprintf("%.2f", c.AverageWait/u),
+			unitTime(c.MaxWait, u),
+			fmt.Sprintf("%.2f", c.AverageTurnaround/u),
+			fmt.Sprintf("%.0f%%", c.CPUShare*100),
+		})
+	}
+	table.Render()
+}
@@ -0,0 +1,149 @@
+package scheduler
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRealTime(t *testing.T) {
+	t.Parallel()
+	// EDF runs 2 first for its earlier deadline; RM runs 1 first for its
+	// shorter period, and 2 completes 2 after its deadline.
+	processes := []Process{
+		{ProcessID: 1, BurstDuration: 3, Period: 5},
+		{ProcessID: 2, BurstDuration: 2, Deadline: 3, Period: 20},
+		{ProcessID: 3, BurstDuration: 4},
+	}
+	tests := []struct {
+		name       string
+		scheduler  Scheduler
+		wantGantt  []TimeSlice
+		wantMissed []bool
+		wantRT     Class
+	}{
+		{
+			name:       "edf",
+			scheduler:  NewEDF(),
+			wantGantt:  []TimeSlice{{PID: 2, Start: 0, Stop: 2}, {PID: 1, Start: 2, Stop: 5}, {PID: 3, Start: 5, Stop: 7}, {PID: 3, Start: 7, Stop: 9}},
+			wantMissed: []bool{false, false, false},
+			wantRT:     Class{Name: "real-time", Processes: 2, AverageWait: 1, MaxWait: 2, AverageTurnaround: 3.5, CPUShare: 5.0 / 9},
+		},
+		{
+			name:       "rm",
+			scheduler:  NewRM(),
+			wantGantt:  []TimeSlice{{PID: 1, Start: 0, Stop: 3}, {PID: 2, Start: 3, Stop: 5}, {PID: 3, Start: 5, Stop: 7}, {PID: 3, Start: 7, Stop: 9}},
+			wantMissed: []bool{false, true, false},
+			wantRT:     Class{Name: "real-time", Processes: 2, DeadlineMisses: 1, MaxLateness: 2, AverageWait: 1.5, MaxWait: 3, AverageTurnaround: 4, CPUShare: 5.0 / 9},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			r, err := tt.scheduler.Schedule(context.Background(), processes)
+			if err != nil {
+				t.Fatalf("Schedule() error = %v", err)
+			}
+			if !reflect.DeepEqual(r.Gantt, tt.wantGantt) {
+				t.Errorf("Gantt = %v, want %v", r.Gantt, tt.wantGantt)
+			}
+			for k, want := range tt.wantMissed {
+				if got := r.Rows[k].Missed; got != want {
+					t.Errorf("process %d Missed = %v, want %v", r.Rows[k].ProcessID, got, want)
+				}
+			}
+			if len(r.Classes) != 2 || !reflect.DeepEqual(r.Classes[0], tt.wantRT) {
+				t.Errorf("Classes = %+v, want real-time %+v", r.Classes, tt.wantRT)
+			}
+			if r.Metrics.DeadlineMisses != tt.wantRT.DeadlineMisses {
+				t.Errorf("DeadlineMisses = %d, want %d", r.Metrics.DeadlineMisses, tt.wantRT.DeadlineMisses)
+			}
+		})
+	}
+}
+
+func TestRealTime_preemptsNormal(t *testing.T) {
+	t.Parallel()
+	// 2 arrives during 1's slice and runs straight away; 1 then rejoins the
+	// queue behind 3.
+	r, err := NewEDF(WithQuantum(4)).Schedule(context.Background(), []Process{
+		{ProcessID: 1, BurstDuration: 6},
+		{ProcessID: 2, BurstDuration: 2, ArrivalTime: 1, Deadline: 5},
+		{ProcessID: 3, BurstDuration: 2},
+	})
+	if err != nil {
+		t.Fatalf("Schedule() error = %v", err)
+	}
+	want := []TimeSlice{
+		{PID: 1, Start: 0, Stop: 1},
+		{PID: 2, Start: 1, Stop: 3},
+		{PID: 3, Start: 3, Stop: 5},
+		{PID: 1, Start: 5, Stop: 9},
+		{PID: 1, Start: 9, Stop: 10},
+	}
+	if !reflect.DeepEqual(r.Gantt, want) {
+		t.Errorf("Gantt = %v, want %v", r.Gantt, want)
+	}
+	if r.Classes[1].MaxWait != 4 {
+		t.Errorf("normal MaxWait = %d, want 4", r.Classes[1].MaxWait)
+	}
+}
+
+func TestRealTime_normalOnlyIsRR(t *testing.T) {
+	t.Parallel()
+	// Arrivals interrupt the running process, which carries on with the
+	// rest of its quantum, so normal processes alone are round-robin.
+	processes := []Process{
+		{ProcessID: 1, BurstDuration: 4},
+		{ProcessID: 2, BurstDuration: 4},
+		{ProcessID: 3, BurstDuration: 1, ArrivalTime: 1},
+	}
+	want, err := NewRR(WithQuantum(2)).Schedule(context.Background(), processes)
+	if err != nil {
+		t.Fatalf("Schedule() error = %v", err)
+	}
+	for _, s := range []Scheduler{NewEDF(WithQuantum(2)), NewRM(WithQuantum(2))} {
+		r, err := s.Schedule(context.Background(), processes)
+		if err != nil {
+			t.Fatalf("Schedule() error = %v", err)
+		}
+		if !reflect.DeepEqual(r.Gantt, want.Gantt) {
+			t.Errorf("Gantt = %v, want %v", r.Gantt, want.Gantt)
+		}
+		if r.Classes != nil {
+			t.Errorf("Classes = %+v without real-time processes, want nil", r.Classes)
+		}
+	}
+}
+
+func TestRealTime_invalid(t *testing.T) {
+	t.Parallel()
+	_, err := NewEDF().Schedule(context.Background(), []Process{{ProcessID: 1, BurstDuration: 2, Deadline: -1}})
+	if !errors.Is(err, ErrValidation) {
+		t.Errorf("Schedule() error = %v, want %v", err, ErrValidation)
+	}
+}
+
+func TestRenderText_classes(t *testing.T) {
+	t.Parallel()
+	r, err := NewRM().Schedule(context.Background(), []Process{
+		{ProcessID: 1, BurstDuration: 3, Period: 5},
+		{ProcessID: 2, BurstDuration: 2, Deadline: 3, Period: 20},
+	})
+	if err != nil {
+		t.Fatalf("Schedule() error = %v", err)
+	}
+	var out bytes.Buffer
+	if err := RenderText(&out, "rm", r); err != nil {
+		t.Fatalf("RenderText() error = %v", err)
+	}
+	for _, want := range []string{"5 (late)", "Scheduling classes", "| real-time |         2 |               1 |"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("RenderText() missing %q in\n%s", want, out.String())
+		}
+	}
+}
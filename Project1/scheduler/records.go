@@ -0,0 +1,171 @@
+package scheduler
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"io"
+)
+
+// recordReader reads the records of a scheduling file as byte slices into
+// its own buffer, so the integer fields of plain records are parsed where
+// they lie instead of each becoming a string first. Plain records, which is
+// every record a scheduler writes, are split on commas directly; the first
+// line with a quote in it hands it and the rest of the file to encoding/csv,
+// which knows how quoted fields may span lines.
+type recordReader struct {
+	br *bufio.Reader
+	// long holds a line too long for br's buffer.
+	long []byte
+	// line is the line of the current record, and columns the 1-based
+	// column each of its fields starts at.
+	line    int
+	fields  [][]byte
+	columns []int
+
+	// cr reads the rest of the file once a quote turns up, and skipped is
+	// the number of lines read before it.
+	cr      *csv.Reader
+	skipped int
+}
+
+// readBufferSize fits the longest records most scheduling files have.
+const readBufferSize = 64 << 10
+
+func newRecordReader(r io.Reader) *recordReader {
+	return &recordReader{br: bufio.NewReaderSize(r, readBufferSize)}
+}
+
+// Read returns the fields of the next record, valid until the next call,
+// or io.EOF once there are none left. Empty lines are skipped. Malformed
+// quoting is reported as a *csv.ParseError, with lines counted from the
+// start of the file.
+func (r *recordReader) Read() ([][]byte, error) {
+	if r.cr != nil {
+		return r.readCSV()
+	}
+	for {
+		line, err := r.readLine()
+		if len(line) == 0 {
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+		r.line++
+		if bytes.IndexByte(line, '"') >= 0 {
+			// Copied, since the csv.Reader reads past where line points.
+			line = append([]byte(nil), line...)
+			r.cr = csv.NewReader(io.MultiReader(bytes.NewReader(line), r.br))
+			r.cr.FieldsPerRecord = -1
+			r.cr.ReuseRecord = true
+			r.skipped = r.line - 1
+			return r.readCSV()
+		}
+		line = bytes.TrimSuffix(line, []byte{'\n'})
+		line = bytes.TrimSuffix(line, []byte{'\r'})
+		if len(line) == 0 {
+			continue
+		}
+
+		r.fields, r.columns = r.fields[:0], r.columns[:0]
+		for start := 0; ; {
+			end := bytes.IndexByte(line[start:], ',')
+			if end < 0 {
+				r.fields = append(r.fields, line[start:])
+				r.columns = append(r.columns, start+1)
+				break
+			}
+			r.fields = append(r.fields, line[start:start+end])
+			r.columns = append(r.columns, start+1)
+			start += end + 1
+		}
+		return r.fields, nil
+	}
+}
+
+// readLine returns the next line including its newline, or the last line
+// and the error that ended it.
+func (r *recordReader) readLine() ([]byte, error) {
+	line, err := r.br.ReadSlice('\n')
+	if err == bufio.ErrBufferFull {
+		r.long = append(r.long[:0], line...)
+		for err == bufio.ErrBufferFull {
+			line, err = r.br.ReadSlice('\n')
+			r.long = append(r.long, line...)
+		}
+		line = r.long
+	}
+	return line, err
+}
+
+// readCSV reads the next record with r.cr.
+func (r *recordReader) readCSV() ([][]byte, error) {
+	record, err := r.cr.Read()
+	if err != nil {
+		var csvErr *csv.ParseError
+		if errors.As(err, &csvErr) {
+			csvErr.StartLine += r.skipped
+			csvErr.Line += r.skipped
+		}
+		return nil, err
+	}
+	r.line, _ = r.cr.FieldPos(0)
+	r.line += r.skipped
+	r.fields, r.columns = r.fields[:0], r.columns[:0]
+	for j, f := range record {
+		_, column := r.cr.FieldPos(j)
+		r.fields = append(r.fields, []byte(f))
+		r.columns = append(r.columns, column)
+	}
+	return r.fields, nil
+}
+
+// FieldPos returns the line and column of field j of the current record.
+// A quoted field that spans lines is reported at the record's first line.
+func (r *recordReader) FieldPos(j int) (line, column int) {
+	if r.cr != nil {
+		line, column = r.cr.FieldPos(j)
+		return line + r.skipped, column
+	}
+	return r.line, r.columns[j]
+}
+
+// atoi parses b as a decimal integer with an optional sign, the way
+// strconv.ParseInt would, without converting it to a string. It reports
+// false for anything else, including numbers long enough to overflow, so
+// the caller can fall back to a parser that explains what is wrong.
+func atoi(b []byte) (int64, bool) {
+	neg := false
+	if len(b) > 0 && (b[0] == '-' || b[0] == '+') {
+		neg = b[0] == '-'
+		b = b[1:]
+	}
+	// 18 digits always fit in an int64.
+	if len(b) == 0 || len(b) > 18 {
+		return 0, false
+	}
+	var n int64
+	for _, c := range b {
+		if c < '0' || c > '9' {
+			return 0, false
+		}
+		n = n*10 + int64(c-'0')
+	}
+	if neg {
+		n = -n
+	}
+	return n, true
+}
+
+// parseField parses an integer field in units of 1/scale, straight from b
+// when it is a plain integer, and otherwise as a string with parse.
+func parseField(b []byte, scale int64, parse func(string) (int64, error)) (int64, error) {
+	if n, ok := atoi(b); ok {
+		if _, ok := mulTime(absTime(n), scale); ok {
+			return n * scale, nil
+		}
+	}
+	return parse(string(b))
+}
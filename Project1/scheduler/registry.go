@@ -0,0 +1,106 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Factory builds a scheduler configured by opts. Factories may be called
+// from several goroutines at once, as the CLI does to run algorithms side by
+// side.
+type Factory func(opts ...Option) Scheduler
+
+var registry = struct {
+	sync.RWMutex
+	names     []string
+	factories map[string]Factory
+}{factories: make(map[string]Factory)}
+
+func init() {
+	Register("fcfs", NewFCFS)
+	Register("sjf", NewSJF)
+	Register("priority", NewPriority)
+	Register("rr", NewRR)
+	Register("edf", NewEDF)
+	Register("rm", NewRM)
+}
+
+// Register makes a scheduler available by name, so it can be listed and
+// selected alongside the built-in algorithms. It panics if the name is
+// empty, already registered, or the factory is nil.
+func Register(name string, factory Factory) {
+	registry.Lock()
+	defer registry.Unlock()
+
+	switch {
+	case name == "":
+		panic("scheduler: Register with empty name")
+	case factory == nil:
+		panic(fmt.Sprintf("scheduler: Register %q with nil factory", name))
+	case registry.factories[name] != nil:
+		panic(fmt.Sprintf("scheduler: Register called twice for %q", name))
+	}
+	registry.factories[name] = factory
+	registry.names = append(registry.names, name)
+}
+
+// Lookup returns the factory registered under name.
+func Lookup(name string) (Factory, bool) {
+	registry.RLock()
+	defer registry.RUnlock()
+
+	factory, ok := registry.factories[name]
+
+	return factory, ok
+}
+
+// Names returns the registered scheduler names in registration order,
+// built-in algorithms first.
+func Names() []string {
+	registry.RLock()
+	defer registry.RUnlock()
+
+	return append([]string(nil), registry.names...)
+}
+
+// ErrUnknownAlgorithm is returned when no scheduler is registered under a name.
+var ErrUnknownAlgorithm = errors.New("unknown algorithm")
+
+// Result is the outcome of running one algorithm through Run.
+type Result struct {
+	// Algorithm is the registered name the result was produced by.
+	Algorithm string
+	ScheduleResult
+	// Report is the schedule rendered by RenderText.
+	Report string
+}
+
+// Run schedules procs with the algorithm registered under name, configured by opts.
+func Run(name string, procs []Process, opts ...Option) (Result, error) {
+	return RunContext(context.Background(), name, procs, opts...)
+}
+
+// RunContext is like Run, but stops early with partial results when ctx is cancelled.
+func RunContext(ctx context.Context, name string, procs []Process, opts ...Option) (Result, error) {
+	factory, ok := Lookup(name)
+	if !ok {
+		return Result{}, fmt.Errorf("%w: %q", ErrUnknownAlgorithm, name)
+	}
+
+	result := Result{Algorithm: name}
+	r, err := factory(opts...).Schedule(ctx, procs)
+	if r == nil {
+		return result, err
+	}
+	result.ScheduleResult = *r
+	var report strings.Builder
+	if renderErr := RenderText(&report, name, r); err == nil {
+		err = renderErr
+	}
+	result.Report = report.String()
+
+	return result, err
+}
@@ -0,0 +1,113 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type noopScheduler struct{}
+
+func (noopScheduler) Schedule(context.Context, []Process) (*ScheduleResult, error) {
+	return &ScheduleResult{}, nil
+}
+
+func TestRegister(t *testing.T) {
+	t.Parallel()
+	Register("test-noop", func(...Option) Scheduler { return noopScheduler{} })
+
+	factory, ok := Lookup("test-noop")
+	if !ok {
+		t.Fatal("Lookup() did not find the registered scheduler")
+	}
+	if _, ok := factory().(noopScheduler); !ok {
+		t.Errorf("factory() = %T, want noopScheduler", factory())
+	}
+
+	names := Names()
+	if want := []string{"fcfs", "sjf", "priority", "rr"}; !reflect.DeepEqual(names[:len(want)], want) {
+		t.Errorf("Names() = %v, want built-ins first %v", names, want)
+	}
+	if names[len(names)-1] != "test-noop" {
+		t.Errorf("Names() = %v, want test-noop last", names)
+	}
+}
+
+func TestRegister_panics(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		reg     string
+		factory Factory
+	}{
+		{name: "empty name", factory: NewFCFS},
+		{name: "nil factory", reg: "test-nil"},
+		{name: "duplicate", reg: "fcfs", factory: NewFCFS},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			defer func() {
+				if recover() == nil {
+					t.Error("Register() did not panic")
+				}
+			}()
+			Register(tt.reg, tt.factory)
+		})
+	}
+}
+
+func TestRun(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: 1, ArrivalTime: 0, BurstDuration: 5, Priority: 2},
+		{ProcessID: 2, ArrivalTime: 3, BurstDuration: 9, Priority: 1},
+		{ProcessID: 3, ArrivalTime: 6, BurstDuration: 6, Priority: 3},
+	}
+	tests := []struct {
+		name    string
+		alg     string
+		opts    []Option
+		want    Metrics
+		wantErr error
+	}{
+		{
+			name: "fcfs",
+			alg:  "fcfs",
+			want: Metrics{AverageWait: 10.0 / 3, AverageTurnaround: 10, Throughput: 3.0 / 20, Completed: 3, Makespan: 20, ContextSwitches: 2, Utilization: 1},
+		},
+		{
+			name:    "invalid option",
+			alg:     "rr",
+			opts:    []Option{WithQuantum(-1)},
+			wantErr: ErrValidation,
+		},
+		{
+			name:    "unknown",
+			alg:     "lottery",
+			wantErr: ErrUnknownAlgorithm,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := Run(tt.alg, processes, tt.opts...)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Run() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got.Algorithm != tt.alg || !reflect.DeepEqual(got.Metrics, tt.want) {
+				t.Errorf("Run() = %+v, want metrics %+v", got, tt.want)
+			}
+			if !strings.Contains(got.Report, "Gantt schedule") {
+				t.Errorf("Run() report is missing the Gantt chart:\n%s", got.Report)
+			}
+		})
+	}
+}
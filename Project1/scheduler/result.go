@@ -0,0 +1,437 @@
+package scheduler
+
+import (
+	"context"
+	"io"
+)
+
+// ScheduleResult is the outcome of a schedule, independent of how it is rendered.
+type ScheduleResult struct {
+	// Seed is the seed given with WithSeed, if any.
+	Seed  *int64      `json:"seed,omitempty"`
+	Gantt []TimeSlice `json:"gantt"`
+	// Streamed is set when the Gantt chart was handed slice by slice to
+	// Options.Slices instead of kept, which leaves Gantt empty.
+	Streamed bool `json:"streamed,omitempty"`
+	// Rows holds one row per completed or killed process, in input order.
+	Rows    []Row   `json:"rows"`
+	Metrics Metrics `json:"metrics"`
+	// Total is the number of processes given to the scheduler.
+	Total int `json:"total"`
+	// Cancelled is set when the context was cancelled before every process completed.
+	Cancelled bool `json:"cancelled,omitempty"`
+	// Truncated is set when the simulation stopped at Options.MaxTime
+	// before every process finished, and Unfinished lists the processes
+	// that had not, in input order.
+	Truncated  bool         `json:"truncated,omitempty"`
+	Unfinished []Unfinished `json:"unfinished,omitempty"`
+	// Checkpoint is set when the simulation paused at Options.CheckpointAt,
+	// which also sets Truncated, to save and resume it from.
+	Checkpoint *Checkpoint `json:"-"`
+	// Transitions are the state changes of every process, in time order.
+	Transitions []Transition `json:"transitions,omitempty"`
+	// TicksPerUnit is how many ticks, the unit of every time in the result,
+	// make one unit of time in text and HTML reports, when more than one.
+	TicksPerUnit int64 `json:"ticks_per_unit,omitempty"`
+	// Inversions are the intervals processes spent blocked on a lock held
+	// by a process of lower priority, in the order they began.
+	Inversions []Inversion `json:"inversions,omitempty"`
+	// Ideal is the metrics of the same schedule simulated without switch
+	// cost or dispatch latency, set by Schedule when either is charged, so
+	// the difference from Metrics is what the overhead costs.
+	Ideal *Metrics `json:"ideal,omitempty"`
+	// Suspensions are the intervals processes were suspended, as slices of
+	// kind SliceSuspended, in the order they began.
+	Suspensions []TimeSlice `json:"suspensions,omitempty"`
+	// Memory is the total memory processes were admitted into, if limited.
+	Memory int64 `json:"memory,omitempty"`
+	// Classes summarize the real-time and the normal processes, when any
+	// process is real-time.
+	Classes []Class `json:"classes,omitempty"`
+	// Tasks summarize the jobs of each periodic task expanded by
+	// ExpandPeriodic, in input order.
+	Tasks []Task `json:"tasks,omitempty"`
+	// Violations are the invariants the schedule breaks, as found by
+	// Verify: none, unless the scheduler has a bug.
+	Violations []Violation `json:"violations,omitempty"`
+	// Trees summarize each process that spawned children together with its
+	// descendants, in input order of their roots.
+	Trees []Tree `json:"trees,omitempty"`
+}
+
+// Row is the timing of one completed or killed process. Wait is the time it spent
+// ready but not running or blocked on a lock, so it excludes IO, the time
+// blocked on I/O.
+type Row struct {
+	ProcessID int64 `json:"pid"`
+	Priority  int64 `json:"priority"`
+	// BurstDuration is the total CPU time across all of the process's bursts.
+	BurstDuration int64 `json:"burst"`
+	IO            int64 `json:"io,omitempty"`
+	ArrivalTime   int64 `json:"arrival"`
+	Wait          int64 `json:"wait"`
+	Turnaround    int64 `json:"turnaround"`
+	Exit          int64 `json:"exit"`
+	Nice          int64 `json:"nice,omitempty"`
+	// Memory is the memory the process needed, and Admission how long it
+	// waited after arriving for that much to be free.
+	Memory    int64 `json:"memory,omitempty"`
+	Admission int64 `json:"admission,omitempty"`
+	// Deadline is when the process had to complete by, if it is real-time,
+	// and Missed is set when it completed after it or was killed.
+	Deadline int64 `json:"deadline,omitempty"`
+	Missed   bool  `json:"missed,omitempty"`
+	// Task and Job identify the periodic task the process is a job of, if
+	// it is one.
+	Task int64 `json:"task,omitempty"`
+	Job  int   `json:"job,omitempty"`
+	// Parent is the process that spawned this one, if any.
+	Parent int64 `json:"parent,omitempty"`
+	// Slice is the process's time slice, scaled by its nice value, under
+	// schedulers that give processes a quantum.
+	Slice int64 `json:"slice,omitempty"`
+	// Residency is how long the process spent in each state.
+	Residency Residency `json:"residency"`
+	// Killed is set when the process was terminated at Exit before it
+	// completed. Killed processes are left out of the averages.
+	Killed bool `json:"killed,omitempty"`
+	// Excluded is set when the process completed but arrived during the
+	// warm-up or completed during the cool-down, so it is left out of the
+	// averages too.
+	Excluded bool `json:"excluded,omitempty"`
+}
+
+// Unfinished is a process that had not finished when the simulation reached
+// its maximum time.
+type Unfinished struct {
+	ProcessID     int64 `json:"pid"`
+	Priority      int64 `json:"priority"`
+	BurstDuration int64 `json:"burst"`
+	ArrivalTime   int64 `json:"arrival"`
+	// Remaining is the CPU time it still needed, across all its bursts.
+	Remaining int64 `json:"remaining"`
+	// State is the state it was left in: new if it had yet to arrive.
+	State State `json:"state"`
+}
+
+// Metrics summarizes a schedule.
+type Metrics struct {
+	AverageWait       float64 `json:"average_wait"`
+	AverageTurnaround float64 `json:"average_turnaround"`
+	// AverageAdmission is the average time processes waited for memory
+	// before they were admitted, when memory is limited.
+	AverageAdmission float64 `json:"average_admission,omitempty"`
+	// Throughput is completed processes per unit of time, counting only
+	// processes and time outside the warm-up and cool-down.
+	Throughput float64 `json:"throughput"`
+	// Completed is the number of processes that ran to completion.
+	Completed int `json:"completed"`
+	// Killed is the number of processes killed before they completed.
+	Killed int `json:"killed,omitempty"`
+	// Excluded is the number of completed processes left out of the
+	// averages by the warm-up and cool-down.
+	Excluded int `json:"excluded,omitempty"`
+	// DeadlineMisses is the number of real-time processes that missed their
+	// deadlines.
+	DeadlineMisses int `json:"deadline_misses,omitempty"`
+	// Makespan is when the last process completed.
+	Makespan float64 `json:"makespan"`
+	// ContextSwitches counts changes of running process between Gantt slices.
+	ContextSwitches int `json:"context_switches"`
+	// Migrations counts the runs that started on a different CPU than the
+	// process last ran on, and MigrationPenalty the time charged for them.
+	Migrations       int     `json:"migrations,omitempty"`
+	MigrationPenalty float64 `json:"migration_penalty,omitempty"`
+	// Overhead is the CPU time spent switching, dispatching, and migrating,
+	// summed across CPUs.
+	Overhead float64 `json:"overhead,omitempty"`
+	// Energy is the energy the CPUs used up to the makespan under the
+	// model set with WithPower, and IdleEnergy how much of it they used
+	// idle.
+	Energy     float64 `json:"energy,omitempty"`
+	IdleEnergy float64 `json:"idle_energy,omitempty"`
+	// Utilization is the fraction of the CPU time up to the makespan, across
+	// all CPUs, spent running processes rather than idle or on overhead.
+	// Like Overhead, it leaves out the warm-up and cool-down.
+	Utilization float64 `json:"utilization"`
+	// Cores reports the work done by each CPU, when there is more than one.
+	Cores []Core `json:"cores,omitempty"`
+	// LoadImbalance is how far the busiest core's work exceeds the mean, as
+	// a fraction of the mean: 0 when every core did the same work.
+	LoadImbalance float64 `json:"load_imbalance,omitempty"`
+	// Imprecise is set when a total the metrics are computed from, such as
+	// the summed waits or the makespan, exceeds 2^53 ticks, beyond which a
+	// float64 rounds it, so the metrics may be off by the rounding.
+	Imprecise bool `json:"imprecise,omitempty"`
+}
+
+// MetricsUpdate is the running metrics of a simulation as each process
+// completes or is killed, in ticks. They cover every process terminated by
+// At, before the warm-up and cool-down are left out, so the final metrics
+// may differ from the last update.
+type MetricsUpdate struct {
+	At        int64 `json:"at"`
+	Completed int   `json:"completed"`
+	Killed    int   `json:"killed,omitempty"`
+	Total     int   `json:"total"`
+	// AverageWait and AverageTurnaround average the completed processes,
+	// and Throughput is how many completed per tick up to At.
+	AverageWait       float64 `json:"average_wait"`
+	AverageTurnaround float64 `json:"average_turnaround"`
+	Throughput        float64 `json:"throughput"`
+}
+
+// Core summarizes the work done by one CPU.
+type Core struct {
+	CPU int `json:"cpu"`
+	// Speed is the core's speed multiplier, 1 unless set with WithSpeeds.
+	Speed float64 `json:"speed"`
+	// Busy is the time the core spent running processes, excluding overhead.
+	Busy int64 `json:"busy"`
+	// Work is the burst time the core completed: Busy scaled by Speed.
+	Work float64 `json:"work"`
+	// Utilization is Busy as a fraction of the makespan.
+	Utilization float64 `json:"utilization"`
+}
+
+// result assembles the ScheduleResult of a finished (or, when cancelErr is
+// non-nil, partially finished) simulation and returns it with cancelErr.
+// Processes that never finished have a nil schedule entry and are left out
+// of the rows; killed processes, and those in the warm-up or cool-down, are
+// left out of the averages. The metrics taken from the Gantt chart come from
+// t, which has already totalled the slices if they were streamed or not
+// kept, and are otherwise totalled from gantt.
+func (o Options) result(gantt []TimeSlice, t *tally, schedule []*Row, totalWait, totalTurnaround, lastCompletion float64, cancelErr error) (*ScheduleResult, error) {
+	r := &ScheduleResult{
+		Gantt:     gantt,
+		Streamed:  o.Slices != nil,
+		Rows:      newRows(len(schedule)),
+		Total:     len(schedule),
+		Cancelled: cancelErr != nil,
+	}
+	if o.TicksPerUnit > 1 {
+		r.TicksPerUnit = o.TicksPerUnit
+	}
+	r.Memory = o.Memory
+	if o.seeded {
+		seed := o.Seed
+		r.Seed = &seed
+	}
+	for _, row := range schedule {
+		if row != nil {
+			r.Rows = append(r.Rows, *row)
+		}
+	}
+
+	r.Trees = trees(r.Rows)
+	from, to := o.WarmUp, int64(lastCompletion)-o.CoolDown
+	if t == nil {
+		t = o.newTally(from, to)
+		for _, ts := range gantt {
+			t.add(ts)
+		}
+	}
+	r.Metrics = Metrics{
+		Makespan:         lastCompletion,
+		ContextSwitches:  t.switches,
+		Migrations:       t.migrations,
+		Overhead:         float64(t.overhead),
+		MigrationPenalty: float64(t.penalty),
+	}
+	busy := t.busy
+	r.Metrics.Imprecise = totalWait > maxExact || totalTurnaround > maxExact || lastCompletion > maxExact || busy > maxExact
+	window := float64(to - from)
+	if window > 0 {
+		r.Metrics.Utilization = float64(busy) / (window * float64(o.CPUs))
+	}
+	for k := range r.Rows {
+		row := &r.Rows[k]
+		if row.Deadline > 0 && (row.Killed || row.Exit > row.Deadline) {
+			row.Missed = true
+			r.Metrics.DeadlineMisses++
+		}
+		switch {
+		case row.Killed:
+			r.Metrics.Killed++
+		case row.ArrivalTime < from || row.Exit > to:
+			row.Excluded = true
+			r.Metrics.Completed++
+			r.Metrics.Excluded++
+			totalWait -= float64(row.Wait)
+			totalTurnaround -= float64(row.Turnaround)
+		default:
+			r.Metrics.Completed++
+			r.Metrics.AverageAdmission += float64(row.Admission)
+		}
+	}
+	r.Classes = classes(r.Rows, t.ran, lastCompletion, o.CPUs)
+	if o.Power != (Power{}) {
+		r.Metrics.Energy, r.Metrics.IdleEnergy = o.energy(t.active, lastCompletion)
+	}
+	if o.CPUs > 1 {
+		r.Metrics.Cores, r.Metrics.LoadImbalance = cores(t.run, lastCompletion)
+	}
+	if count := float64(r.Metrics.Completed - r.Metrics.Excluded); count > 0 {
+		r.Metrics.AverageWait = totalWait / count
+		r.Metrics.AverageTurnaround = totalTurnaround / count
+		r.Metrics.AverageAdmission /= count
+		if window > 0 {
+			r.Metrics.Throughput = count / window
+		}
+	}
+
+	return r, cancelErr
+}
+
+// UnitMetrics returns the metrics of r with times in units of TicksPerUnit
+// ticks, as reports show them: the averages, makespan, overhead, and
+// per-core work divided by TicksPerUnit and throughput multiplied by it.
+// Per-core busy times stay in ticks.
+func (r *ScheduleResult) UnitMetrics() Metrics {
+	return unitMetrics(r.Metrics, r.ticksPerUnit())
+}
+
+// UnitIdeal is UnitMetrics for the ideal metrics, or nil if there are none.
+func (r *ScheduleResult) UnitIdeal() *Metrics {
+	if r.Ideal == nil {
+		return nil
+	}
+	m := unitMetrics(*r.Ideal, r.ticksPerUnit())
+	return &m
+}
+
+func unitMetrics(m Metrics, u float64) Metrics {
+	if u == 1 {
+		return m
+	}
+	m.AverageWait /= u
+	m.AverageTurnaround /= u
+	m.AverageAdmission /= u
+	m.Throughput *= u
+	m.Makespan /= u
+	m.Overhead /= u
+	m.MigrationPenalty /= u
+	m.Energy /= u
+	m.IdleEnergy /= u
+	m.Cores = append([]Core(nil), m.Cores...)
+	for c := range m.Cores {
+		m.Cores[c].Work /= u
+	}
+
+	return m
+}
+
+// tally totals what the metrics need from a Gantt chart a slice at a time,
+// in the chart's order, so a chart streamed to Options.Slices need not be
+// kept to report on it.
+type tally struct {
+	// from and to bound the window busy and the overheads are counted in.
+	from, to int64
+	busy     int64
+	overhead int64
+	penalty  int64
+	// switches counts the changes of running process on each core, going
+	// by the last process each ran, and migrations the runs of each process
+	// that began on a different core than its previous run.
+	switches, migrations int
+	lastPID              map[int]int64
+	lastCPU              map[int64]int
+	// run is the time each core spent running processes, active the time
+	// it was neither idle nor suspended, and ran the time each process ran.
+	run, active []int64
+	ran         map[int64]int64
+}
+
+// newTally returns an empty tally of a chart on o.CPUs cores, counting busy
+// time and overheads from from until to.
+func (o Options) newTally(from, to int64) *tally {
+	return &tally{
+		from:    from,
+		to:      to,
+		lastPID: make(map[int]int64),
+		lastCPU: make(map[int64]int),
+		run:     make([]int64, o.CPUs),
+		active:  make([]int64, o.CPUs),
+		ran:     make(map[int64]int64),
+	}
+}
+
+// add totals the next slice of the chart.
+func (t *tally) add(ts TimeSlice) {
+	d := ts.Stop - ts.Start
+	if ts.Kind != SliceIdle && ts.Kind != SliceSuspended && ts.CPU < len(t.active) {
+		t.active[ts.CPU] += d
+	}
+	if ts.Kind == SliceRun {
+		if pid, ok := t.lastPID[ts.CPU]; ok && ts.PID != pid {
+			t.switches++
+		}
+		t.lastPID[ts.CPU] = ts.PID
+		if cpu, ok := t.lastCPU[ts.PID]; ok && cpu != ts.CPU {
+			t.migrations++
+		}
+		t.lastCPU[ts.PID] = ts.CPU
+		if ts.CPU < len(t.run) {
+			t.run[ts.CPU] += d
+		}
+		t.ran[ts.PID] += d
+	}
+
+	start, stop := ts.Start, ts.Stop
+	if start < t.from {
+		start = t.from
+	}
+	if stop > t.to {
+		stop = t.to
+	}
+	if start >= stop {
+		return
+	}
+	switch ts.Kind {
+	case SliceRun:
+		t.busy += stop - start
+	case SliceSwitch, SliceDispatch:
+		t.overhead += stop - start
+	case SliceMigrate:
+		t.overhead += stop - start
+		t.penalty += stop - start
+	}
+}
+
+// cores summarizes the time each core spent running processes over makespan
+// and how unevenly the work was spread.
+func cores(run []int64, makespan float64) ([]Core, float64) {
+	cs := make([]Core, len(run))
+	var total, most int64
+	for c := range cs {
+		cs[c].CPU = c
+		cs[c].Busy = run[c]
+		total += run[c]
+		if makespan > 0 {
+			cs[c].Utilization = float64(cs[c].Busy) / makespan
+		}
+		if cs[c].Busy > most {
+			most = cs[c].Busy
+		}
+	}
+	if total == 0 {
+		return cs, 0
+	}
+	mean := float64(total) / float64(len(run))
+
+	return cs, float64(most)/mean - 1
+}
+
+// scheduleText runs s and renders the result as text under title. Partial
+// results of a cancelled run are still rendered before the error is returned.
+func scheduleText(ctx context.Context, w io.Writer, title string, s Scheduler, processes []Process) error {
+	r, err := s.Schedule(ctx, processes)
+	if r == nil {
+		return err
+	}
+	if renderErr := RenderText(w, title, r); err == nil {
+		err = renderErr
+	}
+
+	return err
+}
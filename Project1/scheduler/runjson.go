@@ -0,0 +1,74 @@
+package scheduler
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+// jsonRequest is the input of RunJSON.
+type jsonRequest struct {
+	Algorithm string          `json:"algorithm"`
+	Processes []Process       `json:"processes"`
+	Options   json.RawMessage `json:"options"`
+}
+
+// jsonResponse is the output of RunJSON.
+type jsonResponse struct {
+	Algorithm string `json:"algorithm,omitempty"`
+	*ScheduleResult
+	Report string `json:"report,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// RunJSON is Run for callers outside Go, such as the C and WebAssembly
+// bindings, with the request and response as JSON. The request is an
+// object with the registered algorithm, the processes as Process marshals
+// them, and any Options to change from DefaultOptions, named as their
+// fields:
+//
+//	{"algorithm": "rr", "processes": [...], "options": {"Quantum": 4}}
+//
+// The processes are validated and periodic tasks expanded into jobs. The
+// response is the result as RenderJSON writes it, with the algorithm and
+// the text report, or {"error": "..."} if the request could not be run.
+func RunJSON(in []byte) []byte {
+	resp, err := runJSON(in)
+	if err != nil {
+		resp = jsonResponse{Error: err.Error()}
+	}
+	out, err := json.Marshal(resp)
+	if err != nil {
+		out, _ = json.Marshal(jsonResponse{Error: err.Error()})
+	}
+
+	return out
+}
+
+func runJSON(in []byte) (jsonResponse, error) {
+	var req jsonRequest
+	if err := json.Unmarshal(in, &req); err != nil {
+		return jsonResponse{}, err
+	}
+	if req.Algorithm == "" {
+		return jsonResponse{}, errors.New("request names no algorithm")
+	}
+	opts := DefaultOptions()
+	if len(req.Options) != 0 {
+		if err := json.Unmarshal(req.Options, &opts); err != nil {
+			return jsonResponse{}, err
+		}
+	}
+	if err := ValidateProcesses(req.Processes); err != nil {
+		return jsonResponse{}, err
+	}
+	processes, err := ExpandPeriodic(req.Processes)
+	if err != nil {
+		return jsonResponse{}, err
+	}
+	r, err := Run(req.Algorithm, processes, func(o *Options) { *o = opts })
+	if err != nil {
+		return jsonResponse{}, err
+	}
+
+	return jsonResponse{Algorithm: r.Algorithm, ScheduleResult: &r.ScheduleResult, Report: r.Report}, nil
+}
@@ -0,0 +1,52 @@
+package scheduler
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestRunJSON(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: 1, ArrivalTime: 0, BurstDuration: 5, Priority: 2},
+		{ProcessID: 2, ArrivalTime: 3, BurstDuration: 9, Priority: 1},
+		{ProcessID: 3, ArrivalTime: 6, BurstDuration: 6, Priority: 3},
+	}
+	want, err := Run("rr", processes, WithQuantum(4))
+	if err != nil {
+		t.Fatal(err)
+	}
+	in, err := json.Marshal(map[string]interface{}{
+		"algorithm": "rr",
+		"processes": processes,
+		"options":   map[string]interface{}{"Quantum": 4},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got jsonResponse
+	if err := json.Unmarshal(RunJSON(in), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Error != "" || got.Algorithm != "rr" || got.ScheduleResult == nil || got.Metrics.AverageWait != want.Metrics.AverageWait || got.Report == "" {
+		t.Errorf("RunJSON() = %+v, want the rr result with metrics %+v", got, want.Metrics)
+	}
+
+	for _, tt := range []struct {
+		name, in, want string
+	}{
+		{name: "invalid JSON", in: `{`, want: "unexpected end"},
+		{name: "no algorithm", in: `{"processes": []}`, want: "no algorithm"},
+		{name: "unknown algorithm", in: `{"algorithm": "lottery", "processes": [{"ProcessID": 1, "BurstDuration": 1}]}`, want: "unknown algorithm"},
+		{name: "invalid options", in: `{"algorithm": "rr", "processes": [{"ProcessID": 1, "BurstDuration": 1}], "options": {"Quantum": 0}}`, want: "quantum 0"},
+	} {
+		var got jsonResponse
+		if err := json.Unmarshal(RunJSON([]byte(tt.in)), &got); err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(got.Error, tt.want) {
+			t.Errorf("%s: RunJSON() error = %q, want it to mention %q", tt.name, got.Error, tt.want)
+		}
+	}
+}
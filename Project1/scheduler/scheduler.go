@@ -0,0 +1,162 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+)
+
+//region Schedulers
+
+// FCFSSchedule outputs a first-come, first-serve schedule of processes in a GANTT chart and a table of timing given:
+// • an output writer
+// • a title for the chart
+// • a slice of processes
+// It returns an error if the processes are invalid or the output cannot be written.
+// If ctx is cancelled mid-simulation, the processes completed so far are
+// reported and the context's error is returned.
+func FCFSSchedule(ctx context.Context, w io.Writer, title string, processes []Process) error {
+	return scheduleText(ctx, w, title, NewFCFS(), processes)
+}
+
+type fcfs struct{ opts Options }
+
+// NewFCFS returns a first-come, first-serve scheduler.
+func NewFCFS(opts ...Option) Scheduler { return &fcfs{opts: newOptions(opts)} }
+
+func (s *fcfs) Schedule(ctx context.Context, processes []Process) (*ScheduleResult, error) {
+	if err := validate(processes, s.opts); err != nil {
+		return nil, err
+	}
+
+	return simulate(ctx, processes, s.opts, &fifo{})
+}
+
+// SJFPrioritySchedule implements Shortest Job First Priority (SJF Priority) scheduling algorithm.
+func SJFPrioritySchedule(ctx context.Context, w io.Writer, title string, processes []Process) error {
+	return scheduleText(ctx, w, title, NewPriority(), processes)
+}
+
+type priority struct{ opts Options }
+
+// NewPriority returns an SJF priority scheduler: the lower (aged) priority
+// number runs first, and among equal priorities the shortest remaining burst.
+// Waiting processes gain priority at the WithAging rate.
+func NewPriority(opts ...Option) Scheduler { return &priority{opts: newOptions(opts)} }
+
+func (s *priority) Schedule(ctx context.Context, processes []Process) (*ScheduleResult, error) {
+	if err := validate(processes, s.opts); err != nil {
+		return nil, err
+	}
+
+	return simulate(ctx, processes, s.opts, &ranked{before: s.before})
+}
+
+// SJFSchedule implements Shortest Job First (SJF) scheduling algorithm.
+func SJFSchedule(ctx context.Context, w io.Writer, title string, processes []Process) error {
+	return scheduleText(ctx, w, title, NewSJF(), processes)
+}
+
+type sjf struct{ opts Options }
+
+// NewSJF returns a shortest-job-first scheduler.
+func NewSJF(opts ...Option) Scheduler { return &sjf{opts: newOptions(opts)} }
+
+func (s *sjf) Schedule(ctx context.Context, processes []Process) (*ScheduleResult, error) {
+	if err := validate(processes, s.opts); err != nil {
+		return nil, err
+	}
+
+	return simulate(ctx, processes, s.opts, &ranked{before: s.before})
+}
+
+// RRSchedule implements Round-Robin (RR) scheduling algorithm.
+func RRSchedule(ctx context.Context, w io.Writer, title string, processes []Process) error {
+	return scheduleText(ctx, w, title, NewRR(), processes)
+}
+
+type rr struct{ opts Options }
+
+// NewRR returns a round-robin scheduler with the WithQuantum time slice,
+// scaled for each process by its nice value.
+// Ready processes are served from a FIFO queue: new arrivals join the tail
+// ahead of the process whose quantum just expired, so runs are deterministic.
+func NewRR(opts ...Option) Scheduler { return &rr{opts: newOptions(opts)} }
+
+func (s *rr) Schedule(ctx context.Context, processes []Process) (*ScheduleResult, error) {
+	if err := validate(processes, s.opts); err != nil {
+		return nil, err
+	}
+
+	return simulate(ctx, processes, s.opts, &fifo{slice: s.opts.Quantum})
+}
+
+// before reports whether process i should be picked over process j: the one with
+// the better (aged) priority, then less remaining burst, falling back to the
+// tie-break policy.
+func (s *priority) before(e *engine, i, j int) bool {
+	// Aged priorities are compared by their difference, which stays the
+	// same while both processes wait, so the ready queue's ranking holds.
+	if d := float64(e.priority(i)-e.priority(j)) - e.opts.Aging*float64(e.waited(i)-e.waited(j)); d != 0 {
+		return d < 0
+	}
+	if e.remaining[i] != e.remaining[j] {
+		return e.remaining[i] < e.remaining[j]
+	}
+	return s.opts.less(e.processes[i], e.processes[j])
+}
+
+// before reports whether process i should be picked over process j: the one with
+// less remaining burst, falling back to the tie-break policy.
+func (s *sjf) before(e *engine, i, j int) bool {
+	if e.remaining[i] != e.remaining[j] {
+		return e.remaining[i] < e.remaining[j]
+	}
+	return s.opts.less(e.processes[i], e.processes[j])
+}
+
+func validate(processes []Process, opts Options) error {
+	if err := ValidateProcesses(processes); err != nil {
+		return err
+	}
+	if err := opts.validate(); err != nil {
+		return err
+	}
+	for i, p := range processes {
+		for _, cpu := range p.Affinity {
+			if cpu >= opts.CPUs {
+				return &ValidationError{Row: i + 1, Err: fmt.Errorf("affinity for CPU %d, but there are only %d", cpu, opts.CPUs)}
+			}
+		}
+		if opts.Memory > 0 && p.Memory > opts.Memory {
+			return &ValidationError{Row: i + 1, Err: fmt.Errorf("memory %d exceeds the total of %d", p.Memory, opts.Memory)}
+		}
+	}
+	if _, ok := horizon(processes, opts); !ok {
+		return &ValidationError{Err: fmt.Errorf("times too large: the simulation could run past %d ticks", int64(math.MaxInt64))}
+	}
+
+	return nil
+}
+
+// arrivalOrder returns the indices of processes sorted by arrival time,
+// ordering simultaneous arrivals by less and then by input order.
+func arrivalOrder(processes []Process, less func(a, b Process) bool) []int {
+	order := make([]int, len(processes))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		pa, pb := processes[order[a]], processes[order[b]]
+		if pa.ArrivalTime != pb.ArrivalTime {
+			return pa.ArrivalTime < pb.ArrivalTime
+		}
+		return less(pa, pb)
+	})
+
+	return order
+}
+
+//endregion
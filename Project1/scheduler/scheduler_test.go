@@ -0,0 +1,829 @@
+package scheduler
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"os"
+	"path"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestFCFSSchedule(t *testing.T) {
+	t.Parallel()
+	type args struct {
+		processes []Process
+		title     string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantOut string
+	}{
+		{
+			name: "default",
+			args: args{
+				processes: []Process{
+					{
+						ProcessID:     1,
+						ArrivalTime:   0,
+						BurstDuration: 5,
+						Priority:      2,
+					},
+					{
+						ProcessID:     2,
+						ArrivalTime:   3,
+						BurstDuration: 9,
+						Priority:      1,
+					},
+					{
+						ProcessID:     3,
+						ArrivalTime:   6,
+						BurstDuration: 6,
+						Priority:      3,
+					},
+				},
+				title: "First-come, First-serve",
+			},
+			wantOut: loadFixture(t, "fcfs_test.txt"),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var w bytes.Buffer
+			if err := FCFSSchedule(context.Background(), &w, tt.args.title, tt.args.processes); err != nil {
+				t.Fatalf("FCFSSchedule() error = %v", err)
+			}
+			if got := w.String(); got != tt.wantOut {
+				t.Errorf("FCFSSchedule() = %v, want %v", got, tt.wantOut)
+			}
+		})
+	}
+}
+
+func loadFixture(t *testing.T, p ...string) string {
+	b, err := os.ReadFile(path.Join(p...))
+	if err != nil {
+		t.Fail()
+	}
+
+	return string(b)
+}
+
+func TestSchedulersCancelled(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: 1, ArrivalTime: 0, BurstDuration: 5, Priority: 2},
+		{ProcessID: 2, ArrivalTime: 3, BurstDuration: 9, Priority: 1},
+	}
+	tests := []struct {
+		name     string
+		schedule func(context.Context, io.Writer, string, []Process) error
+	}{
+		{name: "FCFS", schedule: FCFSSchedule},
+		{name: "SJF", schedule: SJFSchedule},
+		{name: "SJF priority", schedule: SJFPrioritySchedule},
+		{name: "RR", schedule: RRSchedule},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			var w bytes.Buffer
+			if err := tt.schedule(ctx, &w, tt.name, processes); !errors.Is(err, context.Canceled) {
+				t.Errorf("error = %v, want %v", err, context.Canceled)
+			}
+			if !strings.Contains(w.String(), "cancelled, 0 of 2 processes completed") {
+				t.Errorf("output does not report cancellation:\n%s", w.String())
+			}
+		})
+	}
+}
+
+func TestPriorityOrder(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name      string
+		processes []Process
+		opts      []Option
+		want      []int64
+	}{
+		{
+			name: "lower priority number first",
+			processes: []Process{
+				{ProcessID: 1, BurstDuration: 3, Priority: 1},
+				{ProcessID: 2, BurstDuration: 1, Priority: 50},
+			},
+			want: []int64{1, 2},
+		},
+		{
+			name: "shortest burst breaks priority ties",
+			processes: []Process{
+				{ProcessID: 1, BurstDuration: 3, Priority: 5},
+				{ProcessID: 2, BurstDuration: 2, Priority: 5},
+				{ProcessID: 3, BurstDuration: 1, Priority: 5},
+			},
+			want: []int64{3, 2, 1},
+		},
+		{
+			name: "urgent arrival preempts, less urgent does not",
+			processes: []Process{
+				{ProcessID: 1, BurstDuration: 5, Priority: 2},
+				{ProcessID: 2, ArrivalTime: 3, BurstDuration: 9, Priority: 1},
+				{ProcessID: 3, ArrivalTime: 6, BurstDuration: 6, Priority: 3},
+			},
+			want: []int64{1, 2, 1, 3},
+		},
+		{
+			name: "no aging",
+			processes: []Process{
+				{ProcessID: 1, BurstDuration: 1, Priority: 10},
+				{ProcessID: 2, BurstDuration: 1, Priority: 5},
+				{ProcessID: 3, ArrivalTime: 1, BurstDuration: 1, Priority: 3},
+			},
+			want: []int64{2, 3, 1},
+		},
+		{
+			name: "aging promotes the waiting process",
+			processes: []Process{
+				{ProcessID: 1, BurstDuration: 1, Priority: 10},
+				{ProcessID: 2, BurstDuration: 1, Priority: 5},
+				{ProcessID: 3, ArrivalTime: 1, BurstDuration: 1, Priority: 3},
+			},
+			opts: []Option{WithAging(8)},
+			want: []int64{2, 1, 3},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			r, err := NewPriority(tt.opts...).Schedule(context.Background(), tt.processes)
+			if err != nil {
+				t.Fatalf("Schedule() error = %v", err)
+			}
+			var got []int64
+			for _, slice := range r.Gantt {
+				got = append(got, slice.PID)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Gantt order = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRRRowsOnlyForCompletedProcesses(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: 1, BurstDuration: 1, Priority: 1},
+		{ProcessID: 2, BurstDuration: 3, Priority: 1},
+	}
+	r, err := NewRR(WithQuantum(2)).Schedule(context.Background(), processes)
+	if err != nil {
+		t.Fatalf("Schedule() error = %v", err)
+	}
+	want := []Row{
+		{ProcessID: 1, Priority: 1, BurstDuration: 1, Turnaround: 1, Exit: 1, Slice: 2, Residency: Residency{Running: 1}},
+		{ProcessID: 2, Priority: 1, BurstDuration: 3, Wait: 1, Turnaround: 4, Exit: 4, Slice: 2, Residency: Residency{Ready: 1, Running: 3}},
+	}
+	if !reflect.DeepEqual(r.Rows, want) {
+		t.Errorf("Rows = %+v, want %+v", r.Rows, want)
+	}
+	if r.Metrics.Completed != 2 || r.Total != 2 {
+		t.Errorf("Completed = %d of %d, want 2 of 2", r.Metrics.Completed, r.Total)
+	}
+}
+
+func TestRRIdlesUntilFirstArrival(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: 1, ArrivalTime: 1, BurstDuration: 5, Priority: 2},
+		{ProcessID: 2, ArrivalTime: 30, BurstDuration: 2, Priority: 1},
+	}
+	r, err := NewRR().Schedule(context.Background(), processes)
+	if err != nil {
+		t.Fatalf("Schedule() error = %v", err)
+	}
+	if r.Metrics.Completed != 2 {
+		t.Fatalf("Completed = %d, want 2", r.Metrics.Completed)
+	}
+	if got, want := r.Gantt[0], (TimeSlice{Start: 0, Stop: 1, Kind: SliceIdle}); got != want {
+		t.Errorf("first slice = %+v, want %+v", got, want)
+	}
+	if got := r.Gantt[1].Start; got != 1 {
+		t.Errorf("first process starts at %d, want 1", got)
+	}
+	if got := r.Gantt[len(r.Gantt)-1]; got.Start != 30 || got.Stop != 32 {
+		t.Errorf("last slice = %+v, want 30-32", got)
+	}
+}
+
+func TestRRSchedule(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: 1, ArrivalTime: 0, BurstDuration: 5, Priority: 2},
+		{ProcessID: 2, ArrivalTime: 3, BurstDuration: 9, Priority: 1},
+		{ProcessID: 3, ArrivalTime: 6, BurstDuration: 6, Priority: 3},
+	}
+	r, err := NewRR(WithQuantum(2)).Schedule(context.Background(), processes)
+	if err != nil {
+		t.Fatalf("Schedule() error = %v", err)
+	}
+	wantGantt := []TimeSlice{
+		{PID: 1, Start: 0, Stop: 2},
+		{PID: 1, Start: 2, Stop: 4},
+		{PID: 2, Start: 4, Stop: 6},
+		{PID: 1, Start: 6, Stop: 7},
+		{PID: 3, Start: 7, Stop: 9},
+		{PID: 2, Start: 9, Stop: 11},
+		{PID: 3, Start: 11, Stop: 13},
+		{PID: 2, Start: 13, Stop: 15},
+		{PID: 3, Start: 15, Stop: 17},
+		{PID: 2, Start: 17, Stop: 19},
+		{PID: 2, Start: 19, Stop: 20},
+	}
+	if !reflect.DeepEqual(r.Gantt, wantGantt) {
+		t.Errorf("Gantt = %v, want %v", r.Gantt, wantGantt)
+	}
+	wantRows := []Row{
+		{ProcessID: 1, Priority: 2, BurstDuration: 5, ArrivalTime: 0, Wait: 2, Turnaround: 7, Exit: 7, Slice: 2, Residency: Residency{Ready: 2, Running: 5}},
+		{ProcessID: 2, Priority: 1, BurstDuration: 9, ArrivalTime: 3, Wait: 8, Turnaround: 17, Exit: 20, Slice: 2, Residency: Residency{Ready: 8, Running: 9}},
+		{ProcessID: 3, Priority: 3, BurstDuration: 6, ArrivalTime: 6, Wait: 5, Turnaround: 11, Exit: 17, Slice: 2, Residency: Residency{Ready: 5, Running: 6}},
+	}
+	if !reflect.DeepEqual(r.Rows, wantRows) {
+		t.Errorf("Rows = %+v, want %+v", r.Rows, wantRows)
+	}
+	for _, row := range r.Rows {
+		if row.Wait < 0 {
+			t.Errorf("process %d has negative wait %d", row.ProcessID, row.Wait)
+		}
+	}
+}
+
+func TestRRSimultaneousArrivals(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: 3, BurstDuration: 3, Priority: 1},
+		{ProcessID: 1, BurstDuration: 3, Priority: 3},
+		{ProcessID: 2, BurstDuration: 3, Priority: 2},
+	}
+	tests := []struct {
+		name string
+		tb   TieBreak
+		want []int64
+	}{
+		{name: "input order", tb: ByArrival, want: []int64{3, 1, 2, 3, 1, 2}},
+		{name: "by PID", tb: ByPID, want: []int64{1, 2, 3, 1, 2, 3}},
+		{name: "by priority", tb: ByPriority, want: []int64{3, 2, 1, 3, 2, 1}},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			for run := 0; run < 10; run++ {
+				r, err := NewRR(WithQuantum(2), WithTieBreak(tt.tb)).Schedule(context.Background(), processes)
+				if err != nil {
+					t.Fatalf("Schedule() error = %v", err)
+				}
+				var got []int64
+				for _, slice := range r.Gantt {
+					got = append(got, slice.PID)
+				}
+				if !reflect.DeepEqual(got, tt.want) {
+					t.Fatalf("run %d: dispatch order = %v, want %v", run, got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestSwitchCost(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: 1, ArrivalTime: 0, BurstDuration: 3, Priority: 1},
+		{ProcessID: 2, ArrivalTime: 1, BurstDuration: 2, Priority: 1},
+	}
+	tests := []struct {
+		name      string
+		scheduler Scheduler
+		want      []TimeSlice
+	}{
+		{
+			name:      "FCFS",
+			scheduler: NewFCFS(WithSwitchCost(1)),
+			want: []TimeSlice{
+				{PID: 1, Start: 0, Stop: 3},
+				{PID: 2, Start: 3, Stop: 4, Kind: SliceSwitch},
+				{PID: 2, Start: 4, Stop: 6},
+			},
+		},
+		{
+			name:      "RR",
+			scheduler: NewRR(WithQuantum(2), WithSwitchCost(1)),
+			want: []TimeSlice{
+				{PID: 1, Start: 0, Stop: 2},
+				{PID: 2, Start: 2, Stop: 3, Kind: SliceSwitch},
+				{PID: 2, Start: 3, Stop: 5},
+				{PID: 1, Start: 5, Stop: 6, Kind: SliceSwitch},
+				{PID: 1, Start: 6, Stop: 7},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			r, err := tt.scheduler.Schedule(context.Background(), processes)
+			if err != nil {
+				t.Fatalf("Schedule() error = %v", err)
+			}
+			if !reflect.DeepEqual(r.Gantt, tt.want) {
+				t.Errorf("Gantt = %v, want %v", r.Gantt, tt.want)
+			}
+			if got, want := r.Metrics.ContextSwitches, len(tt.want)/2; got != want {
+				t.Errorf("ContextSwitches = %d, want %d", got, want)
+			}
+		})
+	}
+}
+
+func TestDispatchLatency(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: 1, ArrivalTime: 0, BurstDuration: 4, Priority: 1},
+		{ProcessID: 2, ArrivalTime: 0, BurstDuration: 1, Priority: 1},
+	}
+	r, err := NewRR(WithQuantum(2), WithDispatchLatency(1), WithSwitchCost(2)).Schedule(context.Background(), processes)
+	if err != nil {
+		t.Fatalf("Schedule() error = %v", err)
+	}
+	want := []TimeSlice{
+		{PID: 1, Start: 0, Stop: 1, Kind: SliceDispatch},
+		{PID: 1, Start: 1, Stop: 3},
+		{PID: 2, Start: 3, Stop: 5, Kind: SliceSwitch},
+		{PID: 2, Start: 5, Stop: 6, Kind: SliceDispatch},
+		{PID: 2, Start: 6, Stop: 7},
+		{PID: 1, Start: 7, Stop: 9, Kind: SliceSwitch},
+		{PID: 1, Start: 9, Stop: 10, Kind: SliceDispatch},
+		{PID: 1, Start: 10, Stop: 12},
+	}
+	if !reflect.DeepEqual(r.Gantt, want) {
+		t.Errorf("Gantt = %v, want %v", r.Gantt, want)
+	}
+
+	// A lone process pays the latency on every quantum but never switches.
+	r, err = NewRR(WithQuantum(2), WithDispatchLatency(1)).Schedule(context.Background(), processes[:1])
+	if err != nil {
+		t.Fatalf("Schedule() error = %v", err)
+	}
+	if r.Metrics.Makespan != 6 || r.Metrics.ContextSwitches != 0 {
+		t.Errorf("Makespan = %v, ContextSwitches = %d, want 6 and 0", r.Metrics.Makespan, r.Metrics.ContextSwitches)
+	}
+
+	// A shorter arrival preempts a process still being dispatched, whose
+	// dispatch stops there.
+	r, err = NewSJF(WithDispatchLatency(2)).Schedule(context.Background(), []Process{
+		{ProcessID: 1, ArrivalTime: 0, BurstDuration: 10},
+		{ProcessID: 2, ArrivalTime: 1, BurstDuration: 1},
+	})
+	if err != nil {
+		t.Fatalf("Schedule() error = %v", err)
+	}
+	want = []TimeSlice{
+		{PID: 1, Start: 0, Stop: 1, Kind: SliceDispatch},
+		{PID: 2, Start: 1, Stop: 3, Kind: SliceDispatch},
+		{PID: 2, Start: 3, Stop: 4},
+		{PID: 1, Start: 4, Stop: 6, Kind: SliceDispatch},
+		{PID: 1, Start: 6, Stop: 16},
+	}
+	if !reflect.DeepEqual(r.Gantt, want) {
+		t.Errorf("Gantt = %v, want %v", r.Gantt, want)
+	}
+	if got := r.Rows[0].Residency; got != (Residency{Ready: 6, Running: 10}) {
+		t.Errorf("process 1 Residency = %+v, want 6 ready and 10 running", got)
+	}
+	if r.Rows[1].Wait != 2 || len(r.Violations) != 0 {
+		t.Errorf("process 2 Wait = %d, Violations = %v, want 2 and none", r.Rows[1].Wait, r.Violations)
+	}
+}
+
+func TestOverheadMetrics(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: 1, ArrivalTime: 0, BurstDuration: 4, Priority: 1},
+		{ProcessID: 2, ArrivalTime: 0, BurstDuration: 1, Priority: 1},
+	}
+	r, err := NewRR(WithQuantum(2), WithDispatchLatency(1), WithSwitchCost(2)).Schedule(context.Background(), processes)
+	if err != nil {
+		t.Fatalf("Schedule() error = %v", err)
+	}
+	if r.Metrics.Overhead != 7 || r.Metrics.Utilization != 5.0/12 {
+		t.Errorf("Overhead = %v, Utilization = %v, want 7 and 5/12", r.Metrics.Overhead, r.Metrics.Utilization)
+	}
+	ideal, err := NewRR(WithQuantum(2)).Schedule(context.Background(), processes)
+	if err != nil {
+		t.Fatalf("Schedule() error = %v", err)
+	}
+	if ideal.Ideal != nil {
+		t.Errorf("Ideal = %+v without overhead, want nil", *ideal.Ideal)
+	}
+	if r.Ideal == nil || !reflect.DeepEqual(*r.Ideal, ideal.Metrics) {
+		t.Errorf("Ideal = %+v, want %+v", r.Ideal, ideal.Metrics)
+	}
+
+	// A run stopped at the maximum time leaves its policy holding ready
+	// processes, so it has no ideal metrics rather than ones from a rerun
+	// that starts with them already queued.
+	r, err = NewFCFS(WithSwitchCost(1), WithMaxTime(3)).Schedule(context.Background(), processes)
+	if err != nil {
+		t.Fatalf("Schedule() error = %v", err)
+	}
+	if !r.Truncated || r.Ideal != nil {
+		t.Errorf("Truncated = %v, Ideal = %+v, want true and nil", r.Truncated, r.Ideal)
+	}
+}
+
+func TestWarmUpCoolDown(t *testing.T) {
+	t.Parallel()
+	// FCFS runs 1 over 0-5, 2 over 5-14, and 3 over 14-20: only 2 both
+	// arrives after the warm-up and completes before the cool-down.
+	r, err := NewFCFS(WithWarmUp(2), WithCoolDown(3)).Schedule(context.Background(), []Process{
+		{ProcessID: 1, BurstDuration: 5, ArrivalTime: 0, Priority: 2},
+		{ProcessID: 2, BurstDuration: 9, ArrivalTime: 3, Priority: 1},
+		{ProcessID: 3, BurstDuration: 6, ArrivalTime: 6, Priority: 3},
+	})
+	if err != nil {
+		t.Fatalf("Schedule() error = %v", err)
+	}
+	for k, want := range []bool{true, false, true} {
+		if got := r.Rows[k].Excluded; got != want {
+			t.Errorf("process %d Excluded = %v, want %v", r.Rows[k].ProcessID, got, want)
+		}
+	}
+	want := Metrics{AverageWait: 2, AverageTurnaround: 11, Throughput: 1.0 / 15, Completed: 3, Excluded: 2, Makespan: 20, ContextSwitches: 2, Utilization: 1}
+	if !reflect.DeepEqual(r.Metrics, want) {
+		t.Errorf("Metrics = %+v, want %+v", r.Metrics, want)
+	}
+}
+
+func TestMaxTime(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: 1, BurstDuration: 5, ArrivalTime: 0, Priority: 2},
+		{ProcessID: 2, BurstDuration: 9, ArrivalTime: 3, Priority: 1},
+		{ProcessID: 3, BurstDuration: 6, ArrivalTime: 6, Priority: 3},
+	}
+	tests := []struct {
+		name       string
+		maxTime    int64
+		gantt      []TimeSlice
+		unfinished []Unfinished
+	}{
+		{
+			name:    "mid-run",
+			maxTime: 8,
+			gantt:   []TimeSlice{{PID: 1, Start: 0, Stop: 5}, {PID: 2, Start: 5, Stop: 8}},
+			unfinished: []Unfinished{
+				{ProcessID: 2, Priority: 1, BurstDuration: 9, ArrivalTime: 3, Remaining: 6, State: StateRunning},
+				{ProcessID: 3, Priority: 3, BurstDuration: 6, ArrivalTime: 6, Remaining: 6, State: StateReady},
+			},
+		},
+		{
+			name:    "before an arrival",
+			maxTime: 5,
+			gantt:   []TimeSlice{{PID: 1, Start: 0, Stop: 5}},
+			unfinished: []Unfinished{
+				{ProcessID: 2, Priority: 1, BurstDuration: 9, ArrivalTime: 3, Remaining: 9, State: StateReady},
+				{ProcessID: 3, Priority: 3, BurstDuration: 6, ArrivalTime: 6, Remaining: 6, State: StateNew},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			r, err := NewFCFS(WithMaxTime(tt.maxTime)).Schedule(context.Background(), processes)
+			if err != nil {
+				t.Fatalf("Schedule() error = %v", err)
+			}
+			if !r.Truncated || r.Metrics.Makespan != float64(tt.maxTime) || len(r.Rows) != 1 {
+				t.Errorf("Truncated = %v, Makespan = %v, %d rows, want true, %d, and 1 row", r.Truncated, r.Metrics.Makespan, len(r.Rows), tt.maxTime)
+			}
+			if !reflect.DeepEqual(r.Gantt, tt.gantt) {
+				t.Errorf("Gantt = %v, want %v", r.Gantt, tt.gantt)
+			}
+			if !reflect.DeepEqual(r.Unfinished, tt.unfinished) {
+				t.Errorf("Unfinished = %+v, want %+v", r.Unfinished, tt.unfinished)
+			}
+		})
+	}
+
+	// A horizon the schedule finishes before changes nothing.
+	r, err := NewFCFS(WithMaxTime(20)).Schedule(context.Background(), processes)
+	if err != nil {
+		t.Fatalf("Schedule() error = %v", err)
+	}
+	if r.Truncated || len(r.Rows) != 3 {
+		t.Errorf("Truncated = %v with %d rows, want false and 3 rows", r.Truncated, len(r.Rows))
+	}
+}
+
+func TestFCFSIdleGaps(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: 1, ArrivalTime: 2, BurstDuration: 3, Priority: 1},
+		{ProcessID: 2, ArrivalTime: 10, BurstDuration: 2, Priority: 1},
+		{ProcessID: 3, ArrivalTime: 10, BurstDuration: 1, Priority: 1},
+	}
+	r, err := NewFCFS().Schedule(context.Background(), processes)
+	if err != nil {
+		t.Fatalf("Schedule() error = %v", err)
+	}
+	wantGantt := []TimeSlice{
+		{Start: 0, Stop: 2, Kind: SliceIdle},
+		{PID: 1, Start: 2, Stop: 5},
+		{Start: 5, Stop: 10, Kind: SliceIdle},
+		{PID: 2, Start: 10, Stop: 12},
+		{PID: 3, Start: 12, Stop: 13},
+	}
+	if !reflect.DeepEqual(r.Gantt, wantGantt) {
+		t.Errorf("Gantt = %v, want %v", r.Gantt, wantGantt)
+	}
+	wantRows := []Row{
+		{ProcessID: 1, Priority: 1, BurstDuration: 3, ArrivalTime: 2, Wait: 0, Turnaround: 3, Exit: 5, Residency: Residency{Running: 3}},
+		{ProcessID: 2, Priority: 1, BurstDuration: 2, ArrivalTime: 10, Wait: 0, Turnaround: 2, Exit: 12, Residency: Residency{Running: 2}},
+		{ProcessID: 3, Priority: 1, BurstDuration: 1, ArrivalTime: 10, Wait: 2, Turnaround: 3, Exit: 13, Residency: Residency{Ready: 2, Running: 1}},
+	}
+	if !reflect.DeepEqual(r.Rows, wantRows) {
+		t.Errorf("Rows = %+v, want %+v", r.Rows, wantRows)
+	}
+	if want := 3.0 / 13; r.Metrics.Throughput != want {
+		t.Errorf("Throughput = %v, want %v", r.Metrics.Throughput, want)
+	}
+}
+
+func TestFCFSArrivalOrder(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: 1, ArrivalTime: 4, BurstDuration: 1, Priority: 1},
+		{ProcessID: 2, ArrivalTime: 0, BurstDuration: 2, Priority: 1},
+	}
+	r, err := NewFCFS().Schedule(context.Background(), processes)
+	if err != nil {
+		t.Fatalf("Schedule() error = %v", err)
+	}
+	if got := r.Gantt[0].PID; got != 2 {
+		t.Errorf("first process = %d, want 2, which arrived first", got)
+	}
+	for _, row := range r.Rows {
+		if row.Wait < 0 {
+			t.Errorf("process %d has negative wait %d", row.ProcessID, row.Wait)
+		}
+	}
+}
+
+func TestArbitraryPIDs(t *testing.T) {
+	t.Parallel()
+	// Processes are found by ID, so IDs need not start at 1 or be
+	// contiguous, and children can name any parent.
+	processes := []Process{
+		{ProcessID: 1 << 40, ArrivalTime: 0, BurstDuration: 4, Priority: 2},
+		{ProcessID: -7, ArrivalTime: 1, BurstDuration: 2, Priority: 1},
+		{ProcessID: 0, ArrivalTime: 2, BurstDuration: 3, Priority: 3},
+		{ProcessID: 42, BurstDuration: 1, Priority: 1, Parent: 1 << 40, ForkAt: 2},
+	}
+	if err := ValidateProcesses(processes); err != nil {
+		t.Fatalf("ValidateProcesses() error = %v", err)
+	}
+	for _, name := range []string{"fcfs", "sjf", "priority", "rr", "edf", "rm"} {
+		factory, _ := Lookup(name)
+		r, err := factory().Schedule(context.Background(), processes)
+		if err != nil {
+			t.Fatalf("%s: Schedule() error = %v", name, err)
+		}
+		if r.Metrics.Completed != len(processes) || len(r.Violations) > 0 {
+			t.Errorf("%s: completed %d of %d processes with violations %v", name, r.Metrics.Completed, len(processes), r.Violations)
+		}
+		for _, row := range r.Rows {
+			if row.ProcessID == 42 && (row.ArrivalTime < 2 || row.Parent != 1<<40) {
+				t.Errorf("%s: child row = %+v, want it spawned by %d at 2 or later", name, row, int64(1<<40))
+			}
+		}
+	}
+}
+
+func TestPreemptiveSchedules(t *testing.T) {
+	t.Parallel()
+	example := []Process{
+		{ProcessID: 1, ArrivalTime: 0, BurstDuration: 5, Priority: 2},
+		{ProcessID: 2, ArrivalTime: 3, BurstDuration: 9, Priority: 1},
+		{ProcessID: 3, ArrivalTime: 6, BurstDuration: 6, Priority: 3},
+	}
+	tests := []struct {
+		name      string
+		scheduler Scheduler
+		processes []Process
+		wantGantt []TimeSlice
+		wantRows  []Row
+	}{
+		{
+			name:      "SJF example",
+			scheduler: NewSJF(),
+			processes: example,
+			wantGantt: []TimeSlice{
+				{PID: 1, Start: 0, Stop: 5},
+				{PID: 2, Start: 5, Stop: 6},
+				{PID: 3, Start: 6, Stop: 12},
+				{PID: 2, Start: 12, Stop: 20},
+			},
+			wantRows: []Row{
+				{ProcessID: 1, Priority: 2, BurstDuration: 5, ArrivalTime: 0, Wait: 0, Turnaround: 5, Exit: 5, Residency: Residency{Running: 5}},
+				{ProcessID: 2, Priority: 1, BurstDuration: 9, ArrivalTime: 3, Wait: 8, Turnaround: 17, Exit: 20, Residency: Residency{Ready: 8, Running: 9}},
+				{ProcessID: 3, Priority: 3, BurstDuration: 6, ArrivalTime: 6, Wait: 0, Turnaround: 6, Exit: 12, Residency: Residency{Running: 6}},
+			},
+		},
+		{
+			name:      "shorter arrival preempts",
+			scheduler: NewSJF(),
+			processes: []Process{
+				{ProcessID: 1, ArrivalTime: 0, BurstDuration: 10},
+				{ProcessID: 2, ArrivalTime: 1, BurstDuration: 2},
+			},
+			wantGantt: []TimeSlice{
+				{PID: 1, Start: 0, Stop: 1},
+				{PID: 2, Start: 1, Stop: 3},
+				{PID: 1, Start: 3, Stop: 12},
+			},
+			wantRows: []Row{
+				{ProcessID: 1, BurstDuration: 10, Wait: 2, Turnaround: 12, Exit: 12, Residency: Residency{Ready: 2, Running: 10}},
+				{ProcessID: 2, BurstDuration: 2, ArrivalTime: 1, Turnaround: 2, Exit: 3, Residency: Residency{Running: 2}},
+			},
+		},
+		{
+			name:      "priority idles between arrivals",
+			scheduler: NewPriority(),
+			processes: []Process{
+				{ProcessID: 1, ArrivalTime: 2, BurstDuration: 2, Priority: 5},
+				{ProcessID: 2, ArrivalTime: 8, BurstDuration: 1, Priority: 1},
+			},
+			wantGantt: []TimeSlice{
+				{Start: 0, Stop: 2, Kind: SliceIdle},
+				{PID: 1, Start: 2, Stop: 4},
+				{Start: 4, Stop: 8, Kind: SliceIdle},
+				{PID: 2, Start: 8, Stop: 9},
+			},
+			wantRows: []Row{
+				{ProcessID: 1, Priority: 5, BurstDuration: 2, ArrivalTime: 2, Turnaround: 2, Exit: 4, Residency: Residency{Running: 2}},
+				{ProcessID: 2, Priority: 1, BurstDuration: 1, ArrivalTime: 8, Turnaround: 1, Exit: 9, Residency: Residency{Running: 1}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			r, err := tt.scheduler.Schedule(context.Background(), tt.processes)
+			if err != nil {
+				t.Fatalf("Schedule() error = %v", err)
+			}
+			if !reflect.DeepEqual(r.Gantt, tt.wantGantt) {
+				t.Errorf("Gantt = %v, want %v", r.Gantt, tt.wantGantt)
+			}
+			if !reflect.DeepEqual(r.Rows, tt.wantRows) {
+				t.Errorf("Rows = %+v, want %+v", r.Rows, tt.wantRows)
+			}
+			if r.Metrics.Completed != len(tt.processes) {
+				t.Errorf("Completed = %d, want %d", r.Metrics.Completed, len(tt.processes))
+			}
+		})
+	}
+}
+
+func TestIOCycles(t *testing.T) {
+	t.Parallel()
+	mixed := []Process{
+		{ProcessID: 1, BurstDuration: 2, Cycles: []Cycle{{IO: 3, CPU: 1}}},
+		{ProcessID: 2, BurstDuration: 4},
+	}
+	mixedRows := []Row{
+		{ProcessID: 1, BurstDuration: 3, IO: 3, Wait: 1, Turnaround: 7, Exit: 7, Residency: Residency{Ready: 1, Running: 3, Blocked: 3}},
+		{ProcessID: 2, BurstDuration: 4, Wait: 2, Turnaround: 6, Exit: 6, Residency: Residency{Ready: 2, Running: 4}},
+	}
+	tests := []struct {
+		name      string
+		scheduler Scheduler
+		processes []Process
+		wantGantt []TimeSlice
+		wantRows  []Row
+	}{
+		{
+			name:      "FCFS runs others while blocked",
+			scheduler: NewFCFS(),
+			processes: mixed,
+			wantGantt: []TimeSlice{
+				{PID: 1, Start: 0, Stop: 2},
+				{PID: 2, Start: 2, Stop: 6},
+				{PID: 1, Start: 6, Stop: 7},
+			},
+			wantRows: mixedRows,
+		},
+		{
+			name:      "RR returns from I/O to the ready queue",
+			scheduler: NewRR(WithQuantum(2)),
+			processes: mixed,
+			wantGantt: []TimeSlice{
+				{PID: 1, Start: 0, Stop: 2},
+				{PID: 2, Start: 2, Stop: 4},
+				{PID: 2, Start: 4, Stop: 6},
+				{PID: 1, Start: 6, Stop: 7},
+			},
+			wantRows: []Row{
+				{ProcessID: 1, BurstDuration: 3, IO: 3, Wait: 1, Turnaround: 7, Exit: 7, Slice: 2, Residency: Residency{Ready: 1, Running: 3, Blocked: 3}},
+				{ProcessID: 2, BurstDuration: 4, Wait: 2, Turnaround: 6, Exit: 6, Slice: 2, Residency: Residency{Ready: 2, Running: 4}},
+			},
+		},
+		{
+			name:      "SJF idles while everything is blocked",
+			scheduler: NewSJF(),
+			processes: []Process{{ProcessID: 1, BurstDuration: 1, Cycles: []Cycle{{IO: 2, CPU: 1}}}},
+			wantGantt: []TimeSlice{
+				{PID: 1, Start: 0, Stop: 1},
+				{Start: 1, Stop: 3, Kind: SliceIdle},
+				{PID: 1, Start: 3, Stop: 4},
+			},
+			wantRows: []Row{{ProcessID: 1, BurstDuration: 2, IO: 2, Turnaround: 4, Exit: 4, Residency: Residency{Running: 2, Blocked: 2}}},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			r, err := tt.scheduler.Schedule(context.Background(), tt.processes)
+			if err != nil {
+				t.Fatalf("Schedule() error = %v", err)
+			}
+			if !reflect.DeepEqual(r.Gantt, tt.wantGantt) {
+				t.Errorf("Gantt = %v, want %v", r.Gantt, tt.wantGantt)
+			}
+			if !reflect.DeepEqual(r.Rows, tt.wantRows) {
+				t.Errorf("Rows = %+v, want %+v", r.Rows, tt.wantRows)
+			}
+		})
+	}
+}
+
+func TestScheduleConcurrently(t *testing.T) {
+	t.Parallel()
+	processes := Generate(rand.New(rand.NewSource(3)), 100)
+	processes[2].Cycles = []Cycle{{IO: 3, CPU: 2}}
+	processes[4].Locks = []Lock{{Name: "m", Start: 0, Duration: 1}}
+	processes[5].Locks = []Lock{{Name: "m", Start: 0, Duration: 1}}
+	processes[6].Suspensions = []Suspension{{From: processes[6].ArrivalTime + 1, To: processes[6].ArrivalTime + 5}}
+	processes[8].Affinity = []int{1}
+	want := append([]Process(nil), processes...)
+	for i := range want {
+		want[i].Cycles = append([]Cycle(nil), want[i].Cycles...)
+		want[i].Affinity = append([]int(nil), want[i].Affinity...)
+		want[i].Locks = append([]Lock(nil), want[i].Locks...)
+		want[i].Suspensions = append([]Suspension(nil), want[i].Suspensions...)
+	}
+
+	// Every scheduler is shared by simulations running at the same time
+	// over the same processes, and each gets the result it would alone.
+	for _, name := range []string{"fcfs", "sjf", "priority", "rr", "edf", "rm"} {
+		factory, _ := Lookup(name)
+		s := factory(WithCPUs(2), WithSwitchCost(1), WithTieBreak(ByRandom), WithSeed(1))
+		alone, err := s.Schedule(context.Background(), processes)
+		if err != nil {
+			t.Fatalf("%s: Schedule() error = %v", name, err)
+		}
+		results := make([]*ScheduleResult, 8)
+		var wg sync.WaitGroup
+		for k := range results {
+			wg.Add(1)
+			go func(k int) {
+				defer wg.Done()
+				results[k], _ = s.Schedule(context.Background(), processes)
+			}(k)
+		}
+		wg.Wait()
+		for k, r := range results {
+			if !reflect.DeepEqual(r, alone) {
+				t.Errorf("%s: concurrent Schedule() %d = %+v, want %+v", name, k, r, alone)
+			}
+		}
+	}
+	if !reflect.DeepEqual(processes, want) {
+		t.Error("Schedule() modified the processes")
+	}
+}
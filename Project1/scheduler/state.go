@@ -0,0 +1,175 @@
+package scheduler
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/olekukonko/tablewriter"
+)
+
+// State is where a process is in its lifecycle.
+type State int
+
+const (
+	// StateNew is a process as it arrives, before it joins the ready queue.
+	StateNew State = iota
+	// StateReady is a process waiting in the ready queue.
+	StateReady
+	// StateRunning is the process holding the CPU.
+	StateRunning
+	// StateBlocked is a process waiting for I/O to complete.
+	StateBlocked
+	// StateTerminated is a process that has completed.
+	StateTerminated
+	// StateSuspended is a process that would be ready but is suspended.
+	StateSuspended
+)
+
+var stateNames = []string{
+	StateNew:        "new",
+	StateReady:      "ready",
+	StateRunning:    "running",
+	StateBlocked:    "blocked",
+	StateTerminated: "terminated",
+	StateSuspended:  "suspended",
+}
+
+func (s State) String() string {
+	if s < 0 || int(s) >= len(stateNames) {
+		return fmt.Sprintf("State(%d)", int(s))
+	}
+	return stateNames[s]
+}
+
+// MarshalText encodes the state by name, so JSON output is readable.
+func (s State) MarshalText() ([]byte, error) { return []byte(s.String()), nil }
+
+// UnmarshalText decodes a state encoded by MarshalText.
+func (s *State) UnmarshalText(text []byte) error {
+	for st, name := range stateNames {
+		if name == string(text) {
+			*s = State(st)
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown process state %q", text)
+}
+
+// Transition is a process entering a state at a point in time.
+type Transition struct {
+	PID   int64 `json:"pid"`
+	At    int64 `json:"at"`
+	State State `json:"state"`
+	// Priority is the process's effective priority as it entered the state,
+	// after aging and any inherited priority.
+	Priority float64 `json:"priority,omitempty"`
+}
+
+// Residency is how long a process spent in each state between arriving and
+// terminating. A process is admitted as it arrives, so it spends no time new.
+type Residency struct {
+	Ready   int64 `json:"ready"`
+	Running int64 `json:"running"`
+	Blocked int64 `json:"blocked"`
+	// Suspended is the time spent suspended while it could have run.
+	Suspended int64 `json:"suspended,omitempty"`
+}
+
+// enter moves process i into state s at now.
+func (e *engine) enter(i int, s State) { e.enterAt(i, s, e.now) }
+
+// enterAt moves process i into state s at the given time, charging the time
+// since its last transition to the state it leaves. Events the engine handles
+// after they were due, such as I/O completing mid-slice, enter at the time
+// they happened.
+func (e *engine) enterAt(i int, s State, at int64) {
+	d := at - e.since[i]
+	r := &e.residency[i]
+	switch e.state[i] {
+	case StateReady:
+		r.Ready += d
+	case StateRunning:
+		r.Running += d
+	case StateBlocked:
+		r.Blocked += d
+	case StateSuspended:
+		r.Suspended += d
+	}
+	e.state[i], e.since[i] = s, at
+	e.record(i, s, at, r.Ready)
+}
+
+// record notes process i entering state s at at, after waiting for waited
+// ticks in all, in the transitions unless only the metrics are computed,
+// and hands it to Options.Transitions.
+func (e *engine) record(i int, s State, at, waited int64) {
+	if e.opts.MetricsOnly && e.opts.Transitions == nil {
+		return
+	}
+	tr := Transition{PID: e.processes[i].ProcessID, At: at, State: s, Priority: e.agedPriority(i, waited)}
+	if !e.opts.MetricsOnly {
+		e.transitions = append(e.transitions, tr)
+	}
+	if e.opts.Transitions != nil {
+		e.opts.Transitions(tr)
+	}
+}
+
+// RenderStates writes a table of how long each completed process of r spent
+// in each state.
+func RenderStates(w io.Writer, r *ScheduleResult) error {
+	ew, u := &errWriter{w: w}, r.ticksPerUnit()
+	_, _ = fmt.Fprintln(ew, "State residency")
+	table := tablewriter.NewWriter(ew)
+	header := []string{"ID", "Ready", "Running", "Blocked"}
+	if len(r.Suspensions) > 0 {
+		header = append(header, "Suspended")
+	}
+	table.SetHeader(header)
+	for _, row := range r.Rows {
+		cells := []string{
+			fmt.Sprint(row.ProcessID),
+			unitTime(row.Residency.Ready, u),
+			unitTime(row.Residency.Running, u),
+			unitTime(row.Residency.Blocked, u),
+		}
+		if len(r.Suspensions) > 0 {
+			cells = append(cells, unitTime(row.Residency.Suspended, u))
+		}
+		table.Append(cells)
+	}
+	table.Render()
+
+	return ew.err
+}
+
+// RenderVerbose writes the per-process scheduling details of r that the
+// schedule table leaves out, such as each process's effective time slice,
+// followed by an event log of every state transition with the process's
+// effective priority at the time.
+func RenderVerbose(w io.Writer, r *ScheduleResult) error {
+	ew, u := &errWriter{w: w}, r.ticksPerUnit()
+	_, _ = fmt.Fprintln(ew, "Process details")
+	table := tablewriter.NewWriter(ew)
+	table.SetHeader([]string{"ID", "Nice", "Slice"})
+	for _, row := range r.Rows {
+		slice := "-"
+		if row.Slice > 0 {
+			slice = unitTime(row.Slice, u)
+		}
+		table.Append([]string{fmt.Sprint(row.ProcessID), fmt.Sprint(row.Nice), slice})
+	}
+	table.Render()
+
+	if len(r.Transitions) > 0 {
+		_, _ = fmt.Fprintln(ew, "Event log")
+		table = tablewriter.NewWriter(ew)
+		table.SetHeader([]string{"At", "ID", "State", "Priority"})
+		for _, tr := range r.Transitions {
+			table.Append([]string{unitTime(tr.At, u), fmt.Sprint(tr.PID), tr.State.String(), fmt.Sprintf("%g", tr.Priority)})
+		}
+		table.Render()
+	}
+
+	return ew.err
+}
@@ -0,0 +1,115 @@
+package scheduler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTransitions(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: 1, BurstDuration: 2, Cycles: []Cycle{{IO: 3, CPU: 1}}},
+		{ProcessID: 2, BurstDuration: 4},
+	}
+	r, err := NewFCFS().Schedule(context.Background(), processes)
+	if err != nil {
+		t.Fatalf("Schedule() error = %v", err)
+	}
+	want := []Transition{
+		{PID: 1, At: 0, State: StateNew},
+		{PID: 1, At: 0, State: StateReady},
+		{PID: 2, At: 0, State: StateNew},
+		{PID: 2, At: 0, State: StateReady},
+		{PID: 1, At: 0, State: StateRunning},
+		{PID: 1, At: 2, State: StateBlocked},
+		{PID: 2, At: 2, State: StateRunning},
+		{PID: 1, At: 5, State: StateReady},
+		{PID: 2, At: 6, State: StateTerminated},
+		{PID: 1, At: 6, State: StateRunning},
+		{PID: 1, At: 7, State: StateTerminated},
+	}
+	if !reflect.DeepEqual(r.Transitions, want) {
+		t.Errorf("Transitions = %v, want %v", r.Transitions, want)
+	}
+	if got, want := r.Rows[0].Residency, (Residency{Ready: 1, Running: 3, Blocked: 3}); got != want {
+		t.Errorf("Residency = %+v, want %+v", got, want)
+	}
+}
+
+func TestTransitions_agedPriority(t *testing.T) {
+	t.Parallel()
+	processes := []Process{
+		{ProcessID: 1, BurstDuration: 4, Priority: 5},
+		{ProcessID: 2, BurstDuration: 2, Priority: 9},
+	}
+	r, err := NewFCFS(WithAging(0.5)).Schedule(context.Background(), processes)
+	if err != nil {
+		t.Fatalf("Schedule() error = %v", err)
+	}
+	var got []float64
+	for _, tr := range r.Transitions {
+		if tr.PID == 2 {
+			got = append(got, tr.Priority)
+		}
+	}
+	// Process 2 waits 4 ticks before it runs, improving by 2.
+	if want := []float64{9, 9, 7, 7}; !reflect.DeepEqual(got, want) {
+		t.Errorf("process 2 priorities = %v, want %v", got, want)
+	}
+}
+
+func TestState_text(t *testing.T) {
+	t.Parallel()
+	b, err := json.Marshal([]State{StateNew, StateBlocked, StateTerminated})
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	if want := `["new","blocked","terminated"]`; string(b) != want {
+		t.Errorf("Marshal() = %s, want %s", b, want)
+	}
+	var got []State
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if want := []State{StateNew, StateBlocked, StateTerminated}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Unmarshal() = %v, want %v", got, want)
+	}
+	if err := json.Unmarshal([]byte(`["zombie"]`), &got); err == nil {
+		t.Error("Unmarshal() of an unknown state succeeded")
+	}
+}
+
+func TestRenderStates(t *testing.T) {
+	t.Parallel()
+	r := &ScheduleResult{Rows: []Row{{ProcessID: 4, Residency: Residency{Ready: 1, Running: 3, Blocked: 2}}}}
+	var w bytes.Buffer
+	if err := RenderStates(&w, r); err != nil {
+		t.Fatalf("RenderStates() error = %v", err)
+	}
+	for _, want := range []string{"State residency", "BLOCKED", "|  4 |     1 |       3 |       2 |"} {
+		if !strings.Contains(w.String(), want) {
+			t.Errorf("RenderStates() is missing %q:\n%s", want, w.String())
+		}
+	}
+}
+
+func TestRenderVerbose(t *testing.T) {
+	t.Parallel()
+	r := &ScheduleResult{
+		Rows:        []Row{{ProcessID: 1, Nice: -5, Slice: 6}, {ProcessID: 2}},
+		Transitions: []Transition{{PID: 2, At: 3, State: StateRunning, Priority: 2.5}},
+	}
+	var w bytes.Buffer
+	if err := RenderVerbose(&w, r); err != nil {
+		t.Fatalf("RenderVerbose() error = %v", err)
+	}
+	for _, want := range []string{"Process details", "|  1 |   -5 |     6 |", "|  2 |    0 | -     |", "Event log", "|  3 |  2 | running |      2.5 |"} {
+		if !strings.Contains(w.String(), want) {
+			t.Errorf("RenderVerbose() is missing %q:\n%s", want, w.String())
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package scheduler
+
+import (
+	"container/heap"
+	"math"
+)
+
+// sliceStream hands the Gantt chart of a simulation to Options.Slices as it
+// is produced. Each core keeps only the slices that can still change: its
+// last run, which a resumed process extends and which decides whether the
+// next dispatch is charged a switch, the slices after it, and overhead
+// still under way. The rest wait in pending, a min-heap in the order of
+// ScheduleResult.Gantt, until no core can produce a slice that starts
+// earlier, and then go out to the tally, the verifier, and Options.Slices.
+type sliceStream struct {
+	pending []pendingSlice
+	seq     int
+	tally   *tally
+	verify  *verifier // nil when the schedule is not verified
+	emit    func(ts TimeSlice)
+}
+
+// pendingSlice is a final slice waiting for the slices before it.
+type pendingSlice struct {
+	ts  TimeSlice
+	seq int // order the slice was finalized in, breaking ties on its core
+}
+
+func (s *sliceStream) Len() int { return len(s.pending) }
+func (s *sliceStream) Less(a, b int) bool {
+	x, y := s.pending[a], s.pending[b]
+	switch {
+	case x.ts.Start != y.ts.Start:
+		return x.ts.Start < y.ts.Start
+	case x.ts.CPU != y.ts.CPU:
+		return x.ts.CPU < y.ts.CPU
+	}
+	return x.seq < y.seq
+}
+func (s *sliceStream) Swap(a, b int)      { s.pending[a], s.pending[b] = s.pending[b], s.pending[a] }
+func (s *sliceStream) Push(x interface{}) { s.pending = append(s.pending, x.(pendingSlice)) }
+func (s *sliceStream) Pop() interface{} {
+	last := len(s.pending) - 1
+	ps := s.pending[last]
+	s.pending = s.pending[:last]
+	return ps
+}
+
+// newSliceStream returns the stream of a simulation under opts, or nil if
+// the Gantt chart is kept instead. A simulation of the metrics only streams
+// its chart to nowhere, when nothing needs it whole, just to total it.
+func newSliceStream(opts Options) *sliceStream {
+	emit := opts.Slices
+	if emit == nil {
+		if !opts.MetricsOnly || opts.CoolDown > 0 || opts.CheckpointAt > 0 {
+			return nil
+		}
+		emit = func(TimeSlice) {}
+	}
+	s := &sliceStream{tally: opts.newTally(opts.WarmUp, math.MaxInt64), emit: emit}
+	if !opts.MetricsOnly {
+		s.verify = opts.newVerifier()
+	}
+	return s
+}
+
+// stream hands out the slices of e's Gantt chart that are final and that
+// no core can now precede, or with all set, every slice left, when the
+// simulation is over.
+func (e *engine) stream(all bool) {
+	s := e.slices
+	if s == nil {
+		return
+	}
+	until := int64(math.MaxInt64)
+	for c := range e.cores {
+		k := &e.cores[c]
+		n := len(k.gantt)
+		if !all {
+			for last := n - 1; last >= 0; last-- {
+				if k.gantt[last].Kind == SliceRun {
+					n = last
+					break
+				}
+			}
+			// Overhead not over yet is cut short if its process is.
+			for n > 0 && k.gantt[n-1].Stop > e.now {
+				n--
+			}
+		}
+		for _, ts := range k.gantt[:n] {
+			ts.CPU = c
+			heap.Push(s, pendingSlice{ts: ts, seq: s.seq})
+			s.seq++
+		}
+		k.gantt = k.gantt[:copy(k.gantt, k.gantt[n:])]
+		// The core's next slices start no earlier than what it kept, or
+		// than the idle time from when it was last free.
+		if len(k.gantt) > 0 && k.gantt[0].Start < until {
+			until = k.gantt[0].Start
+		}
+		if k.free < until {
+			until = k.free
+		}
+	}
+	for len(s.pending) > 0 && (all || s.pending[0].ts.Start < until) {
+		ts := heap.Pop(s).(pendingSlice).ts
+		s.tally.add(ts)
+		if s.verify != nil {
+			s.verify.add(ts)
+		}
+		s.emit(ts)
+	}
+}
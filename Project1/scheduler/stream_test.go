@@ -0,0 +1,110 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestWithSlices(t *testing.T) {
+	t.Parallel()
+	processes := Generate(rand.New(rand.NewSource(1)), 200)
+	processes[3].Cycles = []Cycle{{IO: 4, CPU: 3}}
+	processes[7].KillAt = processes[7].ArrivalTime + 2
+	configs := map[string][]Option{
+		"one CPU":   {WithSwitchCost(1)},
+		"dispatch":  {WithDispatchLatency(1), WithWarmUp(20)},
+		"four CPUs": {WithCPUs(4), WithSwitchCost(1), WithMigrationCost(2)},
+		"speeds":    {WithCPUs(3), WithSpeeds(2, 1, 0.5), WithPower(Power{Active: 2, Idle: 0.5})},
+		"truncated": {WithCPUs(2), WithMaxTime(150)},
+	}
+	for _, name := range []string{"fcfs", "sjf", "priority", "rr", "edf", "rm"} {
+		factory, _ := Lookup(name)
+		for config, opts := range configs {
+			kept, err := factory(opts...).Schedule(context.Background(), processes)
+			if err != nil {
+				t.Fatalf("%s, %s: Schedule() error = %v", name, config, err)
+			}
+			var gantt []TimeSlice
+			streamed, err := factory(append(opts, WithSlices(func(ts TimeSlice) { gantt = append(gantt, ts) }))...).Schedule(context.Background(), processes)
+			if err != nil {
+				t.Fatalf("%s, %s: streamed Schedule() error = %v", name, config, err)
+			}
+			if !reflect.DeepEqual(gantt, kept.Gantt) {
+				t.Errorf("%s, %s: streamed slices = %v, want %v", name, config, gantt, kept.Gantt)
+			}
+			if !streamed.Streamed || len(streamed.Gantt) != 0 {
+				t.Errorf("%s, %s: streamed result has Streamed = %v and %d slices, want true and none", name, config, streamed.Streamed, len(streamed.Gantt))
+			}
+			if !reflect.DeepEqual(streamed.Metrics, kept.Metrics) || !reflect.DeepEqual(streamed.Classes, kept.Classes) {
+				t.Errorf("%s, %s: streamed metrics = %+v, want %+v", name, config, streamed.Metrics, kept.Metrics)
+			}
+			if len(streamed.Violations) != 0 {
+				t.Errorf("%s, %s: streamed violations = %v, want none", name, config, streamed.Violations)
+			}
+		}
+	}
+
+	for _, opt := range []Option{WithCoolDown(5), WithCheckpoint(5)} {
+		if _, err := NewFCFS(WithSlices(func(TimeSlice) {}), opt).Schedule(context.Background(), processes); !errors.Is(err, ErrValidation) {
+			t.Errorf("Schedule() error = %v, want %v", err, ErrValidation)
+		}
+	}
+}
+
+func TestWithSlices_violations(t *testing.T) {
+	t.Parallel()
+	v := DefaultOptions().newVerifier()
+	for _, ts := range []TimeSlice{{PID: 1, Start: 0, Stop: 4}, {PID: 2, Start: 3, Stop: 5}} {
+		v.add(ts)
+	}
+	got := v.check([]Row{{ProcessID: 1, BurstDuration: 4, Exit: 4}, {ProcessID: 2, BurstDuration: 2, Exit: 5}})
+	want := []Violation{{Rule: RuleOverlap, PID: 2, At: 3, Got: 3, Want: 4}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("check() = %v, want %v", got, want)
+	}
+}
+
+func TestGanttWriter(t *testing.T) {
+	t.Parallel()
+	r, err := NewRR().Schedule(context.Background(), Generate(rand.New(rand.NewSource(2)), 40))
+	if err != nil {
+		t.Fatalf("Schedule() error = %v", err)
+	}
+	var w strings.Builder
+	gw := NewGanttWriter(&w, 1, 1)
+	for _, ts := range r.Gantt {
+		gw.Add(ts)
+	}
+	if err := gw.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	// The chart is the whole one, cut into rows of ganttRow slices.
+	var want strings.Builder
+	_, _ = want.WriteString("Gantt schedule\n")
+	for gantt := r.Gantt; len(gantt) > 0; {
+		n := ganttRow
+		if len(gantt) < n {
+			n = len(gantt)
+		}
+		outputGanttRow(&want, gantt[:n], 1)
+		gantt = gantt[n:]
+	}
+	if w.String() != want.String() {
+		t.Errorf("GanttWriter wrote\n%s\nwant\n%s", w.String(), want.String())
+	}
+
+	w.Reset()
+	gw = NewGanttWriter(&w, 2, 2)
+	gw.Add(TimeSlice{PID: 1, Start: 0, Stop: 4})
+	gw.Add(TimeSlice{PID: 2, Start: 0, Stop: 3, CPU: 1})
+	if err := gw.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if want := "Gantt schedule\nCPU 0\n|   1   |\n0\t2\n\nCPU 1\n|   2   |\n0\t1.5\n\n"; w.String() != want {
+		t.Errorf("GanttWriter wrote %q, want %q", w.String(), want)
+	}
+}
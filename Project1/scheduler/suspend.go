@@ -0,0 +1,136 @@
+package scheduler
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Suspension is an interval during which a process is taken off the CPU and
+// out of the ready queue, as by an operator or by swapping it out.
+type Suspension struct {
+	From int64 `json:"from"`
+	To   int64 `json:"to"`
+}
+
+// parseSuspension parses a suspend field value of the form from:to,
+// parsing its times with parseTime.
+func parseSuspension(s string, parseTime func(string) (int64, error)) (Suspension, error) {
+	from, to, ok := strings.Cut(s, ":")
+	if !ok {
+		return Suspension{}, fmt.Errorf("suspension %q is not from:to", s)
+	}
+	var sp Suspension
+	var err error
+	if sp.From, err = parseTime(from); err != nil {
+		return Suspension{}, err
+	}
+	if sp.To, err = parseTime(to); err != nil {
+		return Suspension{}, err
+	}
+	return sp, nil
+}
+
+// validateSuspensions checks that the suspensions of p are in order, do not
+// overlap, and begin once it has arrived.
+func validateSuspensions(p Process) error {
+	end := p.ArrivalTime
+	for _, s := range p.Suspensions {
+		switch {
+		case p.Parent != 0:
+			return fmt.Errorf("child of %d cannot be suspended", p.Parent)
+		case s.From < end:
+			return fmt.Errorf("suspension at %d is before arrival or the previous suspension ends", s.From)
+		case s.To <= s.From:
+			return fmt.Errorf("suspension from %d must end after it starts, not at %d", s.From, s.To)
+		}
+		end = s.To
+	}
+	return nil
+}
+
+// suspend takes process i off its CPU and out of the ready queue until it
+// is resumed, stopping its dispatch if it is still being dispatched. A
+// process suspended while blocked finishes its I/O or gets its lock as
+// usual, but only becomes ready once resumed.
+func (e *engine) suspend(i int, p policy) {
+	for c := range e.cores {
+		if k := &e.cores[c]; k.busy && k.proc == i {
+			if k.stop > e.now {
+				k.stop, k.expires = e.now, false
+			}
+			e.end(k, p)
+			if k.proc == i {
+				if k.start > e.now {
+					e.undispatch(k)
+				}
+				k.proc = -1
+			}
+		}
+	}
+	if e.state[i] == StateTerminated {
+		return
+	}
+	e.suspended[i] = len(e.suspensions)
+	e.suspensions = append(e.suspensions, TimeSlice{PID: e.processes[i].ProcessID, Start: e.now, Stop: -1, Kind: SliceSuspended})
+	if e.state[i] == StateReady || e.state[i] == StateRunning {
+		p.drop(i)
+		e.enter(i, StateSuspended)
+	}
+}
+
+// resume ends the suspension of process i, making it ready if it is only
+// waiting to be resumed.
+func (e *engine) resume(i int, p policy) {
+	if _, ok := e.suspended[i]; !ok {
+		return
+	}
+	e.unsuspend(i)
+	if e.state[i] == StateSuspended {
+		e.enter(i, StateReady)
+		p.add(i)
+	}
+}
+
+// unsuspend closes the open suspension of process i, if any, at now.
+func (e *engine) unsuspend(i int) {
+	if k, ok := e.suspended[i]; ok {
+		e.suspensions[k].Stop = e.now
+		delete(e.suspended, i)
+	}
+}
+
+// ready makes process i ready at at, unless it is suspended, when it waits
+// to be resumed instead.
+func (e *engine) ready(i int, at int64, p policy) {
+	if _, ok := e.suspended[i]; ok {
+		e.enterAt(i, StateSuspended, at)
+		return
+	}
+	e.enterAt(i, StateReady, at)
+	p.add(i)
+}
+
+// outputSuspensions writes a chart of when each suspended process was
+// suspended, below the Gantt chart.
+func outputSuspensions(w io.Writer, suspensions []TimeSlice, u float64) {
+	var order []int64
+	byPID := make(map[int64][]TimeSlice)
+	for _, s := range suspensions {
+		if _, ok := byPID[s.PID]; !ok {
+			order = append(order, s.PID)
+		}
+		byPID[s.PID] = append(byPID[s.PID], s)
+	}
+	for _, pid := range order {
+		var chart []TimeSlice
+		var free int64
+		for _, s := range byPID[pid] {
+			free = idle(&chart, free, s.Start)
+			chart = append(chart, s)
+			free = s.Stop
+		}
+		_, _ = fmt.Fprintf(w, "Process %d suspended\n", pid)
+		outputGanttRow(w, chart, u)
+	}
+}
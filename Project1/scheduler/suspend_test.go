@@ -0,0 +1,152 @@
+package scheduler
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSuspend(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name        string
+		s           Scheduler
+		processes   []Process
+		gantt       []TimeSlice
+		suspensions []TimeSlice
+		exit        []int64
+	}{
+		{
+			name: "running and ready",
+			s:    NewFCFS(),
+			processes: []Process{
+				{ProcessID: 1, BurstDuration: 6, Suspensions: []Suspension{{From: 2, To: 5}}},
+				{ProcessID: 2, BurstDuration: 3, ArrivalTime: 1},
+				{ProcessID: 3, BurstDuration: 2, ArrivalTime: 1, Suspensions: []Suspension{{From: 1, To: 9}}},
+			},
+			gantt: []TimeSlice{
+				{PID: 1, Start: 0, Stop: 2},
+				{PID: 2, Start: 2, Stop: 5},
+				{PID: 1, Start: 5, Stop: 9},
+				{PID: 3, Start: 9, Stop: 11},
+			},
+			suspensions: []TimeSlice{
+				{PID: 3, Start: 1, Stop: 9, Kind: SliceSuspended},
+				{PID: 1, Start: 2, Stop: 5, Kind: SliceSuspended},
+			},
+			exit: []int64{9, 5, 11},
+		},
+		{
+			name: "during I/O",
+			s:    NewFCFS(),
+			processes: []Process{
+				{ProcessID: 1, BurstDuration: 1, Cycles: []Cycle{{IO: 2, CPU: 1}}, Suspensions: []Suspension{{From: 2, To: 6}}},
+			},
+			gantt: []TimeSlice{
+				{PID: 1, Start: 0, Stop: 1},
+				{Start: 1, Stop: 6, Kind: SliceIdle},
+				{PID: 1, Start: 6, Stop: 7},
+			},
+			suspensions: []TimeSlice{{PID: 1, Start: 2, Stop: 6, Kind: SliceSuspended}},
+			exit:        []int64{7},
+		},
+		{
+			name: "killed while suspended",
+			s:    NewRR(WithQuantum(2)),
+			processes: []Process{
+				{ProcessID: 1, BurstDuration: 4, KillAt: 3, Suspensions: []Suspension{{From: 1, To: 5}}},
+				{ProcessID: 2, BurstDuration: 2},
+			},
+			gantt: []TimeSlice{
+				{PID: 1, Start: 0, Stop: 1},
+				{PID: 2, Start: 1, Stop: 3},
+			},
+			suspensions: []TimeSlice{{PID: 1, Start: 1, Stop: 3, Kind: SliceSuspended}},
+			exit:        []int64{3, 3},
+		},
+		{
+			name: "while dispatched",
+			s:    NewFCFS(WithDispatchLatency(2)),
+			processes: []Process{
+				{ProcessID: 1, BurstDuration: 3},
+				{ProcessID: 2, BurstDuration: 3, Suspensions: []Suspension{{From: 6, To: 8}}},
+			},
+			gantt: []TimeSlice{
+				{PID: 1, Start: 0, Stop: 2, Kind: SliceDispatch},
+				{PID: 1, Start: 2, Stop: 5},
+				{PID: 2, Start: 5, Stop: 6, Kind: SliceDispatch},
+				{Start: 6, Stop: 8, Kind: SliceIdle},
+				{PID: 2, Start: 8, Stop: 10, Kind: SliceDispatch},
+				{PID: 2, Start: 10, Stop: 13},
+			},
+			suspensions: []TimeSlice{{PID: 2, Start: 6, Stop: 8, Kind: SliceSuspended}},
+			exit:        []int64{5, 13},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			r, err := tt.s.Schedule(context.Background(), tt.processes)
+			if err != nil {
+				t.Fatalf("Schedule() error = %v", err)
+			}
+			if !reflect.DeepEqual(r.Gantt, tt.gantt) {
+				t.Errorf("Gantt = %v, want %v", r.Gantt, tt.gantt)
+			}
+			if !reflect.DeepEqual(r.Suspensions, tt.suspensions) {
+				t.Errorf("Suspensions = %v, want %v", r.Suspensions, tt.suspensions)
+			}
+			for k, row := range r.Rows {
+				if row.Exit != tt.exit[k] {
+					t.Errorf("process %d Exit = %d, want %d", row.ProcessID, row.Exit, tt.exit[k])
+				}
+			}
+		})
+	}
+}
+
+func TestSuspend_residency(t *testing.T) {
+	t.Parallel()
+	r, err := NewFCFS().Schedule(context.Background(), []Process{
+		{ProcessID: 1, BurstDuration: 2},
+		{ProcessID: 2, BurstDuration: 2, Suspensions: []Suspension{{From: 1, To: 4}}},
+	})
+	if err != nil {
+		t.Fatalf("Schedule() error = %v", err)
+	}
+	// Ready until suspended at 1, suspended until 4, then running.
+	if got, want := r.Rows[1].Residency, (Residency{Ready: 1, Running: 2, Suspended: 3}); got != want {
+		t.Errorf("Residency = %+v, want %+v", got, want)
+	}
+	if got := r.Rows[1].Wait; got != 1 {
+		t.Errorf("Wait = %d, want 1", got)
+	}
+
+	// Suspended during its dispatch, it was only ever ready, suspended, or
+	// running.
+	r, err = NewFCFS(WithDispatchLatency(2)).Schedule(context.Background(), []Process{
+		{ProcessID: 1, BurstDuration: 3},
+		{ProcessID: 2, BurstDuration: 3, Suspensions: []Suspension{{From: 6, To: 8}}},
+	})
+	if err != nil {
+		t.Fatalf("Schedule() error = %v", err)
+	}
+	if got, want := r.Rows[1].Residency, (Residency{Ready: 8, Running: 3, Suspended: 2}); got != want {
+		t.Errorf("Residency = %+v, want %+v", got, want)
+	}
+}
+
+func TestRenderText_suspensions(t *testing.T) {
+	t.Parallel()
+	r := &ScheduleResult{Suspensions: []TimeSlice{{PID: 2, Start: 1, Stop: 4, Kind: SliceSuspended}}}
+	var w bytes.Buffer
+	if err := RenderText(&w, "t", r); err != nil {
+		t.Fatalf("RenderText() error = %v", err)
+	}
+	if want := "Process 2 suspended\n|  idle  |  susp  |\n0\t1\t4\n"; !strings.Contains(w.String(), want) {
+		t.Errorf("RenderText() is missing %q:\n%s", want, w.String())
+	}
+}
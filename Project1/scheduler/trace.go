@@ -0,0 +1,80 @@
+package scheduler
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// traceSymbols name processes in traces, in order of process ID.
+const traceSymbols = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789"
+
+// RenderTrace writes r to w as a trace of which process held each CPU at
+// every tick, one character per tick in the compact form textbooks use, as
+// in AAABBC..A: processes are lettered A, B, C, and so on in order of
+// process ID, followed by a legend of the letters. An idle CPU is a dot, and
+// switch, dispatch, or migration overhead a dash. Processes beyond the 62nd
+// are all shown as ?.
+func RenderTrace(w io.Writer, title string, r *ScheduleResult) error {
+	ew := &errWriter{w: w}
+	if r.Seed != nil {
+		title = fmt.Sprintf("%s (seed %d)", title, *r.Seed)
+	}
+	_, _ = fmt.Fprintln(ew, title)
+	if r.TicksPerUnit > 1 {
+		_, _ = fmt.Fprintf(ew, "  one character per 1/%d of a time unit\n", r.TicksPerUnit)
+	}
+
+	var pids []int64
+	symbols := make(map[int64]byte)
+	for _, ts := range r.Gantt {
+		if _, ok := symbols[ts.PID]; ts.Kind == SliceRun && !ok {
+			symbols[ts.PID] = '?'
+			pids = append(pids, ts.PID)
+		}
+	}
+	sort.Slice(pids, func(a, b int) bool { return pids[a] < pids[b] })
+	for k, pid := range pids {
+		if k < len(traceSymbols) {
+			symbols[pid] = traceSymbols[k]
+		}
+	}
+
+	cores := coreGantts(r.Gantt)
+	for c, gantt := range cores {
+		var line []byte
+		for _, ts := range gantt {
+			for int64(len(line)) < ts.Start {
+				line = append(line, '.')
+			}
+			symbol := byte('-')
+			switch ts.Kind {
+			case SliceRun:
+				symbol = symbols[ts.PID]
+			case SliceIdle:
+				symbol = '.'
+			}
+			for t := ts.Start; t < ts.Stop; t++ {
+				line = append(line, symbol)
+			}
+		}
+		if len(cores) > 1 {
+			_, _ = fmt.Fprintf(ew, "  CPU %d %s\n", c, line)
+		} else {
+			_, _ = fmt.Fprintf(ew, "  %s\n", line)
+		}
+	}
+
+	legend := make([]string, 0, len(pids)+1)
+	for k, pid := range pids {
+		if k == len(traceSymbols) {
+			legend = append(legend, "?=the rest")
+			break
+		}
+		legend = append(legend, fmt.Sprintf("%c=%d", traceSymbols[k], pid))
+	}
+	_, _ = fmt.Fprintf(ew, "  %s\n\n", strings.Join(legend, " "))
+
+	return ew.err
+}
@@ -0,0 +1,208 @@
+package scheduler
+
+import (
+	"fmt"
+	"io"
+	"sort"
+)
+
+// Rule is an invariant every valid schedule keeps.
+type Rule string
+
+const (
+	// RuleReversed is a Gantt slice that stops before it starts.
+	RuleReversed Rule = "reversed"
+	// RuleOverlap is a Gantt slice that starts before the previous slice
+	// on the same CPU stops.
+	RuleOverlap Rule = "overlap"
+	// RuleEarly is a process running before it arrived.
+	RuleEarly Rule = "early"
+	// RuleCPUTime is a completed process that ran for more or less CPU time
+	// than its bursts, or a killed one that ran for more.
+	RuleCPUTime Rule = "cpu-time"
+	// RuleExit is a process whose exit time is not when its last run
+	// stopped, or for a killed process, is before it.
+	RuleExit Rule = "exit"
+)
+
+// Violation is a broken invariant found by Verify.
+type Violation struct {
+	Rule Rule  `json:"rule"`
+	PID  int64 `json:"pid,omitempty"`
+	CPU  int   `json:"cpu,omitempty"`
+	// At is when the violation happened: the start of the offending
+	// slice, or the process's exit.
+	At int64 `json:"at"`
+	// Got and Want are the offending and expected times: the previous
+	// slice's stop for an overlap, the arrival for an early run, the CPU
+	// time run and needed, or the last run's stop for an exit.
+	Got  int64 `json:"got"`
+	Want int64 `json:"want"`
+}
+
+func (v Violation) String() string { return v.describe(1) }
+
+// describe explains v with times in units of u ticks.
+func (v Violation) describe(u float64) string {
+	switch v.Rule {
+	case RuleReversed:
+		return fmt.Sprintf("CPU %d: slice of process %d stops at %s before it starts at %s", v.CPU, v.PID, unitTime(v.Got, u), unitTime(v.At, u))
+	case RuleOverlap:
+		return fmt.Sprintf("CPU %d: slice of process %d starts at %s before the previous slice stops at %s", v.CPU, v.PID, unitTime(v.At, u), unitTime(v.Want, u))
+	case RuleEarly:
+		return fmt.Sprintf("process %d runs at %s before arriving at %s", v.PID, unitTime(v.At, u), unitTime(v.Want, u))
+	case RuleCPUTime:
+		return fmt.Sprintf("process %d ran for %s of CPU time, want %s", v.PID, unitTime(v.Got, u), unitTime(v.Want, u))
+	case RuleExit:
+		return fmt.Sprintf("process %d exits at %s, but its last run stops at %s", v.PID, unitTime(v.At, u), unitTime(v.Got, u))
+	}
+	return string(v.Rule)
+}
+
+// Verify checks that r is a valid schedule, built with the given options,
+// and returns the invariants it breaks: on each CPU no two Gantt slices
+// overlap; no process runs before it arrives; every completed process runs
+// for exactly its CPU time, at the speed of the CPUs it ran on, and a killed
+// one for no more; and every process exits when its last run stops, or for
+// a killed process, after. The built-in schedulers verify their own results
+// into ScheduleResult.Violations; custom schedulers can call Verify to do
+// the same.
+func Verify(r *ScheduleResult, opts ...Option) []Violation {
+	return verify(r, newOptions(opts))
+}
+
+func verify(r *ScheduleResult, o Options) []Violation {
+	var vs []Violation
+	byCPU := make(map[int][]TimeSlice)
+	for _, ts := range r.Gantt {
+		if ts.Stop < ts.Start {
+			vs = append(vs, Violation{Rule: RuleReversed, PID: ts.PID, CPU: ts.CPU, At: ts.Start, Got: ts.Stop, Want: ts.Start})
+			continue
+		}
+		byCPU[ts.CPU] = append(byCPU[ts.CPU], ts)
+	}
+
+	cpus := make([]int, 0, len(byCPU))
+	for c := range byCPU {
+		cpus = append(cpus, c)
+	}
+	sort.Ints(cpus)
+	v := o.newVerifier()
+	for _, c := range cpus {
+		gantt := byCPU[c]
+		sort.SliceStable(gantt, func(a, b int) bool { return gantt[a].Start < gantt[b].Start })
+		for _, ts := range gantt {
+			v.add(ts)
+		}
+	}
+
+	return append(vs, v.check(r.Rows)...)
+}
+
+// verifier checks a Gantt chart a slice at a time, in order of start on
+// each CPU, so a chart streamed to Options.Slices need not be kept to verify
+// it.
+type verifier struct {
+	o Options
+	// exact is set when every CPU runs at speed 1, so runs do exactly
+	// their length in work.
+	exact bool
+	// stop is when the last slice on each CPU stopped.
+	stop map[int]int64
+	used map[int64]*usage
+	vs   []Violation
+}
+
+// usage is the CPU time a process ran, between the least work (lo) and the
+// most (hi) its runs can have done, and its first run and when its last
+// one stopped.
+type usage struct {
+	lo, hi, last int64
+	first        TimeSlice
+	ran          bool
+}
+
+func (o Options) newVerifier() *verifier {
+	v := &verifier{o: o, exact: true, stop: make(map[int]int64), used: make(map[int64]*usage)}
+	for c := 0; c < o.CPUs; c++ {
+		v.exact = v.exact && o.speed(c) == speedScale
+	}
+
+	return v
+}
+
+// add checks the next slice.
+func (v *verifier) add(ts TimeSlice) {
+	if ts.Stop < ts.Start {
+		v.vs = append(v.vs, Violation{Rule: RuleReversed, PID: ts.PID, CPU: ts.CPU, At: ts.Start, Got: ts.Stop, Want: ts.Start})
+		return
+	}
+	if stop, ok := v.stop[ts.CPU]; ok && ts.Start < stop {
+		v.vs = append(v.vs, Violation{Rule: RuleOverlap, PID: ts.PID, CPU: ts.CPU, At: ts.Start, Got: ts.Start, Want: stop})
+	}
+	v.stop[ts.CPU] = ts.Stop
+	if ts.Kind != SliceRun {
+		return
+	}
+	u := v.used[ts.PID]
+	if u == nil {
+		u = &usage{}
+		v.used[ts.PID] = u
+	}
+	// A run of d ticks on a CPU of speed s does d*s work, but once
+	// CPUs differ in speed, the last run of a burst is rounded up to
+	// whole ticks and does only more than (d-1)*s.
+	d, s := ts.Stop-ts.Start, v.o.speed(ts.CPU)
+	switch {
+	case d == 0:
+	case v.exact:
+		u.lo += d * s
+	default:
+		u.lo += (d-1)*s + 1
+	}
+	u.hi += d * s
+	if !u.ran || ts.Start < u.first.Start {
+		u.first = ts
+	}
+	if !u.ran || ts.Stop > u.last {
+		u.last = ts.Stop
+	}
+	u.ran = true
+}
+
+// check returns the violations found in the slices so far, followed by
+// those of the processes in rows.
+func (v *verifier) check(rows []Row) []Violation {
+	vs := v.vs
+	for _, row := range rows {
+		u := v.used[row.ProcessID]
+		if u == nil {
+			u = &usage{}
+		}
+		if u.ran && u.first.Start < row.ArrivalTime {
+			vs = append(vs, Violation{Rule: RuleEarly, PID: row.ProcessID, CPU: u.first.CPU, At: u.first.Start, Got: u.first.Start, Want: row.ArrivalTime})
+		}
+		need := row.BurstDuration * speedScale
+		if u.lo > need || (!row.Killed && u.hi < need) {
+			vs = append(vs, Violation{Rule: RuleCPUTime, PID: row.ProcessID, At: row.Exit, Got: u.hi / speedScale, Want: row.BurstDuration})
+		}
+		if u.ran && (u.last > row.Exit || (!row.Killed && u.last != row.Exit)) {
+			vs = append(vs, Violation{Rule: RuleExit, PID: row.ProcessID, At: row.Exit, Got: u.last, Want: row.Exit})
+		}
+	}
+
+	return vs
+}
+
+// outputViolations writes the invariants the schedule breaks, if any, ahead
+// of the rest of the report so they are not missed.
+func outputViolations(w io.Writer, vs []Violation, u float64) {
+	if len(vs) == 0 {
+		return
+	}
+	_, _ = fmt.Fprintf(w, "INVALID SCHEDULE: %d violations\n", len(vs))
+	for _, v := range vs {
+		_, _ = fmt.Fprintf(w, "  - %s\n", v.describe(u))
+	}
+	_, _ = fmt.Fprintln(w)
+}
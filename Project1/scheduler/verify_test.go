@@ -0,0 +1,153 @@
+package scheduler
+
+import (
+	"context"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestVerify(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		r    *ScheduleResult
+		opts []Option
+		want []Violation
+	}{
+		{
+			name: "valid",
+			r: &ScheduleResult{
+				Gantt: []TimeSlice{{PID: 1, Start: 0, Stop: 2}, {PID: 2, Start: 2, Stop: 5}, {PID: 1, Start: 5, Stop: 6}},
+				Rows:  []Row{{ProcessID: 1, BurstDuration: 3, Exit: 6}, {ProcessID: 2, BurstDuration: 3, ArrivalTime: 1, Exit: 5}},
+			},
+		},
+		{
+			name: "overlap",
+			r: &ScheduleResult{
+				Gantt: []TimeSlice{{PID: 1, Start: 0, Stop: 3}, {PID: 2, Start: 2, Stop: 5}, {PID: 3, Start: 2, Stop: 4, CPU: 1}},
+				Rows:  []Row{{ProcessID: 1, BurstDuration: 3, Exit: 3}, {ProcessID: 2, BurstDuration: 3, Exit: 5}, {ProcessID: 3, BurstDuration: 2, Exit: 4}},
+			},
+			want: []Violation{{Rule: RuleOverlap, PID: 2, At: 2, Got: 2, Want: 3}},
+		},
+		{
+			name: "reversed",
+			r: &ScheduleResult{
+				Gantt: []TimeSlice{{PID: 0, Start: 3, Stop: 1, Kind: SliceIdle}},
+			},
+			want: []Violation{{Rule: RuleReversed, At: 3, Got: 1, Want: 3}},
+		},
+		{
+			name: "early",
+			r: &ScheduleResult{
+				Gantt: []TimeSlice{{PID: 1, Start: 1, Stop: 3}},
+				Rows:  []Row{{ProcessID: 1, BurstDuration: 2, ArrivalTime: 2, Exit: 3}},
+			},
+			want: []Violation{{Rule: RuleEarly, PID: 1, At: 1, Got: 1, Want: 2}},
+		},
+		{
+			name: "too little CPU time",
+			r: &ScheduleResult{
+				Gantt: []TimeSlice{{PID: 1, Start: 0, Stop: 2}},
+				Rows:  []Row{{ProcessID: 1, BurstDuration: 3, Exit: 2}},
+			},
+			want: []Violation{{Rule: RuleCPUTime, PID: 1, At: 2, Got: 2, Want: 3}},
+		},
+		{
+			name: "too much CPU time",
+			r: &ScheduleResult{
+				Gantt: []TimeSlice{{PID: 1, Start: 0, Stop: 2}, {PID: 1, Start: 4, Stop: 6, Kind: SliceSwitch}, {PID: 1, Start: 6, Stop: 8}},
+				Rows:  []Row{{ProcessID: 1, BurstDuration: 3, Exit: 8}},
+			},
+			want: []Violation{{Rule: RuleCPUTime, PID: 1, At: 8, Got: 4, Want: 3}},
+		},
+		{
+			name: "faster CPU",
+			r: &ScheduleResult{
+				Gantt: []TimeSlice{{PID: 1, Start: 0, Stop: 2, CPU: 1}},
+				Rows:  []Row{{ProcessID: 1, BurstDuration: 3, Exit: 2}},
+			},
+			opts: []Option{WithCPUs(2), WithSpeeds(1, 1.5)},
+		},
+		{
+			name: "killed",
+			r: &ScheduleResult{
+				Gantt: []TimeSlice{{PID: 1, Start: 0, Stop: 2}},
+				Rows:  []Row{{ProcessID: 1, BurstDuration: 3, Exit: 4, Killed: true}},
+			},
+		},
+		{
+			name: "exit after last run",
+			r: &ScheduleResult{
+				Gantt: []TimeSlice{{PID: 1, Start: 0, Stop: 3}},
+				Rows:  []Row{{ProcessID: 1, BurstDuration: 3, Exit: 4}},
+			},
+			want: []Violation{{Rule: RuleExit, PID: 1, At: 4, Got: 3, Want: 4}},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := Verify(tt.r, tt.opts...); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Verify() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderText_violations(t *testing.T) {
+	t.Parallel()
+	r := &ScheduleResult{
+		Gantt:        []TimeSlice{{PID: 1, Start: 10, Stop: 30}},
+		Rows:         []Row{{ProcessID: 1, BurstDuration: 20, ArrivalTime: 20, Exit: 30}},
+		TicksPerUnit: 10,
+	}
+	r.Violations = Verify(r)
+	var b strings.Builder
+	if err := RenderText(&b, "Broken", r); err != nil {
+		t.Fatalf("RenderText() error = %v", err)
+	}
+	want := "INVALID SCHEDULE: 1 violations\n  - process 1 runs at 1 before arriving at 2\n"
+	if !strings.Contains(b.String(), want) {
+		t.Errorf("RenderText() =\n%s\nwant it to contain\n%s", b.String(), want)
+	}
+}
+
+// TestSchedulers_valid checks every built-in scheduler against the
+// invariants on generated workloads, under options that reshape the Gantt
+// chart.
+func TestSchedulers_valid(t *testing.T) {
+	t.Parallel()
+	options := map[string][]Option{
+		"default":  nil,
+		"overhead": {WithSwitchCost(1), WithDispatchLatency(1)},
+		"cpus":     {WithCPUs(3), WithSpeeds(1, 1.5, 2.5), WithMigrationCost(1)},
+		"slow cpu": {WithSpeeds(0.7), WithQuantum(3)},
+		"memory":   {WithMemory(150), WithCPUs(2)},
+	}
+	for _, name := range Names() {
+		name := name
+		factory, _ := Lookup(name)
+		for label, opts := range options {
+			label, opts := label, opts
+			t.Run(name+"/"+label, func(t *testing.T) {
+				t.Parallel()
+				for seed := int64(1); seed <= 5; seed++ {
+					processes := Generate(rand.New(rand.NewSource(seed)), 30)
+					for i := range processes {
+						processes[i].Memory = 10 + processes[i].ProcessID%50
+					}
+					r, err := factory(opts...).Schedule(context.Background(), processes)
+					if err != nil {
+						t.Fatalf("seed %d: Schedule() error = %v", seed, err)
+					}
+					if len(r.Violations) > 0 {
+						t.Errorf("seed %d: Violations = %v", seed, r.Violations)
+					}
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,589 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math/rand"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/grpc"
+
+	"github.com/rks0134/CSCE4600/Project1/scheduler"
+)
+
+// maxWorkload is the largest workload a request may post, in bytes.
+const maxWorkload = 64 << 20
+
+// Limits on what a request may ask to simulate, so no single request can
+// exhaust the server's memory.
+const (
+	// maxServeCPUs is the most CPUs, and so speeds, a request may give.
+	maxServeCPUs = 1024
+	// maxServeProcesses is the most processes a posted workload may hold,
+	// counting the jobs of its periodic tasks.
+	maxServeProcesses = 100000
+	// maxServeTime is the latest -max-time a request may stop at.
+	maxServeTime = 1000000000
+)
+
+// dashboard is the web front-end served at the root, a single page that
+// drives /algorithms and /schedule.
+//
+//go:embed web/dashboard.html
+var dashboard []byte
+
+// shutdownGrace is how long requests in flight get to finish once the
+// server is asked to stop.
+const shutdownGrace = 10 * time.Second
+
+// serveCmd runs the schedulers as an HTTP service.
+type serveCmd struct {
+	*globalFlags
+	host     string
+	port     int
+	grpcPort int
+	drain    time.Duration
+	// origins lists the origins, besides the server's own, whose pages may
+	// open /live.
+	origins string
+	// draining is set once the server is asked to stop, so /readyz fails
+	// while requests in flight finish.
+	draining atomic.Bool
+	// sessions counts the /live sessions running, which the HTTP server
+	// stops tracking once their connections are taken over.
+	sessions sync.WaitGroup
+}
+
+func (c *serveCmd) defineFlags(flags *flag.FlagSet) {
+	flags.StringVar(&c.host, "host", "", "`host` to listen on (default every interface)")
+	flags.IntVar(&c.port, "port", 8080, "TCP `port` to listen on")
+	flags.IntVar(&c.grpcPort, "grpc-port", 0, "TCP `port` to serve the gRPC Simulator service on, or 0 for none")
+	flags.DurationVar(&c.drain, "drain", 0, "`time` to keep serving with /readyz failing once asked to stop, so load balancers stop routing here first")
+	flags.StringVar(&c.origins, "allow-origins", "", "comma separated `origins`, such as https://example.com, whose pages may open /live besides the server's own")
+}
+
+func (c *serveCmd) run(ctx context.Context, w io.Writer, args []string) error {
+	if len(args) != 0 {
+		return fmt.Errorf("%w: serve takes no arguments", ErrInvalidArgs)
+	}
+	l, err := net.Listen("tcp", net.JoinHostPort(c.host, strconv.Itoa(c.port)))
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidArgs, err)
+	}
+	_, _ = fmt.Fprintf(w, "Serving on http://%s\n", l.Addr())
+	var gl net.Listener
+	if c.grpcPort > 0 {
+		if gl, err = net.Listen("tcp", net.JoinHostPort(c.host, strconv.Itoa(c.grpcPort))); err != nil {
+			_ = l.Close()
+			return fmt.Errorf("%w: %v", ErrInvalidArgs, err)
+		}
+		_, _ = fmt.Fprintf(w, "Serving gRPC on %s\n", gl.Addr())
+	}
+	if err := flush(w); err != nil {
+		_ = l.Close()
+		if gl != nil {
+			_ = gl.Close()
+		}
+		return err
+	}
+
+	// Shutting the server down cancels the /live sessions through their
+	// requests' contexts, as it does not close taken over connections.
+	base, stopSessions := context.WithCancel(context.Background())
+	defer stopSessions()
+	srv := &http.Server{
+		Handler:           c.handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+		BaseContext:       func(net.Listener) context.Context { return base },
+	}
+	srv.RegisterOnShutdown(stopSessions)
+	done := make(chan error, 2)
+	go func() { done <- srv.Serve(l) }()
+	if gl != nil {
+		gs := c.grpcServer()
+		go func() { done <- gs.Serve(gl) }()
+		defer stopGRPC(gs)
+	}
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+	}
+	c.draining.Store(true)
+	if c.drain > 0 {
+		select {
+		case err := <-done:
+			return err
+		case <-time.After(c.drain):
+		}
+	}
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownGrace)
+	defer cancel()
+	err = srv.Shutdown(shutdownCtx)
+	closed := make(chan struct{})
+	go func() {
+		c.sessions.Wait()
+		close(closed)
+	}()
+	select {
+	case <-closed:
+	case <-shutdownCtx.Done():
+		if err == nil {
+			err = shutdownCtx.Err()
+		}
+	}
+
+	return err
+}
+
+// stopGRPC stops gs once the calls in flight finish, or cuts them off
+// after shutdownGrace.
+func stopGRPC(gs *grpc.Server) {
+	stopped := make(chan struct{})
+	go func() {
+		gs.GracefulStop()
+		close(stopped)
+	}()
+	t := time.NewTimer(shutdownGrace)
+	defer t.Stop()
+	select {
+	case <-stopped:
+	case <-t.C:
+		gs.Stop()
+	}
+}
+
+// handler routes the service's endpoints.
+func (c *serveCmd) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", c.serveDashboard)
+	mux.HandleFunc("/algorithms", c.serveAlgorithms)
+	mux.HandleFunc("/schedule", c.serveSchedule)
+	mux.HandleFunc("/live", c.serveLive)
+	mux.HandleFunc("/healthz", c.serveHealth)
+	mux.HandleFunc("/readyz", c.serveReady)
+
+	return mux
+}
+
+// serveDashboard serves the web front-end.
+func (c *serveCmd) serveDashboard(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		writeError(w, http.StatusNotFound, fmt.Errorf("%w: no endpoint %s", ErrInvalidArgs, r.URL.Path))
+		return
+	}
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("%w: %s /, want GET", ErrInvalidArgs, r.Method))
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_, _ = w.Write(dashboard)
+}
+
+// healthStatus is the response to /healthz and /readyz.
+type healthStatus struct {
+	Status string `json:"status"`
+}
+
+// serveHealth reports that the server is up, for liveness probes.
+func (c *serveCmd) serveHealth(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, healthStatus{Status: "ok"})
+}
+
+// serveReady reports whether the server takes new requests, for readiness
+// probes: it fails with 503 once the server is shutting down.
+func (c *serveCmd) serveReady(w http.ResponseWriter, r *http.Request) {
+	if c.draining.Load() {
+		writeJSON(w, http.StatusServiceUnavailable, healthStatus{Status: "draining"})
+		return
+	}
+	writeJSON(w, http.StatusOK, healthStatus{Status: "ok"})
+}
+
+// algorithmInfo describes a registered algorithm to clients.
+type algorithmInfo struct {
+	Name        string `json:"name"`
+	Title       string `json:"title"`
+	UsesQuantum bool   `json:"uses_quantum"`
+}
+
+// serveAlgorithms lists the registered algorithms.
+func (c *serveCmd) serveAlgorithms(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("%w: %s /algorithms, want GET", ErrInvalidArgs, r.Method))
+		return
+	}
+	var infos []algorithmInfo
+	for _, alg := range algorithms() {
+		infos = append(infos, algorithmInfo{Name: alg.name, Title: alg.title, UsesQuantum: alg.usesQuantum})
+	}
+	writeJSON(w, http.StatusOK, infos)
+}
+
+// scheduleRequest is the parameters of a request to /schedule, one per
+// query parameter, named after the schedule command's flags.
+type scheduleRequest struct {
+	simulationFlags
+	seed       seedFlag
+	algorithms string
+	quantum    int64
+	quiet      bool
+	// speed throttles live simulations to that many units of time per
+	// second, or not at all when 0.
+	speed float64
+}
+
+// parseScheduleRequest reads the parameters of a request from its query.
+func parseScheduleRequest(query url.Values) (*scheduleRequest, error) {
+	req := &scheduleRequest{}
+	flags := flag.NewFlagSet("schedule", flag.ContinueOnError)
+	req.simulationFlags.define(flags)
+	req.defineJitter(flags, "")
+	flags.Var(&req.seed, "seed", "")
+	flags.StringVar(&req.algorithms, "algorithms", "all", "")
+	flags.Int64Var(&req.quantum, "quantum", 0, "")
+	flags.BoolVar(&req.quiet, "quiet", false, "")
+	flags.Float64Var(&req.speed, "speed", 0, "")
+	for name, values := range query {
+		for _, v := range values {
+			if err := flags.Set(name, v); err != nil {
+				return nil, fmt.Errorf("%w: parameter %s: %v", ErrInvalidArgs, name, err)
+			}
+		}
+	}
+	if req.quantum < 0 || req.speed < 0 {
+		return nil, fmt.Errorf("%w: quantum and speed must not be negative", ErrInvalidArgs)
+	}
+	switch {
+	case req.cpus > maxServeCPUs:
+		return nil, fmt.Errorf("%w: cpus must be at most %d", ErrInvalidArgs, maxServeCPUs)
+	case strings.Count(req.speeds, ",") >= maxServeCPUs:
+		return nil, fmt.Errorf("%w: speeds must give at most %d speeds", ErrInvalidArgs, maxServeCPUs)
+	case req.maxTime > maxServeTime:
+		return nil, fmt.Errorf("%w: max-time must be at most %d", ErrInvalidArgs, maxServeTime)
+	}
+	req.seed.resolve()
+
+	return req, nil
+}
+
+// scheduleResponse is one algorithm's result in the response to /schedule.
+type scheduleResponse struct {
+	Algorithm string `json:"algorithm"`
+	Title     string `json:"title"`
+	*scheduler.ScheduleResult
+}
+
+// serveSchedule runs the requested algorithms over the workload posted as
+// a scheduling file, or as a JSON array of processes, and responds with
+// their results, in the form of the schedule command's JSON reports.
+func (c *serveCmd) serveSchedule(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("%w: %s /schedule, want POST", ErrInvalidArgs, r.Method))
+		return
+	}
+	req, err := parseScheduleRequest(r.URL.Query())
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	algs, err := selectAlgorithms(req.algorithms)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	processes, opts, err := req.prepare(http.MaxBytesReader(w, r.Body, maxWorkload), r.Header.Get("Content-Type"))
+	if err != nil {
+		writeError(w, errorStatus(err), err)
+		return
+	}
+
+	algs = forWorkload(algs, req.algorithms, processes)
+	jobs := make([]job, 0, len(algs))
+	for _, alg := range algs {
+		jobs = append(jobs, job{alg: alg, opts: opts})
+	}
+	scheduleAll(r.Context(), c.resultCache(), jobs, processes)
+	results := make([]scheduleResponse, 0, len(jobs))
+	for _, j := range jobs {
+		if j.err != nil {
+			writeError(w, errorStatus(j.err), fmt.Errorf("%s: %w", j.alg.name, j.err))
+			return
+		}
+		results = append(results, scheduleResponse{Algorithm: j.alg.name, Title: j.alg.title, ScheduleResult: j.r})
+	}
+	writeJSON(w, http.StatusOK, results)
+}
+
+// prepare reads the workload posted in body, of media type contentType,
+// jitters it as asked, and returns it with the options to simulate it with.
+func (req *scheduleRequest) prepare(body io.Reader, contentType string) ([]scheduler.Process, []scheduler.Option, error) {
+	processes, err := req.readWorkload(body, contentType)
+	if err != nil {
+		return nil, nil, err
+	}
+	if processes, err = req.jittered(req.rand(), processes); err != nil {
+		return nil, nil, err
+	}
+	extra := []scheduler.Option{scheduler.WithSeed(req.seed.value)}
+	if req.quantum > 0 {
+		extra = append(extra, scheduler.WithQuantum(req.quantum*req.unit()))
+	}
+	if req.quiet {
+		extra = append(extra, scheduler.WithMetricsOnly())
+	}
+	opts, err := req.options(extra...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return processes, opts, nil
+}
+
+// liveMessage is a message of a live simulation's WebSocket: an event of
+// the simulation, its running metrics as each process terminates, an
+// algorithm's result once it has finished, or the error that stopped it.
+type liveMessage struct {
+	Type      string                   `json:"type"`
+	Algorithm string                   `json:"algorithm,omitempty"`
+	Event     *liveEvent               `json:"event,omitempty"`
+	Metrics   *scheduler.MetricsUpdate `json:"metrics,omitempty"`
+	Result    *scheduleResponse        `json:"result,omitempty"`
+	Error     *errorReport             `json:"error,omitempty"`
+}
+
+// liveEvent is a process changing state: arrive, ready, dispatch, preempt,
+// block, suspend, or complete.
+type liveEvent struct {
+	Kind     string          `json:"kind"`
+	PID      int64           `json:"pid"`
+	At       int64           `json:"at"`
+	State    scheduler.State `json:"state"`
+	Priority float64         `json:"priority"`
+}
+
+// eventKind names a process's change from state from to state to.
+func eventKind(from, to scheduler.State) string {
+	switch to {
+	case scheduler.StateNew:
+		return "arrive"
+	case scheduler.StateRunning:
+		return "dispatch"
+	case scheduler.StateReady:
+		if from == scheduler.StateRunning {
+			return "preempt"
+		}
+		return "ready"
+	case scheduler.StateBlocked:
+		return "block"
+	case scheduler.StateSuspended:
+		return "suspend"
+	case scheduler.StateTerminated:
+		return "complete"
+	}
+
+	return to.String()
+}
+
+// serveLive runs the requested algorithms, one after another, over the
+// workload a WebSocket client sends as its first message, and sends each
+// process's state changes as the simulation makes them, then each result.
+// With the speed parameter the simulations run no faster than that many
+// units of time per second, for animating them.
+func (c *serveCmd) serveLive(w http.ResponseWriter, r *http.Request) {
+	// Counted from the start, while the server still tracks the
+	// connection, so shutting down cannot miss the session.
+	c.sessions.Add(1)
+	defer c.sessions.Done()
+	req, err := parseScheduleRequest(r.URL.Query())
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	algs, err := selectAlgorithms(req.algorithms)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	ws, err := upgradeWebSocket(w, r, strings.Split(c.origins, ","))
+	if errors.Is(err, errCrossOrigin) {
+		writeError(w, http.StatusForbidden, err)
+		return
+	} else if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	defer func() { _ = ws.Close(wsNormalClosure) }()
+	// The server shutting down ends the session, even while it waits
+	// for the workload.
+	ended := make(chan struct{})
+	defer close(ended)
+	go func() {
+		select {
+		case <-r.Context().Done():
+			_ = ws.Close(wsGoingAway)
+		case <-ended:
+		}
+	}()
+	send := func(m liveMessage) error {
+		data, err := json.Marshal(m)
+		if err != nil {
+			return err
+		}
+		return ws.WriteText(data)
+	}
+	sendError := func(err error) {
+		report := newErrorReport(err)
+		_ = send(liveMessage{Type: "error", Error: &report})
+	}
+
+	workload, err := ws.ReadMessage(maxWorkload)
+	if err != nil {
+		return
+	}
+	contentType := "text/csv"
+	if trimmed := bytes.TrimSpace(workload); len(trimmed) > 0 && trimmed[0] == '[' {
+		contentType = "application/json"
+	}
+	processes, opts, err := req.prepare(bytes.NewReader(workload), contentType)
+	if err != nil {
+		sendError(err)
+		return
+	}
+
+	// The connection is the client's now: it going away, or sending
+	// anything, cancels the simulation.
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+	go func() {
+		_, _ = ws.ReadMessage(maxWorkload)
+		cancel()
+	}()
+	for _, alg := range forWorkload(algs, req.algorithms, processes) {
+		start, last := time.Now(), make(map[int64]scheduler.State)
+		onTransition := func(tr scheduler.Transition) {
+			if req.speed > 0 {
+				due := start.Add(time.Duration(float64(tr.At) / float64(req.unit()) / req.speed * float64(time.Second)))
+				if wait := time.Until(due); wait > 0 {
+					t := time.NewTimer(wait)
+					select {
+					case <-t.C:
+					case <-ctx.Done():
+						t.Stop()
+					}
+				}
+			}
+			from, ok := last[tr.PID]
+			if !ok {
+				from = scheduler.StateNew
+			}
+			last[tr.PID] = tr.State
+			ev := &liveEvent{Kind: eventKind(from, tr.State), PID: tr.PID, At: tr.At, State: tr.State, Priority: tr.Priority}
+			if err := send(liveMessage{Type: "event", Algorithm: alg.name, Event: ev}); err != nil {
+				cancel()
+			}
+		}
+		onUpdate := func(u scheduler.MetricsUpdate) {
+			if err := send(liveMessage{Type: "metrics", Algorithm: alg.name, Metrics: &u}); err != nil {
+				cancel()
+			}
+		}
+		s := alg.new(append(opts, scheduler.WithTransitions(onTransition), scheduler.WithUpdates(onUpdate))...)
+		result, err := s.Schedule(ctx, append([]scheduler.Process(nil), processes...))
+		if err != nil {
+			sendError(fmt.Errorf("%s: %w", alg.name, err))
+			return
+		}
+		if err := send(liveMessage{Type: "result", Algorithm: alg.name, Result: &scheduleResponse{Algorithm: alg.name, Title: alg.title, ScheduleResult: result}}); err != nil {
+			return
+		}
+	}
+}
+
+// rand returns a random source seeded by the request's seed.
+func (req *scheduleRequest) rand() *rand.Rand {
+	return rand.New(rand.NewSource(req.seed.value))
+}
+
+// readWorkload reads the processes posted in body, whose media type is
+// contentType: a JSON array of processes, in ticks, for application/json,
+// and otherwise a scheduling file. It records the workload's ticks per unit
+// of time, as load does for a file.
+func (req *scheduleRequest) readWorkload(body io.Reader, contentType string) ([]scheduler.Process, error) {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	var processes []scheduler.Process
+	var err error
+	if mediaType == "application/json" {
+		req.ticks = 1
+		if err = json.NewDecoder(body).Decode(&processes); err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				return nil, err
+			}
+			return nil, &scheduler.ParseError{Err: err}
+		}
+	} else if processes, req.ticks, err = scheduler.LoadProcessesFractional(body); err != nil {
+		return nil, err
+	}
+
+	return checkWorkload(processes)
+}
+
+// checkWorkload validates the processes of a request and expands their
+// periodic tasks, failing if that makes more than a request may simulate.
+func checkWorkload(processes []scheduler.Process) ([]scheduler.Process, error) {
+	if err := scheduler.ValidateProcesses(processes); err != nil {
+		return nil, err
+	}
+	processes, err := scheduler.ExpandPeriodic(processes)
+	if err != nil {
+		return nil, err
+	}
+	if len(processes) > maxServeProcesses {
+		return nil, fmt.Errorf("%w: workload has %d processes, more than the %d a request may simulate", ErrInvalidArgs, len(processes), maxServeProcesses)
+	}
+
+	return processes, nil
+}
+
+// errorStatus is the HTTP status of a failed request.
+func errorStatus(err error) int {
+	var tooLarge *http.MaxBytesError
+	switch {
+	case errors.As(err, &tooLarge):
+		return http.StatusRequestEntityTooLarge
+	case errors.Is(err, ErrInvalidArgs), errors.Is(err, scheduler.ErrParse), errors.Is(err, scheduler.ErrValidation):
+		return http.StatusBadRequest
+	case errors.Is(err, context.Canceled):
+		// The client went away; it will not see the response anyway.
+		return http.StatusServiceUnavailable
+	}
+
+	return http.StatusInternalServerError
+}
+
+// writeError responds with err in the -errors json form.
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, newErrorReport(err))
+}
+
+// writeJSON responds with v as JSON.
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
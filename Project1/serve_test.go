@@ -0,0 +1,293 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rks0134/CSCE4600/Project1/scheduler"
+)
+
+func Test_serve(t *testing.T) {
+	t.Parallel()
+	srv := httptest.NewServer((&serveCmd{globalFlags: &globalFlags{}}).handler())
+	defer srv.Close()
+	csv, err := os.ReadFile("example_processes.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	post := func(query, contentType, body string) (int, []byte) {
+		t.Helper()
+		resp, err := http.Post(srv.URL+"/schedule?"+query, contentType, strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		data, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp.StatusCode, data
+	}
+
+	status, data := post("algorithms=rr,sjf&quantum=3&seed=1&cpus=2", "text/csv", string(csv))
+	if status != http.StatusOK {
+		t.Fatalf("POST /schedule status = %d, body %s", status, data)
+	}
+	var got []scheduleResponse
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	processes, err := scheduler.LoadProcesses(bytes.NewReader(csv))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := scheduler.NewRR(scheduler.WithQuantum(3), scheduler.WithCPUs(2), scheduler.WithSeed(1)).Schedule(context.Background(), processes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0].Algorithm != "rr" || got[1].Algorithm != "sjf" || !reflect.DeepEqual(got[0].Metrics, want.Metrics) {
+		t.Errorf("POST /schedule = %s, want rr and sjf results with rr metrics %+v", data, want.Metrics)
+	}
+
+	processesJSON, err := json.Marshal(processes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status, data := post("algorithms=fcfs", "application/json", string(processesJSON)); status != http.StatusOK {
+		t.Errorf("POST /schedule with JSON status = %d, body %s", status, data)
+	}
+
+	var huge strings.Builder
+	for pid := 1; pid <= maxServeProcesses+1; pid++ {
+		fmt.Fprintf(&huge, "%d,1,0,1\n", pid)
+	}
+	for _, tt := range []struct {
+		query, body string
+		want        int
+		line        int
+	}{
+		{query: "algorithms=lottery", body: string(csv), want: http.StatusBadRequest},
+		{query: "cpus=0", body: string(csv), want: http.StatusBadRequest},
+		{query: "bogus=1", body: string(csv), want: http.StatusBadRequest},
+		{query: "", body: "1,2,0,1\n2,x,0,1\n", want: http.StatusBadRequest, line: 2},
+		{query: "", body: "1,0,0,1\n", want: http.StatusBadRequest},
+		{query: "cpus=100000000", body: string(csv), want: http.StatusBadRequest},
+		{query: "speeds=" + strings.Repeat("1,", 1024) + "1", body: string(csv), want: http.StatusBadRequest},
+		{query: "max-time=10000000000", body: string(csv), want: http.StatusBadRequest},
+		{query: "", body: huge.String(), want: http.StatusBadRequest},
+	} {
+		status, data := post(tt.query, "text/csv", tt.body)
+		var report errorReport
+		if err := json.Unmarshal(data, &report); err != nil {
+			t.Fatal(err)
+		}
+		if status != tt.want || report.Line != tt.line || report.Message == "" {
+			t.Errorf("POST /schedule?%s = %d %s, want status %d at line %d", tt.query, status, data, tt.want, tt.line)
+		}
+	}
+
+	resp, err := http.Get(srv.URL + "/algorithms")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var algs []algorithmInfo
+	if err := json.NewDecoder(resp.Body).Decode(&algs); err != nil || len(algs) != len(algorithms()) {
+		t.Errorf("GET /algorithms = %v, %v, want %d algorithms", algs, err, len(algorithms()))
+	}
+
+	for path, want := range map[string]int{"/": http.StatusOK, "/missing": http.StatusNotFound} {
+		resp, err := http.Get(srv.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		page, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.StatusCode != want || want == http.StatusOK && !bytes.Equal(page, dashboard) {
+			t.Errorf("GET %s = %d, want %d", path, resp.StatusCode, want)
+		}
+	}
+
+	draining := &serveCmd{globalFlags: &globalFlags{}}
+	probes := httptest.NewServer(draining.handler())
+	defer probes.Close()
+	for _, tt := range []struct {
+		path     string
+		draining bool
+		want     int
+	}{
+		{path: "/healthz", want: http.StatusOK},
+		{path: "/readyz", want: http.StatusOK},
+		{path: "/healthz", draining: true, want: http.StatusOK},
+		{path: "/readyz", draining: true, want: http.StatusServiceUnavailable},
+	} {
+		draining.draining.Store(tt.draining)
+		resp, err := http.Get(probes.URL + tt.path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != tt.want {
+			t.Errorf("GET %s while draining %t = %d, want %d", tt.path, tt.draining, resp.StatusCode, tt.want)
+		}
+	}
+
+	// Interrupting serve shuts the server down.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	var out strings.Builder
+	if err := run(ctx, &out, "binary_name", "serve", "-host", "127.0.0.1", "-port", "0"); err != nil || !strings.HasPrefix(out.String(), "Serving on http://127.0.0.1:") {
+		t.Errorf("serve = %v, printed %q", err, out.String())
+	}
+}
+
+func Test_serveLive(t *testing.T) {
+	t.Parallel()
+	srv := httptest.NewServer((&serveCmd{globalFlags: &globalFlags{}}).handler())
+	defer srv.Close()
+	conn, err := net.Dial("tcp", srv.Listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	_, _ = fmt.Fprintf(conn, "GET /live?algorithms=fcfs,rr&seed=1 HTTP/1.1\r\nHost: test\r\nConnection: Upgrade\r\nUpgrade: websocket\r\nSec-WebSocket-Version: 13\r\nSec-WebSocket-Key: dGhlIHNhbXBsZSBub25jZQ==\r\n\r\n")
+	br := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(br, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols || resp.Header.Get("Sec-WebSocket-Accept") != "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=" {
+		t.Fatalf("handshake response %s, accept %q", resp.Status, resp.Header.Get("Sec-WebSocket-Accept"))
+	}
+	csv, err := os.ReadFile("example_processes.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := writeFrame(conn, wsText, csv, true); err != nil {
+		t.Fatal(err)
+	}
+
+	processes, err := scheduler.LoadProcesses(bytes.NewReader(csv))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var results []string
+	events, updates := make(map[string][]string), make(map[string]int)
+	for len(results) < 2 {
+		_, opcode, payload, err := readFrame(br, maxWorkload, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if opcode != wsText {
+			t.Fatalf("got frame with opcode %d, want text", opcode)
+		}
+		var m liveMessage
+		if err := json.Unmarshal(payload, &m); err != nil {
+			t.Fatal(err)
+		}
+		switch m.Type {
+		case "event":
+			events[m.Algorithm] = append(events[m.Algorithm], m.Event.Kind)
+		case "metrics":
+			updates[m.Algorithm] = m.Metrics.Completed
+		case "result":
+			results = append(results, m.Algorithm)
+			if got := m.Result.Metrics.Completed; got != len(processes) || updates[m.Algorithm] != got {
+				t.Errorf("%s result completed %d processes after updates with %d, want %d", m.Algorithm, got, updates[m.Algorithm], len(processes))
+			}
+		default:
+			t.Fatalf("got message %s", payload)
+		}
+	}
+	if !reflect.DeepEqual(results, []string{"fcfs", "rr"}) {
+		t.Errorf("results for %v, want fcfs then rr", results)
+	}
+	count := func(kinds []string, kind string) int {
+		n := 0
+		for _, k := range kinds {
+			if k == kind {
+				n++
+			}
+		}
+		return n
+	}
+	for alg, kinds := range events {
+		if count(kinds, "arrive") != len(processes) || count(kinds, "complete") != len(processes) {
+			t.Errorf("%s events %v, want every process to arrive and complete", alg, kinds)
+		}
+	}
+	if count(events["fcfs"], "preempt") != 0 || count(events["rr"], "preempt") == 0 {
+		t.Errorf("fcfs preempted %d times and rr %d times, want only rr to preempt", count(events["fcfs"], "preempt"), count(events["rr"], "preempt"))
+	}
+	if _, opcode, _, err := readFrame(br, maxWorkload, false); err != nil || opcode != wsClose {
+		t.Errorf("got frame with opcode %d and error %v after the results, want close", opcode, err)
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, srv.URL+"/live", nil)
+	req.Header.Set("Origin", "https://evil.example")
+	if resp, err := http.DefaultClient.Do(req); err != nil || resp.StatusCode != http.StatusForbidden {
+		t.Errorf("GET /live from another origin = %v, %v, want status 403", resp, err)
+	} else {
+		resp.Body.Close()
+	}
+	if resp, err := http.Get(srv.URL + "/live"); err != nil || resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("GET /live without a handshake = %v, %v, want status 400", resp, err)
+	} else {
+		resp.Body.Close()
+	}
+}
+
+func Test_serveShutdownLive(t *testing.T) {
+	t.Parallel()
+	ctx, stop := context.WithCancel(context.Background())
+	defer stop()
+	out, w := io.Pipe()
+	c := &serveCmd{globalFlags: &globalFlags{}, host: "127.0.0.1"}
+	ran := make(chan error, 1)
+	go func() { ran <- c.run(ctx, w, nil) }()
+	line, err := bufio.NewReader(out).ReadString('\n')
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn, err := net.Dial("tcp", strings.TrimPrefix(strings.TrimSpace(line), "Serving on http://"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	_, _ = fmt.Fprintf(conn, "GET /live HTTP/1.1\r\nHost: test\r\nConnection: Upgrade\r\nUpgrade: websocket\r\nSec-WebSocket-Version: 13\r\nSec-WebSocket-Key: dGhlIHNhbXBsZSBub25jZQ==\r\n\r\n")
+	br := bufio.NewReader(conn)
+	if resp, err := http.ReadResponse(br, nil); err != nil || resp.StatusCode != http.StatusSwitchingProtocols {
+		t.Fatalf("handshake response %v, %v", resp, err)
+	}
+
+	// The session waits for its workload until the server stops.
+	stop()
+	_, opcode, payload, err := readFrame(br, maxWorkload, false)
+	if err != nil || opcode != wsClose || !bytes.Equal(payload, binary.BigEndian.AppendUint16(nil, wsGoingAway)) {
+		t.Errorf("got frame with opcode %d, payload %v, and error %v on shutdown, want close going away", opcode, payload, err)
+	}
+	select {
+	case err := <-ran:
+		if err != nil {
+			t.Errorf("run() error = %v", err)
+		}
+	case <-time.After(shutdownGrace / 2):
+		t.Error("run() did not return once the session closed")
+	}
+}
@@ -0,0 +1,198 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+
+	"github.com/rks0134/CSCE4600/Project1/scheduler"
+)
+
+// simulationFlags are the simulation parameters shared by every command that
+// runs schedulers on a scheduling file.
+type simulationFlags struct {
+	switchCost      int64
+	dispatchLatency int64
+	migrationCost   int64
+	warmUp          int64
+	coolDown        int64
+	maxTime         int64
+	memory          int64
+	tieBreak        string
+	cpus            int
+	speeds          string
+	power           string
+	inherit         bool
+	aging           float64
+	jitter          int64
+	jitterDist      string
+	// ticks is the number of ticks per unit of time in the workload loaded
+	// with load, which every time given by the flags is scaled by.
+	ticks int64
+}
+
+// load loads the workload at path, recording its ticks per unit of time.
+func (f *simulationFlags) load(path string) ([]scheduler.Process, error) {
+	processes, ticks, err := loadWorkload(path)
+	f.ticks = ticks
+	return processes, err
+}
+
+// unit is the number of ticks per unit of time in the loaded workload.
+func (f *simulationFlags) unit() int64 {
+	if f.ticks < 1 {
+		return 1
+	}
+	return f.ticks
+}
+
+func (f *simulationFlags) define(flags *flag.FlagSet) {
+	flags.Int64Var(&f.switchCost, "switch-cost", 0, "context-switch overhead `time` charged between slices of different processes")
+	flags.Int64Var(&f.dispatchLatency, "dispatch-latency", 0, "dispatcher latency `time` charged on every dispatch, including the first")
+	flags.Int64Var(&f.migrationCost, "migration-cost", 0, "cache penalty `time` charged when a process starts on a different CPU than it last ran on")
+	flags.Int64Var(&f.warmUp, "warm-up", 0, "leave processes arriving in the first `time` units out of the averages, for steady-state comparisons")
+	flags.Int64Var(&f.coolDown, "cool-down", 0, "leave processes completing in the last `time` units, the final drain, out of the averages")
+	flags.Int64Var(&f.maxTime, "max-time", 0, "stop each simulation at `time`, reporting the processes left unfinished (default no limit)")
+	flags.Int64Var(&f.memory, "memory", 0, "total `memory` processes are admitted into; processes whose mem= field does not fit wait, in arrival order (default unlimited)")
+	flags.IntVar(&f.cpus, "cpus", 1, "`number` of CPUs to schedule onto")
+	flags.StringVar(&f.speeds, "speeds", "", "comma separated speed `multipliers`, one per CPU, e.g. 2,2,1,1 (default all 1)")
+	flags.StringVar(&f.power, "power", "", "energy model as comma separated active=, static=, and idle= `powers` per unit of time, e.g. active=4,static=1,idle=0.2: a CPU at speed s draws static+active*s³ running and idle when idle (default no energy report)")
+	flags.Float64Var(&f.aging, "aging", 0, "priority `rate` a waiting process gains per unit of time waiting, so priority-ordered schedulers cannot starve it")
+	flags.BoolVar(&f.inherit, "inherit", false, "let a process holding a lock run at the priority of the most urgent process blocked on it")
+	flags.StringVar(&f.jitterDist, "jitter-dist", "uniform", "`distribution` of -jitter shifts: uniform (up to ±n), normal (standard deviation n), or exponential (delays with mean n)")
+	flags.StringVar(&f.tieBreak, "tie-break", "arrival", "how equal candidates are ordered: arrival, pid, priority, or random (drawn from -seed)")
+}
+
+// defineJitter registers -jitter, which commands describe differently.
+func (f *simulationFlags) defineJitter(flags *flag.FlagSet, usage string) {
+	flags.Int64Var(&f.jitter, "jitter", 0, usage)
+}
+
+// jittered returns a copy of processes with arrivals shifted by -jitter,
+// drawn from -jitter-dist using rng. The processes themselves, like the
+// scheduling file they came from, are left untouched.
+func (f *simulationFlags) jittered(rng *rand.Rand, processes []scheduler.Process) ([]scheduler.Process, error) {
+	if f.jitter < 0 {
+		return nil, fmt.Errorf("%w: -jitter must not be negative", ErrInvalidArgs)
+	}
+	dist, err := scheduler.ParseDistribution(f.jitterDist)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidArgs, err)
+	}
+
+	return scheduler.JitterWith(rng, processes, f.jitter*f.unit(), dist), nil
+}
+
+// options returns the scheduler options for the flags, followed by extra.
+// Times are scaled to the ticks of the loaded workload, so call it after
+// load.
+func (f *simulationFlags) options(extra ...scheduler.Option) ([]scheduler.Option, error) {
+	if f.switchCost < 0 || f.dispatchLatency < 0 {
+		return nil, fmt.Errorf("%w: -switch-cost and -dispatch-latency must not be negative", ErrInvalidArgs)
+	}
+	if f.migrationCost < 0 {
+		return nil, fmt.Errorf("%w: -migration-cost must not be negative", ErrInvalidArgs)
+	}
+	if f.warmUp < 0 || f.coolDown < 0 {
+		return nil, fmt.Errorf("%w: -warm-up and -cool-down must not be negative", ErrInvalidArgs)
+	}
+	if f.memory < 0 {
+		return nil, fmt.Errorf("%w: -memory must not be negative", ErrInvalidArgs)
+	}
+	if f.maxTime < 0 {
+		return nil, fmt.Errorf("%w: -max-time must not be negative", ErrInvalidArgs)
+	}
+	if f.aging < 0 {
+		return nil, fmt.Errorf("%w: -aging must not be negative", ErrInvalidArgs)
+	}
+	if f.cpus < 1 {
+		return nil, fmt.Errorf("%w: -cpus must be at least 1", ErrInvalidArgs)
+	}
+	speeds, err := parseSpeeds(f.speeds)
+	if err != nil {
+		return nil, err
+	}
+	if speeds != nil && len(speeds) != f.cpus {
+		return nil, fmt.Errorf("%w: -speeds gives %d speeds for %d CPUs", ErrInvalidArgs, len(speeds), f.cpus)
+	}
+	power, err := parsePower(f.power)
+	if err != nil {
+		return nil, err
+	}
+	tb, err := scheduler.ParseTieBreak(f.tieBreak)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidArgs, err)
+	}
+	u := f.unit()
+	opts := []scheduler.Option{
+		scheduler.WithQuantum(scheduler.DefaultOptions().Quantum * u),
+		scheduler.WithSwitchCost(f.switchCost * u),
+		scheduler.WithDispatchLatency(f.dispatchLatency * u),
+		scheduler.WithMigrationCost(f.migrationCost * u),
+		scheduler.WithWarmUp(f.warmUp * u),
+		scheduler.WithCoolDown(f.coolDown * u),
+		scheduler.WithMaxTime(f.maxTime * u),
+		scheduler.WithMemory(f.memory),
+		scheduler.WithTieBreak(tb),
+		scheduler.WithCPUs(f.cpus),
+		scheduler.WithSpeeds(speeds...),
+		scheduler.WithPower(power),
+		scheduler.WithAging(f.aging / float64(u)),
+		scheduler.WithTicksPerUnit(u),
+	}
+	if f.inherit {
+		opts = append(opts, scheduler.WithPriorityInheritance())
+	}
+
+	return append(opts, extra...), nil
+}
+
+// parseSpeeds parses a comma separated list of positive speed multipliers,
+// returning nil for an empty list.
+func parseSpeeds(list string) ([]float64, error) {
+	if list == "" {
+		return nil, nil
+	}
+	var speeds []float64
+	for _, s := range strings.Split(list, ",") {
+		speed, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+		if err != nil || speed <= 0 {
+			return nil, fmt.Errorf("%w: invalid CPU speed %q", ErrInvalidArgs, s)
+		}
+		speeds = append(speeds, speed)
+	}
+
+	return speeds, nil
+}
+
+// parsePower parses a comma separated list of active=, static=, and idle=
+// powers, each non-negative; those left out are zero.
+func parsePower(list string) (scheduler.Power, error) {
+	var p scheduler.Power
+	if list == "" {
+		return p, nil
+	}
+	for _, pair := range strings.Split(list, ",") {
+		name, value, _ := strings.Cut(strings.TrimSpace(pair), "=")
+		var field *float64
+		switch name {
+		case "active":
+			field = &p.Active
+		case "static":
+			field = &p.Static
+		case "idle":
+			field = &p.Idle
+		default:
+			return p, fmt.Errorf("%w: unknown power %q, want active, static, or idle", ErrInvalidArgs, name)
+		}
+		power, err := strconv.ParseFloat(value, 64)
+		if err != nil || power < 0 {
+			return p, fmt.Errorf("%w: invalid %s power %q", ErrInvalidArgs, name, value)
+		}
+		*field = power
+	}
+
+	return p, nil
+}
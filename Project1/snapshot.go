@@ -0,0 +1,173 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/rks0134/CSCE4600/Project1/scheduler"
+)
+
+// clockTick is how long a clock tick of /proc is: USER_HZ is 100 on every
+// Linux architecture the module builds for.
+const clockTick = 10 * time.Millisecond
+
+// snapshotCmd samples the machine's processes twice and writes what each
+// did in between as a scheduling file.
+type snapshotCmd struct {
+	interval time.Duration
+	unit     time.Duration
+	all      bool
+}
+
+func (c *snapshotCmd) defineFlags(flags *flag.FlagSet) {
+	flags.DurationVar(&c.interval, "interval", time.Second, "how long to sample the processes' CPU usage for")
+	flags.DurationVar(&c.unit, "unit", clockTick, "the wall-clock `duration` of one time unit of the workload, at least 10ms")
+	flags.BoolVar(&c.all, "all", false, "include processes that did not run while sampled, with a burst of one unit")
+}
+
+func (c *snapshotCmd) run(ctx context.Context, w io.Writer, args []string) (err error) {
+	if len(args) > 1 {
+		return fmt.Errorf("%w: must give at most the file to write", ErrInvalidArgs)
+	}
+	if c.interval <= 0 || c.unit < clockTick {
+		return fmt.Errorf("%w: -interval must be positive and -unit at least %v", ErrInvalidArgs, clockTick)
+	}
+	before, err := sampleProcesses()
+	if err != nil {
+		return err
+	}
+	t := time.NewTimer(c.interval)
+	select {
+	case <-t.C:
+	case <-ctx.Done():
+		t.Stop()
+		return ctx.Err()
+	}
+	after, err := sampleProcesses()
+	if err != nil {
+		return err
+	}
+	processes := snapshotWorkload(before, after, c.unit, c.all)
+	if len(processes) == 0 {
+		return errors.New("no process ran while sampled; sample for longer or use -all")
+	}
+
+	out, finish := w, func(err error) error { return err }
+	if len(args) == 1 {
+		if out, finish, err = createOutput(args[0]); err != nil {
+			return err
+		}
+	}
+	err = writeWorkload(out, processes)
+	if len(args) == 1 {
+		if finishErr := finish(err); err == nil {
+			err = finishErr
+		}
+		if err == nil {
+			_, _ = fmt.Fprintf(w, "Wrote %d processes to %s\n", len(processes), args[0])
+		}
+	}
+
+	return err
+}
+
+// procSample is a process as /proc/<pid>/stat describes it, times in clock
+// ticks.
+type procSample struct {
+	pid int64
+	// cpu is the time the process has run, in user and kernel mode.
+	cpu int64
+	// priority is the kernel's: -100 to -2 for real-time processes, most
+	// urgent first, and 20 plus the nice value for the rest.
+	priority, nice int64
+	// start is when the process started, after boot.
+	start int64
+}
+
+// procSnapshot is every process at a moment, uptime clock ticks after boot.
+type procSnapshot struct {
+	uptime    int64
+	processes []procSample
+}
+
+// parseProcStat parses the contents of /proc/<pid>/stat.
+func parseProcStat(stat []byte) (procSample, error) {
+	// The command name is in parentheses and may contain anything,
+	// parentheses and spaces included, so fields are counted from its end.
+	open, end := bytes.IndexByte(stat, '('), bytes.LastIndexByte(stat, ')')
+	if open < 0 || end < open {
+		return procSample{}, fmt.Errorf("malformed stat %q", stat)
+	}
+	var s procSample
+	pid, err := strconv.ParseInt(string(bytes.TrimSpace(stat[:open])), 10, 64)
+	if err != nil {
+		return procSample{}, fmt.Errorf("malformed stat %q", stat)
+	}
+	s.pid = pid
+	// Fields from the state, the third, on.
+	fields := bytes.Fields(stat[end+1:])
+	if len(fields) < 20 {
+		return procSample{}, fmt.Errorf("stat of process %d has %d fields, want at least 22", pid, len(fields)+2)
+	}
+	var n [5]int64
+	for k, field := range []int{11, 12, 15, 16, 19} { // utime, stime, priority, nice, starttime
+		if n[k], err = strconv.ParseInt(string(fields[field]), 10, 64); err != nil {
+			return procSample{}, fmt.Errorf("stat of process %d: %w", pid, err)
+		}
+	}
+	s.cpu, s.priority, s.nice, s.start = n[0]+n[1], n[2], n[3], n[4]
+
+	return s, nil
+}
+
+// snapshotWorkload turns what the processes sampled in both before and
+// after did in between into a workload, in time units of unit. Each
+// process's burst is the CPU time it used, rounded up; it arrives when it
+// started, or at 0 if it was already running. Real-time processes get
+// priority 1 and the rest 11 to 50 by nice value, with their nice value
+// kept. Processes that did not run are left out unless all is set, and
+// then get a burst of one unit.
+func snapshotWorkload(before, after procSnapshot, unit time.Duration, all bool) []scheduler.Process {
+	ran := make(map[int64]int64, len(before.processes))
+	for _, s := range before.processes {
+		ran[s.pid] = s.cpu
+	}
+	ticks := int64(unit / clockTick)
+	var processes []scheduler.Process
+	for _, s := range after.processes {
+		p := scheduler.Process{ProcessID: s.pid, Nice: s.nice}
+		started, seen := ran[s.pid]
+		if s.start < before.uptime {
+			if !seen {
+				continue // missed by the first sample
+			}
+		} else {
+			started = 0
+			p.ArrivalTime = (s.start - before.uptime) / ticks
+		}
+		p.BurstDuration = (s.cpu - started + ticks - 1) / ticks
+		if p.BurstDuration <= 0 {
+			if !all {
+				continue
+			}
+			p.BurstDuration = 1
+		}
+		switch p.Priority = s.priority + 11; {
+		case p.Priority < 1:
+			p.Priority = 1
+		case p.Priority > 50:
+			p.Priority = 50
+		}
+		processes = append(processes, p)
+	}
+	sort.Slice(processes, func(i, j int) bool { return processes[i].ProcessID < processes[j].ProcessID })
+
+	return processes
+}
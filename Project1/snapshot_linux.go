@@ -0,0 +1,47 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+// sampleProcesses reads every process from /proc. Processes that exit
+// while it reads are left out.
+func sampleProcesses() (procSnapshot, error) {
+	uptime, err := os.ReadFile("/proc/uptime")
+	if err != nil {
+		return procSnapshot{}, fmt.Errorf("snapshot: %w", err)
+	}
+	fields := bytes.Fields(uptime)
+	if len(fields) == 0 {
+		return procSnapshot{}, fmt.Errorf("snapshot: malformed /proc/uptime %q", uptime)
+	}
+	seconds, err := strconv.ParseFloat(string(fields[0]), 64)
+	if err != nil {
+		return procSnapshot{}, fmt.Errorf("snapshot: malformed /proc/uptime: %w", err)
+	}
+	snap := procSnapshot{uptime: int64(seconds * float64(time.Second/clockTick))}
+	entries, err := os.ReadDir("/proc")
+	if err != nil {
+		return procSnapshot{}, fmt.Errorf("snapshot: %w", err)
+	}
+	for _, entry := range entries {
+		if _, err := strconv.ParseInt(entry.Name(), 10, 64); err != nil {
+			continue
+		}
+		stat, err := os.ReadFile("/proc/" + entry.Name() + "/stat")
+		if err != nil {
+			continue
+		}
+		s, err := parseProcStat(stat)
+		if err != nil {
+			return procSnapshot{}, fmt.Errorf("snapshot: %w", err)
+		}
+		snap.processes = append(snap.processes, s)
+	}
+
+	return snap, nil
+}
@@ -0,0 +1,10 @@
+//go:build !linux
+
+package main
+
+import "errors"
+
+// sampleProcesses needs Linux's /proc.
+func sampleProcesses() (procSnapshot, error) {
+	return procSnapshot{}, errors.New("snapshot: sampling processes needs Linux's /proc")
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rks0134/CSCE4600/Project1/scheduler"
+)
+
+func Test_parseProcStat(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		stat    string
+		want    procSample
+		wantErr bool
+	}{
+		{
+			name: "plain",
+			stat: "42 (bash) S 1 42 42 34816 42 4194560 1 2 3 4 150 25 0 0 20 0 1 0 9000 1000 200 18446744073709551615\n",
+			want: procSample{pid: 42, cpu: 175, priority: 20, nice: 0, start: 9000},
+		},
+		{
+			name: "name with spaces and parentheses",
+			stat: "7 (a (b) c) R 1 7 7 0 -1 0 0 0 0 0 3 4 0 0 -51 0 1 0 120 0 0",
+			want: procSample{pid: 7, cpu: 7, priority: -51, nice: 0, start: 120},
+		},
+		{
+			name: "niced",
+			stat: "9 (make) S 1 9 9 0 -1 0 0 0 0 0 10 0 0 0 39 19 1 0 5 0 0",
+			want: procSample{pid: 9, cpu: 10, priority: 39, nice: 19, start: 5},
+		},
+		{name: "truncated", stat: "9 (make) S 1 9 9", wantErr: true},
+		{name: "no name", stat: "9 make S 1", wantErr: true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := parseProcStat([]byte(tt.stat))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseProcStat() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("parseProcStat() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_snapshotWorkload(t *testing.T) {
+	t.Parallel()
+	before := procSnapshot{uptime: 1000, processes: []procSample{
+		{pid: 1, cpu: 50, priority: 20, start: 0},
+		{pid: 2, cpu: 80, priority: 39, nice: 19, start: 10},
+		{pid: 3, cpu: 5, priority: 0, nice: -20, start: 20},
+		{pid: 5, cpu: 500, priority: 20, start: 30},
+	}}
+	after := procSnapshot{uptime: 1100, processes: []procSample{
+		{pid: 1, cpu: 50, priority: 20, start: 0},            // idle
+		{pid: 2, cpu: 95, priority: 39, nice: 19, start: 10}, // ran 15 ticks
+		{pid: 3, cpu: 26, priority: 0, nice: -20, start: 20}, // ran 21 ticks
+		{pid: 4, cpu: 30, priority: -100, start: 1040},       // real-time, new
+		{pid: 5, cpu: 8, priority: 20, start: 1060},          // PID reused
+		{pid: 6, cpu: 9, priority: 20, start: 900},           // missed before
+	}}
+	got := snapshotWorkload(before, after, 100*time.Millisecond, false)
+	want := []scheduler.Process{
+		{ProcessID: 2, BurstDuration: 2, Priority: 50, Nice: 19},
+		{ProcessID: 3, BurstDuration: 3, Priority: 11, Nice: -20},
+		{ProcessID: 4, BurstDuration: 3, ArrivalTime: 4, Priority: 1},
+		{ProcessID: 5, BurstDuration: 1, ArrivalTime: 6, Priority: 31},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("snapshotWorkload() = %+v, want %+v", got, want)
+	}
+	got = snapshotWorkload(before, after, 100*time.Millisecond, true)
+	if len(got) != 5 || got[0].ProcessID != 1 || got[0].BurstDuration != 1 {
+		t.Errorf("snapshotWorkload() with all = %+v, want process 1 first with a burst of 1", got)
+	}
+
+	var b strings.Builder
+	if err := writeWorkload(&b, want); err != nil {
+		t.Fatal(err)
+	}
+	processes, err := scheduler.LoadProcesses(strings.NewReader(b.String()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(processes, want) {
+		t.Errorf("written snapshot loads as %+v, want %+v", processes, want)
+	}
+}
+
+func Test_runSnapshot(t *testing.T) {
+	t.Parallel()
+	if _, err := os.Stat("/proc/self/stat"); err != nil {
+		t.Skip("no /proc")
+	}
+	path := filepath.Join(t.TempDir(), "mine.csv")
+	if err := run(context.Background(), io.Discard, "binary_name", "snapshot", "-interval", "10ms", "-all", path); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	processes, err := scheduler.LoadProcesses(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := scheduler.ValidateProcesses(processes); err != nil {
+		t.Error(err)
+	}
+	self := false
+	for _, p := range processes {
+		self = self || p.ProcessID == int64(os.Getpid())
+	}
+	if !self {
+		t.Errorf("snapshot of %d processes is missing the test's own, %d", len(processes), os.Getpid())
+	}
+	if err := run(context.Background(), io.Discard, "binary_name", "snapshot", "-unit", "1ms"); !errors.Is(err, ErrInvalidArgs) {
+		t.Errorf("snapshot with -unit 1ms error = %v, want invalid args", err)
+	}
+}
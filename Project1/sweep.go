@@ -0,0 +1,329 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+
+	"github.com/rks0134/CSCE4600/Project1/scheduler"
+)
+
+// sweepCmd runs algorithms across a range of parameter values on one
+// workload: either a range of quanta, or repeated trials with jittered
+// arrival times.
+type sweepCmd struct {
+	*globalFlags
+	simulationFlags
+	webhookFlags
+	flags      *flag.FlagSet
+	quantum    string
+	algorithms string
+	trials     int
+}
+
+func (c *sweepCmd) defineFlags(flags *flag.FlagSet) {
+	c.flags = flags
+	flags.StringVar(&c.quantum, "quantum", "1..16", "`range` of quanta to sweep, as lo..hi or lo..hi:step; a single quantum with -jitter")
+	flags.StringVar(&c.algorithms, "algorithms", "rr", "comma separated `algorithms` to sweep, or all")
+	c.defineJitter(flags, "jitter each arrival by `n` time units (see -jitter-dist) per trial instead of sweeping the quantum")
+	flags.IntVar(&c.trials, "trials", 100, "`number` of jittered trials per algorithm")
+	c.webhookFlags.define(flags)
+	c.simulationFlags.define(flags)
+}
+
+func (c *sweepCmd) run(ctx context.Context, w io.Writer, args []string) (err error) {
+	w, finish, err := c.webhookFlags.start("sweep", args, w)
+	if err != nil {
+		return err
+	}
+	defer func() { err = finish(err) }()
+	if c.jitter < 0 || c.trials <= 0 {
+		return fmt.Errorf("%w: -jitter must not be negative and -trials must be positive", ErrInvalidArgs)
+	}
+	// Check the simulation flags before loading the workload; the options
+	// themselves depend on its time unit.
+	if _, err := c.options(); err != nil {
+		return err
+	}
+	if c.jitter > 0 {
+		return c.runJitter(ctx, w, args)
+	}
+
+	quanta, err := parseRange(c.quantum)
+	if err != nil {
+		return err
+	}
+	algs, err := quantumAlgorithmsOf(c.algorithms)
+	if err != nil {
+		return err
+	}
+	path, err := workloadPath(args)
+	if err != nil {
+		return err
+	}
+	processes, err := c.load(path)
+	if err != nil {
+		return err
+	}
+	simOpts, err := c.options(scheduler.WithSeed(c.seed.value), scheduler.WithMetricsOnly())
+	if err != nil {
+		return err
+	}
+	bar, err := c.progressBar("sweep")
+	if err != nil {
+		return err
+	}
+
+	_, _ = fmt.Fprintf(w, "Quantum sweep (seed %d)\n", c.seed.value)
+	runs, total := 0, len(algs)*len(quanta)
+	for _, alg := range algs {
+		results := make([]scheduler.Metrics, 0, len(quanta))
+		for _, q := range quanta {
+			r, err := c.resultCache().schedule(ctx, alg, append(simOpts, scheduler.WithQuantum(q*c.unit())), processes)
+			if err != nil {
+				// Report the quanta swept before the interruption.
+				if len(results) > 0 && errors.Is(err, context.Canceled) {
+					outputQuantumSweep(w, alg.title, quanta[:len(results)], results)
+				}
+				return err
+			}
+			if r.Metrics.Completed+r.Metrics.Killed < r.Total {
+				return fmt.Errorf("%s completed only %d of %d processes with quantum %d", alg.name, r.Metrics.Completed, r.Total, q)
+			}
+			results = append(results, r.UnitMetrics())
+			r.Release()
+			if runs++; bar != nil {
+				bar.update(runs, total)
+			}
+		}
+		outputQuantumSweep(w, alg.title, quanta, results)
+		if err := flush(w); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// runJitter schedules the workload once as given and then over c.trials
+// copies with jittered arrivals, reporting how much each metric moves.
+func (c *sweepCmd) runJitter(ctx context.Context, w io.Writer, args []string) error {
+	var quanta []int64
+	if c.isSet("quantum") {
+		var err error
+		if quanta, err = parseRange(c.quantum); err != nil {
+			return err
+		}
+		if len(quanta) != 1 {
+			return fmt.Errorf("%w: -jitter sweeps take a single -quantum, got %q", ErrInvalidArgs, c.quantum)
+		}
+	}
+	algs, err := selectAlgorithms(c.algorithms)
+	if err != nil {
+		return err
+	}
+	path, err := workloadPath(args)
+	if err != nil {
+		return err
+	}
+	processes, err := c.load(path)
+	if err != nil {
+		return err
+	}
+	opts, err := c.options(scheduler.WithSeed(c.seed.value), scheduler.WithMetricsOnly())
+	if err != nil {
+		return err
+	}
+	if quanta != nil {
+		opts = append(opts, scheduler.WithQuantum(quanta[0]*c.unit()))
+	}
+	bar, err := c.progressBar("sweep")
+	if err != nil {
+		return err
+	}
+
+	// Every algorithm sees the same jittered workloads, so differences in
+	// sensitivity come from the algorithm rather than from the draw.
+	rng := c.rand()
+	workloads := make([][]scheduler.Process, c.trials)
+	for i := range workloads {
+		if workloads[i], err = c.jittered(rng, processes); err != nil {
+			return err
+		}
+	}
+
+	_, _ = fmt.Fprintf(w, "Arrival jitter sweep (seed %d, %s jitter %d, %d trials)\n", c.seed.value, c.jitterDist, c.jitter, c.trials)
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"Algorithm", "Metric", "Baseline", "Mean", "Std dev", "Min", "Max"})
+	table.SetAutoMergeCellsByColumnIndex([]int{0})
+	runs, total := 0, len(algs)*(len(workloads)+1)
+	cache := c.resultCache()
+	for _, alg := range algs {
+		var baseline scheduler.Metrics
+		trials := make([]scheduler.Metrics, 0, len(workloads))
+		for i := -1; i < len(workloads); i++ {
+			workload := processes
+			if i >= 0 {
+				workload = workloads[i]
+			}
+			r, err := cache.schedule(ctx, alg, opts, workload)
+			if err != nil {
+				// Report the trials run before the interruption.
+				if errors.Is(err, context.Canceled) {
+					if len(trials) > 0 {
+						appendJitterRows(table, alg.title, baseline, trials)
+					}
+					table.Render()
+					_, _ = fmt.Fprintf(w, "Cancelled during %s after %d of %d trials.\n", alg.name, len(trials), len(workloads))
+				}
+				return err
+			}
+			if r.Metrics.Completed+r.Metrics.Killed < r.Total {
+				return fmt.Errorf("%s completed only %d of %d processes", alg.name, r.Metrics.Completed, r.Total)
+			}
+			if i < 0 {
+				baseline = r.UnitMetrics()
+			} else {
+				trials = append(trials, r.UnitMetrics())
+			}
+			r.Release()
+			if runs++; bar != nil {
+				bar.update(runs, total)
+			}
+		}
+		appendJitterRows(table, alg.title, baseline, trials)
+	}
+	table.Render()
+
+	return nil
+}
+
+// isSet reports whether the named flag was given on the command line.
+func (c *sweepCmd) isSet(name string) bool {
+	set := false
+	c.flags.Visit(func(f *flag.Flag) { set = set || f.Name == name })
+	return set
+}
+
+// appendJitterRows adds one row per compared metric summarizing trials.
+func appendJitterRows(table *tablewriter.Table, title string, baseline scheduler.Metrics, trials []scheduler.Metrics) {
+	for _, m := range compareMetrics {
+		st := summarizeTrials(m, trials)
+		table.Append([]string{
+			title,
+			m.label,
+			fmt.Sprintf("%.2f", m.value(baseline)),
+			fmt.Sprintf("%.2f", st.mean),
+			fmt.Sprintf("%.2f", st.stddev),
+			fmt.Sprintf("%.2f", st.min),
+			fmt.Sprintf("%.2f", st.max),
+		})
+	}
+}
+
+// trialStats summarizes one metric over a set of trials.
+type trialStats struct {
+	mean, stddev, min, max float64
+}
+
+func summarizeTrials(m metric, trials []scheduler.Metrics) trialStats {
+	st := trialStats{min: math.Inf(1), max: math.Inf(-1)}
+	for _, t := range trials {
+		v := m.value(t)
+		st.mean += v
+		st.min = math.Min(st.min, v)
+		st.max = math.Max(st.max, v)
+	}
+	st.mean /= float64(len(trials))
+	for _, t := range trials {
+		d := m.value(t) - st.mean
+		st.stddev += d * d
+	}
+	st.stddev = math.Sqrt(st.stddev / float64(len(trials)))
+
+	return st
+}
+
+// quantumAlgorithmsOf selects the algorithms in list, where "all" means every
+// algorithm that uses the quantum; sweeping any other would repeat one row.
+func quantumAlgorithmsOf(list string) ([]algorithm, error) {
+	algs, err := selectAlgorithms(list)
+	if err != nil {
+		return nil, err
+	}
+	var selected []algorithm
+	for _, alg := range algs {
+		switch {
+		case alg.usesQuantum:
+			selected = append(selected, alg)
+		case list != "all":
+			return nil, fmt.Errorf("%w: %s does not use a quantum", ErrInvalidArgs, alg.name)
+		}
+	}
+
+	return selected, nil
+}
+
+// parseRange parses an inclusive range "lo..hi" or "lo..hi:step" of positive values.
+func parseRange(s string) ([]int64, error) {
+	bounds, stepText, hasStep := strings.Cut(s, ":")
+	loText, hiText, ok := strings.Cut(bounds, "..")
+	if !ok {
+		loText, hiText = bounds, bounds
+	}
+	lo, errLo := strconv.ParseInt(loText, 10, 64)
+	hi, errHi := strconv.ParseInt(hiText, 10, 64)
+	step, errStep := int64(1), error(nil)
+	if hasStep {
+		step, errStep = strconv.ParseInt(stepText, 10, 64)
+	}
+	if errLo != nil || errHi != nil || errStep != nil || lo <= 0 || hi < lo || step <= 0 {
+		return nil, fmt.Errorf("%w: invalid range %q, want lo..hi or lo..hi:step", ErrInvalidArgs, s)
+	}
+
+	var values []int64
+	for v := lo; v <= hi; v += step {
+		values = append(values, v)
+	}
+
+	return values, nil
+}
+
+// outputQuantumSweep prints the sweep table followed by a bar plot of
+// average wait per quantum, which makes the knee of the curve easy to spot.
+func outputQuantumSweep(w io.Writer, title string, quanta []int64, results []scheduler.Metrics) {
+	_, _ = fmt.Fprintln(w, title)
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"Quantum", "Average wait", "Average turnaround", "Context switches"})
+	var maxWait float64
+	for i, m := range results {
+		table.Append([]string{
+			fmt.Sprint(quanta[i]),
+			fmt.Sprintf("%.2f", m.AverageWait),
+			fmt.Sprintf("%.2f", m.AverageTurnaround),
+			fmt.Sprint(m.ContextSwitches),
+		})
+		if m.AverageWait > maxWait {
+			maxWait = m.AverageWait
+		}
+	}
+	table.Render()
+
+	const width = 40
+	_, _ = fmt.Fprintln(w, "Average wait by quantum")
+	for i, m := range results {
+		bar := 0
+		if maxWait > 0 && m.AverageWait > 0 {
+			bar = int(m.AverageWait / maxWait * width)
+		}
+		_, _ = fmt.Fprintf(w, "%4d | %-*s %.2f\n", quanta[i], width, strings.Repeat("#", bar), m.AverageWait)
+	}
+	_, _ = fmt.Fprintln(w)
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"math"
+	"os"
+	"path"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/rks0134/CSCE4600/Project1/scheduler"
+)
+
+func Test_sweepLateArrivals(t *testing.T) {
+	t.Parallel()
+	file := path.Join(t.TempDir(), "late.csv")
+	if err := os.WriteFile(file, []byte("1,5,1,2\n2,9,3,1\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	var w strings.Builder
+	if err := run(context.Background(), &w, "binary_name", "sweep", "-quantum", "1..3", file); err != nil {
+		t.Fatalf("run() error = %v", err)
+	}
+	if !strings.Contains(w.String(), "|       3 |") {
+		t.Errorf("sweep output is missing quantum 3:\n%s", w.String())
+	}
+}
+
+func Test_parseRange(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		s       string
+		want    []int64
+		wantErr error
+	}{
+		{name: "range", s: "1..4", want: []int64{1, 2, 3, 4}},
+		{name: "step", s: "2..9:3", want: []int64{2, 5, 8}},
+		{name: "single", s: "3", want: []int64{3}},
+		{name: "reversed", s: "4..1", wantErr: ErrInvalidArgs},
+		{name: "zero", s: "0..2", wantErr: ErrInvalidArgs},
+		{name: "bad step", s: "1..4:0", wantErr: ErrInvalidArgs},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := parseRange(tt.s)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseRange() = %v, want %v", got, tt.want)
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_summarizeTrials(t *testing.T) {
+	t.Parallel()
+	trials := []scheduler.Metrics{{AverageWait: 1}, {AverageWait: 3}, {AverageWait: 2}, {AverageWait: 2}}
+	got := summarizeTrials(compareMetrics[0], trials)
+	want := trialStats{mean: 2, stddev: math.Sqrt(0.5), min: 1, max: 3}
+	if got != want {
+		t.Errorf("summarizeTrials() = %+v, want %+v", got, want)
+	}
+}
+
+func Test_sweepJitterTable(t *testing.T) {
+	t.Parallel()
+	var w strings.Builder
+	err := run(context.Background(), &w, "binary_name", "-seed", "3", "sweep", "-jitter", "1", "-trials", "3", "-algorithms", "fcfs,rr", "-quantum", "2", "example_processes.csv")
+	if err != nil {
+		t.Fatalf("run() error = %v", err)
+	}
+	// Only the Algorithm column merges; repeated numbers stay visible.
+	for _, line := range strings.Split(w.String(), "\n") {
+		cells := strings.Split(line, "|")
+		if len(cells) != 9 {
+			continue
+		}
+		for _, cell := range cells[2:8] {
+			if strings.TrimSpace(cell) == "" {
+				t.Fatalf("table has a blank cell in %q:\n%s", line, w.String())
+			}
+		}
+	}
+}
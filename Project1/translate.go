@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/rks0134/CSCE4600/Project1/paging"
+	"github.com/rks0134/CSCE4600/Project1/scheduler"
+)
+
+// translateCmd translates virtual addresses to physical ones.
+type translateCmd struct {
+	pageSize   uint64
+	pageTable  string
+	frames     int
+	algorithm  string
+	hex        bool
+	memoryTime time.Duration
+	faultTime  time.Duration
+}
+
+func (c *translateCmd) defineFlags(flags *flag.FlagSet) {
+	flags.Uint64Var(&c.pageSize, "page-size", 4096, "page size in `bytes`, a power of two")
+	flags.StringVar(&c.pageTable, "page-table", "", "`file` giving the frame of each resident page, a page and frame per line, - for an invalid page")
+	flags.IntVar(&c.frames, "frames", 0, "demand page into this many `frames` instead of using a page table")
+	flags.StringVar(&c.algorithm, "algorithm", "lru", "page replacement `algorithm` for demand paging (fifo, lru, opt, clock, lfu)")
+	flags.BoolVar(&c.hex, "hex", false, "print addresses and offsets in hex")
+	flags.DurationVar(&c.memoryTime, "memory-time", 100*time.Nanosecond, "`time` of a memory access")
+	flags.DurationVar(&c.faultTime, "fault-time", 8*time.Millisecond, "`time` to service a page fault")
+}
+
+func (c *translateCmd) run(_ context.Context, w io.Writer, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("%w: must give a file of virtual addresses", ErrInvalidArgs)
+	}
+	if (c.pageTable == "") == (c.frames == 0) {
+		return fmt.Errorf("%w: must give exactly one of -page-table and -frames", ErrInvalidArgs)
+	}
+	if c.frames < 0 {
+		return fmt.Errorf("%w: -frames must be positive", ErrInvalidArgs)
+	}
+	if c.pageSize == 0 || c.pageSize&(c.pageSize-1) != 0 {
+		return fmt.Errorf("%w: -page-size must be a power of two", ErrInvalidArgs)
+	}
+	if c.memoryTime < 0 || c.faultTime < 0 {
+		return fmt.Errorf("%w: -memory-time and -fault-time must not be negative", ErrInvalidArgs)
+	}
+	alg, ok := paging.Find(c.algorithm)
+	if !ok {
+		return fmt.Errorf("%w: unknown page replacement algorithm %q", ErrInvalidArgs, c.algorithm)
+	}
+
+	var addrs []uint64
+	if err := readInputFile(args[0], func(r io.Reader) (err error) {
+		addrs, err = paging.ParseAddresses(r)
+		return err
+	}); err != nil {
+		return err
+	}
+	var r *paging.TranslationResult
+	var err error
+	how := "Frames from the page table in " + c.pageTable
+	if c.pageTable != "" {
+		var table paging.PageTable
+		if err := readInputFile(c.pageTable, func(r io.Reader) (err error) {
+			table, err = paging.ParsePageTable(r)
+			return err
+		}); err != nil {
+			return err
+		}
+		r, err = paging.Translate(addrs, c.pageSize, table)
+	} else {
+		how = fmt.Sprintf("Demand paging into %d frames, replacing by %s", c.frames, alg.Name)
+		r, err = paging.TranslateDemand(addrs, c.pageSize, alg, c.frames)
+	}
+	if err != nil {
+		return err
+	}
+
+	scheduler.RenderTitle(w, fmt.Sprintf("Address translation, %d-byte pages", c.pageSize))
+	_, _ = fmt.Fprintf(w, "%d offset bits. %s\n\n", paging.OffsetBits(c.pageSize), how)
+	paging.RenderTranslations(w, r, c.hex, c.memoryTime, c.faultTime)
+
+	return nil
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func Test_runTranslate(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	addrs, table := filepath.Join(dir, "addresses.txt"), filepath.Join(dir, "table.txt")
+	if err := os.WriteFile(addrs, []byte("1052, 2221\n5499\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(table, []byte("0 5\n1 6\n2 1\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	var w strings.Builder
+	if err := run(context.Background(), &w, "binary_name", "translate", "-page-size", "1024", "-page-table", table, addrs); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"Address translation, 1024-byte pages",
+		"10 offset bits. Frames from the page table in " + table + "\n",
+		"|            1052 |    1 |     28 |     6 |             6172 |       |\n",
+		"|            5499 |    5 |    379 | -     | -                | fault |\n",
+		"Page faults: 1 of 3 translations (33.33%)\n",
+		"Effective access time: (1 - 0.3333) × 100ns + 0.3333 × 8ms = 2.666733ms\n",
+	} {
+		if !strings.Contains(w.String(), want) {
+			t.Errorf("translate wrote:\n%s\nwant it to contain %q", w.String(), want)
+		}
+	}
+
+	w.Reset()
+	if err := run(context.Background(), &w, "binary_name", "translate", "-frames", "1", "-hex", "-page-size", "1024", addrs); err != nil {
+		t.Fatal(err)
+	}
+	if want := "| 0x8ad           |    2 | 0xad   |     0 | 0xad             | fault, evicts page 1 |\n"; !strings.Contains(w.String(), want) {
+		t.Errorf("translate -frames wrote:\n%s\nwant it to contain %q", w.String(), want)
+	}
+
+	for _, args := range [][]string{
+		{addrs},
+		{"-frames", "2", "-page-table", table, addrs},
+		{"-frames", "2", "-page-size", "1000", addrs},
+		{"-frames", "2", "-algorithm", "mru", addrs},
+	} {
+		err := run(context.Background(), io.Discard, append([]string{"binary_name", "translate"}, args...)...)
+		if !errors.Is(err, ErrInvalidArgs) {
+			t.Errorf("translate %v error = %v, want %v", args, err, ErrInvalidArgs)
+		}
+	}
+}
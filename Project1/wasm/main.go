@@ -0,0 +1,35 @@
+//go:build js && wasm
+
+// Command wasm runs the schedulers in a web browser, so teaching pages can
+// simulate schedules client-side with no server. Build it with
+//
+//	GOOS=js GOARCH=wasm go build -o scheduler.wasm ./wasm
+//
+// and load it with scheduler.js, next to the wasm_exec.js of the Go
+// release that built it. It defines two global functions:
+// scheduleJSON(request), which runs a request as scheduler.RunJSON does and
+// returns its JSON response as a string, and schedulerAlgorithms(), which
+// returns the registered algorithm names as a JSON array.
+package main
+
+import (
+	"encoding/json"
+	"syscall/js"
+
+	"github.com/rks0134/CSCE4600/Project1/scheduler"
+)
+
+func main() {
+	js.Global().Set("scheduleJSON", js.FuncOf(func(this js.Value, args []js.Value) any {
+		if len(args) != 1 || args[0].Type() != js.TypeString {
+			return `{"error":"scheduleJSON takes one JSON string"}`
+		}
+		return string(scheduler.RunJSON([]byte(args[0].String())))
+	}))
+	js.Global().Set("schedulerAlgorithms", js.FuncOf(func(this js.Value, args []js.Value) any {
+		names, _ := json.Marshal(scheduler.Names())
+		return string(names)
+	}))
+	// The functions stay callable as long as the program runs.
+	select {}
+}
@@ -0,0 +1,30 @@
+// Loads the WebAssembly build of the schedulers and wraps it for pages:
+//
+//   <script src="wasm_exec.js"></script>
+//   <script src="scheduler.js"></script>
+//   <script>
+//     loadScheduler("scheduler.wasm").then(s => {
+//       const r = s.schedule({ algorithm: "rr", processes: [{ ProcessID: 1, BurstDuration: 5 }], options: { Quantum: 4 } });
+//       console.log(r.metrics.average_wait, r.report);
+//     });
+//   </script>
+//
+// schedule takes and returns objects rather than JSON strings, and throws
+// the error of a request that cannot be scheduled.
+"use strict";
+
+async function loadScheduler(url) {
+  const go = new Go();
+  const { instance } = await WebAssembly.instantiateStreaming(fetch(url), go.importObject);
+  go.run(instance);
+  return {
+    algorithms: () => JSON.parse(globalThis.schedulerAlgorithms()),
+    schedule(request) {
+      const result = JSON.parse(globalThis.scheduleJSON(JSON.stringify(request)));
+      if (result.error) {
+        throw new Error(result.error);
+      }
+      return result;
+    },
+  };
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// webhookTimeout bounds how long posting to a webhook may take.
+const webhookTimeout = 30 * time.Second
+
+// webhookSummaryLimit is how much of a report a webhook message quotes,
+// leaving room in the 2000 characters a Discord message may have.
+const webhookSummaryLimit = 1500
+
+// webhookFlags let a long-running command report to a webhook when it
+// finishes, so experiment runs need no one watching the terminal.
+type webhookFlags struct {
+	webhook string
+}
+
+func (f *webhookFlags) define(flags *flag.FlagSet) {
+	flags.StringVar(&f.webhook, "webhook", "", "`URL` to POST a summary or failure report to when the command ends, such as a Slack or Discord incoming webhook")
+}
+
+// webhookPayload is the JSON posted to a webhook. Text and Content carry
+// the message for Slack and Discord; the other fields are for services of
+// one's own.
+type webhookPayload struct {
+	Text    string   `json:"text"`
+	Content string   `json:"content"`
+	Command string   `json:"command"`
+	Args    []string `json:"args"`
+	OK      bool     `json:"ok"`
+	Error   string   `json:"error,omitempty"`
+	Elapsed float64  `json:"elapsed_seconds"`
+	Report  string   `json:"report"`
+}
+
+// start begins a run of command with args that reports to the webhook, if
+// one is set. Output for the report goes to the returned writer, and the
+// run's error through finish, which posts the report and returns the
+// error, or the webhook's if the run succeeded and posting failed.
+func (f *webhookFlags) start(command string, args []string, w io.Writer) (io.Writer, func(err error) error, error) {
+	if f.webhook == "" {
+		return w, func(err error) error { return err }, nil
+	}
+	if u, err := url.Parse(f.webhook); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, nil, fmt.Errorf("%w: -webhook must be an http or https URL", ErrInvalidArgs)
+	}
+	began := time.Now()
+	var report bytes.Buffer
+	finish := func(err error) error {
+		elapsed := time.Since(began)
+		p := webhookPayload{Command: command, Args: args, OK: err == nil, Elapsed: elapsed.Seconds(), Report: report.String()}
+		if err != nil {
+			p.Error = err.Error()
+			p.Text = fmt.Sprintf("%s failed after %s: %v", command, elapsed.Round(time.Second), err)
+		} else {
+			p.Text = fmt.Sprintf("%s finished in %s", command, elapsed.Round(time.Second))
+		}
+		if summary := strings.TrimSpace(p.Report); summary != "" {
+			p.Text += "\n```\n" + truncateSummary(summary, webhookSummaryLimit) + "\n```"
+		}
+		p.Content = p.Text
+		if postErr := postWebhook(f.webhook, p); err == nil && postErr != nil {
+			return fmt.Errorf("webhook: %w", postErr)
+		}
+
+		return err
+	}
+
+	return &teeWriter{w: w, copy: &report}, finish, nil
+}
+
+// truncateSummary returns summary cut to at most limit bytes, at the start
+// of a rune so multi-byte names stay valid UTF-8, marked as cut.
+func truncateSummary(summary string, limit int) string {
+	if len(summary) <= limit {
+		return summary
+	}
+	for limit > 0 && !utf8.RuneStart(summary[limit]) {
+		limit--
+	}
+
+	return summary[:limit] + "\n…"
+}
+
+// teeWriter writes to w and keeps a copy, flushing w when flushed.
+type teeWriter struct {
+	w    io.Writer
+	copy *bytes.Buffer
+}
+
+func (t *teeWriter) Write(p []byte) (int, error) {
+	t.copy.Write(p)
+	return t.w.Write(p)
+}
+
+func (t *teeWriter) Flush() error { return flush(t.w) }
+
+// postWebhook posts p to webhook. It is not tied to the command's context,
+// so an interrupted run is still reported. Errors leave out the URL, which
+// is often a secret.
+func postWebhook(webhook string, p webhookPayload) error {
+	body, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), webhookTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook, bytes.NewReader(body))
+	if err != nil {
+		return withoutURL(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return withoutURL(err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("responded %s", resp.Status)
+	}
+
+	return nil
+}
+
+// withoutURL strips the URL from a *url.Error.
+func withoutURL(err error) error {
+	var ue *url.Error
+	if errors.As(err, &ue) {
+		return ue.Err
+	}
+	return err
+}
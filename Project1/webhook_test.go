@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func Test_runWebhook(t *testing.T) {
+	t.Parallel()
+	payloads := make(chan webhookPayload, 2)
+	status := http.StatusOK
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var p webhookPayload
+		if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+			t.Error(err)
+		}
+		payloads <- p
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+
+	if err := run(context.Background(), io.Discard, "binary_name", "batch", "-progress", "never", "-webhook", srv.URL, "example_processes.csv"); err != nil {
+		t.Fatal(err)
+	}
+	p := <-payloads
+	if !p.OK || p.Command != "batch" || !strings.HasPrefix(p.Text, "batch finished in ") || p.Content != p.Text || !strings.Contains(p.Report, "Batch of 1 files") {
+		t.Errorf("batch webhook payload = %+v, want a success summary", p)
+	}
+
+	err := run(context.Background(), io.Discard, "binary_name", "sweep", "-progress", "never", "-webhook", srv.URL, "missing.csv")
+	if got := exitCode(err); got != ExitFileNotFound {
+		t.Errorf("sweep of a missing file exit code = %v, want %v", got, ExitFileNotFound)
+	}
+	if p := <-payloads; p.OK || p.Command != "sweep" || !strings.Contains(p.Error, "missing.csv") || !strings.HasPrefix(p.Text, "sweep failed after ") {
+		t.Errorf("sweep webhook payload = %+v, want a failure report", p)
+	}
+
+	status = http.StatusForbidden
+	if err := run(context.Background(), io.Discard, "binary_name", "batch", "-progress", "never", "-webhook", srv.URL, "example_processes.csv"); err == nil || strings.Contains(err.Error(), srv.URL) {
+		t.Errorf("batch with a failing webhook error = %v, want one without its URL", err)
+	}
+	<-payloads
+	if err := run(context.Background(), io.Discard, "binary_name", "batch", "-webhook", "slack", "example_processes.csv"); !errors.Is(err, ErrInvalidArgs) {
+		t.Errorf("batch -webhook slack error = %v, want %v", err, ErrInvalidArgs)
+	}
+}
+
+func Test_truncateSummary(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		summary string
+		limit   int
+		want    string
+	}{
+		{name: "short", summary: "Pod/web", limit: 10, want: "Pod/web"},
+		{name: "ascii", summary: "Pod/web-server", limit: 7, want: "Pod/web\n…"},
+		{name: "inside a rune", summary: "Pod/café-über", limit: 8, want: "Pod/caf\n…"},
+		{name: "at a rune", summary: "Pod/café-über", limit: 9, want: "Pod/café\n…"},
+		{name: "inside a wide rune", summary: "Pod/调度器", limit: 9, want: "Pod/调\n…"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := truncateSummary(tt.summary, tt.limit)
+			if got != tt.want || !utf8.ValidString(got) {
+				t.Errorf("truncateSummary(%q, %d) = %q, want %q", tt.summary, tt.limit, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,281 @@
+package main
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// websocketGUID is appended to the key of an opening handshake to accept it.
+const websocketGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// Opcodes of WebSocket frames.
+const (
+	wsText  = 0x1
+	wsClose = 0x8
+	wsPing  = 0x9
+	wsPong  = 0xa
+)
+
+// Close codes sent when closing a WebSocket.
+const (
+	wsNormalClosure = 1000
+	wsGoingAway     = 1001
+	wsProtocolError = 1002
+	wsTooBig        = 1009
+)
+
+// wsConn is the server end of a WebSocket connection. It implements what
+// streaming simulations need of RFC 6455: whole messages each way, pings,
+// and closing, without extensions or subprotocols. Writes may come from
+// any goroutine; reads from one at a time.
+type wsConn struct {
+	conn net.Conn
+	br   *bufio.Reader
+	mu   sync.Mutex // guards writes
+	bw   *bufio.Writer
+	// closing is set once a close frame has been sent, after which
+	// nothing more may be.
+	closing bool
+}
+
+// upgradeWebSocket completes the opening handshake of the WebSocket request
+// r and takes its connection over. A request from a browser must come from a
+// page of the server's own host or of one of origins, so other sites cannot
+// open connections with their visitors' credentials. If r is not a
+// handshake, or not allowed, it returns an error without responding, so the
+// caller can.
+func upgradeWebSocket(w http.ResponseWriter, r *http.Request, origins []string) (*wsConn, error) {
+	if err := checkOrigin(r, origins); err != nil {
+		return nil, err
+	}
+	switch {
+	case r.Method != http.MethodGet || !headerHas(r.Header, "Connection", "upgrade") || !headerHas(r.Header, "Upgrade", "websocket"):
+		return nil, fmt.Errorf("%w: not a WebSocket handshake", ErrInvalidArgs)
+	case r.Header.Get("Sec-WebSocket-Version") != "13":
+		return nil, fmt.Errorf("%w: WebSocket version %q, want 13", ErrInvalidArgs, r.Header.Get("Sec-WebSocket-Version"))
+	case r.Header.Get("Sec-WebSocket-Key") == "":
+		return nil, fmt.Errorf("%w: WebSocket handshake without a key", ErrInvalidArgs)
+	}
+	hj, ok := w.(http.Hijacker)
+	if !ok {
+		return nil, errors.New("connection cannot be taken over for a WebSocket")
+	}
+	conn, rw, err := hj.Hijack()
+	if err != nil {
+		return nil, err
+	}
+	sum := sha1.Sum([]byte(r.Header.Get("Sec-WebSocket-Key") + websocketGUID))
+	_, _ = fmt.Fprintf(rw, "HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\nSec-WebSocket-Accept: %s\r\n\r\n", base64.StdEncoding.EncodeToString(sum[:]))
+	if err := rw.Flush(); err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+
+	return &wsConn{conn: conn, br: rw.Reader, bw: rw.Writer}, nil
+}
+
+// checkOrigin returns an error wrapping errCrossOrigin unless r has no
+// Origin header, as requests from outside browsers do not, or its Origin is
+// one of origins or has r's host.
+func checkOrigin(r *http.Request, origins []string) error {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return nil
+	}
+	for _, o := range origins {
+		if strings.EqualFold(strings.TrimRight(strings.TrimSpace(o), "/"), origin) {
+			return nil
+		}
+	}
+	if u, err := url.Parse(origin); err == nil && strings.EqualFold(u.Host, r.Host) {
+		return nil
+	}
+
+	return fmt.Errorf("%w: %s", errCrossOrigin, origin)
+}
+
+// headerHas reports whether the comma separated header name lists token,
+// in any case.
+func headerHas(h http.Header, name, token string) bool {
+	for _, v := range h.Values(name) {
+		for _, t := range strings.Split(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(t), token) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// WriteText sends data as one text message.
+func (c *wsConn) WriteText(data []byte) error {
+	return c.write(wsText, data)
+}
+
+func (c *wsConn) write(opcode byte, payload []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closing {
+		return errClosing
+	}
+	c.closing = opcode == wsClose
+	if err := writeFrame(c.bw, opcode, payload, false); err != nil {
+		return err
+	}
+	return c.bw.Flush()
+}
+
+// ReadMessage returns the next text or binary message, of at most limit
+// bytes, answering pings on the way. It returns io.EOF once the client
+// closes the connection, having answered its close frame.
+func (c *wsConn) ReadMessage(limit int64) ([]byte, error) {
+	var msg []byte
+	for {
+		fin, opcode, payload, err := readFrame(c.br, limit-int64(len(msg)), true)
+		if err != nil {
+			switch {
+			case errors.Is(err, errFrameTooBig):
+				_ = c.Close(wsTooBig)
+			case errors.Is(err, errUnmasked):
+				_ = c.Close(wsProtocolError)
+			}
+			return nil, err
+		}
+		switch opcode {
+		case wsPing:
+			if err := c.write(wsPong, payload); err != nil {
+				return nil, err
+			}
+			continue
+		case wsPong:
+			continue
+		case wsClose:
+			_ = c.write(wsClose, payload)
+			return nil, io.EOF
+		}
+		msg = append(msg, payload...)
+		if fin {
+			return msg, nil
+		}
+	}
+}
+
+// Close sends a close frame with code, unless one has been sent already,
+// and closes the connection.
+func (c *wsConn) Close(code uint16) error {
+	err := c.write(wsClose, binary.BigEndian.AppendUint16(nil, code))
+	if errors.Is(err, errClosing) {
+		err = nil
+	}
+	if closeErr := c.conn.Close(); err == nil {
+		err = closeErr
+	}
+
+	return err
+}
+
+var (
+	// errFrameTooBig is returned for a frame longer than allowed.
+	errFrameTooBig = errors.New("WebSocket message too big")
+	// errUnmasked is returned for a client's frame that is not masked,
+	// which RFC 6455 requires the server to fail the connection for.
+	errUnmasked = errors.New("WebSocket frame from the client is not masked")
+	// errCrossOrigin is returned for a handshake from a page of another
+	// site than the server allows.
+	errCrossOrigin = errors.New("WebSocket handshake from a disallowed origin")
+	// errClosing is returned for writes once the connection is closing.
+	errClosing = errors.New("WebSocket is closing")
+)
+
+// writeFrame writes payload as a single frame with the given opcode,
+// masked as a client's frames must be if mask is set.
+func writeFrame(w io.Writer, opcode byte, payload []byte, mask bool) error {
+	header := []byte{0x80 | opcode, 0}
+	switch n := len(payload); {
+	case n < 126:
+		header[1] = byte(n)
+	case n <= 0xffff:
+		header[1] = 126
+		header = binary.BigEndian.AppendUint16(header, uint16(n))
+	default:
+		header[1] = 127
+		header = binary.BigEndian.AppendUint64(header, uint64(n))
+	}
+	if mask {
+		header[1] |= 0x80
+		key := binary.BigEndian.AppendUint32(nil, rand.Uint32())
+		header = append(header, key...)
+		masked := make([]byte, len(payload))
+		for i := range payload {
+			masked[i] = payload[i] ^ key[i%4]
+		}
+		payload = masked
+	}
+	if _, err := w.Write(header); err != nil {
+		return err
+	}
+	_, err := w.Write(payload)
+
+	return err
+}
+
+// readFrame reads the next frame, of at most limit bytes, unmasking it. With
+// mask set it is a client's frame, which must be masked.
+func readFrame(r *bufio.Reader, limit int64, mask bool) (fin bool, opcode byte, payload []byte, err error) {
+	var header [2]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return false, 0, nil, err
+	}
+	fin, opcode = header[0]&0x80 != 0, header[0]&0x0f
+	n := uint64(header[1] & 0x7f)
+	switch n {
+	case 126:
+		var ext [2]byte
+		if _, err := io.ReadFull(r, ext[:]); err != nil {
+			return false, 0, nil, err
+		}
+		n = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err := io.ReadFull(r, ext[:]); err != nil {
+			return false, 0, nil, err
+		}
+		n = binary.BigEndian.Uint64(ext[:])
+	}
+	if n > uint64(limit) {
+		return false, 0, nil, errFrameTooBig
+	}
+	var key [4]byte
+	masked := header[1]&0x80 != 0
+	if mask && !masked {
+		return false, 0, nil, errUnmasked
+	}
+	if masked {
+		if _, err := io.ReadFull(r, key[:]); err != nil {
+			return false, 0, nil, err
+		}
+	}
+	payload = make([]byte, n)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return false, 0, nil, err
+	}
+	if masked {
+		for i := range payload {
+			payload[i] ^= key[i%4]
+		}
+	}
+
+	return fin, opcode, payload, nil
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func Test_wsConnUnmasked(t *testing.T) {
+	t.Parallel()
+	server, client := net.Pipe()
+	defer client.Close()
+	ws := &wsConn{conn: server, br: bufio.NewReader(server), bw: bufio.NewWriter(server)}
+	go func() { _ = writeFrame(client, wsText, []byte("unmasked"), false) }()
+	errc := make(chan error, 1)
+	go func() {
+		_, err := ws.ReadMessage(maxWorkload)
+		errc <- err
+	}()
+	_, opcode, payload, err := readFrame(bufio.NewReader(client), maxWorkload, false)
+	if err != nil || opcode != wsClose || len(payload) < 2 || binary.BigEndian.Uint16(payload) != wsProtocolError {
+		t.Errorf("got frame with opcode %d, payload %v, and error %v, want a close with code %d", opcode, payload, err, wsProtocolError)
+	}
+	if err := <-errc; !errors.Is(err, errUnmasked) {
+		t.Errorf("ReadMessage() of an unmasked frame error = %v, want %v", err, errUnmasked)
+	}
+}
+
+func Test_checkOrigin(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		origin  string
+		origins []string
+		wantErr bool
+	}{
+		{name: "no origin", origin: ""},
+		{name: "same host", origin: "http://sim.example:8080"},
+		{name: "same host any case", origin: "http://SIM.example:8080"},
+		{name: "other port", origin: "http://sim.example:9090", wantErr: true},
+		{name: "other site", origin: "https://evil.example", wantErr: true},
+		{name: "allowed", origin: "https://ui.example", origins: []string{"https://other.example", " https://ui.example/"}},
+		{name: "not allowed", origin: "https://evil.example", origins: []string{"https://ui.example"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			r := httptest.NewRequest(http.MethodGet, "http://sim.example:8080/live", nil)
+			if tt.origin != "" {
+				r.Header.Set("Origin", tt.origin)
+			}
+			if err := checkOrigin(r, tt.origins); (err != nil) != tt.wantErr || err != nil && !errors.Is(err, errCrossOrigin) {
+				t.Errorf("checkOrigin() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_wsConnClientClose(t *testing.T) {
+	t.Parallel()
+	server, client := net.Pipe()
+	defer client.Close()
+	ws := &wsConn{conn: server, br: bufio.NewReader(server), bw: bufio.NewWriter(server)}
+	go func() { _ = writeFrame(client, wsClose, binary.BigEndian.AppendUint16(nil, wsNormalClosure), true) }()
+	go func() {
+		if _, err := ws.ReadMessage(maxWorkload); err != io.EOF {
+			t.Errorf("ReadMessage() after a close error = %v, want %v", err, io.EOF)
+		}
+		_ = ws.Close(wsNormalClosure)
+	}()
+	br := bufio.NewReader(client)
+	if _, opcode, _, err := readFrame(br, maxWorkload, false); err != nil || opcode != wsClose {
+		t.Fatalf("got frame with opcode %d and error %v, want the close echoed", opcode, err)
+	}
+	if _, opcode, _, err := readFrame(br, maxWorkload, false); err != io.EOF {
+		t.Errorf("got frame with opcode %d and error %v after the close, want %v", opcode, err, io.EOF)
+	}
+}